@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/ihavespoons/action-control/internal/actioninputs"
+	"github.com/spf13/viper"
+)
+
+// runActionEntrypoint runs enforce from the GitHub Action's inputs, which the runner passes
+// as INPUT_* environment variables, using the policy content exclusively
+func runActionEntrypoint() {
+	inputs, err := actioninputs.FromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid action inputs: %v", err)
+	}
+
+	viper.Set("policy_content", inputs.PolicyContent)
+	viper.Set("ignore_local_policy", true)
+	viper.Set("github_token", inputs.GitHubToken)
+	viper.Set("repository", inputs.Repository)
+	viper.Set("output_format", []string{inputs.OutputFormat})
+	viper.Set("cache_file", inputs.CacheFile)
+	viper.Set("max_violations", inputs.MaxViolations)
+	viper.Set("fail_on_severity", inputs.FailOnSeverity)
+
+	// On pull request events, evaluate only the workflow files the pull request changes
+	if inputs.ChangedFilesOnly {
+		number, err := actioninputs.PullRequest(os.Getenv, os.ReadFile, inputs.Repository)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		viper.Set("pull_request", number)
+	}
+
+	runEnforce()
+}
@@ -0,0 +1,98 @@
+# Downloads the action-control release binary for this Windows runner, verifies it against the
+# signed release checksums and enforces the policy content, for runners without a POSIX shell
+$ErrorActionPreference = 'Stop'
+
+# Select the binary for the architecture the runner is running on
+switch ($env:RUNNER_ARCH_NAME) {
+  'X64' { $goarch = 'amd64' }
+  'ARM64' { $goarch = 'arm64' }
+  default { Write-Output "Unsupported runner architecture: $env:RUNNER_ARCH_NAME"; exit 1 }
+}
+$asset = "action-control-windows-$goarch.exe"
+
+# Default to the release the action itself is used at, so a new release never runs before the
+# workflow opts into it
+$version = $env:VERSION
+if (-not $version) {
+  $version = if ($env:ACTION_REF) { $env:ACTION_REF } else { Split-Path -Leaf (Split-Path -Parent $env:GITHUB_ACTION_PATH) }
+  if ($version -notmatch '^v[0-9]') {
+    Write-Output "The action is used at $version, not a release tag: set the version input, such as v1.4.0"
+    exit 1
+  }
+}
+if ($version -eq 'latest') {
+  $baseUrl = 'https://github.com/ihavespoons/action-control/releases/latest/download'
+} else {
+  if (-not $version.StartsWith('v')) {
+    $version = "v$version"
+  }
+  $baseUrl = "https://github.com/ihavespoons/action-control/releases/download/$version"
+}
+
+if (-not $env:ACTION_CONTROL_GITHUB_TOKEN) {
+  Write-Output 'GitHub token not provided'
+  exit 1
+}
+if (-not $env:ACTION_CONTROL_POLICY_CONTENT) {
+  Write-Output 'No policy content provided. Please provide policy content.'
+  exit 1
+}
+
+$installDir = Join-Path $env:RUNNER_TEMP 'action-control'
+New-Item -ItemType Directory -Force -Path $installDir | Out-Null
+$binary = Join-Path $installDir $asset
+$checksums = Join-Path $installDir 'checksums.txt'
+$signature = Join-Path $installDir 'checksums.txt.sig'
+$headers = @{ Authorization = "Bearer $env:ACTION_CONTROL_GITHUB_TOKEN" }
+Invoke-WebRequest -Uri "$baseUrl/$asset" -OutFile $binary -Headers $headers -MaximumRetryCount 3
+Invoke-WebRequest -Uri "$baseUrl/checksums.txt" -OutFile $checksums -Headers $headers -MaximumRetryCount 3
+Invoke-WebRequest -Uri "$baseUrl/checksums.txt.sig" -OutFile $signature -Headers $headers -MaximumRetryCount 3
+
+# Verify the checksums were signed with the release key the action pins, so whoever can publish
+# a release can't supply both a binary and its checksum
+$signingKey = ((Get-Content (Join-Path $env:GITHUB_ACTION_PATH 'signing-key.pub') | Where-Object { $_ -notmatch '^#' }) -join '') -replace '\s', ''
+if (-not $signingKey) {
+  Write-Output 'The action pins no release signing key in signing-key.pub'
+  exit 1
+}
+$publicKey = Join-Path $installDir 'signing-key.pem'
+Set-Content -Path $publicKey -Value "-----BEGIN PUBLIC KEY-----`n$signingKey`n-----END PUBLIC KEY-----"
+# Hosted runners have OpenSSL on the PATH; Git for Windows bundles it otherwise
+$openssl = (Get-Command openssl -ErrorAction SilentlyContinue).Source
+if (-not $openssl) {
+  $openssl = Join-Path $env:ProgramFiles 'Git\usr\bin\openssl.exe'
+}
+& $openssl pkeyutl -verify -pubin -inkey $publicKey -rawin -in $checksums -sigfile $signature | Out-Null
+if ($LASTEXITCODE -ne 0) {
+  Write-Output 'Signature of checksums.txt does not verify with the release signing key'
+  exit 1
+}
+
+# Verify the binary against the signed checksums before running it
+$expected = Get-Content $checksums |
+  ForEach-Object { $fields = $_ -split '\s+'; if ($fields[1].TrimStart('*') -eq $asset) { $fields[0] } } |
+  Select-Object -First 1
+if (-not $expected) {
+  Write-Output "No checksum published for $asset"
+  exit 1
+}
+$actual = (Get-FileHash -Algorithm SHA256 -Path $binary).Hash.ToLowerInvariant()
+if ($actual -ne $expected.ToLowerInvariant()) {
+  Write-Output "Checksum mismatch for ${asset}: expected $expected, got $actual"
+  exit 1
+}
+
+# The binary reads the policy content from ACTION_CONTROL_POLICY_CONTENT
+$arguments = @('enforce', '--repo', $env:REPO, '--output', $env:OUTPUT_FORMAT, '--ignore-local-policy')
+if ($env:CACHE_FILE) {
+  $arguments += @('--cache-file', $env:CACHE_FILE)
+}
+$maxViolations = if ($env:MAX_VIOLATIONS) { $env:MAX_VIOLATIONS } else { '0' }
+$failOnSeverity = if ($env:FAIL_ON_SEVERITY) { $env:FAIL_ON_SEVERITY } else { 'error' }
+$arguments += @('--max-violations', $maxViolations, '--fail-on-severity', $failOnSeverity)
+if ($env:PULL_REQUEST) {
+  $arguments += @('--pull-request', $env:PULL_REQUEST)
+}
+
+& $binary @arguments
+exit $LASTEXITCODE
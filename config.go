@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/config"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// flagBindings maps each setting to the flag bound to it, to tell where its value comes from
+var flagBindings = make(map[string]*pflag.Flag)
+
+// envOnlySettings are settings read from the config file or environment without a flag
+var envOnlySettings = []string{
+	"github_token", "runner_deprecations", "serve_token", "serve_webhook_secret", "redact_secret",
+	"notifications.email.host", "notifications.email.port", "notifications.email.username", "notifications.email.password",
+	"notifications.email.from", "notifications.email.recipients", "notifications.email.subject", "notifications.email.template",
+	"notifications.jira.url", "notifications.jira.username", "notifications.jira.token", "notifications.jira.project",
+	"notifications.jira.issue_type", "notifications.jira.labels",
+}
+
+// bindFlag binds a flag to a setting, so it can also be set in the config file and environment
+func bindFlag(key string, flag *pflag.Flag) {
+	flagBindings[key] = flag
+	viper.BindPFlag(key, flag)
+}
+
+// bindEnv binds every known setting to its environment variables
+func bindEnv() {
+	for _, key := range settingKeys() {
+		viper.BindEnv(append([]string{key}, config.EnvNames(key)...)...)
+	}
+}
+
+// settingList returns a list setting, splitting a single value on commas and whitespace as
+// environment variables and scalar config file entries give it
+func settingList(key string) []string {
+	if value, ok := viper.Get(key).(string); ok {
+		return config.ParseList(value)
+	}
+	return viper.GetStringSlice(key)
+}
+
+// settingValue returns the effective value of a setting, splitting list settings
+func settingValue(key string) interface{} {
+	if flag, ok := flagBindings[key]; ok {
+		switch flag.Value.Type() {
+		case "stringArray", "stringSlice":
+			return settingList(key)
+		}
+	}
+	return viper.Get(key)
+}
+
+// unmarshalSetting decodes a structured setting into target, from the config file or from
+// YAML or JSON in its environment variable
+func unmarshalSetting(key string, target interface{}) error {
+	if value, ok := viper.Get(key).(string); ok {
+		return config.DecodeValue(value, target)
+	}
+	return viper.UnmarshalKey(key, target)
+}
+
+// configPath returns the config file config init and set write to: --config, the file in
+// use, or config.yaml in the current directory
+func configPath() string {
+	if path := viper.GetString("config"); path != "" {
+		return path
+	}
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path
+	}
+	return config.DefaultFile
+}
+
+// settingKeys returns the names of all known settings
+func settingKeys() []string {
+	keys := viper.AllKeys()
+	for _, key := range append(envOnlySettings, config.AliasedSettings()...) {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// settingSource describes where the effective value of a setting comes from, in viper's
+// order of precedence: flag, environment, config file, default
+func settingSource(key string, file *viper.Viper) string {
+	if flag, ok := flagBindings[key]; ok && flag.Changed {
+		return "flag --" + flag.Name
+	}
+	for _, envName := range config.EnvNames(key) {
+		if _, ok := os.LookupEnv(envName); ok {
+			return "env " + envName
+		}
+	}
+	if file != nil && file.IsSet(key) {
+		return "file " + file.ConfigFileUsed()
+	}
+	return "default"
+}
+
+// configFileSettings reads the config file in use on its own, to tell its settings apart
+func configFileSettings() *viper.Viper {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return nil
+	}
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		log.Printf("Warning: Could not read config file %s: %v", path, err)
+		return nil
+	}
+	return file
+}
+
+func runConfigInit(force bool) {
+	path := configPath()
+	if err := config.Init(path, force); err != nil {
+		log.Fatalf("Error creating config file: %v", err)
+	}
+	fmt.Printf("Created %s\n", path)
+}
+
+func runConfigGet(key string, showSecrets bool) {
+	key = strings.ToLower(key)
+	if !slices.Contains(settingKeys(), key) {
+		log.Fatalf("Unknown setting %q, run 'action-control config view' to list them", key)
+	}
+
+	value := settingValue(key)
+	if !showSecrets {
+		value = config.Redact(key, value)
+	}
+	switch v := value.(type) {
+	case []string:
+		fmt.Println(strings.Join(v, ","))
+	case nil:
+		fmt.Println()
+	default:
+		fmt.Println(v)
+	}
+}
+
+func runConfigSet(key, value string) {
+	key = strings.ToLower(key)
+	if !slices.Contains(settingKeys(), key) {
+		log.Printf("Warning: %q is not a known setting", key)
+	}
+	if config.IsSecret(key) {
+		log.Printf("Warning: %s is stored in plain text; consider 'action-control auth login' instead", key)
+	}
+
+	path := configPath()
+	if err := config.Set(path, key, value); err != nil {
+		log.Fatalf("Error updating config file: %v", err)
+	}
+	fmt.Printf("Set %s in %s\n", key, path)
+}
+
+func runConfigView(showSecrets bool) {
+	file := configFileSettings()
+
+	var settings []config.Setting
+	for _, key := range settingKeys() {
+		// Settings without a flag only show when they are set
+		if _, bound := flagBindings[key]; !bound && !viper.IsSet(key) {
+			continue
+		}
+		settings = append(settings, config.Setting{
+			Key:    key,
+			Value:  settingValue(key),
+			Source: settingSource(key, file),
+		})
+	}
+
+	output, err := config.Render(settings, showSecrets)
+	if err != nil {
+		log.Fatalf("Error rendering configuration: %v", err)
+	}
+	fmt.Print(output)
+}
+
+func runConfigEnv() {
+	for _, key := range settingKeys() {
+		fmt.Printf("%s: %s\n", key, strings.Join(config.EnvNames(key), ", "))
+	}
+}
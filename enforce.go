@@ -0,0 +1,885 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/artifacts"
+	"github.com/ihavespoons/action-control/internal/cloud"
+	"github.com/ihavespoons/action-control/internal/deadlinks"
+	"github.com/ihavespoons/action-control/internal/deprecations"
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/hygiene"
+	"github.com/ihavespoons/action-control/internal/lint"
+	"github.com/ihavespoons/action-control/internal/metadata"
+	"github.com/ihavespoons/action-control/internal/pinning"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/runtimes"
+	"github.com/ihavespoons/action-control/internal/suppress"
+	"github.com/ihavespoons/action-control/internal/updates"
+	"github.com/ihavespoons/action-control/internal/version"
+	"github.com/spf13/viper"
+)
+
+// enforcement evaluates repositories against policy one at a time as their workflow files
+// arrive, keeping only the outcome of each repository rather than its workflow files
+type enforcement struct {
+	ctx               context.Context
+	client            *github.Client
+	policy            *policy.PolicyConfig
+	ignoreLocalPolicy bool
+	linter            *lint.Linter      // nil unless --lint is set
+	pinVerifier       *pinning.Verifier // nil unless --verify-pins is set
+	withReport        bool
+	detailed          bool                // Describe each violation for JSON output and annotations
+	resolveRefs       bool                // Resolve violating references to commits for JSON output
+	resolvedSHAs      map[string]string   // Resolved commits of action references by reference
+	explain           bool                // Record which rule decided each action for --explain
+	offline           bool                // Evaluating a scan file, without the checks that need the API
+	partial           bool                // Evaluating only the workflow files a pull request changes
+	baseReferences    map[string][]string // Action references of the changed workflow files before the pull request, by repository
+
+	violations       map[string][]string
+	lintFindings     map[string][]lint.Finding
+	cloudAccess      map[string][]cloud.Access
+	cloudDenied      int
+	pinDrift         map[string][]pinning.Drift
+	missingUpdates   map[string]updates.Coverage
+	unprotected      map[string]github.WorkflowProtection
+	unownedWorkflows map[string][]string
+	imageViolations  map[string][]github.Image
+	workflowCalls    map[string][]github.WorkflowCall // Reusable workflow calls violating the policy
+	actionHealth     map[string][]metadata.Finding
+	missingActions   map[string][]deadlinks.Finding
+	actionRuntimes   map[string][]runtimes.Finding
+	deprecations     map[string][]deprecations.Warning
+	unresolved       map[string][]github.UnresolvedReference
+	typosquats       map[string][]policy.Typosquat
+	artifactMisuse   map[string][]artifacts.Finding
+	mergeConflicts   map[string][]policy.MergeConflict
+	repoPolicyIssues map[string]string // Why a required repository policy file is not usable
+	suppressed       map[string][]suppress.Suppression
+	preexisting      map[string][]string // Violating references a pull request's changed files used before it
+	missingRequired  map[string][]string // Required actions the repository's workflows do not use
+	missingWorkflows map[string][]policy.RequiredWorkflow
+	belowMinVersion  map[string][]policy.VersionFloorViolation
+	inputViolations  map[string][]policy.InputViolation
+	hygiene          map[string][]hygiene.Finding
+	explanations     map[string][]policy.Explanation
+	usage            map[string][]formatter.Action // Action usage for --with-report
+	references       map[string][]string           // Distinct action references by repository, for the run history
+	report           formatter.EnforceReport
+}
+
+// newEnforcement prepares the evaluation of repositories against localPolicy
+func newEnforcement(ctx context.Context, client *github.Client, localPolicy *policy.PolicyConfig, ignoreLocalPolicy bool) *enforcement {
+	e := &enforcement{
+		ctx:               ctx,
+		client:            client,
+		policy:            localPolicy,
+		ignoreLocalPolicy: ignoreLocalPolicy,
+		withReport:        viper.GetBool("with_report"),
+		detailed:          hasOutputFormat("json") || hasOutputFormat("template") || hasOutputFormat("sarif") || viper.GetBool("annotations"),
+		resolveRefs:       hasOutputFormat("json") || hasOutputFormat("template"),
+		resolvedSHAs:      make(map[string]string),
+		explain:           viper.GetBool("explain"),
+
+		violations:       make(map[string][]string),
+		lintFindings:     make(map[string][]lint.Finding),
+		cloudAccess:      make(map[string][]cloud.Access),
+		pinDrift:         make(map[string][]pinning.Drift),
+		missingUpdates:   make(map[string]updates.Coverage),
+		unprotected:      make(map[string]github.WorkflowProtection),
+		unownedWorkflows: make(map[string][]string),
+		imageViolations:  make(map[string][]github.Image),
+		workflowCalls:    make(map[string][]github.WorkflowCall),
+		actionHealth:     make(map[string][]metadata.Finding),
+		missingActions:   make(map[string][]deadlinks.Finding),
+		actionRuntimes:   make(map[string][]runtimes.Finding),
+		deprecations:     make(map[string][]deprecations.Warning),
+		unresolved:       make(map[string][]github.UnresolvedReference),
+		typosquats:       make(map[string][]policy.Typosquat),
+		artifactMisuse:   make(map[string][]artifacts.Finding),
+		mergeConflicts:   make(map[string][]policy.MergeConflict),
+		repoPolicyIssues: make(map[string]string),
+		suppressed:       make(map[string][]suppress.Suppression),
+		preexisting:      make(map[string][]string),
+		missingRequired:  make(map[string][]string),
+		missingWorkflows: make(map[string][]policy.RequiredWorkflow),
+		belowMinVersion:  make(map[string][]policy.VersionFloorViolation),
+		inputViolations:  make(map[string][]policy.InputViolation),
+		hygiene:          make(map[string][]hygiene.Finding),
+		explanations:     make(map[string][]policy.Explanation),
+		usage:            make(map[string][]formatter.Action),
+		references:       make(map[string][]string),
+		report: formatter.EnforceReport{
+			SchemaVersion: formatter.EnforceSchemaVersion,
+			PolicyMode:    localPolicy.PolicyMode,
+			Repositories:  make(map[string]formatter.RepositoryResult),
+			Violations:    []formatter.Violation{},
+		},
+	}
+
+	// Run actionlint as an optional rule group
+	if viper.GetBool("lint") {
+		e.linter = lint.NewLinter()
+		if !e.linter.Available() {
+			log.Fatal("actionlint not found in PATH. Install it from https://github.com/rhysd/actionlint or disable --lint.")
+		}
+	}
+
+	// Verify that commented SHA pins still match their tags
+	if viper.GetBool("verify_pins") {
+		e.pinVerifier = pinning.NewVerifier(client)
+	}
+
+	return e
+}
+
+// repoEvaluation is the state of evaluating one repository, shared by the checks that fill in
+// its result
+type repoEvaluation struct {
+	name, owner, repo string // Full name, and its owner and repository parts
+	files             []github.WorkflowFile
+	actions           []github.Action
+	uses              []string // Action references, in the order of actions
+	policy            *policy.PolicyConfig
+	override          bool               // Whether a repository policy file was merged into the policy
+	listViolations    int                // Number of leading violations from the action lists, before unverified creators
+	repository        *github.Repository // Looked up once, by the checks that need it
+	effective         policy.EffectivePolicy
+	result            formatter.RepositoryResult
+}
+
+// evaluate checks a repository's workflow files against policy and records the outcome
+func (e *enforcement) evaluate(repoFullName string, files []github.WorkflowFile) {
+	owner, repoName, ok := strings.Cut(repoFullName, "/")
+	if !ok {
+		return
+	}
+	r := &repoEvaluation{name: repoFullName, owner: owner, repo: repoName, files: files, actions: github.ExtractActions(files)}
+	r.uses = make([]string, len(r.actions))
+	for i, action := range r.actions {
+		r.uses[i] = action.Uses
+	}
+
+	e.recordUsage(r)
+	e.lintWorkflows(r)
+	e.resolveRepoPolicy(r)
+	e.checkActions(r)
+	e.checkWorkflowRules(r)
+	e.checkActionRepositories(r)
+	e.resolveEffective(r)
+	e.describeViolations(r)
+	e.explainActions(r)
+	e.checkRepositorySettings(r)
+
+	result := r.result
+	result.MergeConflicts = e.mergeConflicts[repoFullName]
+	result.EffectivePolicy = r.effective
+	// A repository complies when none of its findings fail the policy; deprecations only fail it
+	// once the policy escalates them
+	result.Compliant = result.Complies(deprecations.Failing(r.policy))
+	result.Severities = result.CountSeverities(deprecations.Failing(r.policy))
+	e.report.Repositories[repoFullName] = result
+
+	// Give early feedback on large scans instead of waiting for the final report
+	if !result.Compliant && viper.GetBool("verbose") {
+		log.Printf("%s is not compliant with the policy", repoFullName)
+	}
+}
+
+// recordUsage records the action references of a repository for the run history, and its
+// action usage for --with-report
+func (e *enforcement) recordUsage(r *repoEvaluation) {
+	if e.withReport {
+		e.usage[r.name] = usageActions(map[string][]github.Action{r.name: r.actions})[r.name]
+	}
+	for _, uses := range r.uses {
+		if !slices.Contains(e.references[r.name], uses) {
+			e.references[r.name] = append(e.references[r.name], uses)
+		}
+	}
+	slices.Sort(e.references[r.name])
+}
+
+// lintWorkflows runs actionlint over the workflow files with --lint and checks commented SHA
+// pins with --verify-pins
+func (e *enforcement) lintWorkflows(r *repoEvaluation) {
+	if e.linter != nil {
+		findings, err := e.linter.LintFiles(r.files)
+		if err != nil {
+			log.Printf("Warning: Could not lint workflows in repository %s: %v", r.name, err)
+		} else if len(findings) > 0 {
+			e.lintFindings[r.name] = findings
+		}
+	}
+	r.result.LintFindings = e.lintFindings[r.name]
+
+	if e.pinVerifier != nil {
+		if drift := e.pinVerifier.Verify(e.ctx, r.actions); len(drift) > 0 {
+			e.pinDrift[r.name] = drift
+		}
+	}
+	r.result.PinDrift = e.pinDrift[r.name]
+}
+
+// resolveRepoPolicy merges the repository's own policy file into the policy, unless the
+// policy or the flag ignores them, and records why a required one is not usable
+func (e *enforcement) resolveRepoPolicy(r *repoEvaluation) {
+	r.policy = e.policy
+	if e.ignoreLocalPolicy || e.policy.RepoPolicy == policy.RepoPolicyIgnore {
+		return
+	}
+
+	var issue string
+	content, err := e.client.GetRepositoryContent(e.ctx, r.owner, r.repo, policy.RepoPolicyPath)
+	if err == nil && len(content) > 0 {
+		merged, err := policy.MergePolicies(e.policy, content, r.name)
+		if err != nil {
+			// Fall back to local policy on error
+			log.Printf("Warning: Could not parse policy file in repository %s: %v", r.name, err)
+			issue = fmt.Sprintf("%s could not be parsed", policy.RepoPolicyPath)
+		} else {
+			r.policy = merged.Policy
+			if e.offline {
+				r.policy = withoutLiveChecks(r.policy)
+			}
+			r.override = true
+			if len(merged.Conflicts) > 0 {
+				e.mergeConflicts[r.name] = merged.Conflicts
+				log.Printf("Warning: Policy file in repository %s conflicts with the global policy in %d places", r.name, len(merged.Conflicts))
+			}
+		}
+	} else {
+		issue = fmt.Sprintf("no %s", policy.RepoPolicyPath)
+	}
+
+	if issue != "" && e.policy.RepoPolicy == policy.RepoPolicyRequire {
+		e.repoPolicyIssues[r.name] = issue
+		r.result.RepoPolicyIssue = issue
+	}
+}
+
+// checkActions checks the actions against the policy, under the rules of their workflow
+// files, and their publishers when the policy requires verified creators. Violations ignore
+// annotations suppress are set aside, as are those a pull request's changed files already had.
+func (e *enforcement) checkActions(r *repoEvaluation) {
+	annotations, errs := suppress.Parse(r.files)
+	for _, err := range errs {
+		log.Printf("Warning: Ignoring invalid annotation in repository %s: %v", r.name, err)
+	}
+	now := time.Now()
+	violations, suppressed := suppress.Apply(annotations, r.actions, policy.CheckWorkflowCompliance(r.policy, r.name, r.actions), now)
+	violations, preexisting := e.attribute(r.name, violations)
+	r.listViolations = len(violations)
+
+	unverified, suppressedUnverified := suppress.Apply(annotations, r.actions, policy.CheckVerifiedCreators(e.ctx, r.policy, r.name, r.uses, e.client), now)
+	unverified, preexistingUnverified := e.attribute(r.name, unverified)
+	suppressed = append(suppressed, suppressedUnverified...)
+	for _, action := range unverified {
+		if !slices.Contains(violations, action) {
+			violations = append(violations, action)
+		}
+	}
+	for _, action := range preexistingUnverified {
+		if !slices.Contains(preexisting, action) {
+			preexisting = append(preexisting, action)
+		}
+	}
+
+	if len(suppressed) > 0 {
+		e.suppressed[r.name] = suppressed
+	}
+	if len(preexisting) > 0 {
+		e.preexisting[r.name] = preexisting
+	}
+	if len(violations) > 0 {
+		e.violations[r.name] = violations
+	}
+	r.result.Violations = violations
+	r.result.Suppressed = suppressed
+	r.result.Preexisting = preexisting
+}
+
+// lookupRepository returns the repository's details, looking them up once for the checks
+// that depend on them
+func (e *enforcement) lookupRepository(r *repoEvaluation) github.Repository {
+	if r.repository == nil {
+		details, err := e.client.GetRepository(e.ctx, r.owner, r.repo)
+		if err != nil {
+			log.Printf("Warning: Could not get details of repository %s: %v", r.name, err)
+		}
+		r.repository = &details
+	}
+	return *r.repository
+}
+
+// checkWorkflowRules checks the workflow files against the policy's rules beyond the action
+// lists: minimum versions, required actions and workflows, step inputs, images, reusable
+// workflows, look-alike actions, hygiene and artifacts crossing trust boundaries
+func (e *enforcement) checkWorkflowRules(r *repoEvaluation) {
+	r.result.BelowMinVersion = policy.CheckMinVersions(r.policy, r.name, r.actions)
+	if len(r.result.BelowMinVersion) > 0 {
+		e.belowMinVersion[r.name] = r.result.BelowMinVersion
+	}
+
+	// Only all of a repository's workflows can lack the required actions and workflows, not
+	// the ones a pull request changes
+	if !e.partial {
+		r.result.MissingRequired = policy.CheckRequiredActions(r.policy, r.name, r.uses)
+		if len(r.result.MissingRequired) > 0 {
+			e.missingRequired[r.name] = r.result.MissingRequired
+		}
+	}
+
+	var visibility string
+	if policy.InputRulesNeedVisibility(r.policy, r.name) {
+		visibility = e.lookupRepository(r).Visibility
+	}
+	r.result.InputViolations = policy.CheckInputRules(r.policy, r.name, visibility, r.actions)
+	if len(r.result.InputViolations) > 0 {
+		e.inputViolations[r.name] = r.result.InputViolations
+	}
+
+	if !e.partial {
+		var defaultBranch string
+		if policy.HasTriggerRequirements(r.policy, r.name) {
+			defaultBranch = e.lookupRepository(r).DefaultBranch
+		}
+		r.result.MissingWorkflows = policy.CheckRequiredWorkflows(r.policy, r.name, r.files, defaultBranch)
+		if len(r.result.MissingWorkflows) > 0 {
+			e.missingWorkflows[r.name] = r.result.MissingWorkflows
+		}
+	}
+
+	// Check job container and service images against the image policy
+	images := github.ExtractImages(r.files)
+	imageStrings := make([]string, len(images))
+	for i, image := range images {
+		imageStrings[i] = image.Image
+	}
+	deniedImages := policy.CheckImageCompliance(r.policy, r.name, imageStrings)
+	for _, image := range images {
+		if slices.Contains(deniedImages, image.Image) {
+			r.result.ImageViolations = append(r.result.ImageViolations, image)
+		}
+	}
+	if len(r.result.ImageViolations) > 0 {
+		e.imageViolations[r.name] = r.result.ImageViolations
+	}
+
+	// Check the reusable workflows jobs call against the reusable workflow rules
+	deniedCalls := policy.CheckReusableWorkflows(r.policy, r.name, r.uses)
+	for _, action := range r.actions {
+		if slices.Contains(deniedCalls, action.Uses) {
+			r.result.WorkflowCalls = append(r.result.WorkflowCalls, github.WorkflowCall{Uses: action.Uses, Workflow: action.Workflow, Job: action.Job, Line: action.Line, Ref: action.Ref})
+		}
+	}
+	if len(r.result.WorkflowCalls) > 0 {
+		e.workflowCalls[r.name] = r.result.WorkflowCalls
+	}
+
+	r.result.Typosquats = policy.CheckTyposquatting(r.policy, r.name, r.uses)
+	if len(r.result.Typosquats) > 0 {
+		e.typosquats[r.name] = r.result.Typosquats
+	}
+
+	// Look for ignored security check failures and jobs without timeouts
+	r.result.Hygiene = hygiene.Check(r.policy, r.name, r.files)
+	if len(r.result.Hygiene) > 0 {
+		e.hygiene[r.name] = r.result.Hygiene
+	}
+
+	// Look for caches and artifacts crossing trust boundaries
+	r.result.ArtifactMisuse = artifacts.Check(r.policy, r.name, r.files)
+	if len(r.result.ArtifactMisuse) > 0 {
+		e.artifactMisuse[r.name] = r.result.ArtifactMisuse
+	}
+}
+
+// checkActionRepositories checks the repositories publishing the actions: their upkeep,
+// whether they still exist, how their actions run and deprecated runtimes, as well as the
+// cloud identities the workflows assume
+func (e *enforcement) checkActionRepositories(r *repoEvaluation) {
+	r.result.ActionHealth = metadata.Check(e.ctx, r.policy, r.name, r.uses, e.client, time.Now())
+	if len(r.result.ActionHealth) > 0 {
+		e.actionHealth[r.name] = r.result.ActionHealth
+	}
+
+	// Look for references to deleted, private or renamed action repositories and owners, and
+	// missing refs
+	r.result.MissingActions = deadlinks.Check(e.ctx, r.policy, r.name, r.uses, e.client)
+	if len(r.result.MissingActions) > 0 {
+		e.missingActions[r.name] = r.result.MissingActions
+	}
+
+	r.result.ActionRuntimes = runtimes.Check(e.ctx, r.policy, r.name, r.uses, e.client)
+	if len(r.result.ActionRuntimes) > 0 {
+		e.actionRuntimes[r.name] = r.result.ActionRuntimes
+	}
+
+	// Look for retired runner labels and deprecated Node.js runtimes
+	r.result.Deprecations = deprecations.Check(e.ctx, r.policy, r.name, r.files, r.uses, e.client)
+	if len(r.result.Deprecations) > 0 {
+		e.deprecations[r.name] = r.result.Deprecations
+	}
+
+	r.result.CloudAccess = cloud.Detect(r.policy, r.name, r.actions)
+	if len(r.result.CloudAccess) > 0 {
+		e.cloudAccess[r.name] = r.result.CloudAccess
+	}
+	e.cloudDenied += len(cloud.Denied(r.result.CloudAccess))
+}
+
+// resolveEffective records which policy layers produced the repository's outcome
+func (e *enforcement) resolveEffective(r *repoEvaluation) {
+	r.effective = r.withOverride(policy.ResolveEffectivePolicy(r.policy, r.name))
+}
+
+// withOverride adds the repository policy file to the layers of an effective policy when it
+// was merged
+func (r *repoEvaluation) withOverride(effective policy.EffectivePolicy) policy.EffectivePolicy {
+	if r.override {
+		effective.Layers = append([]string{policy.LayerGlobal, policy.LayerRepoOverride}, effective.Layers[1:]...)
+	}
+	return effective
+}
+
+// workflowEffective returns the effective policy of a workflow file: path-scoped custom rules
+// decide on the actions of the workflow files they match
+func (r *repoEvaluation) workflowEffective(workflow string) policy.EffectivePolicy {
+	if !policy.HasPathRules(r.policy, r.name) {
+		return r.effective
+	}
+	return r.withOverride(policy.ResolveWorkflowPolicy(r.policy, r.name, workflow))
+}
+
+// describeViolations describes each violating reference for machine-readable output
+func (e *enforcement) describeViolations(r *repoEvaluation) {
+	if !e.detailed {
+		return
+	}
+	// Job rules further restrict the actions of the jobs they match
+	scoped := policy.HasPathRules(r.policy, r.name) || policy.HasJobRules(r.policy, r.name)
+	for _, action := range r.actions {
+		index := slices.Index(r.result.Violations, action.Uses)
+		if index < 0 {
+			continue
+		}
+
+		actionEffective := r.workflowEffective(action.Workflow)
+		rule, entry := policy.MatchedRule(actionEffective, action.Uses)
+		if index >= r.listViolations {
+			rule, entry = policy.RuleVerifiedCreator, ""
+		} else if scoped && policy.ExplainEffective(actionEffective, r.name, action.Uses).Allowed {
+			// Allowed in this workflow file, so denied by a job rule or violating elsewhere
+			denial, denied := policy.CheckJobRules(r.policy, r.name, action)
+			if !denied {
+				continue
+			}
+			rule, entry = policy.RuleJobRules, denial.Rule
+		}
+		e.report.Violations = append(e.report.Violations, formatter.Violation{
+			Repository:  r.name,
+			Workflow:    action.Workflow,
+			Line:        action.Line,
+			Job:         action.Job,
+			Action:      action.Uses,
+			ResolvedSHA: e.resolveSHA(action.Uses),
+			Rule:        rule,
+			Entry:       entry,
+			Mode:        actionEffective.PolicyMode,
+			Severity:    policy.SeverityError,
+			Source:      policy.RuleSource(actionEffective, rule),
+		})
+	}
+}
+
+// explainActions records which rule decided each action with --explain, once for each
+// action, or once for each path-scoped rule and job rule deciding on it
+func (e *enforcement) explainActions(r *repoEvaluation) {
+	if !e.explain {
+		return
+	}
+	jobRules := policy.HasJobRules(r.policy, r.name)
+	explained := make(map[[3]string]bool)
+	for i, action := range r.uses {
+		// Reusable workflows under their own rules are listed in their own section
+		if policy.IsReusableWorkflowCall(r.policy, action) {
+			continue
+		}
+		actionEffective := r.workflowEffective(r.actions[i].Workflow)
+		explanation := policy.ExplainEffective(actionEffective, r.name, action)
+		var denial policy.JobRuleDenial
+		if explanation.Allowed && jobRules && !explanation.Excluded {
+			denial, _ = policy.CheckJobRules(r.policy, r.name, r.actions[i])
+		}
+		key := [3]string{action, actionEffective.CustomRule, denial.Rule}
+		if explained[key] {
+			continue
+		}
+		explained[key] = true
+
+		if denial.Rule != "" {
+			explanation.Reason = fmt.Sprintf("%s, but %s", explanation.Reason, denial.Reason)
+			explanation.Allowed = false
+			explanation.Rule, explanation.Entry = policy.RuleJobRules, denial.Rule
+			explanation.Source = policy.RuleSource(actionEffective, policy.RuleJobRules)
+		}
+		if index := slices.Index(r.result.Violations, action); explanation.Allowed && index >= r.listViolations {
+			explanation.Reason = fmt.Sprintf("%s, but publisher %q is not a verified creator", explanation.Reason, policy.ActionOwner(action))
+			explanation.Allowed = false
+			explanation.Rule, explanation.Entry = policy.RuleVerifiedCreator, ""
+		}
+		if !explanation.Allowed && !slices.Contains(r.result.Violations, action) && slices.ContainsFunc(r.result.Suppressed, func(s suppress.Suppression) bool { return s.Action == action && !s.Expired }) {
+			explanation.Reason += ", but suppressed by an ignore annotation"
+		}
+		r.result.Explanations = append(r.result.Explanations, explanation)
+	}
+	e.explanations[r.name] = r.result.Explanations
+}
+
+// checkRepositorySettings checks the repository beyond its workflow files' contents: that its
+// actions are kept up to date, that workflow changes need review from the designated owners,
+// and that uses values can be evaluated. Excluded repositories are not checked.
+func (e *enforcement) checkRepositorySettings(r *repoEvaluation) {
+	if r.effective.Excluded {
+		return
+	}
+
+	if r.policy.RequireActionsUpdates && len(r.actions) > 0 {
+		coverage := updates.Check(e.ctx, e.client, r.owner, r.repo)
+		r.result.ActionsUpdates = &coverage
+		if !coverage.Covered {
+			e.missingUpdates[r.name] = coverage
+		}
+	}
+
+	paths := make([]string, len(r.files))
+	for i, file := range r.files {
+		paths[i] = file.Path
+	}
+	if r.policy.RequireWorkflowProtection && len(r.files) > 0 {
+		protection, err := e.client.GetWorkflowProtection(e.ctx, r.owner, r.repo, paths)
+		if err != nil {
+			log.Printf("Warning: Could not check workflow protection in repository %s: %v", r.name, err)
+		} else {
+			r.result.WorkflowProtection = &protection
+			if !protection.Protected {
+				e.unprotected[r.name] = protection
+			}
+		}
+	}
+
+	// Report uses values whose expressions cannot be evaluated, as their actions escape the policy
+	r.result.UnresolvedRefs = github.ExtractUnresolvedReferences(r.files)
+	if len(r.result.UnresolvedRefs) > 0 {
+		e.unresolved[r.name] = r.result.UnresolvedRefs
+	}
+
+	if len(r.policy.WorkflowOwners) > 0 && len(r.files) > 0 {
+		r.result.UnownedWorkflows = e.client.GetCodeOwners(e.ctx, r.owner, r.repo).NotOwnedBy(paths, r.policy.WorkflowOwners)
+		if len(r.result.UnownedWorkflows) > 0 {
+			e.unownedWorkflows[r.name] = r.result.UnownedWorkflows
+		}
+	}
+}
+
+// attribute splits the violating references of a repository into those a pull request's
+// changes introduce and those its changed workflow files already used before it. Outside of
+// pull requests every violation is attributed to the repository.
+func (e *enforcement) attribute(repoFullName string, violations []string) (introduced, preexisting []string) {
+	base := e.baseReferences[repoFullName]
+	for _, action := range violations {
+		if slices.Contains(base, action) {
+			preexisting = append(preexisting, action)
+		} else {
+			introduced = append(introduced, action)
+		}
+	}
+	return introduced, preexisting
+}
+
+// goOffline prepares the evaluation of a scan file: checks that look up state the scan file
+// doesn't capture, such as the repositories publishing actions, are turned off with a warning,
+// rather than reporting every action as missing or passing them unchecked
+func (e *enforcement) goOffline() {
+	e.offline = true
+	e.resolveRefs = false
+	if e.pinVerifier != nil {
+		log.Printf("Warning: --verify-pins needs API access, skipped when evaluating a scan file")
+		e.pinVerifier = nil
+	}
+	for _, setting := range liveChecks(e.policy) {
+		log.Printf("Warning: %s needs API access, skipped when evaluating a scan file", setting)
+	}
+	e.policy = withoutLiveChecks(e.policy)
+}
+
+// liveChecks returns the policy settings whose checks need the API beyond the repository's own
+// files, such as to look up the repositories publishing actions or branch protection
+func liveChecks(config *policy.PolicyConfig) []string {
+	var settings []string
+	if config.RequireVerifiedCreator {
+		settings = append(settings, "require_verified_creator")
+	}
+	if metadata.HasThresholds(config) {
+		settings = append(settings, "deny_archived_actions, deny_fork_actions, max_staleness_days and min_repository_age_days")
+	}
+	if deadlinks.Enabled(config) {
+		settings = append(settings, "detect_missing_actions and detect_namespace_takeover")
+	}
+	if runtimes.HasRules(config) {
+		settings = append(settings, "deny_docker_actions_from_unknown_registries and deny_node16_actions")
+	}
+	if config.RequireWorkflowProtection {
+		settings = append(settings, "require_workflow_protection")
+	}
+	return settings
+}
+
+// withoutLiveChecks returns a copy of the policy with the checks listed by liveChecks turned off
+func withoutLiveChecks(config *policy.PolicyConfig) *policy.PolicyConfig {
+	offline := *config
+	offline.RequireVerifiedCreator = false
+	offline.DenyArchivedActions = false
+	offline.DenyForkActions = false
+	offline.MaxStalenessDays = 0
+	offline.MinRepositoryAgeDays = 0
+	offline.DetectMissingActions = false
+	offline.DetectNamespaceTakeover = false
+	offline.DenyDockerActionsFromUnknownRegistries = false
+	offline.DenyNode16Actions = false
+	offline.RequireWorkflowProtection = false
+	return &offline
+}
+
+// resolveSHA returns the commit an action reference resolves to: the reference itself when
+// pinned to a SHA, or the commit of its tag. Other references resolve to an empty string.
+func (e *enforcement) resolveSHA(uses string) string {
+	if !e.resolveRefs {
+		return ""
+	}
+	if sha, ok := e.resolvedSHAs[uses]; ok {
+		return sha
+	}
+
+	var sha string
+	name, ref, _ := strings.Cut(uses, "@")
+	parts := strings.SplitN(name, "/", 3)
+	switch kind, ok := pinning.Classify(uses); {
+	case !ok || len(parts) < 2 || strings.HasPrefix(uses, "docker://"):
+	case kind == pinning.SHA:
+		sha = ref
+	case kind == pinning.Tag:
+		sha, _ = e.client.ResolveTag(e.ctx, parts[0], parts[1], ref)
+	}
+
+	e.resolvedSHAs[uses] = sha
+	return sha
+}
+
+// render formats the enforcement outcome, along with the usage report from the same scan
+// when requested. JSON and custom templates render the report structure, and markdown is
+// rendered for any other format.
+func (e *enforcement) render(format string) (string, error) {
+	var output strings.Builder
+
+	if format == "json" || format == "template" {
+		if e.withReport {
+			e.report.Usage = e.usage
+		}
+	}
+	if format == "template" {
+		return renderTemplate(e.report)
+	}
+	if format == "json" {
+		jsonData, err := formatter.FormatJSON(e.report)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintln(&output, jsonData)
+		return output.String(), nil
+	}
+	if format == "sarif" {
+		return formatter.FormatSARIF(e.report, deprecations.Failing(e.policy), version.Version)
+	}
+
+	// Lead with a matrix of findings by severity and group the sections by severity, unless
+	// there is nothing to triage
+	errors, warnings := e.findingCounts()
+	bySeverity := errors+warnings+len(e.mergeConflicts)+len(e.suppressed)+len(e.preexisting) > 0
+	deprecationsFail := deprecations.Failing(e.policy)
+
+	if e.report.Interrupted != "" {
+		fmt.Fprintln(&output, formatter.FormatScanInterrupted(e.report.Interrupted))
+	}
+	if e.withReport {
+		fmt.Fprintln(&output, formatter.FormatMarkdown(e.usage))
+	}
+	if bySeverity {
+		fmt.Fprintln(&output, formatter.FormatSeverityMatrix(e.report))
+		fmt.Fprintln(&output, formatter.FormatSeverityHeading(policy.SeverityError))
+	}
+	fmt.Fprintln(&output, formatter.FormatPolicyViolations(e.violations, e.report.RepositoryOwners(), e.policy.PolicyMode, viper.GetString("group_violations_by")))
+	if e.linter != nil {
+		fmt.Fprintln(&output, formatter.FormatLintFindings(e.lintFindings))
+	}
+	if len(e.cloudAccess) > 0 || len(e.policy.CloudAccess) > 0 {
+		fmt.Fprintln(&output, formatter.FormatCloudAccess(e.cloudAccess))
+	}
+	if e.pinVerifier != nil {
+		fmt.Fprintln(&output, formatter.FormatPinDrift(e.pinDrift))
+	}
+	if len(e.policy.MinVersions) > 0 {
+		fmt.Fprintln(&output, formatter.FormatMinVersions(e.belowMinVersion))
+	}
+	if len(e.policy.InputRules) > 0 {
+		fmt.Fprintln(&output, formatter.FormatInputViolations(e.inputViolations))
+	}
+	if len(e.policy.RequiredActions) > 0 {
+		fmt.Fprintln(&output, formatter.FormatRequiredActions(e.missingRequired))
+	}
+	if len(e.policy.RequiredWorkflows) > 0 {
+		fmt.Fprintln(&output, formatter.FormatRequiredWorkflows(e.missingWorkflows))
+	}
+	if policy.HasImagePolicy(e.policy) {
+		fmt.Fprintln(&output, formatter.FormatImageViolations(e.imageViolations))
+	}
+	if policy.HasReusableWorkflowPolicy(e.policy) {
+		fmt.Fprintln(&output, formatter.FormatReusableWorkflowViolations(e.workflowCalls))
+	}
+	if e.policy.RequireActionsUpdates {
+		fmt.Fprintln(&output, formatter.FormatActionsUpdates(e.missingUpdates))
+	}
+	if e.policy.RequireWorkflowProtection {
+		fmt.Fprintln(&output, formatter.FormatWorkflowProtection(e.unprotected))
+	}
+	if len(e.policy.WorkflowOwners) > 0 {
+		fmt.Fprintln(&output, formatter.FormatWorkflowOwners(e.unownedWorkflows, e.policy.WorkflowOwners))
+	}
+	if e.policy.DetectTyposquatting {
+		fmt.Fprintln(&output, formatter.FormatTyposquats(e.typosquats))
+	}
+	if e.policy.DetectArtifactMisuse {
+		fmt.Fprintln(&output, formatter.FormatArtifactMisuse(e.artifactMisuse))
+	}
+	if hygiene.Enabled(e.policy) {
+		fmt.Fprintln(&output, formatter.FormatHygiene(e.hygiene))
+	}
+	if metadata.HasThresholds(e.policy) {
+		fmt.Fprintln(&output, formatter.FormatActionHealth(e.actionHealth))
+	}
+	if deadlinks.Enabled(e.policy) {
+		fmt.Fprintln(&output, formatter.FormatMissingActions(e.missingActions))
+	}
+	if runtimes.HasRules(e.policy) {
+		fmt.Fprintln(&output, formatter.FormatActionRuntimes(e.actionRuntimes))
+	}
+	if len(e.deprecations) > 0 && deprecationsFail {
+		fmt.Fprintln(&output, formatter.FormatDeprecations(e.deprecations, true))
+	}
+	if len(e.unresolved) > 0 {
+		fmt.Fprintln(&output, formatter.FormatUnresolvedReferences(e.unresolved))
+	}
+	if e.policy.RepoPolicy == policy.RepoPolicyRequire && !e.ignoreLocalPolicy {
+		fmt.Fprintln(&output, formatter.FormatRepoPolicyIssues(e.repoPolicyIssues))
+	}
+	if len(e.deprecations) > 0 && !deprecationsFail {
+		fmt.Fprintln(&output, formatter.FormatSeverityHeading(policy.SeverityWarning))
+		fmt.Fprintln(&output, formatter.FormatDeprecations(e.deprecations, false))
+	}
+	if bySeverity && len(e.mergeConflicts)+len(e.suppressed)+len(e.preexisting) > 0 {
+		fmt.Fprintln(&output, formatter.FormatSeverityHeading(policy.SeverityInfo))
+	}
+	if len(e.mergeConflicts) > 0 {
+		fmt.Fprintln(&output, formatter.FormatMergeConflicts(e.mergeConflicts))
+	}
+	if len(e.suppressed) > 0 {
+		fmt.Fprintln(&output, formatter.FormatSuppressions(e.suppressed))
+	}
+	if len(e.preexisting) > 0 {
+		fmt.Fprintln(&output, formatter.FormatPreexistingViolations(e.preexisting))
+	}
+	if e.explain {
+		fmt.Fprintln(&output, formatter.FormatExplanations(e.explanations))
+	}
+
+	return output.String(), nil
+}
+
+// summary returns a one-line outcome for the console when all reports are written to files
+func (e *enforcement) summary() string {
+	failing := 0
+	for _, result := range e.report.Repositories {
+		if !result.Compliant {
+			failing++
+		}
+	}
+	if e.report.Interrupted != "" {
+		return fmt.Sprintf("⚠️ Scan interrupted, %d of %d scanned repositories do not comply with the action policy.\n", failing, len(e.report.Repositories))
+	}
+	if failing == 0 && !e.failed() {
+		return fmt.Sprintf("✅ All %d repositories comply with the action policy.\n", len(e.report.Repositories))
+	}
+	if !e.failed() {
+		return fmt.Sprintf("⚠️ %d of %d repositories do not comply with the action policy, within the tolerated findings.\n", failing, len(e.report.Repositories))
+	}
+	return fmt.Sprintf("❌ %d of %d repositories do not comply with the action policy.\n", failing, len(e.report.Repositories))
+}
+
+// Severities of findings that can fail a run, as chosen with --fail-on-severity
+const (
+	failOnError   = "error"   // Findings failing the policy (default)
+	failOnWarning = "warning" // Deprecation warnings too
+	failOnNone    = "none"    // Report without failing
+)
+
+// findingCounts counts the findings across evaluated repositories that fail the policy, and the
+// deprecation warnings that only fail it when the policy escalates them
+func (e *enforcement) findingCounts() (errors, warnings int) {
+	for _, result := range e.report.Repositories {
+		errors += result.Severities.Errors
+		warnings += result.Severities.Warnings
+	}
+	return errors, warnings
+}
+
+// exitFindings is the exit code of enforce runs whose findings fail the policy. Errors exit with
+// 1, so callers such as the server's pull request gate can tell a failed evaluation apart.
+const exitFindings = 2
+
+// failed reports whether the scan was cut short, or whether the findings of the severity chosen
+// with --fail-on-severity exceed the number tolerated with --max-violations
+func (e *enforcement) failed() bool {
+	if e.report.Interrupted != "" {
+		return true
+	}
+
+	errors, warnings := e.findingCounts()
+	counted := errors
+	switch viper.GetString("fail_on_severity") {
+	case failOnWarning:
+		counted += warnings
+	case failOnNone:
+		return false
+	}
+	return counted > viper.GetInt("max_violations")
+}
+
+// violatingWorkflows returns the workflow files of a repository with violating action references
+func (e *enforcement) violatingWorkflows(repoFullName string) []string {
+	var workflows []string
+	for _, violation := range e.report.Violations {
+		if violation.Repository == repoFullName && violation.Workflow != "" && !slices.Contains(workflows, violation.Workflow) {
+			workflows = append(workflows, violation.Workflow)
+		}
+	}
+	return workflows
+}
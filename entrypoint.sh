@@ -1,31 +1,12 @@
 #!/bin/sh
 set -e
 
-# Extract the command (enforce, report, export) and arguments
-CMD="$1"
-REPO="$3"
-OUTPUT_FORMAT="$5"
-GITHUB_TOKEN="$7"
-POLICY_CONTENT="$9"
-
-# Export GitHub token as environment variable
-if [ -n "$GITHUB_TOKEN" ]; then
-  export ACTION_CONTROL_GITHUB_TOKEN="$GITHUB_TOKEN"
-else
-  echo "GitHub token not provided"
+# Fail clearly when the image's binary doesn't match the runner's architecture, such as an
+# amd64-only image pulled on an arm64 self-hosted runner without emulation
+if ! /app/action-control --version > /dev/null 2>&1; then
+  echo "action-control can't run on this $(uname -m) runner; use an image built for its platform"
   exit 1
 fi
 
-# Check if policy content is provided
-if [ -n "$POLICY_CONTENT" ]; then
-  echo "Using policy content from command line argument"
-  # Create a temporary file for the policy content
-  TEMP_POLICY_FILE="/tmp/policy.yaml"
-  echo "$POLICY_CONTENT" > "$TEMP_POLICY_FILE"
-  
-  # Execute with temporary file and ignore local policy flag
-  exec /app/action-control "$CMD" --repo "$REPO" --output "$OUTPUT_FORMAT" --policy "$TEMP_POLICY_FILE" --ignore-local-policy
-else
-  echo "No policy content provided. Please provide policy content."
-  exit 1
-fi
\ No newline at end of file
+# The binary reads the action inputs from the INPUT_* environment variables the runner sets
+exec /app/action-control "$@"
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+
+	"github.com/spf13/viper"
+)
+
+// Upper bounds of the requests enforce checks make per repository, on top of fetching its
+// workflow files. Checks cached across repositories, such as verified creators, are not counted.
+const (
+	repoPolicyRequests         = 1
+	actionsUpdatesRequests     = 11 // Every Dependabot and Renovate configuration location
+	workflowProtectionRequests = 6  // Repository, rules, branch protection and CODEOWNERS locations
+	workflowOwnersRequests     = 3  // CODEOWNERS locations
+	owningTeamsRequests        = 1
+)
+
+// exitWithEstimate prints the predicted API usage of the scan and exits instead of scanning
+// when --estimate is set. It exits with a non-zero code when the remaining quota is too low.
+func exitWithEstimate(ctx context.Context, client *github.Client, org, repo string, extraPerRepo int) {
+	if !viper.GetBool("estimate") {
+		return
+	}
+
+	estimate, err := client.EstimateScan(ctx, org, repo, extraPerRepo)
+	if err != nil {
+		log.Fatalf("Error estimating scan: %v", err)
+	}
+
+	if outputFormat("markdown") == "json" {
+		jsonData, err := formatter.FormatJSON(estimate)
+		if err != nil {
+			log.Fatalf("Error formatting JSON: %v", err)
+		}
+		fmt.Println(jsonData)
+	} else {
+		fmt.Print(formatter.FormatScanEstimate(estimate))
+	}
+
+	if !estimate.Sufficient() {
+		exit(1)
+	}
+	exit(0)
+}
+
+// enforceRequestsPerRepo returns the requests enforce checks make per repository under a policy
+func enforceRequestsPerRepo(config *policy.PolicyConfig) int {
+	requests := 0
+	if !viper.GetBool("ignore_local_policy") && config.RepoPolicy != policy.RepoPolicyIgnore {
+		requests += repoPolicyRequests
+	}
+	if config.RequireActionsUpdates {
+		requests += actionsUpdatesRequests
+	}
+	if config.RequireWorkflowProtection {
+		requests += workflowProtectionRequests
+	}
+	if len(config.WorkflowOwners) > 0 {
+		requests += workflowOwnersRequests
+	}
+	if viper.GetBool("owners_from_teams") {
+		requests += owningTeamsRequests
+	}
+	return requests
+}
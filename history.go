@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/store"
+	"github.com/ihavespoons/action-control/internal/version"
+	"github.com/spf13/viper"
+)
+
+// newRun records the outcome of an enforce report as a run for the store, along with the action
+// references of each repository
+func newRun(target string, report formatter.EnforceReport, references map[string][]string, startedAt time.Time) store.Run {
+	run := store.Run{
+		ID:          store.NewID(startedAt),
+		Target:      target,
+		StartedAt:   startedAt.UTC().Truncate(time.Second),
+		ToolVersion: version.Version,
+		Interrupted: report.Interrupted,
+		Total:       len(report.Repositories),
+	}
+	for name, result := range report.Repositories {
+		if !result.Compliant {
+			run.NonCompliant++
+		}
+		run.Repositories = append(run.Repositories, store.RepositoryRun{
+			Repository: name,
+			Compliant:  result.Compliant,
+			Findings:   result.Findings(),
+			Errors:     result.Severities.Errors,
+			Warnings:   result.Severities.Warnings,
+			Info:       result.Severities.Info,
+			Owners:     result.Owners,
+			Violations: result.Violations,
+			Actions:    references[name],
+		})
+	}
+	sort.Slice(run.Repositories, func(i, j int) bool {
+		return run.Repositories[i].Repository < run.Repositories[j].Repository
+	})
+	return run
+}
+
+// recordRun saves the outcome of an enforce run in the store given with --store. Failing to
+// record it is reported but does not change the outcome.
+func recordRun(ctx context.Context, target string, enforcement *enforcement, startedAt time.Time) {
+	location := viper.GetString("store")
+	if location == "" {
+		return
+	}
+
+	history, err := store.Open(ctx, location)
+	if err != nil {
+		log.Printf("Warning: Could not record run: %v", err)
+		return
+	}
+	defer history.Close()
+
+	run := newRun(target, enforcement.report, enforcement.references, startedAt)
+	if err := history.Save(ctx, run); err != nil {
+		log.Printf("Warning: Could not record run: %v", err)
+		return
+	}
+	announce("Recorded run %s\n", run.ID)
+}
+
+// runHistory lists the runs recorded in the store, or shows the repository results of one run
+func runHistory(args []string) {
+	location := viper.GetString("store")
+	if location == "" {
+		log.Fatal("--store must name the store runs are recorded in")
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	history, err := store.Open(ctx, location)
+	if err != nil {
+		log.Fatalf("Error opening store: %v", err)
+	}
+	defer history.Close()
+
+	var data interface{}
+	var markdown string
+	if len(args) == 1 {
+		run, err := history.Run(ctx, args[0])
+		if errors.Is(err, store.ErrNotFound) {
+			log.Fatalf("No run %s in the store", args[0])
+		} else if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		data, markdown = run, formatter.FormatRun(run)
+	} else {
+		target := viper.GetString("repository")
+		if target == "" {
+			target = viper.GetString("organization")
+		}
+		runs, err := history.Runs(ctx, store.Filter{Target: target, Limit: viper.GetInt("history_limit")})
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if runs == nil {
+			runs = []store.Run{}
+		}
+		data, markdown = runs, formatter.FormatRuns(runs)
+	}
+
+	switch format := outputFormat("markdown"); format {
+	case "json":
+		jsonData, err := formatter.FormatJSON(data)
+		if err != nil {
+			log.Fatalf("Error formatting JSON: %v", err)
+		}
+		fmt.Println(jsonData)
+	case "markdown":
+		fmt.Print(markdown)
+	default:
+		log.Fatalf("Unsupported output format for history: %s", format)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/impact"
+
+	"github.com/spf13/viper"
+)
+
+func runImpact() {
+	// Validate GitHub token
+	token := resolveToken()
+
+	// Get target organization or repository
+	org := viper.GetString("organization")
+	specificRepo := viper.GetString("repository")
+
+	// At least one target must be specified
+	if org == "" && specificRepo == "" {
+		log.Fatal("Either organization (--org) or specific repository (--repo) must be provided.")
+	}
+
+	// Collect retiring runner labels from config and flags
+	var deprecations []impact.Deprecation
+	if err := unmarshalSetting("runner_deprecations", &deprecations); err != nil {
+		log.Fatalf("Error reading runner_deprecations from config: %v", err)
+	}
+	for _, value := range settingList("deprecated_labels") {
+		deprecation, err := impact.ParseDeprecation(value)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		deprecations = append(deprecations, deprecation)
+	}
+	if len(deprecations) == 0 {
+		log.Fatal("No runner labels to check. Use --label or configure runner_deprecations.")
+	}
+
+	// Set default output format if not specified
+	outputFormat := outputFormat("markdown")
+
+	// Initialize GitHub API client
+	client := newClient(token)
+	ctx, cancel := commandContext()
+	defer cancel()
+	checkTokenScopes(ctx, client, "impact")
+	exitWithEstimate(ctx, client, org, specificRepo, 0)
+
+	// Map to store fetched workflow files by repository
+	workflowFilesMap := make(map[string][]github.WorkflowFile)
+	var err error
+
+	if specificRepo != "" {
+		// Scan a single repository
+		parts := strings.Split(specificRepo, "/")
+		if len(parts) != 2 {
+			log.Fatalf("Invalid repository format. Use 'owner/repo' format.")
+		}
+
+		announce("Scanning repository %s for deprecated runner labels...\n", specificRepo)
+		files, err := client.GetWorkflowFiles(ctx, parts[0], parts[1])
+		if err != nil {
+			log.Fatalf("Error retrieving workflows from repository %s: %v", specificRepo, err)
+		}
+		workflowFilesMap[specificRepo] = files
+	} else {
+		// Scan an entire organization
+		announce("Scanning repositories in %s organization for deprecated runner labels...\n", org)
+		checkpointOrgScan(client, org)
+		workflowFilesMap, err = client.WorkflowFilesForOrg(ctx, org)
+		if scanInterrupted(err) {
+			defer exit(1) // Signal the interruption once the partial results are reported
+		} else if err != nil {
+			log.Fatalf("Error retrieving workflows: %v", err)
+		}
+	}
+
+	// Analyze each repository, resolving owning teams only where impact was found
+	var impacts []impact.Impact
+	for repoFullName, files := range workflowFilesMap {
+		repoImpacts := impact.Analyze(repoFullName, files, deprecations, nil)
+		if len(repoImpacts) == 0 {
+			continue
+		}
+
+		parts := strings.Split(repoFullName, "/")
+		owners := client.GetCodeOwners(ctx, parts[0], parts[1])
+		for i := range repoImpacts {
+			repoImpacts[i].Owners = owners.OwnersFor(repoImpacts[i].Workflow)
+		}
+		impacts = append(impacts, repoImpacts...)
+	}
+	impact.Sort(impacts)
+
+	// Format and output the results
+	switch outputFormat {
+	case "json":
+		jsonData, err := formatter.FormatJSON(impacts)
+		if err != nil {
+			log.Fatalf("Error formatting JSON: %v", err)
+		}
+		fmt.Println(jsonData)
+	case "markdown":
+		fmt.Println(formatter.FormatImpact(impacts))
+	default:
+		log.Fatalf("Unsupported output format: %s", outputFormat)
+	}
+}
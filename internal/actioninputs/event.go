@@ -0,0 +1,41 @@
+package actioninputs
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// pullRequestEvents are the events whose payload is a pull request
+var pullRequestEvents = []string{"pull_request", "pull_request_target"}
+
+// PullRequest returns the number of the pull request of repository that triggered the workflow
+// run, read through getenv and readFile from the event payload the runner provides, or 0 when
+// the run was not triggered by one
+func PullRequest(getenv func(string) string, readFile func(string) ([]byte, error), repository string) (int, error) {
+	event := getenv("GITHUB_EVENT_NAME")
+	if !slices.Contains(pullRequestEvents, event) || getenv("GITHUB_EVENT_PATH") == "" {
+		return 0, nil
+	}
+
+	data, err := readFile(getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the %s event payload: %w", event, err)
+	}
+	var payload struct {
+		Number     int `json:"number"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return 0, fmt.Errorf("failed to parse the %s event payload: %w", event, err)
+	}
+
+	// The action can enforce policy on another repository than the one whose event it handles
+	if !strings.EqualFold(payload.Repository.FullName, repository) {
+		return 0, nil
+	}
+	return payload.Number, nil
+}
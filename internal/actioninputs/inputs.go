@@ -0,0 +1,82 @@
+package actioninputs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Inputs are the GitHub Action's inputs, which the runner passes to the action as INPUT_*
+// environment variables
+type Inputs struct {
+	GitHubToken    string
+	Repository     string
+	OutputFormat   string
+	PolicyContent  string
+	CacheFile      string
+	MaxViolations  int
+	FailOnSeverity string
+	// ChangedFilesOnly evaluates only the workflow files a pull request changes on pull
+	// request events, rather than all of the repository's. Off by default, so violations
+	// already in unchanged workflows keep failing pull requests.
+	ChangedFilesOnly bool
+}
+
+// EnvName returns the environment variable the runner sets for an input
+func EnvName(input string) string {
+	return "INPUT_" + strings.ToUpper(strings.ReplaceAll(input, " ", "_"))
+}
+
+// FromEnv reads the action inputs through getenv, applying the action's defaults to inputs
+// left empty and rejecting missing or malformed required ones
+func FromEnv(getenv func(string) string) (Inputs, error) {
+	input := func(name string) string {
+		return strings.TrimSpace(getenv(EnvName(name)))
+	}
+
+	inputs := Inputs{
+		GitHubToken:    input("github_token"),
+		Repository:     input("github_repository"),
+		OutputFormat:   input("output_format"),
+		PolicyContent:  getenv(EnvName("policy_content")),
+		CacheFile:      input("cache_file"),
+		FailOnSeverity: input("fail_on_severity"),
+	}
+
+	if inputs.GitHubToken == "" {
+		return Inputs{}, fmt.Errorf("GitHub token not provided: set the github_token input")
+	}
+	if strings.TrimSpace(inputs.PolicyContent) == "" {
+		return Inputs{}, fmt.Errorf("no policy content provided: set the policy_content input")
+	}
+	if inputs.Repository == "" {
+		inputs.Repository = getenv("GITHUB_REPOSITORY")
+	}
+	if inputs.Repository == "" {
+		return Inputs{}, fmt.Errorf("no repository to enforce policy on: set the github_repository input")
+	}
+	if inputs.OutputFormat == "" {
+		inputs.OutputFormat = "markdown"
+	}
+	if inputs.FailOnSeverity == "" {
+		inputs.FailOnSeverity = "error"
+	}
+
+	if value := input("max_violations"); value != "" {
+		maxViolations, err := strconv.Atoi(value)
+		if err != nil || maxViolations < 0 {
+			return Inputs{}, fmt.Errorf("max_violations must be a non-negative number, got %q", value)
+		}
+		inputs.MaxViolations = maxViolations
+	}
+
+	if value := input("changed_files_only"); value != "" {
+		changedFilesOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			return Inputs{}, fmt.Errorf("changed_files_only must be true or false, got %q", value)
+		}
+		inputs.ChangedFilesOnly = changedFilesOnly
+	}
+
+	return inputs, nil
+}
@@ -0,0 +1,147 @@
+package actioninputs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func envFrom(values map[string]string) func(string) string {
+	return func(name string) string {
+		return values[name]
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	inputs, err := FromEnv(envFrom(map[string]string{
+		"INPUT_GITHUB_TOKEN":       "token",
+		"INPUT_GITHUB_REPOSITORY":  "org/repo",
+		"INPUT_OUTPUT_FORMAT":      "json",
+		"INPUT_POLICY_CONTENT":     "policy_mode: allow\n",
+		"INPUT_CACHE_FILE":         ".cache.json",
+		"INPUT_MAX_VIOLATIONS":     "3",
+		"INPUT_FAIL_ON_SEVERITY":   "warning",
+		"INPUT_CHANGED_FILES_ONLY": "true",
+	}))
+	if err != nil {
+		t.Fatalf("FromEnv returned an error: %v", err)
+	}
+
+	expected := Inputs{
+		GitHubToken:      "token",
+		Repository:       "org/repo",
+		OutputFormat:     "json",
+		PolicyContent:    "policy_mode: allow\n",
+		CacheFile:        ".cache.json",
+		MaxViolations:    3,
+		FailOnSeverity:   "warning",
+		ChangedFilesOnly: true,
+	}
+	if inputs != expected {
+		t.Errorf("Expected %+v, got %+v", expected, inputs)
+	}
+}
+
+func TestFromEnvDefaults(t *testing.T) {
+	inputs, err := FromEnv(envFrom(map[string]string{
+		"INPUT_GITHUB_TOKEN":   "token",
+		"INPUT_POLICY_CONTENT": "policy_mode: deny\n",
+		"GITHUB_REPOSITORY":    "org/repo",
+	}))
+	if err != nil {
+		t.Fatalf("FromEnv returned an error: %v", err)
+	}
+
+	if inputs.Repository != "org/repo" {
+		t.Errorf("Expected the repository to fall back to GITHUB_REPOSITORY, got %q", inputs.Repository)
+	}
+	if inputs.OutputFormat != "markdown" {
+		t.Errorf("Expected markdown output by default, got %q", inputs.OutputFormat)
+	}
+	if inputs.FailOnSeverity != "error" {
+		t.Errorf("Expected error severity by default, got %q", inputs.FailOnSeverity)
+	}
+	if inputs.MaxViolations != 0 {
+		t.Errorf("Expected no tolerated violations by default, got %d", inputs.MaxViolations)
+	}
+	if inputs.ChangedFilesOnly {
+		t.Error("Expected all workflow files evaluated on pull requests by default")
+	}
+}
+
+func TestFromEnvErrors(t *testing.T) {
+	base := map[string]string{
+		"INPUT_GITHUB_TOKEN":      "token",
+		"INPUT_GITHUB_REPOSITORY": "org/repo",
+		"INPUT_POLICY_CONTENT":    "policy_mode: allow\n",
+	}
+
+	tests := []struct {
+		name     string
+		override map[string]string
+		expected string
+	}{
+		{"missing token", map[string]string{"INPUT_GITHUB_TOKEN": ""}, "github_token"},
+		{"missing policy", map[string]string{"INPUT_POLICY_CONTENT": "  \n"}, "policy_content"},
+		{"missing repository", map[string]string{"INPUT_GITHUB_REPOSITORY": ""}, "github_repository"},
+		{"malformed threshold", map[string]string{"INPUT_MAX_VIOLATIONS": "many"}, "max_violations"},
+		{"negative threshold", map[string]string{"INPUT_MAX_VIOLATIONS": "-1"}, "max_violations"},
+		{"malformed changed files only", map[string]string{"INPUT_CHANGED_FILES_ONLY": "sometimes"}, "changed_files_only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make(map[string]string)
+			for name, value := range base {
+				values[name] = value
+			}
+			for name, value := range tt.override {
+				values[name] = value
+			}
+
+			_, err := FromEnv(envFrom(values))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected an error mentioning %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if name := EnvName("github_token"); name != "INPUT_GITHUB_TOKEN" {
+		t.Errorf("Expected INPUT_GITHUB_TOKEN, got %s", name)
+	}
+	if name := EnvName("policy content"); name != "INPUT_POLICY_CONTENT" {
+		t.Errorf("Expected INPUT_POLICY_CONTENT, got %s", name)
+	}
+}
+
+func TestPullRequest(t *testing.T) {
+	payload := `{"number": 42, "repository": {"full_name": "org/repo"}}`
+	readFile := func(path string) ([]byte, error) {
+		if path != "/github/workflow/event.json" {
+			return nil, errors.New("no such file")
+		}
+		return []byte(payload), nil
+	}
+	env := func(event string) func(string) string {
+		return envFrom(map[string]string{"GITHUB_EVENT_NAME": event, "GITHUB_EVENT_PATH": "/github/workflow/event.json"})
+	}
+
+	for _, event := range []string{"pull_request", "pull_request_target"} {
+		if number, err := PullRequest(env(event), readFile, "Org/Repo"); err != nil || number != 42 {
+			t.Errorf("Expected pull request 42 on %s events, got %d, %v", event, number, err)
+		}
+	}
+	if number, err := PullRequest(env("push"), readFile, "org/repo"); err != nil || number != 0 {
+		t.Errorf("Expected no pull request on push events, got %d, %v", number, err)
+	}
+	if number, err := PullRequest(env("pull_request"), readFile, "org/other"); err != nil || number != 0 {
+		t.Errorf("Expected no pull request for another repository, got %d, %v", number, err)
+	}
+
+	payload = "not json"
+	if _, err := PullRequest(env("pull_request"), readFile, "org/repo"); err == nil {
+		t.Error("Expected an error for an invalid payload")
+	}
+}
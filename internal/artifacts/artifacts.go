@@ -0,0 +1,157 @@
+package artifacts
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// Rules a cache or artifact finding can break
+const (
+	// RuleCachePoisoning is a cache saved by a workflow whose trigger runs with the base
+	// repository's cache scope, such as pull_request_target, where a pull request can poison
+	// the cache later restored by default branch runs
+	RuleCachePoisoning = "cache_poisoning"
+	// RuleUntrustedArtifact is an artifact of another run downloaded by a privileged workflow,
+	// such as a workflow_run workflow consuming what a pull request's run uploaded
+	RuleUntrustedArtifact = "untrusted_artifact"
+	// RuleCredentialsInArtifact is an artifact uploading the whole workspace of a job whose
+	// checkout persists the token in .git/config
+	RuleCredentialsInArtifact = "credentials_in_artifact"
+)
+
+// PrivilegedTriggers run with the base repository's token, secrets and cache scope even when
+// started by a pull request from a fork
+var PrivilegedTriggers = []string{"pull_request_target", "workflow_run"}
+
+// Finding is a cache or artifact step used in a risky way
+type Finding struct {
+	Rule     string `json:"rule"`
+	Workflow string `json:"workflow"`
+	Job      string `json:"job"`
+	Action   string `json:"action"`
+	Line     int    `json:"line,omitempty"`
+	Ref      string `json:"ref,omitempty"`     // Branch of the workflow file; empty for the default branch
+	Trigger  string `json:"trigger,omitempty"` // Privileged trigger of the workflow
+	Detail   string `json:"detail"`
+}
+
+// Check looks for risky uses of actions/cache and of the artifact actions in a repository's
+// workflows when the policy sets detect_artifact_misuse. Excluded repositories and files that
+// cannot be parsed are skipped.
+func Check(config *policy.PolicyConfig, repoName string, files []github.WorkflowFile) []Finding {
+	if !config.DetectArtifactMisuse || policy.ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	var findings []Finding
+	for _, file := range files {
+		triggers, err := github.WorkflowTriggers(file)
+		if err != nil {
+			continue
+		}
+		var privileged string
+		for _, trigger := range PrivilegedTriggers {
+			if slices.Contains(triggers, trigger) {
+				privileged = trigger
+				break
+			}
+		}
+
+		actions := github.ExtractActions([]github.WorkflowFile{file})
+		persisted := persistedCredentials(actions)
+		for _, action := range actions {
+			finding := Finding{Workflow: file.Path, Job: action.Job, Action: action.Uses, Line: action.Line, Ref: file.Ref}
+			name := actionName(action.Uses)
+
+			switch {
+			case privileged != "" && savesCache(name, action.With):
+				finding.Rule, finding.Trigger = RuleCachePoisoning, privileged
+				finding.Detail = fmt.Sprintf("saves a cache in a %s workflow, which shares its cache scope with the default branch", privileged)
+			case privileged != "" && downloadsFromOtherRun(name, action.With):
+				finding.Rule, finding.Trigger = RuleUntrustedArtifact, privileged
+				finding.Detail = fmt.Sprintf("downloads artifacts of another run into a %s workflow; treat them as untrusted input", privileged)
+			case name == "actions/upload-artifact" && persisted[action.Job] && uploadsWorkspace(action.With["path"]):
+				finding.Rule = RuleCredentialsInArtifact
+				finding.Detail = "uploads the workspace, including the token actions/checkout persists in .git/config; set persist-credentials: false or narrow the path"
+			default:
+				continue
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Workflow != b.Workflow {
+			return a.Workflow < b.Workflow
+		}
+		if a.Job != b.Job {
+			return a.Job < b.Job
+		}
+		return a.Line < b.Line
+	})
+	return findings
+}
+
+// setupCacheActions are setup actions that save a dependency cache when their cache input is set
+var setupCacheActions = []string{"actions/setup-node", "actions/setup-python", "actions/setup-java", "actions/setup-go", "actions/setup-dotnet"}
+
+// savesCache reports whether a step saves a cache: actions/cache and actions/cache/save always
+// do, setup actions when their cache input enables it
+func savesCache(name string, with map[string]string) bool {
+	switch {
+	case name == "actions/cache" || name == "actions/cache/save":
+		return true
+	case slices.Contains(setupCacheActions, name):
+		value := strings.ToLower(strings.TrimSpace(with["cache"]))
+		return value != "" && value != "false"
+	}
+	return false
+}
+
+// downloadsFromOtherRun reports whether a step downloads artifacts uploaded by another workflow
+// run rather than by an earlier job of the same run
+func downloadsFromOtherRun(name string, with map[string]string) bool {
+	switch name {
+	case "actions/download-artifact":
+		return with["run-id"] != ""
+	case "dawidd6/action-download-artifact":
+		return true
+	}
+	return false
+}
+
+// uploadsWorkspace reports whether an upload path covers the root of the workspace, and with
+// it the .git directory
+func uploadsWorkspace(path string) bool {
+	for _, line := range strings.Split(path, "\n") {
+		switch strings.TrimSuffix(strings.TrimSpace(line), "/") {
+		case ".", "./*", "./**", "*", "**", "${{ github.workspace }}", "${{github.workspace}}":
+			return true
+		}
+	}
+	return false
+}
+
+// persistedCredentials returns the jobs with a checkout that leaves the token in .git/config,
+// which actions/checkout does unless persist-credentials is false
+func persistedCredentials(actions []github.Action) map[string]bool {
+	jobs := make(map[string]bool)
+	for _, action := range actions {
+		if actionName(action.Uses) == "actions/checkout" && strings.ToLower(strings.TrimSpace(action.With["persist-credentials"])) != "false" {
+			jobs[action.Job] = true
+		}
+	}
+	return jobs
+}
+
+// actionName returns the lowercase name of an action reference without its version
+func actionName(uses string) string {
+	name, _, _ := strings.Cut(uses, "@")
+	return strings.ToLower(name)
+}
@@ -0,0 +1,110 @@
+package artifacts
+
+import (
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+const privilegedWorkflow = `on: pull_request_target
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+      - uses: actions/cache@v4
+        with:
+          path: ~/.npm
+          key: npm
+      - uses: actions/cache/restore@v4
+      - uses: actions/setup-node@v4
+        with:
+          cache: npm
+      - uses: actions/setup-go@v5
+        with:
+          cache: false
+`
+
+const workflowRun = `on:
+  workflow_run:
+    workflows: [CI]
+    types: [completed]
+jobs:
+  report:
+    steps:
+      - uses: actions/download-artifact@v4
+        with:
+          run-id: ${{ github.event.workflow_run.id }}
+          github-token: ${{ secrets.GITHUB_TOKEN }}
+      - uses: dawidd6/action-download-artifact@v6
+`
+
+const uploadWorkspace = `on: push
+jobs:
+  leaky:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/upload-artifact@v4
+        with:
+          path: .
+      - uses: actions/download-artifact@v4
+        with:
+          run-id: 123
+  safe:
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          persist-credentials: false
+      - uses: actions/upload-artifact@v4
+        with:
+          path: ${{ github.workspace }}
+  narrow:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/upload-artifact@v4
+        with:
+          path: dist/
+`
+
+func TestCheck(t *testing.T) {
+	files := []github.WorkflowFile{
+		{Name: "pr.yml", Path: ".github/workflows/pr.yml", Content: []byte(privilegedWorkflow)},
+		{Name: "report.yml", Path: ".github/workflows/report.yml", Content: []byte(workflowRun)},
+		{Name: "upload.yml", Path: ".github/workflows/upload.yml", Content: []byte(uploadWorkspace)},
+		{Name: "broken.yml", Path: ".github/workflows/broken.yml", Content: []byte("on: [")},
+	}
+	config := &policy.PolicyConfig{DetectArtifactMisuse: true, ExcludedRepos: []string{"org/excluded"}}
+
+	findings := Check(config, "org/repo", files)
+
+	expected := []struct {
+		rule, workflow, action, trigger string
+	}{
+		{RuleCachePoisoning, ".github/workflows/pr.yml", "actions/cache@v4", "pull_request_target"},
+		{RuleCachePoisoning, ".github/workflows/pr.yml", "actions/setup-node@v4", "pull_request_target"},
+		{RuleUntrustedArtifact, ".github/workflows/report.yml", "actions/download-artifact@v4", "workflow_run"},
+		{RuleUntrustedArtifact, ".github/workflows/report.yml", "dawidd6/action-download-artifact@v6", "workflow_run"},
+		{RuleCredentialsInArtifact, ".github/workflows/upload.yml", "actions/upload-artifact@v4", ""},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %+v", len(expected), findings)
+	}
+	for i, want := range expected {
+		got := findings[i]
+		if got.Rule != want.rule || got.Workflow != want.workflow || got.Action != want.action || got.Trigger != want.trigger || got.Line == 0 || got.Detail == "" {
+			t.Errorf("Finding %d = %+v, want %+v", i, got, want)
+		}
+	}
+	if findings[4].Job != "leaky" {
+		t.Errorf("Expected only the job persisting credentials to be reported, got %+v", findings[4])
+	}
+
+	if findings := Check(config, "org/excluded", files); findings != nil {
+		t.Errorf("Expected excluded repository to be skipped, got %+v", findings)
+	}
+	if findings := Check(&policy.PolicyConfig{}, "org/repo", files); findings != nil {
+		t.Errorf("Expected no findings without detect_artifact_misuse, got %+v", findings)
+	}
+}
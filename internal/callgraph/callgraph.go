@@ -0,0 +1,116 @@
+package callgraph
+
+import (
+	"sort"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Edge is a job calling a reusable workflow
+type Edge struct {
+	Caller string   `json:"caller"`           // Calling workflow as owner/repo/path, with @branch for scanned branches
+	Job    string   `json:"job"`              // Calling job
+	Callee string   `json:"callee"`           // Called workflow as owner/repo/path
+	Ref    string   `json:"ref,omitempty"`    // Version the callee is called at; empty for workflows in the calling repository
+	Inputs []string `json:"inputs,omitempty"` // Names of the inputs passed with "with", sorted
+}
+
+// Callee is a reusable workflow with the jobs calling it
+type Callee struct {
+	Workflow     string   `json:"workflow"`
+	Callers      int      `json:"callers"`      // Number of calling jobs
+	Repositories []string `json:"repositories"` // Repositories with a calling job, sorted
+	Refs         []string `json:"refs,omitempty"`
+}
+
+// Graph records which workflows call which reusable workflows. Callers and callees use the
+// same names, so a reusable workflow that calls others appears on both sides of edges.
+type Graph struct {
+	Edges   []Edge   `json:"edges"`
+	Callees []Callee `json:"callees"` // By number of calling repositories, most first
+}
+
+// Build derives the call graph from the job-level uses of the scanned workflows
+func Build(actionsByRepo map[string][]github.Action) *Graph {
+	graph := &Graph{Edges: []Edge{}, Callees: []Callee{}}
+
+	callees := make(map[string]*Callee)
+	repos := make(map[string]map[string]bool)
+	refs := make(map[string]map[string]bool)
+	for repo, actions := range actionsByRepo {
+		for _, action := range actions {
+			workflow, ok := github.ParseReusableWorkflow(action.Uses)
+			if !ok {
+				continue
+			}
+
+			caller := repo + "/" + action.Workflow
+			if action.Ref != "" {
+				caller += "@" + action.Ref
+			}
+			edge := Edge{
+				Caller: caller,
+				Job:    action.Job,
+				Callee: workflow.Qualified(repo),
+				Ref:    workflow.Ref,
+			}
+			for input := range action.With {
+				edge.Inputs = append(edge.Inputs, input)
+			}
+			sort.Strings(edge.Inputs)
+			graph.Edges = append(graph.Edges, edge)
+
+			callee, ok := callees[edge.Callee]
+			if !ok {
+				callee = &Callee{Workflow: edge.Callee}
+				callees[edge.Callee] = callee
+				repos[edge.Callee] = make(map[string]bool)
+				refs[edge.Callee] = make(map[string]bool)
+			}
+			callee.Callers++
+			repos[edge.Callee][repo] = true
+			if edge.Ref != "" {
+				refs[edge.Callee][edge.Ref] = true
+			}
+		}
+	}
+
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.Caller != b.Caller {
+			return a.Caller < b.Caller
+		}
+		if a.Job != b.Job {
+			return a.Job < b.Job
+		}
+		return a.Callee < b.Callee
+	})
+
+	for name, callee := range callees {
+		callee.Repositories = sortedKeys(repos[name])
+		callee.Refs = sortedKeys(refs[name])
+		graph.Callees = append(graph.Callees, *callee)
+	}
+	sort.Slice(graph.Callees, func(i, j int) bool {
+		a, b := graph.Callees[i], graph.Callees[j]
+		if len(a.Repositories) != len(b.Repositories) {
+			return len(a.Repositories) > len(b.Repositories)
+		}
+		if a.Callers != b.Callers {
+			return a.Callers > b.Callers
+		}
+		return a.Workflow < b.Workflow
+	})
+
+	return graph
+}
+
+// sortedKeys returns the keys of a set in sorted order, nil when it is empty
+func sortedKeys(set map[string]bool) []string {
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
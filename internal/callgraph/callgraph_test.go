@@ -0,0 +1,48 @@
+package callgraph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestBuild(t *testing.T) {
+	actionsByRepo := map[string][]github.Action{
+		"org/app": {
+			{Uses: "actions/checkout@v4", Workflow: ".github/workflows/ci.yml", Job: "build"},
+			{Uses: "org/workflows/.github/workflows/go.yml@v1", Workflow: ".github/workflows/ci.yml", Job: "build", With: map[string]string{"version": "1.24", "lint": "true"}},
+			{Uses: "./.github/workflows/deploy.yml", Workflow: ".github/workflows/ci.yml", Job: "deploy"},
+		},
+		"org/api": {
+			{Uses: "org/workflows/.github/workflows/go.yml@v2", Workflow: ".github/workflows/ci.yml", Job: "test", Ref: "release"},
+		},
+		"org/workflows": {
+			{Uses: "org/workflows/.github/workflows/setup.yml@main", Workflow: ".github/workflows/go.yml", Job: "setup"},
+		},
+	}
+
+	graph := Build(actionsByRepo)
+
+	expectedEdges := []Edge{
+		{Caller: "org/api/.github/workflows/ci.yml@release", Job: "test", Callee: "org/workflows/.github/workflows/go.yml", Ref: "v2"},
+		{Caller: "org/app/.github/workflows/ci.yml", Job: "build", Callee: "org/workflows/.github/workflows/go.yml", Ref: "v1", Inputs: []string{"lint", "version"}},
+		{Caller: "org/app/.github/workflows/ci.yml", Job: "deploy", Callee: "org/app/.github/workflows/deploy.yml"},
+		{Caller: "org/workflows/.github/workflows/go.yml", Job: "setup", Callee: "org/workflows/.github/workflows/setup.yml", Ref: "main"},
+	}
+	if !reflect.DeepEqual(graph.Edges, expectedEdges) {
+		t.Errorf("Edges = %+v, want %+v", graph.Edges, expectedEdges)
+	}
+
+	if len(graph.Callees) != 3 {
+		t.Fatalf("Expected 3 callees, got %+v", graph.Callees)
+	}
+	top := graph.Callees[0]
+	if top.Workflow != "org/workflows/.github/workflows/go.yml" || top.Callers != 2 || !reflect.DeepEqual(top.Repositories, []string{"org/api", "org/app"}) || !reflect.DeepEqual(top.Refs, []string{"v1", "v2"}) {
+		t.Errorf("Unexpected top callee %+v", top)
+	}
+
+	if empty := Build(nil); len(empty.Edges) != 0 || empty.Callees == nil {
+		t.Errorf("Expected an empty graph, got %+v", empty)
+	}
+}
@@ -0,0 +1,172 @@
+package checkpoint
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// DefaultPath is the checkpoint file used when none is configured
+const DefaultPath = ".action-control-checkpoint.ndjson"
+
+// header is the first line of a checkpoint file and identifies the scan it belongs to
+type header struct {
+	Organization string    `json:"organization"`
+	Scope        string    `json:"scope,omitempty"` // Options that change which files are scanned
+	StartedAt    time.Time `json:"started_at"`
+}
+
+// record is a scanned repository and its workflow files
+type record struct {
+	Repository string                `json:"repository"`
+	Files      []github.WorkflowFile `json:"files"`
+}
+
+// Checkpoint records organization scan progress in an append-only file with one line per
+// scanned repository, so an interrupted scan can resume without rescanning repositories.
+// Lines cut short by an interruption are ignored when resuming.
+type Checkpoint struct {
+	path    string
+	mu      sync.Mutex
+	file    *os.File
+	scanned map[string][]github.WorkflowFile
+	header  header
+}
+
+// Open starts a checkpoint for scanning org, replacing any existing checkpoint file. With
+// resume, the repositories recorded in an existing checkpoint for the same organization and
+// scope are restored instead, and new progress is appended to it.
+func Open(path, org, scope string, resume bool) (*Checkpoint, error) {
+	cp := &Checkpoint{
+		path:    path,
+		scanned: make(map[string][]github.WorkflowFile),
+		header:  header{Organization: org, Scope: scope, StartedAt: time.Now().UTC()},
+	}
+
+	if resume {
+		if err := cp.load(); err != nil {
+			return nil, err
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open checkpoint: %w", err)
+		}
+		cp.file = file
+		return cp, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	cp.file = file
+	if err := cp.writeLine(cp.header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return cp, nil
+}
+
+// load restores the progress recorded in the checkpoint file
+func (cp *Checkpoint) load() error {
+	file, err := os.Open(cp.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no checkpoint to resume at %s", cp.path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 256*1024*1024)
+
+	if !scanner.Scan() {
+		return fmt.Errorf("checkpoint %s is empty", cp.path)
+	}
+	var saved header
+	if err := json.Unmarshal(scanner.Bytes(), &saved); err != nil {
+		return fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	if saved.Organization != cp.header.Organization || saved.Scope != cp.header.Scope {
+		return fmt.Errorf("checkpoint %s belongs to a scan of %s with different options", cp.path, saved.Organization)
+	}
+	cp.header = saved
+
+	for scanner.Scan() {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // Partially written line of an interrupted scan
+		}
+		cp.scanned[rec.Repository] = rec.Files
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Scanned returns the workflow files of a repository recorded in the checkpoint
+func (cp *Checkpoint) Scanned(repo string) ([]github.WorkflowFile, bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	files, ok := cp.scanned[repo]
+	return files, ok
+}
+
+// Count returns the number of repositories recorded in the checkpoint
+func (cp *Checkpoint) Count() int {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return len(cp.scanned)
+}
+
+// StartedAt returns when the checkpointed scan started
+func (cp *Checkpoint) StartedAt() time.Time {
+	return cp.header.StartedAt
+}
+
+// Record appends a scanned repository and its workflow files to the checkpoint
+func (cp *Checkpoint) Record(repo string, files []github.WorkflowFile) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if err := cp.writeLine(record{Repository: repo, Files: files}); err != nil {
+		return err
+	}
+	cp.scanned[repo] = files
+	return nil
+}
+
+// Complete removes the checkpoint once the scan has finished
+func (cp *Checkpoint) Complete() error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.file.Close()
+	if err := os.Remove(cp.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+// writeLine appends a JSON encoded line to the checkpoint file
+func (cp *Checkpoint) writeLine(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if _, err := cp.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
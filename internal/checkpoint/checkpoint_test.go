@@ -0,0 +1,70 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.ndjson")
+	files := []github.WorkflowFile{{Name: "ci.yml", Path: ".github/workflows/ci.yml", SHA: "b1", Content: []byte("on: push\n")}}
+
+	cp, err := Open(path, "org", "files", false)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if err := cp.Record("org/one", files); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+	if err := cp.Record("org/empty", nil); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+
+	// Simulate an interruption in the middle of writing a line
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	f.WriteString(`{"repository": "org/two", "fi`)
+	f.Close()
+
+	resumed, err := Open(path, "org", "files", true)
+	if err != nil {
+		t.Fatalf("Open with resume returned error: %v", err)
+	}
+	if resumed.Count() != 2 || !resumed.StartedAt().Equal(cp.StartedAt()) {
+		t.Errorf("Expected 2 repositories from the original scan, got %d started at %v", resumed.Count(), resumed.StartedAt())
+	}
+	restored, ok := resumed.Scanned("org/one")
+	if !ok || len(restored) != 1 || string(restored[0].Content) != "on: push\n" {
+		t.Errorf("Expected workflow files to be restored, got %+v", restored)
+	}
+	if _, ok := resumed.Scanned("org/two"); ok {
+		t.Error("Expected partially written repository to be rescanned")
+	}
+
+	if err := resumed.Complete(); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected checkpoint to be removed, got %v", err)
+	}
+}
+
+func TestCheckpointResumeErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.ndjson")
+
+	if _, err := Open(path, "org", "files", true); err == nil {
+		t.Error("Expected error when there is no checkpoint to resume, got nil")
+	}
+
+	if _, err := Open(path, "org", "files", false); err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if _, err := Open(path, "other-org", "files", true); err == nil {
+		t.Error("Expected error for a checkpoint of another organization, got nil")
+	}
+	if _, err := Open(path, "org", "runs", true); err == nil {
+		t.Error("Expected error for a checkpoint with different options, got nil")
+	}
+}
@@ -0,0 +1,105 @@
+package cloud
+
+import (
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// Supported cloud providers
+const (
+	ProviderAWS   = "aws"
+	ProviderGCP   = "gcp"
+	ProviderAzure = "azure"
+)
+
+// authAction describes a cloud authentication action and the inputs identifying the assumed identity
+type authAction struct {
+	provider string
+	inputs   []string // Inputs identifying the identity, in order of preference
+}
+
+// authActions maps known cloud authentication actions to their identity inputs
+var authActions = map[string]authAction{
+	"aws-actions/configure-aws-credentials": {ProviderAWS, []string{"role-to-assume"}},
+	"google-github-actions/auth":            {ProviderGCP, []string{"workload_identity_provider", "service_account"}},
+	"azure/login":                           {ProviderAzure, []string{"client-id"}},
+}
+
+// Access is a cloud identity assumed by a workflow
+type Access struct {
+	Repository string            `json:"repository"`
+	Workflow   string            `json:"workflow,omitempty"`
+	Job        string            `json:"job,omitempty"`
+	Provider   string            `json:"provider"`
+	Action     string            `json:"action"`
+	Identity   string            `json:"identity"`
+	Details    map[string]string `json:"details,omitempty"` // Other identity-related inputs
+	Allowed    bool              `json:"allowed"`
+}
+
+// Detect finds cloud authentication steps among a repository's actions and evaluates them against policy
+func Detect(config *policy.PolicyConfig, repo string, actions []github.Action) []Access {
+	var accesses []Access
+
+	for _, action := range actions {
+		name, _, _ := strings.Cut(action.Uses, "@")
+		auth, ok := authActions[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+
+		access := Access{
+			Repository: repo,
+			Workflow:   action.Workflow,
+			Job:        action.Job,
+			Provider:   auth.provider,
+			Action:     action.Uses,
+		}
+
+		// The first populated identity input identifies the access; the rest are kept as details
+		for _, input := range auth.inputs {
+			value := action.With[input]
+			if value == "" {
+				continue
+			}
+			if access.Identity == "" {
+				access.Identity = value
+				continue
+			}
+			if access.Details == nil {
+				access.Details = make(map[string]string)
+			}
+			access.Details[input] = value
+		}
+
+		// Azure identities are scoped by tenant and subscription
+		if auth.provider == ProviderAzure {
+			for _, input := range []string{"tenant-id", "subscription-id"} {
+				if value := action.With[input]; value != "" {
+					if access.Details == nil {
+						access.Details = make(map[string]string)
+					}
+					access.Details[input] = value
+				}
+			}
+		}
+
+		access.Allowed = policy.CheckCloudAccess(config, repo, access.Provider, access.Identity)
+		accesses = append(accesses, access)
+	}
+
+	return accesses
+}
+
+// Denied returns the accesses not permitted by policy
+func Denied(accesses []Access) []Access {
+	var denied []Access
+	for _, access := range accesses {
+		if !access.Allowed {
+			denied = append(denied, access)
+		}
+	}
+	return denied
+}
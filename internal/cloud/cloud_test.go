@@ -0,0 +1,64 @@
+package cloud
+
+import (
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+func TestDetect(t *testing.T) {
+	actions := []github.Action{
+		{Uses: "actions/checkout@v4"},
+		{
+			Uses:     "aws-actions/configure-aws-credentials@v4",
+			Workflow: ".github/workflows/deploy.yml",
+			Job:      "deploy",
+			With:     map[string]string{"role-to-assume": "arn:aws:iam::123456789012:role/deploy-api", "aws-region": "us-east-1"},
+		},
+		{
+			Uses: "google-github-actions/auth@v2",
+			With: map[string]string{
+				"workload_identity_provider": "projects/1/locations/global/workloadIdentityPools/ci/providers/github",
+				"service_account":            "deployer@project.iam.gserviceaccount.com",
+			},
+		},
+		{
+			Uses: "Azure/login@v2",
+			With: map[string]string{"client-id": "abc", "tenant-id": "tenant", "subscription-id": "sub"},
+		},
+	}
+
+	config := &policy.PolicyConfig{
+		CloudAccess: []policy.CloudAccessRule{
+			{Provider: "aws", Identity: "arn:aws:iam::123456789012:role/deploy-*", Repos: []string{"org/service-*"}},
+		},
+	}
+
+	accesses := Detect(config, "org/service-api", actions)
+	if len(accesses) != 3 {
+		t.Fatalf("Expected 3 cloud accesses, got %d", len(accesses))
+	}
+
+	aws := accesses[0]
+	if aws.Provider != ProviderAWS || aws.Identity != "arn:aws:iam::123456789012:role/deploy-api" || !aws.Allowed {
+		t.Errorf("Unexpected AWS access: %+v", aws)
+	}
+	if aws.Workflow != ".github/workflows/deploy.yml" || aws.Job != "deploy" {
+		t.Errorf("Expected workflow location to be recorded, got %+v", aws)
+	}
+
+	gcp := accesses[1]
+	if gcp.Provider != ProviderGCP || gcp.Details["service_account"] != "deployer@project.iam.gserviceaccount.com" || gcp.Allowed {
+		t.Errorf("Unexpected GCP access: %+v", gcp)
+	}
+
+	azure := accesses[2]
+	if azure.Provider != ProviderAzure || azure.Identity != "abc" || azure.Details["tenant-id"] != "tenant" {
+		t.Errorf("Unexpected Azure access: %+v", azure)
+	}
+
+	if denied := Denied(accesses); len(denied) != 2 {
+		t.Errorf("Expected 2 denied accesses, got %d", len(denied))
+	}
+}
@@ -0,0 +1,122 @@
+package codeowners
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// Locations lists the paths GitHub searches for a CODEOWNERS file, in order
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule maps a path pattern to its owners
+type Rule struct {
+	Pattern string
+	Owners  []string
+}
+
+// Ruleset is a parsed CODEOWNERS file
+type Ruleset struct {
+	Rules []Rule
+}
+
+// Parse parses the content of a CODEOWNERS file
+func Parse(content []byte) *Ruleset {
+	ruleset := &Ruleset{}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		// Strip comments and surrounding whitespace
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		ruleset.Rules = append(ruleset.Rules, Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+		})
+	}
+
+	return ruleset
+}
+
+// OwnersFor returns the owners of a file path. As in GitHub, the last matching rule wins.
+func (r *Ruleset) OwnersFor(filePath string) []string {
+	if r == nil {
+		return nil
+	}
+
+	for i := len(r.Rules) - 1; i >= 0; i-- {
+		if matches(r.Rules[i].Pattern, filePath) {
+			return r.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// NotOwnedBy returns the paths, in order and without duplicates, whose owners include none of
+// the required owners. Owners are compared case-insensitively, as GitHub does. With a nil
+// ruleset every path is returned.
+func (r *Ruleset) NotOwnedBy(paths []string, required []string) []string {
+	var result []string
+	for _, filePath := range paths {
+		owned := false
+		for _, owner := range r.OwnersFor(filePath) {
+			for _, requiredOwner := range required {
+				if strings.EqualFold(owner, requiredOwner) {
+					owned = true
+				}
+			}
+		}
+		if !owned && !slices.Contains(result, filePath) {
+			result = append(result, filePath)
+		}
+	}
+	return result
+}
+
+// matches reports whether a CODEOWNERS pattern matches a repository-relative path
+func matches(pattern, filePath string) bool {
+	filePath = strings.TrimPrefix(filePath, "/")
+
+	if pattern == "*" {
+		return true
+	}
+
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	// Directory patterns match everything beneath them
+	if strings.HasSuffix(pattern, "/") {
+		dir := strings.TrimSuffix(pattern, "/")
+		if anchored {
+			return filePath == dir || strings.HasPrefix(filePath, dir+"/")
+		}
+		return filePath == dir || strings.HasPrefix(filePath, dir+"/") || strings.Contains(filePath, "/"+dir+"/")
+	}
+
+	// "dir/**" matches everything beneath dir
+	if strings.HasSuffix(pattern, "/**") {
+		return strings.HasPrefix(filePath, strings.TrimSuffix(pattern, "**"))
+	}
+
+	// Patterns containing a slash are relative to the repository root
+	if anchored || strings.Contains(pattern, "/") {
+		if ok, _ := path.Match(pattern, filePath); ok {
+			return true
+		}
+		// A path pattern also matches everything beneath it
+		return strings.HasPrefix(filePath, pattern+"/")
+	}
+
+	// Bare patterns match any path component
+	for _, part := range strings.Split(filePath, "/") {
+		if ok, _ := path.Match(pattern, part); ok {
+			return true
+		}
+	}
+	return false
+}
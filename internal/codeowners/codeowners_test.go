@@ -0,0 +1,62 @@
+package codeowners
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOwnersFor(t *testing.T) {
+	content := []byte(`
+# Default owners
+*                       @org/platform
+
+*.md                    @org/docs
+/.github/workflows/     @org/ci-team @alice
+/deploy/prod.yml        @org/release
+`)
+
+	ruleset := Parse(content)
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"main.go", []string{"@org/platform"}},
+		{"docs/README.md", []string{"@org/docs"}},
+		{".github/workflows/ci.yml", []string{"@org/ci-team", "@alice"}},
+		{"deploy/prod.yml", []string{"@org/release"}},
+		{"deploy/staging.yml", []string{"@org/platform"}},
+	}
+
+	for _, tt := range tests {
+		if got := ruleset.OwnersFor(tt.path); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("OwnersFor(%q) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestOwnersForNilRuleset(t *testing.T) {
+	var ruleset *Ruleset
+	if owners := ruleset.OwnersFor("any/path"); owners != nil {
+		t.Errorf("Expected no owners for nil ruleset, got %v", owners)
+	}
+}
+
+func TestNotOwnedBy(t *testing.T) {
+	ruleset := Parse([]byte(`
+*                          @org/platform
+/.github/workflows/        @org/ci-team @Org/Security
+/.github/workflows/lab.yml @alice
+`))
+
+	paths := []string{".github/workflows/ci.yml", ".github/workflows/lab.yml", ".github/workflows/lab.yml", "main.go"}
+	expected := []string{".github/workflows/lab.yml", "main.go"}
+	if got := ruleset.NotOwnedBy(paths, []string{"@org/security"}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("NotOwnedBy() = %v, expected %v", got, expected)
+	}
+
+	var missing *Ruleset
+	if got := missing.NotOwnedBy(paths[:2], []string{"@org/security"}); len(got) != 2 {
+		t.Errorf("Expected every path without CODEOWNERS, got %v", got)
+	}
+}
@@ -0,0 +1,296 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/secretscan"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the config file config init creates when no --config path is given
+const DefaultFile = "config.yaml"
+
+// Template is the commented config.yaml scaffolded by config init. Every setting is commented
+// out so the file documents the defaults without overriding them.
+const Template = `# action-control configuration
+#
+# Settings are read from this file, from ACTION_CONTROL_* environment variables (such as
+# ACTION_CONTROL_ORGANIZATION) and from command-line flags. Flags take precedence over the
+# environment, which takes precedence over this file. Run 'action-control config view' to
+# see the effective configuration and where each setting comes from.
+#
+# Files that appear to contain credentials are refused. Reference secrets by the environment
+# variable holding them instead, such as github_token: ${GITHUB_TOKEN}.
+
+# API token. Prefer 'action-control auth login', which stores it in the OS keychain, or the
+# ACTION_CONTROL_GITHUB_TOKEN environment variable over keeping it in this file.
+# github_token: ""
+
+# Scopes a classic token must have for each command, checked before scanning. Overrides
+# the built-in requirements (repo, and admin:org for sync-org-settings); an empty list
+# skips the check for that command, as does --skip-scope-check for all of them.
+# skip_scope_check: false
+# token_scopes:
+#   report: ["public_repo"]
+#   enforce: []
+
+# Fail enforce unless the token is a fine-grained personal access token or GitHub App token
+# (--require-fine-grained). Classic tokens granting scopes enforce doesn't need are warned about.
+# require_fine_grained: false
+
+# Organization to scan (--org) or a single repository (--repo, owner/repo)
+# organization: "your-org"
+# repository: "your-org/your-repo"
+
+# Output format: markdown, json, sarif, html, dashboard or template (--output)
+# output_format: "markdown"
+
+# Replace the names of private and internal repositories with pseudonyms in report and enforce
+# output (--redact), keyed with redact_secret to keep them stable across runs. Prefer
+# ACTION_CONTROL_REDACT_SECRET for the key.
+# redact: false
+# redact_secret: ""
+
+# History file the dashboard output records each run's summary in, to chart trends
+# (--dashboard-history)
+# dashboard_history: ""
+
+# Forge provider: github, gitea or forgejo, and the API base URL for GitHub Enterprise
+# or self-hosted instances. The fixture provider serves a local bundle (--fixture)
+# instead, such as one recorded from a live scan with --record.
+# provider: "github"
+# base_url: ""
+# fixture: ""
+
+# Policy file enforced by 'action-control enforce' (--policy)
+# policy_file: "policy.yaml"
+
+# Scan file written by 'action-control scan', evaluated by enforce instead of scanning
+# through the API (--input)
+# input: ""
+
+# Store enforce runs are recorded in and history reads (--store): a SQLite database path,
+# a postgres:// URL, s3://bucket/prefix or json://directory
+# store: ""
+
+# Address 'action-control serve' listens on (--listen), and the bearer token its REST API
+# requires. Prefer the ACTION_CONTROL_SERVE_TOKEN environment variable for the token. The
+# server refuses to start without a token unless serve_insecure_no_auth is set
+# (--insecure-no-auth).
+# serve_listen: "127.0.0.1:8080"
+# serve_token: ""
+# serve_insecure_no_auth: false
+
+# Secret of the GitHub webhook delivering pull_request events to 'action-control serve' at
+# /webhooks/github, whose workflow changes it evaluates and passes or fails with the
+# action-control status check. Prefer ACTION_CONTROL_SERVE_WEBHOOK_SECRET.
+# serve_webhook_secret: ""
+
+# Owners mapping file associating repositories with owning teams (--owners-file), and
+# resolving the owners of other repositories through the teams API (--owners-from-teams).
+# Owners are included in enforce reports and receive notifications.
+# owners_file: "owners.yaml"
+# owners_from_teams: false
+
+# Notification channels enforce sends its outcome to (--notify)
+# notify: ["email", "jira"]
+# notifications:
+#   email:
+#     host: "smtp.example.com"
+#     port: 587
+#     username: ""
+#     password: ""  # Prefer ACTION_CONTROL_NOTIFICATIONS_EMAIL_PASSWORD
+#     from: "action-control@example.com"
+#     recipients: ["security@example.com"]
+#     subject: ""   # Go template rendered with the digest
+#     template: ""  # Go template file for the body
+#   jira:
+#     url: "https://your-org.atlassian.net"
+#     username: ""  # Account of a Jira Cloud API token; empty for a personal access token
+#     token: ""     # Prefer ACTION_CONTROL_NOTIFICATIONS_JIRA_TOKEN
+#     project: "SEC"
+#     issue_type: "Task"
+#     labels: []
+
+# Directory workflow files are read from (--workflows-path), and directories searched
+# for composite action.yml files to scan along with them (--scan-path)
+# workflows_path: ".github/workflows"
+# scan_paths: [".github/actions"]
+
+# Repositories fetched in parallel during organization scans
+# concurrency: 4
+
+# Reuse organization repository listings cached within this long (--repo-cache-ttl),
+# in this file (--repo-cache, default in the user cache directory)
+# repo_cache_ttl: "1h"
+# repo_cache: ""
+
+# Reject oversized, deeply nested or alias-expanding workflow files (--hardened)
+# hardened_parsing: false
+
+# Proxy and TLS settings for API requests
+# proxy_url: ""
+# ca_bundle: ""
+# http_timeout: "0s"
+# http_retries: 0
+# http_retry_backoff: "1s"
+`
+
+// secretMarkers are the parts of setting names whose values are redacted
+var secretMarkers = []string{"token", "secret", "password", "credential", "private_key"}
+
+// Redacted replaces the values of secret settings
+const Redacted = "<redacted>"
+
+// IsSecret reports whether a setting holds a secret that is redacted when displayed
+func IsSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range secretMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns the value to display for a setting, hiding non-empty secret values
+func Redact(key string, value interface{}) interface{} {
+	if !IsSecret(key) || value == nil || fmt.Sprint(value) == "" {
+		return value
+	}
+	return Redacted
+}
+
+// Init writes the commented config template to path, refusing to overwrite an existing
+// file unless force is set
+func Init(path string, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+		}
+	}
+	return os.WriteFile(path, []byte(Template), 0600)
+}
+
+// Set sets a top-level setting in the config file at path, creating the file when missing
+// and keeping its comments. The value is parsed as YAML, so numbers, booleans and lists
+// keep their types.
+func Set(path, key, value string) error {
+	if key == "" || strings.ContainsAny(key, " \t\n") {
+		return fmt.Errorf("invalid setting name %q", key)
+	}
+	if findings := secretscan.Scan([]byte(value)); len(findings) > 0 {
+		return fmt.Errorf("the value of %s looks like a %s: keep it in an environment variable and set %s to a reference such as ${%s} instead",
+			key, findings[0].Kind, key, strings.ToUpper(EnvKeyReplacer.Replace(key)))
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	// A file of only comments has no mapping to add the setting to yet
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s is not a mapping of settings", path)
+	}
+	// Comments before the first setting are attached to the document; keep them above it
+	if doc.HeadComment == "" && len(data) > 0 && len(mapping.Content) == 0 {
+		doc.HeadComment = strings.TrimRight(string(data), "\n")
+	}
+
+	valueNode := parseValue(value)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			// Keep comments of the setting being replaced
+			valueNode.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = valueNode
+			return write(path, &doc)
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, valueNode)
+	return write(path, &doc)
+}
+
+// parseValue parses a setting's value as YAML, falling back to a plain string
+func parseValue(value string) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err == nil && len(doc.Content) == 1 {
+		return doc.Content[0]
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func write(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// Setting is an effective setting and where its value comes from
+type Setting struct {
+	Key    string
+	Value  interface{}
+	Source string
+}
+
+// Render formats effective settings as YAML sorted by name, noting the source of each
+// setting in a comment and redacting secrets unless showSecrets is set
+func Render(settings []Setting, showSecrets bool) (string, error) {
+	sorted := append([]Setting(nil), settings...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, setting := range sorted {
+		value := setting.Value
+		if !showSecrets {
+			value = Redact(setting.Key, value)
+		}
+
+		var valueNode yaml.Node
+		if err := valueNode.Encode(value); err != nil {
+			return "", fmt.Errorf("failed to encode setting %s: %w", setting.Key, err)
+		}
+		// Keep lists and maps on the setting's line, next to its source
+		if valueNode.Kind == yaml.SequenceNode || valueNode.Kind == yaml.MappingNode {
+			valueNode.Style = yaml.FlowStyle
+		}
+		valueNode.LineComment = setting.Source
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: setting.Key}, &valueNode)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(mapping); err != nil {
+		return "", fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variable of every setting
+const EnvPrefix = "ACTION_CONTROL"
+
+// EnvKeyReplacer maps the separators of nested and dashed setting names to the underscores
+// of environment variable names
+var EnvKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// envAliases are further environment variables read for a setting, after its own
+var envAliases = map[string][]string{
+	"github_token":  {"GITHUB_TOKEN", "GH_TOKEN"},
+	"organization":  {EnvPrefix + "_ORG"},
+	"repository":    {EnvPrefix + "_REPO"},
+	"output_format": {EnvPrefix + "_OUTPUT"},
+	"policy_file":   {EnvPrefix + "_POLICY"},
+}
+
+// EnvName returns the environment variable of a setting: the prefix and the setting name in
+// upper case, with the dots of nested settings and dashes replaced by underscores. For
+// example notifications.slack.webhook_url is ACTION_CONTROL_NOTIFICATIONS_SLACK_WEBHOOK_URL.
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(EnvKeyReplacer.Replace(key))
+}
+
+// EnvNames returns every environment variable read for a setting, in order of precedence
+func EnvNames(key string) []string {
+	return append([]string{EnvName(key)}, envAliases[strings.ToLower(key)]...)
+}
+
+// AliasedSettings returns the settings that have alias environment variables
+func AliasedSettings() []string {
+	keys := make([]string, 0, len(envAliases))
+	for key := range envAliases {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ParseList splits a list given as a single value, such as an environment variable, on
+// commas, so entries such as paths may contain spaces
+func ParseList(value string) []string {
+	list := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// DecodeValue decodes a structured setting given as a single value, such as an environment
+// variable, from YAML or JSON into target
+func DecodeValue(value string, target interface{}) error {
+	if err := yaml.Unmarshal([]byte(value), target); err != nil {
+		return fmt.Errorf("failed to decode setting value: %w", err)
+	}
+	return nil
+}
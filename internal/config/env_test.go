@@ -0,0 +1,71 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"github_token":                    "ACTION_CONTROL_GITHUB_TOKEN",
+		"policy_file":                     "ACTION_CONTROL_POLICY_FILE",
+		"output_format":                   "ACTION_CONTROL_OUTPUT_FORMAT",
+		"notifications.slack.webhook_url": "ACTION_CONTROL_NOTIFICATIONS_SLACK_WEBHOOK_URL",
+		"http-retry-backoff":              "ACTION_CONTROL_HTTP_RETRY_BACKOFF",
+		"Runner_Deprecations":             "ACTION_CONTROL_RUNNER_DEPRECATIONS",
+	}
+	for key, expected := range tests {
+		if name := EnvName(key); name != expected {
+			t.Errorf("EnvName(%q) = %q, expected %q", key, name, expected)
+		}
+	}
+}
+
+func TestEnvNames(t *testing.T) {
+	names := EnvNames("github_token")
+	expected := []string{"ACTION_CONTROL_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	// The prefixed variable always comes first
+	if names := EnvNames("organization"); names[0] != "ACTION_CONTROL_ORGANIZATION" || names[1] != "ACTION_CONTROL_ORG" {
+		t.Errorf("Unexpected organization variables %v", names)
+	}
+	if names := EnvNames("concurrency"); len(names) != 1 {
+		t.Errorf("Expected no aliases for concurrency, got %v", names)
+	}
+}
+
+func TestParseList(t *testing.T) {
+	tests := map[string][]string{
+		"json":                 {"json"},
+		"markdown,json":        {"markdown", "json"},
+		"markdown, json,html,": {"markdown", "json", "html"},
+		"my policies/a.yaml":   {"my policies/a.yaml"},
+		"":                     {},
+	}
+	for value, expected := range tests {
+		if list := ParseList(value); !reflect.DeepEqual(list, expected) {
+			t.Errorf("ParseList(%q) = %v, expected %v", value, list, expected)
+		}
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	var deprecations []struct {
+		Label string `yaml:"label"`
+		Date  string `yaml:"date"`
+	}
+
+	if err := DecodeValue(`[{"label": "ubuntu-20.04", "date": "2025-04-15"}]`, &deprecations); err != nil {
+		t.Fatalf("DecodeValue returned an error: %v", err)
+	}
+	if len(deprecations) != 1 || deprecations[0].Label != "ubuntu-20.04" || deprecations[0].Date != "2025-04-15" {
+		t.Errorf("Unexpected decoded value %+v", deprecations)
+	}
+
+	if err := DecodeValue("[unclosed", &deprecations); err == nil {
+		t.Error("Expected an error for a malformed value")
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/ihavespoons/action-control/internal/secretscan"
+	"gopkg.in/yaml.v3"
+)
+
+// reference matches a setting value naming the environment variable holding it, such as
+// ${SMTP_PASSWORD}, so secrets are kept out of config files
+var reference = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// CheckSecrets refuses config files that appear to embed credentials, which are easily
+// committed along with the policy repository
+func CheckSecrets(path string, data []byte) error {
+	if findings := secretscan.Scan(data); len(findings) > 0 {
+		return fmt.Errorf("config file %s appears to contain secrets (%s): reference them by environment variable name instead, such as github_token: ${GITHUB_TOKEN}, and rotate them",
+			path, secretscan.Describe(findings))
+	}
+	return nil
+}
+
+// ResolveReferences replaces setting values of the form ${NAME} in a YAML config file with
+// the value of the environment variable NAME, looked up with lookupEnv. It returns the
+// resolved file, whether any reference was resolved, and the names of referenced variables
+// that are not set, which resolve to empty values.
+func ResolveReferences(data []byte, lookupEnv func(string) (string, bool)) (resolved []byte, changed bool, unset []string, err error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, false, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.ScalarNode {
+			match := reference.FindStringSubmatch(node.Value)
+			if match == nil {
+				return
+			}
+			value, ok := lookupEnv(match[1])
+			if !ok {
+				unset = append(unset, match[1])
+			}
+			// Keep the value a string, whatever the variable holds
+			node.Value, node.Tag, node.Style = value, "!!str", yaml.DoubleQuotedStyle
+			changed = true
+			return
+		}
+		for i, child := range node.Content {
+			// Keys of mappings are setting names, not values
+			if node.Kind == yaml.MappingNode && i%2 == 0 {
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(&doc)
+	if !changed {
+		return data, false, nil, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, false, nil, fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, false, nil, fmt.Errorf("failed to encode config file: %w", err)
+	}
+	return buf.Bytes(), true, unset, nil
+}
@@ -0,0 +1,174 @@
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// DefaultHost is the credential host used for github.com
+const DefaultHost = "github.com"
+
+// username identifies credentials stored by action-control in the helper
+const username = "action-control"
+
+// errNotFound is returned by git credential fill when no helper holds the credential
+var errNotFound = errors.New("no stored credential")
+
+// Store persists API tokens using git's credential helper protocol.
+// The configured helper (osxkeychain, manager, libsecret, ...) decides where
+// the token is kept, so tokens live in the OS keychain rather than in plaintext config.
+type Store struct {
+	Host string
+
+	// run executes "git credential <action>" with the given input; replaceable in tests
+	run func(action string, input string) (string, error)
+	// helpers returns the credential helpers git uses for the host; replaceable in tests
+	helpers func(host string) ([]string, error)
+}
+
+// NewStore creates a credential store for the given host
+func NewStore(host string) *Store {
+	if host == "" {
+		host = DefaultHost
+	}
+	return &Store{
+		Host:    host,
+		run:     runGitCredential,
+		helpers: gitCredentialHelpers,
+	}
+}
+
+// Get returns the stored token, or an empty string if none is stored. Failures of git or
+// the credential helper are returned as errors.
+func (s *Store) Get() (string, error) {
+	output, err := s.run("fill", s.request(""))
+	if errors.Is(err, errNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read token: %w", err)
+	}
+
+	return parseResponse(output)["password"], nil
+}
+
+// Set stores the token with the credential helper and reads it back, as git silently
+// discards credentials when no helper is configured. The store helper is refused, as it
+// keeps tokens in plaintext in ~/.git-credentials.
+func (s *Store) Set(token string) error {
+	if token == "" {
+		return fmt.Errorf("token must not be empty")
+	}
+
+	helpers, err := s.helpers(s.Host)
+	if err != nil {
+		return err
+	}
+	if len(helpers) == 0 {
+		return fmt.Errorf("no git credential helper is configured for %s: configure one that uses the OS keychain, such as osxkeychain, manager or libsecret, with git config --global credential.helper", s.Host)
+	}
+	for _, helper := range helpers {
+		if name, _, _ := strings.Cut(helper, " "); name == "store" || strings.HasSuffix(name, "git-credential-store") {
+			return fmt.Errorf("the git credential helper %q keeps tokens in plaintext on disk: configure one that uses the OS keychain, or set GITHUB_TOKEN instead", helper)
+		}
+	}
+
+	if _, err := s.run("approve", s.request(token)); err != nil {
+		return fmt.Errorf("failed to store token: %w", err)
+	}
+	stored, err := s.Get()
+	if err != nil {
+		return err
+	}
+	if stored != token {
+		return fmt.Errorf("the git credential helper %s did not store the token", strings.Join(helpers, ", "))
+	}
+	return nil
+}
+
+// Delete removes the stored token from the credential helper
+func (s *Store) Delete() error {
+	token, err := s.Get()
+	if err != nil {
+		return err
+	}
+	if _, err := s.run("reject", s.request(token)); err != nil {
+		return fmt.Errorf("failed to remove token: %w", err)
+	}
+	return nil
+}
+
+// request builds a credential helper request for this store
+func (s *Store) request(token string) string {
+	var sb strings.Builder
+	sb.WriteString("protocol=https\n")
+	sb.WriteString(fmt.Sprintf("host=%s\n", s.Host))
+	sb.WriteString(fmt.Sprintf("username=%s\n", username))
+	if token != "" {
+		sb.WriteString(fmt.Sprintf("password=%s\n", token))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// parseResponse parses key=value lines from a credential helper response
+func parseResponse(output string) map[string]string {
+	values := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if found {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// runGitCredential invokes git's credential subsystem without interactive prompts
+func runGitCredential(action string, input string) (string, error) {
+	cmd := exec.Command("git", "credential", action)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// With prompts disabled, git fails to ask for the credential no helper provided
+		if action == "fill" && strings.Contains(stderr.String(), "terminal prompts disabled") {
+			return "", errNotFound
+		}
+		return "", fmt.Errorf("git credential %s: %w: %s", action, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// gitCredentialHelpers returns the credential helpers git configures for a host, both those
+// for every host and those for the host's URL
+func gitCredentialHelpers(host string) ([]string, error) {
+	var helpers []string
+	for _, key := range []string{"credential.helper", fmt.Sprintf("credential.https://%s.helper", host)} {
+		output, err := exec.Command("git", "config", "--get-all", key).Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			continue // The key is not set
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read git credential helpers: %w", err)
+		}
+		for _, helper := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			if helper == "" {
+				// An empty value resets the helpers configured before it
+				helpers = nil
+				continue
+			}
+			helpers = append(helpers, helper)
+		}
+	}
+	return helpers, nil
+}
@@ -0,0 +1,117 @@
+package credentials
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeHelper emulates a credential helper holding a single token in memory
+type fakeHelper struct {
+	token   string
+	discard bool // Accept tokens without storing them, as git does without a helper
+}
+
+func (f *fakeHelper) run(action string, input string) (string, error) {
+	values := parseResponse(input)
+
+	switch action {
+	case "fill":
+		if f.token == "" {
+			return "", errNotFound
+		}
+		return input + "password=" + f.token + "\n", nil
+	case "approve":
+		if !f.discard {
+			f.token = values["password"]
+		}
+	case "reject":
+		f.token = ""
+	}
+	return "", nil
+}
+
+func TestStore(t *testing.T) {
+	helper := &fakeHelper{}
+	store := NewStore("")
+	store.run = helper.run
+	store.helpers = configuredHelpers("osxkeychain")
+
+	if store.Host != DefaultHost {
+		t.Errorf("Expected default host %q, got %q", DefaultHost, store.Host)
+	}
+
+	// No token stored yet
+	token, err := store.Get()
+	if err != nil || token != "" {
+		t.Fatalf("Expected no token, got %q (err: %v)", token, err)
+	}
+
+	if err := store.Set("ghp_secret"); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	token, err = store.Get()
+	if err != nil || token != "ghp_secret" {
+		t.Fatalf("Expected stored token, got %q (err: %v)", token, err)
+	}
+
+	if err := store.Delete(); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if helper.token != "" {
+		t.Error("Expected token to be removed from helper")
+	}
+
+	if err := store.Set(""); err == nil {
+		t.Error("Expected error when storing an empty token")
+	}
+}
+
+// configuredHelpers returns a helpers function reporting the given credential helpers
+func configuredHelpers(helpers ...string) func(string) ([]string, error) {
+	return func(string) ([]string, error) {
+		return helpers, nil
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	store := NewStore("")
+	store.helpers = configuredHelpers("osxkeychain")
+	store.run = func(action string, input string) (string, error) {
+		return "", fmt.Errorf("git credential %s: exit status 1: helper crashed", action)
+	}
+	if token, err := store.Get(); err == nil || !strings.Contains(err.Error(), "helper crashed") {
+		t.Errorf("Expected a helper failure to be an error rather than no token, got %q (err: %v)", token, err)
+	}
+
+	// Tokens git accepts but no helper keeps are not reported as stored
+	helper := &fakeHelper{discard: true}
+	store.run = helper.run
+	if err := store.Set("ghp_secret"); err == nil || !strings.Contains(err.Error(), "did not store the token") {
+		t.Errorf("Expected an error when the token cannot be read back, got %v", err)
+	}
+
+	store.run = (&fakeHelper{}).run
+	for _, helpers := range [][]string{nil, {"store"}, {"osxkeychain", "store --file ~/.tokens"}, {"/usr/lib/git-core/git-credential-store"}} {
+		store.helpers = configuredHelpers(helpers...)
+		if err := store.Set("ghp_secret"); err == nil {
+			t.Errorf("Expected an error storing a token with helpers %v", helpers)
+		}
+	}
+}
+
+func TestRequest(t *testing.T) {
+	store := NewStore("gitea.example.com")
+	request := store.request("abc")
+
+	for _, line := range []string{"protocol=https", "host=gitea.example.com", "username=action-control", "password=abc"} {
+		if !strings.Contains(request, line+"\n") {
+			t.Errorf("Expected request to contain %q, got %q", line, request)
+		}
+	}
+
+	if !strings.HasSuffix(request, "\n\n") {
+		t.Error("Expected request to be terminated by a blank line")
+	}
+}
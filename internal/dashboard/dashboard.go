@@ -0,0 +1,182 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/pinning"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// TopActions is the number of most used actions the dashboard charts
+const TopActions = 10
+
+// Dashboard is the summary of a scan charted by the dashboard
+type Dashboard struct {
+	Title        string                 `json:"title"`
+	GeneratedAt  time.Time              `json:"generated_at"`
+	Repositories int                    `json:"repositories"` // Repositories with workflows
+	References   int                    `json:"references"`
+	Pinning      pinning.Counts         `json:"pinning"`
+	TopActions   []ActionCount          `json:"top_actions"`
+	Violations   []RepositoryViolations `json:"violations,omitempty"` // Repositories violating the policy, most violations first
+	Evaluated    bool                   `json:"evaluated"`            // Repositories were evaluated against a policy
+	Trend        []Point                `json:"trend,omitempty"`      // Earlier summaries from the history file, oldest first
+}
+
+// ActionCount is the number of repositories using an action, at any version
+type ActionCount struct {
+	Action       string `json:"action"`
+	Repositories int    `json:"repositories"`
+}
+
+// RepositoryViolations lists the action references of a repository that violate the policy
+type RepositoryViolations struct {
+	Repository string   `json:"repository"`
+	Violations []string `json:"violations"`
+}
+
+// Point is the summary of one day's scan in the history file
+type Point struct {
+	Date          string  `json:"date"`
+	Repositories  int     `json:"repositories"`
+	References    int     `json:"references"`
+	PinnedPercent float64 `json:"pinned_percent"`       // Share of references pinned to a commit SHA
+	Violations    *int    `json:"violations,omitempty"` // Violating references, when evaluated against a policy
+}
+
+// Build summarizes the scanned action references. When config is not nil, each repository is
+// evaluated against it and the violating repositories are listed.
+func Build(title string, actionsMap map[string][]github.Action, config *policy.PolicyConfig, now time.Time) *Dashboard {
+	dashboard := &Dashboard{
+		Title:       title,
+		GeneratedAt: now.UTC(),
+		Pinning:     pinning.Analyze(actionsMap).Summary,
+		Evaluated:   config != nil,
+	}
+
+	usage := make(map[string]int)
+	for repo, actions := range actionsMap {
+		if len(actions) == 0 {
+			continue
+		}
+		dashboard.Repositories++
+		dashboard.References += len(actions)
+
+		seen := make(map[string]bool)
+		uses := make([]string, 0, len(actions))
+		for _, action := range actions {
+			uses = append(uses, action.Uses)
+			name, _, _ := strings.Cut(action.Uses, "@")
+			if strings.HasPrefix(name, "./") || seen[name] {
+				continue
+			}
+			seen[name] = true
+			usage[name]++
+		}
+
+		if config != nil {
+			if violations, _ := policy.CheckActionCompliance(config, repo, uses); len(violations) > 0 {
+				dashboard.Violations = append(dashboard.Violations, RepositoryViolations{Repository: repo, Violations: violations})
+			}
+		}
+	}
+
+	for action, repos := range usage {
+		dashboard.TopActions = append(dashboard.TopActions, ActionCount{Action: action, Repositories: repos})
+	}
+	sort.Slice(dashboard.TopActions, func(i, j int) bool {
+		if dashboard.TopActions[i].Repositories != dashboard.TopActions[j].Repositories {
+			return dashboard.TopActions[i].Repositories > dashboard.TopActions[j].Repositories
+		}
+		return dashboard.TopActions[i].Action < dashboard.TopActions[j].Action
+	})
+	if len(dashboard.TopActions) > TopActions {
+		dashboard.TopActions = dashboard.TopActions[:TopActions]
+	}
+
+	sort.Slice(dashboard.Violations, func(i, j int) bool {
+		if len(dashboard.Violations[i].Violations) != len(dashboard.Violations[j].Violations) {
+			return len(dashboard.Violations[i].Violations) > len(dashboard.Violations[j].Violations)
+		}
+		return dashboard.Violations[i].Repository < dashboard.Violations[j].Repository
+	})
+
+	return dashboard
+}
+
+// Point summarizes the dashboard for the history file
+func (d *Dashboard) Point() Point {
+	point := Point{
+		Date:          d.GeneratedAt.Format("2006-01-02"),
+		Repositories:  d.Repositories,
+		References:    d.References,
+		PinnedPercent: d.Pinning.Percent(pinning.SHA),
+	}
+	if d.Evaluated {
+		violations := 0
+		for _, repo := range d.Violations {
+			violations += len(repo.Violations)
+		}
+		point.Violations = &violations
+	}
+	return point
+}
+
+// LoadHistory reads the summaries of earlier scans from a history file. A missing file is an
+// empty history, so the first scheduled run starts it.
+func LoadHistory(path string) ([]Point, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dashboard history: %w", err)
+	}
+
+	var points []Point
+	if err := json.Unmarshal(data, &points); err != nil {
+		return nil, fmt.Errorf("failed to parse dashboard history %s: %w", path, err)
+	}
+	return points, nil
+}
+
+// Record adds a summary to the history, replacing any earlier summary of the same day, and
+// returns the history sorted by date
+func Record(points []Point, point Point) []Point {
+	recorded := make([]Point, 0, len(points)+1)
+	for _, existing := range points {
+		if existing.Date != point.Date {
+			recorded = append(recorded, existing)
+		}
+	}
+	recorded = append(recorded, point)
+	sort.SliceStable(recorded, func(i, j int) bool {
+		return recorded[i].Date < recorded[j].Date
+	})
+	return recorded
+}
+
+// SaveHistory writes the history file, creating its directory when needed
+func SaveHistory(path string, points []Point) error {
+	data, err := json.MarshalIndent(points, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dashboard history: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create dashboard history directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write dashboard history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package dashboard
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+func TestBuild(t *testing.T) {
+	actionsMap := map[string][]github.Action{
+		"org/api": {
+			{Uses: "actions/checkout@v4"},
+			{Uses: "actions/checkout@v3"},
+			{Uses: "some-user/upload@main"},
+			{Uses: "./local-action"},
+		},
+		"org/web": {
+			{Uses: "actions/checkout@0123456789abcdef0123456789abcdef01234567"},
+		},
+		"org/empty": {},
+	}
+	config := &policy.PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	dashboard := Build("org", actionsMap, config, now)
+	if dashboard.Repositories != 2 || dashboard.References != 5 {
+		t.Errorf("Expected 2 repositories and 5 references, got %d and %d", dashboard.Repositories, dashboard.References)
+	}
+	if len(dashboard.TopActions) != 2 || dashboard.TopActions[0] != (ActionCount{Action: "actions/checkout", Repositories: 2}) {
+		t.Errorf("Expected actions/checkout in 2 repositories first, got %+v", dashboard.TopActions)
+	}
+	if len(dashboard.Violations) != 1 || dashboard.Violations[0].Repository != "org/api" {
+		t.Errorf("Expected violations in org/api, got %+v", dashboard.Violations)
+	}
+
+	point := dashboard.Point()
+	if point.Date != "2026-03-01" || point.PinnedPercent != 25 || point.Violations == nil || *point.Violations != 2 {
+		t.Errorf("Unexpected point %+v", point)
+	}
+
+	// Without a policy, violations are not counted
+	if point := Build("org", actionsMap, nil, now).Point(); point.Violations != nil {
+		t.Errorf("Expected no violation count without a policy, got %d", *point.Violations)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pages", "history.json")
+
+	// A missing file is an empty history
+	points, err := LoadHistory(path)
+	if err != nil || len(points) != 0 {
+		t.Fatalf("Expected empty history, got %+v (%v)", points, err)
+	}
+
+	points = Record(points, Point{Date: "2026-03-02", References: 2})
+	points = Record(points, Point{Date: "2026-03-01", References: 1})
+	points = Record(points, Point{Date: "2026-03-02", References: 3}) // Replaces the same day
+	if err := SaveHistory(path, points); err != nil {
+		t.Fatalf("SaveHistory returned error: %v", err)
+	}
+
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory returned error: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Date != "2026-03-01" || loaded[1].References != 3 {
+		t.Errorf("Expected two points in date order with the latest of each day, got %+v", loaded)
+	}
+}
@@ -0,0 +1,92 @@
+package deadlinks
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/metadata"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// Reasons an action reference cannot be resolved
+const (
+	ReasonNotFound   = "not_found"   // The repository was deleted or made private
+	ReasonRenamed    = "renamed"     // The repository was renamed or transferred
+	ReasonMissingRef = "missing_ref" // The referenced branch, tag or commit no longer exists
+
+	// The referenced owner account no longer exists, so anyone can register its name and
+	// publish a repository under the referenced name
+	ReasonOwnerDeleted = "owner_deleted" // The repository and its owner account are gone
+	ReasonOwnerRenamed = "owner_renamed" // The repository moved with its owner account, which was renamed
+)
+
+// Fetcher checks whether action references can be resolved
+type Fetcher interface {
+	GetActionAvailability(ctx context.Context, owner, repo, ref string) (github.ActionAvailability, error)
+}
+
+// Finding is an action reference that breaks builds or depends on a name that could be
+// registered by someone else
+type Finding struct {
+	Action    string `json:"action"`
+	Reason    string `json:"reason"`
+	RenamedTo string `json:"renamed_to,omitempty"` // Current name of a renamed repository
+}
+
+// Enabled reports whether the policy checks that action references can be resolved
+func Enabled(config *policy.PolicyConfig) bool {
+	return config.DetectMissingActions || config.DetectNamespaceTakeover
+}
+
+// Check looks up the repository and ref of each action. With detect_namespace_takeover, it
+// reports actions still referencing an owner account that was deleted or renamed, whose name
+// can be claimed by anyone. With detect_missing_actions, it reports actions whose repository no
+// longer exists or is not accessible, was renamed, or lacks the referenced ref. Each action is
+// reported for the first of these reasons that applies. Local actions, container images and
+// actions that cannot be looked up for other reasons, such as rate limits, are not reported,
+// nor are actions in excluded repositories.
+func Check(ctx context.Context, config *policy.PolicyConfig, repoName string, actions []string, fetcher Fetcher) []Finding {
+	if !Enabled(config) || policy.ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var findings []Finding
+	for _, action := range actions {
+		if seen[action] {
+			continue
+		}
+		seen[action] = true
+
+		owner, repo, ok := metadata.ActionRepository(action)
+		if !ok {
+			continue
+		}
+		_, ref, _ := strings.Cut(action, "@")
+		availability, err := fetcher.GetActionAvailability(ctx, owner, repo, ref)
+		if err != nil {
+			continue
+		}
+
+		takeover, missing := config.DetectNamespaceTakeover, config.DetectMissingActions
+		switch {
+		case takeover && !availability.Found && !availability.OwnerFound:
+			findings = append(findings, Finding{Action: action, Reason: ReasonOwnerDeleted})
+		case takeover && availability.RenamedTo != "" && !availability.OwnerFound:
+			findings = append(findings, Finding{Action: action, Reason: ReasonOwnerRenamed, RenamedTo: availability.RenamedTo})
+		case missing && !availability.Found:
+			findings = append(findings, Finding{Action: action, Reason: ReasonNotFound})
+		case missing && availability.RenamedTo != "":
+			findings = append(findings, Finding{Action: action, Reason: ReasonRenamed, RenamedTo: availability.RenamedTo})
+		case missing && !availability.RefFound:
+			findings = append(findings, Finding{Action: action, Reason: ReasonMissingRef})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Action < findings[j].Action
+	})
+	return findings
+}
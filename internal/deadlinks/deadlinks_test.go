@@ -0,0 +1,105 @@
+package deadlinks
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// fakeFetcher serves availability from a map keyed by owner/repo@ref
+type fakeFetcher map[string]github.ActionAvailability
+
+func (f fakeFetcher) GetActionAvailability(ctx context.Context, owner, repo, ref string) (github.ActionAvailability, error) {
+	availability, ok := f[owner+"/"+repo+"@"+ref]
+	if !ok {
+		return github.ActionAvailability{}, errors.New("rate limited")
+	}
+	return availability, nil
+}
+
+func TestCheck(t *testing.T) {
+	fetcher := fakeFetcher{
+		"actions/checkout@v4":     {Repository: "actions/checkout", Found: true, RefFound: true, OwnerFound: true},
+		"actions/checkout@v0":     {Repository: "actions/checkout", Found: true, OwnerFound: true},
+		"deleted/action@v1":       {Repository: "deleted/action", OwnerFound: true},
+		"old-owner/action@main":   {Repository: "old-owner/action", Found: true, RenamedTo: "new-owner/action", RefFound: true, OwnerFound: true},
+		"github/codeql-action@v3": {Repository: "github/codeql-action", Found: true, RefFound: true, OwnerFound: true},
+		"gone/action@v1":          {Repository: "gone/action"},
+		"renamed-org/action@v2":   {Repository: "renamed-org/action", Found: true, RenamedTo: "current-org/action", RefFound: true},
+		"private/action@v2":       {Repository: "private/action"},
+	}
+	actions := []string{
+		"actions/checkout@v4",
+		"actions/checkout@v0",
+		"deleted/action@v1",
+		"deleted/action@v1",
+		"old-owner/action@main",
+		"github/codeql-action/init@v3",
+		"gone/action@v1",
+		"renamed-org/action@v2",
+		"unknown/action@v1",
+		"./local-action",
+		"docker://alpine:3",
+	}
+
+	// Nothing is checked unless enabled
+	if findings := Check(context.Background(), &policy.PolicyConfig{}, "org/repo", actions, fetcher); findings != nil {
+		t.Errorf("Expected no findings without detect_missing_actions, got %+v", findings)
+	}
+
+	tests := []struct {
+		name     string
+		config   *policy.PolicyConfig
+		expected []Finding
+	}{
+		{
+			name:   "missing actions",
+			config: &policy.PolicyConfig{DetectMissingActions: true},
+			expected: []Finding{
+				{Action: "actions/checkout@v0", Reason: ReasonMissingRef},
+				{Action: "deleted/action@v1", Reason: ReasonNotFound},
+				{Action: "gone/action@v1", Reason: ReasonNotFound},
+				{Action: "old-owner/action@main", Reason: ReasonRenamed, RenamedTo: "new-owner/action"},
+				{Action: "renamed-org/action@v2", Reason: ReasonRenamed, RenamedTo: "current-org/action"},
+			},
+		},
+		{
+			name:   "namespace takeover",
+			config: &policy.PolicyConfig{DetectNamespaceTakeover: true},
+			expected: []Finding{
+				{Action: "gone/action@v1", Reason: ReasonOwnerDeleted},
+				{Action: "renamed-org/action@v2", Reason: ReasonOwnerRenamed, RenamedTo: "current-org/action"},
+			},
+		},
+		{
+			name:   "both",
+			config: &policy.PolicyConfig{DetectMissingActions: true, DetectNamespaceTakeover: true},
+			expected: []Finding{
+				{Action: "actions/checkout@v0", Reason: ReasonMissingRef},
+				{Action: "deleted/action@v1", Reason: ReasonNotFound},
+				{Action: "gone/action@v1", Reason: ReasonOwnerDeleted},
+				{Action: "old-owner/action@main", Reason: ReasonRenamed, RenamedTo: "new-owner/action"},
+				{Action: "renamed-org/action@v2", Reason: ReasonOwnerRenamed, RenamedTo: "current-org/action"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if findings := Check(context.Background(), tt.config, "org/repo", actions, fetcher); !reflect.DeepEqual(findings, tt.expected) {
+				t.Errorf("Check() = %+v, want %+v", findings, tt.expected)
+			}
+		})
+	}
+
+	config := &policy.PolicyConfig{DetectMissingActions: true}
+
+	// Excluded repositories are not checked
+	config.ExcludedRepos = []string{"org/excluded"}
+	if findings := Check(context.Background(), config, "org/excluded", []string{"private/action@v2"}, fetcher); findings != nil {
+		t.Errorf("Expected no findings for an excluded repository, got %+v", findings)
+	}
+}
@@ -0,0 +1,82 @@
+package deprecations
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/impact"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/runtimes"
+)
+
+// Kinds of deprecation
+const (
+	KindRunner = "runner" // A job runs on a retired GitHub-hosted runner label
+	KindNode   = "node"   // An action runs on a deprecated Node.js runtime
+)
+
+// Warning is a workflow job or action depending on something GitHub has deprecated
+type Warning struct {
+	Kind        string `json:"kind"`
+	Workflow    string `json:"workflow,omitempty"` // Workflow of a job on a retired runner
+	Job         string `json:"job,omitempty"`
+	Label       string `json:"label,omitempty"` // Retired runner label
+	Action      string `json:"action,omitempty"`
+	Runtime     string `json:"runtime,omitempty"` // Deprecated Node.js runtime of the action
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Failing reports whether the policy escalates deprecation warnings to failures
+func Failing(config *policy.PolicyConfig) bool {
+	return config.Deprecations == policy.DeprecationsFail
+}
+
+// Check reports the jobs of a repository's workflows that run on retired runner labels and the
+// actions declaring a deprecated Node.js runtime, unless the policy turns deprecations off.
+// Actions already denied by deny_node16_actions are not reported again, nor are local actions,
+// actions whose action.yml cannot be read, or anything in excluded repositories.
+func Check(ctx context.Context, config *policy.PolicyConfig, repoName string, files []github.WorkflowFile, actions []string, fetcher runtimes.Fetcher) []Warning {
+	if config.Deprecations == policy.DeprecationsOff || policy.ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	jobs := impact.Analyze(repoName, files, impact.RetiredRunners, nil)
+	impact.Sort(jobs)
+
+	var warnings []Warning
+	for _, job := range jobs {
+		warnings = append(warnings, Warning{
+			Kind:        KindRunner,
+			Workflow:    job.Workflow,
+			Job:         job.Job,
+			Label:       job.Label,
+			Replacement: job.Replacement,
+		})
+	}
+
+	if !config.DenyNode16Actions {
+		seen := make(map[string]bool)
+		var nodeWarnings []Warning
+		for _, action := range actions {
+			if seen[action] || strings.HasPrefix(action, "./") || strings.HasPrefix(action, "docker://") {
+				continue
+			}
+			seen[action] = true
+
+			runtime, err := fetcher.GetActionRuntime(ctx, action)
+			if err != nil || !slices.Contains(runtimes.DeprecatedNodeRuntimes, runtime.Using) {
+				continue
+			}
+			nodeWarnings = append(nodeWarnings, Warning{Kind: KindNode, Action: action, Runtime: runtime.Using})
+		}
+		sort.Slice(nodeWarnings, func(i, j int) bool {
+			return nodeWarnings[i].Action < nodeWarnings[j].Action
+		})
+		warnings = append(warnings, nodeWarnings...)
+	}
+
+	return warnings
+}
@@ -0,0 +1,75 @@
+package deprecations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// fakeFetcher serves action runtimes from a map keyed by action reference
+type fakeFetcher map[string]string
+
+func (f fakeFetcher) GetActionRuntime(ctx context.Context, action string) (github.ActionRuntime, error) {
+	using, ok := f[action]
+	if !ok {
+		return github.ActionRuntime{}, errors.New("not found")
+	}
+	return github.ActionRuntime{Action: action, Kind: github.ClassifyRuntime(using), Using: using}, nil
+}
+
+func TestCheck(t *testing.T) {
+	files := []github.WorkflowFile{{
+		Name: "ci.yml",
+		Path: ".github/workflows/ci.yml",
+		Content: []byte(`jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+  legacy:
+    runs-on: [self-hosted, ubuntu-20.04]
+  mac:
+    runs-on: macos-11
+`),
+	}}
+	fetcher := fakeFetcher{"actions/checkout@v3": "node16", "actions/checkout@v4": "node20", "old/action@v1": "node12"}
+	actions := []string{"actions/checkout@v4", "old/action@v1", "actions/checkout@v3", "old/action@v1", "unknown/action@v1", "./local"}
+
+	warnings := Check(context.Background(), &policy.PolicyConfig{}, "org/repo", files, actions, fetcher)
+	expected := []Warning{
+		{Kind: KindRunner, Workflow: ".github/workflows/ci.yml", Job: "legacy", Label: "ubuntu-20.04", Replacement: "ubuntu-24.04"},
+		{Kind: KindRunner, Workflow: ".github/workflows/ci.yml", Job: "mac", Label: "macos-11", Replacement: "macos-15"},
+		{Kind: KindNode, Action: "actions/checkout@v3", Runtime: "node16"},
+		{Kind: KindNode, Action: "old/action@v1", Runtime: "node12"},
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %+v", len(expected), warnings)
+	}
+	for i := range expected {
+		if warnings[i] != expected[i] {
+			t.Errorf("Warning %d = %+v, want %+v", i, warnings[i], expected[i])
+		}
+	}
+
+	// Node runtimes denied by deny_node16_actions are reported by the runtime rules instead
+	warnings = Check(context.Background(), &policy.PolicyConfig{DenyNode16Actions: true}, "org/repo", files, actions, fetcher)
+	if len(warnings) != 2 {
+		t.Errorf("Expected only the runner warnings, got %+v", warnings)
+	}
+
+	if warnings := Check(context.Background(), &policy.PolicyConfig{Deprecations: policy.DeprecationsOff}, "org/repo", files, actions, fetcher); warnings != nil {
+		t.Errorf("Expected no warnings when deprecations are off, got %+v", warnings)
+	}
+	if warnings := Check(context.Background(), &policy.PolicyConfig{ExcludedRepos: []string{"org/repo"}}, "org/repo", files, actions, fetcher); warnings != nil {
+		t.Errorf("Expected no warnings for an excluded repository, got %+v", warnings)
+	}
+}
+
+func TestFailing(t *testing.T) {
+	if Failing(&policy.PolicyConfig{}) || !Failing(&policy.PolicyConfig{Deprecations: policy.DeprecationsFail}) {
+		t.Error("Expected deprecations to fail only when the policy escalates them")
+	}
+}
@@ -36,6 +36,7 @@ func NewExporter() *ActionExporter {
 func (e *ActionExporter) GeneratePolicyFromActions(actionsMap map[string][]github.Action) (*policy.PolicyConfig, error) {
 	// Create a new policy config
 	policyConfig := &policy.PolicyConfig{
+		Version:       policy.CurrentVersion,
 		PolicyMode:    e.PolicyMode,
 		ExcludedRepos: []string{},
 		CustomRules:   make(map[string]policy.Policy),
@@ -115,6 +116,37 @@ func (e *ActionExporter) GeneratePolicyFromActions(actionsMap map[string][]githu
 	return policyConfig, nil
 }
 
+// GeneratePolicyFromViolations creates a policy containing only the actions that currently
+// violate the existing policy, e.g. as a "to be remediated" deny list or a waiver file
+func (e *ActionExporter) GeneratePolicyFromViolations(actionsMap map[string][]github.Action, existing *policy.PolicyConfig) (*policy.PolicyConfig, error) {
+	violatingMap := make(map[string][]github.Action)
+
+	for repo, actions := range actionsMap {
+		uses := make([]string, len(actions))
+		for i, action := range actions {
+			uses[i] = action.Uses
+		}
+
+		violations, compliant := policy.CheckActionCompliance(existing, repo, uses)
+		if compliant {
+			continue
+		}
+
+		// Keep only the actions reported as violations
+		violating := make(map[string]bool, len(violations))
+		for _, v := range violations {
+			violating[v] = true
+		}
+		for _, action := range actions {
+			if violating[action.Uses] {
+				violatingMap[repo] = append(violatingMap[repo], action)
+			}
+		}
+	}
+
+	return e.GeneratePolicyFromActions(violatingMap)
+}
+
 // ExportPolicyFile writes the policy configuration to a YAML file
 func (e *ActionExporter) ExportPolicyFile(config *policy.PolicyConfig) error {
 	// Create directory if it doesn't exist
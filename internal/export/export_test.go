@@ -3,10 +3,12 @@ package export
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
 )
 
 func TestGeneratePolicyFromActions(t *testing.T) {
@@ -349,3 +351,186 @@ func TestExportPolicyFile(t *testing.T) {
 		}
 	})
 }
+
+func TestMergePolicyFile(t *testing.T) {
+	tempDir := t.TempDir()
+	policyPath := filepath.Join(tempDir, "policy.yaml")
+
+	existing := `# Curated organization policy
+policy_mode: allow
+allowed_actions:
+  - actions/checkout # pinned by security team
+  - zzz/last-entry
+excluded_repos:
+  - org/sandbox
+`
+	if err := os.WriteFile(policyPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write existing policy: %v", err)
+	}
+
+	actionsMap := map[string][]github.Action{
+		"org/repo1": {
+			{Uses: "actions/checkout@v4"},
+			{Uses: "actions/setup-go@v5"},
+		},
+	}
+
+	exporter := NewExporter()
+	exporter.OutputPath = policyPath
+	exporter.IncludeCustom = true
+
+	generated, err := exporter.GeneratePolicyFromActions(actionsMap)
+	if err != nil {
+		t.Fatalf("GeneratePolicyFromActions returned error: %v", err)
+	}
+
+	added, err := exporter.MergePolicyFile(generated)
+	if err != nil {
+		t.Fatalf("MergePolicyFile returned error: %v", err)
+	}
+
+	expectedAdded := []string{
+		"actions/setup-go",
+		"org/repo1: actions/checkout",
+		"org/repo1: actions/setup-go",
+	}
+	if strings.Join(added, ",") != strings.Join(expectedAdded, ",") {
+		t.Errorf("Expected added %v, got %v", expectedAdded, added)
+	}
+
+	content, err := os.ReadFile(policyPath)
+	if err != nil {
+		t.Fatalf("Failed to read merged policy: %v", err)
+	}
+	merged := string(content)
+
+	// Comments, curated sections and ordering are preserved
+	expectedPhrases := []string{
+		"# Curated organization policy",
+		"- actions/checkout # pinned by security team",
+		"- org/sandbox",
+		"  - zzz/last-entry\n  - actions/setup-go\n",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(merged, phrase) {
+			t.Errorf("Expected merged policy to contain %q, got:\n%s", phrase, merged)
+		}
+	}
+
+	// Merging again adds nothing
+	added, err = exporter.MergePolicyFile(generated)
+	if err != nil {
+		t.Fatalf("MergePolicyFile returned error: %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("Expected no additions on second merge, got %v", added)
+	}
+}
+
+func TestMergePolicyFileInferredDenyMode(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	existing := "denied_actions:\n  - bad/action\n"
+	if err := os.WriteFile(policyPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write existing policy: %v", err)
+	}
+	actionsMap := map[string][]github.Action{"org/repo1": {{Uses: "risky/action@v1"}}}
+
+	exporter := NewExporter()
+	exporter.OutputPath = policyPath
+	mode, exists, err := exporter.PolicyFileMode()
+	if err != nil || !exists || mode != "deny" {
+		t.Fatalf("PolicyFileMode() = %q, %v, %v, want deny", mode, exists, err)
+	}
+
+	// Allow-mode entries would make the file infer allow mode, allowing every denied action
+	generated, err := exporter.GeneratePolicyFromActions(actionsMap)
+	if err != nil {
+		t.Fatalf("GeneratePolicyFromActions returned error: %v", err)
+	}
+	if _, err := exporter.MergePolicyFile(generated); err == nil || !strings.Contains(err.Error(), "deny mode") {
+		t.Errorf("Expected merging allow-mode entries to be refused, got %v", err)
+	}
+
+	// Entries in the file's own mode go to its deny list
+	exporter.PolicyMode = ExportMode(mode)
+	generated, err = exporter.GeneratePolicyFromActions(actionsMap)
+	if err != nil {
+		t.Fatalf("GeneratePolicyFromActions returned error: %v", err)
+	}
+	if added, err := exporter.MergePolicyFile(generated); err != nil || len(added) != 1 {
+		t.Fatalf("MergePolicyFile() = %v, %v", added, err)
+	}
+	merged, err := policy.LoadPolicyConfig(policyPath)
+	if err != nil {
+		t.Fatalf("LoadPolicyConfig returned error: %v", err)
+	}
+	if merged.PolicyMode != "deny" || !reflect.DeepEqual(merged.DeniedActions, []string{"bad/action", "risky/action"}) || len(merged.AllowedActions) != 0 {
+		t.Errorf("Expected the deny list to grow in deny mode, got %s mode with %v / %v", merged.PolicyMode, merged.DeniedActions, merged.AllowedActions)
+	}
+}
+
+func TestMergePolicyFileWithoutExistingFile(t *testing.T) {
+	exporter := NewExporter()
+	exporter.OutputPath = filepath.Join(t.TempDir(), "policy.yaml")
+
+	generated, err := exporter.GeneratePolicyFromActions(map[string][]github.Action{
+		"org/repo1": {{Uses: "actions/checkout@v4"}},
+	})
+	if err != nil {
+		t.Fatalf("GeneratePolicyFromActions returned error: %v", err)
+	}
+
+	added, err := exporter.MergePolicyFile(generated)
+	if err != nil {
+		t.Fatalf("MergePolicyFile returned error: %v", err)
+	}
+	if len(added) != 1 || added[0] != "actions/checkout" {
+		t.Errorf("Expected all generated actions to be added, got %v", added)
+	}
+	if _, err := os.Stat(exporter.OutputPath); err != nil {
+		t.Errorf("Expected policy file to be created: %v", err)
+	}
+}
+
+func TestGeneratePolicyFromViolations(t *testing.T) {
+	actionsMap := map[string][]github.Action{
+		"org/repo1": {
+			{Uses: "actions/checkout@v4"},
+			{Uses: "risky/action@v1"},
+		},
+		"org/repo2": {
+			{Uses: "actions/checkout@v4"},
+			{Uses: "other/tool@main"},
+		},
+		"org/excluded": {
+			{Uses: "risky/action@v1"},
+		},
+	}
+
+	existing := &policy.PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"actions/checkout"},
+		ExcludedRepos:  []string{"org/excluded"},
+	}
+
+	exporter := NewExporter()
+	exporter.PolicyMode = "deny"
+	exporter.IncludeCustom = true
+
+	generated, err := exporter.GeneratePolicyFromViolations(actionsMap, existing)
+	if err != nil {
+		t.Fatalf("GeneratePolicyFromViolations returned error: %v", err)
+	}
+
+	expected := []string{"other/tool", "risky/action"}
+	if strings.Join(generated.DeniedActions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected denied actions %v, got %v", expected, generated.DeniedActions)
+	}
+
+	if _, ok := generated.CustomRules["org/excluded"]; ok {
+		t.Error("Expected excluded repository to have no violations")
+	}
+	if rule := generated.CustomRules["org/repo1"]; len(rule.DeniedActions) != 1 || rule.DeniedActions[0] != "risky/action" {
+		t.Errorf("Expected org/repo1 custom rule to contain only risky/action, got %v", rule.DeniedActions)
+	}
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergePolicyFile adds newly discovered actions to the existing policy file at OutputPath
+// instead of overwriting it. Comments, ordering and curated sections such as excluded_repos
+// are preserved. It returns the added entries; custom rule entries are prefixed with the
+// repository name. If no policy file exists yet, the generated policy is written as-is.
+func (e *ActionExporter) MergePolicyFile(generated *policy.PolicyConfig) ([]string, error) {
+	data, err := os.ReadFile(e.OutputPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := e.ExportPolicyFile(generated); err != nil {
+			return nil, err
+		}
+		return e.actionList(generated.AllowedActions, generated.DeniedActions), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read existing policy file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse existing policy file: %w", err)
+	}
+
+	// An empty file has no document node yet
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("existing policy file is not a YAML mapping")
+	}
+
+	// Entries for the wrong list would change the mode ParsePolicyConfig infers for files
+	// without policy_mode, silently turning denied actions into allowed ones or vice versa
+	existingMode, err := fileMode(data)
+	if err != nil {
+		return nil, err
+	}
+	if ExportMode(existingMode) != e.PolicyMode {
+		return nil, fmt.Errorf("existing policy file %s is in %s mode, refusing to merge %s-mode entries into it", e.OutputPath, existingMode, e.PolicyMode)
+	}
+	listKey := "allowed_actions"
+	if e.PolicyMode == "deny" {
+		listKey = "denied_actions"
+	}
+
+	// Merge global actions
+	added := policy.AppendMissing(policy.MappingValue(root, listKey, yaml.SequenceNode), e.actionList(generated.AllowedActions, generated.DeniedActions))
+
+	// Merge repository-specific rules
+	if e.IncludeCustom && len(generated.CustomRules) > 0 {
+		customRules := policy.MappingValue(root, "custom_rules", yaml.MappingNode)
+
+		repos := make([]string, 0, len(generated.CustomRules))
+		for repo := range generated.CustomRules {
+			repos = append(repos, repo)
+		}
+		sort.Strings(repos)
+
+		for _, repo := range repos {
+			rule := generated.CustomRules[repo]
+			repoRule := policy.MappingValue(customRules, repo, yaml.MappingNode)
+			for _, action := range policy.AppendMissing(policy.MappingValue(repoRule, listKey, yaml.SequenceNode), e.actionList(rule.AllowedActions, rule.DeniedActions)) {
+				added = append(added, fmt.Sprintf("%s: %s", repo, action))
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal merged policy: %w", err)
+	}
+	encoder.Close()
+
+	if err := os.WriteFile(e.OutputPath, buf.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write policy file: %w", err)
+	}
+
+	return added, nil
+}
+
+// PolicyFileMode returns the policy mode of the existing policy file at OutputPath, as set with
+// policy_mode or inferred from its lists, and whether the file exists
+func (e *ActionExporter) PolicyFileMode() (string, bool, error) {
+	data, err := os.ReadFile(e.OutputPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read existing policy file: %w", err)
+	}
+	mode, err := fileMode(data)
+	return mode, true, err
+}
+
+// ExportMode returns the exporter policy mode whose list new entries of a policy in the given
+// mode go to: mixed mode policies take new entries in their allow list
+func ExportMode(mode string) string {
+	if mode == "mixed" {
+		return "allow"
+	}
+	return mode
+}
+
+// fileMode returns the policy mode of a policy file's content, as set or inferred
+func fileMode(data []byte) (string, error) {
+	config, err := policy.ParsePolicyConfig(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse existing policy file: %w", err)
+	}
+	return config.PolicyMode, nil
+}
+
+// actionList selects the list matching the exporter's policy mode
+func (e *ActionExporter) actionList(allowed, denied []string) []string {
+	if e.PolicyMode == "deny" {
+		return denied
+	}
+	return allowed
+}
@@ -0,0 +1,85 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/deprecations"
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// FormatAnnotations formats violations as GitHub Actions workflow commands, which render as
+// annotations on the workflow files in the run summary and pull request diff
+func FormatAnnotations(violations []Violation, images map[string][]github.Image) string {
+	var sb strings.Builder
+
+	for _, v := range violations {
+		properties := []string{"file=" + escapeProperty(v.Workflow)}
+		if v.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", v.Line))
+		}
+		properties = append(properties, "title="+escapeProperty("Action policy violation"))
+
+		message := fmt.Sprintf("%s violates %s in %s (%s policy)", v.Action, v.Rule, v.Repository, v.Source)
+		if v.Entry != "" {
+			message = fmt.Sprintf("%s violates %s entry %s in %s (%s policy)", v.Action, v.Rule, v.Entry, v.Repository, v.Source)
+		}
+		sb.WriteString(fmt.Sprintf("::error %s::%s\n", strings.Join(properties, ","), escapeData(message)))
+	}
+
+	repos := sortedRepos(images)
+
+	for _, repo := range repos {
+		for _, image := range images[repo] {
+			message := fmt.Sprintf("Container image %s in job %s is not allowed by policy", image.Image, image.Job)
+			if image.Service != "" {
+				message = fmt.Sprintf("Container image %s of service %s in job %s is not allowed by policy", image.Image, image.Service, image.Job)
+			}
+			sb.WriteString(fmt.Sprintf("::error file=%s,title=%s::%s\n",
+				escapeProperty(image.Workflow), escapeProperty("Container image policy violation"), escapeData(message)))
+		}
+	}
+
+	return sb.String()
+}
+
+// FormatDeprecationAnnotations formats deprecations as warning annotations, or as error
+// annotations when the policy escalates them to failures
+func FormatDeprecationAnnotations(warnings map[string][]deprecations.Warning, failing bool) string {
+	var sb strings.Builder
+	command := "warning"
+	if failing {
+		command = "error"
+	}
+
+	repos := sortedRepos(warnings)
+
+	for _, repo := range repos {
+		for _, warning := range warnings[repo] {
+			if warning.Kind == deprecations.KindNode {
+				message := fmt.Sprintf("%s in %s runs on the deprecated %s runtime", warning.Action, repo, warning.Runtime)
+				sb.WriteString(fmt.Sprintf("::%s title=%s::%s\n", command, escapeProperty("Deprecated Node.js runtime"), escapeData(message)))
+				continue
+			}
+
+			message := fmt.Sprintf("Job %s runs on the retired runner %s", warning.Job, warning.Label)
+			if warning.Replacement != "" {
+				message += ", use " + warning.Replacement
+			}
+			sb.WriteString(fmt.Sprintf("::%s file=%s,title=%s::%s\n", command,
+				escapeProperty(warning.Workflow), escapeProperty("Retired runner"), escapeData(message)))
+		}
+	}
+
+	return sb.String()
+}
+
+// escapeData escapes a workflow command message
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,48 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/artifacts"
+)
+
+// artifactRuleLabels are the headings of cache and artifact rules in reports
+var artifactRuleLabels = map[string]string{
+	artifacts.RuleCachePoisoning:        "Cache poisoning",
+	artifacts.RuleUntrustedArtifact:     "Untrusted artifact",
+	artifacts.RuleCredentialsInArtifact: "Credentials in artifact",
+}
+
+// FormatArtifactMisuse formats the cache and artifact steps crossing trust boundaries
+func FormatArtifactMisuse(findings map[string][]artifacts.Finding) string {
+	var sb strings.Builder
+	sb.WriteString("## 📦 Caches and Artifacts\n\n")
+
+	if len(findings) == 0 {
+		sb.WriteString("No risky use of caches or artifacts found.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(findings)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Risk | Action | Workflow | Job | Detail |\n")
+		sb.WriteString("|------|--------|----------|-----|--------|\n")
+		for _, finding := range findings[repo] {
+			location := finding.Workflow
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, finding.Line)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | `%s` | `%s` | %s |\n", artifactRuleLabels[finding.Rule], finding.Action, location, finding.Job, finding.Detail))
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d risky uses of caches or artifacts.\n", total))
+
+	return sb.String()
+}
@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/callgraph"
+)
+
+// FormatCallGraph formats the reusable workflow call graph as a Mermaid flowchart, followed by
+// the fan-out of each reusable workflow and every call
+func FormatCallGraph(graph *callgraph.Graph) string {
+	var sb strings.Builder
+	sb.WriteString("# Reusable Workflow Call Graph\n\n")
+
+	if len(graph.Edges) == 0 {
+		sb.WriteString("No jobs call reusable workflows.\n")
+		return sb.String()
+	}
+
+	// Number nodes in order of appearance, as Mermaid node IDs cannot contain slashes
+	ids := make(map[string]string)
+	node := func(workflow string) string {
+		if id, ok := ids[workflow]; ok {
+			return id
+		}
+		id := fmt.Sprintf("w%d", len(ids))
+		ids[workflow] = id
+		return fmt.Sprintf("%s[\"%s\"]", id, mermaidText(workflow))
+	}
+
+	sb.WriteString("```mermaid\nflowchart LR\n")
+	for _, edge := range graph.Edges {
+		label := edge.Job
+		if edge.Ref != "" {
+			label += " @" + edge.Ref
+		}
+		sb.WriteString(fmt.Sprintf("  %s -->|\"%s\"| %s\n", node(edge.Caller), mermaidText(label), node(edge.Callee)))
+	}
+	sb.WriteString("```\n\n")
+
+	sb.WriteString("## Fan-out\n\n")
+	sb.WriteString("| Reusable Workflow | Calling Jobs | Repositories | Versions |\n")
+	sb.WriteString("|-------------------|--------------|--------------|----------|\n")
+	for _, callee := range graph.Callees {
+		versions := "-"
+		if len(callee.Refs) > 0 {
+			versions = fmt.Sprintf("`%s`", strings.Join(callee.Refs, "`, `"))
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %d | %s | %s |\n", callee.Workflow, callee.Callers, strings.Join(callee.Repositories, ", "), versions))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Calls\n\n")
+	sb.WriteString("| Caller | Job | Reusable Workflow | Inputs |\n")
+	sb.WriteString("|--------|-----|-------------------|--------|\n")
+	for _, edge := range graph.Edges {
+		callee := edge.Callee
+		if edge.Ref != "" {
+			callee += "@" + edge.Ref
+		}
+		inputs := "-"
+		if len(edge.Inputs) > 0 {
+			inputs = fmt.Sprintf("`%s`", strings.Join(edge.Inputs, "`, `"))
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | `%s` | `%s` | %s |\n", edge.Caller, edge.Job, callee, inputs))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%d calls to %d reusable workflows.\n", len(graph.Edges), len(graph.Callees)))
+
+	return sb.String()
+}
+
+// mermaidText escapes text for a quoted Mermaid label
+func mermaidText(text string) string {
+	return strings.ReplaceAll(text, "\"", "#quot;")
+}
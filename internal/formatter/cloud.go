@@ -0,0 +1,51 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/cloud"
+)
+
+// FormatCloudAccess formats the cloud identities assumed by workflows, grouped by repository
+func FormatCloudAccess(accesses map[string][]cloud.Access) string {
+	var sb strings.Builder
+	sb.WriteString("## ☁️ Cloud Access\n\n")
+
+	if len(accesses) == 0 {
+		sb.WriteString("No cloud authentication actions found.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(accesses)
+
+	denied := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Status | Provider | Identity | Workflow | Job |\n")
+		sb.WriteString("|--------|----------|----------|----------|-----|\n")
+
+		for _, access := range accesses[repo] {
+			status := "✅"
+			if !access.Allowed {
+				status = "❌"
+				denied++
+			}
+			identity := access.Identity
+			if identity == "" {
+				identity = "_unknown_"
+			} else {
+				identity = fmt.Sprintf("`%s`", identity)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | `%s` | %s |\n",
+				status, access.Provider, identity, access.Workflow, access.Job))
+		}
+		sb.WriteString("\n")
+	}
+
+	if denied > 0 {
+		sb.WriteString(fmt.Sprintf("\nFound %d cloud identities assumed without policy permission.\n", denied))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,219 @@
+package formatter
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/dashboard"
+	"github.com/ihavespoons/action-control/internal/pinning"
+)
+
+// dashboardTemplate renders the dashboard as a single page without external assets, so it can
+// be published as is, such as to GitHub Pages
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 64rem; padding: 0 1rem; color: #24292f; }
+.tiles { display: flex; flex-wrap: wrap; gap: 1rem; margin-bottom: 2rem; }
+.tile { border: 1px solid #d0d7de; border-radius: 6px; padding: 1rem; min-width: 10rem; }
+.tile strong { display: block; font-size: 2rem; }
+.bars { display: grid; grid-template-columns: minmax(12rem, max-content) 1fr; gap: 4px 1rem; align-items: center; margin-bottom: 2rem; }
+.bar { height: 1.2rem; background: #2da44e; min-width: 2px; color: #fff; font-size: 0.8rem; padding-left: 4px; box-sizing: border-box; }
+.bar.violations { background: #cf222e; }
+.stack { display: flex; height: 1.6rem; margin-bottom: 0.5rem; border-radius: 6px; overflow: hidden; }
+.stack div { height: 100%; }
+.legend span { display: inline-block; margin-right: 1rem; }
+.swatch { display: inline-block; width: 0.8rem; height: 0.8rem; margin-right: 4px; vertical-align: middle; }
+.sha { background: #2da44e; } .tag { background: #54aeff; } .branch { background: #d4a72c; } .unpinned { background: #cf222e; }
+svg { border: 1px solid #d0d7de; border-radius: 6px; margin-bottom: 0.5rem; }
+code { font-size: 0.9em; }
+footer { color: #57606a; font-size: 0.85em; margin-top: 3rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="tiles">
+<div class="tile"><strong>{{.Repositories}}</strong>repositories with workflows</div>
+<div class="tile"><strong>{{.References}}</strong>action references</div>
+<div class="tile"><strong>{{.PinnedPercent}}</strong>pinned to a commit SHA</div>
+{{- if .Evaluated}}
+<div class="tile"><strong>{{len .Violations}}</strong>repositories violating the policy</div>
+{{- end}}
+</div>
+{{- if .Trend}}
+<h2>Trend</h2>
+<svg viewBox="0 0 {{.Trend.Width}} {{.Trend.Height}}" width="100%" role="img" aria-label="Trend of pinning and violations">
+<polyline fill="none" stroke="#2da44e" stroke-width="2" points="{{.Trend.Pinned}}"/>
+{{- if .Trend.Violations}}
+<polyline fill="none" stroke="#cf222e" stroke-width="2" points="{{.Trend.Violations}}"/>
+{{- end}}
+</svg>
+<p class="legend"><span><span class="swatch sha"></span>SHA pinned, 0–100%</span>{{if .Trend.Violations}}<span><span class="swatch unpinned"></span>Violations, 0–{{.Trend.MaxViolations}}</span>{{end}}<span>{{.Trend.From}} to {{.Trend.To}}</span></p>
+{{- end}}
+<h2>Pinning</h2>
+<div class="stack">
+{{- range .Pinning}}{{if .Count}}<div class="{{.Kind}}" style="width: {{.Width}}" title="{{.Kind}}: {{.Count}}"></div>{{end}}{{end}}
+</div>
+<p class="legend">{{range .Pinning}}<span><span class="swatch {{.Kind}}"></span>{{.Kind}}: {{.Count}}</span>{{end}}</p>
+{{- if .Evaluated}}
+<h2>Violations by Repository</h2>
+{{- if .Violations}}
+<div class="bars">
+{{- range .Violations}}
+<span>{{.Label}}</span><div class="bar violations" style="width: {{.Width}}" title="{{.Title}}">{{.Count}}</div>
+{{- end}}
+</div>
+{{- else}}
+<p>✅ All repositories comply with the policy.</p>
+{{- end}}
+{{- end}}
+<h2>Top Actions</h2>
+<div class="bars">
+{{- range .TopActions}}
+<code>{{.Label}}</code><div class="bar" style="width: {{.Width}}">{{.Count}}</div>
+{{- end}}
+</div>
+<footer>Generated by action-control on {{.GeneratedAt}}</footer>
+</body>
+</html>
+`))
+
+// dashboardBar is a labelled bar, its width relative to the longest bar of its chart
+type dashboardBar struct {
+	Label string
+	Title string
+	Count int
+	Width template.CSS
+}
+
+// dashboardSegment is the share of one pinning kind in the pinning chart
+type dashboardSegment struct {
+	Kind  pinning.Kind
+	Count int
+	Width template.CSS
+}
+
+// dashboardTrend holds the polylines of the trend chart
+type dashboardTrend struct {
+	Width, Height int
+	Pinned        string
+	Violations    string
+	MaxViolations int
+	From, To      string
+}
+
+// FormatDashboard formats the dashboard as a self-contained HTML page with charts of
+// violations by repository, pinning, the most used actions and, when the dashboard has earlier
+// summaries, their trend
+func FormatDashboard(d *dashboard.Dashboard) (string, error) {
+	view := struct {
+		Title         string
+		GeneratedAt   string
+		Repositories  int
+		References    int
+		PinnedPercent string
+		Evaluated     bool
+		Violations    []dashboardBar
+		TopActions    []dashboardBar
+		Pinning       []dashboardSegment
+		Trend         *dashboardTrend
+	}{
+		Title:         d.Title,
+		GeneratedAt:   d.GeneratedAt.Format("2006-01-02 15:04 MST"),
+		Repositories:  d.Repositories,
+		References:    d.References,
+		PinnedPercent: fmt.Sprintf("%.0f%%", d.Pinning.Percent(pinning.SHA)),
+		Evaluated:     d.Evaluated,
+	}
+
+	maxViolations := 0
+	for _, repo := range d.Violations {
+		maxViolations = max(maxViolations, len(repo.Violations))
+	}
+	for _, repo := range d.Violations {
+		view.Violations = append(view.Violations, dashboardBar{
+			Label: repo.Repository,
+			Title: strings.Join(repo.Violations, ", "),
+			Count: len(repo.Violations),
+			Width: barWidth(len(repo.Violations), maxViolations),
+		})
+	}
+
+	maxUsage := 0
+	for _, action := range d.TopActions {
+		maxUsage = max(maxUsage, action.Repositories)
+	}
+	for _, action := range d.TopActions {
+		view.TopActions = append(view.TopActions, dashboardBar{
+			Label: action.Action,
+			Count: action.Repositories,
+			Width: barWidth(action.Repositories, maxUsage),
+		})
+	}
+
+	total := d.Pinning.Total()
+	for _, segment := range []struct {
+		kind  pinning.Kind
+		count int
+	}{{pinning.SHA, d.Pinning.SHA}, {pinning.Tag, d.Pinning.Tag}, {pinning.Branch, d.Pinning.Branch}, {pinning.Unpinned, d.Pinning.Unpinned}} {
+		view.Pinning = append(view.Pinning, dashboardSegment{Kind: segment.kind, Count: segment.count, Width: barWidth(segment.count, total)})
+	}
+
+	// A trend needs at least two summaries
+	if len(d.Trend) > 1 {
+		view.Trend = trendChart(d.Trend)
+	}
+
+	var sb strings.Builder
+	if err := dashboardTemplate.Execute(&sb, view); err != nil {
+		return "", fmt.Errorf("error rendering dashboard: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// barWidth returns the CSS width of a bar of count against the longest bar
+func barWidth(count, longest int) template.CSS {
+	if longest == 0 {
+		return "0%"
+	}
+	return template.CSS(fmt.Sprintf("%.1f%%", float64(count)*100/float64(longest)))
+}
+
+// trendChart plots the pinned share on a 0–100% scale and violations, when recorded, on a scale
+// up to their maximum
+func trendChart(points []dashboard.Point) *dashboardTrend {
+	trend := &dashboardTrend{Width: 600, Height: 160, From: points[0].Date, To: points[len(points)-1].Date}
+	for _, point := range points {
+		if point.Violations != nil {
+			trend.MaxViolations = max(trend.MaxViolations, *point.Violations)
+		}
+	}
+
+	const margin = 8
+	step := float64(trend.Width-2*margin) / float64(len(points)-1)
+	y := func(value, scale float64) float64 {
+		if scale == 0 {
+			return float64(trend.Height - margin)
+		}
+		return float64(trend.Height-margin) - value/scale*float64(trend.Height-2*margin)
+	}
+
+	var pinned, violations []string
+	for i, point := range points {
+		x := margin + float64(i)*step
+		pinned = append(pinned, fmt.Sprintf("%.1f,%.1f", x, y(point.PinnedPercent, 100)))
+		if point.Violations != nil {
+			violations = append(violations, fmt.Sprintf("%.1f,%.1f", x, y(float64(*point.Violations), float64(trend.MaxViolations))))
+		}
+	}
+	trend.Pinned = strings.Join(pinned, " ")
+	if len(violations) > 1 {
+		trend.Violations = strings.Join(violations, " ")
+	}
+	return trend
+}
@@ -0,0 +1,53 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/deadlinks"
+)
+
+// FormatMissingActions formats the action references whose repository or ref cannot be
+// resolved, or whose owner account's name is free to be claimed
+func FormatMissingActions(findings map[string][]deadlinks.Finding) string {
+	var sb strings.Builder
+	sb.WriteString("## 🔗 Missing Actions\n\n")
+
+	if len(findings) == 0 {
+		sb.WriteString("All action references resolve to an existing repository and ref.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(findings)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action | Issue |\n")
+		sb.WriteString("|--------|-------|\n")
+		for _, finding := range findings[repo] {
+			var issue string
+			switch finding.Reason {
+			case deadlinks.ReasonNotFound:
+				issue = "repository deleted or not accessible"
+			case deadlinks.ReasonRenamed:
+				issue = fmt.Sprintf("repository renamed to `%s`", finding.RenamedTo)
+			case deadlinks.ReasonMissingRef:
+				issue = "ref not found"
+			case deadlinks.ReasonOwnerDeleted:
+				issue = "⚠️ owner account deleted, its name can be claimed"
+			case deadlinks.ReasonOwnerRenamed:
+				issue = fmt.Sprintf("⚠️ owner account renamed, moved to `%s`, the old name can be claimed", finding.RenamedTo)
+			default:
+				issue = finding.Reason
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s |\n", finding.Action, issue))
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d action references to deleted, private or renamed repositories or owners, or missing refs, which break builds and can be taken over if the name is registered again.\n", total))
+
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/deprecations"
+)
+
+// FormatDeprecations formats the jobs on retired runner labels and actions on deprecated
+// Node.js runtimes, as failures when the policy escalates them or as warnings otherwise
+func FormatDeprecations(warnings map[string][]deprecations.Warning, failing bool) string {
+	var sb strings.Builder
+	sb.WriteString("## ⏳ Deprecations\n\n")
+
+	if len(warnings) == 0 {
+		sb.WriteString("No workflow depends on retired runners or deprecated Node.js runtimes.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(warnings)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Deprecated | Used By | Replacement |\n")
+		sb.WriteString("|------------|---------|-------------|\n")
+		for _, warning := range warnings[repo] {
+			if warning.Kind == deprecations.KindNode {
+				sb.WriteString(fmt.Sprintf("| `%s` runtime | `%s` | a release of the action on node20 or later |\n", warning.Runtime, warning.Action))
+			} else {
+				sb.WriteString(fmt.Sprintf("| `%s` runner | `%s` job `%s` | %s |\n", warning.Label, warning.Workflow, warning.Job, codeOrDash(warning.Replacement)))
+			}
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	severity := "warnings"
+	if failing {
+		severity = "failures"
+	}
+	sb.WriteString(fmt.Sprintf("Found %d deprecation %s.\n", total, severity))
+
+	return sb.String()
+}
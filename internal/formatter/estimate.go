@@ -0,0 +1,41 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// FormatScanEstimate formats the predicted API usage of a scan and whether the token's
+// remaining quota covers it
+func FormatScanEstimate(estimate github.ScanEstimate) string {
+	var sb strings.Builder
+	sb.WriteString("## 📊 Scan Estimate\n\n")
+
+	sb.WriteString("| Measure | Value |\n")
+	sb.WriteString("|---------|-------|\n")
+	sb.WriteString(fmt.Sprintf("| Repositories | %d |\n", estimate.Repositories))
+	sb.WriteString(fmt.Sprintf("| Workflow files per repository | %.1f (sampled %d) |\n", estimate.WorkflowsPerRepo, estimate.Sampled))
+	sb.WriteString(fmt.Sprintf("| Requests per repository | %.1f |\n", estimate.RequestsPerRepo))
+	sb.WriteString(fmt.Sprintf("| Predicted requests | %d |\n", estimate.Requests))
+	if estimate.RateLimitKnown {
+		sb.WriteString(fmt.Sprintf("| Remaining quota | %d of %d, resets %s |\n", estimate.Remaining, estimate.Limit, estimate.Reset.UTC().Format(time.RFC3339)))
+	} else {
+		sb.WriteString("| Remaining quota | unknown |\n")
+	}
+	sb.WriteString("\n")
+
+	switch {
+	case !estimate.RateLimitKnown:
+		sb.WriteString("The provider does not report a rate limit, so the quota could not be checked.\n")
+	case estimate.Sufficient():
+		sb.WriteString(fmt.Sprintf("✅ The remaining quota covers the scan, leaving %d requests.\n", estimate.Remaining-estimate.Requests))
+	default:
+		sb.WriteString(fmt.Sprintf("❌ The scan needs %d more requests than remain. Schedule it after the reset, or use --checkpoint and --resume to continue once the quota resets.\n", estimate.Requests-estimate.Remaining))
+	}
+	sb.WriteString(fmt.Sprintf("\nThe estimate itself used %d requests.\n", estimate.EstimateRequests))
+
+	return sb.String()
+}
@@ -0,0 +1,78 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// FormatExplanations formats which rule allowed or denied each action, grouped by repository
+func FormatExplanations(explanations map[string][]policy.Explanation) string {
+	var sb strings.Builder
+	sb.WriteString("## 🔍 Policy Decisions\n\n")
+
+	if len(explanations) == 0 {
+		sb.WriteString("No actions were evaluated.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(explanations)
+
+	for _, repo := range repos {
+		repoExplanations := explanations[repo]
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		if len(repoExplanations) > 0 {
+			sb.WriteString(fmt.Sprintf("Layers: %s", strings.Join(repoExplanations[0].Layers, " → ")))
+			if repoExplanations[0].Mode != "" {
+				sb.WriteString(fmt.Sprintf(", mode: %s", repoExplanations[0].Mode))
+			}
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString("| Action | Outcome | Rule | Matched | Source | Reason |\n")
+		sb.WriteString("|--------|---------|------|---------|--------|--------|\n")
+		for _, explanation := range repoExplanations {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s |\n",
+				explanation.Action, explanationOutcome(explanation), explanation.Rule,
+				codeOrDash(explanation.Entry), explanation.Source, explanation.Reason))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// FormatExplanation formats which rule allows or denies a single action
+func FormatExplanation(explanation policy.Explanation) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s in %s: %s\n\n", explanation.Action, explanation.Repository, explanationOutcome(explanation)))
+	sb.WriteString(fmt.Sprintf("  Rule:     %s (%s)\n", explanation.Rule, explanation.Source))
+	if explanation.Entry != "" {
+		sb.WriteString(fmt.Sprintf("  Matched:  %s\n", explanation.Entry))
+	}
+	if explanation.Mode != "" {
+		sb.WriteString(fmt.Sprintf("  Mode:     %s\n", explanation.Mode))
+	}
+	sb.WriteString(fmt.Sprintf("  Layers:   %s\n", strings.Join(explanation.Layers, " → ")))
+	if explanation.CustomRule != "" {
+		sb.WriteString(fmt.Sprintf("  Custom:   %s\n", explanation.CustomRule))
+	}
+	sb.WriteString(fmt.Sprintf("  Reason:   %s\n", explanation.Reason))
+	return sb.String()
+}
+
+// explanationOutcome labels whether an explained action is allowed
+func explanationOutcome(explanation policy.Explanation) string {
+	if explanation.Allowed {
+		return "✅ allowed"
+	}
+	return "❌ denied"
+}
+
+// codeOrDash formats a value as inline code, or a dash when it is empty
+func codeOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return "`" + value + "`"
+}
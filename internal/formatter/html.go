@@ -0,0 +1,133 @@
+package formatter
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/history"
+)
+
+// htmlTemplate renders the usage report and optional adoption heatmap
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>GitHub Actions Usage Report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 2rem; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; }
+th { background: #f6f8fa; }
+td.cell { text-align: center; min-width: 2.5rem; }
+code { font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>GitHub Actions Usage Report</h1>
+{{- if .Heatmap}}
+<h2>Action Adoption Over Time</h2>
+<p>Number of repositories using each action, sampled at the end of each month.</p>
+<table>
+<tr><th>Action</th>{{range .Heatmap.Months}}<th>{{.}}</th>{{end}}</tr>
+{{- range .HeatmapRows}}
+<tr><td><code>{{.Action}}</code></td>{{range .Cells}}<td class="cell" style="background: rgba(46, 160, 67, {{.Alpha}})">{{.Count}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Most Used Actions</h2>
+<table>
+<tr><th>Action</th><th>Usage Count</th></tr>
+{{- range .Usages}}
+<tr><td><code>{{.Action}}</code></td><td>{{.Count}}</td></tr>
+{{- end}}
+</table>
+<h2>Actions by Repository</h2>
+{{- range .Repos}}
+<h3>{{.Name}}</h3>
+<table>
+<tr><th>Action Name</th><th>Action Reference</th></tr>
+{{- range .Actions}}
+<tr><td>{{if .Name}}{{.Name}}{{else}}<em>Unnamed</em>{{end}}</td><td><code>{{.Uses}}</code></td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlUsage struct {
+	Action string
+	Count  int
+}
+
+type htmlRepo struct {
+	Name    string
+	Actions []Action
+}
+
+type htmlCell struct {
+	Count int
+	Alpha template.CSS
+}
+
+type htmlHeatmapRow struct {
+	Action string
+	Cells  []htmlCell
+}
+
+// FormatHTML formats the actions data as a standalone HTML document.
+// When a heatmap is provided, action adoption over time is charted at the top of the report.
+func FormatHTML(data map[string][]Action, heatmap *history.Heatmap) (string, error) {
+	view := struct {
+		Heatmap     *history.Heatmap
+		HeatmapRows []htmlHeatmapRow
+		Usages      []htmlUsage
+		Repos       []htmlRepo
+	}{Heatmap: heatmap}
+
+	repos := sortedRepos(data)
+
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		if len(data[repo]) == 0 {
+			continue
+		}
+		view.Repos = append(view.Repos, htmlRepo{Name: repo, Actions: data[repo]})
+		for _, action := range data[repo] {
+			counts[action.Uses]++
+		}
+	}
+
+	for action, count := range counts {
+		view.Usages = append(view.Usages, htmlUsage{Action: action, Count: count})
+	}
+	sort.Slice(view.Usages, func(i, j int) bool {
+		if view.Usages[i].Count != view.Usages[j].Count {
+			return view.Usages[i].Count > view.Usages[j].Count
+		}
+		return view.Usages[i].Action < view.Usages[j].Action
+	})
+
+	if heatmap != nil {
+		max := heatmap.Max()
+		for _, action := range heatmap.Actions {
+			row := htmlHeatmapRow{Action: action}
+			for _, count := range heatmap.Counts[action] {
+				alpha := 0.0
+				if max > 0 {
+					alpha = float64(count) / float64(max)
+				}
+				row.Cells = append(row.Cells, htmlCell{Count: count, Alpha: template.CSS(fmt.Sprintf("%.2f", alpha))})
+			}
+			view.HeatmapRows = append(view.HeatmapRows, row)
+		}
+	}
+
+	var sb strings.Builder
+	if err := htmlTemplate.Execute(&sb, view); err != nil {
+		return "", fmt.Errorf("error rendering HTML: %w", err)
+	}
+	return sb.String(), nil
+}
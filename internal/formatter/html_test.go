@@ -0,0 +1,154 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/dashboard"
+	"github.com/ihavespoons/action-control/internal/history"
+	"github.com/ihavespoons/action-control/internal/inventory"
+	"github.com/ihavespoons/action-control/internal/pinning"
+)
+
+func TestFormatHTML(t *testing.T) {
+	data := map[string][]Action{
+		"org/repo1": {
+			{Name: "Checkout", Uses: "actions/checkout@v3"},
+			{Name: "", Uses: "custom/<script>@v1"},
+		},
+	}
+
+	heatmap := &history.Heatmap{
+		Months:  []string{"2025-01", "2025-02"},
+		Actions: []string{"actions/checkout"},
+		Counts:  map[string][]int{"actions/checkout": {1, 2}},
+	}
+
+	result, err := FormatHTML(data, heatmap)
+	if err != nil {
+		t.Fatalf("FormatHTML returned error: %v", err)
+	}
+
+	expectedPhrases := []string{
+		"<h1>GitHub Actions Usage Report</h1>",
+		"<h2>Action Adoption Over Time</h2>",
+		"<th>2025-01</th><th>2025-02</th>",
+		"rgba(46, 160, 67, 0.50)\">1</td>",
+		"rgba(46, 160, 67, 1.00)\">2</td>",
+		"<h3>org/repo1</h3>",
+		"<em>Unnamed</em>",
+		"custom/&lt;script&gt;@v1",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected HTML to contain %q, but it doesn't", phrase)
+		}
+	}
+
+	// Without history the heatmap section is omitted
+	result, err = FormatHTML(data, nil)
+	if err != nil {
+		t.Fatalf("FormatHTML returned error: %v", err)
+	}
+	if strings.Contains(result, "Action Adoption Over Time") {
+		t.Error("Expected no heatmap section without history")
+	}
+}
+
+func TestFormatInventoryHTML(t *testing.T) {
+	verified := true
+	inv := &inventory.Inventory{
+		GeneratedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Actions: []inventory.Record{{
+			Action:       "actions/checkout",
+			Versions:     []string{"actions/checkout@v4"},
+			Repositories: []string{"org/repo1"},
+			Pinning:      pinning.Counts{SHA: 1, Tag: 1},
+			Publisher:    inventory.Publisher{Owner: "actions", Verified: &verified},
+			FirstSeen:    time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+			PolicyStatus: inventory.StatusPartial,
+			Violations:   []string{"org/<repo2>"},
+		}},
+	}
+
+	result, err := FormatInventoryHTML(inv)
+	if err != nil {
+		t.Fatalf("FormatInventoryHTML returned error: %v", err)
+	}
+
+	expectedPhrases := []string{
+		"<h1>GitHub Actions Inventory</h1>",
+		`<a href="#action-actions-checkout"><code>actions/checkout</code></a>`,
+		`<section id="action-actions-checkout">`,
+		"actions ✔ verified",
+		"<td>50%</td>",
+		`<td class="partial">partial</td>`,
+		"<td>2026-01-15</td>",
+		"<h3>Violating Repositories</h3>",
+		"org/&lt;repo2&gt;",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected HTML to contain %q, but it doesn't", phrase)
+		}
+	}
+}
+
+func TestFormatDashboard(t *testing.T) {
+	violations, earlier := 3, 5
+	d := &dashboard.Dashboard{
+		Title:        "demo-org GitHub Actions",
+		GeneratedAt:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Repositories: 2,
+		References:   4,
+		Pinning:      pinning.Counts{SHA: 1, Tag: 3},
+		TopActions:   []dashboard.ActionCount{{Action: "actions/checkout", Repositories: 2}, {Action: "custom/<script>", Repositories: 1}},
+		Violations:   []dashboard.RepositoryViolations{{Repository: "demo-org/api", Violations: []string{"some-user/upload-artifact@main"}}},
+		Evaluated:    true,
+		Trend: []dashboard.Point{
+			{Date: "2026-02-01", PinnedPercent: 0, Violations: &earlier},
+			{Date: "2026-03-01", PinnedPercent: 25, Violations: &violations},
+		},
+	}
+
+	result, err := FormatDashboard(d)
+	if err != nil {
+		t.Fatalf("FormatDashboard returned error: %v", err)
+	}
+
+	expectedPhrases := []string{
+		"<h1>demo-org GitHub Actions</h1>",
+		"<strong>25%</strong>pinned to a commit SHA",
+		"<strong>1</strong>repositories violating the policy",
+		"<h2>Trend</h2>",
+		`points="8.0,152.0 592.0,116.0"`,
+		"Violations, 0–5",
+		`<div class="sha" style="width: 25.0%" title="sha: 1"></div>`,
+		`title="some-user/upload-artifact@main">1</div>`,
+		`<code>actions/checkout</code><div class="bar" style="width: 100.0%">2</div>`,
+		"custom/&lt;script&gt;",
+		"Generated by action-control on 2026-03-01 12:00 UTC",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected dashboard to contain %q, but it doesn't", phrase)
+		}
+	}
+
+	// A single summary has no trend, and without a policy there are no violations to chart
+	d.Trend = d.Trend[1:]
+	d.Evaluated, d.Violations = false, nil
+	result, err = FormatDashboard(d)
+	if err != nil {
+		t.Fatalf("FormatDashboard returned error: %v", err)
+	}
+	for _, phrase := range []string{"<h2>Trend</h2>", "Violations by Repository"} {
+		if strings.Contains(result, phrase) {
+			t.Errorf("Expected dashboard not to contain %q", phrase)
+		}
+	}
+}
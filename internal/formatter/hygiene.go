@@ -0,0 +1,51 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/hygiene"
+)
+
+// hygieneRuleLabels are the headings of workflow hygiene rules in reports
+var hygieneRuleLabels = map[string]string{
+	hygiene.RuleContinueOnError: "Ignored failure",
+	hygiene.RuleMissingTimeout:  "No timeout",
+}
+
+// FormatHygiene formats the ignored security check failures and jobs without timeouts
+func FormatHygiene(findings map[string][]hygiene.Finding) string {
+	var sb strings.Builder
+	sb.WriteString("## 🧹 Workflow Hygiene\n\n")
+
+	if len(findings) == 0 {
+		sb.WriteString("No ignored security check failures or jobs without timeouts found.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(findings)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Issue | Workflow | Job | Action | Detail |\n")
+		sb.WriteString("|-------|----------|-----|--------|--------|\n")
+		for _, finding := range findings[repo] {
+			location := finding.Workflow
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, finding.Line)
+			}
+			action := ""
+			if finding.Action != "" {
+				action = fmt.Sprintf("`%s`", finding.Action)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | `%s` | %s | %s |\n", hygieneRuleLabels[finding.Rule], location, finding.Job, action, finding.Detail))
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d workflow hygiene issues.\n", total))
+
+	return sb.String()
+}
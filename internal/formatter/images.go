@@ -0,0 +1,42 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// FormatImageViolations formats the container images violating the image policy, grouped by repository
+func FormatImageViolations(violations map[string][]github.Image) string {
+	var sb strings.Builder
+	sb.WriteString("## 🐳 Container Images\n\n")
+
+	if len(violations) == 0 {
+		sb.WriteString("All job containers and services use allowed images.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(violations)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Image | Workflow | Job | Service |\n")
+		sb.WriteString("|-------|----------|-----|---------|\n")
+
+		for _, image := range violations[repo] {
+			service := image.Service
+			if service == "" {
+				service = "-"
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | `%s` | %s | %s |\n", image.Image, image.Workflow, image.Job, service))
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\nFound %d container images not allowed by policy.\n", count))
+
+	return sb.String()
+}
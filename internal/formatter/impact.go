@@ -0,0 +1,45 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/impact"
+)
+
+// FormatImpact formats runner deprecation impact as a Markdown document
+func FormatImpact(impacts []impact.Impact) string {
+	if len(impacts) == 0 {
+		return "✅ No workflows are pinned to deprecated runner labels."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Runner Deprecation Impact Report\n\n")
+	sb.WriteString("| Repository | Workflow | Job | Label | Replacement | Deadline | Owners |\n")
+	sb.WriteString("|------------|----------|-----|-------|-------------|----------|--------|\n")
+
+	repos := make(map[string]bool)
+	for _, i := range impacts {
+		repos[i.Repository] = true
+
+		owners := strings.Join(i.Owners, ", ")
+		if owners == "" {
+			owners = "_Unowned_"
+		}
+		replacement := i.Replacement
+		if replacement == "" {
+			replacement = "-"
+		}
+		deadline := i.Deadline
+		if deadline == "" {
+			deadline = "-"
+		}
+
+		sb.WriteString(fmt.Sprintf("| %s | `%s` | %s | `%s` | %s | %s | %s |\n",
+			i.Repository, i.Workflow, i.Job, i.Label, replacement, deadline, owners))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nFound %d jobs in %d repositories pinned to deprecated runner labels.\n", len(impacts), len(repos)))
+
+	return sb.String()
+}
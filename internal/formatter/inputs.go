@@ -0,0 +1,49 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// FormatInputViolations formats the steps whose inputs break the input rules, grouped by
+// repository
+func FormatInputViolations(violations map[string][]policy.InputViolation) string {
+	var sb strings.Builder
+	sb.WriteString("## 🎛️ Action Inputs\n\n")
+
+	if len(violations) == 0 {
+		sb.WriteString("All steps set their inputs as the input rules require.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(violations)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action | Workflow | Input | Value | Problem |\n")
+		sb.WriteString("|--------|----------|-------|-------|---------|\n")
+		for _, violation := range violations[repo] {
+			workflow := violation.Workflow
+			if violation.Line > 0 {
+				workflow = fmt.Sprintf("%s:%d", workflow, violation.Line)
+			}
+			value := fmt.Sprintf("`%s`", violation.Value)
+			if violation.Unset {
+				value = "not set"
+				if violation.Value != "" {
+					value = fmt.Sprintf("not set, defaults to `%s`", violation.Value)
+				}
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | `%s` | `%s` | %s | %s |\n", violation.Action, workflow, violation.Input, value, violation.Reason))
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d steps breaking input rules.\n", count))
+
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package formatter
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/inventory"
+	"github.com/ihavespoons/action-control/internal/pinning"
+)
+
+// inventoryTemplate renders the action inventory with a detail section per action
+var inventoryTemplate = template.Must(template.New("inventory").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>GitHub Actions Inventory</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 2rem; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-size: 0.9em; }
+.allowed { color: #1a7f37; }
+.denied { color: #cf222e; }
+.partial { color: #9a6700; }
+</style>
+</head>
+<body>
+<h1>GitHub Actions Inventory</h1>
+<p>{{len .Records}} unique actions, generated {{.GeneratedAt}}.</p>
+<table>
+<tr><th>Action</th><th>Publisher</th><th>Versions</th><th>Repositories</th><th>SHA Pinned</th><th>Policy</th><th>First Seen</th></tr>
+{{- range .Records}}
+<tr><td><a href="#{{.ID}}"><code>{{.Action}}</code></a></td><td>{{.Publisher}}</td><td>{{len .Versions}}</td><td>{{len .Repositories}}</td><td>{{.SHAPinned}}</td><td class="{{.PolicyStatus}}">{{.PolicyStatus}}</td><td>{{.FirstSeen}}</td></tr>
+{{- end}}
+</table>
+{{- range .Records}}
+<section id="{{.ID}}">
+<h2><code>{{.Action}}</code></h2>
+<p>Publisher: {{.Publisher}} &middot; Policy: <span class="{{.PolicyStatus}}">{{.PolicyStatus}}</span> &middot; First seen: {{.FirstSeen}}</p>
+{{- with .Metadata}}
+<p>Repository: {{if .Archived}}<span class="denied">archived</span> &middot; {{end}}{{if .Fork}}<span class="denied">fork{{with .Parent}} of {{.}}{{end}}</span> &middot; {{end}}created {{.CreatedAt.Format "2006-01-02"}} &middot; last push {{.PushedAt.Format "2006-01-02"}} &middot; {{.Stars}} stars &middot; {{.OpenAdvisories}} open security advisories</p>
+{{- end}}
+<h3>Versions in Use</h3>
+<ul>
+{{- range .Versions}}
+<li><code>{{.}}</code></li>
+{{- end}}
+</ul>
+<h3>Pinning</h3>
+<p>SHA: {{.Pinning.SHA}}, tag: {{.Pinning.Tag}}, branch: {{.Pinning.Branch}}, unpinned: {{.Pinning.Unpinned}}</p>
+<h3>Repositories</h3>
+<ul>
+{{- range .Repositories}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- if .Violations}}
+<h3>Violating Repositories</h3>
+<ul>
+{{- range .Violations}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlInventoryRecord struct {
+	inventory.Record
+	ID        string
+	Publisher string
+	SHAPinned string
+	FirstSeen string
+}
+
+// FormatInventoryHTML formats the action inventory as a standalone HTML document with an
+// overview table linking to a detail section for each unique action
+func FormatInventoryHTML(inv *inventory.Inventory) (string, error) {
+	view := struct {
+		GeneratedAt string
+		Records     []htmlInventoryRecord
+	}{GeneratedAt: inv.GeneratedAt.Format("2006-01-02 15:04 MST")}
+
+	for _, record := range inv.Actions {
+		publisher := record.Publisher.Owner
+		if publisher == "" {
+			publisher = "-"
+		} else if record.Publisher.Verified != nil && *record.Publisher.Verified {
+			publisher += " ✔ verified"
+		}
+
+		view.Records = append(view.Records, htmlInventoryRecord{
+			Record:    record,
+			ID:        anchorID(record.Action),
+			Publisher: publisher,
+			SHAPinned: fmt.Sprintf("%.0f%%", record.Pinning.Percent(pinning.SHA)),
+			FirstSeen: record.FirstSeen.Format("2006-01-02"),
+		})
+	}
+
+	var sb strings.Builder
+	if err := inventoryTemplate.Execute(&sb, view); err != nil {
+		return "", fmt.Errorf("error rendering HTML: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// anchorID derives an HTML id from an action name
+func anchorID(action string) string {
+	var sb strings.Builder
+	sb.WriteString("action-")
+	for _, r := range strings.ToLower(action) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
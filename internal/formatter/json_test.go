@@ -0,0 +1,192 @@
+package formatter
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/deprecations"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// schemaObject is the subset of a JSON schema object definition checked against the Go types
+type schemaObject struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// checkSchemaFields verifies that a schema object describes every JSON field of a type and
+// requires exactly the fields that are always present
+func checkSchemaFields(t *testing.T, name string, object schemaObject, typ reflect.Type) {
+	t.Helper()
+
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("json")
+		field, options, _ := strings.Cut(tag, ",")
+		if field == "" || field == "-" {
+			continue
+		}
+
+		if _, ok := object.Properties[field]; !ok {
+			t.Errorf("%s: schema does not describe field %q", name, field)
+		}
+		required := slices.Contains(object.Required, field)
+		if omitempty := strings.Contains(options, "omitempty"); required == omitempty {
+			t.Errorf("%s: field %q required = %v, but omitempty = %v", name, field, required, omitempty)
+		}
+	}
+
+	if len(object.Properties) != countJSONFields(typ) {
+		t.Errorf("%s: schema describes %d fields, type has %d", name, len(object.Properties), countJSONFields(typ))
+	}
+}
+
+// countJSONFields counts the fields of a type that are encoded to JSON
+func countJSONFields(typ reflect.Type) int {
+	count := 0
+	for i := 0; i < typ.NumField(); i++ {
+		if field, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ","); field != "" && field != "-" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestEnforceReportSchema(t *testing.T) {
+	data, err := os.ReadFile("../../schemas/enforce-report.schema.json")
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+
+	var schema struct {
+		schemaObject
+		Defs map[string]schemaObject `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	var version struct {
+		Const string `json:"const"`
+	}
+	json.Unmarshal(schema.Properties["schema_version"], &version)
+	if version.Const != EnforceSchemaVersion {
+		t.Errorf("Schema describes version %q, output is version %q", version.Const, EnforceSchemaVersion)
+	}
+
+	checkSchemaFields(t, "report", schema.schemaObject, reflect.TypeOf(EnforceReport{}))
+	checkSchemaFields(t, "violation", schema.Defs["violation"], reflect.TypeOf(Violation{}))
+	checkSchemaFields(t, "repository", schema.Defs["repository"], reflect.TypeOf(RepositoryResult{}))
+}
+
+func TestSortViolations(t *testing.T) {
+	violations := []Violation{
+		{Repository: "org/b", Workflow: "ci.yml", Line: 3, Action: "x@v1"},
+		{Repository: "org/a", Workflow: "release.yml", Line: 1, Action: "y@v1"},
+		{Repository: "org/a", Workflow: "ci.yml", Line: 10, Action: "z@v1"},
+		{Repository: "org/a", Workflow: "ci.yml", Line: 2, Action: "w@v1"},
+	}
+
+	SortViolations(violations)
+
+	var order []string
+	for _, v := range violations {
+		order = append(order, v.Action)
+	}
+	if strings.Join(order, ",") != "w@v1,z@v1,y@v1,x@v1" {
+		t.Errorf("Unexpected violation order: %v", order)
+	}
+}
+
+func TestFormatSARIF(t *testing.T) {
+	report := EnforceReport{
+		Repositories: map[string]RepositoryResult{
+			"org/api": {
+				Owners:         []string{"@org/platform", "@org/security"},
+				Deprecations:   []deprecations.Warning{{Kind: deprecations.KindRunner, Workflow: ".github/workflows/ci.yml", Job: "test", Label: "ubuntu-20.04", Replacement: "ubuntu-24.04"}},
+				MergeConflicts: []policy.MergeConflict{{Kind: "drops_denied", Rule: "denied_actions", Entry: "evil/action", Resolution: "global"}},
+			},
+		},
+		Violations: []Violation{{
+			Repository: "org/api",
+			Workflow:   ".github/workflows/ci.yml",
+			Line:       12,
+			Action:     "evil/action@v1",
+			Rule:       policy.RuleDeniedActions,
+			Severity:   policy.SeverityError,
+			Source:     policy.SourceGlobal,
+		}},
+	}
+
+	output, err := FormatSARIF(report, false, "v1.2.3")
+	if err != nil {
+		t.Fatalf("FormatSARIF returned error: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Version string `json:"version"`
+					Rules   []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				Properties map[string]string `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatalf("Failed to parse SARIF: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Version != "v1.2.3" {
+		t.Fatalf("Unexpected SARIF log: %s", output)
+	}
+
+	results := log.Runs[0].Results
+	levels := make(map[string]string)
+	for _, result := range results {
+		levels[result.RuleID] = result.Level
+	}
+	expected := map[string]string{policy.RuleDeniedActions: "error", "deprecated_runner": "warning", "merge_conflict": "note"}
+	if !reflect.DeepEqual(levels, expected) {
+		t.Errorf("Expected levels %v, got %v", expected, levels)
+	}
+	if location := results[0].Locations[0].PhysicalLocation; location.ArtifactLocation.URI != ".github/workflows/ci.yml" || location.Region == nil || location.Region.StartLine != 12 {
+		t.Errorf("Expected the violation at ci.yml line 12, got %+v", location)
+	}
+	if results[0].Properties["repository"] != "org/api" || results[0].Properties["owners"] != "@org/platform, @org/security" {
+		t.Errorf("Expected the repository and its owners in the result properties, got %v", results[0].Properties)
+	}
+	if len(log.Runs[0].Tool.Driver.Rules) != 3 {
+		t.Errorf("Expected a rule for each of the 3 rules with results, got %+v", log.Runs[0].Tool.Driver.Rules)
+	}
+
+	// Escalated deprecations are errors
+	output, err = FormatSARIF(report, true, "")
+	if err != nil {
+		t.Fatalf("FormatSARIF returned error: %v", err)
+	}
+	if !strings.Contains(output, `"ruleId": "deprecated_runner",
+          "level": "error"`) {
+		t.Errorf("Expected escalated deprecations as errors, got: %s", output)
+	}
+}
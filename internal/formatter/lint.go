@@ -0,0 +1,38 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/lint"
+)
+
+// FormatLintFindings formats actionlint findings grouped by repository
+func FormatLintFindings(findings map[string][]lint.Finding) string {
+	if len(findings) == 0 {
+		return "✅ No workflow lint findings."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## ⚠️ Workflow Lint Findings\n\n")
+
+	repos := sortedRepos(findings)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Location | Kind | Message |\n")
+		sb.WriteString("|----------|------|---------|\n")
+
+		for _, finding := range findings[repo] {
+			sb.WriteString(fmt.Sprintf("| `%s:%d:%d` | %s | %s |\n",
+				finding.File, finding.Line, finding.Column, finding.Kind, strings.ReplaceAll(finding.Message, "|", "\\|")))
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\nFound %d lint findings in %d repositories.\n", total, len(findings)))
+
+	return sb.String()
+}
@@ -12,18 +12,23 @@ type Action struct {
 	Uses string
 }
 
+// sortedRepos returns the repositories keying a map in sorted order, for consistent output
+func sortedRepos[T any](byRepo map[string]T) []string {
+	repos := make([]string, 0, len(byRepo))
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
 // FormatMarkdown formats the actions data as a Markdown document
 func FormatMarkdown(data map[string][]Action) string {
 	var builder strings.Builder
 
 	builder.WriteString("# GitHub Actions Usage Report\n\n")
 
-	// Sort repositories for consistent output
-	repos := make([]string, 0, len(data))
-	for repo := range data {
-		repos = append(repos, repo)
-	}
-	sort.Strings(repos)
+	repos := sortedRepos(data)
 
 	// Track unique actions
 	uniqueActions := make(map[string]int)
@@ -3,6 +3,26 @@ package formatter
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/artifacts"
+	"github.com/ihavespoons/action-control/internal/callgraph"
+	"github.com/ihavespoons/action-control/internal/cloud"
+	"github.com/ihavespoons/action-control/internal/deadlinks"
+	"github.com/ihavespoons/action-control/internal/deprecations"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/hygiene"
+	"github.com/ihavespoons/action-control/internal/impact"
+	"github.com/ihavespoons/action-control/internal/lint"
+	"github.com/ihavespoons/action-control/internal/metadata"
+	"github.com/ihavespoons/action-control/internal/orgsettings"
+	"github.com/ihavespoons/action-control/internal/pinning"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/runtimes"
+	"github.com/ihavespoons/action-control/internal/stats"
+	"github.com/ihavespoons/action-control/internal/store"
+	"github.com/ihavespoons/action-control/internal/suppress"
+	"github.com/ihavespoons/action-control/internal/updates"
 )
 
 func TestFormatMarkdown(t *testing.T) {
@@ -63,7 +83,7 @@ func TestFormatPolicyViolations(t *testing.T) {
 			"org/repo2": {"third/violation@v3"},
 		}
 
-		result := FormatPolicyViolations(violations, "allow")
+		result := FormatPolicyViolations(violations, nil, "allow", GroupByRepo)
 
 		expectedPhrases := []string{
 			"# Policy Violation Report",
@@ -90,7 +110,7 @@ func TestFormatPolicyViolations(t *testing.T) {
 			"org/repo2": {"third/violation@v3"},
 		}
 
-		result := FormatPolicyViolations(violations, "deny")
+		result := FormatPolicyViolations(violations, nil, "deny", GroupByRepo)
 
 		expectedPhrases := []string{
 			"# Policy Violation Report",
@@ -115,11 +135,82 @@ func TestFormatPolicyViolations(t *testing.T) {
 		}
 	})
 
+	// Test grouping by action
+	t.Run("grouped by action", func(t *testing.T) {
+		violations := map[string][]string{
+			"org/repo1": {"unsafe/action@v1", "another/bad-action@v2"},
+			"org/repo2": {"unsafe/action@v1"},
+		}
+
+		result := FormatPolicyViolations(violations, nil, "deny", GroupByAction)
+
+		expectedPhrases := []string{
+			"## ❌ Denied Actions Found",
+			"### `unsafe/action@v1`\n\nDenied action used in 2 repositories:\n\n- org/repo1\n- org/repo2\n",
+			"### `another/bad-action@v2`\n\nDenied action used in 1 repository:\n\n- org/repo1\n",
+			"Found 2 repositories using denied actions",
+		}
+
+		for _, phrase := range expectedPhrases {
+			if !strings.Contains(result, phrase) {
+				t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+			}
+		}
+
+		// The most widespread action comes first, and each action is listed once
+		if strings.Index(result, "unsafe/action@v1") > strings.Index(result, "another/bad-action@v2") {
+			t.Error("Expected the action used by most repositories first")
+		}
+		if strings.Count(result, "unsafe/action@v1") != 1 {
+			t.Error("Expected each action to be listed once")
+		}
+	})
+
+	// Test grouping by owning team
+	t.Run("grouped by team", func(t *testing.T) {
+		violations := map[string][]string{
+			"org/api":    {"unsafe/action@v1"},
+			"org/shared": {"another/bad-action@v2"},
+			"org/legacy": {"unsafe/action@v1"},
+		}
+		owners := map[string][]string{
+			"org/api":    {"@org/payments"},
+			"org/shared": {"@org/payments", "@org/platform"},
+		}
+
+		result := FormatPolicyViolations(violations, owners, "allow", GroupByTeam)
+
+		expectedPhrases := []string{
+			"### @org/payments\n\n#### org/api\n\nThe following actions are not allowed by policy:\n\n- `unsafe/action@v1`\n\n#### org/shared\n",
+			"### @org/platform\n\n#### org/shared\n",
+			"### Unowned repositories\n\n#### org/legacy\n",
+			"Found 3 repositories with policy violations",
+		}
+		for _, phrase := range expectedPhrases {
+			if !strings.Contains(result, phrase) {
+				t.Errorf("Expected report to contain %q, got:\n%s", phrase, result)
+			}
+		}
+		if strings.Index(result, "@org/platform") > strings.Index(result, "Unowned repositories") {
+			t.Error("Expected unowned repositories after the teams")
+		}
+
+		// Grouped by repository, owners are noted below each repository
+		byRepo := FormatPolicyViolations(violations, owners, "allow", GroupByRepo)
+		if !strings.Contains(byRepo, "### org/shared\n\nOwners: @org/payments, @org/platform\n\n") {
+			t.Errorf("Expected the owners of org/shared, got:\n%s", byRepo)
+		}
+		byAction := FormatPolicyViolations(violations, owners, "allow", GroupByAction)
+		if !strings.Contains(byAction, "- org/api (@org/payments)\n- org/legacy\n") {
+			t.Errorf("Expected owners next to the repositories using an action, got:\n%s", byAction)
+		}
+	})
+
 	// Test without violations
 	t.Run("without violations", func(t *testing.T) {
 		violations := map[string][]string{}
 
-		result := FormatPolicyViolations(violations, "allow")
+		result := FormatPolicyViolations(violations, nil, "allow", GroupByRepo)
 
 		expected := "✅ All repositories comply with the action policy."
 		if !strings.Contains(result, expected) {
@@ -127,3 +218,1154 @@ func TestFormatPolicyViolations(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatLintFindings(t *testing.T) {
+	findings := map[string][]lint.Finding{
+		"org/repo1": {
+			{File: ".github/workflows/ci.yml", Line: 12, Column: 20, Kind: "expression", Message: "property \"foo\" is not defined"},
+		},
+	}
+
+	result := FormatLintFindings(findings)
+
+	expectedPhrases := []string{
+		"## ⚠️ Workflow Lint Findings",
+		"### org/repo1",
+		"`.github/workflows/ci.yml:12:20`",
+		"expression",
+		"Found 1 lint findings in 1 repositories.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+
+	if !strings.Contains(FormatLintFindings(nil), "No workflow lint findings") {
+		t.Error("Expected clean message when there are no findings")
+	}
+}
+
+func TestFormatImpact(t *testing.T) {
+	impacts := []impact.Impact{
+		{Repository: "org/repo1", Workflow: ".github/workflows/ci.yml", Job: "build", Label: "ubuntu-20.04", Replacement: "ubuntu-24.04", Owners: []string{"@org/ci-team"}},
+		{Repository: "org/repo2", Workflow: ".github/workflows/release.yml", Job: "publish", Label: "windows-2019"},
+	}
+
+	result := FormatImpact(impacts)
+
+	expectedPhrases := []string{
+		"# Runner Deprecation Impact Report",
+		"| org/repo1 | `.github/workflows/ci.yml` | build | `ubuntu-20.04` | ubuntu-24.04 | - | @org/ci-team |",
+		"| org/repo2 | `.github/workflows/release.yml` | publish | `windows-2019` | - | - | _Unowned_ |",
+		"Found 2 jobs in 2 repositories",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+}
+
+func TestFormatCloudAccess(t *testing.T) {
+	accesses := map[string][]cloud.Access{
+		"org/repo1": {
+			{Repository: "org/repo1", Provider: "aws", Identity: "arn:aws:iam::1:role/deploy", Workflow: ".github/workflows/deploy.yml", Job: "deploy", Allowed: true},
+			{Repository: "org/repo1", Provider: "gcp", Workflow: ".github/workflows/deploy.yml", Job: "gcp"},
+		},
+	}
+
+	result := FormatCloudAccess(accesses)
+
+	expectedPhrases := []string{
+		"## ☁️ Cloud Access",
+		"### org/repo1",
+		"| ✅ | aws | `arn:aws:iam::1:role/deploy` | `.github/workflows/deploy.yml` | deploy |",
+		"| ❌ | gcp | _unknown_ |",
+		"Found 1 cloud identities assumed without policy permission.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+}
+
+func TestFormatPinning(t *testing.T) {
+	report := pinning.Analyze(map[string][]github.Action{
+		"org/repo1": {
+			{Uses: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683", Workflow: ".github/workflows/ci.yml"},
+			{Uses: "actions/setup-go@v5", Workflow: ".github/workflows/ci.yml"},
+		},
+	})
+
+	result := FormatPinning(report)
+
+	expectedPhrases := []string{
+		"# GitHub Actions Pinning Report",
+		"| SHA | 1 | 50.0% |",
+		"| Tag | 1 | 50.0% |",
+		"| org/repo1 | 50% | 50% | 0% | 0% |",
+		"| `actions/setup-go@v5` | Tag | `.github/workflows/ci.yml` |",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Contains(result, "| `actions/checkout@") {
+		t.Error("Expected SHA-pinned reference to be omitted from mutable references")
+	}
+}
+
+func TestFormatPinDrift(t *testing.T) {
+	if result := FormatPinDrift(nil); !strings.Contains(result, "All commented SHA pins match") {
+		t.Errorf("Expected success message for no drift, got %q", result)
+	}
+
+	result := FormatPinDrift(map[string][]pinning.Drift{
+		"org/repo1": {
+			{Uses: "actions/cache@2222", Tag: "v4.0.0", PinnedSHA: "2222", TagSHA: "1111", Reason: "tag points to a different commit"},
+			{Uses: "actions/setup-go@1111", Tag: "v5.0.0", PinnedSHA: "1111", Reason: "tag no longer exists"},
+		},
+	})
+
+	expectedPhrases := []string{
+		"## ⚠️ Pinned SHA Drift",
+		"| `actions/cache@2222` | v4.0.0 | `1111` | tag points to a different commit |",
+		"| `actions/setup-go@1111` | v5.0.0 | - | tag no longer exists |",
+		"Found 2 SHA pins that do not match their tags.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+}
+
+func TestFormatActionsUpdates(t *testing.T) {
+	if result := FormatActionsUpdates(nil); !strings.Contains(result, "All repositories have Dependabot or Renovate") {
+		t.Errorf("Expected success message for full coverage, got %q", result)
+	}
+
+	result := FormatActionsUpdates(map[string]updates.Coverage{
+		"org/repo2": {Tool: updates.ToolDependabot, ConfigPath: ".github/dependabot.yml"},
+		"org/repo1": {},
+	})
+
+	expectedPhrases := []string{
+		"## 🔄 Actions Updates",
+		"| org/repo1 | _none_ |",
+		"| org/repo2 | `.github/dependabot.yml` does not update `github-actions` |",
+		"Found 2 repositories without automated action updates.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatActionHealth(t *testing.T) {
+	if result := FormatActionHealth(nil); !strings.Contains(result, "maintained repositories") {
+		t.Errorf("Expected success message without findings, got %q", result)
+	}
+
+	result := FormatActionHealth(map[string][]metadata.Finding{
+		"org/repo2": {{Action: "quiet/action@v2", Reason: metadata.ReasonStale, Metadata: github.RepositoryMetadata{PushedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), Stars: 3}}},
+		"org/repo1": {
+			{Action: "old/action@v1", Reason: metadata.ReasonArchived, Metadata: github.RepositoryMetadata{Archived: true, OpenAdvisories: 2}},
+			{Action: "act1ons/checkout@v4", Reason: metadata.ReasonFork, Metadata: github.RepositoryMetadata{Fork: true, Parent: "actions/checkout"}},
+		},
+	})
+
+	expectedPhrases := []string{
+		"## 🩺 Action Health",
+		"| `old/action@v1` | archived | - | 0 | 2 |",
+		"| `quiet/action@v2` | stale | 2023-01-02 | 3 | 0 |",
+		"| `act1ons/checkout@v4` | fork of `actions/checkout` | - | 0 | 0 |",
+		"Found 3 actions from archived, stale, forked or new repositories.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatMissingActions(t *testing.T) {
+	if result := FormatMissingActions(nil); !strings.Contains(result, "All action references resolve") {
+		t.Errorf("Expected success message without findings, got %q", result)
+	}
+
+	result := FormatMissingActions(map[string][]deadlinks.Finding{
+		"org/repo2": {
+			{Action: "actions/checkout@v0", Reason: deadlinks.ReasonMissingRef},
+			{Action: "gone/action@v1", Reason: deadlinks.ReasonOwnerDeleted},
+			{Action: "renamed-org/action@v2", Reason: deadlinks.ReasonOwnerRenamed, RenamedTo: "current-org/action"},
+		},
+		"org/repo1": {
+			{Action: "deleted/action@v1", Reason: deadlinks.ReasonNotFound},
+			{Action: "old-owner/action@main", Reason: deadlinks.ReasonRenamed, RenamedTo: "new-owner/action"},
+		},
+	})
+
+	expectedPhrases := []string{
+		"## 🔗 Missing Actions",
+		"| `deleted/action@v1` | repository deleted or not accessible |",
+		"| `old-owner/action@main` | repository renamed to `new-owner/action` |",
+		"| `actions/checkout@v0` | ref not found |",
+		"| `gone/action@v1` | ⚠️ owner account deleted, its name can be claimed |",
+		"| `renamed-org/action@v2` | ⚠️ owner account renamed, moved to `current-org/action`, the old name can be claimed |",
+		"Found 5 action references",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatTyposquats(t *testing.T) {
+	if result := FormatTyposquats(nil); !strings.Contains(result, "No actions resemble") {
+		t.Errorf("Expected success message without findings, got %q", result)
+	}
+
+	result := FormatTyposquats(map[string][]policy.Typosquat{
+		"org/repo2": {{Action: "docker/build-psuh-action@v6", Resembles: "docker/build-push-action"}},
+		"org/repo1": {{Action: "actions/chekout@v4", Resembles: "actions/checkout"}},
+	})
+
+	expectedPhrases := []string{
+		"## 🎭 Look-alike Actions",
+		"| `actions/chekout@v4` | `actions/checkout` |",
+		"| `docker/build-psuh-action@v6` | `docker/build-push-action` |",
+		"Found 2 actions that may be typosquatting a popular action.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatMinVersions(t *testing.T) {
+	if result := FormatMinVersions(nil); !strings.Contains(result, "All action references meet their minimum version") {
+		t.Errorf("Expected success message without violations, got %q", result)
+	}
+
+	result := FormatMinVersions(map[string][]policy.VersionFloorViolation{
+		"org/repo2": {{Action: "actions/setup-node@main", Workflow: ".github/workflows/ci.yml", Minimum: "v4"}},
+		"org/repo1": {{Action: "actions/checkout@v3", Workflow: ".github/workflows/build.yml", Line: 9, Version: "v3", Minimum: "v4"}},
+	})
+
+	expectedPhrases := []string{
+		"## ⏳ Minimum Action Versions",
+		"| `actions/checkout@v3` | `.github/workflows/build.yml:9` | v3 | v4 |",
+		"| `actions/setup-node@main` | `.github/workflows/ci.yml` | not a version | v4 |",
+		"Found 2 action references below their minimum version.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatInputViolations(t *testing.T) {
+	if result := FormatInputViolations(nil); !strings.Contains(result, "All steps set their inputs") {
+		t.Errorf("Expected success message without violations, got %q", result)
+	}
+
+	result := FormatInputViolations(map[string][]policy.InputViolation{
+		"org/repo2": {{Action: "step-security/harden-runner@v2", Workflow: ".github/workflows/ci.yml", Input: "egress-policy", Unset: true, Reason: "must be block"}},
+		"org/repo1": {
+			{Action: "actions/checkout@v4", Workflow: ".github/workflows/build.yml", Line: 8, Input: "persist-credentials", Value: "true", Unset: true, Reason: "must not be true"},
+			{Action: "actions/checkout@v4", Workflow: ".github/workflows/build.yml", Line: 12, Input: "persist-credentials", Value: "True", Reason: "must not be True"},
+		},
+	})
+
+	expectedPhrases := []string{
+		"## 🎛️ Action Inputs",
+		"| `actions/checkout@v4` | `.github/workflows/build.yml:8` | `persist-credentials` | not set, defaults to `true` | must not be true |",
+		"| `actions/checkout@v4` | `.github/workflows/build.yml:12` | `persist-credentials` | `True` | must not be True |",
+		"| `step-security/harden-runner@v2` | `.github/workflows/ci.yml` | `egress-policy` | not set | must be block |",
+		"Found 3 steps breaking input rules.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatHygiene(t *testing.T) {
+	if result := FormatHygiene(nil); !strings.Contains(result, "No ignored security check failures") {
+		t.Errorf("Expected success message without findings, got %q", result)
+	}
+
+	result := FormatHygiene(map[string][]hygiene.Finding{
+		"org/repo2": {{Rule: hygiene.RuleMissingTimeout, Workflow: ".github/workflows/ci.yml", Job: "build", Detail: "sets no timeout-minutes"}},
+		"org/repo1": {{Rule: hygiene.RuleContinueOnError, Workflow: ".github/workflows/scan.yml", Job: "scan", Action: "github/codeql-action/analyze@v3", Line: 14, Detail: "ignores the failure"}},
+	})
+
+	expectedPhrases := []string{
+		"## 🧹 Workflow Hygiene",
+		"| Ignored failure | `.github/workflows/scan.yml:14` | `scan` | `github/codeql-action/analyze@v3` | ignores the failure |",
+		"| No timeout | `.github/workflows/ci.yml` | `build` |  | sets no timeout-minutes |",
+		"Found 2 workflow hygiene issues.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatRequiredActions(t *testing.T) {
+	if result := FormatRequiredActions(nil); !strings.Contains(result, "All repositories use the required actions") {
+		t.Errorf("Expected success message without missing actions, got %q", result)
+	}
+
+	result := FormatRequiredActions(map[string][]string{
+		"org/repo2": {"org/license-check"},
+		"org/repo1": {"org/security-scan@v2", "org/license-check"},
+	})
+
+	expectedPhrases := []string{
+		"## 📌 Required Actions",
+		"- `org/security-scan@v2` is not used by any workflow",
+		"- `org/license-check` is not used by any workflow",
+		"Found 3 required actions missing from 2 repositories.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatRequiredWorkflows(t *testing.T) {
+	if result := FormatRequiredWorkflows(nil); !strings.Contains(result, "All repositories have the required workflows") {
+		t.Errorf("Expected success message without missing workflows, got %q", result)
+	}
+
+	result := FormatRequiredWorkflows(map[string][]policy.RequiredWorkflow{
+		"org/repo2": {{On: "pull_request"}},
+		"org/repo1": {{Path: "codeql.yml"}, {Path: "dependency-review.yml"}},
+	})
+
+	expectedPhrases := []string{
+		"## 🧪 Required Workflows",
+		"- No workflow file matches `codeql.yml`",
+		"- No workflow file matches `dependency-review.yml`",
+		"- No workflow runs on `pull_request` for the default branch",
+		"Found 3 required workflows missing from 2 repositories.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatSuppressions(t *testing.T) {
+	if result := FormatSuppressions(nil); !strings.Contains(result, "No findings are suppressed") {
+		t.Errorf("Expected empty message without suppressions, got %q", result)
+	}
+
+	result := FormatSuppressions(map[string][]suppress.Suppression{
+		"org/repo2": {{Action: "old/tool@v1", Workflow: ".github/workflows/ci.yml", Line: 7, Pattern: "old/tool", Reason: "replaced next sprint", Expires: "2025-01-31", Expired: true}},
+		"org/repo1": {{Action: "legacy/tool@v1", Workflow: ".github/workflows/build.yml", Line: 4, Pattern: "legacy/*", Reason: "migrating to v2"}},
+	})
+
+	expectedPhrases := []string{
+		"## 🔕 Suppressed Findings",
+		"| `legacy/tool@v1` | `.github/workflows/build.yml:4` | migrating to v2 | never |",
+		"| `old/tool@v1` | `.github/workflows/ci.yml:7` | replaced next sprint | 2025-01-31 ⚠️ expired, not applied |",
+		"Suppressed 1 action references; 1 annotations have expired.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatPreexistingViolations(t *testing.T) {
+	if result := FormatPreexistingViolations(nil); !strings.Contains(result, "used no violating actions") {
+		t.Errorf("Expected empty message without pre-existing violations, got %q", result)
+	}
+
+	result := FormatPreexistingViolations(map[string][]string{
+		"org/repo2": {"old/tool@v1"},
+		"org/repo1": {"legacy/tool@v1", "other/tool@main"},
+	})
+	for _, phrase := range []string{"## ⏪ Pre-existing Violations", "not counted against it", "### org/repo1\n\n- `legacy/tool@v1`\n- `other/tool@main`\n", "- `old/tool@v1`"} {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, got %q", phrase, result)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatExplanations(t *testing.T) {
+	if result := FormatExplanations(nil); !strings.Contains(result, "No actions were evaluated") {
+		t.Errorf("Expected empty message without explanations, got %q", result)
+	}
+
+	config := &policy.PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+	result := FormatExplanations(map[string][]policy.Explanation{
+		"org/repo2": {policy.Explain(config, "org/repo2", "someone/action@v1")},
+		"org/repo1": {policy.Explain(config, "org/repo1", "actions/checkout@v4")},
+	})
+
+	expectedPhrases := []string{
+		"## 🔍 Policy Decisions",
+		"Layers: global, mode: allow",
+		"| `actions/checkout@v4` | ✅ allowed | allowed_actions | `actions/checkout` | global |",
+		"| `someone/action@v1` | ❌ denied | allowed_actions | - | global |",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+
+	single := FormatExplanation(policy.Explain(config, "org/repo1", "actions/checkout@v4"))
+	if !strings.Contains(single, "actions/checkout@v4 in org/repo1: ✅ allowed") || !strings.Contains(single, "Matched:  actions/checkout") {
+		t.Errorf("Unexpected single explanation %q", single)
+	}
+}
+
+func TestFormatMergeConflicts(t *testing.T) {
+	if result := FormatMergeConflicts(nil); !strings.Contains(result, "No repository policy file conflicts") {
+		t.Errorf("Expected success message without conflicts, got %q", result)
+	}
+
+	result := FormatMergeConflicts(map[string][]policy.MergeConflict{
+		"org/repo2": {{Kind: policy.ConflictLoosensMode, Rule: "policy_mode", Entry: "deny", Resolution: policy.ResolutionGlobal}},
+		"org/repo1": {{Kind: policy.ConflictDeniedByGlobal, Rule: policy.RuleAllowedActions, Entry: "bad/action", Resolution: policy.ResolutionRepo}},
+	})
+
+	expectedPhrases := []string{
+		"## ⚖️ Policy Merge Conflicts",
+		"| denied_by_global | allowed_actions | `bad/action` | repository rule applied |",
+		"| loosens_mode | policy_mode | `deny` | repository rule rejected |",
+		"Found 2 conflicts between repository policy files and the global policy.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatRepoPolicyIssues(t *testing.T) {
+	if result := FormatRepoPolicyIssues(nil); !strings.Contains(result, "Every repository has a valid policy file") {
+		t.Errorf("Expected success message without issues, got %q", result)
+	}
+
+	result := FormatRepoPolicyIssues(map[string]string{
+		"org/repo2": "no .github/action-control-policy.yaml",
+		"org/repo1": ".github/action-control-policy.yaml could not be parsed",
+	})
+
+	expectedPhrases := []string{
+		"## 📄 Repository Policy Files",
+		"| org/repo1 | .github/action-control-policy.yaml could not be parsed |",
+		"| org/repo2 | no .github/action-control-policy.yaml |",
+		"Found 2 repositories without the policy file the global policy requires.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatOrgSettingsDrift(t *testing.T) {
+	inSync := FormatOrgSettingsDrift("org", orgsettings.Drift{
+		Current: github.OrgActionsSettings{AllowedActions: "selected"},
+		Target:  github.OrgActionsSettings{AllowedActions: "selected"},
+	})
+	if !strings.Contains(inSync, "settings match the policy") || strings.Contains(inSync, "Planned Settings") {
+		t.Errorf("Expected in-sync message without planned settings, got %q", inSync)
+	}
+
+	result := FormatOrgSettingsDrift("org", orgsettings.Drift{
+		Current:        github.OrgActionsSettings{AllowedActions: "selected", GithubOwnedAllowed: true, PatternsAllowed: []string{"hashicorp/*"}},
+		Target:         github.OrgActionsSettings{AllowedActions: "selected", PatternsAllowed: []string{"actions/checkout@*", "docker/*"}},
+		OnlyInSettings: []string{"hashicorp/*"},
+		OnlyInPolicy:   []string{"docker/*"},
+		Notes:          []string{"2 custom rules apply to individual repositories."},
+	})
+
+	expectedPhrases := []string{
+		"# Organization Actions Settings: org",
+		"## Allowed in GitHub Settings but Not in Policy\n\n- `hashicorp/*`",
+		"## Allowed in Policy but Not in GitHub Settings\n\n- `docker/*`",
+		"| GitHub-owned actions | true | false |",
+		"| Patterns | 1 | 2 |",
+		"- 2 custom rules apply to individual repositories.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+}
+
+func TestFormatWorkflowProtection(t *testing.T) {
+	if result := FormatWorkflowProtection(nil); !strings.Contains(result, "All repositories require review") {
+		t.Errorf("Expected success message without unprotected repositories, got %q", result)
+	}
+
+	result := FormatWorkflowProtection(map[string]github.WorkflowProtection{
+		"org/repo2": {Branch: "master"},
+		"org/repo1": {Branch: "main"},
+	})
+
+	expectedPhrases := []string{
+		"## 🔒 Workflow Protection",
+		"| org/repo1 | `main` |",
+		"| org/repo2 | `master` |",
+		"Found 2 repositories whose workflow files can be changed without review.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatWorkflowOwners(t *testing.T) {
+	required := []string{"@org/security", "@org/platform"}
+	if result := FormatWorkflowOwners(nil, required); !strings.Contains(result, "owned by @org/security or @org/platform") {
+		t.Errorf("Expected success message without unowned workflows, got %q", result)
+	}
+
+	result := FormatWorkflowOwners(map[string][]string{
+		"org/repo2": {".github/workflows/release.yml"},
+		"org/repo1": {".github/workflows/ci.yml", ".github/workflows/lab.yml"},
+	}, required)
+
+	expectedPhrases := []string{
+		"## 👥 Workflow Owners",
+		"### org/repo1\n\n- `.github/workflows/ci.yml`\n- `.github/workflows/lab.yml`",
+		"### org/repo2\n\n- `.github/workflows/release.yml`",
+		"Found 3 workflow files whose changes do not request review from @org/security or @org/platform.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+}
+
+func TestFormatImageViolations(t *testing.T) {
+	if result := FormatImageViolations(nil); !strings.Contains(result, "use allowed images") {
+		t.Errorf("Expected success message for no violations, got %q", result)
+	}
+
+	result := FormatImageViolations(map[string][]github.Image{
+		"org/repo1": {
+			{Image: "redis:7", Workflow: ".github/workflows/ci.yml", Job: "test", Service: "cache"},
+			{Image: "node:14", Workflow: ".github/workflows/ci.yml", Job: "build"},
+		},
+	})
+
+	expectedPhrases := []string{
+		"## 🐳 Container Images",
+		"### org/repo1",
+		"| `redis:7` | `.github/workflows/ci.yml` | test | cache |",
+		"| `node:14` | `.github/workflows/ci.yml` | build | - |",
+		"Found 2 container images not allowed by policy.",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected report to contain %q, but it doesn't", phrase)
+		}
+	}
+}
+
+func TestFormatAnnotations(t *testing.T) {
+	result := FormatAnnotations([]Violation{
+		{Repository: "org/repo", Workflow: ".github/workflows/ci.yml", Line: 12, Action: "evil/action@v1", Rule: "denied_actions", Entry: "evil/action", Source: "global"},
+		{Repository: "org/repo", Workflow: ".github/workflows/a,b.yml", Action: "other/action@v1", Rule: "allowed_actions", Source: "custom"},
+	}, map[string][]github.Image{
+		"org/repo": {{Image: "redis:7", Workflow: ".github/workflows/ci.yml", Job: "test", Service: "cache"}},
+	})
+
+	expected := []string{
+		"::error file=.github/workflows/ci.yml,line=12,title=Action policy violation::evil/action@v1 violates denied_actions entry evil/action in org/repo (global policy)",
+		"::error file=.github/workflows/a%2Cb.yml,title=Action policy violation::other/action@v1 violates allowed_actions in org/repo (custom policy)",
+		"::error file=.github/workflows/ci.yml,title=Container image policy violation::Container image redis:7 of service cache in job test is not allowed by policy",
+	}
+	lines := strings.Split(strings.TrimSpace(result), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d annotations, got %d:\n%s", len(expected), len(lines), result)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Annotation %d:\nexpected %s\ngot      %s", i, expected[i], lines[i])
+		}
+	}
+
+	if escaped := escapeData("50% done\nnext"); escaped != "50%25 done%0Anext" {
+		t.Errorf("Unexpected escaped message: %s", escaped)
+	}
+}
+
+func TestFormatTemplate(t *testing.T) {
+	report := EnforceReport{
+		PolicyMode: "allow",
+		Repositories: map[string]RepositoryResult{
+			"org/repo2": {Compliant: true},
+			"org/repo1": {Compliant: false, Violations: []string{"bad/action@v1"}},
+		},
+		Violations: []Violation{
+			{Repository: "org/repo1", Workflow: ".github/workflows/ci.yml", Line: 7, Action: `bad/action@v1, "beta"`, Rule: "allowed_actions"},
+		},
+	}
+
+	text := `repository,compliant
+{{- range $repo := keys .Repositories}}
+{{$repo}},{{(index $.Repositories $repo).Compliant}}
+{{- end}}
+{{range .Violations}}{{.Workflow | trimPrefix ".github/workflows/"}}:{{.Line}} {{csv .Action}} {{upper .Rule}}{{end}}
+{{default "none" .Usage | len}} {{join "+" (list "a" 1)}}`
+
+	result, err := FormatTemplate("report.tmpl", text, report)
+	if err != nil {
+		t.Fatalf("FormatTemplate() error = %v", err)
+	}
+
+	expected := "repository,compliant\norg/repo1,false\norg/repo2,true\n" +
+		`ci.yml:7 "bad/action@v1, ""beta""" ALLOWED_ACTIONS` + "\n4 a+1"
+	if result != expected {
+		t.Errorf("FormatTemplate() = %q, want %q", result, expected)
+	}
+
+	if _, err := FormatTemplate("broken.tmpl", "{{.Missing}}", report); err == nil {
+		t.Error("Expected an error for a field the data does not have")
+	}
+	if _, err := FormatTemplate("broken.tmpl", "{{range}}", report); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func TestFormatScanEstimate(t *testing.T) {
+	estimate := github.ScanEstimate{
+		Repositories:     40,
+		Sampled:          5,
+		WorkflowsPerRepo: 2.4,
+		RequestsPerRepo:  3.4,
+		Requests:         137,
+		EstimateRequests: 6,
+		Limit:            5000,
+		Remaining:        100,
+		RateLimitKnown:   true,
+	}
+
+	result := FormatScanEstimate(estimate)
+	expectedPhrases := []string{
+		"Scan Estimate",
+		"| Repositories | 40 |",
+		"2.4 (sampled 5)",
+		"| Predicted requests | 137 |",
+		"100 of 5000",
+		"needs 37 more requests",
+		"used 6 requests",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected %q in output", phrase)
+		}
+	}
+
+	estimate.Remaining = 1000
+	if result := FormatScanEstimate(estimate); !strings.Contains(result, "leaving 863 requests") {
+		t.Error("Expected a sufficient quota message")
+	}
+
+	estimate.RateLimitKnown = false
+	if result := FormatScanEstimate(estimate); !strings.Contains(result, "does not report a rate limit") {
+		t.Error("Expected an unknown rate limit message")
+	}
+}
+
+func TestFormatScanInterrupted(t *testing.T) {
+	result := FormatScanInterrupted("scan interrupted after 3 of 20 repositories: context deadline exceeded")
+	if !strings.Contains(result, "Partial results") || !strings.Contains(result, "3 of 20 repositories") {
+		t.Errorf("Expected the interruption in the warning, got %q", result)
+	}
+}
+
+func TestFormatActionRuntimes(t *testing.T) {
+	if result := FormatActionRuntimes(nil); !strings.Contains(result, "All actions run on allowed runtimes") {
+		t.Errorf("Expected empty message, got %q", result)
+	}
+
+	findings := map[string][]runtimes.Finding{
+		"org/repo2": {{Action: "actions/checkout@v3", Reason: runtimes.ReasonDeprecatedNode, Runtime: github.ActionRuntime{Kind: github.ActionKindJavaScript, Using: "node16"}}},
+		"org/repo1": {{Action: "org/built@v1", Reason: runtimes.ReasonUnknownRegistry, Image: "alpine:3", Runtime: github.ActionRuntime{Kind: github.ActionKindDocker, Using: "docker", Image: "Dockerfile"}}},
+	}
+	result := FormatActionRuntimes(findings)
+
+	expectedPhrases := []string{
+		"Action Runtimes",
+		"deprecated runtime `node16`",
+		"image `alpine:3` from an unknown registry",
+		"Found 2 actions",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected %q in output", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatRuntimeReport(t *testing.T) {
+	report := runtimes.Report{
+		Actions: []github.ActionRuntime{
+			{Action: "actions/checkout@v4", Kind: github.ActionKindJavaScript, Using: "node20"},
+			{Action: "org/built@v1", Kind: github.ActionKindDocker, Using: "docker", Image: "Dockerfile", BaseImages: []string{"golang:1.24", "alpine:3"}},
+		},
+		Counts:     map[string]int{github.ActionKindJavaScript: 1, github.ActionKindDocker: 1},
+		Unresolved: []string{"gone/action@v1"},
+	}
+	result := FormatRuntimeReport(report)
+
+	expectedPhrases := []string{
+		"| docker | 1 |",
+		"| javascript | 1 |",
+		"| `actions/checkout@v4` | `javascript` | `node20` | - |",
+		"`Dockerfile` from `golang:1.24`, `alpine:3`",
+		"action.yml of 1 actions: `gone/action@v1`",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected %q in output", phrase)
+		}
+	}
+
+	if result := FormatRuntimeReport(runtimes.Report{}); !strings.Contains(result, "No third-party actions") {
+		t.Errorf("Expected empty message, got %q", result)
+	}
+}
+
+func TestFormatDeprecations(t *testing.T) {
+	if result := FormatDeprecations(nil, false); !strings.Contains(result, "No workflow depends on retired runners") {
+		t.Errorf("Expected empty message, got %q", result)
+	}
+
+	warnings := map[string][]deprecations.Warning{
+		"org/repo2": {{Kind: deprecations.KindNode, Action: "actions/checkout@v3", Runtime: "node16"}},
+		"org/repo1": {{Kind: deprecations.KindRunner, Workflow: ".github/workflows/ci.yml", Job: "build", Label: "ubuntu-20.04", Replacement: "ubuntu-24.04"}},
+	}
+	result := FormatDeprecations(warnings, false)
+
+	expectedPhrases := []string{
+		"Deprecations",
+		"| `ubuntu-20.04` runner | `.github/workflows/ci.yml` job `build` | `ubuntu-24.04` |",
+		"| `node16` runtime | `actions/checkout@v3` |",
+		"Found 2 deprecation warnings",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected %q in output", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+	if result := FormatDeprecations(warnings, true); !strings.Contains(result, "Found 2 deprecation failures") {
+		t.Error("Expected escalated deprecations to be failures")
+	}
+
+	annotations := FormatDeprecationAnnotations(warnings, false)
+	expectedAnnotations := []string{
+		"::warning file=.github/workflows/ci.yml,title=Retired runner::Job build runs on the retired runner ubuntu-20.04, use ubuntu-24.04",
+		"::warning title=Deprecated Node.js runtime::actions/checkout@v3 in org/repo2 runs on the deprecated node16 runtime",
+	}
+	for _, annotation := range expectedAnnotations {
+		if !strings.Contains(annotations, annotation) {
+			t.Errorf("Expected %q in annotations, got %q", annotation, annotations)
+		}
+	}
+	if annotations := FormatDeprecationAnnotations(warnings, true); strings.Contains(annotations, "::warning") {
+		t.Error("Expected error annotations for escalated deprecations")
+	}
+}
+
+func TestFormatUnresolvedReferences(t *testing.T) {
+	if result := FormatUnresolvedReferences(nil); !strings.Contains(result, "All action references could be resolved") {
+		t.Errorf("Expected empty message, got %q", result)
+	}
+
+	references := map[string][]github.UnresolvedReference{
+		"org/repo2": {{Uses: "${{ steps.pick.outputs.action }}", Workflow: ".github/workflows/deploy.yml", Job: "deploy", Reason: "expression \"steps.pick.outputs.action\" cannot be evaluated statically"}},
+		"org/repo1": {{Uses: "org/${{ matrix.tool }}@v1", Workflow: ".github/workflows/ci.yml", Job: "build", Line: 12, Reason: "the job's matrix is not static"}},
+	}
+	result := FormatUnresolvedReferences(references)
+
+	expectedPhrases := []string{
+		"Unresolvable Action References",
+		"| `org/${{ matrix.tool }}@v1` | `.github/workflows/ci.yml:12` | `build` | the job's matrix is not static |",
+		"| `${{ steps.pick.outputs.action }}` | `.github/workflows/deploy.yml` | `deploy` |",
+		"Found 2 unresolvable action references",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected %q in output", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatCallGraph(t *testing.T) {
+	if result := FormatCallGraph(callgraph.Build(nil)); !strings.Contains(result, "No jobs call reusable workflows") {
+		t.Errorf("Expected empty message, got %q", result)
+	}
+
+	graph := callgraph.Build(map[string][]github.Action{
+		"org/app": {
+			{Uses: "org/workflows/.github/workflows/go.yml@v1", Workflow: ".github/workflows/ci.yml", Job: "build", With: map[string]string{"version": "1.24"}},
+			{Uses: "./.github/workflows/deploy.yml", Workflow: ".github/workflows/ci.yml", Job: "deploy"},
+		},
+		"org/workflows": {
+			{Uses: "org/workflows/.github/workflows/setup.yml@main", Workflow: ".github/workflows/go.yml", Job: "setup"},
+		},
+	})
+	result := FormatCallGraph(graph)
+
+	expectedPhrases := []string{
+		"```mermaid\nflowchart LR\n",
+		`w0["org/app/.github/workflows/ci.yml"] -->|"build @v1"| w1["org/workflows/.github/workflows/go.yml"]`,
+		`w0 -->|"deploy"| w2["org/app/.github/workflows/deploy.yml"]`,
+		`w1 -->|"setup @main"| w3["org/workflows/.github/workflows/setup.yml"]`,
+		"| `org/workflows/.github/workflows/go.yml` | 1 | org/app | `v1` |",
+		"| `org/app/.github/workflows/ci.yml` | `build` | `org/workflows/.github/workflows/go.yml@v1` | `version` |",
+		"3 calls to 3 reusable workflows.",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected %q in output:\n%s", phrase, result)
+		}
+	}
+}
+
+func TestFormatReusableWorkflowViolations(t *testing.T) {
+	if result := FormatReusableWorkflowViolations(nil); !strings.Contains(result, "All jobs call allowed reusable workflows") {
+		t.Errorf("Expected empty message, got %q", result)
+	}
+
+	calls := map[string][]github.WorkflowCall{
+		"org/repo2": {{Uses: "./.github/workflows/deploy.yml", Workflow: ".github/workflows/ci.yml", Job: "deploy"}},
+		"org/repo1": {{Uses: "other/workflows/.github/workflows/build.yml@main", Workflow: ".github/workflows/ci.yml", Job: "build"}},
+	}
+	result := FormatReusableWorkflowViolations(calls)
+
+	expectedPhrases := []string{
+		"Reusable Workflows",
+		"| `other/workflows/.github/workflows/build.yml@main` | `.github/workflows/ci.yml` | build |",
+		"| `./.github/workflows/deploy.yml` | `.github/workflows/ci.yml` | deploy |",
+		"Found 2 calls to reusable workflows not allowed by policy",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected %q in output", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatArtifactMisuse(t *testing.T) {
+	if result := FormatArtifactMisuse(nil); !strings.Contains(result, "No risky use of caches or artifacts") {
+		t.Errorf("Expected empty message, got %q", result)
+	}
+
+	findings := map[string][]artifacts.Finding{
+		"org/repo2": {{Rule: artifacts.RuleCredentialsInArtifact, Workflow: ".github/workflows/build.yml", Job: "build", Action: "actions/upload-artifact@v4", Detail: "uploads the workspace"}},
+		"org/repo1": {{Rule: artifacts.RuleCachePoisoning, Workflow: ".github/workflows/pr.yml", Job: "test", Action: "actions/cache@v4", Line: 9, Trigger: "pull_request_target", Detail: "saves a cache"}},
+	}
+	result := FormatArtifactMisuse(findings)
+
+	expectedPhrases := []string{
+		"Caches and Artifacts",
+		"| Cache poisoning | `actions/cache@v4` | `.github/workflows/pr.yml:9` | `test` | saves a cache |",
+		"| Credentials in artifact | `actions/upload-artifact@v4` | `.github/workflows/build.yml` | `build` | uploads the workspace |",
+		"Found 2 risky uses of caches or artifacts",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected %q in output", phrase)
+		}
+	}
+	if strings.Index(result, "org/repo1") > strings.Index(result, "org/repo2") {
+		t.Error("Expected repositories to be sorted")
+	}
+}
+
+func TestFormatSummaryOnly(t *testing.T) {
+	report := EnforceReport{Repositories: map[string]RepositoryResult{
+		"org/b": {Compliant: false, Owners: []string{"@org/platform"}, Violations: []string{"evil/action@v1"}},
+		"org/a": {Compliant: true},
+		"org/c": {
+			Compliant:          false,
+			ImageViolations:    []github.Image{{Image: "redis:7"}},
+			CloudAccess:        []cloud.Access{{Allowed: true}, {Allowed: false}},
+			WorkflowProtection: &github.WorkflowProtection{Protected: false},
+		},
+	}}
+
+	expected := "org/a: compliant\norg/b (@org/platform): not compliant, 1 finding\norg/c: not compliant, 3 findings\n"
+	if result := FormatSummaryOnly(report); result != expected {
+		t.Errorf("FormatSummaryOnly() = %q, want %q", result, expected)
+	}
+}
+
+func TestCountSeverities(t *testing.T) {
+	result := RepositoryResult{
+		Violations:     []string{"evil/action@v1"},
+		Deprecations:   []deprecations.Warning{{Kind: deprecations.KindRunner, Label: "ubuntu-20.04"}},
+		MergeConflicts: []policy.MergeConflict{{Kind: "drops_denied", Rule: "denied_actions"}},
+		Suppressed:     []suppress.Suppression{{Action: "other/action@v1"}, {Action: "old/action@v1", Expired: true}},
+		Preexisting:    []string{"kept/action@v1"},
+	}
+
+	if counts := result.CountSeverities(false); counts != (SeverityCounts{Errors: 1, Warnings: 1, Info: 3}) {
+		t.Errorf("Expected 1 error, 1 warning and 3 info, got %+v", counts)
+	}
+	if counts := result.CountSeverities(true); counts != (SeverityCounts{Errors: 2, Warnings: 0, Info: 3}) {
+		t.Errorf("Expected escalated deprecations to count as errors, got %+v", counts)
+	}
+}
+
+func TestComplies(t *testing.T) {
+	tests := []struct {
+		name   string
+		result RepositoryResult
+		want   bool
+	}{
+		{"no findings", RepositoryResult{}, true},
+		{"violations", RepositoryResult{Violations: []string{"evil/action@v1"}}, false},
+		{"lint findings", RepositoryResult{LintFindings: []lint.Finding{{Kind: "expression"}}}, false},
+		{"denied cloud access", RepositoryResult{CloudAccess: []cloud.Access{{Provider: "aws"}}}, false},
+		{"allowed cloud access", RepositoryResult{CloudAccess: []cloud.Access{{Provider: "aws", Allowed: true}}}, true},
+		{"pin drift", RepositoryResult{PinDrift: []pinning.Drift{{Uses: "actions/cache@2222"}}}, false},
+		{"no actions updates", RepositoryResult{ActionsUpdates: &updates.Coverage{}}, false},
+		{"actions updates", RepositoryResult{ActionsUpdates: &updates.Coverage{Covered: true}}, true},
+		{"image violations", RepositoryResult{ImageViolations: []github.Image{{Image: "redis:7"}}}, false},
+		{"reusable workflow calls", RepositoryResult{WorkflowCalls: []github.WorkflowCall{{Uses: "./.github/workflows/deploy.yml"}}}, false},
+		{"unprotected workflows", RepositoryResult{WorkflowProtection: &github.WorkflowProtection{}}, false},
+		{"protected workflows", RepositoryResult{WorkflowProtection: &github.WorkflowProtection{Protected: true}}, true},
+		{"unowned workflows", RepositoryResult{UnownedWorkflows: []string{".github/workflows/ci.yml"}}, false},
+		{"action health", RepositoryResult{ActionHealth: []metadata.Finding{{Action: "old/action@v1"}}}, false},
+		{"missing actions", RepositoryResult{MissingActions: []deadlinks.Finding{{Action: "gone/action@v1"}}}, false},
+		{"action runtimes", RepositoryResult{ActionRuntimes: []runtimes.Finding{{Action: "actions/checkout@v3"}}}, false},
+		{"unresolved references", RepositoryResult{UnresolvedRefs: []github.UnresolvedReference{{Uses: "${{ matrix.action }}"}}}, false},
+		{"typosquats", RepositoryResult{Typosquats: []policy.Typosquat{{Action: "actions/chekout@v4"}}}, false},
+		{"artifact misuse", RepositoryResult{ArtifactMisuse: []artifacts.Finding{{Action: "actions/cache@v4"}}}, false},
+		{"hygiene", RepositoryResult{Hygiene: []hygiene.Finding{{Rule: hygiene.RuleMissingTimeout}}}, false},
+		{"below minimum version", RepositoryResult{BelowMinVersion: []policy.VersionFloorViolation{{Action: "actions/checkout@v3"}}}, false},
+		{"input violations", RepositoryResult{InputViolations: []policy.InputViolation{{Action: "actions/checkout@v4"}}}, false},
+		{"missing required actions", RepositoryResult{MissingRequired: []string{"org/security-scan"}}, false},
+		{"missing required workflows", RepositoryResult{MissingWorkflows: []policy.RequiredWorkflow{{Path: "codeql.yml"}}}, false},
+		{"repository policy issue", RepositoryResult{RepoPolicyIssue: "no .github/action-control-policy.yaml"}, false},
+		{"only deprecations", RepositoryResult{Deprecations: []deprecations.Warning{{Kind: deprecations.KindRunner, Label: "ubuntu-20.04"}}}, true},
+		{"only informational details", RepositoryResult{
+			MergeConflicts: []policy.MergeConflict{{Kind: "drops_denied"}},
+			Suppressed:     []suppress.Suppression{{Action: "other/action@v1"}},
+			Preexisting:    []string{"kept/action@v1"},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Complies(false); got != tt.want {
+				t.Errorf("Complies(false) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	deprecated := RepositoryResult{Deprecations: []deprecations.Warning{{Kind: deprecations.KindRunner, Label: "ubuntu-20.04"}}}
+	if deprecated.Complies(true) {
+		t.Error("Expected failing deprecations to make the repository non-compliant")
+	}
+}
+
+func TestFormatSeverityMatrix(t *testing.T) {
+	empty := FormatSeverityMatrix(EnforceReport{Repositories: map[string]RepositoryResult{"org/a": {Compliant: true}}})
+	if !strings.Contains(empty, "No findings.") {
+		t.Errorf("Expected a message without findings, got: %s", empty)
+	}
+
+	report := EnforceReport{Repositories: map[string]RepositoryResult{
+		"org/a": {Compliant: true},
+		"org/b": {Severities: SeverityCounts{Errors: 1, Info: 1}},
+		"org/c": {Severities: SeverityCounts{Errors: 3, Warnings: 2}},
+		"org/d": {Compliant: true, Severities: SeverityCounts{Warnings: 1}},
+	}}
+	result := FormatSeverityMatrix(report)
+
+	expectedPhrases := []string{
+		"## Findings by Severity",
+		"| Repository | 🔴 Error | 🟡 Warning | 🔵 Info |",
+		"| org/c | 3 | 2 | 0 |\n| org/b | 1 | 0 | 1 |\n| org/d | 0 | 1 | 0 |\n",
+		"| **Total** | **4** | **3** | **1** |",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected matrix to contain %q, but it doesn't:\n%s", phrase, result)
+		}
+	}
+	if strings.Contains(result, "org/a") {
+		t.Error("Expected repositories without findings to be left out")
+	}
+}
+
+func TestFormatStats(t *testing.T) {
+	empty := FormatStats(&stats.Stats{Repositories: 3})
+	if !strings.Contains(empty, "No third-party actions are used.") {
+		t.Errorf("Expected a message for statistics without owners, got: %s", empty)
+	}
+
+	result := FormatStats(&stats.Stats{
+		Repositories:              10,
+		RepositoriesWithWorkflows: 4,
+		WorkflowsPercent:          40,
+		ActionReferences:          25,
+		UniqueActions:             7,
+		PinnedPercent:             12.5,
+		TopOwners: []stats.OwnerUsage{
+			{Owner: "actions", References: 15, Repositories: 4},
+			{Owner: stats.OwnOrganization, References: 3, Repositories: 2},
+		},
+	})
+
+	expectedPhrases := []string{
+		"# GitHub Actions Statistics",
+		"| Repositories with workflows | 4 (40.0%) |",
+		"| Unique actions | 7 |",
+		"| Pinned to a SHA | 12.5% |",
+		"| actions | 15 | 4 |",
+		"| (own organization) | 3 | 2 |",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected output to contain %q", phrase)
+		}
+	}
+}
+
+func TestFormatRuns(t *testing.T) {
+	if result := FormatRuns(nil); result != "No recorded runs.\n" {
+		t.Errorf("Unexpected output without runs: %q", result)
+	}
+
+	run := store.Run{
+		ID:           "20260501T090000Z-abcd1234",
+		Target:       "org",
+		StartedAt:    time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+		ToolVersion:  "v1.2.3",
+		Total:        2,
+		NonCompliant: 1,
+		Repositories: []store.RepositoryRun{
+			{Repository: "org/a", Compliant: true},
+			{Repository: "org/b", Errors: 1, Owners: []string{"@org/platform"}, Violations: []string{"evil/action@v1"}},
+		},
+	}
+
+	list := FormatRuns([]store.Run{run})
+	if !strings.Contains(list, "| `20260501T090000Z-abcd1234` | org | 2026-05-01T09:00:00Z | 1 | 2 |") {
+		t.Errorf("Expected a row for the run, got:\n%s", list)
+	}
+
+	details := FormatRun(run)
+	for _, phrase := range []string{
+		"org, started 2026-05-01T09:00:00Z with action-control v1.2.3: 1 of 2 repositories do not comply.",
+		"| org/b | @org/platform | 1 | 0 | 0 | `evil/action@v1` |",
+	} {
+		if !strings.Contains(details, phrase) {
+			t.Errorf("Expected run details to contain %q, got:\n%s", phrase, details)
+		}
+	}
+	if strings.Index(details, "org/b") > strings.Index(details, "| org/a") {
+		t.Error("Expected non-compliant repositories first")
+	}
+}
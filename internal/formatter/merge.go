@@ -0,0 +1,70 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// mergeResolutions describes how each merge conflict resolution affects the repository rule
+var mergeResolutions = map[string]string{
+	policy.ResolutionRepo:    "repository rule applied",
+	policy.ResolutionGlobal:  "repository rule rejected",
+	policy.ResolutionRefused: "loosening ignored",
+}
+
+// FormatMergeConflicts formats the conflicts between repository policy files and the global
+// policy, grouped by repository
+func FormatMergeConflicts(conflicts map[string][]policy.MergeConflict) string {
+	var sb strings.Builder
+	sb.WriteString("## ⚖️ Policy Merge Conflicts\n\n")
+
+	if len(conflicts) == 0 {
+		sb.WriteString("No repository policy file conflicts with the global policy.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(conflicts)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Conflict | Rule | Entry | Resolution |\n")
+		sb.WriteString("|----------|------|-------|------------|\n")
+		for _, conflict := range conflicts[repo] {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				conflict.Kind, conflict.Rule, codeOrDash(conflict.Entry), mergeResolutions[conflict.Resolution]))
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d conflicts between repository policy files and the global policy.\n", count))
+
+	return sb.String()
+}
+
+// FormatRepoPolicyIssues formats the repositories without a usable policy file when the policy
+// requires one
+func FormatRepoPolicyIssues(issues map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString("## 📄 Repository Policy Files\n\n")
+
+	if len(issues) == 0 {
+		sb.WriteString("Every repository has a valid policy file.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(issues)
+
+	sb.WriteString("| Repository | Issue |\n")
+	sb.WriteString("|------------|-------|\n")
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", repo, issues[repo]))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nFound %d repositories without the policy file the global policy requires.\n", len(issues)))
+
+	return sb.String()
+}
@@ -0,0 +1,49 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/metadata"
+)
+
+// FormatActionHealth formats the actions whose repositories fail the policy's upkeep thresholds
+// or supply-chain heuristics
+func FormatActionHealth(findings map[string][]metadata.Finding) string {
+	var sb strings.Builder
+	sb.WriteString("## 🩺 Action Health\n\n")
+
+	if len(findings) == 0 {
+		sb.WriteString("All actions come from maintained repositories.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(findings)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action | Issue | Last Push | Stars | Open Advisories |\n")
+		sb.WriteString("|--------|-------|-----------|-------|-----------------|\n")
+		for _, finding := range findings[repo] {
+			lastPush := "-"
+			if !finding.Metadata.PushedAt.IsZero() {
+				lastPush = finding.Metadata.PushedAt.Format("2006-01-02")
+			}
+			issue := finding.Reason
+			if finding.Reason == metadata.ReasonFork && finding.Metadata.Parent != "" {
+				issue = fmt.Sprintf("fork of `%s`", finding.Metadata.Parent)
+			} else if finding.Reason == metadata.ReasonNew {
+				issue = fmt.Sprintf("created %s", finding.Metadata.CreatedAt.Format("2006-01-02"))
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %d | %d |\n",
+				finding.Action, issue, lastPush, finding.Metadata.Stars, finding.Metadata.OpenAdvisories))
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d actions from archived, stale, forked or new repositories.\n", total))
+
+	return sb.String()
+}
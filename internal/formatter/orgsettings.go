@@ -0,0 +1,50 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/orgsettings"
+)
+
+// FormatOrgSettingsDrift formats the drift between an organization's allowed actions settings
+// and the policy, followed by the settings that would reconcile them
+func FormatOrgSettingsDrift(org string, drift orgsettings.Drift) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Organization Actions Settings: %s\n\n", org))
+
+	if drift.InSync() {
+		sb.WriteString("✅ The organization's allowed actions settings match the policy.\n")
+	} else {
+		writeList := func(title string, patterns []string) {
+			sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+			if len(patterns) == 0 {
+				sb.WriteString("_None_\n\n")
+				return
+			}
+			for _, pattern := range patterns {
+				sb.WriteString(fmt.Sprintf("- `%s`\n", pattern))
+			}
+			sb.WriteString("\n")
+		}
+		writeList("Allowed in GitHub Settings but Not in Policy", drift.OnlyInSettings)
+		writeList("Allowed in Policy but Not in GitHub Settings", drift.OnlyInPolicy)
+
+		sb.WriteString("## Planned Settings\n\n")
+		sb.WriteString("| Setting | Current | Planned |\n")
+		sb.WriteString("|---------|---------|---------|\n")
+		sb.WriteString(fmt.Sprintf("| Allowed actions | %s | %s |\n", drift.Current.AllowedActions, drift.Target.AllowedActions))
+		sb.WriteString(fmt.Sprintf("| GitHub-owned actions | %t | %t |\n", drift.Current.GithubOwnedAllowed, drift.Target.GithubOwnedAllowed))
+		sb.WriteString(fmt.Sprintf("| Verified creators | %t | %t |\n", drift.Current.VerifiedAllowed, drift.Target.VerifiedAllowed))
+		sb.WriteString(fmt.Sprintf("| Patterns | %d | %d |\n", len(drift.Current.PatternsAllowed), len(drift.Target.PatternsAllowed)))
+	}
+
+	if len(drift.Notes) > 0 {
+		sb.WriteString("\n## Notes\n\n")
+		for _, note := range drift.Notes {
+			sb.WriteString(fmt.Sprintf("- %s\n", note))
+		}
+	}
+
+	return sb.String()
+}
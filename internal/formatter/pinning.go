@@ -0,0 +1,128 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/pinning"
+)
+
+// FormatPinning formats the version pinning report as a Markdown document
+func FormatPinning(report *pinning.Report) string {
+	var sb strings.Builder
+	sb.WriteString("# GitHub Actions Pinning Report\n\n")
+
+	if len(report.Repositories) == 0 {
+		sb.WriteString("No action references found.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString("| Pinning | References | Share |\n")
+	sb.WriteString("|---------|------------|-------|\n")
+	for _, kind := range []pinning.Kind{pinning.SHA, pinning.Tag, pinning.Branch, pinning.Unpinned} {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %.1f%% |\n", pinningLabel(kind), countOf(report.Summary, kind), report.Summary.Percent(kind)))
+	}
+	sb.WriteString(fmt.Sprintf("\n%d action references across %d repositories.\n\n", report.Summary.Total(), len(report.Repositories)))
+
+	repos := sortedRepos(report.Repositories)
+
+	sb.WriteString("## Pinning by Repository\n\n")
+	sb.WriteString("| Repository | SHA | Tag | Branch | Unpinned |\n")
+	sb.WriteString("|------------|-----|-----|--------|----------|\n")
+	for _, repo := range repos {
+		counts := report.Repositories[repo].Counts
+		sb.WriteString(fmt.Sprintf("| %s | %.0f%% | %.0f%% | %.0f%% | %.0f%% |\n", repo,
+			counts.Percent(pinning.SHA), counts.Percent(pinning.Tag),
+			counts.Percent(pinning.Branch), counts.Percent(pinning.Unpinned)))
+	}
+	sb.WriteString("\n")
+
+	// List references that are not pinned to an immutable SHA
+	sb.WriteString("## Mutable References\n\n")
+	mutable := 0
+	for _, repo := range repos {
+		var refs []pinning.Reference
+		for _, ref := range report.Repositories[repo].References {
+			if ref.Kind != pinning.SHA {
+				refs = append(refs, ref)
+			}
+		}
+		if len(refs) == 0 {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action Reference | Pinning | Workflow |\n")
+		sb.WriteString("|------------------|---------|----------|\n")
+		for _, ref := range refs {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | `%s` |\n", ref.Uses, pinningLabel(ref.Kind), ref.Workflow))
+			mutable++
+		}
+		sb.WriteString("\n")
+	}
+	if mutable == 0 {
+		sb.WriteString("✅ All action references are pinned to a commit SHA.\n")
+	}
+
+	return sb.String()
+}
+
+func pinningLabel(kind pinning.Kind) string {
+	switch kind {
+	case pinning.SHA:
+		return "SHA"
+	case pinning.Tag:
+		return "Tag"
+	case pinning.Branch:
+		return "Branch"
+	default:
+		return "Unpinned"
+	}
+}
+
+func countOf(counts pinning.Counts, kind pinning.Kind) int {
+	switch kind {
+	case pinning.SHA:
+		return counts.SHA
+	case pinning.Tag:
+		return counts.Tag
+	case pinning.Branch:
+		return counts.Branch
+	default:
+		return counts.Unpinned
+	}
+}
+
+// FormatPinDrift formats SHA pins whose version comment no longer matches the tag, grouped by repository
+func FormatPinDrift(drifts map[string][]pinning.Drift) string {
+	if len(drifts) == 0 {
+		return "✅ All commented SHA pins match their tags."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## ⚠️ Pinned SHA Drift\n\n")
+
+	repos := sortedRepos(drifts)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action Reference | Tag | Tag Commit | Reason |\n")
+		sb.WriteString("|------------------|-----|------------|--------|\n")
+
+		for _, drift := range drifts[repo] {
+			tagSHA := "-"
+			if drift.TagSHA != "" {
+				tagSHA = fmt.Sprintf("`%s`", drift.TagSHA)
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", drift.Uses, drift.Tag, tagSHA, drift.Reason))
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\nFound %d SHA pins that do not match their tags. This may indicate a retagged release, tampering, or a stale pin.\n", total))
+
+	return sb.String()
+}
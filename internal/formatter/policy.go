@@ -2,12 +2,131 @@ package formatter
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+
+	"github.com/ihavespoons/action-control/internal/artifacts"
+	"github.com/ihavespoons/action-control/internal/cloud"
+	"github.com/ihavespoons/action-control/internal/deadlinks"
+	"github.com/ihavespoons/action-control/internal/deprecations"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/hygiene"
+	"github.com/ihavespoons/action-control/internal/lint"
+	"github.com/ihavespoons/action-control/internal/metadata"
+	"github.com/ihavespoons/action-control/internal/pinning"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/runtimes"
+	"github.com/ihavespoons/action-control/internal/suppress"
+	"github.com/ihavespoons/action-control/internal/updates"
 )
 
-// Update the FormatPolicyViolations function to mention the policy mode
-func FormatPolicyViolations(violations map[string][]string, policyMode string) string {
+// EnforceSchemaVersion is the version of the enforce JSON output described by
+// schemas/enforce-report.schema.json. It changes only on incompatible changes.
+const EnforceSchemaVersion = "1"
+
+// EnforceReport is the machine-readable result of an enforce run
+type EnforceReport struct {
+	SchemaVersion string                      `json:"schema_version"`
+	PolicyMode    string                      `json:"policy_mode"`
+	Repositories  map[string]RepositoryResult `json:"repositories"`
+	Violations    []Violation                 `json:"violations"`            // Every violating action reference, sorted by location
+	Usage         map[string][]Action         `json:"usage,omitempty"`       // Action usage by repository, with --with-report
+	Interrupted   string                      `json:"interrupted,omitempty"` // Why the scan stopped early, leaving the results partial
+}
+
+// RepositoryResult is the enforcement outcome for a single repository
+type RepositoryResult struct {
+	Compliant          bool                           `json:"compliant"`
+	Owners             []string                       `json:"owners,omitempty"` // Owning teams and users, from the owners mapping or the teams API
+	Violations         []string                       `json:"violations,omitempty"`
+	LintFindings       []lint.Finding                 `json:"lint_findings,omitempty"`
+	CloudAccess        []cloud.Access                 `json:"cloud_access,omitempty"`
+	PinDrift           []pinning.Drift                `json:"pin_drift,omitempty"`
+	ActionsUpdates     *updates.Coverage              `json:"actions_updates,omitempty"`
+	ImageViolations    []github.Image                 `json:"image_violations,omitempty"`
+	WorkflowCalls      []github.WorkflowCall          `json:"reusable_workflow_violations,omitempty"`
+	WorkflowProtection *github.WorkflowProtection     `json:"workflow_protection,omitempty"`
+	UnownedWorkflows   []string                       `json:"unowned_workflows,omitempty"`
+	ActionHealth       []metadata.Finding             `json:"action_health,omitempty"`
+	MissingActions     []deadlinks.Finding            `json:"missing_actions,omitempty"` // Actions whose repository or ref cannot be resolved
+	ActionRuntimes     []runtimes.Finding             `json:"action_runtimes,omitempty"`
+	Deprecations       []deprecations.Warning         `json:"deprecations,omitempty"`
+	UnresolvedRefs     []github.UnresolvedReference   `json:"unresolved_references,omitempty"`
+	Typosquats         []policy.Typosquat             `json:"typosquats,omitempty"`
+	ArtifactMisuse     []artifacts.Finding            `json:"artifact_misuse,omitempty"`
+	Hygiene            []hygiene.Finding              `json:"hygiene,omitempty"` // Ignored security check failures and jobs without timeouts
+	MergeConflicts     []policy.MergeConflict         `json:"merge_conflicts,omitempty"`
+	BelowMinVersion    []policy.VersionFloorViolation `json:"below_min_version,omitempty"`        // References older than their action's minimum version
+	InputViolations    []policy.InputViolation        `json:"input_violations,omitempty"`         // Steps whose inputs break the input rules
+	MissingRequired    []string                       `json:"missing_required_actions,omitempty"` // Required actions the workflows do not use
+	MissingWorkflows   []policy.RequiredWorkflow      `json:"missing_workflows,omitempty"`        // Required workflows the repository lacks
+	Suppressed         []suppress.Suppression         `json:"suppressed_findings,omitempty"`      // Violations suppressed by ignore annotations, and expired annotations
+	Preexisting        []string                       `json:"preexisting_violations,omitempty"`   // Violating references a pull request's changed files used before it
+	RepoPolicyIssue    string                         `json:"repo_policy_issue,omitempty"`        // Why a required repository policy file is not usable
+	Explanations       []policy.Explanation           `json:"explanations,omitempty"`             // Rule deciding each action, with --explain
+	Severities         SeverityCounts                 `json:"severities"`                         // Findings by severity
+	EffectivePolicy    policy.EffectivePolicy         `json:"effective_policy"`
+}
+
+// RepositoryOwners returns the owners of each repository with known owners
+func (r EnforceReport) RepositoryOwners() map[string][]string {
+	owners := make(map[string][]string)
+	for repo, result := range r.Repositories {
+		if len(result.Owners) > 0 {
+			owners[repo] = result.Owners
+		}
+	}
+	return owners
+}
+
+// Violation is a single violating action reference and the rule it violates
+type Violation struct {
+	Repository  string `json:"repository"`
+	Workflow    string `json:"workflow,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Job         string `json:"job,omitempty"`
+	Action      string `json:"action"`
+	ResolvedSHA string `json:"resolved_sha,omitempty"` // Commit the reference resolved to, when known
+	Rule        string `json:"rule"`                   // Policy rule the action violates
+	Entry       string `json:"matched_entry,omitempty"`
+	Mode        string `json:"mode"`
+	Severity    string `json:"severity"`
+	Source      string `json:"source"` // Policy level of the rule: global, custom or repo
+}
+
+// SortViolations orders violations by repository, workflow, line and action
+func SortViolations(violations []Violation) {
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		if a.Workflow != b.Workflow {
+			return a.Workflow < b.Workflow
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Action < b.Action
+	})
+}
+
+// Layouts of the policy violations section
+const (
+	GroupByRepo   = "repo"   // A section per repository listing its violating actions
+	GroupByAction = "action" // A section per violating action listing the repositories using it
+	GroupByTeam   = "team"   // A section per owning team listing its repositories
+)
+
+// unownedSection heads the repositories without owners when violations are grouped by team
+const unownedSection = "Unowned repositories"
+
+// FormatPolicyViolations formats the violating actions, worded for the policy mode, noting the
+// owners of each repository when known. groupBy selects the layout: by repository, by action so
+// that an action used by many repositories is listed once with the repositories using it, or by
+// owning team so that each team finds its repositories together.
+func FormatPolicyViolations(violations map[string][]string, owners map[string][]string, policyMode, groupBy string) string {
 	if len(violations) == 0 {
 		return "✅ All repositories comply with the action policy."
 	}
@@ -21,33 +140,134 @@ func FormatPolicyViolations(violations map[string][]string, policyMode string) s
 		sb.WriteString("## ❌ Policy Violations\n\n")
 	}
 
-	// Sort repositories for consistent output
-	repos := make([]string, 0, len(violations))
-	for repo := range violations {
-		repos = append(repos, repo)
+	repos := sortedRepos(violations)
+
+	switch groupBy {
+	case GroupByAction:
+		writeViolationsByAction(&sb, violations, owners, repos, policyMode)
+	case GroupByTeam:
+		writeViolationsByTeam(&sb, violations, owners, repos, policyMode)
+	default:
+		for _, repo := range repos {
+			sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+			writeRepositoryViolations(&sb, violations[repo], owners[repo], policyMode)
+		}
+	}
+
+	if policyMode == "deny" {
+		sb.WriteString(fmt.Sprintf("\nFound %d repositories using denied actions.\n", len(violations)))
+	} else {
+		sb.WriteString(fmt.Sprintf("\nFound %d repositories with policy violations.\n", len(violations)))
+	}
+
+	return sb.String()
+}
+
+// writeRepositoryViolations lists the violating actions of a repository below its heading
+func writeRepositoryViolations(sb *strings.Builder, actions []string, owners []string, policyMode string) {
+	if len(owners) > 0 {
+		sb.WriteString(fmt.Sprintf("Owners: %s\n\n", strings.Join(owners, ", ")))
+	}
+
+	if policyMode == "deny" {
+		sb.WriteString("The following denied actions were found:\n\n")
+	} else {
+		sb.WriteString("The following actions are not allowed by policy:\n\n")
 	}
-	sort.Strings(repos)
 
+	for _, action := range actions {
+		sb.WriteString(fmt.Sprintf("- `%s`\n", action))
+	}
+	sb.WriteString("\n")
+}
+
+// writeViolationsByTeam lists the repositories of each owning team, teams in order and
+// repositories without owners last. A repository owned by several teams is listed under each.
+func writeViolationsByTeam(sb *strings.Builder, violations map[string][]string, owners map[string][]string, repos []string, policyMode string) {
+	reposByTeam := make(map[string][]string)
+	var unowned []string
 	for _, repo := range repos {
-		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		if len(owners[repo]) == 0 {
+			unowned = append(unowned, repo)
+			continue
+		}
+		for _, team := range owners[repo] {
+			if !slices.Contains(reposByTeam[team], repo) {
+				reposByTeam[team] = append(reposByTeam[team], repo)
+			}
+		}
+	}
 
-		if policyMode == "deny" {
-			sb.WriteString("The following denied actions were found:\n\n")
-		} else {
-			sb.WriteString("The following actions are not allowed by policy:\n\n")
+	teams := make([]string, 0, len(reposByTeam))
+	for team := range reposByTeam {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	writeTeam := func(heading string, teamRepos []string) {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", heading))
+		for _, repo := range teamRepos {
+			sb.WriteString(fmt.Sprintf("#### %s\n\n", repo))
+			writeRepositoryViolations(sb, violations[repo], nil, policyMode)
 		}
+	}
+	for _, team := range teams {
+		writeTeam(team, reposByTeam[team])
+	}
+	if len(unowned) > 0 {
+		writeTeam(unownedSection, unowned)
+	}
+}
 
+// writeViolationsByAction lists each violating action once with the repositories using it,
+// the most widespread first
+func writeViolationsByAction(sb *strings.Builder, violations map[string][]string, owners map[string][]string, repos []string, policyMode string) {
+	reposByAction := make(map[string][]string)
+	for _, repo := range repos {
 		for _, action := range violations[repo] {
-			sb.WriteString(fmt.Sprintf("- `%s`\n", action))
+			if !slices.Contains(reposByAction[action], repo) {
+				reposByAction[action] = append(reposByAction[action], repo)
+			}
 		}
-		sb.WriteString("\n")
 	}
 
-	if policyMode == "deny" {
-		sb.WriteString(fmt.Sprintf("\nFound %d repositories using denied actions.\n", len(violations)))
-	} else {
-		sb.WriteString(fmt.Sprintf("\nFound %d repositories with policy violations.\n", len(violations)))
+	actions := make([]string, 0, len(reposByAction))
+	for action := range reposByAction {
+		actions = append(actions, action)
 	}
+	sort.Slice(actions, func(i, j int) bool {
+		if len(reposByAction[actions[i]]) != len(reposByAction[actions[j]]) {
+			return len(reposByAction[actions[i]]) > len(reposByAction[actions[j]])
+		}
+		return actions[i] < actions[j]
+	})
 
-	return sb.String()
+	for _, action := range actions {
+		sb.WriteString(fmt.Sprintf("### `%s`\n\n", action))
+
+		count := len(reposByAction[action])
+		noun := "repositories"
+		if count == 1 {
+			noun = "repository"
+		}
+		if policyMode == "deny" {
+			sb.WriteString(fmt.Sprintf("Denied action used in %d %s:\n\n", count, noun))
+		} else {
+			sb.WriteString(fmt.Sprintf("Action not allowed by policy, used in %d %s:\n\n", count, noun))
+		}
+
+		for _, repo := range reposByAction[action] {
+			if len(owners[repo]) > 0 {
+				sb.WriteString(fmt.Sprintf("- %s (%s)\n", repo, strings.Join(owners[repo], ", ")))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s\n", repo))
+			}
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// FormatScanInterrupted formats a warning that a scan stopped early and its results are partial
+func FormatScanInterrupted(reason string) string {
+	return fmt.Sprintf("> ⚠️ **Partial results:** %s. Repositories not scanned are missing from this report.\n", reason)
 }
@@ -0,0 +1,32 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatPreexistingViolations formats the violating action references that the workflow files
+// a pull request changes already used before it, grouped by repository. They are reported for
+// context but not held against the pull request.
+func FormatPreexistingViolations(preexisting map[string][]string) string {
+	var sb strings.Builder
+	sb.WriteString("## ⏪ Pre-existing Violations\n\n")
+
+	if len(preexisting) == 0 {
+		sb.WriteString("The changed workflow files used no violating actions before the pull request.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(preexisting)
+
+	sb.WriteString("The changed workflow files already used these actions before the pull request, so they are not counted against it:\n\n")
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		for _, action := range preexisting[repo] {
+			sb.WriteString(fmt.Sprintf("- `%s`\n", action))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// FormatWorkflowProtection formats the repositories whose workflow files can be changed without review
+func FormatWorkflowProtection(unprotected map[string]github.WorkflowProtection) string {
+	var sb strings.Builder
+	sb.WriteString("## 🔒 Workflow Protection\n\n")
+
+	if len(unprotected) == 0 {
+		sb.WriteString("All repositories require review for changes to their workflow files.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(unprotected)
+
+	sb.WriteString("| Repository | Default Branch |\n")
+	sb.WriteString("|------------|----------------|\n")
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("| %s | `%s` |\n", repo, unprotected[repo].Branch))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nFound %d repositories whose workflow files can be changed without review. Require approving reviews with a ruleset or branch protection, or code owner review with CODEOWNERS covering `.github/workflows/`.\n", len(unprotected)))
+
+	return sb.String()
+}
+
+// FormatWorkflowOwners formats the workflow files whose CODEOWNERS entry includes none of the
+// required owners, grouped by repository
+func FormatWorkflowOwners(unowned map[string][]string, required []string) string {
+	var sb strings.Builder
+	sb.WriteString("## 👥 Workflow Owners\n\n")
+
+	if len(unowned) == 0 {
+		sb.WriteString(fmt.Sprintf("Every workflow file is owned by %s in CODEOWNERS.\n", strings.Join(required, " or ")))
+		return sb.String()
+	}
+
+	repos := sortedRepos(unowned)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		for _, path := range unowned[repo] {
+			sb.WriteString(fmt.Sprintf("- `%s`\n", path))
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d workflow files whose changes do not request review from %s.\n", count, strings.Join(required, " or ")))
+
+	return sb.String()
+}
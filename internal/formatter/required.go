@@ -0,0 +1,33 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatRequiredActions formats the required actions missing from each repository's workflows
+func FormatRequiredActions(missing map[string][]string) string {
+	var sb strings.Builder
+	sb.WriteString("## 📌 Required Actions\n\n")
+
+	if len(missing) == 0 {
+		sb.WriteString("All repositories use the required actions.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(missing)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		for _, action := range missing[repo] {
+			sb.WriteString(fmt.Sprintf("- `%s` is not used by any workflow\n", action))
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d required actions missing from %d repositories.\n", count, len(repos)))
+
+	return sb.String()
+}
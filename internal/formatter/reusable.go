@@ -0,0 +1,38 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// FormatReusableWorkflowViolations formats the jobs calling reusable workflows the policy does
+// not allow
+func FormatReusableWorkflowViolations(calls map[string][]github.WorkflowCall) string {
+	var sb strings.Builder
+	sb.WriteString("## 🔁 Reusable Workflows\n\n")
+
+	if len(calls) == 0 {
+		sb.WriteString("All jobs call allowed reusable workflows.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(calls)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Reusable Workflow | Workflow | Job |\n")
+		sb.WriteString("|-------------------|----------|-----|\n")
+		for _, call := range calls[repo] {
+			sb.WriteString(fmt.Sprintf("| `%s` | `%s` | %s |\n", call.Uses, call.Workflow, call.Job))
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d calls to reusable workflows not allowed by policy.\n", count))
+
+	return sb.String()
+}
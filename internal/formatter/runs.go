@@ -0,0 +1,61 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/store"
+)
+
+// FormatRuns formats recorded enforce runs as a table, newest first
+func FormatRuns(runs []store.Run) string {
+	if len(runs) == 0 {
+		return "No recorded runs.\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 🗂️ Recorded Runs\n\n")
+	sb.WriteString("| Run | Target | Started | Not Compliant | Repositories |\n")
+	sb.WriteString("|-----|--------|---------|---------------|--------------|\n")
+	for _, run := range runs {
+		started := run.StartedAt.UTC().Format(time.RFC3339)
+		if run.Interrupted != "" {
+			started += " ⚠️ interrupted"
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %d | %d |\n", run.ID, run.Target, started, run.NonCompliant, run.Total))
+	}
+	return sb.String()
+}
+
+// FormatRun formats a recorded enforce run with the outcome of each repository, those not
+// complying first
+func FormatRun(run store.Run) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## 🗂️ Run `%s`\n\n", run.ID))
+	sb.WriteString(fmt.Sprintf("%s, started %s with action-control %s: %d of %d repositories do not comply.\n\n",
+		run.Target, run.StartedAt.UTC().Format(time.RFC3339), run.ToolVersion, run.NonCompliant, run.Total))
+	if run.Interrupted != "" {
+		sb.WriteString(FormatScanInterrupted(run.Interrupted) + "\n")
+	}
+	if len(run.Repositories) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("| Repository | Owners | 🔴 Error | 🟡 Warning | 🔵 Info | Violating Actions |\n")
+	sb.WriteString("|------------|--------|----------|------------|---------|-------------------|\n")
+	for _, compliant := range []bool{false, true} {
+		for _, result := range run.Repositories {
+			if result.Compliant != compliant {
+				continue
+			}
+			violations := make([]string, len(result.Violations))
+			for i, violation := range result.Violations {
+				violations[i] = "`" + violation + "`"
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %d | %s |\n", result.Repository, strings.Join(result.Owners, ", "),
+				result.Errors, result.Warnings, result.Info, strings.Join(violations, ", ")))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/runtimes"
+)
+
+// FormatActionRuntimes formats the actions whose runtime fails the policy's runtime rules
+func FormatActionRuntimes(findings map[string][]runtimes.Finding) string {
+	var sb strings.Builder
+	sb.WriteString("## 🐳 Action Runtimes\n\n")
+
+	if len(findings) == 0 {
+		sb.WriteString("All actions run on allowed runtimes.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(findings)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action | Runtime | Issue |\n")
+		sb.WriteString("|--------|---------|-------|\n")
+		for _, finding := range findings[repo] {
+			issue := fmt.Sprintf("deprecated runtime `%s`", finding.Runtime.Using)
+			if finding.Reason == runtimes.ReasonUnknownRegistry {
+				issue = fmt.Sprintf("image `%s` from an unknown registry", finding.Image)
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s |\n", finding.Action, describeRuntime(finding.Runtime), issue))
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d actions from unknown registries or on deprecated runtimes.\n", total))
+
+	return sb.String()
+}
+
+// FormatRuntimeReport formats the runtime of every third-party action
+func FormatRuntimeReport(report runtimes.Report) string {
+	var sb strings.Builder
+	sb.WriteString("# Action Runtimes\n\n")
+
+	if len(report.Actions) == 0 && len(report.Unresolved) == 0 {
+		sb.WriteString("No third-party actions found.\n")
+		return sb.String()
+	}
+
+	kinds := make([]string, 0, len(report.Counts))
+	for kind := range report.Counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	sb.WriteString("| Kind | Actions |\n")
+	sb.WriteString("|------|---------|\n")
+	for _, kind := range kinds {
+		sb.WriteString(fmt.Sprintf("| %s | %d |\n", kind, report.Counts[kind]))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("| Action | Kind | Runtime | Image |\n")
+	sb.WriteString("|--------|------|---------|-------|\n")
+	for _, runtime := range report.Actions {
+		image := "-"
+		if runtime.Image != "" {
+			image = fmt.Sprintf("`%s`", runtime.Image)
+			if len(runtime.BaseImages) > 0 {
+				image += fmt.Sprintf(" from `%s`", strings.Join(runtime.BaseImages, "`, `"))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", runtime.Action, codeOrDash(runtime.Kind), describeRuntime(runtime), image))
+	}
+
+	if len(report.Unresolved) > 0 {
+		sb.WriteString(fmt.Sprintf("\nCould not read the action.yml of %d actions: `%s`\n", len(report.Unresolved), strings.Join(report.Unresolved, "`, `")))
+	}
+
+	return sb.String()
+}
+
+// describeRuntime names the runtime an action declares
+func describeRuntime(runtime github.ActionRuntime) string {
+	if runtime.Using == "" {
+		return "-"
+	}
+	return fmt.Sprintf("`%s`", runtime.Using)
+}
@@ -0,0 +1,215 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/deprecations"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// sarifSchema is the schema of the SARIF version FormatSARIF writes
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// Rules of the SARIF results besides the policy rules violating actions are attributed to
+const (
+	sarifRuleDeprecatedRunner  = "deprecated_runner"
+	sarifRuleDeprecatedRuntime = "deprecated_node_runtime"
+	sarifRuleMergeConflict     = "merge_conflict"
+)
+
+// sarifRuleDescriptions describe the rules of SARIF results
+var sarifRuleDescriptions = map[string]string{
+	policy.RuleAlwaysDeny:      "Action is on the always_deny list",
+	policy.RuleDeniedActions:   "Action is on the denied_actions list",
+	policy.RuleAllowedActions:  "Action is not on the allowed_actions list",
+	policy.RuleAllowedOwners:   "Action owner is not on the allowed_owners list",
+	policy.RuleVerifiedCreator: "Action publisher is not a verified creator",
+	sarifRuleDeprecatedRunner:  "Job runs on a retired runner label",
+	sarifRuleDeprecatedRuntime: "Action runs on a deprecated Node.js runtime",
+	sarifRuleMergeConflict:     "Repository policy file conflicts with the global policy",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifLevel maps a severity to its SARIF level
+func sarifLevel(severity string) string {
+	switch severity {
+	case policy.SeverityError:
+		return "error"
+	case policy.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifProperties returns the properties of a result in a repository, with its owners when known
+func sarifProperties(report EnforceReport, repo string, extra ...string) map[string]string {
+	properties := map[string]string{"repository": repo}
+	if owners := report.Repositories[repo].Owners; len(owners) > 0 {
+		properties["owners"] = strings.Join(owners, ", ")
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		properties[extra[i]] = extra[i+1]
+	}
+	return properties
+}
+
+// FormatSARIF formats the enforce report as a SARIF log for code scanning: violating action
+// references as errors, deprecations as warnings unless deprecationsFail escalates them, and
+// policy merge conflicts as notes. Each result records its repository in its properties, as
+// locations are relative to the repository root, along with the repository's owners when known.
+// Violations are only listed when the report was recorded with detailed violations.
+func FormatSARIF(report EnforceReport, deprecationsFail bool, toolVersion string) (string, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "action-control",
+			Version:        toolVersion,
+			InformationURI: "https://github.com/ihavespoons/action-control",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	location := func(path string, line int) []sarifLocation {
+		loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: path}}}
+		if line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+		}
+		return []sarifLocation{loc}
+	}
+
+	for _, violation := range report.Violations {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:     violation.Rule,
+			Level:      sarifLevel(violation.Severity),
+			Message:    sarifMessage{Text: fmt.Sprintf("%s violates %s (%s policy)", violation.Action, violation.Rule, violation.Source)},
+			Locations:  location(violation.Workflow, violation.Line),
+			Properties: sarifProperties(report, violation.Repository, "action", violation.Action),
+		})
+	}
+
+	deprecationSeverity := policy.SeverityWarning
+	if deprecationsFail {
+		deprecationSeverity = policy.SeverityError
+	}
+
+	repos := sortedRepos(report.Repositories)
+
+	for _, repo := range repos {
+		result := report.Repositories[repo]
+		for _, warning := range result.Deprecations {
+			if warning.Kind == deprecations.KindNode {
+				run.Results = append(run.Results, sarifResult{
+					RuleID:     sarifRuleDeprecatedRuntime,
+					Level:      sarifLevel(deprecationSeverity),
+					Message:    sarifMessage{Text: fmt.Sprintf("%s runs on the deprecated %s runtime", warning.Action, warning.Runtime)},
+					Locations:  []sarifLocation{},
+					Properties: sarifProperties(report, repo, "action", warning.Action),
+				})
+				continue
+			}
+			message := fmt.Sprintf("Job %s runs on the retired runner label %s", warning.Job, warning.Label)
+			if warning.Replacement != "" {
+				message += fmt.Sprintf(", use %s instead", warning.Replacement)
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:     sarifRuleDeprecatedRunner,
+				Level:      sarifLevel(deprecationSeverity),
+				Message:    sarifMessage{Text: message},
+				Locations:  location(warning.Workflow, 0),
+				Properties: sarifProperties(report, repo),
+			})
+		}
+
+		for _, conflict := range result.MergeConflicts {
+			message := fmt.Sprintf("%s conflicts with the global policy on %s", policy.RepoPolicyPath, conflict.Rule)
+			if conflict.Entry != "" {
+				message += fmt.Sprintf(" (%s)", conflict.Entry)
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:     sarifRuleMergeConflict,
+				Level:      sarifLevel(policy.SeverityInfo),
+				Message:    sarifMessage{Text: fmt.Sprintf("%s, resolved in favor of %s", message, conflict.Resolution)},
+				Locations:  location(policy.RepoPolicyPath, 0),
+				Properties: sarifProperties(report, repo),
+			})
+		}
+	}
+
+	// Describe each rule that has results
+	seen := make(map[string]bool)
+	for _, result := range run.Results {
+		if seen[result.RuleID] {
+			continue
+		}
+		seen[result.RuleID] = true
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: result.RuleID, ShortDescription: sarifMessage{Text: sarifRuleDescriptions[result.RuleID]}})
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	data, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error formatting SARIF: %w", err)
+	}
+	return string(data), nil
+}
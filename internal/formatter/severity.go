@@ -0,0 +1,112 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// SeverityCounts counts a repository's findings by severity
+type SeverityCounts struct {
+	Errors   int `json:"errors"`   // Findings failing the policy
+	Warnings int `json:"warnings"` // Deprecations, unless the policy escalates them to errors
+	Info     int `json:"info"`     // Policy merge conflicts, violations suppressed by ignore annotations and pre-existing violations
+}
+
+// Total returns the number of findings of every severity
+func (c SeverityCounts) Total() int {
+	return c.Errors + c.Warnings + c.Info
+}
+
+// CountSeverities counts the repository's findings by severity. Deprecations are errors when
+// deprecationsFail is set and warnings otherwise.
+func (r RepositoryResult) CountSeverities(deprecationsFail bool) SeverityCounts {
+	counts := SeverityCounts{Errors: r.Findings(), Info: len(r.MergeConflicts) + len(r.Preexisting)}
+	if !deprecationsFail {
+		counts.Errors -= len(r.Deprecations)
+		counts.Warnings = len(r.Deprecations)
+	}
+	for _, suppression := range r.Suppressed {
+		if !suppression.Expired {
+			counts.Info++
+		}
+	}
+	return counts
+}
+
+// Complies reports whether the repository has no findings failing the policy. Deprecations
+// only fail it when deprecationsFail is set; informational details never do.
+func (r RepositoryResult) Complies(deprecationsFail bool) bool {
+	return r.CountSeverities(deprecationsFail).Errors == 0
+}
+
+// SeverityBadge returns the badge marking findings of a severity in markdown reports
+func SeverityBadge(severity string) string {
+	switch severity {
+	case policy.SeverityError:
+		return "🔴 Error"
+	case policy.SeverityWarning:
+		return "🟡 Warning"
+	default:
+		return "🔵 Info"
+	}
+}
+
+// FormatSeverityHeading formats the heading of the report sections holding findings of a severity
+func FormatSeverityHeading(severity string) string {
+	switch severity {
+	case policy.SeverityError:
+		return "# 🔴 Errors\n"
+	case policy.SeverityWarning:
+		return "# 🟡 Warnings\n"
+	default:
+		return "# 🔵 Info\n"
+	}
+}
+
+// FormatSeverityMatrix formats a table of the findings of each repository by severity, with
+// the repositories with the most errors first, so that reports can be triaged at a glance.
+// Repositories without findings are left out.
+func FormatSeverityMatrix(report EnforceReport) string {
+	repos := make([]string, 0, len(report.Repositories))
+	var total SeverityCounts
+	for repo, result := range report.Repositories {
+		if result.Severities.Total() == 0 {
+			continue
+		}
+		repos = append(repos, repo)
+		total.Errors += result.Severities.Errors
+		total.Warnings += result.Severities.Warnings
+		total.Info += result.Severities.Info
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Findings by Severity\n\n")
+	if len(repos) == 0 {
+		sb.WriteString("No findings.\n")
+		return sb.String()
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		a, b := report.Repositories[repos[i]].Severities, report.Repositories[repos[j]].Severities
+		if a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		if a.Warnings != b.Warnings {
+			return a.Warnings > b.Warnings
+		}
+		return repos[i] < repos[j]
+	})
+
+	sb.WriteString(fmt.Sprintf("| Repository | %s | %s | %s |\n", SeverityBadge(policy.SeverityError), SeverityBadge(policy.SeverityWarning), SeverityBadge(policy.SeverityInfo)))
+	sb.WriteString("|------------|----------|------------|---------|\n")
+	for _, repo := range repos {
+		counts := report.Repositories[repo].Severities
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %d |\n", repo, counts.Errors, counts.Warnings, counts.Info))
+	}
+	sb.WriteString(fmt.Sprintf("| **Total** | **%d** | **%d** | **%d** |\n", total.Errors, total.Warnings, total.Info))
+
+	return sb.String()
+}
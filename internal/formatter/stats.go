@@ -0,0 +1,37 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/stats"
+)
+
+// FormatStats formats the anonymous aggregate statistics of an organization's action usage
+func FormatStats(s *stats.Stats) string {
+	var sb strings.Builder
+	sb.WriteString("# GitHub Actions Statistics\n\n")
+
+	sb.WriteString("| Metric | Value |\n")
+	sb.WriteString("|--------|-------|\n")
+	sb.WriteString(fmt.Sprintf("| Repositories | %d |\n", s.Repositories))
+	sb.WriteString(fmt.Sprintf("| Repositories with workflows | %d (%.1f%%) |\n", s.RepositoriesWithWorkflows, s.WorkflowsPercent))
+	sb.WriteString(fmt.Sprintf("| Workflows | %d |\n", s.Workflows))
+	sb.WriteString(fmt.Sprintf("| Action references | %d |\n", s.ActionReferences))
+	sb.WriteString(fmt.Sprintf("| Unique actions | %d |\n", s.UniqueActions))
+	sb.WriteString(fmt.Sprintf("| Pinned to a SHA | %.1f%% |\n", s.PinnedPercent))
+	sb.WriteString("\n")
+
+	sb.WriteString("## Top Action Owners\n\n")
+	if len(s.TopOwners) == 0 {
+		sb.WriteString("No third-party actions are used.\n")
+		return sb.String()
+	}
+	sb.WriteString("| Owner | References | Repositories |\n")
+	sb.WriteString("|-------|------------|--------------|\n")
+	for _, owner := range s.TopOwners {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d |\n", owner.Owner, owner.References, owner.Repositories))
+	}
+
+	return sb.String()
+}
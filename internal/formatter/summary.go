@@ -0,0 +1,57 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Findings counts the findings recorded for a repository, such as violating actions, lint
+// findings and denied cloud identities. Informational details like explanations and merge
+// conflicts are not counted.
+func (r RepositoryResult) Findings() int {
+	count := len(r.Violations) + len(r.LintFindings) + len(r.PinDrift) + len(r.ImageViolations) +
+		len(r.WorkflowCalls) + len(r.UnownedWorkflows) + len(r.ActionHealth) + len(r.MissingActions) + len(r.ActionRuntimes) +
+		len(r.Deprecations) + len(r.UnresolvedRefs) + len(r.Typosquats) + len(r.ArtifactMisuse) + len(r.MissingRequired) +
+		len(r.MissingWorkflows) + len(r.BelowMinVersion) + len(r.InputViolations) + len(r.Hygiene)
+	for _, access := range r.CloudAccess {
+		if !access.Allowed {
+			count++
+		}
+	}
+	if r.ActionsUpdates != nil && !r.ActionsUpdates.Covered {
+		count++
+	}
+	if r.WorkflowProtection != nil && !r.WorkflowProtection.Protected {
+		count++
+	}
+	if r.RepoPolicyIssue != "" {
+		count++
+	}
+	return count
+}
+
+// FormatSummaryOnly formats one line per repository, stating whether it complies with the
+// policy or how many findings it has
+func FormatSummaryOnly(report EnforceReport) string {
+	repos := sortedRepos(report.Repositories)
+
+	var sb strings.Builder
+	for _, repo := range repos {
+		result := report.Repositories[repo]
+		name := repo
+		if len(result.Owners) > 0 {
+			name = fmt.Sprintf("%s (%s)", repo, strings.Join(result.Owners, ", "))
+		}
+		if result.Compliant {
+			sb.WriteString(fmt.Sprintf("%s: compliant\n", name))
+			continue
+		}
+		findings := result.Findings()
+		noun := "findings"
+		if findings == 1 {
+			noun = "finding"
+		}
+		sb.WriteString(fmt.Sprintf("%s: not compliant, %d %s\n", name, findings, noun))
+	}
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/suppress"
+)
+
+// FormatSuppressions formats the violations suppressed by ignore annotations in workflow
+// files, grouped by repository, so that suppressions stay visible for audit
+func FormatSuppressions(suppressions map[string][]suppress.Suppression) string {
+	var sb strings.Builder
+	sb.WriteString("## 🔕 Suppressed Findings\n\n")
+
+	if len(suppressions) == 0 {
+		sb.WriteString("No findings are suppressed by ignore annotations.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(suppressions)
+
+	suppressed, expired := 0, 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action | Workflow | Reason | Expires |\n")
+		sb.WriteString("|--------|----------|--------|---------|\n")
+		for _, suppression := range suppressions[repo] {
+			workflow := suppression.Workflow
+			if suppression.Line > 0 {
+				workflow = fmt.Sprintf("%s:%d", workflow, suppression.Line)
+			}
+			if suppression.Ref != "" {
+				workflow = fmt.Sprintf("%s (%s)", workflow, suppression.Ref)
+			}
+			expires := suppression.Expires
+			if expires == "" {
+				expires = "never"
+			}
+			if suppression.Expired {
+				expires += " ⚠️ expired, not applied"
+				expired++
+			} else {
+				suppressed++
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | `%s` | %s | %s |\n", suppression.Action, workflow, suppression.Reason, expires))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Suppressed %d action references; %d annotations have expired.\n", suppressed, expired))
+
+	return sb.String()
+}
@@ -0,0 +1,159 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// UsageTemplateData is the data the usage report passes to custom templates
+type UsageTemplateData struct {
+	Usage map[string][]Action // Action usage by repository
+}
+
+// FormatTemplate renders data through a user-provided text/template. Templates can use the
+// functions of TemplateFuncs, which follow the names and argument order of the sprig library.
+func FormatTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Funcs(TemplateFuncs()).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %w", err)
+	}
+
+	var output strings.Builder
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", fmt.Errorf("error rendering template: %w", err)
+	}
+	return output.String(), nil
+}
+
+// TemplateFuncs returns the functions available to custom templates: a subset of the sprig
+// string, list, encoding and date functions, plus csv for writing CSV fields
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// Strings
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"quote":      func(s string) string { return fmt.Sprintf("%q", s) },
+		"squote":     func(s string) string { return "'" + s + "'" },
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"csv":        csvField,
+
+		// Lists and maps
+		"list":      func(items ...interface{}) []interface{} { return items },
+		"keys":      keys,
+		"sortAlpha": sortAlpha,
+
+		// Defaults
+		"default": func(def, value interface{}) interface{} {
+			if empty(value) {
+				return def
+			}
+			return value
+		},
+		"empty": empty,
+
+		// Encoding
+		"toJson": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"toPrettyJson": func(v interface{}) (string, error) {
+			data, err := json.MarshalIndent(v, "", "  ")
+			return string(data), err
+		},
+
+		// Numbers and dates
+		"add":  func(a, b int) int { return a + b },
+		"sub":  func(a, b int) int { return a - b },
+		"now":  time.Now,
+		"date": func(layout string, t time.Time) string { return t.Format(layout) },
+	}
+}
+
+// indent prefixes every line of s with spaces
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// join joins the elements of a list, formatting non-string elements
+func join(sep string, list interface{}) string {
+	return strings.Join(stringList(list), sep)
+}
+
+// csvField quotes s as a CSV field when it contains separators, quotes or line breaks
+func csvField(s string) string {
+	if !strings.ContainsAny(s, ",\"\r\n") {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// keys returns the sorted keys of a map
+func keys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map {
+		return nil
+	}
+
+	result := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		result = append(result, fmt.Sprint(key.Interface()))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// sortAlpha returns the elements of a list as sorted strings
+func sortAlpha(list interface{}) []string {
+	result := stringList(list)
+	sort.Strings(result)
+	return result
+}
+
+// stringList returns the elements of a list or array as strings, in their original order
+func stringList(list interface{}) []string {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		if list == nil {
+			return nil
+		}
+		return []string{fmt.Sprint(list)}
+	}
+
+	result := make([]string, v.Len())
+	for i := range result {
+		result[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return result
+}
+
+// empty reports whether v is the zero value of its type, or an empty collection
+func empty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	}
+	return rv.IsZero()
+}
@@ -0,0 +1,37 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// FormatTyposquats formats the actions resembling popular actions, grouped by repository
+func FormatTyposquats(typosquats map[string][]policy.Typosquat) string {
+	var sb strings.Builder
+	sb.WriteString("## 🎭 Look-alike Actions\n\n")
+
+	if len(typosquats) == 0 {
+		sb.WriteString("No actions resemble the name of a popular action.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(typosquats)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action | Resembles |\n")
+		sb.WriteString("|--------|-----------|\n")
+		for _, typosquat := range typosquats[repo] {
+			sb.WriteString(fmt.Sprintf("| `%s` | `%s` |\n", typosquat.Action, typosquat.Resembles))
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d actions that may be typosquatting a popular action.\n", count))
+
+	return sb.String()
+}
@@ -0,0 +1,42 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// FormatUnresolvedReferences formats the uses values whose expressions cannot be evaluated
+// statically, so the actions they run cannot be checked against the policy
+func FormatUnresolvedReferences(references map[string][]github.UnresolvedReference) string {
+	var sb strings.Builder
+	sb.WriteString("## ❔ Unresolvable Action References\n\n")
+
+	if len(references) == 0 {
+		sb.WriteString("All action references could be resolved.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(references)
+
+	total := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Reference | Workflow | Job | Reason |\n")
+		sb.WriteString("|-----------|----------|-----|--------|\n")
+		for _, reference := range references[repo] {
+			location := reference.Workflow
+			if reference.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, reference.Line)
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | `%s` | `%s` | %s |\n", reference.Uses, location, reference.Job, reference.Reason))
+			total++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d unresolvable action references. Replace dynamic references with static ones so the policy can check them.\n", total))
+
+	return sb.String()
+}
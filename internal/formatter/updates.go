@@ -0,0 +1,35 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/updates"
+)
+
+// FormatActionsUpdates formats the repositories lacking automated updates for their actions
+func FormatActionsUpdates(missing map[string]updates.Coverage) string {
+	var sb strings.Builder
+	sb.WriteString("## 🔄 Actions Updates\n\n")
+
+	if len(missing) == 0 {
+		sb.WriteString("All repositories have Dependabot or Renovate configured to update their actions.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(missing)
+
+	sb.WriteString("| Repository | Configuration |\n")
+	sb.WriteString("|------------|---------------|\n")
+	for _, repo := range repos {
+		configuration := "_none_"
+		if coverage := missing[repo]; coverage.ConfigPath != "" {
+			configuration = fmt.Sprintf("`%s` does not update `github-actions`", coverage.ConfigPath)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", repo, configuration))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nFound %d repositories without automated action updates.\n", len(missing)))
+
+	return sb.String()
+}
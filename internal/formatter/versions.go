@@ -0,0 +1,46 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// FormatMinVersions formats the action references older than their action's minimum version,
+// grouped by repository
+func FormatMinVersions(violations map[string][]policy.VersionFloorViolation) string {
+	var sb strings.Builder
+	sb.WriteString("## ⏳ Minimum Action Versions\n\n")
+
+	if len(violations) == 0 {
+		sb.WriteString("All action references meet their minimum version.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(violations)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		sb.WriteString("| Action | Workflow | Version | Minimum |\n")
+		sb.WriteString("|--------|----------|---------|---------|\n")
+		for _, violation := range violations[repo] {
+			workflow := violation.Workflow
+			if violation.Line > 0 {
+				workflow = fmt.Sprintf("%s:%d", workflow, violation.Line)
+			}
+			version := violation.Version
+			if version == "" {
+				version = "not a version"
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | `%s` | %s | %s |\n", violation.Action, workflow, version, violation.Minimum))
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d action references below their minimum version.\n", count))
+
+	return sb.String()
+}
@@ -0,0 +1,39 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// FormatRequiredWorkflows formats the required workflows each repository lacks
+func FormatRequiredWorkflows(missing map[string][]policy.RequiredWorkflow) string {
+	var sb strings.Builder
+	sb.WriteString("## 🧪 Required Workflows\n\n")
+
+	if len(missing) == 0 {
+		sb.WriteString("All repositories have the required workflows.\n")
+		return sb.String()
+	}
+
+	repos := sortedRepos(missing)
+
+	count := 0
+	for _, repo := range repos {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", repo))
+		for _, required := range missing[repo] {
+			if required.On != "" {
+				sb.WriteString(fmt.Sprintf("- No workflow runs on `%s` for the default branch\n", required.On))
+			} else {
+				sb.WriteString(fmt.Sprintf("- No workflow file matches `%s`\n", required.Path))
+			}
+			count++
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d required workflows missing from %d repositories.\n", count, len(repos)))
+
+	return sb.String()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"path"
+	"regexp"
 	"strings"
 
 	"github.com/google/go-github/v70/github"
@@ -12,19 +14,122 @@ import (
 
 // Action represents a GitHub action reference from a workflow file
 type Action struct {
-	Name string
-	Uses string
+	Name     string
+	Uses     string
+	Workflow string            // Path of the workflow file referencing the action
+	Job      string            // Job referencing the action
+	Ref      string            // Branch of the workflow file; empty for the default branch
+	With     map[string]string // Step inputs
+	Comment  string            // Trailing comment on the uses line, such as the version of a SHA pin
+	Line     int               // Line of the uses key in the workflow file; 0 when unknown
+
+	WorkflowName string      // name of the workflow; empty when unset
+	JobName      string      // name of the job, as shown in the Actions UI; empty when unset
+	Permissions  Permissions // GITHUB_TOKEN permissions of the job
+
+	ContinueOnError bool // continue-on-error is true for the step, so its failure is ignored
+}
+
+// WorkflowFile represents a workflow definition fetched from a repository
+type WorkflowFile struct {
+	Name    string
+	Path    string
+	SHA     string // Blob SHA of the content
+	Ref     string // Branch the file was read from; empty for the default branch
+	Content []byte
+}
+
+// IsAction reports whether the file is the action.yml of a composite action rather than a
+// workflow
+func (f WorkflowFile) IsAction() bool {
+	return IsActionPath(f.Path)
+}
+
+// IsActionPath reports whether a path names an action metadata file, action.yml or action.yaml
+func IsActionPath(file string) bool {
+	name := path.Base(file)
+	return name == "action.yml" || name == "action.yaml"
 }
 
 // GetActions retrieves all actions used in workflow files for a repository
 func (c *Client) GetActions(ctx context.Context, owner, repo string) ([]Action, error) {
-	// First, get all workflow files in .github/workflows directory
+	files, err := c.GetWorkflowFiles(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExtractActions(files), nil
+}
+
+// ExtractActions extracts action references from already fetched workflow files.
+// Files that cannot be parsed are skipped.
+func ExtractActions(files []WorkflowFile) []Action {
+	var allActions []Action
+
+	// Process each workflow file
+	for _, file := range files {
+		actions, err := extractActionsFromWorkflow(file.Content, file.Name)
+		if err != nil {
+			continue
+		}
+		comments := usesComments(file.Content)
+		for i := range actions {
+			actions[i].Workflow = file.Path
+			actions[i].Ref = file.Ref
+			actions[i].Comment = comments[actions[i].Uses]
+		}
+
+		allActions = append(allActions, actions...)
+	}
+
+	return allActions
+}
+
+// GetWorkflowFiles retrieves the content of all workflow files for a repository.
+// The Git Trees API is tried first; providers or repositories where it is unavailable
+// fall back to listing the workflows directory through the contents API.
+//
+// When a branch pattern is set, workflow files on matching branches that differ from the
+// default branch are included as well, with Ref set to their branch.
+//
+// When a run source is set, the files are discovered from recent workflow runs instead.
+func (c *Client) GetWorkflowFiles(ctx context.Context, owner, repo string) ([]WorkflowFile, error) {
+	if !c.runsSince.IsZero() {
+		return c.getWorkflowFilesFromRuns(ctx, owner, repo)
+	}
+
+	// A repository without a workflows directory has no workflow files, which would take
+	// the contents API another request to confirm
+	files, err := c.getWorkflowFilesFromTree(ctx, owner, repo, "HEAD")
+	switch {
+	case err == nil:
+	case treeAbsent(err):
+		files = nil
+	case treeFallback(err):
+		files, err = c.getWorkflowFilesFromContents(ctx, owner, repo)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if c.branches == "" {
+		return files, nil
+	}
+
+	return c.addBranchWorkflowFiles(ctx, owner, repo, files)
+}
+
+// getWorkflowFilesFromContents retrieves workflow files through the contents API
+func (c *Client) getWorkflowFilesFromContents(ctx context.Context, owner, repo string) ([]WorkflowFile, error) {
+	// First, get all workflow files in the workflows directory
 	opts := &github.RepositoryContentGetOptions{}
 	_, dirContent, _, err := c.client.Repositories.GetContents(
 		ctx,
 		owner,
 		repo,
-		".github/workflows",
+		c.workflowsPath(),
 		opts,
 	)
 
@@ -32,9 +137,9 @@ func (c *Client) GetActions(ctx context.Context, owner, repo string) ([]Action,
 		return nil, fmt.Errorf("failed to get workflow directory: %w", err)
 	}
 
-	var allActions []Action
+	var files []WorkflowFile
 
-	// Process each workflow file
+	// Fetch each workflow file
 	for _, file := range dirContent {
 		if !strings.HasSuffix(*file.Name, ".yml") && !strings.HasSuffix(*file.Name, ".yaml") {
 			continue
@@ -61,61 +166,315 @@ func (c *Client) GetActions(ctx context.Context, owner, repo string) ([]Action,
 			continue
 		}
 
-		actions, err := extractActionsFromWorkflow(content, *file.Name)
-		if err != nil {
-			continue
-		}
-
-		allActions = append(allActions, actions...)
+		files = append(files, WorkflowFile{
+			Name:    *file.Name,
+			Path:    *file.Path,
+			SHA:     fileContent.GetSHA(),
+			Content: content,
+		})
 	}
 
-	return allActions, nil
+	return files, nil
 }
 
 // extractActionsFromWorkflow parses a workflow file and extracts action references
 func extractActionsFromWorkflow(content []byte, filename string) ([]Action, error) {
-	var workflow map[string]interface{}
-	if err := yaml.Unmarshal(content, &workflow); err != nil {
-		return nil, fmt.Errorf("failed to parse workflow file %s: %w", filename, err)
+	actions, _, err := parseWorkflowUses(content, filename)
+	return actions, err
+}
+
+// parseWorkflowUses parses a workflow file and extracts action references, along with uses
+// values built from expressions that cannot be evaluated. Static expressions are evaluated,
+// with one action per value a matrix property takes. Every document of a multi-document file
+// is read, with anchors, aliases and merge keys resolved.
+func parseWorkflowUses(content []byte, filename string) ([]Action, []UnresolvedReference, error) {
+	documents, err := workflowDocuments(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse workflow file %s: %w", filename, err)
 	}
 
 	actions := []Action{}
+	var unresolved []UnresolvedReference
+	for _, document := range documents {
+		var workflow map[string]interface{}
+		if err := document.Decode(&workflow); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse workflow file %s: %w", filename, err)
+		}
+		documentActions, documentUnresolved := workflowUses(workflow, usesLines(document))
+		actions = append(actions, documentActions...)
+		unresolved = append(unresolved, documentUnresolved...)
+	}
 
-	// Extract the workflow name
+	return actions, unresolved, nil
+}
+
+// workflowUses extracts the action references of a single decoded workflow document
+func workflowUses(workflow map[string]interface{}, lines map[usesPosition]int) ([]Action, []UnresolvedReference) {
+	var actions []Action
+	var unresolved []UnresolvedReference
+
+	// Extract the workflow name and the permissions its jobs inherit
 	workflowName, _ := workflow["name"].(string)
+	var workflowPermissions Permissions
+	if permissions, set := workflow["permissions"]; set {
+		workflowPermissions = parsePermissions(permissions)
+	}
+
+	// add records the references a uses value resolves to, or why it cannot be resolved
+	add := func(action Action, scope expressionScope) {
+		references, err := resolveUses(action.Uses, scope)
+		if err != nil {
+			unresolved = append(unresolved, UnresolvedReference{Uses: action.Uses, Job: action.Job, Line: action.Line, Reason: err.Error()})
+			return
+		}
+		for _, reference := range references {
+			action.Uses = reference
+			actions = append(actions, action)
+		}
+	}
+
+	// addSteps records the actions the steps of a job use
+	addSteps := func(job Action, value interface{}, matrix interface{}, envs ...interface{}) {
+		steps, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for i, step := range steps {
+			if stepMap, ok := step.(map[string]interface{}); ok {
+				if uses, ok := stepMap["uses"].(string); ok {
+					name := ""
+					if n, ok := stepMap["name"].(string); ok {
+						name = n
+					}
+					add(Action{
+						Name: name,
+						Uses: uses,
+						Job:  job.Job,
+						With: stepInputs(stepMap["with"]),
+						Line: lines[usesPosition{job.Job, i}],
+
+						WorkflowName: workflowName,
+						JobName:      job.JobName,
+						Permissions:  job.Permissions,
+
+						ContinueOnError: isTrue(stepMap["continue-on-error"]),
+					}, newExpressionScope(matrix, append(envs, stepMap["env"])...))
+				}
+			}
+		}
+	}
 
 	// Process jobs section if it exists
 	if jobs, ok := workflow["jobs"].(map[string]interface{}); ok {
 		for jobName, jobConfig := range jobs {
 			if jobMap, ok := jobConfig.(map[string]interface{}); ok {
+				var matrix interface{}
+				if strategy, ok := jobMap["strategy"].(map[string]interface{}); ok {
+					matrix = strategy["matrix"]
+				}
+
+				// Job-level permissions replace the workflow's
+				job := Action{Job: jobName, WorkflowName: workflowName, Permissions: workflowPermissions}
+				job.JobName, _ = jobMap["name"].(string)
+				if permissions, set := jobMap["permissions"]; set {
+					job.Permissions = parsePermissions(permissions)
+				}
+
 				// Check for a job-level 'uses' field (e.g., for reusable workflows)
 				if uses, ok := jobMap["uses"].(string); ok {
-					actions = append(actions, Action{
+					add(Action{
 						Name: fmt.Sprintf("%s (job: %s)", workflowName, jobName),
 						Uses: uses,
-					})
+						Job:  jobName,
+						With: stepInputs(jobMap["with"]),
+						Line: lines[usesPosition{jobName, -1}],
+
+						WorkflowName: workflowName,
+						JobName:      job.JobName,
+						Permissions:  job.Permissions,
+					}, newExpressionScope(matrix, workflow["env"]))
 				}
 
 				// Process steps if they exist
-				if steps, ok := jobMap["steps"].([]interface{}); ok {
-					for _, step := range steps {
-						if stepMap, ok := step.(map[string]interface{}); ok {
-							if uses, ok := stepMap["uses"].(string); ok {
-								name := ""
-								if n, ok := stepMap["name"].(string); ok {
-									name = n
-								}
-								actions = append(actions, Action{
-									Name: name,
-									Uses: uses,
-								})
-							}
-						}
-					}
-				}
+				addSteps(job, jobMap["steps"], matrix, workflow["env"], jobMap["env"])
 			}
 		}
 	}
 
-	return actions, nil
+	// Process the steps of a composite action's action.yml
+	if runs, ok := workflow["runs"].(map[string]interface{}); ok && runs["using"] == "composite" {
+		addSteps(Action{Job: compositeJob}, runs["steps"], nil)
+	}
+
+	return actions, unresolved
+}
+
+// stepInputs converts a step's "with" block into string inputs
+func stepInputs(value interface{}) map[string]string {
+	with, ok := value.(map[string]interface{})
+	if !ok || len(with) == 0 {
+		return nil
+	}
+
+	inputs := make(map[string]string, len(with))
+	for key, v := range with {
+		inputs[key] = fmt.Sprint(v)
+	}
+	return inputs
+}
+
+// usesLine matches a uses key with a trailing comment, as written by tools that pin actions
+// to a SHA while recording the version, e.g. "uses: actions/checkout@<sha> # v4.1.1"
+var usesLine = regexp.MustCompile(`^\s*(?:-\s+)?uses:\s*["']?([^\s"'#]+)["']?\s+#\s*(.*?)\s*$`)
+
+// usesComments maps each referenced action to the first trailing comment found on its uses line.
+// The YAML decoder discards comments, so they are recovered from the raw content.
+func usesComments(content []byte) map[string]string {
+	comments := make(map[string]string)
+	for _, line := range strings.Split(string(content), "\n") {
+		match := usesLine.FindStringSubmatch(line)
+		if match == nil || match[2] == "" {
+			continue
+		}
+		if _, seen := comments[match[1]]; !seen {
+			comments[match[1]] = match[2]
+		}
+	}
+	return comments
+}
+
+// compositeJob is the job of the steps of a composite action, which have none
+const compositeJob = ""
+
+// usesPosition identifies a uses key by its job and step index; step is -1 for a job-level uses
+type usesPosition struct {
+	job  string
+	step int
+}
+
+// usesLines maps each uses key of a workflow document to the line it appears on. Decoding into
+// a map discards positions, so they are recovered from the document's nodes. Keys inherited
+// through an alias or merge key report the line of the anchored definition.
+func usesLines(doc *yaml.Node) map[usesPosition]int {
+	lines := make(map[usesPosition]int)
+	if len(doc.Content) == 0 {
+		return lines
+	}
+
+	// The steps of a composite action belong to no job
+	if steps := mappingValue(mappingValue(doc.Content[0], "runs"), "steps"); steps != nil && steps.Kind == yaml.SequenceNode {
+		for s, step := range steps.Content {
+			if uses := mappingValue(resolveAlias(step), "uses"); uses != nil {
+				lines[usesPosition{compositeJob, s}] = uses.Line
+			}
+		}
+	}
+
+	jobs := mappingValue(doc.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return lines
+	}
+
+	for _, jobName := range mappingKeys(jobs) {
+		job := mappingValue(jobs, jobName)
+
+		if uses := mappingValue(job, "uses"); uses != nil {
+			lines[usesPosition{jobName, -1}] = uses.Line
+		}
+
+		steps := mappingValue(job, "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for s, step := range steps.Content {
+			if uses := mappingValue(resolveAlias(step), "uses"); uses != nil {
+				lines[usesPosition{jobName, s}] = uses.Line
+			}
+		}
+	}
+
+	return lines
+}
+
+// mappingValue returns the value node of a key in a mapping node, or nil. Keys the mapping
+// inherits through merge keys are found too, with its own keys taking precedence.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !isMergeKey(node.Content[i]) && node.Content[i].Value == key {
+			return resolveAlias(node.Content[i+1])
+		}
+	}
+	for _, merged := range mergedMappings(node) {
+		if value := mappingValue(merged, key); value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// mappingKeys returns the keys of a mapping node, including those inherited through merge keys,
+// in document order
+func mappingKeys(node *yaml.Node) []string {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !isMergeKey(node.Content[i]) {
+			add(node.Content[i].Value)
+		}
+	}
+	for _, merged := range mergedMappings(node) {
+		for _, key := range mappingKeys(merged) {
+			add(key)
+		}
+	}
+	return keys
+}
+
+// mergedMappings returns the mappings merged into a mapping node through "<<" keys, in the
+// order they take precedence
+func mergedMappings(node *yaml.Node) []*yaml.Node {
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !isMergeKey(node.Content[i]) {
+			continue
+		}
+		value := resolveAlias(node.Content[i+1])
+		switch value.Kind {
+		case yaml.MappingNode:
+			merged = append(merged, value)
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				merged = append(merged, resolveAlias(item))
+			}
+		}
+	}
+	return merged
+}
+
+// isMergeKey reports whether a mapping key is the "<<" merge key
+func isMergeKey(key *yaml.Node) bool {
+	return key.Kind == yaml.ScalarNode && key.Value == "<<" && (key.Tag == "!!merge" || key.Tag == "")
+}
+
+// resolveAlias returns the node an alias refers to, or the node itself
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.AliasNode {
+		return node.Alias
+	}
+	return node
 }
@@ -15,6 +15,12 @@ func TestGetActions(t *testing.T) {
 		// Debug the received path
 		t.Logf("Mock server received request to path: %s", r.URL.Path)
 
+		// Refuse tree requests by ref and path as Gitea does, falling back to the contents API
+		if r.URL.Path == "/repos/owner/repo/git/trees/HEAD:.github" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
 		// Handle request for workflow directory listing
 		if r.URL.Path == "/repos/owner/repo/contents/.github/workflows" {
 			fmt.Fprint(w, `[
@@ -92,3 +98,103 @@ func TestExtractActionsFromWorkflow(t *testing.T) {
 		t.Errorf("Expected action name to be 'Setup Node', got %q", actions[1].Name)
 	}
 }
+
+func TestExtractActionsInputs(t *testing.T) {
+	files := []WorkflowFile{{
+		Name: "deploy.yml",
+		Path: ".github/workflows/deploy.yml",
+		Content: []byte(`
+jobs:
+  deploy:
+    steps:
+      - uses: aws-actions/configure-aws-credentials@v4
+        with:
+          role-to-assume: arn:aws:iam::123456789012:role/deploy
+          aws-region: us-east-1
+`),
+	}}
+
+	actions := ExtractActions(files)
+	if len(actions) != 1 {
+		t.Fatalf("Expected 1 action, got %d", len(actions))
+	}
+
+	action := actions[0]
+	if action.Workflow != ".github/workflows/deploy.yml" || action.Job != "deploy" {
+		t.Errorf("Expected workflow and job to be recorded, got %q / %q", action.Workflow, action.Job)
+	}
+	if action.With["role-to-assume"] != "arn:aws:iam::123456789012:role/deploy" || action.With["aws-region"] != "us-east-1" {
+		t.Errorf("Unexpected step inputs: %v", action.With)
+	}
+}
+
+func TestExtractActionsComments(t *testing.T) {
+	files := []WorkflowFile{{
+		Name: "ci.yml",
+		Path: ".github/workflows/ci.yml",
+		Content: []byte(`
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2
+      - name: Setup
+        uses: "actions/setup-go@v5"   # pinned by tag
+      - uses: actions/cache@v4
+`),
+	}}
+
+	actions := ExtractActions(files)
+	if len(actions) != 3 {
+		t.Fatalf("Expected 3 actions, got %d", len(actions))
+	}
+
+	expected := map[string]string{
+		"actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683": "v4.2.2",
+		"actions/setup-go@v5": "pinned by tag",
+		"actions/cache@v4":    "",
+	}
+	for _, action := range actions {
+		if action.Comment != expected[action.Uses] {
+			t.Errorf("Expected comment %q for %s, got %q", expected[action.Uses], action.Uses, action.Comment)
+		}
+	}
+}
+
+func TestExtractActionsLines(t *testing.T) {
+	files := []WorkflowFile{{
+		Name: "ci.yml",
+		Path: ".github/workflows/ci.yml",
+		Content: []byte(`name: CI
+on: push
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@v4
+      - run: make
+      - name: Cache
+        uses: actions/cache@v4
+  release:
+    uses: org/workflows/.github/workflows/release.yml@main
+  again:
+    steps:
+      - uses: actions/checkout@v4
+`),
+	}}
+
+	expected := map[string]int{
+		"build/actions/checkout@v4":                                6,
+		"build/actions/cache@v4":                                   9,
+		"release/org/workflows/.github/workflows/release.yml@main": 11,
+		"again/actions/checkout@v4":                                14,
+	}
+
+	actions := ExtractActions(files)
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected %d actions, got %d", len(expected), len(actions))
+	}
+	for _, action := range actions {
+		if line := expected[action.Job+"/"+action.Uses]; action.Line != line {
+			t.Errorf("Expected %s in job %s on line %d, got %d", action.Uses, action.Job, line, action.Line)
+		}
+	}
+}
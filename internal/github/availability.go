@@ -0,0 +1,112 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ActionAvailability describes whether the repository and version of an action reference can
+// still be resolved
+type ActionAvailability struct {
+	Repository string `json:"repository"`           // Repository as referenced, such as "actions/checkout"
+	Found      bool   `json:"found"`                // The repository exists and is accessible to the token
+	RenamedTo  string `json:"renamed_to,omitempty"` // Current name of a renamed or transferred repository
+	RefFound   bool   `json:"ref_found"`            // The referenced branch, tag or commit exists
+	Ref        string `json:"ref,omitempty"`        // Referenced branch, tag or commit
+	OwnerFound bool   `json:"owner_found"`          // The referenced owner account still exists
+}
+
+// GetActionAvailability checks that the repository publishing an action exists, has not been
+// renamed or transferred, and still has the referenced ref. Repositories that were deleted or
+// made private are reported as not found rather than as an error. When the repository is not
+// found or has moved to another owner, the referenced owner account is looked up too, as a
+// renamed or deleted account frees its name to be registered by anyone. Results are cached for
+// the lifetime of the client.
+func (c *Client) GetActionAvailability(ctx context.Context, owner, repo, ref string) (ActionAvailability, error) {
+	key := owner + "/" + repo + "@" + ref
+	c.mu.Lock()
+	availability, ok := c.availability[key]
+	c.mu.Unlock()
+	if ok {
+		return availability, nil
+	}
+
+	availability = ActionAvailability{Repository: owner + "/" + repo, Ref: ref}
+	repository, resp, err := c.client.Repositories.Get(ctx, owner, repo)
+	switch {
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+		if availability.OwnerFound, err = c.accountExists(ctx, owner); err != nil {
+			return ActionAvailability{}, err
+		}
+	case err != nil:
+		return ActionAvailability{}, fmt.Errorf("failed to get repository %s/%s: %w", owner, repo, err)
+	default:
+		availability.Found = true
+		// Requests for a renamed repository are redirected to its current name
+		if fullName := repository.GetFullName(); fullName != "" && !strings.EqualFold(fullName, availability.Repository) {
+			availability.RenamedTo = fullName
+		}
+
+		// A repository transferred away from a renamed or deleted account leaves the old name
+		// unclaimed
+		availability.OwnerFound = true
+		if newOwner, _, _ := strings.Cut(availability.RenamedTo, "/"); newOwner != "" && !strings.EqualFold(newOwner, owner) {
+			if availability.OwnerFound, err = c.accountExists(ctx, owner); err != nil {
+				return ActionAvailability{}, err
+			}
+		}
+
+		availability.RefFound = ref == ""
+		if ref != "" {
+			_, resp, err := c.client.Repositories.GetCommitSHA1(ctx, owner, repo, ref, "")
+			switch {
+			case resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity):
+			case err != nil:
+				return ActionAvailability{}, fmt.Errorf("failed to resolve %s for %s/%s: %w", ref, owner, repo, err)
+			default:
+				availability.RefFound = true
+			}
+		}
+	}
+
+	c.mu.Lock()
+	if c.availability == nil {
+		c.availability = make(map[string]ActionAvailability)
+	}
+	c.availability[key] = availability
+	c.mu.Unlock()
+
+	return availability, nil
+}
+
+// accountExists reports whether a user or organization account exists under a login. Results
+// are cached for the lifetime of the client.
+func (c *Client) accountExists(ctx context.Context, login string) (bool, error) {
+	c.mu.Lock()
+	exists, ok := c.accounts[login]
+	c.mu.Unlock()
+	if ok {
+		return exists, nil
+	}
+
+	_, resp, err := c.client.Users.Get(ctx, login)
+	switch {
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+		exists = false
+	case err != nil:
+		return false, fmt.Errorf("failed to get account %s: %w", login, err)
+	default:
+		exists = true
+	}
+
+	c.mu.Lock()
+	if c.accounts == nil {
+		c.accounts = make(map[string]bool)
+	}
+	c.accounts[login] = exists
+	c.mu.Unlock()
+
+	return exists, nil
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetActionAvailability(t *testing.T) {
+	requests := 0
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/actions/checkout":
+			fmt.Fprint(w, `{"full_name": "actions/checkout"}`)
+		case "/repos/actions/checkout/commits/v4":
+			fmt.Fprint(w, "0123456789abcdef0123456789abcdef01234567")
+		case "/repos/actions/checkout/commits/v0":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"message": "No commit found for SHA: v0"}`)
+		case "/repos/old-owner/action":
+			// Renamed repositories are served under their current name
+			fmt.Fprint(w, `{"full_name": "new-owner/action"}`)
+		case "/repos/old-owner/action/commits/main":
+			fmt.Fprint(w, "0123456789abcdef0123456789abcdef01234567")
+		case "/repos/moved-owner/action":
+			fmt.Fprint(w, `{"full_name": "current-owner/action"}`)
+		case "/repos/moved-owner/action/commits/v1":
+			fmt.Fprint(w, "0123456789abcdef0123456789abcdef01234567")
+		case "/users/old-owner", "/users/gone-repo":
+			fmt.Fprint(w, `{"login": "`+strings.TrimPrefix(r.URL.Path, "/users/")+`"}`)
+		case "/repos/broken/action":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message": "Server Error"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		owner, repo, ref string
+		expected         ActionAvailability
+	}{
+		{"actions", "checkout", "v4", ActionAvailability{Repository: "actions/checkout", Ref: "v4", Found: true, RefFound: true, OwnerFound: true}},
+		{"actions", "checkout", "v0", ActionAvailability{Repository: "actions/checkout", Ref: "v0", Found: true, OwnerFound: true}},
+		{"actions", "checkout", "", ActionAvailability{Repository: "actions/checkout", Found: true, RefFound: true, OwnerFound: true}},
+		// Transferred away from an account that still exists
+		{"old-owner", "action", "main", ActionAvailability{Repository: "old-owner/action", Ref: "main", Found: true, RenamedTo: "new-owner/action", RefFound: true, OwnerFound: true}},
+		// Transferred away from a renamed account, whose old name is free
+		{"moved-owner", "action", "v1", ActionAvailability{Repository: "moved-owner/action", Ref: "v1", Found: true, RenamedTo: "current-owner/action", RefFound: true}},
+		// Deleted repository of an existing account
+		{"gone-repo", "action", "v1", ActionAvailability{Repository: "gone-repo/action", Ref: "v1", OwnerFound: true}},
+		// Deleted account
+		{"deleted", "action", "v1", ActionAvailability{Repository: "deleted/action", Ref: "v1"}},
+	}
+	for _, tt := range tests {
+		availability, err := client.GetActionAvailability(ctx, tt.owner, tt.repo, tt.ref)
+		if err != nil {
+			t.Fatalf("GetActionAvailability(%s/%s@%s) error = %v", tt.owner, tt.repo, tt.ref, err)
+		}
+		if availability != tt.expected {
+			t.Errorf("GetActionAvailability(%s/%s@%s) = %+v, want %+v", tt.owner, tt.repo, tt.ref, availability, tt.expected)
+		}
+	}
+
+	// Results are cached
+	before := requests
+	if _, err := client.GetActionAvailability(ctx, "actions", "checkout", "v4"); err != nil {
+		t.Fatalf("GetActionAvailability() error = %v", err)
+	}
+	if requests != before {
+		t.Errorf("Expected cached availability, got %d new requests", requests-before)
+	}
+
+	// Other errors are not mistaken for missing repositories
+	if _, err := client.GetActionAvailability(ctx, "broken", "action", "v1"); err == nil {
+		t.Error("Expected error for a server error")
+	}
+}
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// Branch is a repository branch and the commit at its head
+type Branch struct {
+	Name string
+	SHA  string
+}
+
+// ListBranches returns a repository's branches
+func (c *Client) ListBranches(ctx context.Context, owner, repo string) ([]Branch, error) {
+	opts := &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+
+	var result []Branch
+	for {
+		branches, resp, err := c.client.Repositories.ListBranches(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+
+		for _, branch := range branches {
+			result = append(result, Branch{Name: branch.GetName(), SHA: branch.GetCommit().GetSHA()})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// addBranchWorkflowFiles appends the workflow files of branches matching the branch pattern
+// that are not identical to a file already collected, so unchanged workflows are reported once
+func (c *Client) addBranchWorkflowFiles(ctx context.Context, owner, repo string, files []WorkflowFile) ([]WorkflowFile, error) {
+	branches, err := c.ListBranches(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range files {
+		seen[file.SHA] = true
+	}
+
+	for _, branch := range branches {
+		if ok, _ := path.Match(c.branches, branch.Name); !ok {
+			continue
+		}
+
+		// Address the tree by commit, as branch names may contain slashes
+		branchFiles, err := c.getNewWorkflowFilesFromTree(ctx, owner, repo, branch.SHA, seen)
+		if err != nil {
+			continue // Branches without workflows have no .github tree
+		}
+		for i := range branchFiles {
+			branchFiles[i].Ref = branch.Name
+		}
+		files = append(files, branchFiles...)
+	}
+
+	return files, nil
+}
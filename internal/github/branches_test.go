@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetWorkflowFilesBranches(t *testing.T) {
+	changed := "name: Changed\non: push\njobs:\n  build:\n    steps:\n      - uses: evil/action@v1\n"
+
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/owner/repo/git/trees/HEAD:.github":
+			fmt.Fprint(w, `{"sha": "t0", "tree": [{"path": "workflows/ci.yml", "type": "blob", "sha": "b1"}]}`)
+		case "/repos/owner/repo/branches":
+			fmt.Fprint(w, `[
+				{"name": "main", "commit": {"sha": "c0"}},
+				{"name": "feature/x", "commit": {"sha": "c1"}},
+				{"name": "release", "commit": {"sha": "c2"}}
+			]`)
+		case "/repos/owner/repo/git/trees/c0:.github":
+			fmt.Fprint(w, `{"sha": "t0", "tree": [{"path": "workflows/ci.yml", "type": "blob", "sha": "b1"}]}`)
+		case "/repos/owner/repo/git/trees/c1:.github":
+			fmt.Fprint(w, `{"sha": "t1", "tree": [
+				{"path": "workflows/ci.yml", "type": "blob", "sha": "b1"},
+				{"path": "workflows/new.yml", "type": "blob", "sha": "b2"}
+			]}`)
+		case "/repos/owner/repo/git/blobs/b1":
+			fmt.Fprint(w, CreateMockWorkflowContent())
+		case "/repos/owner/repo/git/blobs/b2":
+			fmt.Fprint(w, changed)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	// Only the default branch is scanned without a pattern
+	files, err := client.GetWorkflowFiles(ctx, "owner", "repo")
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected 1 default branch file, got %d (err: %v)", len(files), err)
+	}
+
+	if err := client.SetBranches("feature/*"); err != nil {
+		t.Fatalf("SetBranches returned error: %v", err)
+	}
+
+	files, err = client.GetWorkflowFiles(ctx, "owner", "repo")
+	if err != nil {
+		t.Fatalf("GetWorkflowFiles returned error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected unchanged workflow to be reported once plus the new branch file, got %d", len(files))
+	}
+	if files[0].Ref != "" || files[1].Ref != "feature/x" || files[1].Path != ".github/workflows/new.yml" {
+		t.Errorf("Unexpected files: %+v / %+v", files[0], files[1])
+	}
+
+	actions := ExtractActions(files)
+	if last := actions[len(actions)-1]; last.Uses != "evil/action@v1" || last.Ref != "feature/x" {
+		t.Errorf("Expected branch action to carry its branch, got %+v", last)
+	}
+
+	if err := client.SetBranches("["); err == nil {
+		t.Error("Expected error for invalid pattern, got nil")
+	}
+}
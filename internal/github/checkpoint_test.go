@@ -0,0 +1,108 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeCheckpoint keeps checkpointed repositories in memory
+type fakeCheckpoint struct {
+	mu        sync.Mutex
+	scanned   map[string][]WorkflowFile
+	recorded  []string
+	completed bool
+}
+
+func (f *fakeCheckpoint) Scanned(repo string) ([]WorkflowFile, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	files, ok := f.scanned[repo]
+	return files, ok
+}
+
+func (f *fakeCheckpoint) Record(repo string, files []WorkflowFile) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scanned[repo] = files
+	f.recorded = append(f.recorded, repo)
+	return nil
+}
+
+func (f *fakeCheckpoint) Complete() error {
+	f.completed = true
+	return nil
+}
+
+func TestWorkflowFilesForOrgCheckpoint(t *testing.T) {
+	rateLimited := false
+
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/orgs/org/repos":
+			fmt.Fprint(w, `[{"name": "one", "full_name": "org/one"}, {"name": "two", "full_name": "org/two"}, {"name": "three", "full_name": "org/three"}]`)
+		case rateLimited && strings.HasPrefix(r.URL.Path, "/repos/org/three/"):
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "4102444800")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+		case strings.HasSuffix(r.URL.Path, "/git/trees/HEAD:.github"):
+			fmt.Fprint(w, `{"sha": "t0", "tree": [{"path": "workflows/ci.yml", "type": "blob", "sha": "b1"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/git/blobs/b1"):
+			fmt.Fprint(w, CreateMockWorkflowContent())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	// Repositories restored from the checkpoint are not scanned again
+	checkpoint := &fakeCheckpoint{scanned: map[string][]WorkflowFile{
+		"org/one": {{Name: "restored.yml", Path: ".github/workflows/restored.yml"}},
+	}}
+	client.SetCheckpoint(checkpoint)
+
+	result, err := client.WorkflowFilesForOrg(ctx, "org")
+	if err != nil {
+		t.Fatalf("WorkflowFilesForOrg returned error: %v", err)
+	}
+	if len(result) != 3 || result["org/one"][0].Name != "restored.yml" {
+		t.Errorf("Expected restored and scanned repositories, got %+v", result)
+	}
+	if strings.Join(checkpoint.recorded, ",") != "org/two,org/three" {
+		t.Errorf("Expected newly scanned repositories to be recorded, got %v", checkpoint.recorded)
+	}
+	if !checkpoint.completed {
+		t.Error("Expected checkpoint to be completed")
+	}
+
+	// Rate limit exhaustion stops the scan so it can resume later
+	rateLimited = true
+	checkpoint = &fakeCheckpoint{scanned: map[string][]WorkflowFile{}}
+	client.SetCheckpoint(checkpoint)
+
+	if _, err := client.WorkflowFilesForOrg(ctx, "org"); err == nil || !strings.Contains(err.Error(), "after 2 of 3 repositories") {
+		t.Errorf("Expected rate limit error after 2 repositories, got %v", err)
+	}
+	if len(checkpoint.recorded) != 2 || checkpoint.completed {
+		t.Errorf("Expected 2 recorded repositories and an incomplete checkpoint, got %v (completed: %v)", checkpoint.recorded, checkpoint.completed)
+	}
+
+	// Without a checkpoint, rate limited repositories are skipped as before
+	server, client = MockServer(t, mockHandler)
+	defer server.Close()
+	result, err = client.WorkflowFilesForOrg(ctx, "org")
+	if err != nil || len(result) != 2 {
+		t.Errorf("Expected rate limited repository to be skipped, got %d repositories (err: %v)", len(result), err)
+	}
+}
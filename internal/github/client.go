@@ -3,32 +3,163 @@ package github
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
+	"path"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/codeowners"
 
 	"github.com/google/go-github/v70/github"
 	"golang.org/x/oauth2"
 )
 
+// DefaultWorkflowsPath is the directory GitHub reads workflow files from
+const DefaultWorkflowsPath = ".github/workflows"
+
 // Client provides access to GitHub API
 type Client struct {
-	client *github.Client
-	token  string
+	client      *github.Client
+	token       string
+	requests    *int64          // Number of API requests issued
+	observer    ScanObserver    // Receives progress notifications during organization scans
+	branches    string          // Pattern of additional branches to scan; empty for the default branch only
+	runsSince   time.Time       // Discover workflow files from runs created since then; zero to read the repository
+	workflows   string          // Directory of workflow files; empty for .github/workflows
+	scanPaths   []string        // Directories searched for composite action.yml files
+	checkpoint  ScanCheckpoint  // Records organization scan progress; nil when not checkpointing
+	repoCache   RepositoryCache // Keeps organization repository listings; nil when not caching
+	concurrency int             // Number of repositories fetched in parallel during organization scans
+	downloads   *http.Client    // Follows release asset redirects without sending the token
+
+	mu           sync.Mutex
+	verified     map[string]bool               // Cached verified creator status by owner
+	metadata     map[string]RepositoryMetadata // Cached repository metadata by owner/repo
+	runtimes     map[string]ActionRuntime      // Cached action runtimes by action reference
+	availability map[string]ActionAvailability // Cached availability by owner/repo@ref
+	accounts     map[string]bool               // Cached account existence by login
 }
 
-// NewClient creates a new GitHub client with the provided token
+// NewClient creates a new GitHub client with the provided token. Without a token, requests
+// are unauthenticated, which only suits public data such as releases.
 func NewClient(token string) *Client {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+	// The default transport cannot fail to build
+	client, _ := NewClientWithHTTPConfig(token, HTTPConfig{})
+	return client
+}
+
+// NewClientWithHTTPConfig creates a new GitHub client with the provided token whose requests
+// use the given proxy, CA bundle, timeout and retry settings
+func NewClientWithHTTPConfig(token string, httpConfig HTTPConfig) (*Client, error) {
+	transport, err := httpConfig.transport()
+	if err != nil {
+		return nil, err
+	}
+	return newClientWithTransport(token, transport), nil
+}
+
+// newClientWithTransport creates a new GitHub client with the provided token whose requests
+// use the given transport
+func newClientWithTransport(token string, transport http.RoundTripper) *Client {
+	tc := &http.Client{Transport: transport}
+	if token != "" {
+		ts := oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		)
+		tc = &http.Client{Transport: &oauth2.Transport{Source: ts, Base: transport}}
+	}
+
+	// Count requests so scans can report API usage
+	requests := new(int64)
+	tc.Transport = &countingTransport{base: tc.Transport, count: requests}
 
 	return &Client{
-		client: github.NewClient(tc),
-		token:  token,
+		client:    github.NewClient(tc),
+		token:     token,
+		requests:  requests,
+		observer:  noopObserver{},
+		downloads: &http.Client{Transport: transport},
+	}
+}
+
+// SetObserver registers an observer for organization scan progress
+func (c *Client) SetObserver(observer ScanObserver) {
+	if observer == nil {
+		observer = noopObserver{}
+	}
+	c.observer = observer
+}
+
+// SetBranches sets a glob pattern of branches whose workflow files are scanned in addition to
+// the default branch, such as "*" for all branches or "release/*"
+func (c *Client) SetBranches(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+	}
+	c.branches = pattern
+	return nil
+}
+
+// SetWorkflowsPath sets the directory workflow files are read from, such as .gitea/workflows,
+// instead of .github/workflows
+func (c *Client) SetWorkflowsPath(dir string) error {
+	dir = path.Clean(strings.Trim(dir, "/"))
+	if dir == "." || strings.HasPrefix(dir, "..") {
+		return fmt.Errorf("invalid workflows path %q", dir)
+	}
+	c.workflows = dir
+	return nil
+}
+
+// SetScanPaths sets directories, such as .github/actions, searched for composite action.yml
+// files whose steps are scanned along with the workflow files
+func (c *Client) SetScanPaths(dirs []string) error {
+	c.scanPaths = nil
+	for _, dir := range dirs {
+		dir = path.Clean(strings.Trim(dir, "/"))
+		if dir == "." || strings.HasPrefix(dir, "..") {
+			return fmt.Errorf("invalid scan path %q", dir)
+		}
+		c.scanPaths = append(c.scanPaths, dir)
+	}
+	return nil
+}
+
+// workflowsPath returns the directory workflow files are read from
+func (c *Client) workflowsPath() string {
+	if c.workflows == "" {
+		return DefaultWorkflowsPath
+	}
+	return c.workflows
+}
+
+// SetCheckpoint registers a checkpoint that organization scans restore scanned repositories
+// from and record their progress to
+func (c *Client) SetCheckpoint(checkpoint ScanCheckpoint) {
+	c.checkpoint = checkpoint
+}
+
+// SetRepositoryCache registers a cache that organization repository listings are read from
+// and stored in
+func (c *Client) SetRepositoryCache(cache RepositoryCache) {
+	c.repoCache = cache
+}
+
+// SetConcurrency sets how many repositories organization scans fetch in parallel
+func (c *Client) SetConcurrency(n int) {
+	c.concurrency = n
+}
+
+// RequestCount returns the number of API requests issued by the client
+func (c *Client) RequestCount() int64 {
+	if c.requests == nil {
+		return 0
 	}
+	return atomic.LoadInt64(c.requests)
 }
 
 // GetRepositoryContent retrieves file content from a repository
@@ -66,32 +197,104 @@ func (c *Client) GetRepositoryContent(ctx context.Context, owner, repo, path str
 	return content, nil
 }
 
-// ActionsForOrg retrieves all actions used across an organization's repositories
+// ActionsForOrg retrieves all actions used across an organization's repositories. When the
+// scan is interrupted, the actions of the repositories scanned so far are returned with a
+// *ScanInterruptedError.
 func (c *Client) ActionsForOrg(ctx context.Context, org string) (map[string][]Action, error) {
-	repos, err := c.ListRepositories(ctx, org)
-	if err != nil {
+	filesMap, err := c.WorkflowFilesForOrg(ctx, org)
+	if filesMap == nil {
 		return nil, err
 	}
 
 	result := make(map[string][]Action)
+	for repo, files := range filesMap {
+		result[repo] = ExtractActions(files)
+	}
 
-	for _, repo := range repos {
-		parts := strings.Split(repo.FullName, "/")
-		if len(parts) != 2 {
-			continue
+	return result, err
+}
+
+// WorkflowFilesForOrg retrieves workflow file content across an organization's repositories.
+// When the scan is interrupted, the files of the repositories scanned so far are returned with
+// a *ScanInterruptedError.
+func (c *Client) WorkflowFilesForOrg(ctx context.Context, org string) (map[string][]WorkflowFile, error) {
+	result := make(map[string][]WorkflowFile)
+	err := c.StreamWorkflowFilesForOrg(ctx, org, func(repo string, files []WorkflowFile) error {
+		result[repo] = files
+		return nil
+	})
+	var interrupted *ScanInterruptedError
+	if errors.As(err, &interrupted) {
+		return result, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// IsNotFound reports whether err was caused by a resource, such as a file or repository, that
+// does not exist or is not visible to the token
+func IsNotFound(err error) bool {
+	var errorResponse *github.ErrorResponse
+	return errors.As(err, &errorResponse) && errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusNotFound
+}
+
+// isRateLimitError reports whether err was caused by exhausting the primary or secondary rate limit
+func isRateLimitError(err error) bool {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr)
+}
+
+// GetCodeOwners retrieves and parses the repository's CODEOWNERS file.
+// It returns nil when the repository has no CODEOWNERS file.
+func (c *Client) GetCodeOwners(ctx context.Context, owner, repo string) *codeowners.Ruleset {
+	for _, location := range codeowners.Locations {
+		content, err := c.GetRepositoryContent(ctx, owner, repo, location)
+		if err == nil && len(content) > 0 {
+			return codeowners.Parse(content)
 		}
+	}
+	return nil
+}
 
-		owner := parts[0]
-		repoName := parts[1]
+// GetRepositoryFiles retrieves those of the given files that exist on the default branch. Each
+// directory holding one of the paths is listed once, and only the files found are requested,
+// rather than requesting every path.
+func (c *Client) GetRepositoryFiles(ctx context.Context, owner, repo string, paths []string) (map[string][]byte, error) {
+	listed := make(map[string]map[string]bool)
+	files := make(map[string][]byte)
 
-		actions, err := c.GetActions(ctx, owner, repoName)
-		if err != nil {
-			// Log error but continue with other repositories
+	for _, filePath := range paths {
+		dir := path.Dir(filePath)
+		if dir == "." {
+			dir = ""
+		}
+
+		if listed[dir] == nil {
+			listed[dir] = make(map[string]bool)
+			_, entries, resp, err := c.client.Repositories.GetContents(ctx, owner, repo, dir, nil)
+			if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+				return nil, fmt.Errorf("failed to list %s/%s:%s: %w", owner, repo, dir, err)
+			}
+			for _, entry := range entries {
+				if entry.GetType() == "file" {
+					listed[dir][entry.GetPath()] = true
+				}
+			}
+		}
+		if !listed[dir][filePath] {
 			continue
 		}
 
-		result[repo.FullName] = actions
+		content, err := c.GetRepositoryContent(ctx, owner, repo, filePath)
+		if err != nil {
+			return nil, err
+		}
+		files[filePath] = content
 	}
 
-	return result, nil
+	return files, nil
 }
@@ -1,6 +1,7 @@
 package github
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -17,8 +18,9 @@ func MockServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *Clie
 	// Create a test server with the provided handler
 	server := httptest.NewServer(handler)
 
-	// Create a GitHub client
-	httpClient := &http.Client{}
+	// Create a GitHub client that counts requests made to the mock server
+	requests := new(int64)
+	httpClient := &http.Client{Transport: &countingTransport{count: requests}}
 
 	// Create a new GitHub API client
 	githubClient := github.NewClient(httpClient)
@@ -32,8 +34,10 @@ func MockServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *Clie
 
 	// Create our client wrapper around the GitHub client
 	client := &Client{
-		client: githubClient,
-		token:  "mock-token",
+		client:   githubClient,
+		token:    "mock-token",
+		requests: requests,
+		observer: noopObserver{},
 	}
 
 	return server, client
@@ -82,3 +86,28 @@ func CreateMockRepositoriesResponse(repos []Repository) string {
 	// Join all repository JSON objects with commas
 	return "[" + strings.Join(responseItems, ",") + "]"
 }
+
+func TestGetRepositoryFiles(t *testing.T) {
+	client := NewFixtureClient(&Fixture{Repositories: []FixtureRepository{{
+		FullName: "org/repo",
+		Files: map[string]string{
+			".github/CODEOWNERS":       "* @org/team\n",
+			".github/workflows/ci.yml": "on: push\n",
+			"renovate.json":            "{}\n",
+		},
+	}}})
+
+	paths := []string{".github/CODEOWNERS", ".github/dependabot.yml", "CODEOWNERS", "renovate.json", "docs/CODEOWNERS"}
+	files, err := client.GetRepositoryFiles(context.Background(), "org", "repo", paths)
+	if err != nil {
+		t.Fatalf("GetRepositoryFiles() error = %v", err)
+	}
+	if len(files) != 2 || string(files[".github/CODEOWNERS"]) != "* @org/team\n" || string(files["renovate.json"]) != "{}\n" {
+		t.Errorf("Expected CODEOWNERS and renovate.json, got %v", files)
+	}
+
+	// Three directory listings and two file requests
+	if requests := client.RequestCount(); requests != 5 {
+		t.Errorf("Expected 5 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,138 @@
+package github
+
+import (
+	"context"
+	"math"
+	"path"
+	"strings"
+	"time"
+)
+
+// estimateSampleSize is the number of repositories sampled to predict the cost of a scan
+const estimateSampleSize = 5
+
+// ScanEstimate predicts the API requests a scan will make before it fetches workflow files
+type ScanEstimate struct {
+	Repositories     int       `json:"repositories"`
+	Sampled          int       `json:"sampled"`                  // Repositories inspected to predict the cost per repository
+	WorkflowsPerRepo float64   `json:"workflows_per_repository"` // Average workflow files in the sampled repositories
+	RequestsPerRepo  float64   `json:"requests_per_repository"`
+	Requests         int       `json:"requests"`          // Predicted requests of the scan
+	EstimateRequests int64     `json:"estimate_requests"` // Requests spent on the estimate itself
+	Limit            int       `json:"limit,omitempty"`   // Hourly request limit of the token, when known
+	Remaining        int       `json:"remaining,omitempty"`
+	Reset            time.Time `json:"reset"`
+	RateLimitKnown   bool      `json:"rate_limit_known"`
+}
+
+// Sufficient reports whether the token's remaining quota covers the predicted requests.
+// It is true when the rate limit is unknown, as with providers that do not report one.
+func (e ScanEstimate) Sufficient() bool {
+	return !e.RateLimitKnown || e.Requests <= e.Remaining
+}
+
+// EstimateScan predicts the requests a scan of an organization, or of a single repository
+// when repo is set, will make. It lists the repositories and inspects a few evenly spaced
+// ones to learn how many workflow files and matching branches a repository has, then adds
+// extraPerRepo requests for the checks run on every repository.
+func (c *Client) EstimateScan(ctx context.Context, org, repo string, extraPerRepo int) (ScanEstimate, error) {
+	started := c.RequestCount()
+	var estimate ScanEstimate
+
+	var names []string
+	if repo != "" {
+		names = []string{repo}
+	} else {
+		repos, err := c.ListRepositories(ctx, org)
+		if err != nil {
+			return estimate, err
+		}
+		for _, r := range repos {
+			names = append(names, r.FullName)
+		}
+	}
+	estimate.Repositories = len(names)
+	listRequests := c.RequestCount() - started
+
+	// Inspect evenly spaced repositories, skipping those without accessible workflows
+	var workflows, branches int
+	for _, name := range sampleRepositories(names, estimateSampleSize) {
+		owner, repoName, _ := strings.Cut(name, "/")
+		count, err := c.countWorkflowFiles(ctx, owner, repoName)
+		if err != nil {
+			continue
+		}
+		estimate.Sampled++
+		workflows += count
+
+		if c.branches != "" && c.runsSince.IsZero() {
+			repoBranches, err := c.ListBranches(ctx, owner, repoName)
+			if err == nil {
+				for _, branch := range repoBranches {
+					if ok, _ := path.Match(c.branches, branch.Name); ok {
+						branches++
+					}
+				}
+			}
+		}
+	}
+
+	if estimate.Sampled > 0 {
+		estimate.WorkflowsPerRepo = float64(workflows) / float64(estimate.Sampled)
+	}
+
+	// A tree or run listing request, then one request per workflow file
+	perRepo := 1 + estimate.WorkflowsPerRepo
+	if c.branches != "" && c.runsSince.IsZero() && estimate.Sampled > 0 {
+		// Listing branches, then a tree request per matching branch
+		perRepo += 1 + float64(branches)/float64(estimate.Sampled)
+	}
+	perRepo += float64(extraPerRepo)
+
+	estimate.RequestsPerRepo = perRepo
+	estimate.Requests = int(listRequests) + int(math.Ceil(perRepo*float64(estimate.Repositories)))
+
+	// The rate limit endpoint does not count against the limit
+	if limits, _, err := c.client.RateLimit.Get(ctx); err == nil && limits.GetCore() != nil {
+		core := limits.GetCore()
+		estimate.RateLimitKnown = true
+		estimate.Limit = core.Limit
+		estimate.Remaining = core.Remaining
+		estimate.Reset = core.Reset.Time
+	}
+
+	estimate.EstimateRequests = c.RequestCount() - started
+	return estimate, nil
+}
+
+// countWorkflowFiles returns the number of workflow files in a repository from its .github tree
+func (c *Client) countWorkflowFiles(ctx context.Context, owner, repo string) (int, error) {
+	tree, _, err := c.client.Git.GetTree(ctx, owner, repo, "HEAD:"+githubDir, true)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, entry := range tree.Entries {
+		entryPath := path.Join(githubDir, entry.GetPath())
+		name := path.Base(entryPath)
+		if entry.GetType() == "blob" && path.Dir(entryPath) == githubDir+"/workflows" &&
+			(strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// sampleRepositories returns up to n evenly spaced repositories
+func sampleRepositories(names []string, n int) []string {
+	if len(names) <= n {
+		return names
+	}
+
+	sample := make([]string, n)
+	for i := range sample {
+		sample[i] = names[i*len(names)/n]
+	}
+	return sample
+}
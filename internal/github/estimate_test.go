@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEstimateScan(t *testing.T) {
+	_, client := MockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/orgs/org/repos":
+			var repos []string
+			for i := 0; i < 10; i++ {
+				repos = append(repos, fmt.Sprintf(`{"name": "r%d", "full_name": "org/r%d"}`, i, i))
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(repos, ","))
+		case strings.HasSuffix(r.URL.Path, "/git/trees/HEAD:.github"):
+			fmt.Fprint(w, `{"sha": "abc", "tree": [
+				{"path": "workflows/ci.yml", "type": "blob"},
+				{"path": "workflows/release.yaml", "type": "blob"},
+				{"path": "workflows/README.md", "type": "blob"},
+				{"path": "dependabot.yml", "type": "blob"}
+			]}`)
+		case r.URL.Path == "/rate_limit":
+			fmt.Fprint(w, `{"resources": {"core": {"limit": 5000, "remaining": 30, "reset": 4102444800}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	estimate, err := client.EstimateScan(context.Background(), "org", "", 1)
+	if err != nil {
+		t.Fatalf("EstimateScan returned error: %v", err)
+	}
+
+	if estimate.Repositories != 10 || estimate.Sampled != 5 || estimate.WorkflowsPerRepo != 2 {
+		t.Errorf("Unexpected sample %+v", estimate)
+	}
+	// One listing request, then a tree, two workflow files and one extra request per repository
+	if estimate.RequestsPerRepo != 4 || estimate.Requests != 41 {
+		t.Errorf("Expected 4 requests per repository and 41 in total, got %+v", estimate)
+	}
+	if !estimate.RateLimitKnown || estimate.Remaining != 30 || estimate.Sufficient() {
+		t.Errorf("Expected the remaining quota to be insufficient, got %+v", estimate)
+	}
+	if estimate.EstimateRequests != 7 {
+		t.Errorf("Expected 7 requests spent on the estimate, got %d", estimate.EstimateRequests)
+	}
+}
+
+func TestSampleRepositories(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	if sample := sampleRepositories(names, 3); strings.Join(sample, ",") != "a,c,e" {
+		t.Errorf("Expected evenly spaced sample, got %v", sample)
+	}
+	if sample := sampleRepositories(names[:2], 3); len(sample) != 2 {
+		t.Errorf("Expected every repository of a small list, got %v", sample)
+	}
+}
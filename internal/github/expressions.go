@@ -0,0 +1,215 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxResolvedReferences bounds how many references a single uses value may expand to, so a
+// large matrix cannot blow up the action list
+const maxResolvedReferences = 64
+
+// UnresolvedReference is a uses value built from an expression that cannot be evaluated
+// statically, such as one reading step outputs or a matrix loaded with fromJSON
+type UnresolvedReference struct {
+	Uses     string `json:"uses"`
+	Workflow string `json:"workflow"`
+	Job      string `json:"job"`
+	Line     int    `json:"line,omitempty"`
+	Ref      string `json:"ref,omitempty"` // Branch of the workflow file; empty for the default branch
+	Reason   string `json:"reason"`
+}
+
+// ExtractUnresolvedReferences returns the uses values of already fetched workflow files that
+// are built from expressions extraction cannot evaluate. Files that cannot be parsed are skipped.
+func ExtractUnresolvedReferences(files []WorkflowFile) []UnresolvedReference {
+	var unresolved []UnresolvedReference
+	for _, file := range files {
+		_, references, err := parseWorkflowUses(file.Content, file.Name)
+		if err != nil {
+			continue
+		}
+		for i := range references {
+			references[i].Workflow = file.Path
+			references[i].Ref = file.Ref
+		}
+		unresolved = append(unresolved, references...)
+	}
+	return unresolved
+}
+
+// expressionPattern matches a ${{ ... }} expression
+var expressionPattern = regexp.MustCompile(`\$\{\{\s*(.*?)\s*\}\}`)
+
+// expressionScope holds the values static expressions in a uses value can read
+type expressionScope struct {
+	env    map[string]interface{} // Workflow, job and step env, later levels overriding earlier ones
+	matrix interface{}            // The job's strategy.matrix, nil without one
+}
+
+// newExpressionScope merges the env maps of a workflow, job and step
+func newExpressionScope(matrix interface{}, envs ...interface{}) expressionScope {
+	scope := expressionScope{env: make(map[string]interface{}), matrix: matrix}
+	for _, env := range envs {
+		if values, ok := env.(map[string]interface{}); ok {
+			for key, value := range values {
+				scope.env[key] = value
+			}
+		}
+	}
+	return scope
+}
+
+// resolveUses evaluates the expressions in a uses value and returns every reference it can
+// expand to, such as one per matrix value. Values without expressions are returned as is.
+func resolveUses(uses string, scope expressionScope) ([]string, error) {
+	matches := expressionPattern.FindAllStringSubmatchIndex(uses, -1)
+	if matches == nil {
+		return []string{uses}, nil
+	}
+
+	// Expand every expression in turn, keeping the literal text between them
+	resolved := []string{""}
+	last := 0
+	for _, match := range matches {
+		values, err := scope.evaluate(uses[match[2]:match[3]])
+		if err != nil {
+			return nil, err
+		}
+
+		prefix := uses[last:match[0]]
+		var expanded []string
+		for _, partial := range resolved {
+			for _, value := range values {
+				expanded = append(expanded, partial+prefix+value)
+			}
+		}
+		if len(expanded) > maxResolvedReferences {
+			return nil, fmt.Errorf("expands to more than %d references", maxResolvedReferences)
+		}
+		resolved = expanded
+		last = match[1]
+	}
+	for i := range resolved {
+		resolved[i] += uses[last:]
+	}
+
+	return dedupe(resolved), nil
+}
+
+// evaluate returns the possible values of a single static expression: a literal, an env
+// variable or a matrix property
+func (s expressionScope) evaluate(expression string) ([]string, error) {
+	if literal, ok := stringLiteral(expression); ok {
+		return []string{literal}, nil
+	}
+	if _, err := strconv.ParseFloat(expression, 64); err == nil || expression == "true" || expression == "false" {
+		return []string{expression}, nil
+	}
+
+	path := strings.Split(expression, ".")
+	switch {
+	case len(path) == 2 && path[0] == "env":
+		value, ok := s.env[path[1]]
+		if !ok {
+			return nil, fmt.Errorf("env.%s is not set in the workflow", path[1])
+		}
+		text, ok := scalar(value)
+		if !ok || strings.Contains(text, "${{") {
+			return nil, fmt.Errorf("env.%s is not static", path[1])
+		}
+		return []string{text}, nil
+	case len(path) >= 2 && path[0] == "matrix":
+		return s.matrixValues(path[1:])
+	}
+
+	return nil, fmt.Errorf("expression %q cannot be evaluated statically", expression)
+}
+
+// matrixValues returns every value a matrix property takes across the matrix and its include
+// entries. Exclude entries are ignored, so every value that can occur is returned.
+func (s expressionScope) matrixValues(path []string) ([]string, error) {
+	matrix, ok := s.matrix.(map[string]interface{})
+	if !ok {
+		if s.matrix == nil {
+			return nil, fmt.Errorf("the job has no matrix")
+		}
+		return nil, fmt.Errorf("the job's matrix is not static")
+	}
+
+	var candidates []interface{}
+	if values, ok := matrix[path[0]]; ok && path[0] != "include" && path[0] != "exclude" {
+		list, ok := values.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("matrix.%s is not static", path[0])
+		}
+		candidates = append(candidates, list...)
+	}
+	if includes, ok := matrix["include"].([]interface{}); ok {
+		for _, include := range includes {
+			if entry, ok := include.(map[string]interface{}); ok {
+				if value, ok := entry[path[0]]; ok {
+					candidates = append(candidates, value)
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("matrix.%s is not defined", path[0])
+	}
+
+	var values []string
+	for _, candidate := range candidates {
+		// Follow nested properties of object values, such as matrix.target.action
+		for _, key := range path[1:] {
+			object, ok := candidate.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("matrix.%s is not an object", strings.Join(path, "."))
+			}
+			candidate = object[key]
+		}
+		text, ok := scalar(candidate)
+		if !ok || strings.Contains(text, "${{") {
+			return nil, fmt.Errorf("matrix.%s is not static", strings.Join(path, "."))
+		}
+		values = append(values, text)
+	}
+
+	return dedupe(values), nil
+}
+
+// stringLiteral returns the value of a single-quoted expression string literal
+func stringLiteral(expression string) (string, bool) {
+	if len(expression) < 2 || expression[0] != '\'' || expression[len(expression)-1] != '\'' {
+		return "", false
+	}
+	return strings.ReplaceAll(expression[1:len(expression)-1], "''", "'"), true
+}
+
+// scalar returns the text of a string, number or boolean YAML value
+func scalar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case int, float64, bool:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// dedupe returns the distinct values in sorted order
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var distinct []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			distinct = append(distinct, value)
+		}
+	}
+	sort.Strings(distinct)
+	return distinct
+}
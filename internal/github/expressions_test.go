@@ -0,0 +1,113 @@
+package github
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestExpressionAwareExtraction(t *testing.T) {
+	content := []byte(`name: CI
+env:
+  SETUP_VERSION: v4
+jobs:
+  build:
+    strategy:
+      matrix:
+        language: [go, node]
+        include:
+          - language: python
+    env:
+      CACHE: actions/cache
+    steps:
+      - uses: actions/setup-${{ matrix.language }}@${{ env.SETUP_VERSION }}
+      - uses: ${{ env.CACHE }}@v4
+      - uses: actions/checkout@${{ 'v4' }}
+  targets:
+    strategy:
+      matrix:
+        target:
+          - action: org/deploy-aws@v1
+          - action: org/deploy-gcp@v1
+    steps:
+      - uses: ${{ matrix.target.action }}
+  dynamic:
+    strategy:
+      matrix: ${{ fromJSON(needs.setup.outputs.matrix) }}
+    steps:
+      - uses: org/${{ matrix.tool }}@v1
+      - uses: ${{ steps.pick.outputs.action }}
+      - uses: actions/upload-artifact@v4
+`)
+
+	actions, unresolved, err := parseWorkflowUses(content, "ci.yml")
+	if err != nil {
+		t.Fatalf("parseWorkflowUses returned error: %v", err)
+	}
+
+	var uses []string
+	for _, action := range actions {
+		uses = append(uses, action.Uses)
+	}
+	expected := []string{
+		"actions/cache@v4",
+		"actions/checkout@v4",
+		"actions/setup-go@v4",
+		"actions/setup-node@v4",
+		"actions/setup-python@v4",
+		"actions/upload-artifact@v4",
+		"org/deploy-aws@v1",
+		"org/deploy-gcp@v1",
+	}
+	sort.Strings(uses)
+	if !reflect.DeepEqual(uses, expected) {
+		t.Errorf("Resolved uses = %v, want %v", uses, expected)
+	}
+
+	if len(unresolved) != 2 {
+		t.Fatalf("Expected 2 unresolved references, got %+v", unresolved)
+	}
+	for _, reference := range unresolved {
+		if reference.Job != "dynamic" || reference.Line == 0 {
+			t.Errorf("Unexpected unresolved reference %+v", reference)
+		}
+	}
+
+	files := []WorkflowFile{{Name: "ci.yml", Path: ".github/workflows/ci.yml", Ref: "main", Content: content}}
+	references := ExtractUnresolvedReferences(files)
+	if len(references) != 2 || references[0].Workflow != ".github/workflows/ci.yml" || references[0].Ref != "main" {
+		t.Errorf("Expected unresolved references with their workflow, got %+v", references)
+	}
+}
+
+func TestResolveUses(t *testing.T) {
+	scope := newExpressionScope(map[string]interface{}{"v": []interface{}{1, 2}}, map[string]interface{}{"OWNER": "org"})
+
+	tests := []struct {
+		uses     string
+		expected []string
+		err      string
+	}{
+		{"actions/checkout@v4", []string{"actions/checkout@v4"}, ""},
+		{"${{ env.OWNER }}/tool@v${{ matrix.v }}", []string{"org/tool@v1", "org/tool@v2"}, ""},
+		{"${{ 'it''s' }}/tool@v1", []string{"it's/tool@v1"}, ""},
+		{"${{ env.MISSING }}/tool@v1", nil, "env.MISSING is not set"},
+		{"org/tool@${{ github.sha }}", nil, "cannot be evaluated statically"},
+		{"org/tool@${{ matrix.other }}", nil, "matrix.other is not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uses, func(t *testing.T) {
+			resolved, err := resolveUses(tt.uses, scope)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(resolved, tt.expected) {
+				t.Errorf("resolveUses() = %v, %v, want %v", resolved, err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderFixture serves repositories and workflow files from a local fixture bundle instead
+// of an API, for demos, policy development and tests without network access or a token
+const ProviderFixture = "fixture"
+
+// Fixture is a bundle of repositories and their files, and of API responses recorded from a
+// live scan, served in place of the GitHub API
+type Fixture struct {
+	Organization string                     `json:"organization,omitempty"`
+	Repositories []FixtureRepository        `json:"repositories,omitempty"`
+	Responses    map[string]FixtureResponse `json:"responses,omitempty"` // Recorded responses by "GET /path?query"
+}
+
+// FixtureRepository is a repository of a fixture and the files on its default branch
+type FixtureRepository struct {
+	FullName      string            `json:"full_name"`
+	Description   string            `json:"description,omitempty"`
+	Visibility    string            `json:"visibility,omitempty"`     // public, private or internal; public by default
+	DefaultBranch string            `json:"default_branch,omitempty"` // main by default
+	Archived      bool              `json:"archived,omitempty"`
+	Files         map[string]string `json:"files,omitempty"` // Contents by path, such as ".github/workflows/ci.yml"
+}
+
+// FixtureResponse is a recorded API response
+type FixtureResponse struct {
+	Status int             `json:"status"`
+	Link   string          `json:"link,omitempty"` // Pagination links
+	Body   json.RawMessage `json:"body,omitempty"` // JSON bodies
+	Raw    string          `json:"raw,omitempty"`  // Other bodies, such as raw file contents
+}
+
+// LoadFixture reads a fixture bundle
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	for _, repo := range fixture.Repositories {
+		if owner, name, ok := strings.Cut(repo.FullName, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid repository %q in fixture %s, must be owner/repo", repo.FullName, path)
+		}
+	}
+	return &fixture, nil
+}
+
+// NewFixtureClient creates a client whose requests are served from a fixture. Recorded
+// responses are served as recorded; repositories are served through the repository, Git
+// tree, blob, contents and branch endpoints, and every other request is not found.
+func NewFixtureClient(fixture *Fixture) *Client {
+	return newClientWithTransport("", &fixtureTransport{fixture: fixture})
+}
+
+// fixtureTransport answers API requests from a fixture
+type fixtureTransport struct {
+	fixture *Fixture
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	if recorded, ok := t.fixture.Responses[responseKey(req)]; ok {
+		resp := fixtureResponse(req, recorded.Status, recorded.Body, recorded.Raw)
+		if recorded.Link != "" {
+			resp.Header.Set("Link", recorded.Link)
+		}
+		return resp, nil
+	}
+
+	if req.Method == http.MethodGet {
+		if body, raw, ok := t.serve(req); ok {
+			return fixtureResponse(req, http.StatusOK, body, raw), nil
+		}
+	}
+	return fixtureResponse(req, http.StatusNotFound, json.RawMessage(`{"message": "Not Found"}`), ""), nil
+}
+
+// serve answers a request from the fixture's repositories
+func (t *fixtureTransport) serve(req *http.Request) (body json.RawMessage, raw string, ok bool) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "rate_limit":
+		reset := time.Now().Add(time.Hour).Unix()
+		return marshalFixture(map[string]any{"resources": map[string]any{
+			"core": map[string]any{"limit": 5000, "remaining": 5000, "reset": reset},
+		}}), "", true
+
+	case len(parts) == 3 && parts[0] == "orgs" && parts[2] == "repos":
+		var repos []any
+		for _, repo := range t.fixture.Repositories {
+			if owner, _, _ := strings.Cut(repo.FullName, "/"); strings.EqualFold(owner, parts[1]) {
+				repos = append(repos, repo.json())
+			}
+		}
+		if repos == nil {
+			repos = []any{}
+		}
+		return marshalFixture(repos), "", true
+	}
+
+	if len(parts) < 3 || parts[0] != "repos" {
+		return nil, "", false
+	}
+	repo := t.repository(parts[1] + "/" + parts[2])
+	if repo == nil {
+		return nil, "", false
+	}
+	rest := parts[3:]
+
+	switch {
+	case len(rest) == 0:
+		return marshalFixture(repo.json()), "", true
+
+	case len(rest) == 1 && rest[0] == "branches":
+		return marshalFixture([]any{map[string]any{
+			"name":   repo.defaultBranch(),
+			"commit": map[string]any{"sha": repo.treeSHA("")},
+		}}), "", true
+
+	case len(rest) == 3 && rest[0] == "git" && rest[1] == "trees":
+		// Trees are requested as ref:dir
+		ref, dir, _ := strings.Cut(rest[2], ":")
+		if ref != "HEAD" && ref != repo.defaultBranch() {
+			return nil, "", false
+		}
+		entries := repo.treeEntries(dir)
+		if entries == nil {
+			return nil, "", false
+		}
+		return marshalFixture(map[string]any{"sha": repo.treeSHA(dir), "tree": entries, "truncated": false}), "", true
+
+	case len(rest) == 3 && rest[0] == "git" && rest[1] == "blobs":
+		for _, content := range repo.Files {
+			if blobSHA(content) == rest[2] {
+				if strings.Contains(req.Header.Get("Accept"), "raw") {
+					return nil, content, true
+				}
+				return marshalFixture(map[string]any{
+					"sha":      rest[2],
+					"encoding": "base64",
+					"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+				}), "", true
+			}
+		}
+
+	case len(rest) >= 1 && rest[0] == "contents":
+		if ref := req.URL.Query().Get("ref"); ref != "" && ref != "HEAD" && ref != repo.defaultBranch() {
+			return nil, "", false
+		}
+		filePath := strings.Join(rest[1:], "/")
+		if content, ok := repo.Files[filePath]; ok {
+			return marshalFixture(map[string]any{
+				"type":     "file",
+				"name":     path.Base(filePath),
+				"path":     filePath,
+				"sha":      blobSHA(content),
+				"encoding": "base64",
+				"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+			}), "", true
+		}
+		if listing := repo.directory(filePath); listing != nil {
+			return marshalFixture(listing), "", true
+		}
+	}
+
+	return nil, "", false
+}
+
+// repository returns the fixture repository with a full name
+func (t *fixtureTransport) repository(fullName string) *FixtureRepository {
+	for i := range t.fixture.Repositories {
+		if strings.EqualFold(t.fixture.Repositories[i].FullName, fullName) {
+			return &t.fixture.Repositories[i]
+		}
+	}
+	return nil
+}
+
+// defaultBranch returns the repository's default branch
+func (r *FixtureRepository) defaultBranch() string {
+	if r.DefaultBranch == "" {
+		return "main"
+	}
+	return r.DefaultBranch
+}
+
+// json returns the repository in the form of the repositories API
+func (r *FixtureRepository) json() map[string]any {
+	visibility := r.Visibility
+	if visibility == "" {
+		visibility = "public"
+	}
+	_, name, _ := strings.Cut(r.FullName, "/")
+	return map[string]any{
+		"name":           name,
+		"full_name":      r.FullName,
+		"description":    r.Description,
+		"private":        visibility != "public",
+		"visibility":     visibility,
+		"default_branch": r.defaultBranch(),
+		"archived":       r.Archived,
+	}
+}
+
+// paths returns the sorted paths of the repository's files under dir
+func (r *FixtureRepository) paths(dir string) []string {
+	var paths []string
+	for filePath := range r.Files {
+		if dir == "" || strings.HasPrefix(filePath, dir+"/") {
+			paths = append(paths, filePath)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// treeEntries returns the recursive Git tree entries of the files under dir, relative to it,
+// or nil when dir has no files
+func (r *FixtureRepository) treeEntries(dir string) []any {
+	var entries []any
+	for _, filePath := range r.paths(dir) {
+		entries = append(entries, map[string]any{
+			"path": strings.TrimPrefix(filePath, dir+"/"),
+			"type": "blob",
+			"mode": "100644",
+			"sha":  blobSHA(r.Files[filePath]),
+		})
+	}
+	return entries
+}
+
+// treeSHA derives a SHA of the files under dir that changes whenever one of them changes
+func (r *FixtureRepository) treeSHA(dir string) string {
+	hash := sha1.New()
+	for _, filePath := range r.paths(dir) {
+		fmt.Fprintf(hash, "%s %s\n", filePath, blobSHA(r.Files[filePath]))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// directory returns the contents API listing of a directory, or nil when it has no files
+func (r *FixtureRepository) directory(dir string) []any {
+	seen := make(map[string]bool)
+	var listing []any
+	for _, filePath := range r.paths(dir) {
+		name, rest, nested := strings.Cut(strings.TrimPrefix(filePath, dir+"/"), "/")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		entry := map[string]any{"name": name, "path": path.Join(dir, name), "type": "file"}
+		if nested && rest != "" {
+			entry["type"] = "dir"
+		} else {
+			entry["sha"] = blobSHA(r.Files[filePath])
+		}
+		listing = append(listing, entry)
+	}
+	return listing
+}
+
+// blobSHA returns the Git blob SHA of file content
+func blobSHA(content string) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("blob %d\x00%s", len(content), content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// marshalFixture encodes a synthesized response body
+func marshalFixture(v any) json.RawMessage {
+	// Maps and slices of basic values always encode
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// fixtureResponse builds the response to a request from a JSON or raw body
+func fixtureResponse(req *http.Request, status int, body json.RawMessage, raw string) *http.Response {
+	header := make(http.Header)
+	content := []byte(raw)
+	if body != nil {
+		header.Set("Content-Type", "application/json")
+		content = body
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(content)),
+		ContentLength: int64(len(content)),
+		Request:       req,
+	}
+}
+
+// responseKey identifies a request among recorded responses
+func responseKey(req *http.Request) string {
+	key := req.Method + " " + req.URL.Path
+	if req.URL.RawQuery != "" {
+		key += "?" + req.URL.RawQuery
+	}
+	return key
+}
+
+// Recorder captures the API responses of a live scan into a fixture that replays them
+type Recorder struct {
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecorder creates a recorder for a scan of org
+func NewRecorder(org string) *Recorder {
+	return &Recorder{fixture: Fixture{Organization: org, Responses: make(map[string]FixtureResponse)}}
+}
+
+// Save writes the recorded fixture. It holds response bodies, such as the contents of private
+// workflow files, but no credentials.
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// record captures the response to a GET request, whose body is read to be replayed too
+func (r *Recorder) record(req *http.Request, resp *http.Response) error {
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	recorded := FixtureResponse{Status: resp.StatusCode, Link: resp.Header.Get("Link")}
+	if json.Valid(content) {
+		recorded.Body = json.RawMessage(content)
+	} else {
+		recorded.Raw = string(content)
+	}
+
+	r.mu.Lock()
+	r.fixture.Responses[responseKey(req)] = recorded
+	r.mu.Unlock()
+	return nil
+}
+
+// recordingTransport passes requests on and records the responses to GET requests
+type recordingTransport struct {
+	base     http.RoundTripper
+	recorder *Recorder
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet {
+		return resp, err
+	}
+	if err := t.recorder.record(req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,150 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const fixtureWorkflow = `name: CI
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+`
+
+func TestFixtureClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "org.json")
+	bundle := `{
+  "organization": "demo",
+  "repositories": [
+    {"full_name": "demo/api", "visibility": "private", "files": {
+      ".github/workflows/ci.yml": ` + fmt.Sprintf("%q", fixtureWorkflow) + `,
+      ".github/dependabot.yml": "version: 2\n",
+      "README.md": "# API\n"
+    }},
+    {"full_name": "demo/docs", "default_branch": "trunk"},
+    {"full_name": "other/tool"}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(bundle), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+	client := NewFixtureClient(fixture)
+	ctx := context.Background()
+
+	repos, err := client.ListRepositories(ctx, "demo")
+	if err != nil {
+		t.Fatalf("ListRepositories() error = %v", err)
+	}
+	if len(repos) != 2 || repos[0].FullName != "demo/api" || !repos[0].IsPrivate || repos[1].Visibility != "public" {
+		t.Errorf("Expected the organization's 2 repositories, got %+v", repos)
+	}
+
+	repo, err := client.GetRepository(ctx, "demo", "docs")
+	if err != nil || repo.DefaultBranch != "trunk" {
+		t.Errorf("Expected default branch trunk, got %+v (error: %v)", repo, err)
+	}
+
+	files, err := client.GetWorkflowFiles(ctx, "demo", "api")
+	if err != nil {
+		t.Fatalf("GetWorkflowFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Path != ".github/workflows/ci.yml" || string(files[0].Content) != fixtureWorkflow {
+		t.Errorf("Expected the CI workflow, got %+v", files)
+	}
+	if actions := ExtractActions(files); len(actions) != 1 || actions[0].Uses != "actions/checkout@v4" {
+		t.Errorf("Expected actions/checkout@v4, got %+v", actions)
+	}
+
+	content, err := client.GetRepositoryContent(ctx, "demo", "api", ".github/dependabot.yml")
+	if err != nil || string(content) != "version: 2\n" {
+		t.Errorf("Expected dependabot.yml content, got %q (error: %v)", content, err)
+	}
+
+	// Repositories without workflow files have none, and unknown ones are not found
+	if files, err := client.GetWorkflowFiles(ctx, "demo", "docs"); err == nil && len(files) != 0 {
+		t.Errorf("Expected no workflow files, got %+v", files)
+	}
+	if _, err := client.GetRepository(ctx, "demo", "missing"); err == nil {
+		t.Error("Expected error for a repository not in the fixture")
+	}
+}
+
+func TestLoadFixtureInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "org.json")
+	if err := os.WriteFile(path, []byte(`{"repositories": [{"full_name": "no-owner"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFixture(path); err == nil {
+		t.Error("Expected error for a repository without an owner")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/live/repos":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `[{"name": "app", "full_name": "live/app", "visibility": "internal", "private": true}]`)
+		case "/repos/live/app/git/trees/HEAD:.github":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"sha": "tree", "tree": [{"path": "workflows/ci.yml", "type": "blob", "sha": "abc"}]}`)
+		case "/repos/live/app/git/blobs/abc":
+			fmt.Fprint(w, fixtureWorkflow)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder("live")
+	client, err := NewClientWithHTTPConfig("", HTTPConfig{Recorder: recorder})
+	if err != nil {
+		t.Fatalf("NewClientWithHTTPConfig() error = %v", err)
+	}
+	serverURL, _ := url.Parse(server.URL + "/")
+	client.client.BaseURL = serverURL
+
+	ctx := context.Background()
+	live, err := client.WorkflowFilesForOrg(ctx, "live")
+	if err != nil {
+		t.Fatalf("WorkflowFilesForOrg() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "recorded.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+	if fixture.Organization != "live" {
+		t.Errorf("Expected organization live, got %q", fixture.Organization)
+	}
+
+	// The recorded scan replays without the server
+	server.Close()
+	replayed, err := NewFixtureClient(fixture).WorkflowFilesForOrg(ctx, "live")
+	if err != nil {
+		t.Fatalf("WorkflowFilesForOrg() replay error = %v", err)
+	}
+	files := replayed["live/app"]
+	if len(files) != 1 || string(files[0].Content) != fixtureWorkflow || len(live["live/app"]) != 1 {
+		t.Errorf("Expected the recorded workflow to replay, got %+v", replayed)
+	}
+}
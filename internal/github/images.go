@@ -0,0 +1,91 @@
+package github
+
+import (
+	"sort"
+)
+
+// Image is a container image a workflow job runs in or starts as a service
+type Image struct {
+	Image    string `json:"image"`
+	Workflow string `json:"workflow"`          // Path of the workflow file referencing the image
+	Job      string `json:"job"`               // Job referencing the image
+	Service  string `json:"service,omitempty"` // Service name; empty for the job container
+	Ref      string `json:"ref,omitempty"`     // Branch of the workflow file; empty for the default branch
+}
+
+// ExtractImages extracts job container and service images from already fetched workflow files.
+// Files that cannot be parsed are skipped.
+func ExtractImages(files []WorkflowFile) []Image {
+	var images []Image
+
+	for _, file := range files {
+		workflows, err := decodeWorkflows(file.Content)
+		if err != nil {
+			continue
+		}
+		for _, workflow := range workflows {
+			images = append(images, jobImages(file, workflow)...)
+		}
+	}
+
+	return images
+}
+
+// jobImages extracts the job container and service images of a single workflow document
+func jobImages(file WorkflowFile, workflow map[string]interface{}) []Image {
+	var images []Image
+
+	jobsMap, ok := workflow["jobs"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	// Sort jobs for consistent output
+	jobNames := make([]string, 0, len(jobsMap))
+	for jobName := range jobsMap {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	for _, jobName := range jobNames {
+		jobMap, ok := jobsMap[jobName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if image := containerImage(jobMap["container"]); image != "" {
+			images = append(images, Image{Image: image, Workflow: file.Path, Job: jobName, Ref: file.Ref})
+		}
+
+		services, ok := jobMap["services"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		serviceNames := make([]string, 0, len(services))
+		for serviceName := range services {
+			serviceNames = append(serviceNames, serviceName)
+		}
+		sort.Strings(serviceNames)
+
+		for _, serviceName := range serviceNames {
+			if image := containerImage(services[serviceName]); image != "" {
+				images = append(images, Image{Image: image, Workflow: file.Path, Job: jobName, Service: serviceName, Ref: file.Ref})
+			}
+		}
+	}
+
+	return images
+}
+
+// containerImage returns the image of a container or service definition, which is either
+// the image itself or a mapping with an image key
+func containerImage(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		image, _ := v["image"].(string)
+		return image
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestExtractImages(t *testing.T) {
+	content := `
+name: CI
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    container:
+      image: node:20
+      options: --cpus 1
+    services:
+      redis:
+        image: redis:7
+      db:
+        image: ghcr.io/acme/postgres@sha256:abc
+  build:
+    runs-on: ubuntu-latest
+    container: golang:1.24
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+`
+	files := []WorkflowFile{
+		{Name: "ci.yml", Path: ".github/workflows/ci.yml", Ref: "main", Content: []byte(content)},
+		{Name: "broken.yml", Path: ".github/workflows/broken.yml", Content: []byte("jobs: [")},
+	}
+
+	images := ExtractImages(files)
+
+	expected := []Image{
+		{Image: "golang:1.24", Workflow: ".github/workflows/ci.yml", Job: "build", Ref: "main"},
+		{Image: "node:20", Workflow: ".github/workflows/ci.yml", Job: "test", Ref: "main"},
+		{Image: "ghcr.io/acme/postgres@sha256:abc", Workflow: ".github/workflows/ci.yml", Job: "test", Service: "db", Ref: "main"},
+		{Image: "redis:7", Workflow: ".github/workflows/ci.yml", Job: "test", Service: "redis", Ref: "main"},
+	}
+
+	if len(images) != len(expected) {
+		t.Fatalf("Expected %d images, got %d: %+v", len(expected), len(images), images)
+	}
+	for i := range expected {
+		if images[i] != expected[i] {
+			t.Errorf("Image %d: expected %+v, got %+v", i, expected[i], images[i])
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Job represents a job definition within a workflow file
+type Job struct {
+	Workflow string   // Path of the workflow file defining the job
+	Name     string   // Job identifier
+	RunsOn   []string // Runner labels the job is pinned to
+	Uses     string   // Reusable workflow the job calls instead of running steps
+
+	ContinueOnError bool // continue-on-error is true, so the job's failure does not fail the run
+	HasTimeout      bool // timeout-minutes is set, instead of the 6 hour default
+}
+
+// ExtractJobs parses a workflow file and returns its jobs sorted by name
+func ExtractJobs(file WorkflowFile) ([]Job, error) {
+	workflows, err := decodeWorkflows(file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file %s: %w", file.Name, err)
+	}
+
+	jobs := []Job{}
+
+	for _, workflow := range workflows {
+		jobsMap, ok := workflow["jobs"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for jobName, jobConfig := range jobsMap {
+			jobMap, ok := jobConfig.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			uses, _ := jobMap["uses"].(string)
+			_, hasTimeout := jobMap["timeout-minutes"]
+			jobs = append(jobs, Job{
+				Workflow:        file.Path,
+				Name:            jobName,
+				RunsOn:          runnerLabels(jobMap["runs-on"]),
+				Uses:            uses,
+				ContinueOnError: isTrue(jobMap["continue-on-error"]),
+				HasTimeout:      hasTimeout,
+			})
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+
+	return jobs, nil
+}
+
+// runnerLabels normalizes the forms accepted by runs-on into a list of labels
+func runnerLabels(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var labels []string
+		for _, item := range v {
+			if label, ok := item.(string); ok {
+				labels = append(labels, label)
+			}
+		}
+		return labels
+	case map[string]interface{}:
+		// runs-on: { group: ..., labels: ... }
+		return runnerLabels(v["labels"])
+	}
+	return nil
+}
+
+// isTrue reports whether a workflow value is literally true. Expressions, which are only known
+// at run time, are not.
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(strings.TrimSpace(v), "true")
+	}
+	return false
+}
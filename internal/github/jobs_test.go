@@ -0,0 +1,97 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractJobs(t *testing.T) {
+	file := WorkflowFile{
+		Name: "ci.yml",
+		Path: ".github/workflows/ci.yml",
+		Content: []byte(`
+name: CI
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-20.04
+  test:
+    runs-on: [self-hosted, linux]
+  deploy:
+    runs-on:
+      group: production
+      labels: windows-2019
+  reusable:
+    uses: org/repo/.github/workflows/shared.yml@main
+`),
+	}
+
+	jobs, err := ExtractJobs(file)
+	if err != nil {
+		t.Fatalf("ExtractJobs returned error: %v", err)
+	}
+
+	expected := map[string][]string{
+		"build":    {"ubuntu-20.04"},
+		"deploy":   {"windows-2019"},
+		"reusable": nil,
+		"test":     {"self-hosted", "linux"},
+	}
+
+	if len(jobs) != len(expected) {
+		t.Fatalf("Expected %d jobs, got %d", len(expected), len(jobs))
+	}
+
+	for _, job := range jobs {
+		if job.Workflow != ".github/workflows/ci.yml" {
+			t.Errorf("Expected workflow path to be set, got %q", job.Workflow)
+		}
+		if !reflect.DeepEqual(job.RunsOn, expected[job.Name]) {
+			t.Errorf("Job %s: expected runs-on %v, got %v", job.Name, expected[job.Name], job.RunsOn)
+		}
+	}
+
+	// Jobs are sorted by name
+	if jobs[0].Name != "build" || jobs[3].Name != "test" {
+		t.Errorf("Expected jobs sorted by name, got %s..%s", jobs[0].Name, jobs[3].Name)
+	}
+}
+
+func TestExtractJobsHygiene(t *testing.T) {
+	file := WorkflowFile{Path: ".github/workflows/ci.yml", Content: []byte(`on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    timeout-minutes: 10
+    continue-on-error: true
+  test:
+    runs-on: ubuntu-latest
+    continue-on-error: ${{ matrix.experimental }}
+  reusable:
+    uses: org/repo/.github/workflows/shared.yml@main
+    steps:
+      - uses: actions/checkout@v4
+        continue-on-error: true
+`)}
+
+	jobs, err := ExtractJobs(file)
+	if err != nil {
+		t.Fatalf("ExtractJobs returned error: %v", err)
+	}
+	if !jobs[0].ContinueOnError || !jobs[0].HasTimeout {
+		t.Errorf("Expected build to continue on error with a timeout, got %+v", jobs[0])
+	}
+	if jobs[1].Uses != "org/repo/.github/workflows/shared.yml@main" || jobs[1].HasTimeout {
+		t.Errorf("Expected the reusable workflow call without a timeout, got %+v", jobs[1])
+	}
+	if jobs[2].ContinueOnError {
+		t.Errorf("Expected an expression not to count as continuing on error, got %+v", jobs[2])
+	}
+
+	actions := ExtractActions([]WorkflowFile{file})
+	for _, action := range actions {
+		if action.Uses == "actions/checkout@v4" && !action.ContinueOnError {
+			t.Errorf("Expected the step to continue on error, got %+v", action)
+		}
+	}
+}
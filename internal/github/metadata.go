@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// RepositoryMetadata describes the upkeep of the repository publishing an action
+type RepositoryMetadata struct {
+	Repository     string    `json:"repository"`
+	Archived       bool      `json:"archived"`
+	Fork           bool      `json:"fork"`
+	Parent         string    `json:"parent,omitempty"` // Repository a fork was created from
+	CreatedAt      time.Time `json:"created_at"`
+	PushedAt       time.Time `json:"pushed_at"`
+	Stars          int       `json:"stars"`
+	OpenAdvisories int       `json:"open_advisories"` // Published security advisories that were not withdrawn
+}
+
+// GetRepositoryMetadata retrieves the archived and fork status, creation and last push, star
+// count and security advisories of a repository. Advisories that cannot be listed are counted as none. Results
+// are cached for the lifetime of the client.
+func (c *Client) GetRepositoryMetadata(ctx context.Context, owner, repo string) (RepositoryMetadata, error) {
+	key := owner + "/" + repo
+	c.mu.Lock()
+	metadata, ok := c.metadata[key]
+	c.mu.Unlock()
+	if ok {
+		return metadata, nil
+	}
+
+	repository, _, err := c.client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return RepositoryMetadata{}, fmt.Errorf("failed to get repository %s: %w", key, err)
+	}
+	metadata = RepositoryMetadata{
+		Repository: repository.GetFullName(),
+		Archived:   repository.GetArchived(),
+		Fork:       repository.GetFork(),
+		Parent:     repository.GetParent().GetFullName(),
+		CreatedAt:  repository.GetCreatedAt().Time,
+		PushedAt:   repository.GetPushedAt().Time,
+		Stars:      repository.GetStargazersCount(),
+	}
+
+	opts := &github.ListRepositorySecurityAdvisoriesOptions{
+		State:             "published",
+		ListCursorOptions: github.ListCursorOptions{PerPage: 100},
+	}
+	advisories, _, err := c.client.SecurityAdvisories.ListRepositorySecurityAdvisories(ctx, owner, repo, opts)
+	if err == nil {
+		for _, advisory := range advisories {
+			if advisory.WithdrawnAt == nil {
+				metadata.OpenAdvisories++
+			}
+		}
+	}
+
+	c.mu.Lock()
+	if c.metadata == nil {
+		c.metadata = make(map[string]RepositoryMetadata)
+	}
+	c.metadata[key] = metadata
+	c.mu.Unlock()
+
+	return metadata, nil
+}
@@ -0,0 +1,77 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetRepositoryMetadata(t *testing.T) {
+	requests := 0
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/old/action":
+			fmt.Fprint(w, `{"full_name": "old/action", "archived": true, "pushed_at": "2021-03-04T05:06:07Z", "stargazers_count": 42}`)
+		case "/repos/old/action/security-advisories":
+			if r.URL.Query().Get("state") != "published" {
+				t.Errorf("Expected published advisories to be listed, got state %q", r.URL.Query().Get("state"))
+			}
+			fmt.Fprint(w, `[{"ghsa_id": "GHSA-1"}, {"ghsa_id": "GHSA-2", "withdrawn_at": "2022-01-01T00:00:00Z"}]`)
+		case "/repos/private/action":
+			fmt.Fprint(w, `{"full_name": "private/action", "stargazers_count": 1, "fork": true, "parent": {"full_name": "actions/checkout"}, "created_at": "2025-05-01T00:00:00Z"}`)
+		case "/repos/private/action/security-advisories":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "Forbidden"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	metadata, err := client.GetRepositoryMetadata(ctx, "old", "action")
+	if err != nil {
+		t.Fatalf("GetRepositoryMetadata() error = %v", err)
+	}
+	expected := RepositoryMetadata{
+		Repository:     "old/action",
+		Archived:       true,
+		PushedAt:       time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+		Stars:          42,
+		OpenAdvisories: 1,
+	}
+	if !metadata.PushedAt.Equal(expected.PushedAt) {
+		t.Errorf("Expected pushed at %v, got %v", expected.PushedAt, metadata.PushedAt)
+	}
+	metadata.PushedAt = expected.PushedAt
+	if metadata != expected {
+		t.Errorf("GetRepositoryMetadata() = %+v, want %+v", metadata, expected)
+	}
+
+	// Results are cached
+	before := requests
+	if _, err := client.GetRepositoryMetadata(ctx, "old", "action"); err != nil || requests != before {
+		t.Errorf("Expected cached result without a request, got %d requests", requests-before)
+	}
+
+	// Advisories that cannot be listed count as none
+	metadata, err = client.GetRepositoryMetadata(ctx, "private", "action")
+	if err != nil || metadata.OpenAdvisories != 0 || metadata.Stars != 1 {
+		t.Errorf("GetRepositoryMetadata() = (%+v, %v), want 1 star and no advisories", metadata, err)
+	}
+	if !metadata.Fork || metadata.Parent != "actions/checkout" || metadata.CreatedAt.Year() != 2025 {
+		t.Errorf("Expected a fork of actions/checkout created in 2025, got %+v", metadata)
+	}
+
+	if _, err := client.GetRepositoryMetadata(ctx, "missing", "action"); err == nil {
+		t.Error("Expected error for a missing repository, got nil")
+	}
+}
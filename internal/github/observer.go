@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// RepoStats describes the scan of a single repository within an organization scan
+type RepoStats struct {
+	Repository string
+	Index      int // 1-based position of the repository in the scan
+	Total      int // Number of repositories in the scan
+	Duration   time.Duration
+	APICalls   int64
+	Err        error
+}
+
+// ScanObserver receives progress notifications during organization scans. Repository
+// notifications may arrive concurrently when repositories are scanned in parallel.
+type ScanObserver interface {
+	ScanStarted(total int)
+	RepoStarted(repo string, index, total int)
+	RepoScanned(stats RepoStats)
+	ScanFinished()
+}
+
+// ScanCheckpoint persists organization scan progress so an interrupted scan can resume
+// without rescanning the repositories it already covered
+type ScanCheckpoint interface {
+	Scanned(repo string) ([]WorkflowFile, bool)
+	Record(repo string, files []WorkflowFile) error
+	Complete() error
+}
+
+// RepositoryCache keeps organization repository listings between runs, so runs within a
+// short window don't enumerate every repository again
+type RepositoryCache interface {
+	Repositories(org string) ([]Repository, bool)
+	StoreRepositories(org string, repos []Repository) error
+}
+
+// noopObserver ignores all scan notifications
+type noopObserver struct{}
+
+func (noopObserver) ScanStarted(total int)                     {}
+func (noopObserver) RepoStarted(repo string, index, total int) {}
+func (noopObserver) RepoScanned(stats RepoStats)               {}
+func (noopObserver) ScanFinished()                             {}
+
+// countingTransport counts requests passing through an HTTP transport
+type countingTransport struct {
+	base  http.RoundTripper
+	count *int64
+}
+
+// requestCounterKey is the context key of an additional per-operation request counter
+type requestCounterKey struct{}
+
+// withRequestCounter returns a context whose requests are also counted in count
+func withRequestCounter(ctx context.Context, count *int64) context.Context {
+	return context.WithValue(ctx, requestCounterKey{}, count)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(t.count, 1)
+	if count, ok := req.Context().Value(requestCounterKey{}).(*int64); ok {
+		atomic.AddInt64(count, 1)
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
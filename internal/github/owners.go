@@ -0,0 +1,39 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// IsVerifiedCreator reports whether an action owner is an organization that has verified
+// its domain with GitHub, which the Marketplace verified creator badge builds on. User
+// accounts are never verified. Results are cached for the lifetime of the client.
+func (c *Client) IsVerifiedCreator(ctx context.Context, owner string) (bool, error) {
+	c.mu.Lock()
+	verified, ok := c.verified[owner]
+	c.mu.Unlock()
+	if ok {
+		return verified, nil
+	}
+
+	org, resp, err := c.client.Organizations.Get(ctx, owner)
+	if err != nil {
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return false, fmt.Errorf("failed to get organization %s: %w", owner, err)
+		}
+		// Not an organization, so a user account
+		verified = false
+	} else {
+		verified = org.GetIsVerified()
+	}
+
+	c.mu.Lock()
+	if c.verified == nil {
+		c.verified = make(map[string]bool)
+	}
+	c.verified[owner] = verified
+	c.mu.Unlock()
+
+	return verified, nil
+}
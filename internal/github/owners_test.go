@@ -0,0 +1,55 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIsVerifiedCreator(t *testing.T) {
+	requests := 0
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/orgs/actions":
+			fmt.Fprint(w, `{"login": "actions", "is_verified": true}`)
+		case "/orgs/small-org":
+			fmt.Fprint(w, `{"login": "small-org", "is_verified": false}`)
+		case "/orgs/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message": "Server Error"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	tests := map[string]bool{
+		"actions":   true,
+		"small-org": false,
+		"some-user": false,
+	}
+	for owner, want := range tests {
+		got, err := client.IsVerifiedCreator(ctx, owner)
+		if err != nil || got != want {
+			t.Errorf("IsVerifiedCreator(%q) = (%v, %v), want %v", owner, got, err, want)
+		}
+	}
+
+	// Results are cached
+	before := requests
+	if verified, _ := client.IsVerifiedCreator(ctx, "actions"); !verified || requests != before {
+		t.Errorf("Expected cached result without a request, got %v after %d requests", verified, requests-before)
+	}
+
+	if _, err := client.IsVerifiedCreator(ctx, "broken"); err == nil {
+		t.Error("Expected error for failed lookup, got nil")
+	}
+}
@@ -0,0 +1,163 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseLimits bounds the resources spent parsing a single workflow file
+type ParseLimits struct {
+	MaxBytes int // Maximum size of the raw file
+	MaxNodes int // Maximum number of nodes after expanding aliases
+	MaxDepth int // Maximum nesting depth after expanding aliases
+}
+
+// DefaultParseLimits are generous for real workflows but stop alias bombs
+// ("billion laughs") and pathologically nested or oversized files
+var DefaultParseLimits = ParseLimits{
+	MaxBytes: 1 << 20,
+	MaxNodes: 100000,
+	MaxDepth: 64,
+}
+
+// parseLimits holds the limits of hardened parsing mode; nil disables it
+var parseLimits *ParseLimits
+
+// SetHardenedParsing enables hardened parsing of workflow files with the given limits,
+// or disables it when limits is nil. It should be called before scanning starts.
+func SetHardenedParsing(limits *ParseLimits) {
+	parseLimits = limits
+}
+
+// unmarshalWorkflow decodes workflow content, enforcing parse limits in hardened mode
+func unmarshalWorkflow(content []byte, out interface{}) error {
+	if parseLimits == nil {
+		return yaml.Unmarshal(content, out)
+	}
+
+	if len(content) > parseLimits.MaxBytes {
+		return fmt.Errorf("workflow file exceeds %d bytes", parseLimits.MaxBytes)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return err
+	}
+
+	if _, err := checkLimits(&doc, *parseLimits); err != nil {
+		return err
+	}
+
+	return doc.Decode(out)
+}
+
+// workflowDocuments parses every document of a multi-document workflow file, skipping empty
+// ones. In hardened mode the limits apply to the file as a whole, so the node budget is shared
+// across documents.
+func workflowDocuments(content []byte) ([]*yaml.Node, error) {
+	var limits ParseLimits
+	if parseLimits != nil {
+		limits = *parseLimits
+		if len(content) > limits.MaxBytes {
+			return nil, fmt.Errorf("workflow file exceeds %d bytes", limits.MaxBytes)
+		}
+	}
+
+	var documents []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return documents, nil
+			}
+			return nil, err
+		}
+
+		if parseLimits != nil {
+			nodes, err := checkLimits(&doc, limits)
+			if err != nil {
+				return nil, err
+			}
+			limits.MaxNodes -= nodes
+		}
+
+		if len(doc.Content) > 0 && doc.Content[0].Tag != "!!null" {
+			documents = append(documents, &doc)
+		}
+	}
+}
+
+// decodeWorkflows decodes every document of a workflow file, see workflowDocuments
+func decodeWorkflows(content []byte) ([]map[string]interface{}, error) {
+	documents, err := workflowDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := make([]map[string]interface{}, 0, len(documents))
+	for _, document := range documents {
+		var workflow map[string]interface{}
+		if err := document.Decode(&workflow); err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, workflow)
+	}
+	return workflows, nil
+}
+
+// checkLimits measures the size and depth of a document as it would be decoded, counting each
+// alias as a full copy of its anchor without materializing it, and returns its number of nodes
+func checkLimits(doc *yaml.Node, limits ParseLimits) (int, error) {
+	type measure struct {
+		nodes int
+		depth int
+	}
+	measured := make(map[*yaml.Node]measure)
+	inProgress := make(map[*yaml.Node]bool)
+
+	var walk func(n *yaml.Node) (measure, error)
+	walk = func(n *yaml.Node) (measure, error) {
+		if m, ok := measured[n]; ok {
+			return m, nil
+		}
+		if inProgress[n] {
+			return measure{}, fmt.Errorf("workflow file contains a recursive alias")
+		}
+		inProgress[n] = true
+		defer delete(inProgress, n)
+
+		m := measure{nodes: 1, depth: 1}
+		children := n.Content
+		if n.Kind == yaml.AliasNode && n.Alias != nil {
+			children = []*yaml.Node{n.Alias}
+		}
+
+		for _, child := range children {
+			cm, err := walk(child)
+			if err != nil {
+				return measure{}, err
+			}
+			m.nodes += cm.nodes
+			if cm.depth+1 > m.depth {
+				m.depth = cm.depth + 1
+			}
+			if m.nodes > limits.MaxNodes {
+				return measure{}, fmt.Errorf("workflow file expands to more than %d nodes", limits.MaxNodes)
+			}
+			if m.depth > limits.MaxDepth {
+				return measure{}, fmt.Errorf("workflow file is nested deeper than %d levels", limits.MaxDepth)
+			}
+		}
+
+		measured[n] = m
+		return m, nil
+	}
+
+	m, err := walk(doc)
+	return m.nodes, err
+}
@@ -0,0 +1,238 @@
+package github
+
+import (
+	"strings"
+	"testing"
+)
+
+// billionLaughs expands to 9^9 scalars when aliases are resolved
+const billionLaughs = `a: &a ["lol","lol","lol","lol","lol","lol","lol","lol","lol"]
+b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a]
+c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b]
+d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c]
+e: &e [*d,*d,*d,*d,*d,*d,*d,*d,*d]
+f: &f [*e,*e,*e,*e,*e,*e,*e,*e,*e]
+g: &g [*f,*f,*f,*f,*f,*f,*f,*f,*f]
+h: &h [*g,*g,*g,*g,*g,*g,*g,*g,*g]
+i: &i [*h,*h,*h,*h,*h,*h,*h,*h,*h]
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@v4
+`
+
+func withHardenedParsing(t *testing.T, limits ParseLimits) {
+	t.Helper()
+	SetHardenedParsing(&limits)
+	t.Cleanup(func() { SetHardenedParsing(nil) })
+}
+
+func TestHardenedParsing(t *testing.T) {
+	withHardenedParsing(t, DefaultParseLimits)
+
+	// Regular workflows, including anchors, parse as before
+	content := `
+name: CI
+defaults: &defaults
+  uses: actions/checkout@v4
+jobs:
+  build:
+    steps:
+      - <<: *defaults
+      - uses: actions/setup-go@v5
+`
+	actions, err := extractActionsFromWorkflow([]byte(content), "ci.yml")
+	if err != nil {
+		t.Fatalf("Expected workflow to parse, got error: %v", err)
+	}
+	if len(actions) != 2 {
+		t.Errorf("Expected 2 actions, got %d", len(actions))
+	}
+
+	if _, err := extractActionsFromWorkflow([]byte(billionLaughs), "bomb.yml"); err == nil || !strings.Contains(err.Error(), "nodes") {
+		t.Errorf("Expected alias expansion to be rejected, got %v", err)
+	}
+
+	deep := "jobs: " + strings.Repeat("[", 100) + strings.Repeat("]", 100)
+	if _, err := extractActionsFromWorkflow([]byte(deep), "deep.yml"); err == nil || !strings.Contains(err.Error(), "nested") {
+		t.Errorf("Expected deep nesting to be rejected, got %v", err)
+	}
+
+	if _, err := extractActionsFromWorkflow(make([]byte, DefaultParseLimits.MaxBytes+1), "big.yml"); err == nil || !strings.Contains(err.Error(), "bytes") {
+		t.Errorf("Expected oversized file to be rejected, got %v", err)
+	}
+
+	if _, err := ExtractJobs(WorkflowFile{Name: "bomb.yml", Content: []byte(billionLaughs)}); err == nil {
+		t.Error("Expected job extraction to reject alias expansion")
+	}
+}
+
+func TestHardenedParsingEmpty(t *testing.T) {
+	withHardenedParsing(t, DefaultParseLimits)
+
+	actions, err := extractActionsFromWorkflow(nil, "empty.yml")
+	if err != nil {
+		t.Fatalf("Expected empty workflow to parse, got error: %v", err)
+	}
+	if len(actions) != 0 {
+		t.Errorf("Expected no actions, got %d", len(actions))
+	}
+}
+
+func FuzzExtractActionsFromWorkflow(f *testing.F) {
+	f.Add([]byte(CreateMockWorkflowContent()))
+	f.Add([]byte(billionLaughs))
+	f.Add([]byte("jobs:\n  call:\n    uses: org/repo/.github/workflows/build.yml@main\n    with:\n      a: 1\n"))
+	f.Add([]byte("jobs: {a: {steps: [{uses: x@y, with: {k: [1, 2]}}, null, 3]}}"))
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		for _, limits := range []*ParseLimits{&DefaultParseLimits, nil} {
+			// Unbounded parsing is only safe on inputs hardened mode accepts
+			if limits == nil && checkFuzzInput(content) != nil {
+				continue
+			}
+			SetHardenedParsing(limits)
+
+			actions, err := extractActionsFromWorkflow(content, "fuzz.yml")
+			if err != nil {
+				continue
+			}
+			for _, action := range actions {
+				if action.Uses == "" {
+					t.Errorf("Extracted action with empty uses: %+v", action)
+				}
+				if action.Job == "" {
+					t.Errorf("Extracted action without job: %+v", action)
+				}
+			}
+		}
+		SetHardenedParsing(nil)
+	})
+}
+
+func FuzzExtractJobs(f *testing.F) {
+	f.Add([]byte(CreateMockWorkflowContent()))
+	f.Add([]byte("jobs:\n  a:\n    runs-on: [self-hosted, linux]\n  b:\n    runs-on: ubuntu-latest\n"))
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		withHardenedParsing(t, DefaultParseLimits)
+
+		jobs, err := ExtractJobs(WorkflowFile{Name: "fuzz.yml", Path: ".github/workflows/fuzz.yml", Content: content})
+		if err != nil {
+			return
+		}
+		for i := 1; i < len(jobs); i++ {
+			if jobs[i-1].Name > jobs[i].Name {
+				t.Errorf("Jobs not sorted: %q before %q", jobs[i-1].Name, jobs[i].Name)
+			}
+		}
+	})
+}
+
+// checkFuzzInput applies the default limits to content without decoding it
+func checkFuzzInput(content []byte) error {
+	saved := parseLimits
+	defer SetHardenedParsing(saved)
+
+	SetHardenedParsing(&DefaultParseLimits)
+	_, err := workflowDocuments(content)
+	return err
+}
+
+// templatedWorkflow defines steps and jobs through anchors, aliases and merge keys across two
+// documents
+const templatedWorkflow = `x-checkout: &checkout
+  uses: actions/checkout@v4
+x-steps: &steps
+  - *checkout
+  - <<: *checkout
+    name: Checkout again
+  - <<: [{uses: actions/setup-go@v5}, *checkout]
+    with:
+      go-version: "1.24"
+jobs:
+  build: &build
+    runs-on: ubuntu-latest
+    container: golang:1.24
+    steps: *steps
+  test:
+    <<: *build
+    runs-on: windows-latest
+---
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: org/deploy@v1
+---
+`
+
+func TestTemplatedWorkflow(t *testing.T) {
+	actions, err := extractActionsFromWorkflow([]byte(templatedWorkflow), "ci.yml")
+	if err != nil {
+		t.Fatalf("Expected workflow to parse, got error: %v", err)
+	}
+
+	counts := make(map[string]int)
+	lines := make(map[string]int)
+	for _, action := range actions {
+		counts[action.Job+" "+action.Uses]++
+		if action.Line == 0 {
+			t.Errorf("Expected a line for %s in job %s", action.Uses, action.Job)
+		}
+		lines[action.Job+" "+action.Uses] = action.Line
+	}
+	expected := map[string]int{
+		"build actions/checkout@v4": 2,
+		"build actions/setup-go@v5": 1,
+		"test actions/checkout@v4":  2,
+		"test actions/setup-go@v5":  1,
+		"deploy org/deploy@v1":      1,
+	}
+	if len(counts) != len(expected) {
+		t.Errorf("Expected actions %v, got %v", expected, counts)
+	}
+	for key, count := range expected {
+		if counts[key] != count {
+			t.Errorf("Expected %d of %q, got %d", count, key, counts[key])
+		}
+	}
+	if lines["test actions/setup-go@v5"] != 7 {
+		t.Errorf("Expected merged uses to report the anchored line 7, got %d", lines["test actions/setup-go@v5"])
+	}
+	if lines["deploy org/deploy@v1"] != 23 {
+		t.Errorf("Expected the second document's uses on line 23, got %d", lines["deploy org/deploy@v1"])
+	}
+
+	jobs, err := ExtractJobs(WorkflowFile{Name: "ci.yml", Path: ".github/workflows/ci.yml", Content: []byte(templatedWorkflow)})
+	if err != nil {
+		t.Fatalf("Expected jobs to parse, got error: %v", err)
+	}
+	var names []string
+	for _, job := range jobs {
+		names = append(names, job.Name+":"+strings.Join(job.RunsOn, ","))
+	}
+	if strings.Join(names, " ") != "build:ubuntu-latest deploy:ubuntu-latest test:windows-latest" {
+		t.Errorf("Unexpected jobs %v", names)
+	}
+
+	images := ExtractImages([]WorkflowFile{{Name: "ci.yml", Path: ".github/workflows/ci.yml", Content: []byte(templatedWorkflow)}})
+	if len(images) != 2 || images[0].Job != "build" || images[1].Job != "test" {
+		t.Errorf("Expected the container image inherited by test, got %+v", images)
+	}
+}
+
+func TestHardenedParsingDocuments(t *testing.T) {
+	withHardenedParsing(t, ParseLimits{MaxBytes: 1 << 20, MaxNodes: 30, MaxDepth: 64})
+
+	document := "jobs:\n  build:\n    steps:\n      - uses: actions/checkout@v4\n"
+	if _, err := extractActionsFromWorkflow([]byte(document), "one.yml"); err != nil {
+		t.Fatalf("Expected a single document to fit the limits, got %v", err)
+	}
+
+	// The node budget covers the file, so many small documents cannot add up past it
+	many := strings.Repeat(document+"---\n", 3)
+	if _, err := extractActionsFromWorkflow([]byte(many), "many.yml"); err == nil || !strings.Contains(err.Error(), "nodes") {
+		t.Errorf("Expected documents to share the node budget, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package github
+
+import "strings"
+
+// Permission levels of a GITHUB_TOKEN scope, from least to most access
+const (
+	PermissionNone  = "none"
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+)
+
+// allPermissions is the scope holding the level read-all or write-all grants every scope
+const allPermissions = "*"
+
+// Permissions are the GITHUB_TOKEN permissions a job sets, by scope such as "id-token". Nil
+// when neither the job nor its workflow sets permissions, leaving the repository's default.
+type Permissions map[string]string
+
+// parsePermissions reads a permissions key, either a map of scopes to levels or one of the
+// read-all and write-all shorthands. An empty map grants no scope.
+func parsePermissions(value interface{}) Permissions {
+	permissions := Permissions{}
+	switch value := value.(type) {
+	case string:
+		switch value {
+		case "read-all":
+			permissions[allPermissions] = PermissionRead
+		case "write-all":
+			permissions[allPermissions] = PermissionWrite
+		}
+	case map[string]interface{}:
+		for scope, level := range value {
+			if level, ok := level.(string); ok {
+				permissions[scope] = strings.ToLower(level)
+			}
+		}
+	}
+	return permissions
+}
+
+// Grants reports whether the permissions give a scope at least the given level. Unset
+// permissions grant nothing, as the repository's default is not known from the workflow.
+func (p Permissions) Grants(scope, level string) bool {
+	granted, ok := p[scope]
+	if !ok {
+		granted = p[allPermissions]
+	}
+	return permissionRank(granted) >= permissionRank(level) && permissionRank(level) > 0
+}
+
+// permissionRank orders permission levels; unknown levels rank as none
+func permissionRank(level string) int {
+	switch level {
+	case PermissionRead:
+		return 1
+	case PermissionWrite:
+		return 2
+	}
+	return 0
+}
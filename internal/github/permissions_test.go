@@ -0,0 +1,77 @@
+package github
+
+import "testing"
+
+func TestExtractActionsPermissions(t *testing.T) {
+	files := []WorkflowFile{{
+		Name: "release.yml",
+		Path: ".github/workflows/release.yml",
+		Content: []byte(`
+name: Release
+permissions:
+  contents: read
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@v4
+  publish:
+    name: Publish to registry
+    permissions:
+      id-token: write
+    steps:
+      - uses: pypa/gh-action-pypi-publish@release/v1
+  sign:
+    permissions: write-all
+    uses: org/workflows/.github/workflows/sign.yml@main
+`),
+	}}
+
+	byJob := make(map[string]Action)
+	for _, action := range ExtractActions(files) {
+		byJob[action.Job] = action
+	}
+
+	build, publish, sign := byJob["build"], byJob["publish"], byJob["sign"]
+	if build.WorkflowName != "Release" || build.JobName != "" {
+		t.Errorf("Expected the workflow name and no job name, got %q / %q", build.WorkflowName, build.JobName)
+	}
+	if !build.Permissions.Grants("contents", PermissionRead) || build.Permissions.Grants("id-token", PermissionWrite) {
+		t.Errorf("Expected build to inherit the workflow's permissions, got %v", build.Permissions)
+	}
+	if publish.JobName != "Publish to registry" {
+		t.Errorf("Expected the job name to be recorded, got %q", publish.JobName)
+	}
+	if !publish.Permissions.Grants("id-token", PermissionWrite) || publish.Permissions.Grants("contents", PermissionRead) {
+		t.Errorf("Expected job permissions to replace the workflow's, got %v", publish.Permissions)
+	}
+	if !sign.Permissions.Grants("id-token", PermissionWrite) {
+		t.Errorf("Expected write-all to grant id-token: write, got %v", sign.Permissions)
+	}
+}
+
+func TestPermissionsGrants(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions Permissions
+		scope       string
+		level       string
+		want        bool
+	}{
+		{"unset", nil, "id-token", PermissionWrite, false},
+		{"write grants read", Permissions{"contents": PermissionWrite}, "contents", PermissionRead, true},
+		{"read does not grant write", Permissions{"contents": PermissionRead}, "contents", PermissionWrite, false},
+		{"none", Permissions{"id-token": PermissionNone}, "id-token", PermissionRead, false},
+		{"read-all", parsePermissions("read-all"), "packages", PermissionRead, true},
+		{"read-all does not grant write", parsePermissions("read-all"), "packages", PermissionWrite, false},
+		{"scope overrides shorthand", Permissions{"*": PermissionWrite, "id-token": PermissionNone}, "id-token", PermissionWrite, false},
+		{"empty map", parsePermissions(map[string]interface{}{}), "contents", PermissionRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.permissions.Grants(tt.scope, tt.level); got != tt.want {
+				t.Errorf("Grants(%q, %q) = %v, want %v", tt.scope, tt.level, got, tt.want)
+			}
+		})
+	}
+}
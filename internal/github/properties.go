@@ -0,0 +1,43 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// RepositoryProperties returns the custom property values of every repository in an
+// organization, keyed by repository full name and property name. Multi-select properties
+// have one entry per selected value.
+func (c *Client) RepositoryProperties(ctx context.Context, org string) (map[string]map[string][]string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	result := make(map[string]map[string][]string)
+	for {
+		repos, resp, err := c.client.Organizations.ListCustomPropertyValues(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list custom property values: %w", err)
+		}
+
+		for _, repo := range repos {
+			properties := make(map[string][]string)
+			for _, property := range repo.Properties {
+				switch value := property.Value.(type) {
+				case string:
+					properties[property.PropertyName] = []string{value}
+				case []string:
+					properties[property.PropertyName] = value
+				}
+			}
+			result[repo.RepositoryFullName] = properties
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
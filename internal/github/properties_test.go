@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRepositoryProperties(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path != "/orgs/test-org/properties/values" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"repository_full_name": "test-org/payments", "properties": [
+				{"property_name": "data-classification", "value": "restricted"},
+				{"property_name": "teams", "value": ["payments", "platform"]},
+				{"property_name": "unset", "value": null}
+			]},
+			{"repository_full_name": "test-org/docs", "properties": []}
+		]`)
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	properties, err := client.RepositoryProperties(context.Background(), "test-org")
+	if err != nil {
+		t.Fatalf("RepositoryProperties returned error: %v", err)
+	}
+
+	expected := map[string]map[string][]string{
+		"test-org/payments": {
+			"data-classification": {"restricted"},
+			"teams":               {"payments", "platform"},
+		},
+		"test-org/docs": {},
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("Expected %v, got %v", expected, properties)
+	}
+}
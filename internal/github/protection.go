@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Ways workflow files can be protected from unreviewed changes
+const (
+	ProtectionRulesetReview   = "ruleset_review"        // A ruleset requires approving reviews on the default branch
+	ProtectionBranchReview    = "branch_protection"     // Branch protection requires approving reviews on the default branch
+	ProtectionCodeOwners      = "codeowners_review"     // Code owner review is required and CODEOWNERS covers every workflow file
+	ProtectionPathRestriction = "file_path_restriction" // A push ruleset restricts changes to workflow files
+)
+
+// WorkflowProtection describes how a repository's workflow files are protected from changes
+// that nobody reviewed
+type WorkflowProtection struct {
+	Protected bool     `json:"protected"`
+	Branch    string   `json:"branch"`            // Default branch whose rules were checked
+	Methods   []string `json:"methods,omitempty"` // Protections in place
+}
+
+// GetWorkflowProtection checks whether changes to the repository's workflow files need review
+// through rulesets or branch protection on the default branch, or are restricted by a push
+// ruleset. Code owner review only protects workflow files when CODEOWNERS assigns owners to
+// every path in workflowPaths. Branch protection that cannot be read, for example without
+// administration access, is treated as absent.
+func (c *Client) GetWorkflowProtection(ctx context.Context, owner, repo string, workflowPaths []string) (WorkflowProtection, error) {
+	repository, _, err := c.client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return WorkflowProtection{}, fmt.Errorf("failed to get repository %s/%s: %w", owner, repo, err)
+	}
+	protection := WorkflowProtection{Branch: repository.GetDefaultBranch()}
+
+	codeOwnerReview := false
+	rules, _, err := c.client.Repositories.GetRulesForBranch(ctx, owner, repo, protection.Branch)
+	if err != nil {
+		return WorkflowProtection{}, fmt.Errorf("failed to get rules for %s/%s@%s: %w", owner, repo, protection.Branch, err)
+	}
+	if rules != nil {
+		for _, rule := range rules.PullRequest {
+			if rule.Parameters.RequiredApprovingReviewCount > 0 {
+				protection.add(ProtectionRulesetReview)
+			}
+			codeOwnerReview = codeOwnerReview || rule.Parameters.RequireCodeOwnerReview
+		}
+		for _, rule := range rules.FilePathRestriction {
+			for _, restricted := range rule.Parameters.RestrictedFilePaths {
+				if restrictsWorkflows(restricted) {
+					protection.add(ProtectionPathRestriction)
+				}
+			}
+		}
+	}
+
+	if branchProtection, _, err := c.client.Repositories.GetBranchProtection(ctx, owner, repo, protection.Branch); err == nil {
+		if reviews := branchProtection.GetRequiredPullRequestReviews(); reviews != nil {
+			if reviews.RequiredApprovingReviewCount > 0 {
+				protection.add(ProtectionBranchReview)
+			}
+			codeOwnerReview = codeOwnerReview || reviews.RequireCodeOwnerReviews
+		}
+	}
+
+	if codeOwnerReview && len(workflowPaths) > 0 {
+		owners := c.GetCodeOwners(ctx, owner, repo)
+		covered := true
+		for _, workflowPath := range workflowPaths {
+			if len(owners.OwnersFor(workflowPath)) == 0 {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			protection.add(ProtectionCodeOwners)
+		}
+	}
+
+	protection.Protected = len(protection.Methods) > 0
+	return protection, nil
+}
+
+// add records a protection method once
+func (p *WorkflowProtection) add(method string) {
+	for _, existing := range p.Methods {
+		if existing == method {
+			return
+		}
+	}
+	p.Methods = append(p.Methods, method)
+}
+
+// workflowRestrictions are restricted file path patterns that cover every workflow file
+var workflowRestrictions = []string{
+	".github/workflows/*", ".github/workflows/**", ".github/workflows/**/*",
+	".github/**", ".github/**/*", "**", "**/*",
+}
+
+// restrictsWorkflows reports whether a restricted file path pattern of a push ruleset covers
+// the workflows directory
+func restrictsWorkflows(pattern string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	for _, restriction := range workflowRestrictions {
+		if pattern == restriction {
+			return true
+		}
+	}
+	ok, _ := path.Match(pattern, ".github/workflows/workflow.yml")
+	return ok
+}
@@ -0,0 +1,83 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetWorkflowProtection(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/org/ruleset", "/repos/org/classic", "/repos/org/codeowners", "/repos/org/open", "/repos/org/restricted":
+			fmt.Fprint(w, `{"default_branch": "main"}`)
+		case "/repos/org/ruleset/rules/branches/main":
+			fmt.Fprint(w, `[{"type": "pull_request", "ruleset_source_type": "Organization", "ruleset_source": "org", "ruleset_id": 1, "parameters": {"required_approving_review_count": 1}}]`)
+		case "/repos/org/codeowners/rules/branches/main":
+			fmt.Fprint(w, `[{"type": "pull_request", "ruleset_source_type": "Repository", "ruleset_source": "org/codeowners", "ruleset_id": 2, "parameters": {"required_approving_review_count": 0, "require_code_owner_review": true}}]`)
+		case "/repos/org/restricted/rules/branches/main":
+			fmt.Fprint(w, `[{"type": "file_path_restriction", "ruleset_source_type": "Organization", "ruleset_source": "org", "ruleset_id": 3, "parameters": {"restricted_file_paths": [".github/workflows/**"]}}]`)
+		case "/repos/org/classic/rules/branches/main", "/repos/org/open/rules/branches/main":
+			fmt.Fprint(w, `[]`)
+		case "/repos/org/classic/branches/main/protection":
+			fmt.Fprint(w, `{"required_pull_request_reviews": {"required_approving_review_count": 2}}`)
+		case "/repos/org/codeowners/contents/.github/CODEOWNERS":
+			fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, EncodeContent("/.github/workflows/ @org/platform\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	paths := []string{".github/workflows/ci.yml", ".github/workflows/release.yml"}
+	tests := map[string][]string{
+		"ruleset":    {ProtectionRulesetReview},
+		"classic":    {ProtectionBranchReview},
+		"codeowners": {ProtectionCodeOwners},
+		"restricted": {ProtectionPathRestriction},
+		"open":       nil,
+	}
+	for repo, methods := range tests {
+		protection, err := client.GetWorkflowProtection(ctx, "org", repo, paths)
+		if err != nil {
+			t.Fatalf("GetWorkflowProtection(%s) error = %v", repo, err)
+		}
+		if !reflect.DeepEqual(protection.Methods, methods) || protection.Protected != (methods != nil) || protection.Branch != "main" {
+			t.Errorf("GetWorkflowProtection(%s) = %+v, want methods %v", repo, protection, methods)
+		}
+	}
+
+	// Code owner review does not protect workflow files without owners
+	protection, err := client.GetWorkflowProtection(ctx, "org", "codeowners", []string{".github/workflows/ci.yml", "deploy/.github/workflows/other.yml"})
+	if err != nil || protection.Protected {
+		t.Errorf("GetWorkflowProtection() = (%+v, %v), want unprotected", protection, err)
+	}
+
+	if _, err := client.GetWorkflowProtection(ctx, "org", "missing", paths); err == nil {
+		t.Error("Expected error for a missing repository, got nil")
+	}
+}
+
+func TestRestrictsWorkflows(t *testing.T) {
+	tests := map[string]bool{
+		".github/workflows/**":    true,
+		"/.github/**":             true,
+		".github/workflows/*.yml": true,
+		"**":                      true,
+		".github/*":               false,
+		"src/**":                  false,
+	}
+	for pattern, expected := range tests {
+		if got := restrictsWorkflows(pattern); got != expected {
+			t.Errorf("restrictsWorkflows(%q) = %v, want %v", pattern, got, expected)
+		}
+	}
+}
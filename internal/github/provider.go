@@ -0,0 +1,66 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Supported forge providers
+const (
+	ProviderGitHub  = "github"
+	ProviderGitea   = "gitea"
+	ProviderForgejo = "forgejo"
+)
+
+// NewClientForProvider creates a client for the given forge provider whose requests use the
+// given HTTP settings. Gitea and Forgejo expose a GitHub-compatible REST API under /api/v1,
+// so the same client is used with its base URL pointed at the self-hosted instance.
+func NewClientForProvider(token, provider, baseURL string, httpConfig HTTPConfig) (*Client, error) {
+	client, err := NewClientWithHTTPConfig(token, httpConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(provider) {
+	case "", ProviderGitHub:
+		if baseURL == "" {
+			return client, nil
+		}
+		// GitHub Enterprise Server
+		enterpriseClient, err := client.client.WithEnterpriseURLs(baseURL, baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub base URL %s: %w", baseURL, err)
+		}
+		client.client = enterpriseClient
+	case ProviderGitea, ProviderForgejo:
+		if baseURL == "" {
+			return nil, fmt.Errorf("a base URL is required for the %s provider", provider)
+		}
+		apiURL, err := forgeAPIURL(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		client.client.BaseURL = apiURL
+		client.client.UploadURL = apiURL
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s, must be 'github', 'gitea' or 'forgejo'", provider)
+	}
+
+	return client, nil
+}
+
+// forgeAPIURL returns the API root for a Gitea/Forgejo instance
+func forgeAPIURL(baseURL string) (*url.URL, error) {
+	trimmed := strings.TrimSuffix(baseURL, "/")
+	if !strings.HasSuffix(trimmed, "/api/v1") {
+		trimmed += "/api/v1"
+	}
+
+	apiURL, err := url.Parse(trimmed + "/")
+	if err != nil || apiURL.Host == "" {
+		return nil, fmt.Errorf("invalid base URL: %s", baseURL)
+	}
+
+	return apiURL, nil
+}
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewClientForProvider(t *testing.T) {
+	t.Run("default github", func(t *testing.T) {
+		client, err := NewClientForProvider("token", "", "", HTTPConfig{})
+		if err != nil {
+			t.Fatalf("NewClientForProvider returned error: %v", err)
+		}
+		if client.client.BaseURL.String() != "https://api.github.com/" {
+			t.Errorf("Expected default GitHub API URL, got %s", client.client.BaseURL)
+		}
+	})
+
+	t.Run("gitea requires base url", func(t *testing.T) {
+		if _, err := NewClientForProvider("token", ProviderGitea, "", HTTPConfig{}); err == nil {
+			t.Error("Expected error when base URL is missing, got nil")
+		}
+	})
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		if _, err := NewClientForProvider("token", "bitbucket", "", HTTPConfig{}); err == nil {
+			t.Error("Expected error for unsupported provider, got nil")
+		}
+	})
+
+	t.Run("forgejo api path", func(t *testing.T) {
+		for _, baseURL := range []string{"https://code.example.com", "https://code.example.com/api/v1/"} {
+			client, err := NewClientForProvider("token", ProviderForgejo, baseURL, HTTPConfig{})
+			if err != nil {
+				t.Fatalf("NewClientForProvider returned error: %v", err)
+			}
+			if client.client.BaseURL.String() != "https://code.example.com/api/v1/" {
+				t.Errorf("Expected API URL for %s to be https://code.example.com/api/v1/, got %s", baseURL, client.client.BaseURL)
+			}
+		}
+	})
+}
+
+func TestGiteaListRepositories(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/orgs/test-org/repos" {
+			t.Errorf("Expected path to be /api/v1/orgs/test-org/repos, got %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, CreateMockRepositoriesResponse([]Repository{
+			{Name: "repo1", FullName: "test-org/repo1"},
+		}))
+	})
+
+	server := httptest.NewServer(mockHandler)
+	defer server.Close()
+
+	client, err := NewClientForProvider("token", ProviderGitea, server.URL, HTTPConfig{})
+	if err != nil {
+		t.Fatalf("NewClientForProvider returned error: %v", err)
+	}
+
+	repos, err := client.ListRepositories(context.Background(), "test-org")
+	if err != nil {
+		t.Fatalf("ListRepositories returned error: %v", err)
+	}
+
+	if len(repos) != 1 || repos[0].FullName != "test-org/repo1" {
+		t.Errorf("Expected single repository test-org/repo1, got %+v", repos)
+	}
+}
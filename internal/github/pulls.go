@@ -0,0 +1,150 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// Commit status states
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusError   = "error"
+)
+
+// PullRequest identifies the commits a pull request merges
+type PullRequest struct {
+	Number  int
+	HeadSHA string
+	BaseRef string
+	BaseSHA string
+	// BasePaths holds the path of each changed workflow file at the base commit by its path at
+	// the head commit, leaving out added files
+	BasePaths map[string]string
+}
+
+// isScannedPath reports whether a repository file is a workflow file or a composite action
+// under the scan paths, as scans read them
+func (c *Client) isScannedPath(file string) bool {
+	name := path.Base(file)
+	if path.Dir(file) == c.workflowsPath() && (strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")) {
+		return true
+	}
+	for _, scanPath := range c.scanPaths {
+		if strings.HasPrefix(file, scanPath+"/") && IsActionPath(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPullRequestWorkflowFiles retrieves the workflow files, and composite actions under the
+// scan paths, that a pull request adds or modifies, as they are at its head commit. Files it
+// removes are left out, as they no longer run.
+func (c *Client) GetPullRequestWorkflowFiles(ctx context.Context, owner, repo string, number int) (PullRequest, []WorkflowFile, error) {
+	pr, _, err := c.client.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return PullRequest{}, nil, fmt.Errorf("failed to get pull request #%d: %w", number, err)
+	}
+	pull := PullRequest{
+		Number:    number,
+		HeadSHA:   pr.GetHead().GetSHA(),
+		BaseRef:   pr.GetBase().GetRef(),
+		BaseSHA:   pr.GetBase().GetSHA(),
+		BasePaths: make(map[string]string),
+	}
+
+	var files []WorkflowFile
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		changed, resp, err := c.client.PullRequests.ListFiles(ctx, owner, repo, number, opts)
+		if err != nil {
+			return pull, nil, fmt.Errorf("failed to list files of pull request #%d: %w", number, err)
+		}
+
+		for _, file := range changed {
+			if file.GetStatus() == "removed" || !c.isScannedPath(file.GetFilename()) {
+				continue
+			}
+			// Blobs of pull requests from forks are reachable from the base repository
+			content, _, err := c.client.Git.GetBlobRaw(ctx, owner, repo, file.GetSHA())
+			if err != nil {
+				return pull, nil, fmt.Errorf("failed to read %s of pull request #%d: %w", file.GetFilename(), number, err)
+			}
+			switch file.GetStatus() {
+			case "added":
+			case "renamed":
+				pull.BasePaths[file.GetFilename()] = file.GetPreviousFilename()
+			default:
+				pull.BasePaths[file.GetFilename()] = file.GetFilename()
+			}
+			files = append(files, WorkflowFile{
+				Name:    path.Base(file.GetFilename()),
+				Path:    file.GetFilename(),
+				SHA:     file.GetSHA(),
+				Ref:     pr.GetHead().GetRef(),
+				Content: content,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return pull, files, nil
+}
+
+// GetPullRequestBaseFiles retrieves the changed workflow files of a pull request as they are at
+// its base commit, to tell the findings its changes introduce from those they keep
+func (c *Client) GetPullRequestBaseFiles(ctx context.Context, owner, repo string, pull PullRequest) ([]WorkflowFile, error) {
+	var files []WorkflowFile
+	for _, basePath := range pull.BasePaths {
+		content, _, resp, err := c.client.Repositories.GetContents(ctx, owner, repo, basePath, &github.RepositoryContentGetOptions{Ref: pull.BaseSHA})
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s at the base of pull request #%d: %w", basePath, pull.Number, err)
+		}
+		decoded, err := content.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", basePath, err)
+		}
+		files = append(files, WorkflowFile{
+			Name:    path.Base(basePath),
+			Path:    basePath,
+			SHA:     content.GetSHA(),
+			Ref:     pull.BaseRef,
+			Content: []byte(decoded),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+// CreateStatus sets a commit status, such as the outcome of a required status check, on a
+// commit. The description is cut to the 140 characters GitHub accepts.
+func (c *Client) CreateStatus(ctx context.Context, owner, repo, sha, state, statusContext, description, targetURL string) error {
+	if runes := []rune(description); len(runes) > 140 {
+		description = string(runes[:139]) + "…"
+	}
+	status := &github.RepoStatus{State: &state, Context: &statusContext, Description: &description}
+	if targetURL != "" {
+		status.TargetURL = &targetURL
+	}
+	if _, _, err := c.client.Repositories.CreateStatus(ctx, owner, repo, sha, status); err != nil {
+		return fmt.Errorf("failed to set commit status: %w", err)
+	}
+	return nil
+}
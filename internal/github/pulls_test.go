@@ -0,0 +1,130 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetPullRequestWorkflowFiles(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/7":
+			fmt.Fprint(w, `{"number": 7, "head": {"sha": "head1", "ref": "feature"}, "base": {"ref": "main", "sha": "base1"}}`)
+		case "/repos/owner/repo/pulls/7/files":
+			fmt.Fprint(w, `[
+                {"filename": ".github/workflows/ci.yml", "status": "modified", "sha": "b1"},
+                {"filename": ".github/workflows/old.yml", "status": "removed", "sha": "b2"},
+                {"filename": ".github/workflows/nested/other.yml", "status": "added", "sha": "b3"},
+                {"filename": ".github/actions/setup/action.yml", "status": "added", "sha": "b4"},
+                {"filename": "README.md", "status": "modified", "sha": "b5"}
+            ]`)
+		case "/repos/owner/repo/git/blobs/b1":
+			fmt.Fprint(w, CreateMockWorkflowContent())
+		case "/repos/owner/repo/git/blobs/b4":
+			fmt.Fprint(w, "runs:\n  using: composite\n  steps:\n    - uses: actions/cache@v4\n")
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	if err := client.SetScanPaths([]string{".github/actions"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pull, files, err := client.GetPullRequestWorkflowFiles(context.Background(), "owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("GetPullRequestWorkflowFiles returned error: %v", err)
+	}
+	if pull.HeadSHA != "head1" || pull.BaseRef != "main" {
+		t.Errorf("Unexpected pull request: %+v", pull)
+	}
+	if len(files) != 2 || files[0].Path != ".github/workflows/ci.yml" || files[1].Path != ".github/actions/setup/action.yml" {
+		t.Fatalf("Expected the changed workflow and composite action, got %+v", files)
+	}
+	if files[0].Ref != "feature" || files[0].SHA != "b1" {
+		t.Errorf("Expected the file at the head branch, got %+v", files[0])
+	}
+	if len(pull.BasePaths) != 1 || pull.BasePaths[".github/workflows/ci.yml"] != ".github/workflows/ci.yml" {
+		t.Errorf("Expected the base path of the modified workflow only, got %v", pull.BasePaths)
+	}
+	if actions := ExtractActions(files); len(actions) != 3 {
+		t.Errorf("Expected 3 actions from the changed files, got %d", len(actions))
+	}
+}
+
+func TestCreateStatus(t *testing.T) {
+	var status map[string]string
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/owner/repo/statuses/head1" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&status)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	description := strings.Repeat("é", 200)
+	if err := client.CreateStatus(context.Background(), "owner", "repo", "head1", StatusFailure, "action-control", description, ""); err != nil {
+		t.Fatalf("CreateStatus returned error: %v", err)
+	}
+	if status["state"] != "failure" || status["context"] != "action-control" {
+		t.Errorf("Unexpected status: %v", status)
+	}
+	if got := []rune(status["description"]); len(got) != 140 {
+		t.Errorf("Expected the description cut to 140 characters, got %d", len(got))
+	}
+	if _, ok := status["target_url"]; ok {
+		t.Errorf("Expected no target URL, got %q", status["target_url"])
+	}
+}
+
+func TestGetPullRequestBaseFiles(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("ref") != "base1" {
+			t.Errorf("Expected files read at the base commit, got ref %q", r.URL.Query().Get("ref"))
+		}
+
+		switch r.URL.Path {
+		case "/repos/owner/repo/contents/.github/workflows/old-name.yml":
+			fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "sha": "a1", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(CreateMockWorkflowContent())))
+		case "/repos/owner/repo/contents/.github/workflows/gone.yml":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	pull := PullRequest{Number: 7, BaseSHA: "base1", BaseRef: "main", BasePaths: map[string]string{
+		".github/workflows/new-name.yml": ".github/workflows/old-name.yml",
+		".github/workflows/gone.yml":     ".github/workflows/gone.yml",
+	}}
+	files, err := client.GetPullRequestBaseFiles(context.Background(), "owner", "repo", pull)
+	if err != nil {
+		t.Fatalf("GetPullRequestBaseFiles returned error: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != ".github/workflows/old-name.yml" || files[0].Ref != "main" {
+		t.Fatalf("Expected the renamed workflow at its base path, got %+v", files)
+	}
+	if actions := ExtractActions(files); len(actions) != 2 {
+		t.Errorf("Expected 2 actions from the base workflow, got %d", len(actions))
+	}
+}
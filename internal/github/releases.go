@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// Release is a published release and the files attached to it
+type Release struct {
+	Tag    string
+	Assets map[string]int64 // Asset IDs by file name
+}
+
+// GetLatestRelease returns the latest published release of a repository
+func (c *Client) GetLatestRelease(ctx context.Context, owner, repo string) (Release, error) {
+	release, _, err := c.client.Repositories.GetLatestRelease(ctx, owner, repo)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to get latest release of %s/%s: %w", owner, repo, err)
+	}
+	return newRelease(release), nil
+}
+
+// GetReleaseByTag returns the release of a repository published for a tag
+func (c *Client) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (Release, error) {
+	release, _, err := c.client.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to get release %s of %s/%s: %w", tag, owner, repo, err)
+	}
+	return newRelease(release), nil
+}
+
+// DownloadReleaseAsset returns the content of a release asset
+func (c *Client) DownloadReleaseAsset(ctx context.Context, owner, repo string, id int64) ([]byte, error) {
+	downloads := c.downloads
+	if downloads == nil {
+		downloads = http.DefaultClient
+	}
+	rc, _, err := c.client.Repositories.DownloadReleaseAsset(ctx, owner, repo, id, downloads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download release asset %d of %s/%s: %w", id, owner, repo, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release asset %d of %s/%s: %w", id, owner, repo, err)
+	}
+	return content, nil
+}
+
+// newRelease converts a GitHub release
+func newRelease(release *github.RepositoryRelease) Release {
+	result := Release{Tag: release.GetTagName(), Assets: make(map[string]int64)}
+	for _, asset := range release.Assets {
+		result.Assets[asset.GetName()] = asset.GetID()
+	}
+	return result
+}
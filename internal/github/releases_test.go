@@ -0,0 +1,43 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestReleases(t *testing.T) {
+	_, client := MockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/tool/releases/latest":
+			fmt.Fprint(w, `{"tag_name": "v1.2.0", "assets": [{"id": 7, "name": "tool-linux-amd64"}]}`)
+		case "/repos/owner/tool/releases/tags/v1.1.0":
+			fmt.Fprint(w, `{"tag_name": "v1.1.0", "assets": []}`)
+		case "/repos/owner/tool/releases/assets/7":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			fmt.Fprint(w, "binary")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	latest, err := client.GetLatestRelease(ctx, "owner", "tool")
+	if err != nil || latest.Tag != "v1.2.0" || latest.Assets["tool-linux-amd64"] != 7 {
+		t.Fatalf("Unexpected latest release %+v, %v", latest, err)
+	}
+
+	tagged, err := client.GetReleaseByTag(ctx, "owner", "tool", "v1.1.0")
+	if err != nil || tagged.Tag != "v1.1.0" {
+		t.Errorf("Unexpected tagged release %+v, %v", tagged, err)
+	}
+	if _, err := client.GetReleaseByTag(ctx, "owner", "tool", "v9.9.9"); err == nil {
+		t.Error("Expected an error for a missing release")
+	}
+
+	content, err := client.DownloadReleaseAsset(ctx, "owner", "tool", 7)
+	if err != nil || string(content) != "binary" {
+		t.Errorf("Unexpected asset content %q, %v", content, err)
+	}
+}
@@ -13,10 +13,20 @@ type Repository struct {
 	FullName    string
 	Description string
 	IsPrivate   bool
+	Visibility  string // "public", "private" or "internal"
+	// DefaultBranch is only set by GetRepository
+	DefaultBranch string
 }
 
-// ListRepositories retrieves all repositories for an organization
+// ListRepositories retrieves all repositories for an organization, from the repository cache
+// when it holds a fresh listing
 func (c *Client) ListRepositories(ctx context.Context, org string) ([]Repository, error) {
+	if c.repoCache != nil {
+		if repos, ok := c.repoCache.Repositories(org); ok {
+			return repos, nil
+		}
+	}
+
 	opts := &github.RepositoryListByOrgOptions{
 		ListOptions: github.ListOptions{
 			PerPage: 100, // Adjust as needed
@@ -36,6 +46,7 @@ func (c *Client) ListRepositories(ctx context.Context, org string) ([]Repository
 				FullName:    repo.GetFullName(),
 				Description: repo.GetDescription(),
 				IsPrivate:   repo.GetPrivate(),
+				Visibility:  repo.GetVisibility(),
 			})
 		}
 
@@ -45,5 +56,26 @@ func (c *Client) ListRepositories(ctx context.Context, org string) ([]Repository
 		opts.Page = resp.NextPage
 	}
 
+	// A listing that can't be cached is still usable
+	if c.repoCache != nil {
+		_ = c.repoCache.StoreRepositories(org, allRepos)
+	}
+
 	return allRepos, nil
 }
+
+// GetRepository returns a repository's details, including its default branch
+func (c *Client) GetRepository(ctx context.Context, owner, repo string) (Repository, error) {
+	repository, _, err := c.client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to get repository %s/%s: %w", owner, repo, err)
+	}
+	return Repository{
+		Name:          repository.GetName(),
+		FullName:      repository.GetFullName(),
+		Description:   repository.GetDescription(),
+		IsPrivate:     repository.GetPrivate(),
+		Visibility:    repository.GetVisibility(),
+		DefaultBranch: repository.GetDefaultBranch(),
+	}, nil
+}
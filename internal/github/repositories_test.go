@@ -60,3 +60,80 @@ func TestListRepositories(t *testing.T) {
 		}
 	})
 }
+
+// fakeRepositoryCache keeps repository listings in memory
+type fakeRepositoryCache struct {
+	listings map[string][]Repository
+}
+
+func (f *fakeRepositoryCache) Repositories(org string) ([]Repository, bool) {
+	repos, ok := f.listings[org]
+	return repos, ok
+}
+
+func (f *fakeRepositoryCache) StoreRepositories(org string, repos []Repository) error {
+	f.listings[org] = repos
+	return nil
+}
+
+func TestListRepositoriesCache(t *testing.T) {
+	requests := 0
+	server, client := MockServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, CreateMockRepositoriesResponse([]Repository{{Name: "repo1", FullName: "org/repo1"}}))
+	}))
+	defer server.Close()
+
+	cache := &fakeRepositoryCache{listings: map[string][]Repository{
+		"cached-org": {{Name: "cached", FullName: "cached-org/cached"}},
+	}}
+	client.SetRepositoryCache(cache)
+
+	// A cached listing is returned without enumerating the organization
+	repos, err := client.ListRepositories(context.Background(), "cached-org")
+	if err != nil {
+		t.Fatalf("ListRepositories returned error: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "cached" || requests != 0 {
+		t.Errorf("Expected the cached listing without requests, got %+v after %d requests", repos, requests)
+	}
+
+	// A fetched listing is stored for the next run
+	repos, err = client.ListRepositories(context.Background(), "org")
+	if err != nil {
+		t.Fatalf("ListRepositories returned error: %v", err)
+	}
+	if len(repos) != 1 || requests != 1 {
+		t.Fatalf("Expected 1 fetched repository after 1 request, got %+v after %d requests", repos, requests)
+	}
+	if stored := cache.listings["org"]; len(stored) != 1 || stored[0].Name != "repo1" {
+		t.Errorf("Expected the fetched listing to be cached, got %+v", stored)
+	}
+}
+
+func TestGetRepository(t *testing.T) {
+	server, client := MockServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/org/repo" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name": "repo", "full_name": "org/repo", "private": true, "visibility": "internal", "default_branch": "trunk"}`)
+	}))
+	defer server.Close()
+
+	repository, err := client.GetRepository(context.Background(), "org", "repo")
+	if err != nil {
+		t.Fatalf("GetRepository returned error: %v", err)
+	}
+	expected := Repository{Name: "repo", FullName: "org/repo", IsPrivate: true, Visibility: "internal", DefaultBranch: "trunk"}
+	if repository != expected {
+		t.Errorf("Expected %+v, got %+v", expected, repository)
+	}
+
+	if _, err := client.GetRepository(context.Background(), "org", "missing"); err == nil {
+		t.Error("Expected an error for a missing repository")
+	}
+}
@@ -0,0 +1,65 @@
+package github
+
+import (
+	"path"
+	"strings"
+)
+
+// ReusableWorkflow is a reusable workflow called by a job-level uses
+type ReusableWorkflow struct {
+	Owner string // Empty for a workflow in the calling repository
+	Repo  string
+	Path  string // Path of the workflow file, such as .github/workflows/build.yml
+	Ref   string // Version the workflow is called at; empty for a workflow in the calling repository
+}
+
+// WorkflowCall is a job calling a reusable workflow
+type WorkflowCall struct {
+	Uses     string `json:"uses"`
+	Workflow string `json:"workflow"` // Path of the calling workflow file
+	Job      string `json:"job"`
+	Line     int    `json:"line,omitempty"`
+	Ref      string `json:"ref,omitempty"` // Branch of the calling workflow file; empty for the default branch
+}
+
+// ParseReusableWorkflow parses a uses value calling a reusable workflow, either
+// owner/repo/.github/workflows/file.yml@ref or ./.github/workflows/file.yml in the
+// calling repository. It reports false for step actions.
+func ParseReusableWorkflow(uses string) (ReusableWorkflow, bool) {
+	if local, ok := strings.CutPrefix(uses, "./"); ok {
+		if !isWorkflowPath(local) {
+			return ReusableWorkflow{}, false
+		}
+		return ReusableWorkflow{Path: local}, true
+	}
+
+	reference, ref, ok := strings.Cut(uses, "@")
+	if !ok {
+		return ReusableWorkflow{}, false
+	}
+	parts := strings.SplitN(reference, "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || !isWorkflowPath(parts[2]) {
+		return ReusableWorkflow{}, false
+	}
+	return ReusableWorkflow{Owner: parts[0], Repo: parts[1], Path: parts[2], Ref: ref}, true
+}
+
+// Local reports whether the workflow is in the calling repository
+func (w ReusableWorkflow) Local() bool {
+	return w.Owner == ""
+}
+
+// Qualified names the workflow file as owner/repo/path, resolving local workflows against
+// the calling repository given as owner/repo
+func (w ReusableWorkflow) Qualified(callingRepo string) string {
+	if w.Local() {
+		return callingRepo + "/" + w.Path
+	}
+	return w.Owner + "/" + w.Repo + "/" + w.Path
+}
+
+// isWorkflowPath reports whether a path names a workflow file directly in .github/workflows
+func isWorkflowPath(file string) bool {
+	ext := path.Ext(file)
+	return path.Dir(file) == ".github/workflows" && (ext == ".yml" || ext == ".yaml")
+}
@@ -0,0 +1,31 @@
+package github
+
+import "testing"
+
+func TestParseReusableWorkflow(t *testing.T) {
+	tests := []struct {
+		uses      string
+		expected  ReusableWorkflow
+		reusable  bool
+		qualified string
+	}{
+		{"org/workflows/.github/workflows/build.yml@v1", ReusableWorkflow{Owner: "org", Repo: "workflows", Path: ".github/workflows/build.yml", Ref: "v1"}, true, "org/workflows/.github/workflows/build.yml"},
+		{"./.github/workflows/deploy.yaml", ReusableWorkflow{Path: ".github/workflows/deploy.yaml"}, true, "org/app/.github/workflows/deploy.yaml"},
+		{"actions/checkout@v4", ReusableWorkflow{}, false, ""},
+		{"org/repo/path/to/action@v1", ReusableWorkflow{}, false, ""},
+		{"./.github/actions/setup", ReusableWorkflow{}, false, ""},
+		{"org/repo/.github/workflows/nested/build.yml@v1", ReusableWorkflow{}, false, ""},
+		{"org/repo/.github/workflows/build.yml", ReusableWorkflow{}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uses, func(t *testing.T) {
+			workflow, ok := ParseReusableWorkflow(tt.uses)
+			if ok != tt.reusable || workflow != tt.expected {
+				t.Fatalf("ParseReusableWorkflow() = %+v, %v, want %+v, %v", workflow, ok, tt.expected, tt.reusable)
+			}
+			if ok && workflow.Qualified("org/app") != tt.qualified {
+				t.Errorf("Qualified() = %q, want %q", workflow.Qualified("org/app"), tt.qualified)
+			}
+		})
+	}
+}
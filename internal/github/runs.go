@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// SetRunSource switches workflow discovery to the Actions API: instead of the files currently in
+// the repository, the workflow files that ran since the given time are scanned at the commit they
+// ran at. This also covers workflows that were deleted or only exist on other branches.
+func (c *Client) SetRunSource(since time.Time) {
+	c.runsSince = since
+}
+
+// getWorkflowFilesFromRuns retrieves the workflow files of runs created since the run source
+// cutoff. Each distinct file version is fetched once, with Ref set to the branch it ran on.
+func (c *Client) getWorkflowFilesFromRuns(ctx context.Context, owner, repo string) ([]WorkflowFile, error) {
+	opts := &github.ListWorkflowRunsOptions{
+		Created:     ">=" + c.runsSince.UTC().Format(time.DateOnly),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var files []WorkflowFile
+	fetched := make(map[string]bool) // Workflow paths already fetched at a commit
+	seen := make(map[string]bool)    // Blob SHAs already collected
+
+	for {
+		runs, resp, err := c.client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+		}
+
+		for _, run := range runs.WorkflowRuns {
+			// Dynamic runs such as code scanning default setup have no workflow file
+			workflowPath := run.GetPath()
+			if path.Dir(workflowPath) != githubDir+"/workflows" {
+				continue
+			}
+
+			key := workflowPath + "@" + run.GetHeadSHA()
+			if fetched[key] {
+				continue
+			}
+			fetched[key] = true
+
+			file, _, _, err := c.client.Repositories.GetContents(ctx, owner, repo, workflowPath,
+				&github.RepositoryContentGetOptions{Ref: run.GetHeadSHA()})
+			if err != nil || file == nil {
+				continue // Skip files we can't access, e.g. runs of force-pushed commits
+			}
+			if seen[file.GetSHA()] {
+				continue
+			}
+			seen[file.GetSHA()] = true
+
+			content, err := file.GetContent()
+			if err != nil {
+				continue
+			}
+
+			files = append(files, WorkflowFile{
+				Name:    path.Base(workflowPath),
+				Path:    workflowPath,
+				SHA:     file.GetSHA(),
+				Ref:     run.GetHeadBranch(),
+				Content: []byte(content),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return files, nil
+}
+
+// ParseSince parses a look-back window such as "30d", "12h" or "2w" into a duration. Day and
+// week units are supported in addition to the units accepted by time.ParseDuration.
+func ParseSince(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		count, ok := strings.CutSuffix(value, suffix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return duration, nil
+}
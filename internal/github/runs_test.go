@@ -0,0 +1,101 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetWorkflowFilesFromRuns(t *testing.T) {
+	deleted := "name: Deleted\non: push\njobs:\n  build:\n    steps:\n      - uses: evil/action@v1\n"
+	encoded := func(content string) string {
+		return base64.StdEncoding.EncodeToString([]byte(content))
+	}
+
+	var contentRequests int
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/runs":
+			if got := r.URL.Query().Get("created"); got != ">=2025-01-01" {
+				t.Errorf("Expected created filter >=2025-01-01, got %q", got)
+			}
+			fmt.Fprint(w, `{"total_count": 4, "workflow_runs": [
+				{"path": ".github/workflows/ci.yml", "head_sha": "c1", "head_branch": "main"},
+				{"path": ".github/workflows/ci.yml", "head_sha": "c1", "head_branch": "main"},
+				{"path": ".github/workflows/ci.yml", "head_sha": "c2", "head_branch": "main"},
+				{"path": ".github/workflows/old.yml", "head_sha": "c0", "head_branch": "feature"},
+				{"path": "dynamic/github-code-scanning/codeql", "head_sha": "c1", "head_branch": "main"}
+			]}`)
+		case "/repos/owner/repo/contents/.github/workflows/ci.yml":
+			contentRequests++
+			// The workflow is unchanged between c1 and c2
+			fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "sha": "b1", "content": %q}`, encoded(CreateMockWorkflowContent()))
+		case "/repos/owner/repo/contents/.github/workflows/old.yml":
+			contentRequests++
+			if ref := r.URL.Query().Get("ref"); ref != "c0" {
+				t.Errorf("Expected file to be fetched at the run commit, got ref %q", ref)
+			}
+			fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "sha": "b2", "content": %q}`, encoded(deleted))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	client.SetRunSource(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	files, err := client.GetWorkflowFiles(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("GetWorkflowFiles returned error: %v", err)
+	}
+
+	if contentRequests != 3 {
+		t.Errorf("Expected each workflow to be fetched once per commit, got %d requests", contentRequests)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 distinct workflow files, got %d", len(files))
+	}
+	if files[1].Path != ".github/workflows/old.yml" || files[1].Ref != "feature" {
+		t.Errorf("Unexpected file from deleted workflow: %+v", files[1])
+	}
+
+	actions := ExtractActions(files)
+	if last := actions[len(actions)-1]; last.Uses != "evil/action@v1" {
+		t.Errorf("Expected action of deleted workflow, got %+v", last)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"0d", 0, true},
+		{"-1d", 0, true},
+		{"xd", 0, true},
+		{"30", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// Kinds of action, by how they run
+const (
+	ActionKindDocker     = "docker"
+	ActionKindJavaScript = "javascript"
+	ActionKindComposite  = "composite"
+)
+
+// ActionRuntime describes how an action runs, as declared by the runs section of its action.yml
+type ActionRuntime struct {
+	Action     string   `json:"action"`                // Action reference, such as "actions/checkout@v4"
+	Kind       string   `json:"kind"`                  // docker, javascript or composite; empty when unrecognized
+	Using      string   `json:"using"`                 // Declared runtime, such as "node20" or "docker"
+	Image      string   `json:"image,omitempty"`       // Image of Docker actions, or the Dockerfile they are built from
+	BaseImages []string `json:"base_images,omitempty"` // Images the Dockerfile of an action built from source starts from
+}
+
+// BuiltFromSource reports whether a Docker action is built from a Dockerfile in its repository
+// rather than run from a prebuilt image
+func (r ActionRuntime) BuiltFromSource() bool {
+	return r.Kind == ActionKindDocker && r.Image != "" && !strings.HasPrefix(r.Image, "docker://")
+}
+
+// GetActionRuntime retrieves the action.yml of an action at the referenced version and
+// classifies how it runs. For Docker actions built from a Dockerfile, the Dockerfile is read for
+// its base images. Container image references ("docker://...") are classified without requests.
+// Results are cached for the lifetime of the client.
+func (c *Client) GetActionRuntime(ctx context.Context, action string) (ActionRuntime, error) {
+	if strings.HasPrefix(action, "docker://") {
+		return ActionRuntime{Action: action, Kind: ActionKindDocker, Using: "docker", Image: action}, nil
+	}
+
+	c.mu.Lock()
+	runtime, ok := c.runtimes[action]
+	c.mu.Unlock()
+	if ok {
+		return runtime, nil
+	}
+
+	name, ref, _ := strings.Cut(action, "@")
+	parts := strings.SplitN(name, "/", 3)
+	if strings.HasPrefix(action, "./") || len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return ActionRuntime{}, fmt.Errorf("not a repository action: %s", action)
+	}
+	owner, repo, dir := parts[0], parts[1], ""
+	if len(parts) == 3 {
+		dir = parts[2]
+	}
+
+	var content []byte
+	for _, file := range []string{"action.yml", "action.yaml"} {
+		if found, err := c.getContentAtRef(ctx, owner, repo, path.Join(dir, file), ref); err == nil {
+			content = found
+			break
+		}
+	}
+	if content == nil {
+		return ActionRuntime{}, fmt.Errorf("no action.yml found for %s", action)
+	}
+
+	var metadata struct {
+		Runs struct {
+			Using string `yaml:"using"`
+			Image string `yaml:"image"`
+		} `yaml:"runs"`
+	}
+	if err := unmarshalWorkflow(content, &metadata); err != nil {
+		return ActionRuntime{}, fmt.Errorf("invalid action.yml for %s: %w", action, err)
+	}
+
+	runtime = ActionRuntime{Action: action, Using: metadata.Runs.Using, Image: metadata.Runs.Image}
+	runtime.Kind = ClassifyRuntime(runtime.Using)
+
+	// Actions built from source start from the images named in their Dockerfile
+	if runtime.BuiltFromSource() {
+		dockerfile, err := c.getContentAtRef(ctx, owner, repo, path.Join(dir, runtime.Image), ref)
+		if err == nil {
+			runtime.BaseImages = DockerfileBaseImages(dockerfile)
+		}
+	}
+
+	c.mu.Lock()
+	if c.runtimes == nil {
+		c.runtimes = make(map[string]ActionRuntime)
+	}
+	c.runtimes[action] = runtime
+	c.mu.Unlock()
+
+	return runtime, nil
+}
+
+// getContentAtRef retrieves file content from a repository at a branch, tag or commit
+func (c *Client) getContentAtRef(ctx context.Context, owner, repo, filePath, ref string) ([]byte, error) {
+	file, _, _, err := c.client.Repositories.GetContents(ctx, owner, repo, filePath,
+		&github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s is not a file", filePath)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode content: %w", err)
+	}
+	return []byte(content), nil
+}
+
+// ClassifyRuntime returns the kind of action declaring a runs.using value, or an empty
+// string when the runtime is not recognized
+func ClassifyRuntime(using string) string {
+	switch {
+	case using == "docker":
+		return ActionKindDocker
+	case using == "composite":
+		return ActionKindComposite
+	case strings.HasPrefix(using, "node"):
+		return ActionKindJavaScript
+	}
+	return ""
+}
+
+// DockerfileBaseImages returns the images the FROM instructions of a Dockerfile start from,
+// skipping scratch and earlier build stages
+func DockerfileBaseImages(dockerfile []byte) []string {
+	var images []string
+	stages := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(dockerfile))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+
+		// Skip flags such as --platform
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		image := args[0]
+		if image != "scratch" && !stages[strings.ToLower(image)] && !slices.Contains(images, image) {
+			images = append(images, image)
+		}
+		if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = true
+		}
+	}
+
+	return images
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetActionRuntime(t *testing.T) {
+	files := map[string]string{
+		"/repos/actions/checkout/contents/action.yml@v3": "runs:\n  using: node16\n  main: dist/index.js\n",
+		"/repos/actions/checkout/contents/action.yml@v4": "runs:\n  using: node20\n  main: dist/index.js\n",
+		"/repos/org/tool/contents/lint/action.yaml@v1":   "runs:\n  using: docker\n  image: Dockerfile\n",
+		"/repos/org/tool/contents/lint/Dockerfile@v1":    "FROM golang:1.24 AS build\nFROM --platform=linux/amd64 quay.io/org/base:1\nCOPY --from=build /app /app\n",
+		"/repos/org/setup/contents/action.yml@main":      "runs:\n  using: composite\n  steps: []\n",
+		"/repos/org/prebuilt/contents/action.yml@v2":     "runs:\n  using: docker\n  image: docker://ghcr.io/org/prebuilt:2\n",
+	}
+
+	requests := 0
+	_, client := MockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		content, ok := files[r.URL.Path+"@"+r.URL.Query().Get("ref")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, EncodeContent(content))
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		action   string
+		expected ActionRuntime
+	}{
+		{"actions/checkout@v3", ActionRuntime{Action: "actions/checkout@v3", Kind: ActionKindJavaScript, Using: "node16"}},
+		{"actions/checkout@v4", ActionRuntime{Action: "actions/checkout@v4", Kind: ActionKindJavaScript, Using: "node20"}},
+		{"org/tool/lint@v1", ActionRuntime{Action: "org/tool/lint@v1", Kind: ActionKindDocker, Using: "docker", Image: "Dockerfile", BaseImages: []string{"golang:1.24", "quay.io/org/base:1"}}},
+		{"org/setup@main", ActionRuntime{Action: "org/setup@main", Kind: ActionKindComposite, Using: "composite"}},
+		{"org/prebuilt@v2", ActionRuntime{Action: "org/prebuilt@v2", Kind: ActionKindDocker, Using: "docker", Image: "docker://ghcr.io/org/prebuilt:2"}},
+		{"docker://alpine:3", ActionRuntime{Action: "docker://alpine:3", Kind: ActionKindDocker, Using: "docker", Image: "docker://alpine:3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			runtime, err := client.GetActionRuntime(ctx, tt.action)
+			if err != nil {
+				t.Fatalf("GetActionRuntime returned error: %v", err)
+			}
+			if !reflect.DeepEqual(runtime, tt.expected) {
+				t.Errorf("GetActionRuntime() = %+v, want %+v", runtime, tt.expected)
+			}
+		})
+	}
+
+	// Runtimes are cached
+	before := requests
+	if _, err := client.GetActionRuntime(ctx, "actions/checkout@v4"); err != nil || requests != before {
+		t.Errorf("Expected a cached runtime without requests, got %d requests, %v", requests-before, err)
+	}
+
+	for _, action := range []string{"missing/action@v1", "./local"} {
+		if _, err := client.GetActionRuntime(ctx, action); err == nil {
+			t.Errorf("Expected an error for %s", action)
+		}
+	}
+}
+
+func TestDockerfileBaseImages(t *testing.T) {
+	dockerfile := []byte(`# syntax=docker/dockerfile:1
+FROM node:20-alpine AS deps
+RUN npm ci
+from deps as build
+FROM scratch
+FROM node:20-alpine
+`)
+	expected := []string{"node:20-alpine"}
+	if images := DockerfileBaseImages(dockerfile); !reflect.DeepEqual(images, expected) {
+		t.Errorf("DockerfileBaseImages() = %v, want %v", images, expected)
+	}
+}
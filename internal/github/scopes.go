@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// TeamRepositories returns the full names of the repositories a team has access to
+func (c *Client) TeamRepositories(ctx context.Context, org, slug string) ([]string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var names []string
+	for {
+		repos, resp, err := c.client.Teams.ListTeamReposBySlug(ctx, org, slug, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories for team %s/%s: %w", org, slug, err)
+		}
+
+		for _, repo := range repos {
+			names = append(names, repo.GetFullName())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return names, nil
+}
+
+// teamPermissions ranks the repository permissions a team can hold, the highest first
+var teamPermissions = []string{"admin", "maintain", "push"}
+
+// OwningTeams returns the handles, such as @your-org/payments, of the teams owning a repository:
+// those holding its highest permission granted to any team, admin before maintain before write.
+// Teams with only triage or read access do not own the repository.
+func (c *Client) OwningTeams(ctx context.Context, owner, repo string) ([]string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	byPermission := make(map[string][]string)
+	for {
+		teams, resp, err := c.client.Repositories.ListTeams(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list teams of repository %s/%s: %w", owner, repo, err)
+		}
+
+		for _, team := range teams {
+			byPermission[team.GetPermission()] = append(byPermission[team.GetPermission()], fmt.Sprintf("@%s/%s", owner, team.GetSlug()))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	for _, permission := range teamPermissions {
+		if teams := byPermission[permission]; len(teams) > 0 {
+			return teams, nil
+		}
+	}
+	return nil, nil
+}
+
+// TopicRepositories returns the full names of an organization's repositories tagged with a topic
+func (c *Client) TopicRepositories(ctx context.Context, org, topic string) ([]string, error) {
+	opts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	query := fmt.Sprintf("org:%s topic:%s", org, topic)
+
+	var names []string
+	for {
+		result, resp, err := c.client.Search.Repositories(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search repositories with topic %s: %w", topic, err)
+		}
+
+		for _, repo := range result.Repositories {
+			names = append(names, repo.GetFullName())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return names, nil
+}
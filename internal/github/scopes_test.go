@@ -0,0 +1,87 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestTeamAndTopicRepositories(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/orgs/test-org/teams/platform/repos":
+			fmt.Fprint(w, `[{"full_name": "test-org/infra"}, {"full_name": "test-org/deploy"}]`)
+		case "/search/repositories":
+			if q := r.URL.Query().Get("q"); q != "org:test-org topic:frontend" {
+				t.Errorf("Unexpected search query %q", q)
+			}
+			fmt.Fprint(w, `{"total_count": 1, "items": [{"full_name": "test-org/web"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	repos, err := client.TeamRepositories(ctx, "test-org", "platform")
+	if err != nil || !reflect.DeepEqual(repos, []string{"test-org/infra", "test-org/deploy"}) {
+		t.Errorf("Unexpected team repositories %v (err: %v)", repos, err)
+	}
+
+	repos, err = client.TopicRepositories(ctx, "test-org", "frontend")
+	if err != nil || !reflect.DeepEqual(repos, []string{"test-org/web"}) {
+		t.Errorf("Unexpected topic repositories %v (err: %v)", repos, err)
+	}
+
+	if _, err := client.TeamRepositories(ctx, "test-org", "missing"); err == nil {
+		t.Error("Expected error for missing team, got nil")
+	}
+}
+
+func TestOwningTeams(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/test-org/api/teams":
+			fmt.Fprint(w, `[{"slug": "readers", "permission": "pull"}, {"slug": "devs", "permission": "push"}, {"slug": "platform", "permission": "admin"}, {"slug": "payments", "permission": "admin"}]`)
+		case "/repos/test-org/docs/teams":
+			fmt.Fprint(w, `[{"slug": "writers", "permission": "push"}, {"slug": "readers", "permission": "pull"}]`)
+		case "/repos/test-org/open/teams":
+			fmt.Fprint(w, `[{"slug": "everyone", "permission": "triage"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		repo     string
+		expected []string
+	}{
+		{"api", []string{"@test-org/platform", "@test-org/payments"}},
+		{"docs", []string{"@test-org/writers"}},
+		{"open", nil},
+	}
+	for _, tt := range tests {
+		teams, err := client.OwningTeams(ctx, "test-org", tt.repo)
+		if err != nil || !reflect.DeepEqual(teams, tt.expected) {
+			t.Errorf("OwningTeams(%q) = %v (err: %v), expected %v", tt.repo, teams, err, tt.expected)
+		}
+	}
+
+	if _, err := client.OwningTeams(ctx, "test-org", "missing"); err == nil {
+		t.Error("Expected error for missing repository, got nil")
+	}
+}
@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// Values of an organization's allowed actions setting
+const (
+	AllowedActionsAll       = "all"        // Any action may run
+	AllowedActionsLocalOnly = "local_only" // Only actions defined in the organization's repositories
+	AllowedActionsSelected  = "selected"   // Local actions and those matching the selected actions settings
+)
+
+// OrgActionsSettings are an organization's GitHub-native settings for which actions may run
+type OrgActionsSettings struct {
+	EnabledRepositories string   `json:"enabled_repositories"` // "all", "none" or "selected"
+	AllowedActions      string   `json:"allowed_actions"`
+	GithubOwnedAllowed  bool     `json:"github_owned_allowed"` // Allows every action owned by GitHub (actions/*, github/*)
+	VerifiedAllowed     bool     `json:"verified_allowed"`     // Allows every Marketplace action from verified creators
+	PatternsAllowed     []string `json:"patterns_allowed"`
+}
+
+// GetOrgActionsSettings retrieves the organization's allowed actions settings. The selected
+// actions are only read when the organization restricts actions to selected ones.
+func (c *Client) GetOrgActionsSettings(ctx context.Context, org string) (*OrgActionsSettings, error) {
+	permissions, _, err := c.client.Actions.GetActionsPermissions(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actions permissions for %s: %w", org, err)
+	}
+
+	settings := &OrgActionsSettings{
+		EnabledRepositories: permissions.GetEnabledRepositories(),
+		AllowedActions:      permissions.GetAllowedActions(),
+	}
+	if settings.AllowedActions != AllowedActionsSelected {
+		return settings, nil
+	}
+
+	allowed, _, err := c.client.Actions.GetActionsAllowed(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get selected actions for %s: %w", org, err)
+	}
+	settings.GithubOwnedAllowed = allowed.GetGithubOwnedAllowed()
+	settings.VerifiedAllowed = allowed.GetVerifiedAllowed()
+	settings.PatternsAllowed = allowed.PatternsAllowed
+
+	return settings, nil
+}
+
+// UpdateOrgActionsSettings changes the organization's allowed actions settings. The selected
+// actions are only written when the settings restrict actions to selected ones. Which
+// repositories may run actions is kept as given in settings.
+func (c *Client) UpdateOrgActionsSettings(ctx context.Context, org string, settings OrgActionsSettings) error {
+	permissions := github.ActionsPermissions{
+		EnabledRepositories: github.Ptr(settings.EnabledRepositories),
+		AllowedActions:      github.Ptr(settings.AllowedActions),
+	}
+	if _, _, err := c.client.Actions.EditActionsPermissions(ctx, org, permissions); err != nil {
+		return fmt.Errorf("failed to update actions permissions for %s: %w", org, err)
+	}
+	if settings.AllowedActions != AllowedActionsSelected {
+		return nil
+	}
+
+	allowed := github.ActionsAllowed{
+		GithubOwnedAllowed: github.Ptr(settings.GithubOwnedAllowed),
+		VerifiedAllowed:    github.Ptr(settings.VerifiedAllowed),
+		PatternsAllowed:    settings.PatternsAllowed,
+	}
+	if _, _, err := c.client.Actions.EditActionsAllowed(ctx, org, allowed); err != nil {
+		return fmt.Errorf("failed to update selected actions for %s: %w", org, err)
+	}
+
+	return nil
+}
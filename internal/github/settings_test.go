@@ -0,0 +1,104 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetOrgActionsSettings(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/orgs/selected-org/actions/permissions":
+			fmt.Fprint(w, `{"enabled_repositories": "all", "allowed_actions": "selected"}`)
+		case "/orgs/selected-org/actions/permissions/selected-actions":
+			fmt.Fprint(w, `{"github_owned_allowed": true, "verified_allowed": false, "patterns_allowed": ["docker/*", "hashicorp/setup-terraform@*"]}`)
+		case "/orgs/open-org/actions/permissions":
+			fmt.Fprint(w, `{"enabled_repositories": "all", "allowed_actions": "all"}`)
+		case "/orgs/open-org/actions/permissions/selected-actions":
+			t.Error("Selected actions should not be read when all actions are allowed")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	settings, err := client.GetOrgActionsSettings(ctx, "selected-org")
+	if err != nil {
+		t.Fatalf("GetOrgActionsSettings() error = %v", err)
+	}
+	expected := &OrgActionsSettings{
+		EnabledRepositories: "all",
+		AllowedActions:      AllowedActionsSelected,
+		GithubOwnedAllowed:  true,
+		PatternsAllowed:     []string{"docker/*", "hashicorp/setup-terraform@*"},
+	}
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("GetOrgActionsSettings() = %+v, want %+v", settings, expected)
+	}
+
+	settings, err = client.GetOrgActionsSettings(ctx, "open-org")
+	if err != nil || settings.AllowedActions != AllowedActionsAll || settings.PatternsAllowed != nil {
+		t.Errorf("GetOrgActionsSettings() = (%+v, %v), want all actions allowed", settings, err)
+	}
+
+	if _, err := client.GetOrgActionsSettings(ctx, "missing-org"); err == nil {
+		t.Error("Expected error for a missing organization, got nil")
+	}
+}
+
+func TestUpdateOrgActionsSettings(t *testing.T) {
+	bodies := make(map[string]map[string]interface{})
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT request, got %s", r.Method)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		bodies[r.URL.Path] = body
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	err := client.UpdateOrgActionsSettings(context.Background(), "org", OrgActionsSettings{
+		EnabledRepositories: "selected",
+		AllowedActions:      AllowedActionsSelected,
+		PatternsAllowed:     []string{"actions/checkout@*", "docker/*"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateOrgActionsSettings() error = %v", err)
+	}
+
+	permissions := bodies["/orgs/org/actions/permissions"]
+	if permissions["enabled_repositories"] != "selected" || permissions["allowed_actions"] != "selected" {
+		t.Errorf("Unexpected permissions update: %v", permissions)
+	}
+	allowed := bodies["/orgs/org/actions/permissions/selected-actions"]
+	if allowed["github_owned_allowed"] != false || allowed["verified_allowed"] != false || len(allowed["patterns_allowed"].([]interface{})) != 2 {
+		t.Errorf("Unexpected selected actions update: %v", allowed)
+	}
+
+	// Selected actions are not written unless actions are restricted to them
+	bodies = make(map[string]map[string]interface{})
+	if err := client.UpdateOrgActionsSettings(context.Background(), "org", OrgActionsSettings{EnabledRepositories: "all", AllowedActions: AllowedActionsAll}); err != nil {
+		t.Fatalf("UpdateOrgActionsSettings() error = %v", err)
+	}
+	if _, ok := bodies["/orgs/org/actions/permissions/selected-actions"]; ok || len(bodies) != 1 {
+		t.Errorf("Expected only the permissions to be updated, got %v", bodies)
+	}
+}
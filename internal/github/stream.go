@@ -0,0 +1,165 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// repoResult is the outcome of fetching a single repository's workflow files
+type repoResult struct {
+	repo     string
+	files    []WorkflowFile
+	err      error
+	restored bool // Restored from the checkpoint rather than fetched
+}
+
+// ScanInterruptedError is returned when an organization scan stops early because its context
+// was cancelled or timed out. The repositories scanned until then have been handled.
+type ScanInterruptedError struct {
+	Scanned int // Repositories handled before the interruption
+	Total   int
+	Err     error
+}
+
+// Error implements error
+func (e *ScanInterruptedError) Error() string {
+	return fmt.Sprintf("scan interrupted after %d of %d repositories: %v", e.Scanned, e.Total, e.Err)
+}
+
+// Unwrap returns the context error that interrupted the scan
+func (e *ScanInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// StreamWorkflowFilesForOrg fetches the workflow files of an organization's repositories,
+// up to the configured concurrency at a time, and passes each repository's files to handle
+// as soon as they arrive. handle is called from the calling goroutine, so only the repositories
+// in flight are held in memory. Returning an error from handle stops the scan. When ctx is
+// cancelled, the scan stops with a *ScanInterruptedError and leaves the checkpoint to resume.
+func (c *Client) StreamWorkflowFilesForOrg(ctx context.Context, org string, handle func(repo string, files []WorkflowFile) error) error {
+	repos, err := c.ListRepositories(ctx, org)
+	if err != nil {
+		return err
+	}
+
+	c.observer.ScanStarted(len(repos))
+	defer c.observer.ScanFinished()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := c.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	indexes := make(chan int)
+	results := make(chan repoResult)
+
+	// Producers fetch repositories in parallel
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := c.fetchRepository(ctx, repos[i].FullName, i+1, len(repos))
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(indexes)
+		for i := range repos {
+			if len(strings.Split(repos[i].FullName, "/")) != 2 {
+				continue
+			}
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Consume repositories in the order they finish
+	handled := 0
+	for result := range results {
+		if result.err != nil {
+			// Stop instead of skipping the remaining repositories, so the scan can resume later
+			if c.checkpoint != nil && isRateLimitError(result.err) {
+				return fmt.Errorf("rate limit exceeded after %d of %d repositories, resume the scan once it resets: %w", handled, len(repos), result.err)
+			}
+			// Skip repositories without accessible workflows
+			continue
+		}
+
+		if c.checkpoint != nil && !result.restored {
+			if err := c.checkpoint.Record(result.repo, result.files); err != nil {
+				return err
+			}
+		}
+		if err := handle(result.repo, result.files); err != nil {
+			return err
+		}
+		handled++
+	}
+
+	if err := ctx.Err(); err != nil {
+		return &ScanInterruptedError{Scanned: handled, Total: len(repos), Err: err}
+	}
+
+	if c.checkpoint != nil {
+		if err := c.checkpoint.Complete(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchRepository fetches a repository's workflow files, or restores them from the checkpoint,
+// and reports its progress to the observer
+func (c *Client) fetchRepository(ctx context.Context, fullName string, index, total int) repoResult {
+	c.observer.RepoStarted(fullName, index, total)
+
+	// Restore repositories covered before an interruption
+	if c.checkpoint != nil {
+		if files, ok := c.checkpoint.Scanned(fullName); ok {
+			c.observer.RepoScanned(RepoStats{Repository: fullName, Index: index, Total: total})
+			return repoResult{repo: fullName, files: files, restored: true}
+		}
+	}
+
+	// Count this repository's requests separately from those of parallel fetches
+	calls := new(int64)
+	started := time.Now()
+
+	owner, repo, _ := strings.Cut(fullName, "/")
+	files, err := c.GetWorkflowFiles(withRequestCounter(ctx, calls), owner, repo)
+
+	c.observer.RepoScanned(RepoStats{
+		Repository: fullName,
+		Index:      index,
+		Total:      total,
+		Duration:   time.Since(started),
+		APICalls:   atomic.LoadInt64(calls),
+		Err:        err,
+	})
+
+	return repoResult{repo: fullName, files: files, err: err}
+}
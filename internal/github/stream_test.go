@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStreamWorkflowFilesForOrg(t *testing.T) {
+	var repos []Repository
+	for i := 0; i < 20; i++ {
+		repos = append(repos, Repository{Name: fmt.Sprintf("repo%d", i), FullName: fmt.Sprintf("org/repo%d", i)})
+	}
+
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/orgs/org/repos":
+			fmt.Fprint(w, CreateMockRepositoriesResponse(repos))
+		case strings.HasSuffix(r.URL.Path, "/git/trees/HEAD:.github"):
+			fmt.Fprint(w, `{"sha": "t0", "tree": [{"path": "workflows/ci.yml", "type": "blob", "sha": "b1"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/git/blobs/b1"):
+			fmt.Fprint(w, CreateMockWorkflowContent())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client.SetObserver(observer)
+	client.SetConcurrency(4)
+
+	handled := make(map[string]int)
+	err := client.StreamWorkflowFilesForOrg(context.Background(), "org", func(repo string, files []WorkflowFile) error {
+		handled[repo] = len(files)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamWorkflowFilesForOrg returned error: %v", err)
+	}
+
+	if len(handled) != 20 || handled["org/repo7"] != 1 {
+		t.Errorf("Expected every repository to be handled with its workflow file, got %v", handled)
+	}
+
+	// Requests are attributed to their own repository despite parallel fetches
+	if len(observer.scanned) != 20 {
+		t.Fatalf("Expected 20 repository notifications, got %d", len(observer.scanned))
+	}
+	for _, stats := range observer.scanned {
+		if stats.APICalls != 2 {
+			t.Errorf("Expected 2 API calls for %s, got %d", stats.Repository, stats.APICalls)
+		}
+	}
+
+	// An error from the handler stops the scan
+	stop := errors.New("stop")
+	calls := 0
+	err = client.StreamWorkflowFilesForOrg(context.Background(), "org", func(repo string, files []WorkflowFile) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected scan to stop after the first handler error, got %v after %d calls", err, calls)
+	}
+
+	// Cancelling the context interrupts the scan, keeping the repositories handled so far
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = client.StreamWorkflowFilesForOrg(ctx, "org", func(repo string, files []WorkflowFile) error {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return nil
+	})
+	var interrupted *ScanInterruptedError
+	if !errors.As(err, &interrupted) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected an interrupted scan, got %v", err)
+	}
+	if interrupted.Scanned != calls || interrupted.Total != 20 || calls >= 20 {
+		t.Errorf("Expected the interruption to record %d of 20 repositories, got %+v", calls, interrupted)
+	}
+}
@@ -0,0 +1,40 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrTagNotFound is returned by ResolveTag when the tag does not exist
+var ErrTagNotFound = errors.New("tag not found")
+
+// maxTagDepth bounds how many annotated tag objects are followed to reach a commit
+const maxTagDepth = 5
+
+// ResolveTag returns the commit SHA a tag currently points to, dereferencing annotated tags
+func (c *Client) ResolveTag(ctx context.Context, owner, repo, tag string) (string, error) {
+	ref, resp, err := c.client.Git.GetRef(ctx, owner, repo, "tags/"+tag)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", ErrTagNotFound
+		}
+		return "", fmt.Errorf("failed to get tag %s for %s/%s: %w", tag, owner, repo, err)
+	}
+
+	// Annotated tags point to a tag object, which in turn points to the commit
+	object := ref.GetObject()
+	for depth := 0; object.GetType() == "tag"; depth++ {
+		if depth == maxTagDepth {
+			return "", fmt.Errorf("tag %s for %s/%s is nested too deeply", tag, owner, repo)
+		}
+		tagObject, _, err := c.client.Git.GetTag(ctx, owner, repo, object.GetSHA())
+		if err != nil {
+			return "", fmt.Errorf("failed to get annotated tag %s for %s/%s: %w", tag, owner, repo, err)
+		}
+		object = tagObject.GetObject()
+	}
+
+	return object.GetSHA(), nil
+}
@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestResolveTag(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/actions/checkout/git/ref/tags/v4.1.1":
+			fmt.Fprint(w, `{"ref": "refs/tags/v4.1.1", "object": {"type": "commit", "sha": "aaaa"}}`)
+		case "/repos/actions/checkout/git/ref/tags/v4.2.0":
+			fmt.Fprint(w, `{"ref": "refs/tags/v4.2.0", "object": {"type": "tag", "sha": "tag1"}}`)
+		case "/repos/actions/checkout/git/tags/tag1":
+			fmt.Fprint(w, `{"sha": "tag1", "object": {"type": "commit", "sha": "bbbb"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+	ctx := context.Background()
+
+	sha, err := client.ResolveTag(ctx, "actions", "checkout", "v4.1.1")
+	if err != nil || sha != "aaaa" {
+		t.Errorf("Expected lightweight tag to resolve to aaaa, got %q (err: %v)", sha, err)
+	}
+
+	sha, err = client.ResolveTag(ctx, "actions", "checkout", "v4.2.0")
+	if err != nil || sha != "bbbb" {
+		t.Errorf("Expected annotated tag to resolve to bbbb, got %q (err: %v)", sha, err)
+	}
+
+	if _, err := client.ResolveTag(ctx, "actions", "checkout", "v0.0.0"); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("Expected ErrTagNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TokenScopes returns the OAuth scopes granted to a classic personal access token or OAuth app
+// token, as reported in the X-OAuth-Scopes header. reported is false for tokens whose
+// permissions are not expressed as scopes, such as fine-grained personal access tokens and
+// GitHub App installation tokens.
+func (c *Client) TokenScopes(ctx context.Context) (scopes []string, reported bool, err error) {
+	// The rate limit endpoint does not count against the limit
+	_, resp, err := c.client.RateLimit.Get(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check token scopes: %w", err)
+	}
+
+	header, reported := resp.Header["X-Oauth-Scopes"]
+	if !reported {
+		return nil, false, nil
+	}
+	for _, value := range header {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes, true, nil
+}
@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestTokenScopes(t *testing.T) {
+	header := "repo, read:org"
+	server, client := MockServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			t.Errorf("Expected the rate limit endpoint, got %s", r.URL.Path)
+		}
+		if header != "-" {
+			w.Header().Set("X-OAuth-Scopes", header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"resources": {}}`)
+	}))
+	defer server.Close()
+
+	scopes, reported, err := client.TokenScopes(context.Background())
+	if err != nil {
+		t.Fatalf("TokenScopes() error = %v", err)
+	}
+	if !reported || !slices.Equal(scopes, []string{"repo", "read:org"}) {
+		t.Errorf("TokenScopes() = %v (reported: %v), want [repo read:org]", scopes, reported)
+	}
+
+	// A classic token without scopes reports an empty header
+	header = ""
+	scopes, reported, err = client.TokenScopes(context.Background())
+	if err != nil || !reported || len(scopes) != 0 {
+		t.Errorf("TokenScopes() = %v (reported: %v, error: %v), want no scopes reported", scopes, reported, err)
+	}
+
+	// Fine-grained and GitHub App tokens report no header
+	header = "-"
+	if _, reported, err := client.TokenScopes(context.Background()); err != nil || reported {
+		t.Errorf("Expected scopes not to be reported without the header, got reported: %v, error: %v", reported, err)
+	}
+}
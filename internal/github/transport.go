@@ -0,0 +1,150 @@
+package github
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// HTTPConfig configures the HTTP transport of API requests, such as for running behind a
+// TLS-intercepting proxy. The zero value uses the default transport.
+type HTTPConfig struct {
+	ProxyURL     string        // Proxy for all requests; empty to use HTTPS_PROXY and friends from the environment
+	CABundle     string        // PEM file of CA certificates trusted in addition to the system pool
+	Timeout      time.Duration // Timeout of each request attempt; zero for none
+	Retries      int           // Retries of idempotent requests that failed or hit a server error
+	RetryBackoff time.Duration // Delay before the first retry, doubled for each further retry
+	Recorder     *Recorder     // Captures responses into a fixture; nil when not recording
+}
+
+// transport builds the HTTP transport for a configuration
+func (cfg HTTPConfig) transport() (http.RoundTripper, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: %s", cfg.ProxyURL)
+		}
+		base.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CABundle)
+		}
+		base.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	if cfg.Timeout < 0 || cfg.Retries < 0 || cfg.RetryBackoff < 0 {
+		return nil, fmt.Errorf("HTTP timeout, retries and retry backoff must not be negative")
+	}
+	var transport http.RoundTripper = base
+	if cfg.Recorder != nil {
+		transport = &recordingTransport{base: base, recorder: cfg.Recorder}
+	}
+	if cfg.Timeout == 0 && cfg.Retries == 0 {
+		return transport, nil
+	}
+	return &retryTransport{base: transport, timeout: cfg.Timeout, retries: cfg.Retries, backoff: cfg.RetryBackoff}, nil
+}
+
+// HTTPClient returns a client for requests to services other than the forge, such as
+// notification channels, with the proxy, CA bundle, timeout and retry settings. Their responses
+// are not recorded into fixtures.
+func (cfg HTTPConfig) HTTPClient() (*http.Client, error) {
+	cfg.Recorder = nil
+	transport, err := cfg.transport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// retryTransport times out request attempts and retries idempotent requests that failed
+// or hit a server error. Rate limit responses are not retried, as scans handle them.
+type retryTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	delay := t.backoff
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req)
+		if !idempotent || attempt >= t.retries || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// attempt sends a request once, bounded by the timeout
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout == 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The timeout covers reading the body too, so cancel once it is closed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retryable reports whether a request attempt failed in a way worth retrying
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// cancelOnClose releases the context of a request attempt once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPConfig(t *testing.T) {
+	var attempts int64
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first two attempts, stalling the first beyond the timeout
+		switch atomic.AddInt64(&attempts, 1) {
+		case 1:
+			time.Sleep(200 * time.Millisecond)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"tag_name": "v1.0.0"}`)
+	}))
+	defer server.Close()
+
+	// Trust the test server's self-signed certificate through a CA bundle
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certificate, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := HTTPConfig{CABundle: bundle, Timeout: 50 * time.Millisecond, Retries: 2, RetryBackoff: time.Millisecond}
+	client, err := NewClientForProvider("token", ProviderGitHub, server.URL, config)
+	if err != nil {
+		t.Fatalf("NewClientForProvider returned error: %v", err)
+	}
+
+	release, err := client.GetLatestRelease(context.Background(), "owner", "tool")
+	if err != nil || release.Tag != "v1.0.0" {
+		t.Fatalf("Expected the release after retries, got %+v, %v", release, err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if client.RequestCount() != 1 {
+		t.Errorf("Expected retries to count as one request, got %d", client.RequestCount())
+	}
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		client, err := NewClientForProvider("token", ProviderGitHub, server.URL, HTTPConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.GetLatestRelease(context.Background(), "owner", "tool"); err == nil {
+			t.Error("Expected a certificate error without the CA bundle")
+		}
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		invalid := []HTTPConfig{
+			{ProxyURL: "not a url"},
+			{CABundle: filepath.Join(t.TempDir(), "missing.pem")},
+			{Retries: -1},
+		}
+		for _, config := range invalid {
+			if _, err := NewClientWithHTTPConfig("token", config); err == nil {
+				t.Errorf("Expected an error for %+v", config)
+			}
+		}
+	})
+}
+
+func TestRetryTransportSkipsNonIdempotentRequests(t *testing.T) {
+	var attempts int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	transport := &retryTransport{base: http.DefaultTransport, retries: 3}
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if attempts != 1 {
+		t.Errorf("Expected a single attempt for a POST request, got %d", attempts)
+	}
+}
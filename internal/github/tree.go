@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// githubDir is the repository directory holding workflows and repository policy
+const githubDir = ".github"
+
+// errTreeTruncated is returned for trees too large for the Git Trees API to list in full
+var errTreeTruncated = errors.New("tree is truncated")
+
+// GetWorkflowFilesAt retrieves workflow files as they were at a specific commit or ref
+func (c *Client) GetWorkflowFilesAt(ctx context.Context, owner, repo, ref string) ([]WorkflowFile, error) {
+	return c.getWorkflowFilesFromTree(ctx, owner, repo, ref)
+}
+
+// GithubTreeSHA returns the SHA of the .github directory tree at ref. It changes whenever a
+// workflow file or the repository policy changes, so it identifies their combined state.
+func (c *Client) GithubTreeSHA(ctx context.Context, owner, repo, ref string) (string, error) {
+	tree, _, err := c.client.Git.GetTree(ctx, owner, repo, ref+":"+githubDir, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s tree: %w", githubDir, err)
+	}
+	return tree.GetSHA(), nil
+}
+
+// CommitBefore returns the SHA of the latest commit on the default branch at or before the given time.
+// It returns an empty string if the repository has no commits before that time.
+func (c *Client) CommitBefore(ctx context.Context, owner, repo string, until time.Time) (string, error) {
+	commits, _, err := c.client.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{
+		Until:       until,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return "", nil
+	}
+	return commits[0].GetSHA(), nil
+}
+
+// getWorkflowFilesFromTree fetches workflow files with a single recursive Git Trees request for
+// the .github directory followed by one raw blob request per workflow file, instead of a
+// directory listing plus a contents request per file.
+func (c *Client) getWorkflowFilesFromTree(ctx context.Context, owner, repo, ref string) ([]WorkflowFile, error) {
+	return c.getNewWorkflowFilesFromTree(ctx, owner, repo, ref, nil)
+}
+
+// treeFallback reports whether workflow files must be read through the contents API after
+// the Git Trees API failed: its tree was truncated, or the forge does not support reading a
+// directory's tree by ref and path, which Gitea and Forgejo refuse as a bad request
+func treeFallback(err error) bool {
+	if errors.Is(err, errTreeTruncated) {
+		return true
+	}
+	var errorResponse *github.ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Response == nil {
+		return false
+	}
+	switch errorResponse.Response.StatusCode {
+	case http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// treeAbsent reports whether a tree request failed because the directory does not exist at
+// the ref, or the repository has no commits yet
+func treeAbsent(err error) bool {
+	var errorResponse *github.ErrorResponse
+	return IsNotFound(err) || errors.As(err, &errorResponse) && errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusConflict
+}
+
+// getNewWorkflowFilesFromTree fetches the workflow files at ref whose blob SHA is not in seen,
+// adding the SHAs of fetched files to seen when it is non-nil
+func (c *Client) getNewWorkflowFilesFromTree(ctx context.Context, owner, repo, ref string, seen map[string]bool) ([]WorkflowFile, error) {
+	// Read the parent of the workflows directory, .github by default
+	workflowsPath := c.workflowsPath()
+	root := path.Dir(workflowsPath)
+	if root == "." {
+		root = workflowsPath
+	}
+
+	files, err := c.getNewTreeFiles(ctx, owner, repo, ref, root, seen, func(entryPath string) bool {
+		name := path.Base(entryPath)
+		return path.Dir(entryPath) == workflowsPath && (strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml"))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Composite actions in the scan paths are scanned like workflows; paths that do not exist
+	// in the repository are skipped
+	for _, scanPath := range c.scanPaths {
+		actionFiles, err := c.getNewTreeFiles(ctx, owner, repo, ref, scanPath, seen, IsActionPath)
+		if err != nil {
+			continue
+		}
+		files = append(files, actionFiles...)
+	}
+
+	return files, nil
+}
+
+// getNewTreeFiles fetches the files under a directory at ref whose path satisfies include and
+// whose blob SHA is not in seen, adding the SHAs of fetched files to seen when it is non-nil
+func (c *Client) getNewTreeFiles(ctx context.Context, owner, repo, ref, dir string, seen map[string]bool, include func(string) bool) ([]WorkflowFile, error) {
+	tree, _, err := c.client.Git.GetTree(ctx, owner, repo, ref+":"+dir, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s tree: %w", dir, err)
+	}
+
+	// Truncated trees may be missing workflow files
+	if tree.GetTruncated() {
+		return nil, fmt.Errorf("%s tree for %s/%s: %w", dir, owner, repo, errTreeTruncated)
+	}
+
+	var files []WorkflowFile
+
+	for _, entry := range tree.Entries {
+		// Tree paths are relative to the directory
+		entryPath := path.Join(dir, entry.GetPath())
+		if entry.GetType() != "blob" || !include(entryPath) {
+			continue
+		}
+		name := path.Base(entryPath)
+
+		if seen[entry.GetSHA()] {
+			continue
+		}
+
+		content, _, err := c.client.Git.GetBlobRaw(ctx, owner, repo, entry.GetSHA())
+		if err != nil {
+			continue // Skip files we can't access
+		}
+		if seen != nil {
+			seen[entry.GetSHA()] = true
+		}
+
+		files = append(files, WorkflowFile{
+			Name:    name,
+			Path:    entryPath,
+			SHA:     entry.GetSHA(),
+			Content: content,
+		})
+	}
+
+	return files, nil
+}
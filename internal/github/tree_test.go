@@ -0,0 +1,264 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetWorkflowFilesFromTree(t *testing.T) {
+	var requests int32
+
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/owner/repo/git/trees/HEAD:.github":
+			if r.URL.Query().Get("recursive") != "1" {
+				t.Errorf("Expected recursive tree request, got query %q", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{
+                "sha": "abc",
+                "truncated": false,
+                "tree": [
+                    {"path": "workflows", "type": "tree", "sha": "t1"},
+                    {"path": "workflows/ci.yml", "type": "blob", "sha": "b1"},
+                    {"path": "workflows/README.md", "type": "blob", "sha": "b2"},
+                    {"path": "workflows/nested/other.yml", "type": "blob", "sha": "b3"},
+                    {"path": "action-control-policy.yaml", "type": "blob", "sha": "b4"}
+                ]
+            }`)
+		case "/repos/owner/repo/git/blobs/b1":
+			fmt.Fprint(w, CreateMockWorkflowContent())
+		default:
+			t.Logf("No mock handler for path: %s, returning 404", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	files, err := client.GetWorkflowFiles(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("GetWorkflowFiles returned error: %v", err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 workflow file, got %d", len(files))
+	}
+
+	if files[0].Path != ".github/workflows/ci.yml" || files[0].Name != "ci.yml" {
+		t.Errorf("Unexpected workflow file: %s (%s)", files[0].Path, files[0].Name)
+	}
+
+	if actions := ExtractActions(files); len(actions) != 2 {
+		t.Errorf("Expected 2 actions from workflow blob, got %d", len(actions))
+	}
+
+	// One tree request plus one blob request
+	if requests != 2 {
+		t.Errorf("Expected 2 API requests, got %d", requests)
+	}
+}
+
+func TestGetWorkflowFilesScanPaths(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/owner/repo/git/trees/HEAD:.gitea":
+			fmt.Fprint(w, `{"truncated": false, "tree": [
+                {"path": "workflows/ci.yml", "type": "blob", "sha": "b1"}
+            ]}`)
+		case "/repos/owner/repo/git/trees/HEAD:ci/actions":
+			fmt.Fprint(w, `{"truncated": false, "tree": [
+                {"path": "setup/action.yml", "type": "blob", "sha": "b2"},
+                {"path": "setup/README.md", "type": "blob", "sha": "b3"},
+                {"path": "build/action.yaml", "type": "blob", "sha": "b4"}
+            ]}`)
+		case "/repos/owner/repo/git/blobs/b1":
+			fmt.Fprint(w, CreateMockWorkflowContent())
+		case "/repos/owner/repo/git/blobs/b2":
+			fmt.Fprint(w, "name: Setup\nruns:\n  using: composite\n  steps:\n    - run: echo setup\n    - uses: actions/setup-go@v5\n      with:\n        go-version: stable\n")
+		case "/repos/owner/repo/git/blobs/b4":
+			fmt.Fprint(w, "name: Build\nruns:\n  using: node20\n  main: index.js\n")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	if err := client.SetWorkflowsPath("/.gitea/workflows/"); err != nil {
+		t.Fatalf("SetWorkflowsPath returned error: %v", err)
+	}
+	if err := client.SetScanPaths([]string{"ci/actions", "missing"}); err != nil {
+		t.Fatalf("SetScanPaths returned error: %v", err)
+	}
+
+	files, err := client.GetWorkflowFiles(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("GetWorkflowFiles returned error: %v", err)
+	}
+	if len(files) != 3 || files[0].Path != ".gitea/workflows/ci.yml" || files[1].Path != "ci/actions/setup/action.yml" {
+		t.Fatalf("Expected the workflow and both action files, got %+v", files)
+	}
+	if files[0].IsAction() || !files[1].IsAction() || !files[2].IsAction() {
+		t.Errorf("Expected only the action files to be actions")
+	}
+
+	// Composite steps are extracted with their line; other actions run no steps
+	actions := ExtractActions(files[1:])
+	if len(actions) != 1 || actions[0].Uses != "actions/setup-go@v5" || actions[0].Line != 6 || actions[0].With["go-version"] != "stable" {
+		t.Errorf("Expected the composite step, got %+v", actions)
+	}
+
+	if err := client.SetScanPaths([]string{"../outside"}); err == nil {
+		t.Error("Expected an error for a scan path outside the repository")
+	}
+	if err := client.SetWorkflowsPath("/"); err == nil {
+		t.Error("Expected an error for the repository root as workflows path")
+	}
+}
+
+// recordingObserver records scan notifications for assertions
+type recordingObserver struct {
+	mu       sync.Mutex
+	total    int
+	started  []string
+	scanned  []RepoStats
+	finished bool
+}
+
+func (o *recordingObserver) ScanStarted(total int) { o.total = total }
+func (o *recordingObserver) RepoStarted(repo string, index, total int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.started = append(o.started, repo)
+}
+func (o *recordingObserver) RepoScanned(stats RepoStats) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.scanned = append(o.scanned, stats)
+}
+func (o *recordingObserver) ScanFinished() { o.finished = true }
+
+func TestWorkflowFilesForOrgObserver(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/orgs/test-org/repos":
+			fmt.Fprint(w, CreateMockRepositoriesResponse([]Repository{
+				{Name: "repo1", FullName: "test-org/repo1"},
+				{Name: "repo2", FullName: "test-org/repo2"},
+			}))
+		case "/repos/test-org/repo1/git/trees/HEAD:.github":
+			fmt.Fprint(w, `{"sha": "abc", "tree": [{"path": "workflows/ci.yml", "type": "blob", "sha": "b1"}]}`)
+		case "/repos/test-org/repo1/git/blobs/b1":
+			fmt.Fprint(w, CreateMockWorkflowContent())
+		case "/repos/test-org/repo2/git/trees/HEAD:.github":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client.SetObserver(observer)
+
+	result, err := client.WorkflowFilesForOrg(context.Background(), "test-org")
+	if err != nil {
+		t.Fatalf("WorkflowFilesForOrg returned error: %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Errorf("Expected 1 repository with workflows, got %d", len(result))
+	}
+
+	if observer.total != 2 || len(observer.scanned) != 2 || !observer.finished {
+		t.Fatalf("Unexpected observer state: %+v", observer)
+	}
+
+	if len(observer.started) != 2 || observer.started[0] != "test-org/repo1" {
+		t.Errorf("Expected repository start notifications in scan order, got %v", observer.started)
+	}
+
+	if observer.scanned[0].Repository != "test-org/repo1" || observer.scanned[0].APICalls != 2 {
+		t.Errorf("Expected repo1 scan with 2 API calls, got %+v", observer.scanned[0])
+	}
+
+	if observer.scanned[1].Err == nil {
+		t.Error("Expected repo2 scan to report an error")
+	}
+
+	if client.RequestCount() == 0 {
+		t.Error("Expected client to count API requests")
+	}
+}
+
+func TestGetWorkflowFilesWithoutGithubDirectory(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/empty/git/trees/HEAD:.github":
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message": "Git Repository is empty."}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	// A missing .github directory means no workflow files, without asking the contents API
+	for _, repo := range []string{"repo", "empty"} {
+		before := client.RequestCount()
+		files, err := client.GetWorkflowFiles(context.Background(), "owner", repo)
+		if err != nil {
+			t.Fatalf("GetWorkflowFiles returned error for %s: %v", repo, err)
+		}
+		if len(files) != 0 {
+			t.Errorf("Expected no workflow files for %s, got %d", repo, len(files))
+		}
+		if requests := client.RequestCount() - before; requests != 1 {
+			t.Errorf("Expected a single tree request for %s, got %d requests", repo, requests)
+		}
+	}
+}
+
+func TestGithubTreeSHA(t *testing.T) {
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/repos/owner/repo/git/trees/HEAD:.github" && r.URL.Query().Get("recursive") == "" {
+			fmt.Fprint(w, `{"sha": "abc123", "tree": []}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+	})
+
+	server, client := MockServer(t, mockHandler)
+	defer server.Close()
+
+	sha, err := client.GithubTreeSHA(context.Background(), "owner", "repo", "HEAD")
+	if err != nil || sha != "abc123" {
+		t.Errorf("Expected tree SHA abc123, got %q (err: %v)", sha, err)
+	}
+
+	if _, err := client.GithubTreeSHA(context.Background(), "owner", "missing", "HEAD"); err == nil {
+		t.Error("Expected error for missing tree, got nil")
+	}
+}
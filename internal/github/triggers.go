@@ -0,0 +1,145 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// WorkflowTriggers returns the events that trigger a workflow file, sorted. The on key may be a
+// single event, a list of events, or a mapping of events to their filters.
+func WorkflowTriggers(file WorkflowFile) ([]string, error) {
+	workflows, err := decodeWorkflows(file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file %s: %w", file.Name, err)
+	}
+
+	seen := make(map[string]bool)
+	var triggers []string
+	add := func(event string) {
+		if event != "" && !seen[event] {
+			seen[event] = true
+			triggers = append(triggers, event)
+		}
+	}
+	for _, workflow := range workflows {
+		switch on := workflow["on"].(type) {
+		case string:
+			add(on)
+		case []interface{}:
+			for _, event := range on {
+				if name, ok := event.(string); ok {
+					add(name)
+				}
+			}
+		case map[string]interface{}:
+			for event := range on {
+				add(event)
+			}
+		}
+	}
+
+	sort.Strings(triggers)
+	return triggers, nil
+}
+
+// TriggeredFor reports whether a workflow file runs on an event for a branch, honoring the
+// event's branches and branches-ignore filters. An event without filters runs for every
+// branch; one with filters never matches an unknown (empty) branch.
+func TriggeredFor(file WorkflowFile, event, branch string) (bool, error) {
+	workflows, err := decodeWorkflows(file.Content)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse workflow file %s: %w", file.Name, err)
+	}
+
+	for _, workflow := range workflows {
+		switch on := workflow["on"].(type) {
+		case string:
+			if on == event {
+				return true, nil
+			}
+		case []interface{}:
+			for _, name := range on {
+				if name == event {
+					return true, nil
+				}
+			}
+		case map[string]interface{}:
+			filters, ok := on[event]
+			if !ok {
+				continue
+			}
+			if matchesBranchFilters(filters, branch) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// matchesBranchFilters checks a branch against an event's branches or branches-ignore filter.
+// Patterns are evaluated in order, so a later "!" pattern excludes branches an earlier one
+// included.
+func matchesBranchFilters(filters interface{}, branch string) bool {
+	mapping, ok := filters.(map[string]interface{})
+	if !ok {
+		return true
+	}
+	include, hasInclude := mapping["branches"].([]interface{})
+	ignore, hasIgnore := mapping["branches-ignore"].([]interface{})
+	if !hasInclude && !hasIgnore {
+		return true
+	}
+	if branch == "" {
+		return false
+	}
+
+	if hasIgnore {
+		for _, pattern := range ignore {
+			if name, ok := pattern.(string); ok && matchesBranchPattern(name, branch) {
+				return false
+			}
+		}
+		return true
+	}
+
+	matched := false
+	for _, pattern := range include {
+		name, ok := pattern.(string)
+		if !ok {
+			continue
+		}
+		if negated, isNegated := strings.CutPrefix(name, "!"); isNegated {
+			if matchesBranchPattern(negated, branch) {
+				matched = false
+			}
+		} else if matchesBranchPattern(name, branch) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matchesBranchPattern matches a branch against a workflow filter pattern, where * matches
+// anything but / and ** matches anything
+func matchesBranchPattern(pattern, branch string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+
+	matched, err := regexp.MatchString(expr.String(), branch)
+	return err == nil && matched
+}
@@ -0,0 +1,66 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorkflowTriggers(t *testing.T) {
+	tests := map[string][]string{
+		"on: push\n":                        {"push"},
+		"on: [pull_request_target, push]\n": {"pull_request_target", "push"},
+		"on:\n  workflow_run:\n    workflows: [CI]\n  push:\n    branches: [main]\n": {"push", "workflow_run"},
+		"on: push\n---\non: [push, workflow_dispatch]\n":                             {"push", "workflow_dispatch"},
+		"name: no triggers\n": nil,
+	}
+	for content, expected := range tests {
+		triggers, err := WorkflowTriggers(WorkflowFile{Name: "ci.yml", Content: []byte(content)})
+		if err != nil {
+			t.Fatalf("WorkflowTriggers(%q) returned error: %v", content, err)
+		}
+		if !reflect.DeepEqual(triggers, expected) {
+			t.Errorf("WorkflowTriggers(%q) = %v, want %v", content, triggers, expected)
+		}
+	}
+
+	if _, err := WorkflowTriggers(WorkflowFile{Name: "bad.yml", Content: []byte("on: [")}); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
+
+func TestTriggeredFor(t *testing.T) {
+	tests := []struct {
+		content string
+		branch  string
+		want    bool
+	}{
+		{"on: pull_request\n", "main", true},
+		{"on: [push, pull_request]\n", "main", true},
+		{"on: push\n", "main", false},
+		{"on:\n  pull_request:\n", "main", true},
+		{"on:\n  pull_request:\n    types: [opened]\n", "main", true},
+		{"on:\n  pull_request:\n    branches: [main]\n", "main", true},
+		{"on:\n  pull_request:\n    branches: [develop]\n", "main", false},
+		{"on:\n  pull_request:\n    branches: ['release/**', '!release/old']\n", "release/v1/hotfix", true},
+		{"on:\n  pull_request:\n    branches: ['release/*', '!release/old']\n", "release/old", false},
+		{"on:\n  pull_request:\n    branches: ['release/*']\n", "release/v1/hotfix", false},
+		{"on:\n  pull_request:\n    branches-ignore: [main]\n", "main", false},
+		{"on:\n  pull_request:\n    branches-ignore: ['dependabot/**']\n", "main", true},
+		{"on:\n  pull_request:\n    branches: [main]\n", "", false},
+		{"on: push\n---\non: pull_request\n", "main", true},
+	}
+
+	for _, test := range tests {
+		got, err := TriggeredFor(WorkflowFile{Name: "ci.yml", Content: []byte(test.content)}, "pull_request", test.branch)
+		if err != nil {
+			t.Fatalf("TriggeredFor(%q) returned error: %v", test.content, err)
+		}
+		if got != test.want {
+			t.Errorf("TriggeredFor(%q, %q) = %v, want %v", test.content, test.branch, got, test.want)
+		}
+	}
+
+	if _, err := TriggeredFor(WorkflowFile{Name: "bad.yml", Content: []byte("on: [")}, "push", "main"); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
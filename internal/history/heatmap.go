@@ -0,0 +1,139 @@
+package history
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Source provides historical workflow content for a repository
+type Source interface {
+	CommitBefore(ctx context.Context, owner, repo string, until time.Time) (string, error)
+	GetWorkflowFilesAt(ctx context.Context, owner, repo, ref string) ([]github.WorkflowFile, error)
+}
+
+// Heatmap counts how many repositories used each action in each sampled month
+type Heatmap struct {
+	Months  []string         `json:"months"`
+	Actions []string         `json:"actions"`
+	Counts  map[string][]int `json:"counts"` // Repositories using the action, per month
+}
+
+// MonthlySamples returns sample points at the end of each of the last n months, oldest first.
+// The final sample is now, so the latest month reflects the current state.
+func MonthlySamples(now time.Time, months int) []time.Time {
+	samples := make([]time.Time, 0, months)
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	for i := months - 1; i > 0; i-- {
+		// The last instant of the month i months ago
+		samples = append(samples, firstOfMonth.AddDate(0, -i+1, 0).Add(-time.Second))
+	}
+	if months > 0 {
+		samples = append(samples, now)
+	}
+
+	return samples
+}
+
+// Build samples each repository's workflows at the given points in time and counts action usage.
+// Repositories or months that cannot be sampled are skipped.
+func Build(ctx context.Context, src Source, repos []string, samples []time.Time) *Heatmap {
+	heatmap := &Heatmap{
+		Counts: make(map[string][]int),
+	}
+	for _, sample := range samples {
+		heatmap.Months = append(heatmap.Months, sample.Format("2006-01"))
+	}
+
+	for _, repoFullName := range repos {
+		owner, repo, ok := strings.Cut(repoFullName, "/")
+		if !ok {
+			continue
+		}
+
+		for i, sample := range samples {
+			sha, err := src.CommitBefore(ctx, owner, repo, sample)
+			if err != nil || sha == "" {
+				continue
+			}
+
+			files, err := src.GetWorkflowFilesAt(ctx, owner, repo, sha)
+			if err != nil {
+				continue
+			}
+
+			heatmap.add(i, github.ExtractActions(files))
+		}
+	}
+
+	heatmap.Actions = make([]string, 0, len(heatmap.Counts))
+	for action := range heatmap.Counts {
+		heatmap.Actions = append(heatmap.Actions, action)
+	}
+	heatmap.sortActions()
+
+	return heatmap
+}
+
+// Top keeps only the n actions with the highest total usage
+func (h *Heatmap) Top(n int) {
+	if n <= 0 || len(h.Actions) <= n {
+		return
+	}
+
+	for _, action := range h.Actions[n:] {
+		delete(h.Counts, action)
+	}
+	h.Actions = h.Actions[:n]
+}
+
+// Max returns the highest count in the heatmap
+func (h *Heatmap) Max() int {
+	max := 0
+	for _, counts := range h.Counts {
+		for _, count := range counts {
+			if count > max {
+				max = count
+			}
+		}
+	}
+	return max
+}
+
+// add records one repository's actions for a month, counting each action once per repository
+func (h *Heatmap) add(month int, actions []github.Action) {
+	seen := make(map[string]bool)
+	for _, action := range actions {
+		name, _, _ := strings.Cut(action.Uses, "@")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if _, ok := h.Counts[name]; !ok {
+			h.Counts[name] = make([]int, len(h.Months))
+		}
+		h.Counts[name][month]++
+	}
+}
+
+// sortActions orders actions by total usage, descending, then by name
+func (h *Heatmap) sortActions() {
+	totals := make(map[string]int, len(h.Actions))
+	for _, action := range h.Actions {
+		for _, count := range h.Counts[action] {
+			totals[action] += count
+		}
+	}
+
+	sort.Slice(h.Actions, func(i, j int) bool {
+		if totals[h.Actions[i]] != totals[h.Actions[j]] {
+			return totals[h.Actions[i]] > totals[h.Actions[j]]
+		}
+		return h.Actions[i] < h.Actions[j]
+	})
+}
@@ -0,0 +1,84 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// fakeSource serves workflow content keyed by repository and sample month
+type fakeSource struct {
+	workflows map[string]string // "owner/repo@2025-01" -> workflow YAML
+}
+
+func (f *fakeSource) CommitBefore(ctx context.Context, owner, repo string, until time.Time) (string, error) {
+	key := fmt.Sprintf("%s/%s@%s", owner, repo, until.Format("2006-01"))
+	if _, ok := f.workflows[key]; !ok {
+		return "", nil
+	}
+	return key, nil
+}
+
+func (f *fakeSource) GetWorkflowFilesAt(ctx context.Context, owner, repo, ref string) ([]github.WorkflowFile, error) {
+	return []github.WorkflowFile{{Name: "ci.yml", Path: ".github/workflows/ci.yml", Content: []byte(f.workflows[ref])}}, nil
+}
+
+func workflow(uses ...string) string {
+	content := "jobs:\n  build:\n    steps:\n"
+	for _, u := range uses {
+		content += fmt.Sprintf("      - uses: %s\n", u)
+	}
+	return content
+}
+
+func TestMonthlySamples(t *testing.T) {
+	now := time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)
+	samples := MonthlySamples(now, 3)
+
+	expected := []time.Time{
+		time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2025, 2, 28, 23, 59, 59, 0, time.UTC),
+		now,
+	}
+
+	if !reflect.DeepEqual(samples, expected) {
+		t.Errorf("Expected samples %v, got %v", expected, samples)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	src := &fakeSource{workflows: map[string]string{
+		"org/repo1@2025-01": workflow("actions/checkout@v3"),
+		"org/repo1@2025-02": workflow("actions/checkout@v4", "actions/checkout@v4", "old/action@v1"),
+		"org/repo2@2025-02": workflow("actions/checkout@v4"),
+	}}
+
+	samples := []time.Time{
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+	}
+
+	heatmap := Build(context.Background(), src, []string{"org/repo1", "org/repo2"}, samples)
+
+	if !reflect.DeepEqual(heatmap.Months, []string{"2025-01", "2025-02"}) {
+		t.Errorf("Unexpected months: %v", heatmap.Months)
+	}
+	if !reflect.DeepEqual(heatmap.Actions, []string{"actions/checkout", "old/action"}) {
+		t.Errorf("Expected actions ordered by usage, got %v", heatmap.Actions)
+	}
+	if !reflect.DeepEqual(heatmap.Counts["actions/checkout"], []int{1, 2}) {
+		t.Errorf("Expected checkout counted once per repo per month, got %v", heatmap.Counts["actions/checkout"])
+	}
+	if heatmap.Max() != 2 {
+		t.Errorf("Expected max count of 2, got %d", heatmap.Max())
+	}
+
+	heatmap.Top(1)
+	if len(heatmap.Actions) != 1 || len(heatmap.Counts) != 1 {
+		t.Errorf("Expected heatmap trimmed to top action, got %v", heatmap.Actions)
+	}
+}
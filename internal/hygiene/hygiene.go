@@ -0,0 +1,136 @@
+package hygiene
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// Rules a hygiene finding can break
+const (
+	// RuleContinueOnError is a security-relevant step, or a job running one, whose failure is
+	// ignored with continue-on-error: true, so a failed check still passes the run
+	RuleContinueOnError = "continue_on_error"
+	// RuleMissingTimeout is a job without timeout-minutes, which runs for up to 6 hours when
+	// it hangs
+	RuleMissingTimeout = "missing_timeout"
+)
+
+// SecurityActions are the actions whose failure means a security check did not pass. Policies
+// add their own with security_actions; required actions count as well.
+var SecurityActions = []string{
+	"actions/dependency-review-action",
+	"anchore/scan-action",
+	"aquasecurity/trivy-action",
+	"github/codeql-action/*",
+	"gitleaks/gitleaks-action",
+	"ossf/scorecard-action",
+	"snyk/actions/*",
+	"step-security/harden-runner",
+	"trufflesecurity/trufflehog",
+}
+
+// Finding is a job or step breaking a workflow hygiene rule
+type Finding struct {
+	Rule     string `json:"rule"`
+	Workflow string `json:"workflow"`
+	Job      string `json:"job"`
+	Action   string `json:"action,omitempty"` // Security-relevant step, for continue-on-error findings
+	Line     int    `json:"line,omitempty"`
+	Ref      string `json:"ref,omitempty"` // Branch of the workflow file; empty for the default branch
+	Detail   string `json:"detail"`
+}
+
+// Enabled reports whether the policy turns on any hygiene check
+func Enabled(config *policy.PolicyConfig) bool {
+	return config.DetectContinueOnError || config.RequireJobTimeouts
+}
+
+// Check looks for security-relevant steps whose failure is ignored when the policy sets
+// detect_continue_on_error, and for jobs without a timeout when it sets require_job_timeouts.
+// Jobs calling reusable workflows cannot set a timeout and are skipped, as are excluded
+// repositories and files that cannot be parsed.
+func Check(config *policy.PolicyConfig, repoName string, files []github.WorkflowFile) []Finding {
+	if !Enabled(config) || policy.ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	patterns := securityPatterns(config)
+	var findings []Finding
+	for _, file := range files {
+		jobs, err := github.ExtractJobs(file)
+		if err != nil {
+			continue
+		}
+		actions := github.ExtractActions([]github.WorkflowFile{file})
+
+		for _, job := range jobs {
+			if config.RequireJobTimeouts && job.Uses == "" && !job.HasTimeout {
+				findings = append(findings, Finding{
+					Rule:     RuleMissingTimeout,
+					Workflow: file.Path,
+					Job:      job.Name,
+					Ref:      file.Ref,
+					Detail:   "sets no timeout-minutes, so a hung run is only stopped after 6 hours",
+				})
+			}
+			if !config.DetectContinueOnError {
+				continue
+			}
+
+			for _, action := range actions {
+				if action.Job != job.Name || !isSecurityAction(patterns, action.Uses) {
+					continue
+				}
+				finding := Finding{Rule: RuleContinueOnError, Workflow: file.Path, Job: job.Name, Action: action.Uses, Line: action.Line, Ref: file.Ref}
+				switch {
+				case action.ContinueOnError:
+					finding.Detail = "ignores the failure of a security check with continue-on-error: true"
+				case job.ContinueOnError:
+					finding.Detail = "runs a security check in a job with continue-on-error: true, which ignores its failure"
+				default:
+					continue
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Workflow != b.Workflow {
+			return a.Workflow < b.Workflow
+		}
+		if a.Job != b.Job {
+			return a.Job < b.Job
+		}
+		return a.Line < b.Line
+	})
+	return findings
+}
+
+// securityPatterns returns the patterns of the security-relevant actions under a policy
+func securityPatterns(config *policy.PolicyConfig) []string {
+	patterns := slices.Clone(SecurityActions)
+	patterns = append(patterns, config.SecurityActions...)
+	for _, required := range config.RequiredActions {
+		name, _, _ := strings.Cut(required.Action, "@")
+		patterns = append(patterns, name)
+	}
+	return patterns
+}
+
+// isSecurityAction reports whether an action reference matches a security action pattern
+func isSecurityAction(patterns []string, uses string) bool {
+	name, _, _ := strings.Cut(strings.ToLower(uses), "@")
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package hygiene
+
+import (
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+const scanWorkflow = `on: push
+jobs:
+  scan:
+    runs-on: ubuntu-latest
+    timeout-minutes: 30
+    steps:
+      - uses: actions/checkout@v4
+        continue-on-error: true
+      - uses: github/codeql-action/analyze@v3
+        continue-on-error: true
+      - uses: org/security-scan@v2
+  audit:
+    runs-on: ubuntu-latest
+    continue-on-error: true
+    steps:
+      - uses: aquasecurity/trivy-action@0.28.0
+      - uses: actions/setup-node@v4
+  shared:
+    uses: org/workflows/.github/workflows/release.yml@v1
+`
+
+func TestCheck(t *testing.T) {
+	files := []github.WorkflowFile{{Path: ".github/workflows/scan.yml", Content: []byte(scanWorkflow)}}
+	config := &policy.PolicyConfig{
+		DetectContinueOnError: true,
+		RequireJobTimeouts:    true,
+		RequiredActions:       []policy.RequiredAction{{Action: "org/security-scan@v2"}},
+		ExcludedRepos:         []string{"org/excluded"},
+	}
+
+	findings := Check(config, "org/app", files)
+	expected := []Finding{
+		{Rule: RuleMissingTimeout, Job: "audit"},
+		{Rule: RuleContinueOnError, Job: "audit", Action: "aquasecurity/trivy-action@0.28.0"},
+		{Rule: RuleContinueOnError, Job: "scan", Action: "github/codeql-action/analyze@v3"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %+v", len(expected), findings)
+	}
+	for i, finding := range findings {
+		if finding.Rule != expected[i].Rule || finding.Job != expected[i].Job || finding.Action != expected[i].Action {
+			t.Errorf("Finding %d: expected %+v, got %+v", i, expected[i], finding)
+		}
+		if finding.Workflow != ".github/workflows/scan.yml" || finding.Detail == "" {
+			t.Errorf("Finding %d: expected the workflow and a detail, got %+v", i, finding)
+		}
+	}
+
+	// Policy additions and required actions are security-relevant too
+	config.RequiredActions = nil
+	config.SecurityActions = []string{"actions/checkout"}
+	config.RequireJobTimeouts = false
+	findings = Check(config, "org/app", files)
+	if len(findings) != 3 || findings[1].Action != "actions/checkout@v4" {
+		t.Errorf("Expected the checkout step to be reported, got %+v", findings)
+	}
+
+	if findings := Check(config, "org/excluded", files); findings != nil {
+		t.Errorf("Expected excluded repository to be skipped, got %+v", findings)
+	}
+	if findings := Check(&policy.PolicyConfig{}, "org/app", files); findings != nil {
+		t.Errorf("Expected no findings without hygiene checks, got %+v", findings)
+	}
+}
@@ -0,0 +1,100 @@
+package impact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/codeowners"
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Deprecation describes a runner label that is being retired
+type Deprecation struct {
+	Label       string `mapstructure:"label" yaml:"label" json:"label"`
+	Replacement string `mapstructure:"replacement" yaml:"replacement,omitempty" json:"replacement,omitempty"`
+	Deadline    string `mapstructure:"deadline" yaml:"deadline,omitempty" json:"deadline,omitempty"`
+}
+
+// RetiredRunners are the GitHub-hosted runner labels GitHub has deprecated or retired
+var RetiredRunners = []Deprecation{
+	{Label: "ubuntu-18.04", Replacement: "ubuntu-24.04"},
+	{Label: "ubuntu-20.04", Replacement: "ubuntu-24.04"},
+	{Label: "macos-10.15", Replacement: "macos-15"},
+	{Label: "macos-11", Replacement: "macos-15"},
+	{Label: "macos-12", Replacement: "macos-15"},
+	{Label: "macos-13", Replacement: "macos-15"},
+	{Label: "windows-2016", Replacement: "windows-2025"},
+	{Label: "windows-2019", Replacement: "windows-2025"},
+}
+
+// Impact is a single job pinned to a deprecated runner label
+type Impact struct {
+	Repository  string   `json:"repository"`
+	Workflow    string   `json:"workflow"`
+	Job         string   `json:"job"`
+	Label       string   `json:"label"`
+	Replacement string   `json:"replacement,omitempty"`
+	Deadline    string   `json:"deadline,omitempty"`
+	Owners      []string `json:"owners,omitempty"`
+}
+
+// ParseDeprecation parses a "label[=replacement]" command-line value
+func ParseDeprecation(value string) (Deprecation, error) {
+	label, replacement, _ := strings.Cut(value, "=")
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return Deprecation{}, fmt.Errorf("invalid runner label: %q", value)
+	}
+	return Deprecation{Label: label, Replacement: strings.TrimSpace(replacement)}, nil
+}
+
+// Analyze reports every job in the repository's workflows that runs on a deprecated label.
+// Owners are resolved from the repository's CODEOWNERS rules for the workflow file.
+func Analyze(repo string, files []github.WorkflowFile, deprecations []Deprecation, owners *codeowners.Ruleset) []Impact {
+	byLabel := make(map[string]Deprecation, len(deprecations))
+	for _, d := range deprecations {
+		byLabel[d.Label] = d
+	}
+
+	var impacts []Impact
+	for _, file := range files {
+		jobs, err := github.ExtractJobs(file)
+		if err != nil {
+			continue
+		}
+
+		for _, job := range jobs {
+			for _, label := range job.RunsOn {
+				deprecation, ok := byLabel[label]
+				if !ok {
+					continue
+				}
+				impacts = append(impacts, Impact{
+					Repository:  repo,
+					Workflow:    job.Workflow,
+					Job:         job.Name,
+					Label:       label,
+					Replacement: deprecation.Replacement,
+					Deadline:    deprecation.Deadline,
+					Owners:      owners.OwnersFor(job.Workflow),
+				})
+			}
+		}
+	}
+
+	return impacts
+}
+
+// Sort orders impacts by repository, workflow and job for consistent output
+func Sort(impacts []Impact) {
+	sort.Slice(impacts, func(i, j int) bool {
+		if impacts[i].Repository != impacts[j].Repository {
+			return impacts[i].Repository < impacts[j].Repository
+		}
+		if impacts[i].Workflow != impacts[j].Workflow {
+			return impacts[i].Workflow < impacts[j].Workflow
+		}
+		return impacts[i].Job < impacts[j].Job
+	})
+}
@@ -0,0 +1,67 @@
+package impact
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/codeowners"
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestParseDeprecation(t *testing.T) {
+	d, err := ParseDeprecation("ubuntu-20.04=ubuntu-24.04")
+	if err != nil {
+		t.Fatalf("ParseDeprecation returned error: %v", err)
+	}
+	if d.Label != "ubuntu-20.04" || d.Replacement != "ubuntu-24.04" {
+		t.Errorf("Unexpected deprecation: %+v", d)
+	}
+
+	d, err = ParseDeprecation("windows-2019")
+	if err != nil || d.Label != "windows-2019" || d.Replacement != "" {
+		t.Errorf("Expected label without replacement, got %+v (err: %v)", d, err)
+	}
+
+	if _, err := ParseDeprecation("=ubuntu-24.04"); err == nil {
+		t.Error("Expected error for missing label, got nil")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	files := []github.WorkflowFile{
+		{
+			Name: "ci.yml",
+			Path: ".github/workflows/ci.yml",
+			Content: []byte(`
+jobs:
+  build:
+    runs-on: ubuntu-20.04
+  test:
+    runs-on: ubuntu-latest
+`),
+		},
+	}
+
+	deprecations := []Deprecation{
+		{Label: "ubuntu-20.04", Replacement: "ubuntu-24.04", Deadline: "2025-04-15"},
+	}
+	owners := codeowners.Parse([]byte("/.github/ @org/ci-team\n"))
+
+	impacts := Analyze("org/repo", files, deprecations, owners)
+
+	expected := []Impact{
+		{
+			Repository:  "org/repo",
+			Workflow:    ".github/workflows/ci.yml",
+			Job:         "build",
+			Label:       "ubuntu-20.04",
+			Replacement: "ubuntu-24.04",
+			Deadline:    "2025-04-15",
+			Owners:      []string{"@org/ci-team"},
+		},
+	}
+
+	if !reflect.DeepEqual(impacts, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, impacts)
+	}
+}
@@ -0,0 +1,199 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/metadata"
+	"github.com/ihavespoons/action-control/internal/pinning"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// Policy statuses of an inventory record
+const (
+	StatusAllowed     = "allowed"     // Permitted in every repository using it
+	StatusDenied      = "denied"      // A violation in every repository using it
+	StatusPartial     = "partial"     // Permitted in some repositories only
+	StatusUnevaluated = "unevaluated" // No policy was provided
+)
+
+// Publisher describes the owner of an action
+type Publisher struct {
+	Owner    string `json:"owner"`
+	Verified *bool  `json:"verified,omitempty"` // Nil when the owner could not be looked up
+}
+
+// Record describes a single unique action across all scanned repositories
+type Record struct {
+	Action       string         `json:"action"`
+	Versions     []string       `json:"versions"`
+	Repositories []string       `json:"repositories"`
+	Pinning      pinning.Counts `json:"pinning"`
+	Publisher    Publisher      `json:"publisher"`
+	FirstSeen    time.Time      `json:"first_seen"`
+	PolicyStatus string         `json:"policy_status"`
+	Violations   []string       `json:"violating_repositories,omitempty"`
+
+	// Metadata of the repository publishing the action, with --enrich-metadata
+	Metadata *github.RepositoryMetadata `json:"metadata,omitempty"`
+}
+
+// Inventory lists every unique action in use, sorted by action name
+type Inventory struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Actions     []Record  `json:"actions"`
+}
+
+// Build creates an inventory from the actions discovered in each repository. When config is
+// non-nil each action's policy status is evaluated per repository; first-seen timestamps are
+// carried over from previous when the action was already inventoried.
+func Build(actionsMap map[string][]github.Action, config *policy.PolicyConfig, previous *Inventory, now time.Time) *Inventory {
+	firstSeen := make(map[string]time.Time)
+	if previous != nil {
+		for _, record := range previous.Actions {
+			firstSeen[record.Action] = record.FirstSeen
+		}
+	}
+
+	records := make(map[string]*Record)
+	for repo, actions := range actionsMap {
+		for _, action := range actions {
+			name, _, _ := strings.Cut(action.Uses, "@")
+			if strings.HasPrefix(action.Uses, "./") {
+				continue // Local actions are part of the repository itself
+			}
+
+			record, ok := records[name]
+			if !ok {
+				record = &Record{
+					Action:    name,
+					Publisher: Publisher{Owner: policy.ActionOwner(action.Uses)},
+					FirstSeen: now,
+				}
+				if seen, ok := firstSeen[name]; ok {
+					record.FirstSeen = seen
+				}
+				records[name] = record
+			}
+
+			if !containsString(record.Versions, action.Uses) {
+				record.Versions = append(record.Versions, action.Uses)
+			}
+			if !containsString(record.Repositories, repo) {
+				record.Repositories = append(record.Repositories, repo)
+			}
+			if kind, ok := pinning.Classify(action.Uses); ok {
+				switch kind {
+				case pinning.SHA:
+					record.Pinning.SHA++
+				case pinning.Tag:
+					record.Pinning.Tag++
+				case pinning.Branch:
+					record.Pinning.Branch++
+				case pinning.Unpinned:
+					record.Pinning.Unpinned++
+				}
+			}
+		}
+	}
+
+	inventory := &Inventory{GeneratedAt: now}
+	for _, record := range records {
+		sort.Strings(record.Versions)
+		sort.Strings(record.Repositories)
+		record.PolicyStatus = StatusUnevaluated
+		if config != nil {
+			evaluate(record, actionsMap, config)
+		}
+		inventory.Actions = append(inventory.Actions, *record)
+	}
+
+	sort.Slice(inventory.Actions, func(i, j int) bool {
+		return inventory.Actions[i].Action < inventory.Actions[j].Action
+	})
+
+	return inventory
+}
+
+// evaluate determines the record's policy status across the repositories using it
+func evaluate(record *Record, actionsMap map[string][]github.Action, config *policy.PolicyConfig) {
+	for _, repo := range record.Repositories {
+		var uses []string
+		for _, action := range actionsMap[repo] {
+			if action.Uses == record.Action || strings.HasPrefix(action.Uses, record.Action+"@") {
+				uses = append(uses, action.Uses)
+			}
+		}
+		if _, compliant := policy.CheckActionCompliance(config, repo, uses); !compliant {
+			record.Violations = append(record.Violations, repo)
+		}
+	}
+
+	switch len(record.Violations) {
+	case 0:
+		record.PolicyStatus = StatusAllowed
+	case len(record.Repositories):
+		record.PolicyStatus = StatusDenied
+	default:
+		record.PolicyStatus = StatusPartial
+	}
+}
+
+// EnrichPublishers looks up whether each action's owner is a verified creator.
+// Owners that cannot be looked up are left unset.
+func (inv *Inventory) EnrichPublishers(ctx context.Context, verifier policy.CreatorVerifier) {
+	for i := range inv.Actions {
+		publisher := &inv.Actions[i].Publisher
+		if publisher.Owner == "" {
+			continue
+		}
+		if verified, err := verifier.IsVerifiedCreator(ctx, publisher.Owner); err == nil {
+			publisher.Verified = &verified
+		}
+	}
+}
+
+// EnrichMetadata looks up the archived status, last push, star count and open security
+// advisories of each action's repository. Repositories that cannot be looked up are left unset.
+func (inv *Inventory) EnrichMetadata(ctx context.Context, fetcher metadata.Fetcher) {
+	for i := range inv.Actions {
+		owner, repo, ok := metadata.ActionRepository(inv.Actions[i].Action)
+		if !ok {
+			continue
+		}
+		if repoMetadata, err := fetcher.GetRepositoryMetadata(ctx, owner, repo); err == nil {
+			inv.Actions[i].Metadata = &repoMetadata
+		}
+	}
+}
+
+// Load reads a previously written JSON inventory
+func Load(path string) (*Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory: %w", err)
+	}
+
+	var inventory Inventory
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory: %w", err)
+	}
+
+	return &inventory, nil
+}
+
+// containsString checks if a string slice contains a specific string
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+var testActions = map[string][]github.Action{
+	"org/repo1": {
+		{Uses: "actions/checkout@v4"},
+		{Uses: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683"},
+		{Uses: "some/action@main"},
+		{Uses: "./local"},
+	},
+	"org/repo2": {
+		{Uses: "actions/checkout@v4"},
+		{Uses: "some/action@main"},
+	},
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	earlier := now.AddDate(0, -2, 0)
+	previous := &Inventory{Actions: []Record{{Action: "actions/checkout", FirstSeen: earlier}}}
+
+	config := &policy.PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"actions/checkout"},
+		CustomRules: map[string]policy.Policy{
+			"org/repo2": {PolicyMode: "allow", AllowedActions: []string{"actions/checkout", "some/action"}},
+		},
+	}
+
+	inventory := Build(testActions, config, previous, now)
+
+	if len(inventory.Actions) != 2 {
+		t.Fatalf("Expected 2 unique actions, got %d", len(inventory.Actions))
+	}
+
+	checkout := inventory.Actions[0]
+	if checkout.Action != "actions/checkout" || !reflect.DeepEqual(checkout.Repositories, []string{"org/repo1", "org/repo2"}) {
+		t.Errorf("Unexpected checkout record: %+v", checkout)
+	}
+	if len(checkout.Versions) != 2 || checkout.Pinning.SHA != 1 || checkout.Pinning.Tag != 2 {
+		t.Errorf("Unexpected versions or pinning: %v %+v", checkout.Versions, checkout.Pinning)
+	}
+	if !checkout.FirstSeen.Equal(earlier) || checkout.PolicyStatus != StatusAllowed || checkout.Publisher.Owner != "actions" {
+		t.Errorf("Unexpected checkout metadata: %+v", checkout)
+	}
+
+	other := inventory.Actions[1]
+	if !other.FirstSeen.Equal(now) || other.PolicyStatus != StatusPartial || !reflect.DeepEqual(other.Violations, []string{"org/repo1"}) {
+		t.Errorf("Unexpected some/action record: %+v", other)
+	}
+
+	if unevaluated := Build(testActions, nil, nil, now); unevaluated.Actions[0].PolicyStatus != StatusUnevaluated {
+		t.Errorf("Expected unevaluated status without policy, got %s", unevaluated.Actions[0].PolicyStatus)
+	}
+}
+
+type fakeVerifier map[string]bool
+
+func (f fakeVerifier) IsVerifiedCreator(ctx context.Context, owner string) (bool, error) {
+	verified, ok := f[owner]
+	if !ok {
+		return false, errors.New("lookup failed")
+	}
+	return verified, nil
+}
+
+func TestEnrichPublishers(t *testing.T) {
+	inventory := Build(testActions, nil, nil, time.Now())
+	inventory.EnrichPublishers(context.Background(), fakeVerifier{"actions": true})
+
+	if v := inventory.Actions[0].Publisher.Verified; v == nil || !*v {
+		t.Errorf("Expected actions to be verified, got %v", v)
+	}
+	if v := inventory.Actions[1].Publisher.Verified; v != nil {
+		t.Errorf("Expected unknown verification for failed lookup, got %v", *v)
+	}
+}
+
+type fakeMetadata map[string]github.RepositoryMetadata
+
+func (f fakeMetadata) GetRepositoryMetadata(ctx context.Context, owner, repo string) (github.RepositoryMetadata, error) {
+	repoMetadata, ok := f[owner+"/"+repo]
+	if !ok {
+		return github.RepositoryMetadata{}, errors.New("lookup failed")
+	}
+	return repoMetadata, nil
+}
+
+func TestEnrichMetadata(t *testing.T) {
+	inventory := Build(testActions, nil, nil, time.Now())
+	inventory.EnrichMetadata(context.Background(), fakeMetadata{"actions/checkout": {Repository: "actions/checkout", Stars: 6000}})
+
+	if m := inventory.Actions[0].Metadata; m == nil || m.Stars != 6000 {
+		t.Errorf("Expected checkout metadata, got %+v", m)
+	}
+	if m := inventory.Actions[1].Metadata; m != nil {
+		t.Errorf("Expected no metadata for failed lookup, got %+v", m)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	if err := os.WriteFile(path, []byte(`{"actions": [{"action": "actions/checkout", "first_seen": "2026-01-01T00:00:00Z"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inventory, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(inventory.Actions) != 1 || inventory.Actions[0].FirstSeen.Year() != 2026 {
+		t.Errorf("Unexpected inventory: %+v", inventory)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file, got nil")
+	}
+}
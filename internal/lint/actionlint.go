@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Finding represents a single actionlint diagnostic
+type Finding struct {
+	File    string `json:"filepath"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Linter runs actionlint against workflow files
+type Linter struct {
+	Path string // Path to the actionlint binary
+}
+
+// NewLinter creates a Linter that uses actionlint from PATH
+func NewLinter() *Linter {
+	return &Linter{
+		Path: "actionlint",
+	}
+}
+
+// Available reports whether the actionlint binary can be found
+func (l *Linter) Available() bool {
+	_, err := exec.LookPath(l.Path)
+	return err == nil
+}
+
+// Lint runs actionlint on a single workflow file and returns its findings.
+// shellcheck and pyflakes findings are included when those tools are installed.
+func (l *Linter) Lint(file github.WorkflowFile) ([]Finding, error) {
+	cmd := exec.Command(l.Path, "-format", "{{json .}}", "-stdin-filename", file.Path, "-")
+	cmd.Stdin = bytes.NewReader(file.Content)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// actionlint exits with status 1 when it reports findings
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("actionlint failed on %s: %w: %s", file.Path, err, stderr.String())
+		}
+	}
+
+	return parseOutput(stdout.Bytes())
+}
+
+// LintFiles runs actionlint on each workflow file and combines the findings
+func (l *Linter) LintFiles(files []github.WorkflowFile) ([]Finding, error) {
+	var findings []Finding
+	for _, file := range files {
+		// actionlint only checks workflows, not composite action files
+		if file.IsAction() {
+			continue
+		}
+		fileFindings, err := l.Lint(file)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, fileFindings...)
+	}
+	return findings, nil
+}
+
+// parseOutput decodes actionlint's JSON output
+func parseOutput(output []byte) ([]Finding, error) {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	var findings []Finding
+	if err := json.Unmarshal(output, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse actionlint output: %w", err)
+	}
+	return findings, nil
+}
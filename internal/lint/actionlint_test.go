@@ -0,0 +1,68 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestParseOutput(t *testing.T) {
+	output := []byte(`[
+  {
+    "message": "property \"foo\" is not defined",
+    "filepath": ".github/workflows/ci.yml",
+    "line": 12,
+    "column": 20,
+    "kind": "expression",
+    "snippet": "${{ foo.bar }}",
+    "end_column": 30
+  }
+]`)
+
+	findings, err := parseOutput(output)
+	if err != nil {
+		t.Fatalf("parseOutput returned error: %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d", len(findings))
+	}
+
+	finding := findings[0]
+	if finding.File != ".github/workflows/ci.yml" || finding.Line != 12 || finding.Kind != "expression" {
+		t.Errorf("Unexpected finding: %+v", finding)
+	}
+
+	// Empty output means no findings
+	findings, err = parseOutput([]byte("\n"))
+	if err != nil || findings != nil {
+		t.Errorf("Expected no findings for empty output, got %v (err: %v)", findings, err)
+	}
+}
+
+func TestLint(t *testing.T) {
+	linter := NewLinter()
+	if !linter.Available() {
+		t.Skip("actionlint not installed")
+	}
+
+	file := github.WorkflowFile{
+		Name: "ci.yml",
+		Path: ".github/workflows/ci.yml",
+		Content: []byte(`on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown.value }}
+`),
+	}
+
+	findings, err := linter.Lint(file)
+	if err != nil {
+		t.Fatalf("Lint returned error: %v", err)
+	}
+	if len(findings) == 0 {
+		t.Error("Expected actionlint to report the undefined context")
+	}
+}
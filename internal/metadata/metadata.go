@@ -0,0 +1,101 @@
+package metadata
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// Reasons an action fails the upkeep thresholds
+const (
+	ReasonArchived = "archived" // The action's repository is archived
+	ReasonStale    = "stale"    // The action's repository was not pushed to within max_staleness_days
+	ReasonFork     = "fork"     // The action is hosted in a fork, possibly of a well-known action
+	ReasonNew      = "new"      // The action's repository was created within min_repository_age_days
+)
+
+// Fetcher retrieves metadata of the repository publishing an action
+type Fetcher interface {
+	GetRepositoryMetadata(ctx context.Context, owner, repo string) (github.RepositoryMetadata, error)
+}
+
+// Finding is an action whose repository fails an upkeep threshold of the policy
+type Finding struct {
+	Action   string                    `json:"action"`
+	Reason   string                    `json:"reason"`
+	Metadata github.RepositoryMetadata `json:"metadata"`
+}
+
+// HasThresholds reports whether the policy sets any upkeep threshold or supply-chain heuristic
+// for action repositories
+func HasThresholds(config *policy.PolicyConfig) bool {
+	return config.DenyArchivedActions || config.MaxStalenessDays > 0 || config.DenyForkActions || config.MinRepositoryAgeDays > 0
+}
+
+// Check looks up the repository of each action and reports those that are archived when the
+// policy sets deny_archived_actions, forks when it sets deny_fork_actions, created within
+// min_repository_age_days, or last pushed to before max_staleness_days ago. Each action is
+// reported for the first of these reasons that applies. Local actions, container images and actions whose repository cannot be looked up are not
+// reported, nor are actions in excluded repositories.
+func Check(ctx context.Context, config *policy.PolicyConfig, repoName string, actions []string, fetcher Fetcher, now time.Time) []Finding {
+	if !HasThresholds(config) || policy.ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var findings []Finding
+	for _, action := range actions {
+		if seen[action] {
+			continue
+		}
+		seen[action] = true
+
+		owner, repo, ok := ActionRepository(action)
+		if !ok {
+			continue
+		}
+		metadata, err := fetcher.GetRepositoryMetadata(ctx, owner, repo)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case config.DenyArchivedActions && metadata.Archived:
+			findings = append(findings, Finding{Action: action, Reason: ReasonArchived, Metadata: metadata})
+		case config.DenyForkActions && metadata.Fork:
+			findings = append(findings, Finding{Action: action, Reason: ReasonFork, Metadata: metadata})
+		case config.MinRepositoryAgeDays > 0 && !metadata.CreatedAt.IsZero() && now.Sub(metadata.CreatedAt) < days(config.MinRepositoryAgeDays):
+			findings = append(findings, Finding{Action: action, Reason: ReasonNew, Metadata: metadata})
+		case config.MaxStalenessDays > 0 && !metadata.PushedAt.IsZero() && now.Sub(metadata.PushedAt) > days(config.MaxStalenessDays):
+			findings = append(findings, Finding{Action: action, Reason: ReasonStale, Metadata: metadata})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Action < findings[j].Action
+	})
+	return findings
+}
+
+// days returns the duration of n days
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// ActionRepository returns the repository publishing an action reference such as
+// "github/codeql-action/init@v3". Local actions and container images have no repository.
+func ActionRepository(action string) (owner, repo string, ok bool) {
+	if strings.HasPrefix(action, "./") || strings.HasPrefix(action, "docker://") {
+		return "", "", false
+	}
+	name, _, _ := strings.Cut(action, "@")
+	parts := strings.SplitN(name, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
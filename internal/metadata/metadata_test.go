@@ -0,0 +1,125 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// fakeFetcher serves repository metadata from a map keyed by owner/repo
+type fakeFetcher map[string]github.RepositoryMetadata
+
+func (f fakeFetcher) GetRepositoryMetadata(ctx context.Context, owner, repo string) (github.RepositoryMetadata, error) {
+	metadata, ok := f[owner+"/"+repo]
+	if !ok {
+		return github.RepositoryMetadata{}, errors.New("not found")
+	}
+	return metadata, nil
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	fetcher := fakeFetcher{
+		"actions/checkout":     {Repository: "actions/checkout", PushedAt: now.AddDate(0, 0, -3), Stars: 6000},
+		"old/action":           {Repository: "old/action", Archived: true, PushedAt: now.AddDate(-3, 0, 0)},
+		"quiet/action":         {Repository: "quiet/action", PushedAt: now.AddDate(0, 0, -400)},
+		"github/codeql-action": {Repository: "github/codeql-action", Archived: true, PushedAt: now},
+		"act1ons/checkout":     {Repository: "act1ons/checkout", Fork: true, Parent: "actions/checkout", CreatedAt: now.AddDate(-1, 0, 0), PushedAt: now},
+		"fresh/action":         {Repository: "fresh/action", CreatedAt: now.AddDate(0, 0, -5), PushedAt: now},
+	}
+	actions := []string{
+		"actions/checkout@v4",
+		"old/action@v1",
+		"quiet/action@v2",
+		"quiet/action@v2",
+		"github/codeql-action/init@v3",
+		"unknown/action@v1",
+		"act1ons/checkout@v4",
+		"fresh/action@v1",
+		"./local-action",
+		"docker://alpine:3",
+	}
+
+	tests := []struct {
+		name     string
+		config   *policy.PolicyConfig
+		expected map[string]string
+	}{
+		{
+			name:     "no thresholds",
+			config:   &policy.PolicyConfig{},
+			expected: map[string]string{},
+		},
+		{
+			name:   "archived only",
+			config: &policy.PolicyConfig{DenyArchivedActions: true},
+			expected: map[string]string{
+				"old/action@v1":                ReasonArchived,
+				"github/codeql-action/init@v3": ReasonArchived,
+			},
+		},
+		{
+			name:   "archived and staleness",
+			config: &policy.PolicyConfig{DenyArchivedActions: true, MaxStalenessDays: 365},
+			expected: map[string]string{
+				"old/action@v1":                ReasonArchived,
+				"quiet/action@v2":              ReasonStale,
+				"github/codeql-action/init@v3": ReasonArchived,
+			},
+		},
+		{
+			name:   "supply-chain heuristics",
+			config: &policy.PolicyConfig{DenyForkActions: true, MinRepositoryAgeDays: 30},
+			expected: map[string]string{
+				"act1ons/checkout@v4": ReasonFork,
+				"fresh/action@v1":     ReasonNew,
+			},
+		},
+		{
+			name:     "excluded repository",
+			config:   &policy.PolicyConfig{DenyArchivedActions: true, ExcludedRepos: []string{"org/repo"}},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Check(context.Background(), tt.config, "org/repo", actions, fetcher, now)
+			if len(findings) != len(tt.expected) {
+				t.Fatalf("Expected %d findings, got %d: %+v", len(tt.expected), len(findings), findings)
+			}
+			for i, finding := range findings {
+				if reason, ok := tt.expected[finding.Action]; !ok || reason != finding.Reason {
+					t.Errorf("Unexpected finding %s (%s)", finding.Action, finding.Reason)
+				}
+				if i > 0 && findings[i-1].Action > finding.Action {
+					t.Errorf("Findings are not sorted: %s before %s", findings[i-1].Action, finding.Action)
+				}
+			}
+		})
+	}
+}
+
+func TestActionRepository(t *testing.T) {
+	tests := map[string]string{
+		"actions/checkout@v4":          "actions/checkout",
+		"github/codeql-action/init@v3": "github/codeql-action",
+		"./local-action":               "",
+		"docker://alpine:3":            "",
+		"invalid@v1":                   "",
+	}
+	for action, expected := range tests {
+		owner, repo, ok := ActionRepository(action)
+		got := ""
+		if ok {
+			got = owner + "/" + repo
+		}
+		if got != expected {
+			t.Errorf("ActionRepository(%q) = %q, want %q", action, got, expected)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+)
+
+// Digest summarizes the outcome of an enforce run for notifications
+type Digest struct {
+	Target       string // Scanned organization or repository
+	GeneratedAt  time.Time
+	Total        int                // Evaluated repositories
+	Repositories []RepositoryDigest // Repositories not complying with the policy, sorted by name
+	Interrupted  string             // Why the scan stopped early, leaving the results partial
+}
+
+// RepositoryDigest summarizes the findings of a repository not complying with the policy
+type RepositoryDigest struct {
+	Name       string
+	Owners     []string // Owning teams and users, when known
+	Findings   int
+	Severities formatter.SeverityCounts
+	Violations []string // Violating action references
+}
+
+// NewDigest summarizes an enforce report
+func NewDigest(target string, report formatter.EnforceReport, now time.Time) Digest {
+	digest := Digest{
+		Target:      target,
+		GeneratedAt: now,
+		Total:       len(report.Repositories),
+		Interrupted: report.Interrupted,
+	}
+	for name, result := range report.Repositories {
+		if result.Compliant {
+			continue
+		}
+		digest.Repositories = append(digest.Repositories, RepositoryDigest{
+			Name:       name,
+			Owners:     result.Owners,
+			Findings:   result.Findings(),
+			Severities: result.Severities,
+			Violations: result.Violations,
+		})
+	}
+	sort.Slice(digest.Repositories, func(i, j int) bool {
+		return digest.Repositories[i].Name < digest.Repositories[j].Name
+	})
+	return digest
+}
+
+// For narrows the digest to the given repositories, such as those of one owner
+func (d Digest) For(repos []string) Digest {
+	narrowed := d
+	narrowed.Total = len(repos)
+	narrowed.Repositories = nil
+	for _, repo := range d.Repositories {
+		if slices.Contains(repos, repo.Name) {
+			narrowed.Repositories = append(narrowed.Repositories, repo)
+		}
+	}
+	return narrowed
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultSMTPPort is the SMTP submission port, used with STARTTLS when the server offers it
+const DefaultSMTPPort = 587
+
+// defaultEmailTemplate renders the body of digest emails
+const defaultEmailTemplate = `Action policy compliance digest for {{.Target}}, {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}
+{{if .Interrupted}}
+The scan was interrupted ({{.Interrupted}}), repositories not scanned are missing.
+{{end}}
+{{- if .Repositories}}
+{{len .Repositories}} of {{.Total}} repositories do not comply with the action policy:
+{{range .Repositories}}
+{{.Name}}{{if .Owners}} ({{join .Owners ", "}}){{end}}: {{.Severities.Errors}} errors, {{.Severities.Warnings}} warnings
+{{- range .Violations}}
+  - {{.}}
+{{- end}}
+{{end}}
+{{- else}}
+All {{.Total}} repositories comply with the action policy.
+{{end}}`
+
+// emailFuncs are the functions available to email templates
+var emailFuncs = template.FuncMap{"join": strings.Join}
+
+// EmailConfig configures the email notifier
+type EmailConfig struct {
+	Host       string
+	Port       int // DefaultSMTPPort when zero
+	Username   string
+	Password   string
+	From       string
+	Recipients []string // Receive the digest of every repository, such as a security distribution list
+	Subject    string   // Subject template, rendered like the body
+	Template   string   // Path of a text/template file for the body, rendered with a Digest
+}
+
+// Email sends digests by SMTP
+type Email struct {
+	config  EmailConfig
+	subject *template.Template
+	body    *template.Template
+	now     func() time.Time
+	send    func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmail prepares an email notifier, reading its body template
+func NewEmail(config EmailConfig) (*Email, error) {
+	if config.Host == "" || config.From == "" {
+		return nil, errors.New("email notifications need an SMTP host and a from address")
+	}
+	if config.Port == 0 {
+		config.Port = DefaultSMTPPort
+	}
+	if config.Subject == "" {
+		config.Subject = `action-control: {{if .Repositories}}{{len .Repositories}} of {{.Total}} repositories do not comply{{else}}all {{.Total}} repositories comply{{end}} ({{.Target}})`
+	}
+
+	body := defaultEmailTemplate
+	if config.Template != "" {
+		content, err := os.ReadFile(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email template: %w", err)
+		}
+		body = string(content)
+	}
+
+	email := &Email{config: config, now: time.Now, send: smtp.SendMail}
+	var err error
+	if email.subject, err = template.New("subject").Funcs(emailFuncs).Parse(config.Subject); err != nil {
+		return nil, fmt.Errorf("failed to parse email subject: %w", err)
+	}
+	if email.body, err = template.New("body").Funcs(emailFuncs).Parse(body); err != nil {
+		return nil, fmt.Errorf("failed to parse email template: %w", err)
+	}
+	return email, nil
+}
+
+// Notify emails the digest of every repository to the configured recipients, and to each owner
+// in ownerEmails the digest of the repositories they own. Owners only receive an email when
+// one of their repositories does not comply.
+func (e *Email) Notify(digest Digest, ownerEmails map[string][]string) error {
+	var errs []error
+	if len(e.config.Recipients) > 0 {
+		if err := e.deliver(e.config.Recipients, digest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Collect each owner's repositories, so that an owner of several gets a single email
+	reposByEmail := make(map[string][]string)
+	for _, repo := range digest.Repositories {
+		for _, address := range ownerEmails[repo.Name] {
+			reposByEmail[address] = append(reposByEmail[address], repo.Name)
+		}
+	}
+	addresses := make([]string, 0, len(reposByEmail))
+	for address := range reposByEmail {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		if err := e.deliver([]string{address}, digest.For(reposByEmail[address])); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver renders a digest and sends it to the recipients
+func (e *Email) deliver(to []string, digest Digest) error {
+	var subject, body bytes.Buffer
+	if err := e.subject.Execute(&subject, digest); err != nil {
+		return fmt.Errorf("failed to render email subject: %w", err)
+	}
+	if err := e.body.Execute(&body, digest); err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.TrimSpace(subject.String())))
+	fmt.Fprintf(&msg, "Date: %s\r\n", e.now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body.String(), "\r\n", "\n"), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+	}
+	addr := net.JoinHostPort(e.config.Host, strconv.Itoa(e.config.Port))
+	if err := e.send(addr, auth, e.config.From, to, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to email %s: %w", strings.Join(to, ", "), err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"errors"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+)
+
+// sentEmail is an email captured by the fake send function
+type sentEmail struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	msg  string
+}
+
+// fakeEmail returns an email notifier that records emails instead of sending them
+func fakeEmail(t *testing.T, config EmailConfig) (*Email, *[]sentEmail) {
+	t.Helper()
+	email, err := NewEmail(config)
+	if err != nil {
+		t.Fatalf("NewEmail() error: %v", err)
+	}
+	email.now = func() time.Time { return time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC) }
+	var sent []sentEmail
+	email.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		sent = append(sent, sentEmail{addr: addr, auth: auth, from: from, to: to, msg: string(msg)})
+		return nil
+	}
+	return email, &sent
+}
+
+func testReport() formatter.EnforceReport {
+	return formatter.EnforceReport{Repositories: map[string]formatter.RepositoryResult{
+		"your-org/api":       {Compliant: false, Owners: []string{"@your-org/platform"}, Violations: []string{"bad/action@v1"}, Severities: formatter.SeverityCounts{Errors: 1}},
+		"your-org/web":       {Compliant: false, Violations: []string{"other/action@main"}, Severities: formatter.SeverityCounts{Errors: 1}},
+		"your-org/compliant": {Compliant: true},
+	}}
+}
+
+func TestNewDigest(t *testing.T) {
+	digest := NewDigest("your-org", testReport(), time.Now())
+	if digest.Total != 3 || len(digest.Repositories) != 2 {
+		t.Fatalf("Expected 2 of 3 repositories in the digest, got %d of %d", len(digest.Repositories), digest.Total)
+	}
+	if digest.Repositories[0].Name != "your-org/api" || digest.Repositories[0].Findings != 1 {
+		t.Errorf("Expected your-org/api with 1 finding first, got %+v", digest.Repositories[0])
+	}
+
+	narrowed := digest.For([]string{"your-org/web"})
+	if narrowed.Total != 1 || len(narrowed.Repositories) != 1 || narrowed.Repositories[0].Name != "your-org/web" {
+		t.Errorf("Expected the digest narrowed to your-org/web, got %+v", narrowed)
+	}
+}
+
+func TestEmailNotify(t *testing.T) {
+	email, sent := fakeEmail(t, EmailConfig{
+		Host:       "smtp.example.com",
+		Username:   "action-control",
+		Password:   "secret",
+		From:       "action-control@example.com",
+		Recipients: []string{"security@example.com"},
+	})
+
+	digest := NewDigest("your-org", testReport(), time.Now())
+	owners := map[string][]string{
+		"your-org/api":       {"api@example.com", "platform@example.com"},
+		"your-org/web":       {"platform@example.com"},
+		"your-org/compliant": {"compliant@example.com"},
+	}
+	if err := email.Notify(digest, owners); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+
+	if len(*sent) != 3 {
+		t.Fatalf("Expected emails to the security list and 2 owners, got %d", len(*sent))
+	}
+	security := (*sent)[0]
+	if security.addr != "smtp.example.com:587" || security.auth == nil || security.from != "action-control@example.com" {
+		t.Errorf("Expected authenticated submission on port 587, got %s from %s", security.addr, security.from)
+	}
+	for _, expected := range []string{
+		"To: security@example.com\r\n",
+		"Subject: action-control: 2 of 3 repositories do not comply (your-org)\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"your-org/api (@your-org/platform): 1 errors, 0 warnings\r\n  - bad/action@v1",
+		"your-org/web",
+	} {
+		if !strings.Contains(security.msg, expected) {
+			t.Errorf("Expected the digest email to contain %q, got:\n%s", expected, security.msg)
+		}
+	}
+
+	api := (*sent)[1]
+	if api.to[0] != "api@example.com" || strings.Contains(api.msg, "your-org/web") || !strings.Contains(api.msg, "1 of 1 repositories") {
+		t.Errorf("Expected the api owner to only receive your-org/api, got:\n%s", api.msg)
+	}
+	platform := (*sent)[2]
+	if platform.to[0] != "platform@example.com" || !strings.Contains(platform.msg, "your-org/api") || !strings.Contains(platform.msg, "your-org/web") {
+		t.Errorf("Expected the platform owner to receive both repositories in one email, got:\n%s", platform.msg)
+	}
+}
+
+func TestEmailTemplateAndErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digest.tmpl")
+	if err := os.WriteFile(path, []byte("{{range .Repositories}}{{.Name}};{{end}}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	email, sent := fakeEmail(t, EmailConfig{Host: "localhost", Port: 25, From: "a@example.com", Recipients: []string{"b@example.com"}, Template: path})
+	if err := email.Notify(NewDigest("your-org", testReport(), time.Now()), nil); err != nil {
+		t.Fatalf("Notify() error: %v", err)
+	}
+	if !strings.HasSuffix((*sent)[0].msg, "\r\n\r\nyour-org/api;your-org/web;") || (*sent)[0].auth != nil {
+		t.Errorf("Expected the custom template without authentication, got:\n%s", (*sent)[0].msg)
+	}
+
+	email.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+	if err := email.Notify(NewDigest("your-org", testReport(), time.Now()), nil); err == nil || !strings.Contains(err.Error(), "b@example.com") {
+		t.Errorf("Expected a delivery error naming the recipient, got %v", err)
+	}
+
+	if _, err := NewEmail(EmailConfig{From: "a@example.com"}); err == nil {
+		t.Error("Expected an email notifier without a host to be rejected")
+	}
+}
@@ -0,0 +1,200 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultJiraIssueType is the type of the issues the Jira notifier opens
+const DefaultJiraIssueType = "Task"
+
+// fingerprintPrefix starts the label identifying the issue of a repository
+const fingerprintPrefix = "action-control-"
+
+// JiraConfig configures the Jira notifier
+type JiraConfig struct {
+	URL       string   // Base URL of the Jira site, such as https://your-org.atlassian.net
+	Username  string   // Account email for Jira Cloud API tokens; empty to send Token as a personal access token
+	Token     string   // API token or personal access token
+	Project   string   // Key of the project issues are opened in
+	IssueType string   // DefaultJiraIssueType when empty
+	Labels    []string // Added to opened issues besides the fingerprint label
+}
+
+// Jira opens an issue per repository not complying with the policy, and updates it on later runs
+type Jira struct {
+	config JiraConfig
+	client *http.Client
+}
+
+// NewJira prepares a Jira notifier sending requests with client
+func NewJira(config JiraConfig, client *http.Client) (*Jira, error) {
+	if config.URL == "" || config.Project == "" || config.Token == "" {
+		return nil, errors.New("Jira notifications need a site URL, a project key and a token")
+	}
+	if _, err := url.ParseRequestURI(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid Jira URL: %s", config.URL)
+	}
+	if config.IssueType == "" {
+		config.IssueType = DefaultJiraIssueType
+	}
+	config.URL = strings.TrimRight(config.URL, "/")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Jira{config: config, client: client}, nil
+}
+
+// Fingerprint returns the stable label identifying the issue of a repository. It depends only on
+// the repository name, so every run finds the issue opened by earlier runs.
+func Fingerprint(repo string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(repo)))
+	return fingerprintPrefix + hex.EncodeToString(sum[:])[:16]
+}
+
+// Notify opens an issue for each repository in the digest, or updates the summary and
+// description of its open issue when an earlier run opened one
+func (j *Jira) Notify(digest Digest) error {
+	var errs []error
+	for _, repo := range digest.Repositories {
+		if err := j.sync(digest, repo); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update Jira issue of %s: %w", repo.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// jiraIssue holds the fields of an issue the notifier writes
+type jiraIssue struct {
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// sync opens or updates the issue of a repository
+func (j *Jira) sync(digest Digest, repo RepositoryDigest) error {
+	fingerprint := Fingerprint(repo.Name)
+	key, err := j.findIssue(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"summary":     fmt.Sprintf("%s does not comply with the GitHub Actions policy", repo.Name),
+		"description": jiraDescription(digest, repo),
+	}
+	if key != "" {
+		return j.do(http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(key), jiraIssue{Fields: fields}, nil)
+	}
+
+	fields["project"] = map[string]string{"key": j.config.Project}
+	fields["issuetype"] = map[string]string{"name": j.config.IssueType}
+	fields["labels"] = append([]string{fingerprint}, j.config.Labels...)
+	return j.do(http.MethodPost, "/rest/api/2/issue", jiraIssue{Fields: fields}, nil)
+}
+
+// findIssue returns the key of the open issue labelled with the fingerprint in the project, or
+// an empty key when there is none. Jira Cloud searches with /search/jql, and Jira Data Center
+// and Server, which lack it, with /search.
+func (j *Jira) findIssue(fingerprint string) (string, error) {
+	query := url.Values{
+		"jql":        {fmt.Sprintf(`project = "%s" AND labels = "%s" AND statusCategory != Done ORDER BY created ASC`, j.config.Project, fingerprint)},
+		"fields":     {"key"},
+		"maxResults": {"1"},
+	}
+	var result struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+
+	err := j.do(http.MethodGet, "/rest/api/2/search/jql?"+query.Encode(), nil, &result)
+	var status *jiraStatusError
+	if errors.As(err, &status) && status.code == http.StatusNotFound {
+		err = j.do(http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &result)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(result.Issues) == 0 {
+		return "", nil
+	}
+	return result.Issues[0].Key, nil
+}
+
+// jiraStatusError is an unsuccessful response from the Jira API
+type jiraStatusError struct {
+	code int
+	body string
+}
+
+func (e *jiraStatusError) Error() string {
+	return fmt.Sprintf("Jira API responded %d: %s", e.code, e.body)
+}
+
+// do sends a request to the Jira API, encoding body and decoding the response into result when
+// they are non-nil
+func (j *Jira) do(method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, j.config.URL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if j.config.Username != "" {
+		req.SetBasicAuth(j.config.Username, j.config.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.config.Token)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &jiraStatusError{code: resp.StatusCode, body: strings.TrimSpace(string(message))}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// jiraDescription describes the findings of a repository in Jira wiki markup
+func jiraDescription(digest Digest, repo RepositoryDigest) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "action-control found %d findings in *%s* that do not comply with the GitHub Actions policy: %d errors and %d warnings.\n",
+		repo.Findings, repo.Name, repo.Severities.Errors, repo.Severities.Warnings)
+	if len(repo.Owners) > 0 {
+		fmt.Fprintf(&sb, "\nOwners: %s\n", strings.Join(repo.Owners, ", "))
+	}
+	if len(repo.Violations) > 0 {
+		sb.WriteString("\nh3. Violating actions\n")
+		for _, violation := range repo.Violations {
+			fmt.Fprintf(&sb, "* {{%s}}\n", violation)
+		}
+	}
+	fmt.Fprintf(&sb, "\nLast checked on %s. Run {{action-control enforce --repo %s}} for the full report. This issue is updated by each scan while the repository does not comply.\n",
+		digest.GeneratedAt.Format("2006-01-02 15:04 MST"), repo.Name)
+	return sb.String()
+}
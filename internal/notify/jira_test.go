@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeJira serves the Jira API with an issue already open for your-org/web
+type fakeJira struct {
+	cloud   bool // Whether /search/jql is available
+	created []map[string]interface{}
+	updated map[string]map[string]interface{}
+	auth    string
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.auth = r.Header.Get("Authorization")
+	switch {
+	case r.Method == http.MethodGet && (r.URL.Path == "/rest/api/2/search/jql" && f.cloud || r.URL.Path == "/rest/api/2/search" && !f.cloud):
+		jql := r.URL.Query().Get("jql")
+		if !strings.Contains(jql, `project = "SEC"`) {
+			http.Error(w, "unexpected project", http.StatusBadRequest)
+			return
+		}
+		issues := []map[string]string{}
+		if strings.Contains(jql, Fingerprint("your-org/web")) {
+			issues = append(issues, map[string]string{"key": "SEC-7"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"issues": issues})
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		var issue jiraIssue
+		json.NewDecoder(r.Body).Decode(&issue)
+		f.created = append(f.created, issue.Fields)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"key": "SEC-8"}`))
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/"):
+		var issue jiraIssue
+		json.NewDecoder(r.Body).Decode(&issue)
+		f.updated[strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")] = issue.Fields
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestJiraNotify(t *testing.T) {
+	for _, cloud := range []bool{true, false} {
+		fake := &fakeJira{cloud: cloud, updated: make(map[string]map[string]interface{})}
+		server := httptest.NewServer(fake)
+
+		jira, err := NewJira(JiraConfig{URL: server.URL + "/", Username: "bot@example.com", Token: "token", Project: "SEC", Labels: []string{"actions"}}, server.Client())
+		if err != nil {
+			t.Fatalf("NewJira() error: %v", err)
+		}
+		if err := jira.Notify(NewDigest("your-org", testReport(), time.Now())); err != nil {
+			t.Fatalf("Notify() error (cloud %v): %v", cloud, err)
+		}
+		server.Close()
+
+		if len(fake.created) != 1 || len(fake.updated) != 1 {
+			t.Fatalf("Expected one issue opened and one updated (cloud %v), got %d and %d", cloud, len(fake.created), len(fake.updated))
+		}
+		created := fake.created[0]
+		if created["summary"] != "your-org/api does not comply with the GitHub Actions policy" {
+			t.Errorf("Unexpected summary %v", created["summary"])
+		}
+		labels, _ := created["labels"].([]interface{})
+		if len(labels) != 2 || labels[0] != Fingerprint("your-org/api") || labels[1] != "actions" {
+			t.Errorf("Expected the fingerprint and configured labels, got %v", created["labels"])
+		}
+		if issueType := created["issuetype"].(map[string]interface{})["name"]; issueType != DefaultJiraIssueType {
+			t.Errorf("Expected the default issue type, got %v", issueType)
+		}
+		if description, _ := created["description"].(string); !strings.Contains(description, "* {{bad/action@v1}}") || !strings.Contains(description, "Owners: @your-org/platform") {
+			t.Errorf("Expected the violating action in the description, got %q", description)
+		}
+
+		updated, ok := fake.updated["SEC-7"]
+		if !ok || !strings.Contains(updated["description"].(string), "other/action@main") || updated["labels"] != nil {
+			t.Errorf("Expected SEC-7 to be updated without changing its labels, got %v", fake.updated)
+		}
+		if !strings.HasPrefix(fake.auth, "Basic ") {
+			t.Errorf("Expected basic authentication with a username, got %q", fake.auth)
+		}
+	}
+}
+
+func TestJiraErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer pat" {
+			t.Errorf("Expected a bearer token without a username, got %q", r.Header.Get("Authorization"))
+		}
+		http.Error(w, `{"errorMessages":["Field 'labels' cannot be set"]}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	jira, err := NewJira(JiraConfig{URL: server.URL, Token: "pat", Project: "SEC"}, server.Client())
+	if err != nil {
+		t.Fatalf("NewJira() error: %v", err)
+	}
+	err = jira.Notify(NewDigest("your-org", testReport(), time.Now()))
+	if err == nil || !strings.Contains(err.Error(), "your-org/api") || !strings.Contains(err.Error(), "cannot be set") {
+		t.Errorf("Expected errors naming the repository and Jira's message, got %v", err)
+	}
+
+	if _, err := NewJira(JiraConfig{URL: server.URL, Token: "pat"}, nil); err == nil {
+		t.Error("Expected a Jira notifier without a project to be rejected")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if Fingerprint("Your-Org/API") != Fingerprint("your-org/api") {
+		t.Error("Expected fingerprints to ignore case, as repository names do")
+	}
+	if Fingerprint("your-org/api") == Fingerprint("your-org/web") {
+		t.Error("Expected repositories to have distinct fingerprints")
+	}
+	if got := Fingerprint("your-org/api"); !strings.HasPrefix(got, "action-control-") || len(got) != len("action-control-")+16 {
+		t.Errorf("Unexpected fingerprint %q", got)
+	}
+}
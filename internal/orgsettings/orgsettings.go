@@ -0,0 +1,140 @@
+package orgsettings
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// githubOwners are the owners whose actions the github_owned_allowed setting allows
+var githubOwners = []string{"actions", "github"}
+
+// Drift compares an organization's allowed actions settings with an allow-mode policy
+type Drift struct {
+	Current        github.OrgActionsSettings `json:"current"`
+	Target         github.OrgActionsSettings `json:"target"`           // Settings enforcing the policy's global allow list
+	OnlyInSettings []string                  `json:"only_in_settings"` // Allowed by the settings but not by the policy
+	OnlyInPolicy   []string                  `json:"only_in_policy"`   // Allowed by the policy but not by the settings
+	Notes          []string                  `json:"notes,omitempty"`
+}
+
+// InSync reports whether the settings already match the target settings
+func (d Drift) InSync() bool {
+	return d.Current.AllowedActions == d.Target.AllowedActions &&
+		d.Current.GithubOwnedAllowed == d.Target.GithubOwnedAllowed &&
+		d.Current.VerifiedAllowed == d.Target.VerifiedAllowed &&
+		len(d.OnlyInSettings) == 0 && len(d.OnlyInPolicy) == 0
+}
+
+// Patterns translates the global allow list of an allow-mode policy into selected actions
+// patterns: allowed actions without a version allow every ref ("owner/repo@*") and allowed
+// owners allow all their repositories ("owner/*")
+func Patterns(config *policy.PolicyConfig) ([]string, error) {
+	if config.PolicyMode != "allow" {
+		return nil, fmt.Errorf("only allow-mode policies can be expressed as organization settings, policy mode is %q", config.PolicyMode)
+	}
+
+	var patterns []string
+	for _, action := range config.AllowedActions {
+		patterns = appendUnique(patterns, canonical(action))
+	}
+	for _, owner := range config.AllowedOwners {
+		patterns = appendUnique(patterns, strings.ToLower(owner)+"/*")
+	}
+	sort.Strings(patterns)
+
+	return patterns, nil
+}
+
+// Compare reports the drift between an organization's allowed actions settings and the
+// global allow list of an allow-mode policy. Custom rules and deny lists cannot be expressed
+// as organization settings and are noted instead.
+func Compare(config *policy.PolicyConfig, current github.OrgActionsSettings) (Drift, error) {
+	patterns, err := Patterns(config)
+	if err != nil {
+		return Drift{}, err
+	}
+
+	drift := Drift{
+		Current: current,
+		Target: github.OrgActionsSettings{
+			EnabledRepositories: current.EnabledRepositories,
+			AllowedActions:      github.AllowedActionsSelected,
+			PatternsAllowed:     patterns,
+		},
+		OnlyInSettings: []string{},
+		OnlyInPolicy:   []string{},
+	}
+
+	switch current.AllowedActions {
+	case github.AllowedActionsAll:
+		drift.OnlyInSettings = append(drift.OnlyInSettings, "*")
+		drift.Notes = append(drift.Notes, "GitHub allows all actions to run, so nothing outside the policy is blocked natively.")
+	case github.AllowedActionsLocalOnly:
+		drift.OnlyInPolicy = append(drift.OnlyInPolicy, patterns...)
+		drift.Notes = append(drift.Notes, "GitHub only allows local actions, so every action in the policy is blocked natively.")
+	default:
+		var settingsPatterns []string
+		for _, pattern := range current.PatternsAllowed {
+			settingsPatterns = appendUnique(settingsPatterns, canonical(pattern))
+		}
+		if current.GithubOwnedAllowed {
+			for _, owner := range githubOwners {
+				settingsPatterns = appendUnique(settingsPatterns, owner+"/*")
+			}
+			drift.Notes = append(drift.Notes, "GitHub allows all actions owned by GitHub (actions/*, github/*).")
+		}
+		if current.VerifiedAllowed {
+			drift.OnlyInSettings = append(drift.OnlyInSettings, "verified creators")
+			drift.Notes = append(drift.Notes, "GitHub allows all Marketplace actions from verified creators, which the policy cannot list.")
+		}
+
+		for _, pattern := range settingsPatterns {
+			if !slices.Contains(patterns, pattern) {
+				drift.OnlyInSettings = append(drift.OnlyInSettings, pattern)
+			}
+		}
+		for _, pattern := range patterns {
+			if !slices.Contains(settingsPatterns, pattern) && !(current.GithubOwnedAllowed && ownedByGitHub(pattern)) {
+				drift.OnlyInPolicy = append(drift.OnlyInPolicy, pattern)
+			}
+		}
+	}
+
+	if len(config.CustomRules) > 0 {
+		drift.Notes = append(drift.Notes, fmt.Sprintf("%d custom rules apply to individual repositories and are not reflected in organization settings.", len(config.CustomRules)))
+	}
+	if len(config.DeniedActions) > 0 || len(config.AlwaysDeny) > 0 {
+		drift.Notes = append(drift.Notes, "Denied actions are blocked by not being allowed; deny lists are not reflected in organization settings.")
+	}
+
+	return drift, nil
+}
+
+// canonical normalizes a policy entry or settings pattern for comparison: lowercase, with
+// entries naming a repository without a ref allowing every ref
+func canonical(pattern string) string {
+	pattern = strings.ToLower(pattern)
+	if !strings.Contains(pattern, "@") && !strings.HasSuffix(pattern, "*") {
+		pattern += "@*"
+	}
+	return pattern
+}
+
+// ownedByGitHub reports whether a pattern only selects actions owned by GitHub
+func ownedByGitHub(pattern string) bool {
+	owner, _, _ := strings.Cut(pattern, "/")
+	return slices.Contains(githubOwners, owner)
+}
+
+// appendUnique appends item to slice unless it is already present
+func appendUnique(slice []string, item string) []string {
+	if slices.Contains(slice, item) {
+		return slice
+	}
+	return append(slice, item)
+}
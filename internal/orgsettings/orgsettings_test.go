@@ -0,0 +1,94 @@
+package orgsettings
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+var allowPolicy = &policy.PolicyConfig{
+	PolicyMode:     "allow",
+	AllowedActions: []string{"actions/checkout", "actions/setup-go@v5", "Docker/build-push-action"},
+	AllowedOwners:  []string{"your-org"},
+}
+
+func TestPatterns(t *testing.T) {
+	patterns, err := Patterns(allowPolicy)
+	if err != nil {
+		t.Fatalf("Patterns() error = %v", err)
+	}
+	expected := []string{"actions/checkout@*", "actions/setup-go@v5", "docker/build-push-action@*", "your-org/*"}
+	if !reflect.DeepEqual(patterns, expected) {
+		t.Errorf("Patterns() = %v, want %v", patterns, expected)
+	}
+
+	if _, err := Patterns(&policy.PolicyConfig{PolicyMode: "deny"}); err == nil {
+		t.Error("Expected error for a deny-mode policy, got nil")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name           string
+		current        github.OrgActionsSettings
+		onlyInSettings []string
+		onlyInPolicy   []string
+		inSync         bool
+	}{
+		{
+			name: "in sync",
+			current: github.OrgActionsSettings{
+				AllowedActions:  github.AllowedActionsSelected,
+				PatternsAllowed: []string{"your-org/*", "actions/checkout", "actions/setup-go@v5", "docker/build-push-action@*"},
+			},
+			onlyInSettings: []string{},
+			onlyInPolicy:   []string{},
+			inSync:         true,
+		},
+		{
+			name: "drift both ways",
+			current: github.OrgActionsSettings{
+				AllowedActions:     github.AllowedActionsSelected,
+				GithubOwnedAllowed: true,
+				PatternsAllowed:    []string{"your-org/*", "hashicorp/*"},
+			},
+			onlyInSettings: []string{"hashicorp/*", "actions/*", "github/*"},
+			onlyInPolicy:   []string{"docker/build-push-action@*"},
+		},
+		{
+			name:           "all actions allowed",
+			current:        github.OrgActionsSettings{AllowedActions: github.AllowedActionsAll},
+			onlyInSettings: []string{"*"},
+			onlyInPolicy:   []string{},
+		},
+		{
+			name:           "local actions only",
+			current:        github.OrgActionsSettings{AllowedActions: github.AllowedActionsLocalOnly},
+			onlyInSettings: []string{},
+			onlyInPolicy:   []string{"actions/checkout@*", "actions/setup-go@v5", "docker/build-push-action@*", "your-org/*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift, err := Compare(allowPolicy, tt.current)
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if !reflect.DeepEqual(drift.OnlyInSettings, tt.onlyInSettings) {
+				t.Errorf("OnlyInSettings = %v, want %v", drift.OnlyInSettings, tt.onlyInSettings)
+			}
+			if !reflect.DeepEqual(drift.OnlyInPolicy, tt.onlyInPolicy) {
+				t.Errorf("OnlyInPolicy = %v, want %v", drift.OnlyInPolicy, tt.onlyInPolicy)
+			}
+			if drift.InSync() != tt.inSync {
+				t.Errorf("InSync() = %v, want %v", drift.InSync(), tt.inSync)
+			}
+			if drift.Target.AllowedActions != github.AllowedActionsSelected || len(drift.Target.PatternsAllowed) != 4 {
+				t.Errorf("Unexpected target settings: %+v", drift.Target)
+			}
+		})
+	}
+}
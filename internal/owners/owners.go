@@ -0,0 +1,102 @@
+package owners
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping associates repositories with the owners responsible for them, and owners with the
+// email addresses notifications reach them at
+type Mapping struct {
+	// Repositories lists owners by repository, as owner/repo or a glob pattern such as
+	// your-org/payments-*. Owners are GitHub handles, like @your-org/payments, or email addresses.
+	Repositories map[string][]string `yaml:"repositories"`
+	// Contacts maps owner handles to email addresses
+	Contacts map[string]string `yaml:"contacts,omitempty"`
+}
+
+// Load reads an owners mapping file
+func Load(filePath string) (*Mapping, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read owners file: %w", err)
+	}
+
+	var mapping Mapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse owners file %s: %w", filePath, err)
+	}
+	for pattern := range mapping.Repositories {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return nil, fmt.Errorf("invalid repository pattern %q in owners file %s: %w", pattern, filePath, err)
+		}
+	}
+	return &mapping, nil
+}
+
+// OwnersOf returns the owners of a repository: those listed for it by name, or else those of
+// the most specific matching pattern, the longest one. Repositories are matched
+// case-insensitively. A nil mapping has no owners.
+func (m *Mapping) OwnersOf(repo string) []string {
+	if m == nil {
+		return nil
+	}
+
+	var best string
+	matched := false
+	for pattern := range m.Repositories {
+		if strings.EqualFold(pattern, repo) {
+			return m.Repositories[pattern]
+		}
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(repo)); ok && (!matched || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best)) {
+			best, matched = pattern, true
+		}
+	}
+	if !matched {
+		return nil
+	}
+	return m.Repositories[best]
+}
+
+// Emails returns the email addresses of owners, in order and without duplicates. Owners that
+// are email addresses are used as they are, and handles are looked up in the contacts, case-
+// insensitively as GitHub compares them. Handles without a contact are left out.
+func (m *Mapping) Emails(owners []string) []string {
+	var emails []string
+	seen := make(map[string]bool)
+	for _, owner := range owners {
+		email, ok := m.email(owner)
+		if !ok || seen[strings.ToLower(email)] {
+			continue
+		}
+		seen[strings.ToLower(email)] = true
+		emails = append(emails, email)
+	}
+	return emails
+}
+
+// email returns the email address of an owner
+func (m *Mapping) email(owner string) (string, bool) {
+	if IsEmail(owner) {
+		return owner, true
+	}
+	if m == nil {
+		return "", false
+	}
+	for handle, email := range m.Contacts {
+		if strings.EqualFold(strings.TrimPrefix(handle, "@"), strings.TrimPrefix(owner, "@")) {
+			return email, true
+		}
+	}
+	return "", false
+}
+
+// IsEmail reports whether an owner is an email address rather than a GitHub handle
+func IsEmail(owner string) bool {
+	at := strings.Index(owner, "@")
+	return at > 0 && at < len(owner)-1
+}
@@ -0,0 +1,84 @@
+package owners
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOwnersOf(t *testing.T) {
+	mapping := &Mapping{Repositories: map[string][]string{
+		"your-org/*":               {"@your-org/platform"},
+		"your-org/payments-*":      {"@your-org/payments"},
+		"Your-Org/payments-legacy": {"legacy@example.com"},
+	}}
+
+	tests := []struct {
+		repo     string
+		expected []string
+	}{
+		{"your-org/api", []string{"@your-org/platform"}},
+		{"your-org/payments-api", []string{"@your-org/payments"}},
+		{"your-org/payments-legacy", []string{"legacy@example.com"}},
+		{"YOUR-ORG/Payments-API", []string{"@your-org/payments"}},
+		{"other-org/api", nil},
+	}
+	for _, tt := range tests {
+		if got := mapping.OwnersOf(tt.repo); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("OwnersOf(%q) = %v, expected %v", tt.repo, got, tt.expected)
+		}
+	}
+
+	var none *Mapping
+	if got := none.OwnersOf("your-org/api"); got != nil {
+		t.Errorf("Expected a nil mapping to have no owners, got %v", got)
+	}
+}
+
+func TestEmails(t *testing.T) {
+	mapping := &Mapping{Contacts: map[string]string{
+		"@your-org/payments": "payments@example.com",
+		"alice":              "alice@example.com",
+	}}
+
+	got := mapping.Emails([]string{"@Your-Org/Payments", "@alice", "payments@example.com", "@bob", "security@example.com"})
+	expected := []string{"payments@example.com", "alice@example.com", "security@example.com"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Emails() = %v, expected %v", got, expected)
+	}
+
+	var none *Mapping
+	if got := none.Emails([]string{"@alice", "alice@example.com"}); !reflect.DeepEqual(got, []string{"alice@example.com"}) {
+		t.Errorf("Expected a nil mapping to pass email addresses through, got %v", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owners.yaml")
+	content := `repositories:
+  your-org/api: ["@your-org/platform"]
+contacts:
+  "@your-org/platform": platform@example.com
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got := mapping.Emails(mapping.OwnersOf("your-org/api")); !reflect.DeepEqual(got, []string{"platform@example.com"}) {
+		t.Errorf("Expected the platform contact, got %v", got)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("repositories:\n  \"your-org/[\": [\"@a\"]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(invalid); err == nil {
+		t.Error("Expected an invalid repository pattern to be rejected")
+	}
+}
@@ -0,0 +1,158 @@
+package pinning
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Kind describes how an action reference is pinned
+type Kind string
+
+const (
+	SHA      Kind = "sha"      // Full commit SHA or image digest; immutable
+	Tag      Kind = "tag"      // Version tag; mutable but conventionally stable
+	Branch   Kind = "branch"   // Branch name; changes with every push
+	Unpinned Kind = "unpinned" // No reference at all
+)
+
+var (
+	commitSHA  = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	versionTag = regexp.MustCompile(`^v?\d+(\.\d+)*([-+][0-9A-Za-z.-]+)?$`)
+)
+
+// Classify determines how an action reference is pinned. Local actions
+// (./path) are versioned with the repository itself and report ok=false.
+//
+// Refs that look like versions (v4, v1.2.3, 2.0) are treated as tags and
+// everything else as a branch, since the two cannot be told apart from the
+// reference alone.
+func Classify(uses string) (Kind, bool) {
+	if strings.HasPrefix(uses, "./") {
+		return "", false
+	}
+
+	if image, ok := strings.CutPrefix(uses, "docker://"); ok {
+		if strings.Contains(image, "@sha256:") {
+			return SHA, true
+		}
+		// A tag follows the last colon, unless that colon belongs to a registry port
+		if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+			return Tag, true
+		}
+		return Unpinned, true
+	}
+
+	i := strings.LastIndex(uses, "@")
+	if i < 0 || i == len(uses)-1 {
+		return Unpinned, true
+	}
+
+	ref := uses[i+1:]
+	switch {
+	case commitSHA.MatchString(ref):
+		return SHA, true
+	case versionTag.MatchString(ref):
+		return Tag, true
+	default:
+		return Branch, true
+	}
+}
+
+// Counts tallies action references by pinning kind
+type Counts struct {
+	SHA      int `json:"sha"`
+	Tag      int `json:"tag"`
+	Branch   int `json:"branch"`
+	Unpinned int `json:"unpinned"`
+}
+
+// Total returns the number of classified references
+func (c Counts) Total() int {
+	return c.SHA + c.Tag + c.Branch + c.Unpinned
+}
+
+// Percent returns the share of references of the given kind, from 0 to 100
+func (c Counts) Percent(kind Kind) float64 {
+	total := c.Total()
+	if total == 0 {
+		return 0
+	}
+
+	var n int
+	switch kind {
+	case SHA:
+		n = c.SHA
+	case Tag:
+		n = c.Tag
+	case Branch:
+		n = c.Branch
+	case Unpinned:
+		n = c.Unpinned
+	}
+
+	return float64(n) * 100 / float64(total)
+}
+
+func (c *Counts) add(kind Kind) {
+	switch kind {
+	case SHA:
+		c.SHA++
+	case Tag:
+		c.Tag++
+	case Branch:
+		c.Branch++
+	case Unpinned:
+		c.Unpinned++
+	}
+}
+
+// Reference is a single classified action reference
+type Reference struct {
+	Uses     string `json:"uses"`
+	Workflow string `json:"workflow,omitempty"`
+	Kind     Kind   `json:"kind"`
+}
+
+// RepositoryPinning summarizes the pinning of one repository's action references
+type RepositoryPinning struct {
+	Counts
+	References []Reference `json:"references"`
+}
+
+// Report summarizes action pinning per repository and across the organization
+type Report struct {
+	Repositories map[string]RepositoryPinning `json:"repositories"`
+	Summary      Counts                       `json:"summary"`
+}
+
+// Analyze classifies every action reference in the scanned repositories
+func Analyze(actionsMap map[string][]github.Action) *Report {
+	report := &Report{
+		Repositories: make(map[string]RepositoryPinning),
+	}
+
+	for repo, actions := range actionsMap {
+		var result RepositoryPinning
+		for _, action := range actions {
+			kind, ok := Classify(action.Uses)
+			if !ok {
+				continue
+			}
+			result.add(kind)
+			report.Summary.add(kind)
+			result.References = append(result.References, Reference{
+				Uses:     action.Uses,
+				Workflow: action.Workflow,
+				Kind:     kind,
+			})
+		}
+
+		if result.Total() > 0 {
+			report.Repositories[repo] = result
+		}
+	}
+
+	return report
+}
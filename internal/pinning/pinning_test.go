@@ -0,0 +1,75 @@
+package pinning
+
+import (
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		uses string
+		kind Kind
+		ok   bool
+	}{
+		{"actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683", SHA, true},
+		{"actions/checkout@v4", Tag, true},
+		{"actions/setup-go@v5.0.1", Tag, true},
+		{"some/action@1.2.3-beta.1", Tag, true},
+		{"some/action@main", Branch, true},
+		{"some/action@release/v2", Branch, true},
+		{"org/repo/.github/workflows/build.yml@11bd71901bbe5b1630ceea73d27597364c9af683", SHA, true},
+		{"some/action", Unpinned, true},
+		{"some/action@", Unpinned, true},
+		{"docker://alpine@sha256:abc123", SHA, true},
+		{"docker://alpine:3.19", Tag, true},
+		{"docker://alpine", Unpinned, true},
+		{"docker://registry:5000/alpine", Unpinned, true},
+		{"./.github/actions/local", "", false},
+	}
+
+	for _, tt := range tests {
+		kind, ok := Classify(tt.uses)
+		if kind != tt.kind || ok != tt.ok {
+			t.Errorf("Classify(%q) = (%q, %v), want (%q, %v)", tt.uses, kind, ok, tt.kind, tt.ok)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	actionsMap := map[string][]github.Action{
+		"org/repo1": {
+			{Uses: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683"},
+			{Uses: "actions/setup-go@v5"},
+			{Uses: "some/action@main"},
+			{Uses: "./local"},
+		},
+		"org/repo2": {
+			{Uses: "actions/checkout@v4"},
+		},
+		"org/repo3": {
+			{Uses: "./local"},
+		},
+	}
+
+	report := Analyze(actionsMap)
+
+	if len(report.Repositories) != 2 {
+		t.Fatalf("Expected 2 repositories with references, got %d", len(report.Repositories))
+	}
+
+	repo1 := report.Repositories["org/repo1"]
+	if repo1.SHA != 1 || repo1.Tag != 1 || repo1.Branch != 1 || repo1.Total() != 3 {
+		t.Errorf("Unexpected counts for org/repo1: %+v", repo1.Counts)
+	}
+
+	if report.Summary.Tag != 2 || report.Summary.Total() != 4 {
+		t.Errorf("Unexpected summary: %+v", report.Summary)
+	}
+	if got := report.Summary.Percent(Tag); got != 50 {
+		t.Errorf("Expected 50%% tag-pinned, got %v", got)
+	}
+	if got := (Counts{}).Percent(SHA); got != 0 {
+		t.Errorf("Expected 0%% for empty counts, got %v", got)
+	}
+}
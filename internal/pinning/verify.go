@@ -0,0 +1,129 @@
+package pinning
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// TagResolver resolves the commit a tag currently points to
+type TagResolver interface {
+	ResolveTag(ctx context.Context, owner, repo, tag string) (string, error)
+}
+
+// Drift describes a SHA pin whose version comment no longer matches the tagged commit
+type Drift struct {
+	Uses      string `json:"uses"`
+	Workflow  string `json:"workflow,omitempty"`
+	Tag       string `json:"tag"`
+	PinnedSHA string `json:"pinned_sha"`
+	TagSHA    string `json:"tag_sha,omitempty"` // Empty when the tag no longer exists
+	Reason    string `json:"reason"`
+}
+
+// versionComment extracts a version from a pin comment such as "v4.1.1" or "tag=v4.1.1"
+var versionComment = regexp.MustCompile(`^(?:tag=|pin@)?(v?\d+(?:\.\d+)*(?:[-+][0-9A-Za-z.-]+)?)(?:\s|$)`)
+
+// VersionFromComment returns the version recorded in a pin comment, if any
+func VersionFromComment(comment string) (string, bool) {
+	match := versionComment.FindStringSubmatch(strings.TrimSpace(comment))
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// VerifyPins checks SHA-pinned actions carrying a version comment against the tag they claim,
+// returning, per repository, the pins whose tag now points elsewhere or no longer exists.
+// Each action and tag is resolved once across all repositories; tags that cannot be resolved
+// for other reasons are skipped.
+func VerifyPins(ctx context.Context, resolver TagResolver, actionsMap map[string][]github.Action) map[string][]Drift {
+	verifier := NewVerifier(resolver)
+
+	result := make(map[string][]Drift)
+	for repo, actions := range actionsMap {
+		if drift := verifier.Verify(ctx, actions); len(drift) > 0 {
+			result[repo] = drift
+		}
+	}
+
+	return result
+}
+
+// tagKey identifies a tag of an action repository
+type tagKey struct{ repo, tag string }
+
+// Verifier checks SHA pins one repository at a time, resolving each action and tag once
+// across all repositories it verifies
+type Verifier struct {
+	resolver TagResolver
+	resolved map[tagKey]string
+	missing  map[tagKey]bool
+}
+
+// NewVerifier creates a Verifier resolving tags with resolver
+func NewVerifier(resolver TagResolver) *Verifier {
+	return &Verifier{
+		resolver: resolver,
+		resolved: make(map[tagKey]string),
+		missing:  make(map[tagKey]bool),
+	}
+}
+
+// Verify returns the pins among a repository's actions whose tag now points elsewhere or no longer exists
+func (v *Verifier) Verify(ctx context.Context, actions []github.Action) []Drift {
+	var result []Drift
+	for _, action := range actions {
+		if kind, ok := Classify(action.Uses); !ok || kind != SHA || strings.HasPrefix(action.Uses, "docker://") {
+			continue
+		}
+		tag, ok := VersionFromComment(action.Comment)
+		if !ok {
+			continue
+		}
+
+		at := strings.LastIndex(action.Uses, "@")
+		parts := strings.SplitN(action.Uses[:at], "/", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		pinned := action.Uses[at+1:]
+
+		k := tagKey{parts[0] + "/" + parts[1], tag}
+		sha, seen := v.resolved[k]
+		if !seen && !v.missing[k] {
+			var err error
+			sha, err = v.resolver.ResolveTag(ctx, parts[0], parts[1], tag)
+			switch {
+			case errors.Is(err, github.ErrTagNotFound):
+				v.missing[k] = true
+			case err != nil:
+				continue
+			default:
+				v.resolved[k] = sha
+			}
+		}
+
+		drift := Drift{
+			Uses:      action.Uses,
+			Workflow:  action.Workflow,
+			Tag:       tag,
+			PinnedSHA: pinned,
+			TagSHA:    sha,
+		}
+		switch {
+		case v.missing[k]:
+			drift.Reason = "tag no longer exists"
+		case sha != pinned:
+			drift.Reason = "tag points to a different commit"
+		default:
+			continue
+		}
+		result = append(result, drift)
+	}
+
+	return result
+}
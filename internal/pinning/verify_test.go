@@ -0,0 +1,82 @@
+package pinning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+type fakeResolver struct {
+	tags  map[string]string
+	calls int
+}
+
+func (f *fakeResolver) ResolveTag(ctx context.Context, owner, repo, tag string) (string, error) {
+	f.calls++
+	if tag == "v9" {
+		return "", errors.New("rate limited")
+	}
+	sha, ok := f.tags[owner+"/"+repo+"@"+tag]
+	if !ok {
+		return "", github.ErrTagNotFound
+	}
+	return sha, nil
+}
+
+func TestVersionFromComment(t *testing.T) {
+	tests := map[string]string{
+		"v4.1.1":             "v4.1.1",
+		"v4":                 "v4",
+		"tag=v1.2.3":         "v1.2.3",
+		"v2.0.0 (latest)":    "v2.0.0",
+		"pinned by renovate": "",
+		"":                   "",
+	}
+	for comment, want := range tests {
+		got, ok := VersionFromComment(comment)
+		if got != want || ok != (want != "") {
+			t.Errorf("VersionFromComment(%q) = (%q, %v), want %q", comment, got, ok, want)
+		}
+	}
+}
+
+func TestVerifyPins(t *testing.T) {
+	good := "1111111111111111111111111111111111111111"
+	moved := "2222222222222222222222222222222222222222"
+	resolver := &fakeResolver{tags: map[string]string{
+		"actions/checkout@v4.1.1": good,
+		"actions/cache@v4.0.0":    good,
+	}}
+
+	actionsMap := map[string][]github.Action{
+		"org/repo1": {
+			{Uses: "actions/checkout@" + good, Comment: "v4.1.1"},
+			{Uses: "actions/cache@" + moved, Comment: "v4.0.0", Workflow: ".github/workflows/ci.yml"},
+			{Uses: "actions/setup-go@" + good, Comment: "v5.0.0"},
+			{Uses: "actions/upload-artifact@" + good, Comment: "v9"},
+			{Uses: "actions/setup-node@v4", Comment: "v4"},
+			{Uses: "actions/stale@" + good},
+		},
+		"org/repo2": {
+			{Uses: "actions/checkout@" + good, Comment: "v4.1.1"},
+		},
+	}
+
+	drifts := VerifyPins(context.Background(), resolver, actionsMap)
+
+	if len(drifts) != 1 || len(drifts["org/repo1"]) != 2 {
+		t.Fatalf("Expected 2 drifts in org/repo1 only, got %+v", drifts)
+	}
+	repoDrifts := drifts["org/repo1"]
+	if repoDrifts[0].Uses != "actions/cache@"+moved || repoDrifts[0].TagSHA != good || repoDrifts[0].Reason != "tag points to a different commit" {
+		t.Errorf("Unexpected drift: %+v", repoDrifts[0])
+	}
+	if repoDrifts[1].Tag != "v5.0.0" || repoDrifts[1].Reason != "tag no longer exists" {
+		t.Errorf("Unexpected drift: %+v", repoDrifts[1])
+	}
+	if resolver.calls != 4 {
+		t.Errorf("Expected each tag to be resolved once (4 calls), got %d", resolver.calls)
+	}
+}
@@ -0,0 +1,37 @@
+package policy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Base64Prefix marks policy content that is base64-encoded, for policies whose special
+// characters don't survive being passed through a shell, an environment variable or a workflow
+const Base64Prefix = "base64:"
+
+// DecodePolicyContent returns the YAML of policy content given inline, decoding it when it
+// carries the base64 prefix
+func DecodePolicyContent(content string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(content), Base64Prefix)
+	if !ok {
+		return []byte(content), nil
+	}
+
+	// Wrapped output of base64 tools spans several lines
+	encoded = strings.Join(strings.Fields(encoded), "")
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 policy content: %w", err)
+	}
+	return data, nil
+}
+
+// ParsePolicyContent parses policy content given inline, plain or base64-encoded
+func ParsePolicyContent(content string) (*PolicyConfig, error) {
+	data, err := DecodePolicyContent(content)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicyConfig(data)
+}
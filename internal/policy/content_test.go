@@ -0,0 +1,37 @@
+package policy
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestParsePolicyContent(t *testing.T) {
+	content := "policy_mode: allow\nallowed_actions:\n  - actions/checkout@v4\n  - \"owner/action@*\"\n"
+
+	plain, err := ParsePolicyContent(content)
+	if err != nil {
+		t.Fatalf("Failed to parse plain policy content: %v", err)
+	}
+	if len(plain.AllowedActions) != 2 {
+		t.Errorf("Expected 2 allowed actions, got %v", plain.AllowedActions)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+	// Wrap the encoded content the way base64 tools do
+	wrapped := Base64Prefix + encoded[:20] + "\n" + encoded[20:] + "\n"
+	decoded, err := ParsePolicyContent(wrapped)
+	if err != nil {
+		t.Fatalf("Failed to parse base64 policy content: %v", err)
+	}
+	if decoded.PolicyMode != "allow" || len(decoded.AllowedActions) != 2 || decoded.AllowedActions[1] != "owner/action@*" {
+		t.Errorf("Expected the decoded policy to match the plain one, got %+v", decoded)
+	}
+}
+
+func TestDecodePolicyContentInvalid(t *testing.T) {
+	_, err := DecodePolicyContent("base64:not base64!")
+	if err == nil || !strings.Contains(err.Error(), "base64") {
+		t.Errorf("Expected a base64 decoding error, got %v", err)
+	}
+}
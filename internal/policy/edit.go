@@ -0,0 +1,120 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Edit is a change to an action list of a policy file, in the global policy or a custom rule
+type Edit struct {
+	Rule   string // Key of the custom rule whose list is edited; the global policy when empty
+	List   string // allowed_actions or denied_actions
+	Action string // Action reference without version
+	Remove bool   // Remove the action and its versioned entries instead of adding it
+}
+
+// EditPolicyFile applies edits to a policy file in place. Unlike SavePolicyConfig, which
+// rewrites the whole file, only the edited lists change: comments, ordering and formatting
+// are preserved.
+func EditPolicyFile(path string, edits []Edit) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read policy config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse policy config: %w", err)
+	}
+	// An empty file has no document node yet
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("policy config is not a YAML mapping")
+	}
+
+	for _, edit := range edits {
+		mapping := root
+		if edit.Rule != "" {
+			mapping = MappingValue(MappingValue(root, "custom_rules", yaml.MappingNode), edit.Rule, yaml.MappingNode)
+		}
+		list := MappingValue(mapping, edit.List, yaml.SequenceNode)
+		if edit.Remove {
+			removeAction(list, edit.Action)
+		} else {
+			AppendMissing(list, []string{edit.Action})
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to marshal policy config: %w", err)
+	}
+	encoder.Close()
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write policy config: %w", err)
+	}
+	return nil
+}
+
+// MappingValue returns the value node for key in a mapping node, creating it with the given kind if missing
+func MappingValue(mapping *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			// A key with an empty value (e.g. "allowed_actions:") is parsed as null
+			if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+				value.Kind = kind
+				value.Tag = ""
+				value.Value = ""
+			}
+			return value
+		}
+	}
+
+	value := &yaml.Node{Kind: kind}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		value,
+	)
+	return value
+}
+
+// AppendMissing appends the actions not already present in a sequence node and returns them
+func AppendMissing(sequence *yaml.Node, actions []string) []string {
+	existing := make(map[string]bool)
+	for _, item := range sequence.Content {
+		existing[item.Value] = true
+	}
+
+	var added []string
+	for _, action := range actions {
+		if existing[action] {
+			continue
+		}
+		sequence.Content = append(sequence.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: action})
+		existing[action] = true
+		added = append(added, action)
+	}
+	return added
+}
+
+// removeAction removes the entries of an action, with or without a version, from a sequence node
+func removeAction(sequence *yaml.Node, action string) {
+	remaining := sequence.Content[:0]
+	for _, item := range sequence.Content {
+		if item.Value != action && !strings.HasPrefix(item.Value, action+"@") {
+			remaining = append(remaining, item)
+		}
+	}
+	sequence.Content = remaining
+}
@@ -0,0 +1,57 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEditPolicyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	original := `# Organization action policy
+policy_mode: allow
+allowed_actions:
+  - actions/checkout # pinned by the platform team
+denied_actions:
+  - other/tool@main
+custom_rules:
+  # Release pipelines
+  org/releases:
+    allowed_actions:
+      - actions/checkout
+`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+
+	edits := []Edit{
+		{List: "allowed_actions", Action: "third/party"},
+		{List: "allowed_actions", Action: "actions/checkout"},
+		{List: "denied_actions", Action: "other/tool", Remove: true},
+		{Rule: "org/releases", List: "allowed_actions", Action: "goreleaser/goreleaser-action"},
+	}
+	if err := EditPolicyFile(path, edits); err != nil {
+		t.Fatalf("EditPolicyFile returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read policy: %v", err)
+	}
+	expected := `# Organization action policy
+policy_mode: allow
+allowed_actions:
+  - actions/checkout # pinned by the platform team
+  - third/party
+denied_actions: []
+custom_rules:
+  # Release pipelines
+  org/releases:
+    allowed_actions:
+      - actions/checkout
+      - goreleaser/goreleaser-action
+`
+	if string(data) != expected {
+		t.Errorf("Expected only the edited lists to change, got:\n%s", data)
+	}
+}
@@ -0,0 +1,132 @@
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Policy layers that can contribute to a repository's effective policy
+const (
+	LayerGlobal       = "global"
+	LayerRepoOverride = "repo_override"
+	LayerCustomRule   = "custom_rule"
+	LayerExcluded     = "excluded"
+)
+
+// EffectivePolicy is the rule set that applies to a single repository after all layers are resolved
+type EffectivePolicy struct {
+	Layers         []string `json:"layers"`
+	Excluded       bool     `json:"excluded"`
+	CustomRule     string   `json:"custom_rule,omitempty"` // Key of the custom rule that applied
+	PolicyMode     string   `json:"policy_mode"`
+	AllowedActions []string `json:"allowed_actions,omitempty"`
+	DeniedActions  []string `json:"denied_actions,omitempty"`
+	AllowedOwners  []string `json:"allowed_owners,omitempty"`
+	AlwaysDeny     []string `json:"always_deny,omitempty"`
+	Digest         string   `json:"digest"`
+}
+
+// ResolveEffectivePolicy determines the rules applied to a repository and the layers they came from
+func ResolveEffectivePolicy(policy *PolicyConfig, repoName string) EffectivePolicy {
+	return resolveEffectivePolicy(policy, repoName, "")
+}
+
+// resolveEffectivePolicy determines the rules applied to a repository, or to one of its
+// workflow files when workflowPath is set
+func resolveEffectivePolicy(policy *PolicyConfig, repoName, workflowPath string) EffectivePolicy {
+	effective := EffectivePolicy{
+		Layers:     []string{LayerGlobal},
+		AlwaysDeny: policy.AlwaysDeny,
+	}
+
+	// Check if repository is excluded from policy
+	for _, excludedRepo := range policy.ExcludedRepos {
+		if excludedRepo == repoName {
+			effective.Excluded = true
+			effective.Layers = append(effective.Layers, LayerExcluded)
+			effective.Digest = effective.digest()
+			return effective
+		}
+	}
+
+	// Determine which policy to apply (global or custom)
+	var allowedActions, deniedActions []string
+	var policyMode string
+
+	ruleKey, customPolicy, exists := pathRuleFor(policy, repoName, workflowPath)
+	if !exists {
+		ruleKey, customPolicy, exists = customRuleFor(policy, repoName)
+	}
+	if exists {
+		effective.Layers = append(effective.Layers, LayerCustomRule)
+		effective.CustomRule = ruleKey
+
+		// Use custom policy for this repository
+		allowedActions = customPolicy.AllowedActions
+		deniedActions = customPolicy.DeniedActions
+		policyMode = customPolicy.PolicyMode
+
+		// If custom policy mode is not specified, inherit from global
+		if policyMode == "" {
+			policyMode = policy.PolicyMode
+		}
+
+		// If custom policy doesn't specify actions for its mode, inherit from global
+		if (policyMode == "allow" || policyMode == "mixed") && len(allowedActions) == 0 {
+			allowedActions = policy.AllowedActions
+		}
+		if (policyMode == "deny" || policyMode == "mixed") && len(deniedActions) == 0 {
+			deniedActions = policy.DeniedActions
+		}
+	} else {
+		// Use global policy
+		allowedActions = policy.AllowedActions
+		deniedActions = policy.DeniedActions
+		policyMode = policy.PolicyMode
+	}
+
+	// Default to allow mode if not specified
+	if policyMode == "" {
+		if len(allowedActions) > 0 {
+			policyMode = "allow"
+		} else if len(deniedActions) > 0 {
+			policyMode = "deny"
+		} else {
+			policyMode = "allow" // Default fallback
+		}
+	}
+
+	effective.PolicyMode = policyMode
+	effective.AllowedActions = allowedActions
+	effective.DeniedActions = deniedActions
+	effective.AllowedOwners = policy.AllowedOwners
+	effective.Digest = effective.digest()
+
+	return effective
+}
+
+// digest returns a stable SHA-256 digest of the effective rule set, independent of list order
+func (e EffectivePolicy) digest() string {
+	allowed := append([]string(nil), e.AllowedActions...)
+	denied := append([]string(nil), e.DeniedActions...)
+	owners := append([]string(nil), e.AllowedOwners...)
+	alwaysDeny := append([]string(nil), e.AlwaysDeny...)
+	sort.Strings(allowed)
+	sort.Strings(owners)
+	sort.Strings(denied)
+	sort.Strings(alwaysDeny)
+
+	data, _ := json.Marshal(struct {
+		Excluded   bool     `json:"excluded"`
+		Mode       string   `json:"mode"`
+		Allowed    []string `json:"allowed"`
+		Denied     []string `json:"denied"`
+		AlwaysDeny []string `json:"always_deny"`
+		Owners     []string `json:"owners,omitempty"`
+	}{e.Excluded, e.PolicyMode, allowed, denied, alwaysDeny, owners})
+
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
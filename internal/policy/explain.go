@@ -0,0 +1,103 @@
+package policy
+
+import "fmt"
+
+// Explanation describes how a repository's effective policy decides on a single action
+type Explanation struct {
+	Action     string   `json:"action"`
+	Repository string   `json:"repository"`
+	Allowed    bool     `json:"allowed"`
+	Rule       string   `json:"rule"`                    // Rule that decided the outcome
+	Entry      string   `json:"matched_entry,omitempty"` // List entry that matched, empty when nothing matched
+	Source     string   `json:"source"`                  // Policy level the rule came from
+	Mode       string   `json:"mode,omitempty"`
+	Layers     []string `json:"layers"`
+	CustomRule string   `json:"custom_rule,omitempty"`
+	Excluded   bool     `json:"excluded"`
+	Reason     string   `json:"reason"`
+}
+
+// Explain describes which rule allows or denies an action in a repository under a policy
+func Explain(config *PolicyConfig, repoName, action string) Explanation {
+	return ExplainEffective(ResolveEffectivePolicy(config, repoName), repoName, action)
+}
+
+// ExplainEffective describes which rule of a repository's effective policy allows or denies
+// an action. It follows the same order as CheckActionCompliance: always_deny first, then the
+// repository exclusion, then the allow and deny lists of the policy mode.
+func ExplainEffective(effective EffectivePolicy, repoName, action string) Explanation {
+	explanation := Explanation{
+		Action:     action,
+		Repository: repoName,
+		Mode:       effective.PolicyMode,
+		Layers:     effective.Layers,
+		CustomRule: effective.CustomRule,
+		Excluded:   effective.Excluded,
+	}
+
+	normalized := normalizeAction(action)
+	owner := ActionOwner(action)
+	decide := func(allowed bool, rule, entry, reason string) Explanation {
+		explanation.Allowed = allowed
+		explanation.Rule = rule
+		explanation.Entry = entry
+		explanation.Source = RuleSource(effective, rule)
+		explanation.Reason = reason
+		return explanation
+	}
+
+	if entry, ok := matchingEntry(effective.AlwaysDeny, action, normalized); ok {
+		return decide(false, RuleAlwaysDeny, entry,
+			fmt.Sprintf("matches always_deny entry %q, which applies to every repository", entry))
+	}
+	if effective.Excluded {
+		excluded := decide(true, RuleExcludedRepos, repoName,
+			"repository is listed in excluded_repos, so only always_deny applies")
+		excluded.Source = SourceGlobal // Exclusions are only set globally
+		return excluded
+	}
+
+	allowedEntry, explicitlyAllowed := matchingEntry(effective.AllowedActions, action, normalized)
+	ownerAllowed := owner != "" && contains(effective.AllowedOwners, owner)
+	from := describeSource(effective)
+
+	switch effective.PolicyMode {
+	case "deny", "mixed":
+		if entry, ok := matchingEntry(effective.DeniedActions, action, normalized); ok {
+			return decide(false, RuleDeniedActions, entry,
+				fmt.Sprintf("matches denied_actions entry %q of the %s", entry, from))
+		}
+		if effective.PolicyMode == "deny" {
+			if len(effective.AllowedOwners) > 0 && owner != "" && !ownerAllowed && !explicitlyAllowed {
+				return decide(false, RuleAllowedOwners, "",
+					fmt.Sprintf("owner %q is not in allowed_owners of the %s and the action has no allowed_actions entry", owner, from))
+			}
+			return decide(true, RuleDeniedActions, "",
+				fmt.Sprintf("no denied_actions entry of the %s matches", from))
+		}
+	}
+
+	switch {
+	case explicitlyAllowed:
+		return decide(true, RuleAllowedActions, allowedEntry,
+			fmt.Sprintf("matches allowed_actions entry %q of the %s", allowedEntry, from))
+	case ownerAllowed:
+		return decide(true, RuleAllowedOwners, owner,
+			fmt.Sprintf("owner %q is in allowed_owners of the %s", owner, from))
+	}
+	return decide(false, RuleAllowedActions, "",
+		fmt.Sprintf("no allowed_actions or allowed_owners entry of the %s matches", from))
+}
+
+// describeSource names the policy level the lists of an effective policy come from
+func describeSource(effective EffectivePolicy) string {
+	switch {
+	case contains(effective.Layers, LayerRepoOverride) && effective.CustomRule != "":
+		return fmt.Sprintf("repository policy file merged with custom rule %q", effective.CustomRule)
+	case contains(effective.Layers, LayerRepoOverride):
+		return "repository policy file merged with the global policy"
+	case effective.CustomRule != "":
+		return fmt.Sprintf("custom rule %q", effective.CustomRule)
+	}
+	return "global policy"
+}
@@ -0,0 +1,58 @@
+package policy
+
+import "testing"
+
+func TestExplain(t *testing.T) {
+	config := &PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"actions/checkout", "docker/login-action@v3"},
+		AllowedOwners:  []string{"aws-actions"},
+		AlwaysDeny:     []string{"evil/action"},
+		ExcludedRepos:  []string{"org/sandbox"},
+		CustomRules: map[string]Policy{
+			"org/legacy": {PolicyMode: "mixed", AllowedActions: []string{"actions/*", "actions/setup-go"}, DeniedActions: []string{"actions/setup-go@v1"}},
+			"org/open":   {PolicyMode: "deny", DeniedActions: []string{"bad/action"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		repo    string
+		action  string
+		allowed bool
+		rule    string
+		entry   string
+		source  string
+	}{
+		{"allow list entry", "org/app", "actions/checkout@v4", true, RuleAllowedActions, "actions/checkout", SourceGlobal},
+		{"versioned allow entry", "org/app", "docker/login-action@v3", true, RuleAllowedActions, "docker/login-action@v3", SourceGlobal},
+		{"other version of versioned entry", "org/app", "docker/login-action@v2", false, RuleAllowedActions, "", SourceGlobal},
+		{"allowed owner", "org/app", "aws-actions/configure-aws-credentials@v4", true, RuleAllowedOwners, "aws-actions", SourceGlobal},
+		{"always deny", "org/app", "evil/action@v1", false, RuleAlwaysDeny, "evil/action", SourceGlobal},
+		{"always deny in excluded repo", "org/sandbox", "evil/action@v1", false, RuleAlwaysDeny, "evil/action", SourceGlobal},
+		{"excluded repo", "org/sandbox", "random/action@v1", true, RuleExcludedRepos, "org/sandbox", SourceGlobal},
+		{"mixed deny overrides allow", "org/legacy", "actions/setup-go@v1", false, RuleDeniedActions, "actions/setup-go@v1", SourceCustom},
+		{"mixed allow", "org/legacy", "actions/setup-go@v5", true, RuleAllowedActions, "actions/setup-go", SourceCustom},
+		{"deny mode not listed", "org/open", "aws-actions/setup-sam@v2", true, RuleDeniedActions, "", SourceCustom},
+		{"deny mode restricted owner", "org/open", "random/action@v1", false, RuleAllowedOwners, "", SourceCustom},
+		{"deny mode listed", "org/open", "bad/action@main", false, RuleDeniedActions, "bad/action", SourceCustom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation := Explain(config, tt.repo, tt.action)
+			if explanation.Allowed != tt.allowed || explanation.Rule != tt.rule || explanation.Entry != tt.entry || explanation.Source != tt.source {
+				t.Errorf("Expected allowed=%v rule=%s entry=%q source=%s, got %+v", tt.allowed, tt.rule, tt.entry, tt.source, explanation)
+			}
+			if explanation.Reason == "" {
+				t.Error("Expected a reason")
+			}
+
+			// The explanation agrees with the compliance check
+			_, compliant := CheckActionCompliance(config, tt.repo, []string{tt.action})
+			if compliant != explanation.Allowed {
+				t.Errorf("Explanation allowed=%v disagrees with CheckActionCompliance=%v", explanation.Allowed, compliant)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyFiles expands policy paths into the files they name, in order: files as given and
+// the .yaml and .yml files of directories in lexical order
+func PolicyFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy config: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy directory: %w", err)
+		}
+		var dirFiles []string
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				dirFiles = append(dirFiles, filepath.Join(path, entry.Name()))
+			}
+		}
+		if len(dirFiles) == 0 {
+			return nil, fmt.Errorf("no policy files in directory %s", path)
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
+
+// LoadPolicyFiles loads the policy combined from several files or directories of files, so
+// base rules, team overlays and exceptions can be kept as separate documents. Files are
+// combined in the order of PolicyFiles: mappings such as custom_rules are merged key by key,
+// lists are concatenated without duplicates, and other values of later files replace those
+// of earlier ones. Files must agree on the policy mode they set or infer from their lists, as
+// a combined deny list and allow list would otherwise silently turn into an allow policy.
+func LoadPolicyFiles(paths []string) (*PolicyConfig, error) {
+	files, err := PolicyFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 1 {
+		return LoadPolicyConfig(files[0])
+	}
+
+	combined := map[string]interface{}{}
+	var documents []policyDocument
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy config: %w", err)
+		}
+		// Check each file, as the combined policy no longer tells which file a secret is in
+		if err := checkSecrets(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		var document map[string]interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("failed to parse policy config %s: %w", file, err)
+		}
+		documents = append(documents, policyDocument{file, document})
+		combined = combinePolicyValues(combined, document).(map[string]interface{})
+	}
+	if err := checkFileModes(documents); err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(combined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine policy files: %w", err)
+	}
+	return ParsePolicyConfig(data)
+}
+
+// policyDocument is a policy file and its parsed document, to check the mode it sets or infers
+type policyDocument struct {
+	file     string
+	document map[string]interface{}
+}
+
+// checkFileModes checks policy files agree on their mode: files setting policy_mode must set
+// the same one, and files inferring it from their action lists the way ParsePolicyConfig does
+// must infer that mode, unless it is mixed, or the same one as each other
+func checkFileModes(documents []policyDocument) error {
+	var modeFile, mode string
+	for _, m := range documents {
+		if explicit, _ := m.document["policy_mode"].(string); explicit != "" {
+			if mode != "" && explicit != mode {
+				return fmt.Errorf("policy file %s is in %s mode but %s is in %s mode: set the same policy_mode in both", m.file, explicit, modeFile, mode)
+			}
+			modeFile, mode = m.file, explicit
+		}
+	}
+	if mode == "mixed" {
+		return nil
+	}
+
+	for _, m := range documents {
+		inferred := ""
+		if allowed, _ := m.document["allowed_actions"].([]interface{}); len(allowed) > 0 {
+			inferred = "allow"
+		} else if denied, _ := m.document["denied_actions"].([]interface{}); len(denied) > 0 {
+			inferred = "deny"
+		}
+		if explicit, _ := m.document["policy_mode"].(string); explicit != "" || inferred == "" {
+			continue
+		}
+		if mode != "" && inferred != mode {
+			return fmt.Errorf("policy file %s is in %s mode but %s is in %s mode: set the same policy_mode in both", m.file, inferred, modeFile, mode)
+		}
+		modeFile, mode = m.file, inferred
+	}
+	return nil
+}
+
+// combinePolicyValues overlays a value of a later policy file on that of an earlier one
+func combinePolicyValues(base, overlay interface{}) interface{} {
+	switch overlayValue := overlay.(type) {
+	case map[string]interface{}:
+		baseMap, ok := base.(map[string]interface{})
+		if !ok {
+			return overlayValue
+		}
+		combined := make(map[string]interface{}, len(baseMap)+len(overlayValue))
+		for key, value := range baseMap {
+			combined[key] = value
+		}
+		for key, value := range overlayValue {
+			combined[key] = combinePolicyValues(baseMap[key], value)
+		}
+		return combined
+	case []interface{}:
+		baseList, ok := base.([]interface{})
+		if !ok {
+			return overlayValue
+		}
+		combined := append([]interface{}(nil), baseList...)
+		for _, item := range overlayValue {
+			duplicate := false
+			for _, existing := range combined {
+				if reflect.DeepEqual(existing, item) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				combined = append(combined, item)
+			}
+		}
+		return combined
+	case nil:
+		// An empty document or key leaves the earlier value in place
+		if base != nil {
+			return base
+		}
+		return overlay
+	default:
+		return overlay
+	}
+}
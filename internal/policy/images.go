@@ -0,0 +1,81 @@
+package policy
+
+import (
+	"path"
+	"strings"
+)
+
+// defaultRegistry is the registry of image references without a registry host
+const defaultRegistry = "docker.io"
+
+// HasImagePolicy reports whether the policy restricts container images
+func HasImagePolicy(config *PolicyConfig) bool {
+	return len(config.AllowedImages) > 0 || len(config.DeniedImages) > 0 || len(config.AllowedRegistries) > 0
+}
+
+// CheckImageCompliance returns the container images that violate the image policy. Denied images
+// are always reported; when allowed images or registries are configured, images must match one of
+// them. Images set through expressions cannot be evaluated and are skipped, as are excluded
+// repositories.
+func CheckImageCompliance(config *PolicyConfig, repoName string, images []string) []string {
+	if !HasImagePolicy(config) || ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	restricted := len(config.AllowedImages) > 0 || len(config.AllowedRegistries) > 0
+
+	var violations []string
+	for _, image := range images {
+		if strings.Contains(image, "${{") || contains(violations, image) {
+			continue
+		}
+
+		switch {
+		case matchesAnyImage(config.DeniedImages, image):
+			violations = append(violations, image)
+		case restricted && !matchesAnyImage(config.AllowedImages, image) && !contains(config.AllowedRegistries, ImageRegistry(image)):
+			violations = append(violations, image)
+		}
+	}
+
+	return violations
+}
+
+// ImageRegistry returns the registry host of an image reference, such as "ghcr.io" for
+// "ghcr.io/org/image:tag" and "docker.io" for "node:20"
+func ImageRegistry(image string) string {
+	image = strings.TrimPrefix(image, "docker://")
+	first, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return first
+	}
+	return defaultRegistry
+}
+
+// matchesAnyImage checks if an image matches any of the patterns. Patterns support globs, and
+// patterns without a tag or digest match every tag and digest of the image.
+func matchesAnyImage(patterns []string, image string) bool {
+	image = strings.TrimPrefix(image, "docker://")
+	name := imageName(image)
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, image); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// imageName strips the tag and digest from an image reference
+func imageName(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+
+	// A colon after the last slash separates the tag; earlier colons belong to a registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
@@ -0,0 +1,144 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Repository visibilities input rules can be limited to
+const (
+	VisibilityPublic   = "public"
+	VisibilityPrivate  = "private"
+	VisibilityInternal = "internal"
+)
+
+// InputRule constrains an input of the steps using an action, such as forbidding
+// persist-credentials: true for actions/checkout in public repositories
+type InputRule struct {
+	// Action is owner/repo or owner/repo/path, matching every version. Globs are supported.
+	Action string `yaml:"action"`
+	Input  string `yaml:"input"`
+	// Allowed lists the values the input must have, Denied the values it must not have.
+	// Values are compared case-insensitively.
+	Allowed []string `yaml:"allowed,omitempty"`
+	Denied  []string `yaml:"denied,omitempty"`
+	// Default is the value of the input for steps that do not set it, as the action defines
+	Default string `yaml:"default,omitempty"`
+	// Visibility limits the rule to public, private or internal repositories
+	Visibility string `yaml:"visibility,omitempty"`
+	// Repos are the repository patterns the rule applies to; every repository when empty
+	Repos []string `yaml:"repos,omitempty"`
+}
+
+// InputViolation is a step whose input breaks an input rule
+type InputViolation struct {
+	Action   string `json:"action"`
+	Workflow string `json:"workflow"`
+	Job      string `json:"job,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Input    string `json:"input"`
+	Value    string `json:"value"`
+	Unset    bool   `json:"unset,omitempty"` // The step does not set the input, Value is its default
+	Reason   string `json:"reason"`
+}
+
+// InputRulesNeedVisibility reports whether input rules for a repository depend on its
+// visibility, which then needs to be looked up
+func InputRulesNeedVisibility(config *PolicyConfig, repoName string) bool {
+	for _, rule := range config.InputRules {
+		if rule.Visibility != "" && (RequiredAction{Repos: rule.Repos}).Applies(repoName) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckInputRules returns the steps whose inputs break the input rules applying to a
+// repository. Rules limited to a visibility are skipped when visibility is empty, as it could
+// not be looked up. Inputs set by expressions cannot be evaluated and are skipped. Excluded
+// repositories are not checked.
+func CheckInputRules(config *PolicyConfig, repoName, visibility string, actions []github.Action) []InputViolation {
+	if len(config.InputRules) == 0 || ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	var violations []InputViolation
+	for _, action := range actions {
+		for _, rule := range config.InputRules {
+			if rule.Visibility != "" && rule.Visibility != visibility {
+				continue
+			}
+			if !(RequiredAction{Repos: rule.Repos}).Applies(repoName) {
+				continue
+			}
+			if matched, _ := path.Match(rule.Action, normalizeAction(action.Uses)); !matched {
+				continue
+			}
+
+			value, set := action.With[rule.Input]
+			if !set {
+				value = rule.Default
+			}
+			if strings.Contains(value, "${{") {
+				continue
+			}
+
+			var reason string
+			switch {
+			case containsFold(rule.Denied, value):
+				reason = fmt.Sprintf("must not be %s", value)
+			case len(rule.Allowed) > 0 && !containsFold(rule.Allowed, value):
+				reason = fmt.Sprintf("must be %s", strings.Join(rule.Allowed, " or "))
+			default:
+				continue
+			}
+			violations = append(violations, InputViolation{
+				Action:   action.Uses,
+				Workflow: action.Workflow,
+				Job:      action.Job,
+				Line:     action.Line,
+				Input:    rule.Input,
+				Value:    value,
+				Unset:    !set,
+				Reason:   reason,
+			})
+		}
+	}
+
+	return violations
+}
+
+// validateInputRules checks the input rules of a policy are well formed
+func validateInputRules(rules []InputRule) error {
+	for _, rule := range rules {
+		if _, err := path.Match(rule.Action, ""); err != nil || rule.Action == "" {
+			return fmt.Errorf("invalid input_rules action %q", rule.Action)
+		}
+		if rule.Input == "" {
+			return fmt.Errorf("input_rules entry for %s has no input", rule.Action)
+		}
+		if len(rule.Allowed) == 0 && len(rule.Denied) == 0 {
+			return fmt.Errorf("input_rules entry for %s input %s needs allowed or denied values", rule.Action, rule.Input)
+		}
+		switch rule.Visibility {
+		case "", VisibilityPublic, VisibilityPrivate, VisibilityInternal:
+		default:
+			return fmt.Errorf("invalid input_rules visibility %q, expected %s, %s or %s", rule.Visibility, VisibilityPublic, VisibilityPrivate, VisibilityInternal)
+		}
+		for _, repo := range rule.Repos {
+			if _, err := path.Match(repo, ""); err != nil {
+				return fmt.Errorf("invalid input_rules repos pattern %q for %s", repo, rule.Action)
+			}
+		}
+	}
+	return nil
+}
+
+// containsFold checks if a string slice contains a string, ignoring case
+func containsFold(slice []string, item string) bool {
+	return slices.ContainsFunc(slice, func(s string) bool { return strings.EqualFold(s, item) })
+}
@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// firstPartyOwners publish GitHub's own actions, which deny_third_party trusts
+var firstPartyOwners = []string{"actions", "github"}
+
+// JobRule restricts the actions of the workflows and jobs it matches beyond the repository's
+// effective policy, as release pipelines warrant less trust than build pipelines. A job rule
+// only tightens the policy: actions it permits must still comply with the repository's rules.
+type JobRule struct {
+	// Name identifies the rule in reports; rules without one are identified by their position
+	Name string `yaml:"name,omitempty"`
+
+	// Workflows are globs matching the name of the workflow or its file path, Jobs globs
+	// matching the id or name of the job. Permissions match jobs whose GITHUB_TOKEN is granted
+	// at least the given levels, such as {id-token: write}; jobs and workflows not setting
+	// permissions never match. Every selector set must match.
+	Workflows   []string          `yaml:"workflows,omitempty"`
+	Jobs        []string          `yaml:"jobs,omitempty"`
+	Permissions map[string]string `yaml:"permissions,omitempty"`
+	// Repos are the repository patterns the rule applies to; every repository when empty
+	Repos []string `yaml:"repos,omitempty"`
+
+	// DeniedActions are denied in matching jobs. Once AllowedActions, AllowedOwners or
+	// DenyThirdParty is set, other actions are denied too; DenyThirdParty permits the actions
+	// of GitHub and of the repository's owner. Patterns support globs. Local actions and
+	// container images are left to the repository's rules.
+	DeniedActions  []string `yaml:"denied_actions,omitempty"`
+	AllowedActions []string `yaml:"allowed_actions,omitempty"`
+	AllowedOwners  []string `yaml:"allowed_owners,omitempty"`
+	DenyThirdParty bool     `yaml:"deny_third_party,omitempty"`
+}
+
+// JobRuleDenial is the job rule denying an action and why
+type JobRuleDenial struct {
+	Rule   string // Name of the job rule
+	Reason string
+}
+
+// Matches reports whether the rule applies to the job of an action in a repository
+func (r JobRule) Matches(repoName string, action github.Action) bool {
+	if !(RequiredAction{Repos: r.Repos}).Applies(repoName) {
+		return false
+	}
+	if len(r.Workflows) > 0 && !matchesAny(r.Workflows, action.WorkflowName, action.Workflow) {
+		return false
+	}
+	if len(r.Jobs) > 0 && !matchesAny(r.Jobs, action.Job, action.JobName) {
+		return false
+	}
+	for scope, level := range r.Permissions {
+		if !action.Permissions.Grants(scope, level) {
+			return false
+		}
+	}
+	return true
+}
+
+// denies returns why the rule denies an action in a repository, or an empty string when it
+// does not
+func (r JobRule) denies(repoName, actionWithVersion string) string {
+	action := normalizeAction(actionWithVersion)
+	if entry, ok := globEntry(r.DeniedActions, action, actionWithVersion); ok {
+		return fmt.Sprintf("matches denied_actions entry %q", entry)
+	}
+
+	owner := ActionOwner(actionWithVersion)
+	if owner == "" || !(r.DenyThirdParty || len(r.AllowedActions) > 0 || len(r.AllowedOwners) > 0) {
+		return ""
+	}
+	if _, ok := globEntry(r.AllowedActions, action, actionWithVersion); ok || contains(r.AllowedOwners, owner) {
+		return ""
+	}
+	if r.DenyThirdParty {
+		repoOwner, _, _ := strings.Cut(repoName, "/")
+		if contains(firstPartyOwners, owner) || strings.EqualFold(owner, repoOwner) {
+			return ""
+		}
+		return fmt.Sprintf("third-party owner %q is not in allowed_owners and the action has no allowed_actions entry", owner)
+	}
+	return "no allowed_actions or allowed_owners entry matches"
+}
+
+// name identifies the rule at an index of job_rules
+func (r JobRule) name(index int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("job_rules[%d]", index)
+}
+
+// HasJobRules reports whether any job rule applies to a repository
+func HasJobRules(config *PolicyConfig, repoName string) bool {
+	for _, rule := range config.JobRules {
+		if (RequiredAction{Repos: rule.Repos}).Applies(repoName) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckJobRules returns whether a job rule denies an action of a repository, naming the first
+// rule to deny it
+func CheckJobRules(config *PolicyConfig, repoName string, action github.Action) (JobRuleDenial, bool) {
+	for i, rule := range config.JobRules {
+		if !rule.Matches(repoName, action) {
+			continue
+		}
+		if reason := rule.denies(repoName, action.Uses); reason != "" {
+			name := rule.name(i)
+			return JobRuleDenial{
+				Rule:   name,
+				Reason: fmt.Sprintf("%s of job rule %q, which applies to job %s of %s", reason, name, action.Job, action.Workflow),
+			}, true
+		}
+	}
+	return JobRuleDenial{}, false
+}
+
+// validateJobRules checks the job rules of a policy are well formed
+func validateJobRules(rules []JobRule) error {
+	for i, rule := range rules {
+		name := rule.name(i)
+		if len(rule.Workflows) == 0 && len(rule.Jobs) == 0 && len(rule.Permissions) == 0 {
+			return fmt.Errorf("job rule %q needs workflows, jobs or permissions to match", name)
+		}
+		if len(rule.DeniedActions) == 0 && len(rule.AllowedActions) == 0 && len(rule.AllowedOwners) == 0 && !rule.DenyThirdParty {
+			return fmt.Errorf("job rule %q needs denied_actions, allowed_actions, allowed_owners or deny_third_party", name)
+		}
+		for _, patterns := range [][]string{rule.Workflows, rule.Jobs, rule.Repos, rule.DeniedActions, rule.AllowedActions} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+					return fmt.Errorf("invalid pattern %q in job rule %q", pattern, name)
+				}
+			}
+		}
+		for scope, level := range rule.Permissions {
+			if level != github.PermissionRead && level != github.PermissionWrite {
+				return fmt.Errorf("invalid permission level %q for %s in job rule %q, expected %s or %s", level, scope, name, github.PermissionRead, github.PermissionWrite)
+			}
+		}
+	}
+	return nil
+}
+
+// matchesAny reports whether a glob pattern matches any of the non-empty values
+func matchesAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if matched, _ := path.Match(pattern, value); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// globEntry returns the glob pattern matching an action with or without its version
+func globEntry(patterns []string, action, actionWithVersion string) (string, bool) {
+	for _, pattern := range patterns {
+		if matchesAny([]string{pattern}, action, actionWithVersion) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
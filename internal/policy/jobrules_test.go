@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+const jobRulesPolicy = `
+policy_mode: deny
+denied_actions: [evil/action]
+excluded_repos: [org/sandbox]
+job_rules:
+  - name: release jobs
+    jobs: [release, "publish-*"]
+    deny_third_party: true
+    allowed_owners: [docker]
+  - workflows: [Deploy]
+    permissions:
+      id-token: write
+    allowed_actions: [actions/checkout, "aws-actions/*"]
+`
+
+func TestCheckJobRules(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(jobRulesPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicyConfig returned error: %v", err)
+	}
+	oidc := github.Permissions{"id-token": github.PermissionWrite}
+
+	tests := []struct {
+		name   string
+		action github.Action
+		rule   string
+	}{
+		{"third-party in release job", github.Action{Uses: "goreleaser/goreleaser-action@v6", Job: "release"}, "release jobs"},
+		{"job matched by glob", github.Action{Uses: "pypa/gh-action-pypi-publish@v1", Job: "publish-pypi"}, "release jobs"},
+		{"job matched by name", github.Action{Uses: "pypa/gh-action-pypi-publish@v1", Job: "ship", JobName: "release"}, "release jobs"},
+		{"GitHub action in release job", github.Action{Uses: "actions/checkout@v4", Job: "release"}, ""},
+		{"own action in release job", github.Action{Uses: "org/tools@v1", Job: "release"}, ""},
+		{"allowed owner in release job", github.Action{Uses: "docker/login-action@v3", Job: "release"}, ""},
+		{"local action in release job", github.Action{Uses: "./.github/actions/sign", Job: "release"}, ""},
+		{"third-party in build job", github.Action{Uses: "goreleaser/goreleaser-action@v6", Job: "build"}, ""},
+		{"unlisted with id-token", github.Action{Uses: "some/tool@v1", WorkflowName: "Deploy", Permissions: oidc}, "job_rules[1]"},
+		{"allowed glob with id-token", github.Action{Uses: "aws-actions/configure-aws-credentials@v4", WorkflowName: "Deploy", Permissions: oidc}, ""},
+		{"unlisted without id-token", github.Action{Uses: "some/tool@v1", WorkflowName: "Deploy"}, ""},
+		{"unlisted in other workflow", github.Action{Uses: "some/tool@v1", WorkflowName: "CI", Permissions: oidc}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			denial, denied := CheckJobRules(config, "org/app", tt.action)
+			if denied != (tt.rule != "") || denial.Rule != tt.rule {
+				t.Errorf("CheckJobRules() = %+v, %v, want rule %q", denial, denied, tt.rule)
+			}
+		})
+	}
+}
+
+func TestCheckWorkflowComplianceJobRules(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(jobRulesPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicyConfig returned error: %v", err)
+	}
+
+	actions := []github.Action{
+		{Uses: "goreleaser/goreleaser-action@v6", Job: "build"},
+		{Uses: "goreleaser/goreleaser-action@v6", Job: "release"},
+		{Uses: "evil/action@v1", Job: "release"},
+		{Uses: "actions/checkout@v4", Job: "release"},
+	}
+	expected := []string{"evil/action@v1", "goreleaser/goreleaser-action@v6"}
+	if violations := CheckWorkflowCompliance(config, "org/app", actions); !reflect.DeepEqual(violations, expected) {
+		t.Errorf("CheckWorkflowCompliance() = %v, want %v", violations, expected)
+	}
+
+	// Excluded repositories are not checked
+	if violations := CheckWorkflowCompliance(config, "org/sandbox", actions); len(violations) != 0 {
+		t.Errorf("Expected no violations in an excluded repository, got %v", violations)
+	}
+}
+
+func TestValidateJobRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		err   string
+	}{
+		{"no selector", "- deny_third_party: true", "needs workflows, jobs or permissions"},
+		{"no restriction", "- jobs: [release]", "needs denied_actions"},
+		{"invalid glob", "- {name: bad, jobs: ['[release'], deny_third_party: true}", `invalid pattern "[release" in job rule "bad"`},
+		{"invalid level", "- {permissions: {id-token: admin}, deny_third_party: true}", `invalid permission level "admin"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicyConfig([]byte("job_rules:\n" + tt.rules))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParsePolicyConfig() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
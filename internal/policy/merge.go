@@ -0,0 +1,341 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Merge strategies for repository policy files that conflict with the global policy
+const (
+	MergePermissive = "permissive"
+	MergeStrict     = "strict"
+)
+
+// Kinds of difference between a repository policy file and the global policy
+const (
+	// ConflictDeniedByGlobal is a repository allow entry for an action the global policy denies
+	ConflictDeniedByGlobal = "denied_by_global"
+	// ConflictDropsDenied is a global deny entry missing from the repository's deny list
+	ConflictDropsDenied = "drops_denied"
+	// ConflictLoosensMode is a repository switching from allow or mixed mode to deny mode,
+	// which allows every action not denied
+	ConflictLoosensMode = "loosens_mode"
+	// ConflictExtendsAllowed is a repository allow entry for an action the global policy does
+	// not allow. It loosens the policy without contradicting it, so it is only reported when
+	// repository overrides are not allowed.
+	ConflictExtendsAllowed = "extends_allowed"
+	// ConflictInvalidMode is a repository policy_mode other than allow, deny or mixed, under
+	// which no action would violate the action lists. The repository rule is always rejected.
+	ConflictInvalidMode = "invalid_mode"
+)
+
+// Resolutions of merge conflicts
+const (
+	ResolutionRepo    = "repo"    // The repository rule was applied
+	ResolutionGlobal  = "global"  // The repository rule was rejected by the strict strategy
+	ResolutionRefused = "refused" // The loosening was dropped because overrides are not allowed
+)
+
+// MergeConflict is a difference between a repository policy file and the global policy
+type MergeConflict struct {
+	Kind       string `json:"kind"`
+	Rule       string `json:"rule"` // Policy key the conflict is in
+	Entry      string `json:"entry,omitempty"`
+	Resolution string `json:"resolution"`
+}
+
+// MergeResult is the outcome of merging a repository policy file into the global policy
+type MergeResult struct {
+	Policy    *PolicyConfig
+	Conflicts []MergeConflict
+}
+
+// MergePolicies merges a repository policy file into the global policy for that repository.
+//
+// The merge is deterministic and follows these rules:
+//   - always_deny entries of the repository are added to the global list, which can never shrink
+//   - the repository's custom rule for itself, or else its top-level action lists, replace any
+//     global custom rule for the repository; other keys of the repository file are ignored
+//   - a policy_mode other than allow, deny or mixed, at the top level or in a custom rule, is
+//     a conflict that rejects the repository rule whatever the strategy
+//   - allowing an action the global policy denies, dropping a global deny entry, or switching
+//     from allow or mixed mode to deny mode are conflicts
+//   - with the permissive strategy (default) conflicts are reported and the repository rule
+//     applies; with the strict strategy a conflicting repository rule is rejected
+//   - when allow_repo_overrides is false, every loosening is dropped from the repository rule,
+//     including allow entries the global policy does not have, so it can only tighten
+func MergePolicies(globalPolicy *PolicyConfig, repoPolicyContent []byte, repoName string) (MergeResult, error) {
+	// Create a deep copy of the global policy
+	mergedPolicy := &PolicyConfig{
+		Version:        globalPolicy.Version,
+		AllowedActions: make([]string, len(globalPolicy.AllowedActions)),
+		DeniedActions:  make([]string, len(globalPolicy.DeniedActions)),
+		ExcludedRepos:  make([]string, len(globalPolicy.ExcludedRepos)),
+		CustomRules:    make(map[string]Policy),
+		PolicyMode:     globalPolicy.PolicyMode,
+		AlwaysDeny:     make([]string, len(globalPolicy.AlwaysDeny)),
+		CloudAccess:    globalPolicy.CloudAccess,
+
+		AllowedOwners:             globalPolicy.AllowedOwners,
+		RequireVerifiedCreator:    globalPolicy.RequireVerifiedCreator,
+		RequireActionsUpdates:     globalPolicy.RequireActionsUpdates,
+		RequireWorkflowProtection: globalPolicy.RequireWorkflowProtection,
+		WorkflowOwners:            globalPolicy.WorkflowOwners,
+		DenyArchivedActions:       globalPolicy.DenyArchivedActions,
+		MaxStalenessDays:          globalPolicy.MaxStalenessDays,
+		DenyForkActions:           globalPolicy.DenyForkActions,
+		MinRepositoryAgeDays:      globalPolicy.MinRepositoryAgeDays,
+		DetectMissingActions:      globalPolicy.DetectMissingActions,
+		DetectNamespaceTakeover:   globalPolicy.DetectNamespaceTakeover,
+		DetectTyposquatting:       globalPolicy.DetectTyposquatting,
+		KnownActions:              globalPolicy.KnownActions,
+		DetectArtifactMisuse:      globalPolicy.DetectArtifactMisuse,
+		RequiredActions:           append([]RequiredAction(nil), globalPolicy.RequiredActions...),
+		RequiredWorkflows:         globalPolicy.RequiredWorkflows,
+		MinVersions:               globalPolicy.MinVersions,
+		InputRules:                globalPolicy.InputRules,
+		JobRules:                  globalPolicy.JobRules,
+		DetectContinueOnError:     globalPolicy.DetectContinueOnError,
+		SecurityActions:           globalPolicy.SecurityActions,
+		RequireJobTimeouts:        globalPolicy.RequireJobTimeouts,
+
+		AllowedImages:     globalPolicy.AllowedImages,
+		DeniedImages:      globalPolicy.DeniedImages,
+		AllowedRegistries: globalPolicy.AllowedRegistries,
+
+		AllowedReusableWorkflows: globalPolicy.AllowedReusableWorkflows,
+		DeniedReusableWorkflows:  globalPolicy.DeniedReusableWorkflows,
+
+		DenyDockerActionsFromUnknownRegistries: globalPolicy.DenyDockerActionsFromUnknownRegistries,
+		DenyNode16Actions:                      globalPolicy.DenyNode16Actions,
+		Deprecations:                           globalPolicy.Deprecations,
+
+		scopedRepos: globalPolicy.scopedRepos,
+
+		RepoPolicy:         globalPolicy.RepoPolicy,
+		MergeStrategy:      globalPolicy.MergeStrategy,
+		AllowRepoOverrides: globalPolicy.AllowRepoOverrides,
+		MinToolVersion:     globalPolicy.MinToolVersion,
+		ToolVersionCheck:   globalPolicy.ToolVersionCheck,
+	}
+
+	// Copy slices and map
+	copy(mergedPolicy.AllowedActions, globalPolicy.AllowedActions)
+	copy(mergedPolicy.DeniedActions, globalPolicy.DeniedActions)
+	copy(mergedPolicy.ExcludedRepos, globalPolicy.ExcludedRepos)
+	copy(mergedPolicy.AlwaysDeny, globalPolicy.AlwaysDeny)
+	for k, v := range globalPolicy.CustomRules {
+		mergedPolicy.CustomRules[k] = v
+	}
+
+	// Parse repo policy
+	var repoPolicy PolicyConfig
+	if err := yaml.Unmarshal(repoPolicyContent, &repoPolicy); err != nil {
+		return MergeResult{}, fmt.Errorf("failed to parse repository policy: %w", err)
+	}
+
+	// Repositories may extend the kill-switch list but never remove from it
+	for _, action := range repoPolicy.AlwaysDeny {
+		if !contains(mergedPolicy.AlwaysDeny, action) {
+			mergedPolicy.AlwaysDeny = append(mergedPolicy.AlwaysDeny, action)
+		}
+	}
+
+	// Repositories may require more actions of themselves but never drop a requirement
+	if err := validateRequiredActions(repoPolicy.RequiredActions); err != nil {
+		return MergeResult{}, fmt.Errorf("invalid repository policy: %w", err)
+	}
+	for _, required := range repoPolicy.RequiredActions {
+		mergedPolicy.RequiredActions = append(mergedPolicy.RequiredActions, RequiredAction{Action: required.Action, Repos: []string{repoName}})
+	}
+
+	// A mode the action lists can't be enforced in would allow every action
+	if invalid := invalidModes(repoPolicy); len(invalid) > 0 {
+		return MergeResult{Policy: mergedPolicy, Conflicts: resolve(invalid, ResolutionGlobal)}, nil
+	}
+
+	// Determine the repository rule: its custom rule for itself, or its top-level lists
+	rule, exists := repoPolicy.CustomRules[repoName]
+	if !exists {
+		if len(repoPolicy.AllowedActions) == 0 && len(repoPolicy.DeniedActions) == 0 {
+			return MergeResult{Policy: mergedPolicy}, nil
+		}
+		rule = Policy{
+			AllowedActions: repoPolicy.AllowedActions,
+			DeniedActions:  repoPolicy.DeniedActions,
+			PolicyMode:     repoPolicy.PolicyMode,
+		}
+		// Set policy mode for the repo if specified, otherwise inherit
+		if rule.PolicyMode == "" {
+			rule.PolicyMode = determineRepoMode(rule, globalPolicy.PolicyMode)
+		}
+	}
+
+	// Compare the rules applied with and without the repository rule
+	baseline := ResolveEffectivePolicy(globalPolicy, repoName)
+	mergedPolicy.CustomRules[repoName] = rule
+	candidate := ResolveEffectivePolicy(mergedPolicy, repoName)
+	if baseline.Excluded {
+		return MergeResult{Policy: mergedPolicy}, nil
+	}
+	conflicts := mergeConflicts(baseline, candidate)
+
+	if globalPolicy.AllowRepoOverrides != nil && !*globalPolicy.AllowRepoOverrides {
+		mergedPolicy.CustomRules[repoName] = tighten(rule, baseline, candidate, conflicts)
+		return MergeResult{Policy: mergedPolicy, Conflicts: resolve(conflicts, ResolutionRefused)}, nil
+	}
+
+	// Allow entries beyond the global policy are what repository overrides are for
+	var contradictions []MergeConflict
+	for _, conflict := range conflicts {
+		if conflict.Kind != ConflictExtendsAllowed {
+			contradictions = append(contradictions, conflict)
+		}
+	}
+	if globalPolicy.MergeStrategy == MergeStrict && len(contradictions) > 0 {
+		if globalRule, ok := globalPolicy.CustomRules[repoName]; ok {
+			mergedPolicy.CustomRules[repoName] = globalRule
+		} else {
+			delete(mergedPolicy.CustomRules, repoName)
+		}
+		return MergeResult{Policy: mergedPolicy, Conflicts: resolve(contradictions, ResolutionGlobal)}, nil
+	}
+
+	return MergeResult{Policy: mergedPolicy, Conflicts: resolve(contradictions, ResolutionRepo)}, nil
+}
+
+// invalidModes lists the policy_mode values of a repository policy file other than allow, deny
+// or mixed, at the top level and in each custom rule
+func invalidModes(repoPolicy PolicyConfig) []MergeConflict {
+	var conflicts []MergeConflict
+	if repoPolicy.PolicyMode != "" && !validPolicyMode(repoPolicy.PolicyMode) {
+		conflicts = append(conflicts, MergeConflict{Kind: ConflictInvalidMode, Rule: "policy_mode", Entry: repoPolicy.PolicyMode})
+	}
+	rules := make([]string, 0, len(repoPolicy.CustomRules))
+	for name := range repoPolicy.CustomRules {
+		rules = append(rules, name)
+	}
+	sort.Strings(rules)
+	for _, name := range rules {
+		if mode := repoPolicy.CustomRules[name].PolicyMode; mode != "" && !validPolicyMode(mode) {
+			conflicts = append(conflicts, MergeConflict{Kind: ConflictInvalidMode, Rule: fmt.Sprintf("custom_rules.%s.policy_mode", name), Entry: mode})
+		}
+	}
+	return conflicts
+}
+
+// resolve records how conflicts were resolved
+func resolve(conflicts []MergeConflict, resolution string) []MergeConflict {
+	for i := range conflicts {
+		conflicts[i].Resolution = resolution
+	}
+	return conflicts
+}
+
+// mergeConflicts lists how a repository's candidate rules loosen its baseline rules, from the
+// most severe kind of conflict to the least and in list order within a kind
+func mergeConflicts(baseline, candidate EffectivePolicy) []MergeConflict {
+	var conflicts []MergeConflict
+	baselineDenies := baseline.PolicyMode == "deny" || baseline.PolicyMode == "mixed"
+	baselineAllows := baseline.PolicyMode == "allow" || baseline.PolicyMode == "mixed"
+	candidateDenies := candidate.PolicyMode == "deny" || candidate.PolicyMode == "mixed"
+
+	if baselineAllows && candidate.PolicyMode == "deny" {
+		conflicts = append(conflicts, MergeConflict{Kind: ConflictLoosensMode, Rule: "policy_mode", Entry: candidate.PolicyMode})
+	}
+
+	if candidate.PolicyMode != "deny" {
+		for _, entry := range candidate.AllowedActions {
+			normalized := normalizeAction(entry)
+			_, alwaysDenied := matchingEntry(baseline.AlwaysDeny, entry, normalized)
+			_, denied := matchingEntry(baseline.DeniedActions, entry, normalized)
+			_, allowed := matchingEntry(baseline.AllowedActions, entry, normalized)
+			switch {
+			case alwaysDenied || (baselineDenies && denied):
+				conflicts = append(conflicts, MergeConflict{Kind: ConflictDeniedByGlobal, Rule: RuleAllowedActions, Entry: entry})
+			case baselineAllows && !allowed:
+				conflicts = append(conflicts, MergeConflict{Kind: ConflictExtendsAllowed, Rule: RuleAllowedActions, Entry: entry})
+			}
+		}
+	}
+
+	if baselineDenies && candidateDenies {
+		for _, entry := range baseline.DeniedActions {
+			if !contains(candidate.DeniedActions, entry) {
+				conflicts = append(conflicts, MergeConflict{Kind: ConflictDropsDenied, Rule: RuleDeniedActions, Entry: entry})
+			}
+		}
+	}
+
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return conflictOrder(conflicts[i].Kind) < conflictOrder(conflicts[j].Kind)
+	})
+	return conflicts
+}
+
+// conflictOrder orders conflicts from the most to the least severe
+func conflictOrder(kind string) int {
+	switch kind {
+	case ConflictDeniedByGlobal:
+		return 0
+	case ConflictDropsDenied:
+		return 1
+	case ConflictLoosensMode:
+		return 2
+	}
+	return 3
+}
+
+// tighten removes the loosening conflicts from a repository rule, keeping its tightening. The
+// global mode is kept unless the candidate's is one the action lists can be enforced in.
+func tighten(rule Policy, baseline, candidate EffectivePolicy, conflicts []MergeConflict) Policy {
+	tightened := Policy{
+		PolicyMode:     baseline.PolicyMode,
+		AllowedActions: candidate.AllowedActions,
+		DeniedActions:  candidate.DeniedActions,
+		When:           rule.When,
+	}
+	if validPolicyMode(candidate.PolicyMode) {
+		tightened.PolicyMode = candidate.PolicyMode
+	}
+
+	var refusedAllows []string
+	for _, conflict := range conflicts {
+		switch conflict.Kind {
+		case ConflictLoosensMode:
+			tightened.PolicyMode = baseline.PolicyMode
+		case ConflictDeniedByGlobal, ConflictExtendsAllowed:
+			refusedAllows = append(refusedAllows, conflict.Entry)
+		}
+	}
+
+	var allowed []string
+	for _, entry := range tightened.AllowedActions {
+		if !contains(refusedAllows, entry) {
+			allowed = append(allowed, entry)
+		}
+	}
+	if len(allowed) == 0 {
+		// An empty allow list would inherit the global list, so keep the baseline's instead
+		allowed = baseline.AllowedActions
+	}
+	tightened.AllowedActions = allowed
+
+	if tightened.PolicyMode == "deny" || tightened.PolicyMode == "mixed" {
+		tightened.DeniedActions = union(baseline.DeniedActions, tightened.DeniedActions)
+	}
+	return tightened
+}
+
+// union returns the entries of a followed by those of b not already in a
+func union(a, b []string) []string {
+	result := append([]string(nil), a...)
+	for _, entry := range b {
+		if !contains(result, entry) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,187 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergePolicies(t *testing.T) {
+	global := func() *PolicyConfig {
+		return &PolicyConfig{
+			PolicyMode:     "mixed",
+			AllowedActions: []string{"actions/checkout", "actions/setup-go"},
+			DeniedActions:  []string{"bad/action"},
+			AlwaysDeny:     []string{"evil/action"},
+		}
+	}
+	repoConfig := []byte(`
+policy_mode: mixed
+allowed_actions:
+  - actions/checkout
+  - bad/action
+  - someone/tool
+denied_actions:
+  - other/denied
+always_deny:
+  - repo/denied
+`)
+
+	t.Run("permissive reports conflicts and applies the repository rule", func(t *testing.T) {
+		result, err := MergePolicies(global(), repoConfig, "org/app")
+		if err != nil {
+			t.Fatalf("MergePolicies returned error: %v", err)
+		}
+
+		expected := []MergeConflict{
+			{Kind: ConflictDeniedByGlobal, Rule: RuleAllowedActions, Entry: "bad/action", Resolution: ResolutionRepo},
+			{Kind: ConflictDropsDenied, Rule: RuleDeniedActions, Entry: "bad/action", Resolution: ResolutionRepo},
+		}
+		if !reflect.DeepEqual(result.Conflicts, expected) {
+			t.Errorf("Expected conflicts %+v, got %+v", expected, result.Conflicts)
+		}
+		if _, compliant := CheckActionCompliance(result.Policy, "org/app", []string{"bad/action@v1", "someone/tool@v1"}); !compliant {
+			t.Error("Expected the repository rule to apply")
+		}
+		if !contains(result.Policy.AlwaysDeny, "repo/denied") || !contains(result.Policy.AlwaysDeny, "evil/action") {
+			t.Errorf("Expected always_deny to be extended, got %v", result.Policy.AlwaysDeny)
+		}
+	})
+
+	t.Run("strict rejects a conflicting repository rule", func(t *testing.T) {
+		config := global()
+		config.MergeStrategy = MergeStrict
+		result, err := MergePolicies(config, repoConfig, "org/app")
+		if err != nil {
+			t.Fatalf("MergePolicies returned error: %v", err)
+		}
+
+		if len(result.Conflicts) != 2 || result.Conflicts[0].Resolution != ResolutionGlobal {
+			t.Errorf("Expected conflicts resolved in favor of the global policy, got %+v", result.Conflicts)
+		}
+		if _, exists := result.Policy.CustomRules["org/app"]; exists {
+			t.Error("Expected the repository rule to be rejected")
+		}
+		if !contains(result.Policy.AlwaysDeny, "repo/denied") {
+			t.Error("Expected always_deny to be extended even when the rule is rejected")
+		}
+	})
+
+	t.Run("strict accepts allow list extensions", func(t *testing.T) {
+		config := global()
+		config.MergeStrategy = MergeStrict
+		result, err := MergePolicies(config, []byte("allowed_actions: [someone/tool]\n"), "org/app")
+		if err != nil {
+			t.Fatalf("MergePolicies returned error: %v", err)
+		}
+		if len(result.Conflicts) != 0 {
+			t.Errorf("Expected no conflicts, got %+v", result.Conflicts)
+		}
+		if _, compliant := CheckActionCompliance(result.Policy, "org/app", []string{"someone/tool@v1"}); !compliant {
+			t.Error("Expected the repository to allow its own action")
+		}
+	})
+
+	t.Run("overrides disabled only tighten", func(t *testing.T) {
+		config := global()
+		disabled := false
+		config.AllowRepoOverrides = &disabled
+		result, err := MergePolicies(config, repoConfig, "org/app")
+		if err != nil {
+			t.Fatalf("MergePolicies returned error: %v", err)
+		}
+
+		if len(result.Conflicts) != 3 || result.Conflicts[2].Kind != ConflictExtendsAllowed || result.Conflicts[2].Resolution != ResolutionRefused {
+			t.Errorf("Expected refused conflicts including the allow list extension, got %+v", result.Conflicts)
+		}
+		violations, _ := CheckActionCompliance(result.Policy, "org/app", []string{"actions/checkout@v4", "bad/action@v1", "someone/tool@v1", "other/denied@v1"})
+		if !reflect.DeepEqual(violations, []string{"bad/action@v1", "someone/tool@v1", "other/denied@v1"}) {
+			t.Errorf("Expected only tightening to apply, got violations %v", violations)
+		}
+	})
+
+	t.Run("overrides disabled refuse deny mode", func(t *testing.T) {
+		config := &PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+		disabled := false
+		config.AllowRepoOverrides = &disabled
+		result, err := MergePolicies(config, []byte("policy_mode: deny\ndenied_actions: [bad/action]\n"), "org/app")
+		if err != nil {
+			t.Fatalf("MergePolicies returned error: %v", err)
+		}
+
+		if len(result.Conflicts) != 1 || result.Conflicts[0].Kind != ConflictLoosensMode {
+			t.Errorf("Expected a refused mode change, got %+v", result.Conflicts)
+		}
+		violations, _ := CheckActionCompliance(result.Policy, "org/app", []string{"actions/checkout@v4", "bad/action@v1", "someone/tool@v1"})
+		if !reflect.DeepEqual(violations, []string{"bad/action@v1", "someone/tool@v1"}) {
+			t.Errorf("Expected the allow list to keep applying, got violations %v", violations)
+		}
+	})
+
+	t.Run("invalid modes reject the repository rule", func(t *testing.T) {
+		strict := &PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}, MergeStrategy: MergeStrict}
+		disabled := false
+		strict.AllowRepoOverrides = &disabled
+		permissive := &PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+
+		tests := []struct {
+			name     string
+			config   *PolicyConfig
+			content  string
+			conflict MergeConflict
+		}{
+			{"top level", strict, "policy_mode: off\nallowed_actions: [evil/thing]\n", MergeConflict{Kind: ConflictInvalidMode, Rule: "policy_mode", Entry: "off", Resolution: ResolutionGlobal}},
+			{"custom rule", permissive, "custom_rules:\n  org/app:\n    policy_mode: alow\n    allowed_actions: [evil/thing]\n", MergeConflict{Kind: ConflictInvalidMode, Rule: "custom_rules.org/app.policy_mode", Entry: "alow", Resolution: ResolutionGlobal}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := MergePolicies(tt.config, []byte(tt.content), "org/app")
+				if err != nil {
+					t.Fatalf("MergePolicies returned error: %v", err)
+				}
+				if !reflect.DeepEqual(result.Conflicts, []MergeConflict{tt.conflict}) {
+					t.Errorf("Expected conflicts %+v, got %+v", []MergeConflict{tt.conflict}, result.Conflicts)
+				}
+				if _, exists := result.Policy.CustomRules["org/app"]; exists {
+					t.Error("Expected the repository rule to be rejected")
+				}
+				violations, _ := CheckActionCompliance(result.Policy, "org/app", []string{"actions/checkout@v4", "evil/thing@v1"})
+				if !reflect.DeepEqual(violations, []string{"evil/thing@v1"}) {
+					t.Errorf("Expected the global policy to keep applying, got violations %v", violations)
+				}
+			})
+		}
+	})
+}
+
+func TestTightenKeepsGlobalMode(t *testing.T) {
+	baseline := EffectivePolicy{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+	candidate := EffectivePolicy{PolicyMode: "off", AllowedActions: []string{"actions/checkout"}}
+	if tightened := tighten(Policy{}, baseline, candidate, nil); tightened.PolicyMode != "allow" {
+		t.Errorf("Expected the global mode to be kept, got %q", tightened.PolicyMode)
+	}
+	candidate.PolicyMode = "mixed"
+	if tightened := tighten(Policy{}, baseline, candidate, nil); tightened.PolicyMode != "mixed" {
+		t.Errorf("Expected a valid candidate mode to apply, got %q", tightened.PolicyMode)
+	}
+}
+
+func TestParsePolicyConfigMergeStrategy(t *testing.T) {
+	if _, err := ParsePolicyConfig([]byte("merge_strategy: lenient\n")); err == nil {
+		t.Error("Expected an error for an unknown merge strategy")
+	}
+	if _, err := ParsePolicyConfig([]byte("merge_strategy: strict\n")); err != nil {
+		t.Errorf("Expected strict to be accepted, got %v", err)
+	}
+}
+
+func TestParsePolicyConfigRepoPolicy(t *testing.T) {
+	if _, err := ParsePolicyConfig([]byte("repo_policy: sometimes\n")); err == nil {
+		t.Error("Expected an error for an unknown repo_policy value")
+	}
+	for _, value := range []string{RepoPolicyMerge, RepoPolicyIgnore, RepoPolicyRequire} {
+		config, err := ParsePolicyConfig([]byte("repo_policy: " + value + "\n"))
+		if err != nil || config.RepoPolicy != value {
+			t.Errorf("Expected repo_policy %s to be accepted, got %+v, %v", value, config, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the newest policy format version, written by export and policy migrate
+const CurrentVersion = 1
+
+// migration upgrades a policy document from the previous version to Version
+type migration struct {
+	Version     int
+	Description string
+	Apply       func(root *yaml.Node) error
+}
+
+// migrations upgrade policy documents one version at a time, in order
+var migrations = []migration{
+	{
+		Version:     1,
+		Description: "add the version field",
+		Apply:       func(root *yaml.Node) error { return nil },
+	},
+}
+
+// MigrationResult describes the upgrade of a policy file
+type MigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	Changes     []string // Descriptions of the migrations applied, in order
+	Content     []byte   // The upgraded policy, with the original comments
+}
+
+// Upgraded reports whether the policy needed upgrading
+func (r MigrationResult) Upgraded() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// MigratePolicy upgrades policy content to CurrentVersion, keeping its comments and order.
+// Content already at the current version is returned unchanged.
+func MigratePolicy(content []byte) (MigrationResult, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return MigrationResult{}, fmt.Errorf("failed to parse policy config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return MigrationResult{}, fmt.Errorf("policy config is not a mapping")
+	}
+	root := doc.Content[0]
+
+	version := 0
+	versionNode := policyVersionNode(root)
+	if versionNode != nil {
+		parsed, err := strconv.Atoi(versionNode.Value)
+		if err != nil {
+			return MigrationResult{}, fmt.Errorf("invalid policy version %q", versionNode.Value)
+		}
+		version = parsed
+	}
+	if version < 0 || version > CurrentVersion {
+		return MigrationResult{}, fmt.Errorf("unsupported policy version %d, this release supports versions up to %d: upgrade action-control", version, CurrentVersion)
+	}
+
+	result := MigrationResult{FromVersion: version, ToVersion: CurrentVersion, Content: content}
+	if version == CurrentVersion {
+		return result, nil
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		if err := m.Apply(root); err != nil {
+			return MigrationResult{}, fmt.Errorf("failed to migrate policy to version %d: %w", m.Version, err)
+		}
+		result.Changes = append(result.Changes, fmt.Sprintf("version %d: %s", m.Version, m.Description))
+	}
+
+	// Record the new version as the first key, where readers look for it
+	if versionNode != nil {
+		versionNode.Value = strconv.Itoa(CurrentVersion)
+	} else {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+		value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(CurrentVersion)}
+		// Keep a leading comment of the file above the version
+		if len(root.Content) > 0 {
+			key.HeadComment = root.Content[0].HeadComment
+			root.Content[0].HeadComment = ""
+		}
+		root.Content = append([]*yaml.Node{key, value}, root.Content...)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return MigrationResult{}, fmt.Errorf("failed to encode policy config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return MigrationResult{}, fmt.Errorf("failed to encode policy config: %w", err)
+	}
+
+	// The upgraded policy must still be valid
+	if _, err := ParsePolicyConfig(buf.Bytes()); err != nil {
+		return MigrationResult{}, fmt.Errorf("migrated policy is invalid: %w", err)
+	}
+	result.Content = buf.Bytes()
+	return result, nil
+}
+
+// policyVersionNode returns the value node of the version key of a policy mapping
+func policyVersionNode(root *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "version" {
+			return root.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigratePolicy(t *testing.T) {
+	content := `# Organization policy
+policy_mode: allow
+allowed_actions:
+  - actions/checkout@v4 # pinned by the platform team
+`
+
+	result, err := MigratePolicy([]byte(content))
+	if err != nil {
+		t.Fatalf("MigratePolicy returned an error: %v", err)
+	}
+	if !result.Upgraded() || result.FromVersion != 0 || result.ToVersion != CurrentVersion {
+		t.Errorf("Expected an upgrade from version 0 to %d, got %+v", CurrentVersion, result)
+	}
+	if len(result.Changes) != CurrentVersion {
+		t.Errorf("Expected one change per version, got %v", result.Changes)
+	}
+
+	migrated := string(result.Content)
+	if !strings.HasPrefix(migrated, "# Organization policy\nversion: 1\n") {
+		t.Errorf("Expected the version first, below the file's comment, got:\n%s", migrated)
+	}
+	if !strings.Contains(migrated, "# pinned by the platform team") {
+		t.Errorf("Expected comments to be kept, got:\n%s", migrated)
+	}
+
+	config, err := ParsePolicyConfig(result.Content)
+	if err != nil {
+		t.Fatalf("Migrated policy does not parse: %v", err)
+	}
+	if config.Version != CurrentVersion || len(config.AllowedActions) != 1 {
+		t.Errorf("Unexpected migrated policy %+v", config)
+	}
+
+	// Migrating again changes nothing
+	again, err := MigratePolicy(result.Content)
+	if err != nil {
+		t.Fatalf("MigratePolicy returned an error: %v", err)
+	}
+	if again.Upgraded() || string(again.Content) != migrated {
+		t.Errorf("Expected a current policy to be left unchanged, got %+v", again)
+	}
+}
+
+func TestMigratePolicyErrors(t *testing.T) {
+	tests := map[string]string{
+		"newer version":   "version: 99\npolicy_mode: allow\n",
+		"invalid version": "version: latest\n",
+		"not a mapping":   "- actions/checkout@v4\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := MigratePolicy([]byte(content)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestParsePolicyConfigVersion(t *testing.T) {
+	legacy, err := ParsePolicyConfig([]byte("policy_mode: deny\n"))
+	if err != nil {
+		t.Fatalf("Expected a policy without a version to parse: %v", err)
+	}
+	if legacy.Version != 0 {
+		t.Errorf("Expected version 0 for a legacy policy, got %d", legacy.Version)
+	}
+
+	if _, err := ParsePolicyConfig([]byte("version: 99\n")); err == nil || !strings.Contains(err.Error(), "upgrade action-control") {
+		t.Errorf("Expected an error for a newer policy version, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// DefaultPolicyFile is the local policy file enforce reads when no policy file is given
+const DefaultPolicyFile = "policy.yaml"
+
+// ContentGetter reads a file of a repository
+type ContentGetter interface {
+	GetRepositoryContent(ctx context.Context, owner, repo, path string) ([]byte, error)
+}
+
+// OrgPolicy is a central policy read from an organization's OrgPolicyRepo
+type OrgPolicy struct {
+	Config *PolicyConfig
+	Path   string // Path of the policy file within OrgPolicyRepo
+	Digest string
+}
+
+// DiscoverOrgPolicy reports whether to look for the central policy. requested is the
+// org_policy setting, nil when unset, which decides when given. Otherwise the central policy
+// is only discovered when no policy file is given and DefaultPolicyFile does not exist, so an
+// existing local policy keeps applying.
+func DiscoverOrgPolicy(requested *bool, policyGiven bool) bool {
+	if requested != nil {
+		return *requested
+	}
+	if policyGiven {
+		return false
+	}
+	_, err := os.Stat(DefaultPolicyFile)
+	return os.IsNotExist(err)
+}
+
+// LoadOrgPolicy reads the central policy of an organization at the first of OrgPolicyPaths
+// found in OrgPolicyRepo. It returns nil without error only when none of them exists. Any other
+// error reading or parsing the policy is returned rather than treated as absent, so an
+// unreadable central policy never silently gives way to a local policy that may be looser.
+func LoadOrgPolicy(ctx context.Context, getter ContentGetter, org string) (*OrgPolicy, error) {
+	for _, path := range OrgPolicyPaths {
+		content, err := getter.GetRepositoryContent(ctx, org, OrgPolicyRepo, path)
+		if github.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read central policy %s/%s:%s: %w", org, OrgPolicyRepo, path, err)
+		}
+
+		config, err := ParsePolicyConfig(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid central policy %s/%s:%s: %w", org, OrgPolicyRepo, path, err)
+		}
+		return &OrgPolicy{Config: config, Path: path, Digest: Digest(content)}, nil
+	}
+	return nil, nil
+}
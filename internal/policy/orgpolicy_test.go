@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// orgPolicyServer serves the GitHub contents API of org/.github with the given status and
+// policy content for each path, and 404 for other paths
+func orgPolicyServer(t *testing.T, files map[string]string, status map[string]int) *github.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/org/.github/contents/")
+		if code, ok := status[path]; ok {
+			w.WriteHeader(code)
+			fmt.Fprint(w, `{"message": "error"}`)
+			return
+		}
+		content, ok := files[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"type": "file", "path": %q, "encoding": "base64", "content": %q}`, path, base64.StdEncoding.EncodeToString([]byte(content)))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := github.NewClientForProvider("token", github.ProviderGitHub, server.URL+"/", github.HTTPConfig{})
+	if err != nil {
+		t.Fatalf("NewClientForProvider returned error: %v", err)
+	}
+	return client
+}
+
+func TestLoadOrgPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("found", func(t *testing.T) {
+		content := "allowed_actions: [actions/checkout]\n"
+		client := orgPolicyServer(t, map[string]string{RepoPolicyPath: content}, nil)
+		orgPolicy, err := LoadOrgPolicy(ctx, client, "org")
+		if err != nil {
+			t.Fatalf("LoadOrgPolicy returned error: %v", err)
+		}
+		if orgPolicy == nil || orgPolicy.Path != RepoPolicyPath || orgPolicy.Digest != Digest([]byte(content)) {
+			t.Fatalf("Expected the policy at %s, got %+v", RepoPolicyPath, orgPolicy)
+		}
+		if len(orgPolicy.Config.AllowedActions) != 1 {
+			t.Errorf("Expected the policy to be parsed, got %+v", orgPolicy.Config)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		orgPolicy, err := LoadOrgPolicy(ctx, orgPolicyServer(t, nil, nil), "org")
+		if err != nil || orgPolicy != nil {
+			t.Errorf("Expected no policy and no error, got %+v, %v", orgPolicy, err)
+		}
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		client := orgPolicyServer(t, map[string]string{RepoPolicyPath: "allowed_actions: [x/y]\n"}, map[string]int{OrgPolicyPath: http.StatusForbidden})
+		if _, err := LoadOrgPolicy(ctx, client, "org"); err == nil || !strings.Contains(err.Error(), "failed to read central policy") {
+			t.Errorf("Expected a 403 to be an error rather than a fallback, got %v", err)
+		}
+	})
+
+	t.Run("unparseable", func(t *testing.T) {
+		client := orgPolicyServer(t, map[string]string{OrgPolicyPath: "allowed_actions: [unclosed\n"}, nil)
+		if _, err := LoadOrgPolicy(ctx, client, "org"); err == nil || !strings.Contains(err.Error(), "invalid central policy") {
+			t.Errorf("Expected a parse error, got %v", err)
+		}
+	})
+}
+
+func TestDiscoverOrgPolicy(t *testing.T) {
+	t.Chdir(t.TempDir())
+	yes, no := true, false
+
+	if !DiscoverOrgPolicy(nil, false) {
+		t.Error("Expected discovery without --policy and a local policy file")
+	}
+	if DiscoverOrgPolicy(nil, true) {
+		t.Error("Expected no discovery when --policy is given")
+	}
+	if !DiscoverOrgPolicy(&yes, true) || DiscoverOrgPolicy(&no, false) {
+		t.Error("Expected --org-policy to decide when set")
+	}
+
+	if err := os.WriteFile(DefaultPolicyFile, []byte("allowed_actions: [actions/checkout]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if DiscoverOrgPolicy(nil, false) {
+		t.Errorf("Expected an existing %s to keep applying", DefaultPolicyFile)
+	}
+}
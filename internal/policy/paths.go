@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// PathSelectorSeparator separates the repository from the workflow path glob in the keys of
+// custom rules applying to some workflow files of a repository, such as
+// "org/monorepo:.github/workflows/deploy-*", so the pipelines of a monorepo can have rules of
+// their own
+const PathSelectorSeparator = ":"
+
+// splitPathSelector splits a path-scoped custom rule key into its repository and workflow path
+// glob. ok is false for other keys.
+func splitPathSelector(key string) (repo, glob string, ok bool) {
+	if IsScopeSelector(key) {
+		return "", "", false
+	}
+	repo, glob, ok = strings.Cut(key, PathSelectorSeparator)
+	return repo, glob, ok
+}
+
+// HasPathRules reports whether any custom rule applies to some workflow files of a repository
+func HasPathRules(config *PolicyConfig, repoName string) bool {
+	for key := range config.CustomRules {
+		if repo, _, ok := splitPathSelector(key); ok && repo == repoName {
+			return true
+		}
+	}
+	return false
+}
+
+// pathRuleFor returns the path-scoped custom rule applying to a workflow file of a repository
+// and its key. When several globs match the path, the first in sorted key order applies.
+func pathRuleFor(config *PolicyConfig, repoName, workflowPath string) (string, Policy, bool) {
+	if workflowPath == "" {
+		return "", Policy{}, false
+	}
+
+	keys := make([]string, 0, len(config.CustomRules))
+	for key := range config.CustomRules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		repo, glob, ok := splitPathSelector(key)
+		if !ok || repo != repoName {
+			continue
+		}
+		if matched, _ := path.Match(glob, workflowPath); matched {
+			return key, config.CustomRules[key], true
+		}
+	}
+	return "", Policy{}, false
+}
+
+// ResolveWorkflowPolicy determines the rules applied to a workflow file of a repository. A
+// custom rule keyed by the repository and a glob matching the file's path takes the place of
+// the repository's own rule; other files get the repository's effective policy.
+func ResolveWorkflowPolicy(config *PolicyConfig, repoName, workflowPath string) EffectivePolicy {
+	return resolveEffectivePolicy(config, repoName, workflowPath)
+}
+
+// CheckWorkflowCompliance verifies that actions comply with the policy, each under the rules of
+// the workflow file referencing it and the job rules of its job, and returns the violating
+// references. Without path-scoped custom rules or job rules for the repository it is the same
+// as CheckActionCompliance.
+func CheckWorkflowCompliance(config *PolicyConfig, repoName string, actions []github.Action) []string {
+	uses := make([]string, len(actions))
+	for i, action := range actions {
+		uses[i] = action.Uses
+	}
+	if !HasPathRules(config, repoName) {
+		violations, _ := CheckActionCompliance(config, repoName, uses)
+		return appendJobRuleViolations(config, repoName, actions, violations)
+	}
+
+	// The kill-switch deny list applies to every workflow, excluded or not
+	killSwitched := alwaysDenied(config.AlwaysDeny, uses)
+	violations := append([]string(nil), killSwitched...)
+
+	byWorkflow := make(map[string]EffectivePolicy)
+	for _, action := range actions {
+		if contains(killSwitched, action.Uses) || IsReusableWorkflowCall(config, action.Uses) {
+			continue
+		}
+		effective, ok := byWorkflow[action.Workflow]
+		if !ok {
+			effective = resolveEffectivePolicy(config, repoName, action.Workflow)
+			byWorkflow[action.Workflow] = effective
+		}
+		if !effective.Excluded && violatesLists(effective, action.Uses) {
+			violations = append(violations, action.Uses)
+		}
+	}
+	return appendJobRuleViolations(config, repoName, actions, violations)
+}
+
+// appendJobRuleViolations adds the actions job rules deny, and the lists allow, to violations.
+// Excluded repositories are not checked.
+func appendJobRuleViolations(config *PolicyConfig, repoName string, actions []github.Action, violations []string) []string {
+	if !HasJobRules(config, repoName) || ResolveEffectivePolicy(config, repoName).Excluded {
+		return violations
+	}
+	for _, action := range actions {
+		if contains(violations, action.Uses) || IsReusableWorkflowCall(config, action.Uses) {
+			continue
+		}
+		if _, denied := CheckJobRules(config, repoName, action); denied {
+			violations = append(violations, action.Uses)
+		}
+	}
+	return violations
+}
+
+// validatePathRules checks the workflow path globs of path-scoped custom rule keys
+func validatePathRules(rules map[string]Policy) error {
+	for key := range rules {
+		repo, glob, ok := splitPathSelector(key)
+		if !ok {
+			continue
+		}
+		if strings.Count(repo, "/") != 1 || glob == "" {
+			return fmt.Errorf("invalid custom rule %q, expected owner/repo:workflow-path-glob", key)
+		}
+		if rules[key].When != nil {
+			return fmt.Errorf("custom rule %q applies to workflow files of a repository and can't have a when condition", key)
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid workflow path glob in custom rule %q: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestCheckWorkflowCompliance(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(`
+policy_mode: allow
+allowed_actions: [actions/checkout]
+always_deny: [evil/action]
+custom_rules:
+  org/monorepo:
+    allowed_actions: [actions/checkout, actions/setup-go]
+  "org/monorepo:.github/workflows/deploy-*":
+    allowed_actions: [actions/checkout, aws-actions/configure-aws-credentials]
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicyConfig returned error: %v", err)
+	}
+
+	actions := []github.Action{
+		{Uses: "actions/checkout@v4", Workflow: ".github/workflows/ci.yml"},
+		{Uses: "actions/setup-go@v5", Workflow: ".github/workflows/ci.yml"},
+		{Uses: "aws-actions/configure-aws-credentials@v4", Workflow: ".github/workflows/ci.yml"},
+		{Uses: "actions/checkout@v4", Workflow: ".github/workflows/deploy-prod.yml"},
+		{Uses: "actions/setup-go@v5", Workflow: ".github/workflows/deploy-prod.yml"},
+		{Uses: "aws-actions/configure-aws-credentials@v4", Workflow: ".github/workflows/deploy-prod.yml"},
+		{Uses: "evil/action@v1", Workflow: ".github/workflows/deploy-prod.yml"},
+	}
+	expected := []string{"evil/action@v1", "aws-actions/configure-aws-credentials@v4", "actions/setup-go@v5"}
+	if violations := CheckWorkflowCompliance(config, "org/monorepo", actions); !reflect.DeepEqual(violations, expected) {
+		t.Errorf("CheckWorkflowCompliance() = %v, want %v", violations, expected)
+	}
+
+	// Other repositories are checked as a whole
+	expected = []string{"evil/action@v1", "actions/setup-go@v5", "aws-actions/configure-aws-credentials@v4", "actions/setup-go@v5", "aws-actions/configure-aws-credentials@v4"}
+	if violations := CheckWorkflowCompliance(config, "org/other", actions); !reflect.DeepEqual(violations, expected) {
+		t.Errorf("CheckWorkflowCompliance() = %v, want %v", violations, expected)
+	}
+}
+
+func TestResolveWorkflowPolicy(t *testing.T) {
+	config := &PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"actions/checkout"},
+		CustomRules: map[string]Policy{
+			"org/monorepo": {AllowedActions: []string{"actions/setup-go"}},
+			"org/monorepo:.github/workflows/deploy-*": {PolicyMode: "deny", DeniedActions: []string{"bad/action"}},
+			"org/monorepo:.github/workflows/*":        {AllowedActions: []string{"actions/cache"}},
+		},
+	}
+
+	tests := []struct {
+		workflow   string
+		customRule string
+		mode       string
+	}{
+		{".github/workflows/deploy-prod.yml", "org/monorepo:.github/workflows/*", "allow"},
+		{".github/actions/build/action.yml", "org/monorepo", "allow"},
+		{"", "org/monorepo", "allow"},
+	}
+	for _, tt := range tests {
+		effective := ResolveWorkflowPolicy(config, "org/monorepo", tt.workflow)
+		if effective.CustomRule != tt.customRule || effective.PolicyMode != tt.mode {
+			t.Errorf("ResolveWorkflowPolicy(%q) applied %q in %s mode, want %q in %s mode", tt.workflow, effective.CustomRule, effective.PolicyMode, tt.customRule, tt.mode)
+		}
+	}
+
+	// The first matching key in sorted order applies
+	delete(config.CustomRules, "org/monorepo:.github/workflows/*")
+	if effective := ResolveWorkflowPolicy(config, "org/monorepo", ".github/workflows/deploy-prod.yml"); effective.CustomRule != "org/monorepo:.github/workflows/deploy-*" || effective.PolicyMode != "deny" {
+		t.Errorf("Expected the deploy rule to apply, got %q in %s mode", effective.CustomRule, effective.PolicyMode)
+	}
+	if !HasPathRules(config, "org/monorepo") || HasPathRules(config, "org/other") {
+		t.Error("Expected only org/monorepo to have path-scoped rules")
+	}
+	// Path-scoped rules don't apply to the repository as a whole
+	if effective := ResolveEffectivePolicy(config, "org/monorepo"); effective.CustomRule != "org/monorepo" {
+		t.Errorf("Expected the repository rule to apply to the repository, got %q", effective.CustomRule)
+	}
+}
+
+func TestValidatePathRules(t *testing.T) {
+	tests := []struct {
+		policy string
+		err    string
+	}{
+		{"custom_rules:\n  \"org/repo:.github/workflows/[\": {}\n", "invalid workflow path glob"},
+		{"custom_rules:\n  \"repo:.github/workflows/*\": {}\n", "expected owner/repo:workflow-path-glob"},
+		{"custom_rules:\n  \"org/repo:\": {}\n", "expected owner/repo:workflow-path-glob"},
+		{"custom_rules:\n  \"org/repo:*.yml\":\n    when: {property: team, equals: a}\n", "can't have a when condition"},
+	}
+	for _, tt := range tests {
+		if _, err := ParsePolicyConfig([]byte(tt.policy)); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParsePolicyConfig(%q) error = %v, want %q", tt.policy, err, tt.err)
+		}
+	}
+
+	if _, err := ParsePolicyConfig([]byte("custom_rules:\n  \"topic:frontend\": {}\n  \"org/repo:.github/workflows/deploy-*\": {}\n")); err != nil {
+		t.Errorf("Expected topic selectors and path-scoped rules to be valid, got %v", err)
+	}
+}
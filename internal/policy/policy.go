@@ -1,26 +1,218 @@
 package policy
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/secretscan"
+	"github.com/ihavespoons/action-control/internal/version"
 
 	"gopkg.in/yaml.v3"
 )
 
+// How repository policy files at RepoPolicyPath are treated
+const (
+	RepoPolicyMerge   = "merge"   // Merge them with the global policy (default)
+	RepoPolicyIgnore  = "ignore"  // Ignore them, so only the global policy applies
+	RepoPolicyRequire = "require" // Merge them, and report repositories without a valid one
+)
+
+// How deprecated runner labels and Node.js runtimes are reported
+const (
+	DeprecationsWarn = "warn" // Report them as warnings (default)
+	DeprecationsFail = "fail" // Report them as failures
+	DeprecationsOff  = "off"  // Do not look for them
+)
+
+const (
+	// RepoPolicyPath is the path of a repository's own policy file
+	RepoPolicyPath = ".github/action-control-policy.yaml"
+	// OrgPolicyRepo is the organization repository that holds the central policy by convention
+	OrgPolicyRepo = ".github"
+	// OrgPolicyPath is the path of the central policy file within OrgPolicyRepo
+	OrgPolicyPath = "action-control-policy.yaml"
+)
+
+// OrgPolicyPaths are the paths searched for the central policy within OrgPolicyRepo, in order,
+// like the locations GitHub searches for default community health files
+var OrgPolicyPaths = []string{OrgPolicyPath, RepoPolicyPath}
+
 // PolicyConfig defines the structure for the policy configuration file
 type PolicyConfig struct {
+	// Version is the policy format version the file is written for. Files without one are
+	// read as version 0 and can be upgraded with MigratePolicy.
+	Version int `yaml:"version,omitempty"`
+
 	AllowedActions []string          `yaml:"allowed_actions,omitempty"`
 	DeniedActions  []string          `yaml:"denied_actions,omitempty"`
 	ExcludedRepos  []string          `yaml:"excluded_repos,omitempty"`
 	CustomRules    map[string]Policy `yaml:"custom_rules,omitempty"`
-	PolicyMode     string            `yaml:"policy_mode,omitempty"` // "allow" or "deny"
+	PolicyMode     string            `yaml:"policy_mode,omitempty"` // "allow", "deny" or "mixed"
+
+	// AlwaysDeny lists known-malicious actions denied in every repository, including excluded
+	// repositories, regardless of custom rules or repository overrides
+	AlwaysDeny []string `yaml:"always_deny,omitempty"`
+
+	// AllowedOwners permits every action published by these owners (organizations or users)
+	AllowedOwners []string `yaml:"allowed_owners,omitempty"`
+	// RequireVerifiedCreator reports actions whose owner is not a verified organization,
+	// unless the action or its owner is explicitly allowed
+	RequireVerifiedCreator bool `yaml:"require_verified_creator,omitempty"`
+	// RequireActionsUpdates reports repositories without Dependabot or Renovate configured to
+	// update their actions
+	RequireActionsUpdates bool `yaml:"require_actions_updates,omitempty"`
+	// RequireWorkflowProtection reports repositories whose workflow files can be changed without
+	// review, since enforcement is moot if anyone can edit workflows unreviewed
+	RequireWorkflowProtection bool `yaml:"require_workflow_protection,omitempty"`
+	// WorkflowOwners are the CODEOWNERS owners, such as "@org/security", at least one of which
+	// must own every workflow file so that workflow changes request the right reviewers
+	WorkflowOwners []string `yaml:"workflow_owners,omitempty"`
+
+	// Upkeep thresholds for the repositories publishing actions. DenyArchivedActions reports
+	// actions from archived repositories; MaxStalenessDays reports actions from repositories
+	// not pushed to within that many days.
+	DenyArchivedActions bool `yaml:"deny_archived_actions,omitempty"`
+	MaxStalenessDays    int  `yaml:"max_staleness_days,omitempty"`
+
+	// Supply-chain heuristics for look-alike actions. DenyForkActions reports actions hosted in
+	// forks; MinRepositoryAgeDays reports actions from repositories created more recently.
+	DenyForkActions      bool `yaml:"deny_fork_actions,omitempty"`
+	MinRepositoryAgeDays int  `yaml:"min_repository_age_days,omitempty"`
+
+	// DetectMissingActions reports actions whose repository was deleted, made private or
+	// renamed, or no longer has the referenced ref. These break builds, and a name freed by a
+	// deletion or rename can be registered by someone else.
+	DetectMissingActions bool `yaml:"detect_missing_actions,omitempty"`
+	// DetectNamespaceTakeover reports actions still referencing an owner account that was
+	// deleted or renamed, whose abandoned name can be claimed to serve malicious code
+	DetectNamespaceTakeover bool `yaml:"detect_namespace_takeover,omitempty"`
+
+	// DetectTyposquatting reports actions whose names closely resemble a popular action or one
+	// of KnownActions, such as "actions/chekout"
+	DetectTyposquatting bool     `yaml:"detect_typosquatting,omitempty"`
+	KnownActions        []string `yaml:"known_actions,omitempty"`
+
+	// DetectArtifactMisuse reports caches saved by pull_request_target and workflow_run
+	// workflows, artifacts of other runs downloaded by them, and artifacts uploading the
+	// workspace of a job whose checkout persists its token
+	DetectArtifactMisuse bool `yaml:"detect_artifact_misuse,omitempty"`
+
+	// MinVersions sets the oldest version of an action, by owner/repo, that workflows may use,
+	// such as {actions/checkout: v4}, checked in every policy mode
+	MinVersions map[string]string `yaml:"min_versions,omitempty"`
+
+	// InputRules constrain the inputs of the steps using an action, such as requiring
+	// egress-policy: block for step-security/harden-runner
+	InputRules []InputRule `yaml:"input_rules,omitempty"`
+
+	// JobRules restrict the actions of the workflows and jobs they match, such as denying
+	// third-party actions in release jobs or jobs granted id-token: write
+	JobRules []JobRule `yaml:"job_rules,omitempty"`
+
+	// RequiredActions are the actions every matching repository's workflows must use, such as a
+	// security scan or license check mandated for compliance
+	RequiredActions []RequiredAction `yaml:"required_actions,omitempty"`
+	// RequiredWorkflows are the workflow files, or workflows running on an event for the default
+	// branch, every matching repository must have, such as CodeQL or pull request CI
+	RequiredWorkflows []RequiredWorkflow `yaml:"required_workflows,omitempty"`
+
+	// Workflow hygiene checks. DetectContinueOnError reports security-relevant steps, and the
+	// jobs running them, whose failure continue-on-error ignores; SecurityActions adds to the
+	// actions counted as security-relevant. RequireJobTimeouts reports jobs without
+	// timeout-minutes.
+	DetectContinueOnError bool     `yaml:"detect_continue_on_error,omitempty"`
+	SecurityActions       []string `yaml:"security_actions,omitempty"`
+	RequireJobTimeouts    bool     `yaml:"require_job_timeouts,omitempty"`
+
+	// Container image rules for job containers and services. Images matching DeniedImages are
+	// reported; when AllowedImages or AllowedRegistries are set, other images are reported too.
+	AllowedImages     []string `yaml:"allowed_images,omitempty"`
+	DeniedImages      []string `yaml:"denied_images,omitempty"`
+	AllowedRegistries []string `yaml:"allowed_registries,omitempty"`
+
+	// Runtime rules, checked against each action's action.yml. DenyDockerActionsFromUnknownRegistries
+	// reports Docker actions whose image, or the base images of their Dockerfile, come from a
+	// registry not in AllowedRegistries; DenyNode16Actions reports JavaScript actions running on
+	// a deprecated Node.js runtime (node16 or node12).
+	DenyDockerActionsFromUnknownRegistries bool `yaml:"deny_docker_actions_from_unknown_registries,omitempty"`
+	DenyNode16Actions                      bool `yaml:"deny_node16_actions,omitempty"`
+
+	// Reusable workflow rules for job-level uses, checked instead of the action lists once set.
+	// Calls matching DeniedReusableWorkflows are reported; when AllowedReusableWorkflows is set,
+	// calls not matching it are reported too. Patterns are owner/repo or owner/repo/path with an
+	// optional @ref, and support globs.
+	AllowedReusableWorkflows []string `yaml:"allowed_reusable_workflows,omitempty"`
+	DeniedReusableWorkflows  []string `yaml:"denied_reusable_workflows,omitempty"`
+
+	// Deprecations controls how jobs on retired runner labels and actions on deprecated Node.js
+	// runtimes are reported: "warn" (default), "fail", or "off" to skip looking for them
+	Deprecations string `yaml:"deprecations,omitempty"`
+
+	// CloudAccess restricts which repositories may assume which cloud identities
+	CloudAccess []CloudAccessRule `yaml:"cloud_access,omitempty"`
+
+	// scopedRepos maps team and topic custom rule keys to the repositories they select,
+	// as resolved by ResolveScopes
+	scopedRepos map[string][]string
+
+	// RepoPolicy controls whether repository policy files are honored: "merge" (default),
+	// "ignore", or "require", which also reports repositories without one
+	RepoPolicy string `yaml:"repo_policy,omitempty"`
+
+	// MergeStrategy decides what happens when a repository policy file conflicts with the
+	// global policy: "permissive" (default) applies the repository rule and reports the
+	// conflicts, "strict" rejects the repository rule. AllowRepoOverrides set to false only
+	// lets repository policy files tighten the global policy.
+	MergeStrategy      string `yaml:"merge_strategy,omitempty"`
+	AllowRepoOverrides *bool  `yaml:"allow_repo_overrides,omitempty"`
+
+	// MinToolVersion is the oldest action-control release allowed to evaluate this policy
+	MinToolVersion   string `yaml:"min_tool_version,omitempty"`
+	ToolVersionCheck string `yaml:"tool_version_check,omitempty"` // "fail" (default) or "warn"
+}
+
+// CloudAccessRule permits repositories matching Repos to assume cloud identities matching
+// Identity (an AWS role ARN, GCP workload identity provider or service account, or Azure
+// client ID). Both support glob patterns.
+type CloudAccessRule struct {
+	Provider string   `yaml:"provider"` // "aws", "gcp" or "azure"
+	Identity string   `yaml:"identity"`
+	Repos    []string `yaml:"repos"`
 }
 
 // Policy defines repository-specific policy
 type Policy struct {
 	AllowedActions []string `yaml:"allowed_actions,omitempty"`
 	DeniedActions  []string `yaml:"denied_actions,omitempty"`
-	PolicyMode     string   `yaml:"policy_mode,omitempty"` // "allow" or "deny"
+	PolicyMode     string   `yaml:"policy_mode,omitempty"` // "allow", "deny" or "mixed"
+
+	// When applies the rule to every repository whose custom properties match,
+	// instead of the repository named by the rule's key
+	When *Condition `yaml:"when,omitempty"`
+}
+
+// Condition matches repositories by the value of a GitHub repository custom property
+type Condition struct {
+	Property string   `yaml:"property"`
+	Equals   string   `yaml:"equals,omitempty"`
+	In       []string `yaml:"in,omitempty"` // Matches any of the listed values
+}
+
+// Matches reports whether a repository's property values satisfy the condition.
+// Multi-select properties match when any selected value does.
+func (c *Condition) Matches(properties map[string][]string) bool {
+	for _, value := range properties[c.Property] {
+		if (c.Equals != "" && value == c.Equals) || contains(c.In, value) {
+			return true
+		}
+	}
+	return false
 }
 
 // LoadPolicyConfig loads policy configuration from the specified file
@@ -30,6 +222,15 @@ func LoadPolicyConfig(configPath string) (*PolicyConfig, error) {
 		return nil, fmt.Errorf("failed to read policy config: %w", err)
 	}
 
+	return ParsePolicyConfig(data)
+}
+
+// ParsePolicyConfig parses policy configuration from raw YAML content
+func ParsePolicyConfig(data []byte) (*PolicyConfig, error) {
+	if err := checkSecrets(data); err != nil {
+		return nil, err
+	}
+
 	var config PolicyConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse policy config: %w", err)
@@ -46,57 +247,143 @@ func LoadPolicyConfig(configPath string) (*PolicyConfig, error) {
 		}
 	}
 
+	if config.Version < 0 || config.Version > CurrentVersion {
+		return nil, fmt.Errorf("unsupported policy version %d, this release supports versions up to %d: upgrade action-control", config.Version, CurrentVersion)
+	}
+
+	if !validPolicyMode(config.PolicyMode) {
+		return nil, fmt.Errorf("invalid policy_mode %q, expected allow, deny or mixed", config.PolicyMode)
+	}
+	rules := make([]string, 0, len(config.CustomRules))
+	for name := range config.CustomRules {
+		rules = append(rules, name)
+	}
+	sort.Strings(rules)
+	for _, name := range rules {
+		if mode := config.CustomRules[name].PolicyMode; mode != "" && !validPolicyMode(mode) {
+			return nil, fmt.Errorf("invalid policy_mode %q in custom rule %q, expected allow, deny or mixed", mode, name)
+		}
+	}
+
+	switch config.RepoPolicy {
+	case "", RepoPolicyMerge, RepoPolicyIgnore, RepoPolicyRequire:
+	default:
+		return nil, fmt.Errorf("invalid repo_policy %q, expected %s, %s or %s", config.RepoPolicy, RepoPolicyMerge, RepoPolicyIgnore, RepoPolicyRequire)
+	}
+
+	switch config.MergeStrategy {
+	case "", MergeStrict, MergePermissive:
+	default:
+		return nil, fmt.Errorf("invalid merge_strategy %q, expected %s or %s", config.MergeStrategy, MergeStrict, MergePermissive)
+	}
+
+	switch config.Deprecations {
+	case "", DeprecationsWarn, DeprecationsFail, DeprecationsOff:
+	default:
+		return nil, fmt.Errorf("invalid deprecations %q, expected %s, %s or %s", config.Deprecations, DeprecationsWarn, DeprecationsFail, DeprecationsOff)
+	}
+
+	if err := validateWorkflowPatterns("allowed_reusable_workflows", config.AllowedReusableWorkflows); err != nil {
+		return nil, err
+	}
+	if err := validateWorkflowPatterns("denied_reusable_workflows", config.DeniedReusableWorkflows); err != nil {
+		return nil, err
+	}
+
+	if err := validateMinVersions(config.MinVersions); err != nil {
+		return nil, err
+	}
+	for _, pattern := range config.SecurityActions {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("invalid security_actions pattern %q", pattern)
+		}
+	}
+
+	if err := validatePathRules(config.CustomRules); err != nil {
+		return nil, err
+	}
+	if err := validateInputRules(config.InputRules); err != nil {
+		return nil, err
+	}
+	if err := validateJobRules(config.JobRules); err != nil {
+		return nil, err
+	}
+	if err := validateRequiredActions(config.RequiredActions); err != nil {
+		return nil, err
+	}
+	if err := validateRequiredWorkflows(config.RequiredWorkflows); err != nil {
+		return nil, err
+	}
+
+	if config.DenyDockerActionsFromUnknownRegistries && len(config.AllowedRegistries) == 0 {
+		return nil, fmt.Errorf("deny_docker_actions_from_unknown_registries requires allowed_registries")
+	}
+
 	return &config, nil
 }
 
-// MergeRepoPolicy merges repository-specific policy with global policy
-func MergeRepoPolicy(globalPolicy *PolicyConfig, repoPolicyContent []byte, repoName string) (*PolicyConfig, error) {
-	// Create a deep copy of the global policy
-	mergedPolicy := &PolicyConfig{
-		AllowedActions: make([]string, len(globalPolicy.AllowedActions)),
-		DeniedActions:  make([]string, len(globalPolicy.DeniedActions)),
-		ExcludedRepos:  make([]string, len(globalPolicy.ExcludedRepos)),
-		CustomRules:    make(map[string]Policy),
-		PolicyMode:     globalPolicy.PolicyMode,
-	}
-
-	// Copy slices and map
-	copy(mergedPolicy.AllowedActions, globalPolicy.AllowedActions)
-	copy(mergedPolicy.DeniedActions, globalPolicy.DeniedActions)
-	copy(mergedPolicy.ExcludedRepos, globalPolicy.ExcludedRepos)
-	for k, v := range globalPolicy.CustomRules {
-		mergedPolicy.CustomRules[k] = v
-	}
-
-	// Parse repo policy
-	var repoPolicy PolicyConfig
-	if err := yaml.Unmarshal(repoPolicyContent, &repoPolicy); err != nil {
-		return nil, fmt.Errorf("failed to parse repository policy: %w", err)
-	}
-
-	// Apply repo-specific overrides if provided
-	customRule, exists := repoPolicy.CustomRules[repoName]
-	if exists {
-		mergedPolicy.CustomRules[repoName] = customRule
-	} else if len(repoPolicy.AllowedActions) > 0 || len(repoPolicy.DeniedActions) > 0 {
-		// If repo doesn't have a specific custom rule but has global actions,
-		// create a custom rule for it
-		policy := Policy{
-			AllowedActions: repoPolicy.AllowedActions,
-			DeniedActions:  repoPolicy.DeniedActions,
-		}
-
-		// Set policy mode for the repo if specified, otherwise inherit
-		if repoPolicy.PolicyMode != "" {
-			policy.PolicyMode = repoPolicy.PolicyMode
-		} else {
-			policy.PolicyMode = determineRepoMode(policy, globalPolicy.PolicyMode)
-		}
+// checkSecrets refuses policies that appear to embed credentials, as policies are meant to be
+// committed and shared. A policy needs no credentials: action-control reads them from its
+// configuration.
+func checkSecrets(data []byte) error {
+	if findings := secretscan.Scan(data); len(findings) > 0 {
+		return fmt.Errorf("policy appears to contain secrets (%s): remove them from the policy and rotate them", secretscan.Describe(findings))
+	}
+	return nil
+}
+
+// CheckToolVersion verifies that the running binary satisfies the policy's minimum tool version.
+// Development builds cannot be compared and are always accepted.
+func CheckToolVersion(config *PolicyConfig) error {
+	if config.MinToolVersion == "" || !version.IsRelease() {
+		return nil
+	}
 
-		mergedPolicy.CustomRules[repoName] = policy
+	ok, err := version.AtLeast(config.MinToolVersion)
+	if err != nil {
+		return fmt.Errorf("invalid min_tool_version in policy: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("policy requires action-control %s or newer, running %s", config.MinToolVersion, version.Version)
 	}
 
-	return mergedPolicy, nil
+	return nil
+}
+
+// Digest returns the hex-encoded SHA-256 digest of raw policy content
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ConfigDigest returns the hex-encoded SHA-256 digest of a parsed policy configuration
+func ConfigDigest(config *PolicyConfig) string {
+	data, _ := yaml.Marshal(config)
+	return Digest(data)
+}
+
+// SavePolicyConfig writes policy configuration to the specified file
+func SavePolicyConfig(configPath string, config *PolicyConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy config: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write policy config: %w", err)
+	}
+
+	return nil
+}
+
+// MergeRepoPolicy merges repository-specific policy with global policy, as MergePolicies
+// does, discarding the conflicts it reports
+func MergeRepoPolicy(globalPolicy *PolicyConfig, repoPolicyContent []byte, repoName string) (*PolicyConfig, error) {
+	result, err := MergePolicies(globalPolicy, repoPolicyContent, repoName)
+	if err != nil {
+		return nil, err
+	}
+	return result.Policy, nil
 }
 
 // determineRepoMode figures out the appropriate policy mode for a repository
@@ -116,73 +403,161 @@ func determineRepoMode(policy Policy, defaultMode string) string {
 
 // CheckActionCompliance verifies that all actions comply with the policy
 func CheckActionCompliance(policy *PolicyConfig, repoName string, actions []string) ([]string, bool) {
-	// Check if repository is excluded from policy
-	for _, excludedRepo := range policy.ExcludedRepos {
-		if excludedRepo == repoName {
-			return nil, true // Repository is excluded, so it's compliant
+	// The kill-switch deny list applies to every repository, excluded or not
+	killSwitched := alwaysDenied(policy.AlwaysDeny, actions)
+	violations := append([]string(nil), killSwitched...)
+
+	// Resolve the rules that apply to this repository
+	effective := ResolveEffectivePolicy(policy, repoName)
+	if effective.Excluded {
+		return violations, len(violations) == 0 // Excluded repositories only honor always_deny
+	}
+
+	// Check actions against policy, skipping actions already reported by always_deny and
+	// reusable workflows covered by their own rules
+	for _, actionWithVersion := range actions {
+		if contains(killSwitched, actionWithVersion) || IsReusableWorkflowCall(policy, actionWithVersion) {
+			continue
+		}
+		if violatesLists(effective, actionWithVersion) {
+			violations = append(violations, actionWithVersion)
 		}
 	}
 
-	// Determine which policy to apply (global or custom)
-	var allowedActions, deniedActions []string
-	var policyMode string
+	return violations, len(violations) == 0
+}
 
-	if customPolicy, exists := policy.CustomRules[repoName]; exists {
-		// Use custom policy for this repository
-		allowedActions = customPolicy.AllowedActions
-		deniedActions = customPolicy.DeniedActions
-		policyMode = customPolicy.PolicyMode
+// validPolicyMode reports whether a policy_mode is one the action lists can be enforced in
+func validPolicyMode(mode string) bool {
+	switch mode {
+	case "allow", "deny", "mixed":
+		return true
+	}
+	return false
+}
 
-		// If custom policy mode is not specified, inherit from global
-		if policyMode == "" {
-			policyMode = policy.PolicyMode
-		}
+// violatesLists reports whether an action violates the allow and deny lists of an effective
+// policy's mode
+func violatesLists(effective EffectivePolicy, actionWithVersion string) bool {
+	allowedActions := effective.AllowedActions
+	deniedActions := effective.DeniedActions
+	allowedOwners := effective.AllowedOwners
+
+	// Normalize actions by removing version info for policy checking
+	action := normalizeAction(actionWithVersion)
+
+	explicitlyAllowed := contains(allowedActions, action) || contains(allowedActions, actionWithVersion)
+	owner := ActionOwner(actionWithVersion)
+	ownerAllowed := owner != "" && contains(allowedOwners, owner)
 
-		// If custom policy doesn't specify actions for its mode, inherit from global
-		if policyMode == "allow" && len(allowedActions) == 0 {
-			allowedActions = policy.AllowedActions
-		} else if policyMode == "deny" && len(deniedActions) == 0 {
-			deniedActions = policy.DeniedActions
+	switch effective.PolicyMode {
+	case "allow":
+		// In allow mode, action must be in the allowed list or published by an allowed owner
+		return !explicitlyAllowed && !ownerAllowed
+	case "deny":
+		// In deny mode, action must NOT be in the denied list
+		if contains(deniedActions, action) || contains(deniedActions, actionWithVersion) {
+			return true
+		}
+		// When owners are restricted, other publishers need an explicit allow entry
+		return len(allowedOwners) > 0 && owner != "" && !ownerAllowed && !explicitlyAllowed
+	case "mixed":
+		// In mixed mode, action must be allowed and the deny list overrides the allow list
+		if contains(deniedActions, action) || contains(deniedActions, actionWithVersion) {
+			return true
 		}
-	} else {
-		// Use global policy
-		allowedActions = policy.AllowedActions
-		deniedActions = policy.DeniedActions
-		policyMode = policy.PolicyMode
+		return !explicitlyAllowed && !ownerAllowed
+	}
+	return false
+}
+
+// CheckCloudAccess reports whether a repository may assume a cloud identity.
+// When the policy has no cloud_access rules, all access is permitted.
+func CheckCloudAccess(config *PolicyConfig, repoName, provider, identity string) bool {
+	if len(config.CloudAccess) == 0 {
+		return true
 	}
 
-	// Default to allow mode if not specified
-	if policyMode == "" {
-		if len(allowedActions) > 0 {
-			policyMode = "allow"
-		} else if len(deniedActions) > 0 {
-			policyMode = "deny"
-		} else {
-			policyMode = "allow" // Default fallback
+	for _, rule := range config.CloudAccess {
+		if rule.Provider != provider {
+			continue
+		}
+		if ok, _ := path.Match(rule.Identity, identity); !ok {
+			continue
+		}
+		for _, repo := range rule.Repos {
+			if ok, _ := path.Match(repo, repoName); ok {
+				return true
+			}
 		}
 	}
 
-	// Check actions against policy
-	var violations []string
+	return false
+}
 
-	// Normalize actions by removing version info for policy checking
+// CreatorVerifier reports whether an action owner is a verified creator
+type CreatorVerifier interface {
+	IsVerifiedCreator(ctx context.Context, owner string) (bool, error)
+}
+
+// CheckVerifiedCreators returns the actions whose owner is not a verified creator when the
+// policy sets require_verified_creator. Actions that are explicitly allowed, published by an
+// allowed owner, or local to the repository are not checked. Owners that cannot be verified
+// because of an error are treated as unverified.
+func CheckVerifiedCreators(ctx context.Context, config *PolicyConfig, repoName string, actions []string, verifier CreatorVerifier) []string {
+	if !config.RequireVerifiedCreator {
+		return nil
+	}
+
+	effective := ResolveEffectivePolicy(config, repoName)
+	if effective.Excluded {
+		return nil
+	}
+
+	var violations []string
 	for _, actionWithVersion := range actions {
-		action := normalizeAction(actionWithVersion)
+		owner := ActionOwner(actionWithVersion)
+		if owner == "" || contains(effective.AllowedOwners, owner) {
+			continue
+		}
+		if contains(effective.AllowedActions, normalizeAction(actionWithVersion)) || contains(effective.AllowedActions, actionWithVersion) {
+			continue
+		}
 
-		if policyMode == "allow" {
-			// In allow mode, action must be in the allowed list
-			if !contains(allowedActions, action) && !contains(allowedActions, actionWithVersion) {
+		if verified, err := verifier.IsVerifiedCreator(ctx, owner); err != nil || !verified {
+			if !contains(violations, actionWithVersion) {
 				violations = append(violations, actionWithVersion)
 			}
-		} else if policyMode == "deny" {
-			// In deny mode, action must NOT be in the denied list
-			if contains(deniedActions, action) || contains(deniedActions, actionWithVersion) {
+		}
+	}
+
+	return violations
+}
+
+// ActionOwner returns the owner of an action reference such as "actions/checkout@v4".
+// Local actions and container images have no owner and return an empty string.
+func ActionOwner(action string) string {
+	if strings.HasPrefix(action, "./") || strings.HasPrefix(action, "docker://") {
+		return ""
+	}
+	owner, _, found := strings.Cut(normalizeAction(action), "/")
+	if !found {
+		return ""
+	}
+	return owner
+}
+
+// alwaysDenied returns the actions that appear on the always_deny list
+func alwaysDenied(alwaysDeny []string, actions []string) []string {
+	var violations []string
+	for _, actionWithVersion := range actions {
+		if contains(alwaysDeny, normalizeAction(actionWithVersion)) || contains(alwaysDeny, actionWithVersion) {
+			if !contains(violations, actionWithVersion) {
 				violations = append(violations, actionWithVersion)
 			}
 		}
 	}
-
-	return violations, len(violations) == 0
+	return violations
 }
 
 // normalizeAction removes version info from action string
@@ -1,10 +1,16 @@
 package policy
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/version"
 )
 
 func TestLoadPolicyConfig(t *testing.T) {
@@ -459,3 +465,948 @@ custom_rules:
 		}
 	})
 }
+
+func TestParsePolicyConfig(t *testing.T) {
+	content := []byte(`
+denied_actions:
+  - bad/action
+`)
+
+	config, err := ParsePolicyConfig(content)
+	if err != nil {
+		t.Fatalf("ParsePolicyConfig returned error: %v", err)
+	}
+
+	if config.PolicyMode != "deny" {
+		t.Errorf("Expected policy mode to be inferred as 'deny', got %q", config.PolicyMode)
+	}
+
+	if _, err := ParsePolicyConfig([]byte("allowed_actions: [")); err == nil {
+		t.Error("Expected error for invalid YAML, got nil")
+	}
+
+	secret := []byte("allowed_actions:\n  - actions/checkout\nwebhook: https://hooks.slack.com/services/T000/B000/XXXX\n")
+	if _, err := ParsePolicyConfig(secret); err == nil || !strings.Contains(err.Error(), "Slack webhook URL on line 3") {
+		t.Errorf("Expected an error for a policy embedding a secret, got %v", err)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	// SHA-256 of the empty string
+	expected := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if got := Digest(nil); got != expected {
+		t.Errorf("Expected digest %q, got %q", expected, got)
+	}
+
+	if Digest([]byte("a")) == Digest([]byte("b")) {
+		t.Error("Expected different content to produce different digests")
+	}
+}
+
+func TestConfigDigest(t *testing.T) {
+	a := &PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+	b := &PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+	if ConfigDigest(a) != ConfigDigest(b) {
+		t.Error("Expected equal configurations to have equal digests")
+	}
+
+	b.AllowedActions = append(b.AllowedActions, "actions/setup-go")
+	if ConfigDigest(a) == ConfigDigest(b) {
+		t.Error("Expected different configurations to have different digests")
+	}
+}
+
+func TestCheckToolVersion(t *testing.T) {
+	original := version.Version
+	defer func() { version.Version = original }()
+
+	config := &PolicyConfig{MinToolVersion: "1.4.0"}
+
+	version.Version = "v1.3.0"
+	if err := CheckToolVersion(config); err == nil {
+		t.Error("Expected error for outdated binary, got nil")
+	}
+
+	version.Version = "v1.4.0"
+	if err := CheckToolVersion(config); err != nil {
+		t.Errorf("Expected no error for matching version, got %v", err)
+	}
+
+	version.Version = "dev"
+	if err := CheckToolVersion(config); err != nil {
+		t.Errorf("Expected development builds to be accepted, got %v", err)
+	}
+}
+
+func TestSavePolicyConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	config := &PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"actions/checkout"},
+	}
+
+	if err := SavePolicyConfig(path, config); err != nil {
+		t.Fatalf("SavePolicyConfig returned error: %v", err)
+	}
+
+	loaded, err := LoadPolicyConfig(path)
+	if err != nil {
+		t.Fatalf("LoadPolicyConfig returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, config) {
+		t.Errorf("Expected saved policy %+v, got %+v", config, loaded)
+	}
+}
+
+func TestResolveEffectivePolicy(t *testing.T) {
+	config := &PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"actions/checkout", "actions/setup-go"},
+		ExcludedRepos:  []string{"org/sandbox"},
+		CustomRules: map[string]Policy{
+			"org/special": {PolicyMode: "deny", DeniedActions: []string{"bad/action"}},
+		},
+	}
+
+	global := ResolveEffectivePolicy(config, "org/regular")
+	if !reflect.DeepEqual(global.Layers, []string{LayerGlobal}) || global.PolicyMode != "allow" {
+		t.Errorf("Unexpected effective policy for regular repo: %+v", global)
+	}
+
+	custom := ResolveEffectivePolicy(config, "org/special")
+	if !reflect.DeepEqual(custom.Layers, []string{LayerGlobal, LayerCustomRule}) || custom.PolicyMode != "deny" {
+		t.Errorf("Unexpected effective policy for custom repo: %+v", custom)
+	}
+
+	excluded := ResolveEffectivePolicy(config, "org/sandbox")
+	if !excluded.Excluded || !reflect.DeepEqual(excluded.Layers, []string{LayerGlobal, LayerExcluded}) {
+		t.Errorf("Unexpected effective policy for excluded repo: %+v", excluded)
+	}
+
+	// Digests differ between rule sets but ignore list ordering
+	if global.Digest == custom.Digest || global.Digest == excluded.Digest {
+		t.Error("Expected different rule sets to have different digests")
+	}
+	reordered := &PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/setup-go", "actions/checkout"}}
+	if ResolveEffectivePolicy(reordered, "org/regular").Digest != global.Digest {
+		t.Error("Expected digest to be independent of action order")
+	}
+}
+
+func TestAlwaysDeny(t *testing.T) {
+	config := &PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"actions/checkout", "evil/action"},
+		AlwaysDeny:     []string{"evil/action"},
+		ExcludedRepos:  []string{"org/excluded"},
+		CustomRules: map[string]Policy{
+			"org/custom": {PolicyMode: "deny", DeniedActions: []string{"other/action"}},
+		},
+	}
+
+	actions := []string{"actions/checkout@v4", "evil/action@v1"}
+
+	for _, repo := range []string{"org/regular", "org/excluded", "org/custom"} {
+		violations, compliant := CheckActionCompliance(config, repo, actions)
+		if compliant || !reflect.DeepEqual(violations, []string{"evil/action@v1"}) {
+			t.Errorf("%s: expected always_deny violation, got %v (compliant: %v)", repo, violations, compliant)
+		}
+	}
+
+	// Repository overrides cannot remove the kill-switch entry
+	merged, err := MergeRepoPolicy(config, []byte(`
+always_deny: []
+allowed_actions:
+  - evil/action
+`), "org/override")
+	if err != nil {
+		t.Fatalf("MergeRepoPolicy returned error: %v", err)
+	}
+	if _, compliant := CheckActionCompliance(merged, "org/override", actions); compliant {
+		t.Error("Expected repository override not to bypass always_deny")
+	}
+}
+
+func TestCheckCloudAccess(t *testing.T) {
+	// Without rules every repository may assume any identity
+	if !CheckCloudAccess(&PolicyConfig{}, "org/any", "aws", "arn:aws:iam::1:role/admin") {
+		t.Error("Expected cloud access to be unrestricted without rules")
+	}
+
+	config := &PolicyConfig{
+		CloudAccess: []CloudAccessRule{
+			{Provider: "aws", Identity: "arn:aws:iam::123456789012:role/deploy-*", Repos: []string{"org/service-*"}},
+			{Provider: "gcp", Identity: "projects/1/locations/global/workloadIdentityPools/ci/providers/github", Repos: []string{"org/infra"}},
+		},
+	}
+
+	tests := []struct {
+		repo, provider, identity string
+		expected                 bool
+	}{
+		{"org/service-api", "aws", "arn:aws:iam::123456789012:role/deploy-api", true},
+		{"org/website", "aws", "arn:aws:iam::123456789012:role/deploy-api", false},
+		{"org/service-api", "aws", "arn:aws:iam::123456789012:role/admin", false},
+		{"org/infra", "gcp", "projects/1/locations/global/workloadIdentityPools/ci/providers/github", true},
+		{"org/infra", "azure", "00000000-0000-0000-0000-000000000000", false},
+	}
+
+	for _, tt := range tests {
+		if got := CheckCloudAccess(config, tt.repo, tt.provider, tt.identity); got != tt.expected {
+			t.Errorf("CheckCloudAccess(%s, %s, %s) = %v, expected %v", tt.repo, tt.provider, tt.identity, got, tt.expected)
+		}
+	}
+}
+
+func TestAllowedOwners(t *testing.T) {
+	actions := []string{"actions/checkout@v4", "my-org/deploy@v1", "random/action@v2", "./local-action"}
+
+	allowConfig := &PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"./local-action"},
+		AllowedOwners:  []string{"actions", "my-org"},
+	}
+	violations, compliant := CheckActionCompliance(allowConfig, "org/repo", actions)
+	if compliant || !reflect.DeepEqual(violations, []string{"random/action@v2"}) {
+		t.Errorf("allow mode: expected only random/action to violate, got %v", violations)
+	}
+
+	denyConfig := &PolicyConfig{
+		PolicyMode:     "deny",
+		DeniedActions:  []string{"my-org/deploy"},
+		AllowedActions: []string{"random/action"},
+		AllowedOwners:  []string{"actions", "my-org"},
+	}
+	violations, _ = CheckActionCompliance(denyConfig, "org/repo", append(actions, "other/action@v1"))
+	if !reflect.DeepEqual(violations, []string{"my-org/deploy@v1", "other/action@v1"}) {
+		t.Errorf("deny mode: expected denied action and unlisted owner to violate, got %v", violations)
+	}
+
+	if owner := ActionOwner("org/repo/.github/workflows/build.yml@main"); owner != "org" {
+		t.Errorf("Expected owner org for reusable workflow, got %q", owner)
+	}
+	if owner := ActionOwner("docker://alpine:3"); owner != "" {
+		t.Errorf("Expected no owner for container image, got %q", owner)
+	}
+}
+
+type fakeVerifier map[string]bool
+
+func (f fakeVerifier) IsVerifiedCreator(ctx context.Context, owner string) (bool, error) {
+	verified, ok := f[owner]
+	if !ok {
+		return false, errors.New("lookup failed")
+	}
+	return verified, nil
+}
+
+func TestCheckVerifiedCreators(t *testing.T) {
+	verifier := fakeVerifier{"actions": true, "someone": false}
+	actions := []string{"actions/checkout@v4", "someone/action@v1", "trusted/action@v1", "listed/action@v2", "unknown/action@v1", "./local"}
+
+	// Disabled unless required
+	if violations := CheckVerifiedCreators(context.Background(), &PolicyConfig{}, "org/repo", actions, verifier); violations != nil {
+		t.Errorf("Expected no violations without require_verified_creator, got %v", violations)
+	}
+
+	config := &PolicyConfig{
+		PolicyMode:             "deny",
+		RequireVerifiedCreator: true,
+		AllowedOwners:          []string{"trusted"},
+		AllowedActions:         []string{"listed/action"},
+		ExcludedRepos:          []string{"org/excluded"},
+	}
+
+	violations := CheckVerifiedCreators(context.Background(), config, "org/repo", actions, verifier)
+	if !reflect.DeepEqual(violations, []string{"someone/action@v1", "unknown/action@v1"}) {
+		t.Errorf("Expected unverified owners to violate, got %v", violations)
+	}
+
+	if violations := CheckVerifiedCreators(context.Background(), config, "org/excluded", actions, verifier); violations != nil {
+		t.Errorf("Expected excluded repository to be skipped, got %v", violations)
+	}
+}
+
+func TestMixedPolicyMode(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(`
+policy_mode: mixed
+allowed_actions:
+  - actions/checkout
+  - actions/setup-go
+  - my-org/deploy
+  - my-org/legacy
+denied_actions:
+  - actions/setup-go@v3
+  - my-org/legacy
+custom_rules:
+  org/legacy-app:
+    policy_mode: deny
+  org/strict:
+    policy_mode: mixed
+    allowed_actions:
+      - actions/checkout
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicyConfig returned error: %v", err)
+	}
+
+	actions := []string{"actions/checkout@v4", "actions/setup-go@v3", "actions/setup-go@v5", "my-org/deploy@v1", "my-org/legacy@v1", "random/action@v1"}
+
+	tests := []struct {
+		repo     string
+		expected []string
+	}{
+		// Deny entries override the allow list
+		{"org/app", []string{"actions/setup-go@v3", "my-org/legacy@v1", "random/action@v1"}},
+		// A custom rule can switch a repository to deny-only semantics
+		{"org/legacy-app", []string{"actions/setup-go@v3", "my-org/legacy@v1"}},
+		// A mixed custom rule with its own allow list inherits the global deny list
+		{"org/strict", []string{"actions/setup-go@v3", "actions/setup-go@v5", "my-org/deploy@v1", "my-org/legacy@v1", "random/action@v1"}},
+	}
+
+	for _, tt := range tests {
+		violations, _ := CheckActionCompliance(config, tt.repo, actions)
+		if !reflect.DeepEqual(violations, tt.expected) {
+			t.Errorf("%s: expected violations %v, got %v", tt.repo, tt.expected, violations)
+		}
+	}
+}
+
+type fakeScopeResolver struct {
+	teams      map[string][]string
+	topics     map[string][]string
+	properties map[string]map[string][]string
+}
+
+func (f fakeScopeResolver) RepositoryProperties(ctx context.Context, org string) (map[string]map[string][]string, error) {
+	if f.properties == nil {
+		return nil, errors.New("custom properties unavailable")
+	}
+	return f.properties, nil
+}
+
+func (f fakeScopeResolver) TeamRepositories(ctx context.Context, org, slug string) ([]string, error) {
+	repos, ok := f.teams[org+"/"+slug]
+	if !ok {
+		return nil, errors.New("team not found")
+	}
+	return repos, nil
+}
+
+func (f fakeScopeResolver) TopicRepositories(ctx context.Context, org, topic string) ([]string, error) {
+	return f.topics[org+"/"+topic], nil
+}
+
+func TestResolveScopes(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(`
+policy_mode: allow
+allowed_actions:
+  - actions/checkout
+custom_rules:
+  "@org/platform":
+    allowed_actions:
+      - hashicorp/setup-terraform
+  topic:frontend:
+    allowed_actions:
+      - actions/setup-node
+  org/web:
+    allowed_actions:
+      - actions/checkout
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicyConfig returned error: %v", err)
+	}
+
+	resolver := fakeScopeResolver{
+		teams:  map[string][]string{"org/platform": {"org/infra", "org/shared"}},
+		topics: map[string][]string{"org/frontend": {"org/web", "org/shared", "org/site"}},
+	}
+
+	// Selectors only apply once resolved
+	if _, compliant := CheckActionCompliance(config, "org/infra", []string{"hashicorp/setup-terraform@v3"}); compliant {
+		t.Error("Expected team rule not to apply before scopes are resolved")
+	}
+
+	if err := ResolveScopes(context.Background(), config, resolver, "org"); err != nil {
+		t.Fatalf("ResolveScopes returned error: %v", err)
+	}
+
+	tests := []struct {
+		repo     string
+		rule     string
+		action   string
+		expected bool
+	}{
+		{"org/infra", "@org/platform", "hashicorp/setup-terraform@v3", true},
+		{"org/site", "topic:frontend", "actions/setup-node@v4", true},
+		// Exact repository keys take precedence over selectors
+		{"org/web", "org/web", "actions/setup-node@v4", false},
+		// The first matching selector in sorted order applies
+		{"org/shared", "@org/platform", "actions/setup-node@v4", false},
+		{"org/other", "", "hashicorp/setup-terraform@v3", false},
+	}
+
+	for _, tt := range tests {
+		effective := ResolveEffectivePolicy(config, tt.repo)
+		if effective.CustomRule != tt.rule {
+			t.Errorf("%s: expected custom rule %q, got %q", tt.repo, tt.rule, effective.CustomRule)
+		}
+		if _, compliant := CheckActionCompliance(config, tt.repo, []string{tt.action}); compliant != tt.expected {
+			t.Errorf("%s: expected %s compliance %v, got %v", tt.repo, tt.action, tt.expected, compliant)
+		}
+	}
+
+	// Resolved scopes survive merging a repository override
+	merged, err := MergeRepoPolicy(config, []byte("always_deny: [evil/action]"), "org/infra")
+	if err != nil {
+		t.Fatalf("MergeRepoPolicy returned error: %v", err)
+	}
+	if effective := ResolveEffectivePolicy(merged, "org/infra"); effective.CustomRule != "@org/platform" {
+		t.Errorf("Expected team rule after merge, got %q", effective.CustomRule)
+	}
+
+	config.CustomRules["@missing"] = Policy{}
+	if err := ResolveScopes(context.Background(), config, resolver, "org"); err == nil {
+		t.Error("Expected error for invalid team selector, got nil")
+	}
+}
+
+func TestPropertyConditions(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(`
+policy_mode: deny
+denied_actions:
+  - evil/action
+custom_rules:
+  restricted-data:
+    when:
+      property: data-classification
+      equals: restricted
+    policy_mode: allow
+    allowed_actions:
+      - actions/checkout
+  payments-team:
+    when:
+      property: teams
+      in: [payments]
+    policy_mode: allow
+    allowed_actions:
+      - actions/setup-go
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicyConfig returned error: %v", err)
+	}
+
+	if !HasScopedRules(config) {
+		t.Fatal("Expected conditional rules to require scope resolution")
+	}
+
+	resolver := fakeScopeResolver{properties: map[string]map[string][]string{
+		"org/vault":    {"data-classification": {"restricted"}},
+		"org/billing":  {"teams": {"platform", "payments"}},
+		"org/both":     {"data-classification": {"restricted"}, "teams": {"payments"}},
+		"org/internal": {"data-classification": {"internal"}},
+	}}
+	if err := ResolveScopes(context.Background(), config, resolver, "org"); err != nil {
+		t.Fatalf("ResolveScopes returned error: %v", err)
+	}
+
+	tests := map[string]string{
+		"org/vault":    "restricted-data",
+		"org/billing":  "payments-team",
+		"org/both":     "payments-team", // First matching key in sorted order
+		"org/internal": "",
+	}
+	for repo, rule := range tests {
+		if effective := ResolveEffectivePolicy(config, repo); effective.CustomRule != rule {
+			t.Errorf("%s: expected custom rule %q, got %q", repo, rule, effective.CustomRule)
+		}
+	}
+
+	if _, compliant := CheckActionCompliance(config, "org/vault", []string{"random/action@v1"}); compliant {
+		t.Error("Expected restricted repository to only allow listed actions")
+	}
+	if _, compliant := CheckActionCompliance(config, "org/internal", []string{"random/action@v1"}); !compliant {
+		t.Error("Expected unmatched repository to use the global deny policy")
+	}
+
+	if err := ResolveScopes(context.Background(), config, fakeScopeResolver{}, "org"); err == nil {
+		t.Error("Expected error when custom properties cannot be read, got nil")
+	}
+
+	config.CustomRules["invalid"] = Policy{When: &Condition{Property: "tier"}}
+	if err := ResolveScopes(context.Background(), config, resolver, "org"); err == nil {
+		t.Error("Expected error for condition without a value, got nil")
+	}
+}
+
+func TestCheckImageCompliance(t *testing.T) {
+	config := &PolicyConfig{
+		AllowedImages:     []string{"node", "postgres:16", "ghcr.io/other/*"},
+		DeniedImages:      []string{"ghcr.io/acme/legacy*"},
+		AllowedRegistries: []string{"ghcr.io", "registry.internal:5000"},
+		ExcludedRepos:     []string{"org/excluded"},
+	}
+
+	images := []string{
+		"node:20",                             // Allowed image, any tag
+		"postgres:16",                         // Allowed image and tag
+		"postgres:15",                         // Tag not allowed
+		"redis:7",                             // Docker Hub is not an allowed registry
+		"ghcr.io/acme/api@sha256:abc",         // Allowed registry
+		"ghcr.io/acme/legacy-db:1",            // Denied despite the allowed registry
+		"registry.internal:5000/tools/lint:2", // Allowed registry with port
+		"${{ matrix.image }}",                 // Expressions cannot be evaluated
+		"redis:7",                             // Reported once
+	}
+
+	violations := CheckImageCompliance(config, "org/repo", images)
+	expected := []string{"postgres:15", "redis:7", "ghcr.io/acme/legacy-db:1"}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Expected violations %v, got %v", expected, violations)
+	}
+
+	if violations := CheckImageCompliance(config, "org/excluded", images); len(violations) != 0 {
+		t.Errorf("Expected excluded repository to be skipped, got %v", violations)
+	}
+
+	denyOnly := &PolicyConfig{DeniedImages: []string{"*/evil/*"}}
+	violations = CheckImageCompliance(denyOnly, "org/repo", []string{"node:20", "docker.io/evil/miner:latest"})
+	if !reflect.DeepEqual(violations, []string{"docker.io/evil/miner:latest"}) {
+		t.Errorf("Expected only denied image to be reported, got %v", violations)
+	}
+
+	if violations := CheckImageCompliance(&PolicyConfig{}, "org/repo", images); violations != nil {
+		t.Errorf("Expected no violations without an image policy, got %v", violations)
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	tests := map[string]string{
+		"node:20":                        "docker.io",
+		"library/node:20":                "docker.io",
+		"ghcr.io/org/image:1":            "ghcr.io",
+		"localhost/image":                "localhost",
+		"registry.internal:5000/img":     "registry.internal:5000",
+		"docker://quay.io/org/image:1":   "quay.io",
+		"mcr.microsoft.com/mssql/server": "mcr.microsoft.com",
+	}
+	for image, expected := range tests {
+		if got := ImageRegistry(image); got != expected {
+			t.Errorf("ImageRegistry(%q) = %q, want %q", image, got, expected)
+		}
+	}
+}
+
+func TestMatchedRule(t *testing.T) {
+	config := &PolicyConfig{
+		PolicyMode:     "mixed",
+		AllowedActions: []string{"actions/checkout"},
+		DeniedActions:  []string{"evil/action@v1"},
+		AlwaysDeny:     []string{"malware/action"},
+		CustomRules: map[string]Policy{
+			"org/custom": {PolicyMode: "deny", DeniedActions: []string{"other/action"}},
+		},
+	}
+
+	tests := []struct {
+		repo, action, rule, entry, source string
+	}{
+		{"org/repo", "malware/action@v2", RuleAlwaysDeny, "malware/action", SourceGlobal},
+		{"org/repo", "evil/action@v1", RuleDeniedActions, "evil/action@v1", SourceGlobal},
+		{"org/repo", "unknown/action@v1", RuleAllowedActions, "", SourceGlobal},
+		{"org/custom", "other/action@v3", RuleDeniedActions, "other/action", SourceCustom},
+		{"org/custom", "malware/action@v2", RuleAlwaysDeny, "malware/action", SourceGlobal},
+	}
+
+	for _, tt := range tests {
+		effective := ResolveEffectivePolicy(config, tt.repo)
+		rule, entry := MatchedRule(effective, tt.action)
+		if rule != tt.rule || entry != tt.entry {
+			t.Errorf("%s %s: expected rule %s (%q), got %s (%q)", tt.repo, tt.action, tt.rule, tt.entry, rule, entry)
+		}
+		if source := RuleSource(effective, rule); source != tt.source {
+			t.Errorf("%s %s: expected source %s, got %s", tt.repo, tt.action, tt.source, source)
+		}
+	}
+
+	// Deny mode violations of actions not on the deny list come from owner restrictions
+	denyConfig := &PolicyConfig{PolicyMode: "deny", AllowedOwners: []string{"actions"}}
+	if rule, _ := MatchedRule(ResolveEffectivePolicy(denyConfig, "org/repo"), "random/action@v1"); rule != RuleAllowedOwners {
+		t.Errorf("Expected allowed_owners rule, got %s", rule)
+	}
+
+	repoOverride := EffectivePolicy{Layers: []string{LayerGlobal, LayerRepoOverride, LayerCustomRule}, PolicyMode: "allow"}
+	if source := RuleSource(repoOverride, RuleAllowedActions); source != SourceRepo {
+		t.Errorf("Expected repository policy source, got %s", source)
+	}
+}
+
+func TestCheckTyposquatting(t *testing.T) {
+	actions := []string{
+		"actions/checkout@v4",
+		"actions/chekout@v4",
+		"actions/chekout@v4",
+		"act1ons/checkout@v4",
+		"docker/build-psuh-action@v6",
+		"actions/cache/restore@v4",
+		"org/my-acton@v1",
+		"unrelated/tool@v1",
+		"actions/setup-go@v5",
+		"./local-action",
+		"docker://alpine:3",
+	}
+
+	config := &PolicyConfig{
+		DetectTyposquatting: true,
+		KnownActions:        []string{"org/my-action"},
+		CustomRules: map[string]Policy{
+			"org/trusting": {AllowedActions: []string{"act1ons/checkout"}},
+		},
+	}
+
+	typosquats := CheckTyposquatting(config, "org/repo", actions)
+	expected := []Typosquat{
+		{Action: "actions/chekout@v4", Resembles: "actions/checkout"},
+		{Action: "act1ons/checkout@v4", Resembles: "actions/checkout"},
+		{Action: "docker/build-psuh-action@v6", Resembles: "docker/build-push-action"},
+		{Action: "org/my-acton@v1", Resembles: "org/my-action"},
+	}
+	if !reflect.DeepEqual(typosquats, expected) {
+		t.Errorf("CheckTyposquatting() = %+v, want %+v", typosquats, expected)
+	}
+
+	// Explicitly allowed look-alikes are not reported
+	for _, typosquat := range CheckTyposquatting(config, "org/trusting", actions) {
+		if typosquat.Action == "act1ons/checkout@v4" {
+			t.Error("Expected explicitly allowed action not to be reported")
+		}
+	}
+
+	if typosquats := CheckTyposquatting(&PolicyConfig{}, "org/repo", actions); typosquats != nil {
+		t.Errorf("Expected no findings without detect_typosquatting, got %+v", typosquats)
+	}
+	config.ExcludedRepos = []string{"org/repo"}
+	if typosquats := CheckTyposquatting(config, "org/repo", actions); typosquats != nil {
+		t.Errorf("Expected no findings for excluded repository, got %+v", typosquats)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"checkout", "checkout", 0},
+		{"checkout", "chekout", 1},
+		{"checkout", "chekcout", 1},
+		{"checkout", "checkuot", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestParsePolicyConfigRuntimeRules(t *testing.T) {
+	if _, err := ParsePolicyConfig([]byte("deny_docker_actions_from_unknown_registries: true\n")); err == nil {
+		t.Error("Expected an error without allowed_registries")
+	}
+	if _, err := ParsePolicyConfig([]byte("deny_docker_actions_from_unknown_registries: true\nallowed_registries: [ghcr.io]\n")); err != nil {
+		t.Errorf("Expected a valid policy, got %v", err)
+	}
+}
+
+func TestParsePolicyConfigPolicyMode(t *testing.T) {
+	if _, err := ParsePolicyConfig([]byte("policy_mode: alow\nallowed_actions: [actions/checkout]\n")); err == nil || !strings.Contains(err.Error(), `invalid policy_mode "alow"`) {
+		t.Errorf("Expected an error for an unknown policy_mode, got %v", err)
+	}
+	custom := []byte("allowed_actions: [actions/checkout]\ncustom_rules:\n  org/repo:\n    policy_mode: off\n")
+	if _, err := ParsePolicyConfig(custom); err == nil || !strings.Contains(err.Error(), `custom rule "org/repo"`) {
+		t.Errorf("Expected an error for an unknown custom rule policy_mode, got %v", err)
+	}
+	for _, mode := range []string{"allow", "deny", "mixed"} {
+		if _, err := ParsePolicyConfig([]byte("policy_mode: " + mode + "\ncustom_rules:\n  org/repo:\n    policy_mode: " + mode + "\n")); err != nil {
+			t.Errorf("Expected policy_mode %s to be valid, got %v", mode, err)
+		}
+	}
+}
+
+func TestCheckReusableWorkflows(t *testing.T) {
+	config := &PolicyConfig{
+		PolicyMode:               "allow",
+		AllowedActions:           []string{"actions/checkout"},
+		AllowedReusableWorkflows: []string{"org/workflows", "org/*/.github/workflows/release.yml@v*", "./.github/workflows/*.yml"},
+		DeniedReusableWorkflows:  []string{"org/workflows/.github/workflows/legacy.yml"},
+		AlwaysDeny:               []string{"org/evil/.github/workflows/steal.yml"},
+		ExcludedRepos:            []string{"org/excluded"},
+	}
+
+	uses := []string{
+		"actions/checkout@v4",                            // Step actions follow the action lists
+		"org/workflows/.github/workflows/build.yml@main", // Any workflow of the central repository
+		"org/workflows/.github/workflows/legacy.yml@v1",  // Denied despite the allowed repository
+		"org/app/.github/workflows/release.yml@v2",       // Path and ref pattern
+		"org/app/.github/workflows/release.yml@main",     // Ref does not match
+		"other/workflows/.github/workflows/build.yml@v1", // Not allowed
+		"./.github/workflows/deploy.yml",                 // Local workflow
+		"org/evil/.github/workflows/steal.yml@main",      // Reported by always_deny instead
+		"other/workflows/.github/workflows/build.yml@v1", // Reported once
+	}
+
+	violations := CheckReusableWorkflows(config, "org/app", uses)
+	expected := []string{
+		"org/workflows/.github/workflows/legacy.yml@v1",
+		"org/app/.github/workflows/release.yml@main",
+		"other/workflows/.github/workflows/build.yml@v1",
+	}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Expected violations %v, got %v", expected, violations)
+	}
+
+	// Reusable workflows are no longer held to the action lists, except always_deny
+	actionViolations, _ := CheckActionCompliance(config, "org/app", uses)
+	if !reflect.DeepEqual(actionViolations, []string{"org/evil/.github/workflows/steal.yml@main"}) {
+		t.Errorf("Expected only the always_deny workflow as an action violation, got %v", actionViolations)
+	}
+
+	if violations := CheckReusableWorkflows(config, "org/excluded", uses); len(violations) != 0 {
+		t.Errorf("Expected excluded repository to be skipped, got %v", violations)
+	}
+
+	// Without reusable workflow rules the action lists keep applying to reusable workflows
+	config.AllowedReusableWorkflows, config.DeniedReusableWorkflows = nil, nil
+	if violations := CheckReusableWorkflows(config, "org/app", uses); violations != nil {
+		t.Errorf("Expected no violations without reusable workflow rules, got %v", violations)
+	}
+	if actionViolations, _ := CheckActionCompliance(config, "org/app", uses); len(actionViolations) != len(uses)-1 {
+		t.Errorf("Expected reusable workflows to be checked as actions, got %v", actionViolations)
+	}
+}
+
+func TestParsePolicyConfigReusableWorkflows(t *testing.T) {
+	if _, err := ParsePolicyConfig([]byte("allowed_reusable_workflows: [\"org/[workflows\"]\n")); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+	if _, err := ParsePolicyConfig([]byte("denied_reusable_workflows: [\"@v1\"]\n")); err == nil {
+		t.Error("Expected an error for a pattern without a workflow")
+	}
+	if _, err := ParsePolicyConfig([]byte("allowed_reusable_workflows: [org/workflows, \"org/*/.github/workflows/*.yml@v*\"]\n")); err != nil {
+		t.Errorf("Expected a valid policy, got %v", err)
+	}
+}
+
+func TestCheckRequiredActions(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(`required_actions:
+  - action: org/security-scan@v2
+  - action: org/license-check
+    repos: ["org/service-*"]
+  - action: org/deploy/*
+    repos: ["org/app"]
+excluded_repos: [org/excluded]
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+
+	tests := []struct {
+		repo     string
+		actions  []string
+		expected []string
+	}{
+		{"org/app", []string{"org/security-scan@v2", "org/deploy/staging@v1"}, nil},
+		{"org/app", []string{"org/security-scan@v1", "org/deploy@v1"}, []string{"org/security-scan@v2", "org/deploy/*"}},
+		{"org/service-a", []string{"org/security-scan@v2", "org/license-check@main"}, nil},
+		{"org/service-a", []string{"org/security-scan@v2"}, []string{"org/license-check"}},
+		{"org/other", nil, []string{"org/security-scan@v2"}},
+		{"org/excluded", nil, nil},
+	}
+
+	for _, test := range tests {
+		if missing := CheckRequiredActions(config, test.repo, test.actions); !reflect.DeepEqual(missing, test.expected) {
+			t.Errorf("CheckRequiredActions(%s, %v) = %v, expected %v", test.repo, test.actions, missing, test.expected)
+		}
+	}
+
+	if _, err := ParsePolicyConfig([]byte("required_actions:\n  - repos: [org/app]\n")); err == nil {
+		t.Error("Expected an error for a required action without an action")
+	}
+	if _, err := ParsePolicyConfig([]byte("required_actions:\n  - action: org/scan\n    repos: [\"org/[app\"]\n")); err == nil {
+		t.Error("Expected an error for a malformed repository pattern")
+	}
+}
+
+func TestMergeRequiredActions(t *testing.T) {
+	global := &PolicyConfig{PolicyMode: "allow", RequiredActions: []RequiredAction{{Action: "org/security-scan"}}}
+
+	merged, err := MergeRepoPolicy(global, []byte("required_actions:\n  - action: org/license-check\n    repos: [\"*\"]\n"), "org/app")
+	if err != nil {
+		t.Fatalf("Failed to merge policies: %v", err)
+	}
+	expected := []RequiredAction{{Action: "org/security-scan"}, {Action: "org/license-check", Repos: []string{"org/app"}}}
+	if !reflect.DeepEqual(merged.RequiredActions, expected) {
+		t.Errorf("Expected repository requirements scoped to the repository, got %+v", merged.RequiredActions)
+	}
+	if len(global.RequiredActions) != 1 {
+		t.Errorf("Expected the global policy to be unchanged, got %+v", global.RequiredActions)
+	}
+}
+
+func TestCheckRequiredWorkflows(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(`required_workflows:
+  - path: codeql.yml
+  - path: .github/workflows/dependency-review.y*ml
+    repos: ["org/service-*"]
+  - on: pull_request
+excluded_repos: [org/excluded]
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	if !HasTriggerRequirements(config, "org/app") || HasTriggerRequirements(&PolicyConfig{RequiredWorkflows: []RequiredWorkflow{{Path: "ci.yml"}}}, "org/app") {
+		t.Error("Expected trigger requirements only for on entries")
+	}
+
+	codeql := github.WorkflowFile{Path: ".github/workflows/codeql.yml", Content: []byte("on: push\n")}
+	review := github.WorkflowFile{Path: ".github/workflows/dependency-review.yaml", Content: []byte("on:\n  pull_request:\n    branches: [main]\n")}
+	branchOnly := github.WorkflowFile{Path: ".github/workflows/codeql.yml", Ref: "develop", Content: []byte("on: pull_request\n")}
+
+	tests := []struct {
+		repo     string
+		files    []github.WorkflowFile
+		branch   string
+		expected []string
+	}{
+		{"org/app", []github.WorkflowFile{codeql, review}, "main", nil},
+		{"org/app", []github.WorkflowFile{codeql, review}, "trunk", []string{"on: pull_request"}},
+		{"org/app", []github.WorkflowFile{codeql, review}, "", []string{"on: pull_request"}},
+		{"org/service-a", []github.WorkflowFile{codeql}, "main", []string{".github/workflows/dependency-review.y*ml", "on: pull_request"}},
+		{"org/app", []github.WorkflowFile{branchOnly}, "main", []string{"codeql.yml", "on: pull_request"}},
+		{"org/excluded", nil, "main", nil},
+	}
+
+	for _, test := range tests {
+		var missing []string
+		for _, required := range CheckRequiredWorkflows(config, test.repo, test.files, test.branch) {
+			missing = append(missing, required.String())
+		}
+		if !reflect.DeepEqual(missing, test.expected) {
+			t.Errorf("CheckRequiredWorkflows(%s, %q) = %v, expected %v", test.repo, test.branch, missing, test.expected)
+		}
+	}
+
+	for _, invalid := range []string{
+		"required_workflows:\n  - repos: [org/app]\n",
+		"required_workflows:\n  - path: ci.yml\n    on: push\n",
+		"required_workflows:\n  - path: \"[ci.yml\"\n",
+	} {
+		if _, err := ParsePolicyConfig([]byte(invalid)); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestCheckMinVersions(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(`policy_mode: deny
+denied_actions: [evil/action]
+min_versions:
+  actions/checkout: v4
+  actions/setup-node: v4.1
+excluded_repos: [org/excluded]
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+
+	sha := "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683"
+	actions := []github.Action{
+		{Uses: "actions/checkout@v4", Workflow: "ci.yml"},
+		{Uses: "actions/checkout@v3.6.0", Workflow: "ci.yml", Line: 7},
+		{Uses: sha, Workflow: "ci.yml", Comment: "v4.2.2"},
+		{Uses: sha, Workflow: "old.yml", Comment: "v2.7.0"},
+		{Uses: sha, Workflow: "bare.yml"},
+		{Uses: "actions/setup-node@v4", Workflow: "ci.yml"},
+		{Uses: "actions/setup-node@main", Workflow: "ci.yml"},
+		{Uses: "actions/cache@v1", Workflow: "ci.yml"},
+	}
+
+	expected := []VersionFloorViolation{
+		{Action: "actions/checkout@v3.6.0", Workflow: "ci.yml", Line: 7, Version: "v3.6.0", Minimum: "v4"},
+		{Action: sha, Workflow: "old.yml", Version: "v2.7.0", Minimum: "v4"},
+		{Action: "actions/setup-node@v4", Workflow: "ci.yml", Version: "v4", Minimum: "v4.1"},
+		{Action: "actions/setup-node@main", Workflow: "ci.yml", Minimum: "v4.1"},
+	}
+	if violations := CheckMinVersions(config, "org/app", actions); !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Expected violations %+v, got %+v", expected, violations)
+	}
+	if violations := CheckMinVersions(config, "org/excluded", actions); violations != nil {
+		t.Errorf("Expected excluded repository to be skipped, got %+v", violations)
+	}
+
+	if _, err := ParsePolicyConfig([]byte("min_versions:\n  actions/checkout: latest\n")); err == nil {
+		t.Error("Expected an error for a floor that is not a version")
+	}
+}
+
+func TestCheckInputRules(t *testing.T) {
+	config, err := ParsePolicyConfig([]byte(`input_rules:
+  - action: actions/checkout
+    input: persist-credentials
+    denied: ["true"]
+    default: "true"
+    visibility: public
+  - action: step-security/harden-runner
+    input: egress-policy
+    allowed: [block]
+  - action: org/deploy/*
+    input: environment
+    allowed: [staging]
+    repos: ["org/sandbox-*"]
+excluded_repos: [org/excluded]
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	if !InputRulesNeedVisibility(config, "org/app") || InputRulesNeedVisibility(&PolicyConfig{InputRules: config.InputRules[1:]}, "org/app") {
+		t.Error("Expected visibility to be needed only for rules limited to a visibility")
+	}
+
+	actions := []github.Action{
+		{Uses: "actions/checkout@v4", Workflow: "ci.yml", Line: 5},
+		{Uses: "actions/checkout@v4", Workflow: "ci.yml", With: map[string]string{"persist-credentials": "false"}},
+		{Uses: "actions/checkout@v4", Workflow: "ci.yml", With: map[string]string{"persist-credentials": "${{ inputs.persist }}"}},
+		{Uses: "step-security/harden-runner@v2", Workflow: "ci.yml", Job: "build", With: map[string]string{"egress-policy": "Block"}},
+		{Uses: "step-security/harden-runner@v2", Workflow: "ci.yml", Job: "test", With: map[string]string{"egress-policy": "audit"}},
+		{Uses: "org/deploy/prod@v1", Workflow: "deploy.yml", With: map[string]string{"environment": "production"}},
+	}
+
+	expected := []InputViolation{
+		{Action: "actions/checkout@v4", Workflow: "ci.yml", Line: 5, Input: "persist-credentials", Value: "true", Unset: true, Reason: "must not be true"},
+		{Action: "step-security/harden-runner@v2", Workflow: "ci.yml", Job: "test", Input: "egress-policy", Value: "audit", Reason: "must be block"},
+	}
+	if violations := CheckInputRules(config, "org/app", VisibilityPublic, actions); !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Expected violations %+v, got %+v", expected, violations)
+	}
+
+	// Visibility rules only apply to matching repositories, repository patterns likewise
+	violations := CheckInputRules(config, "org/sandbox-a", VisibilityPrivate, actions)
+	if len(violations) != 2 || violations[0].Input != "egress-policy" || violations[1].Value != "production" {
+		t.Errorf("Expected the harden-runner and deploy violations, got %+v", violations)
+	}
+	if violations := CheckInputRules(config, "org/excluded", VisibilityPublic, actions); violations != nil {
+		t.Errorf("Expected excluded repository to be skipped, got %+v", violations)
+	}
+
+	for _, invalid := range []string{
+		"input_rules:\n  - action: actions/checkout\n    denied: [\"true\"]\n",
+		"input_rules:\n  - action: actions/checkout\n    input: persist-credentials\n",
+		"input_rules:\n  - action: actions/checkout\n    input: persist-credentials\n    denied: [\"true\"]\n    visibility: secret\n",
+	} {
+		if _, err := ParsePolicyConfig([]byte(invalid)); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// RequiredAction mandates an action, such as a security scan or license check, in the
+// workflows of the repositories matching Repos
+type RequiredAction struct {
+	// Action is owner/repo or owner/repo/path, optionally with @ref. Without a ref any version
+	// satisfies the requirement. Globs are supported.
+	Action string `yaml:"action"`
+	// Repos are the repository patterns the requirement applies to; every repository when empty
+	Repos []string `yaml:"repos,omitempty"`
+}
+
+// Applies reports whether the requirement covers a repository
+func (r RequiredAction) Applies(repoName string) bool {
+	if len(r.Repos) == 0 {
+		return true
+	}
+	for _, repo := range r.Repos {
+		if matched, _ := path.Match(repo, repoName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// SatisfiedBy reports whether an action reference meets the requirement
+func (r RequiredAction) SatisfiedBy(action string) bool {
+	candidate := action
+	if !strings.Contains(r.Action, "@") {
+		candidate = normalizeAction(action)
+	}
+	matched, _ := path.Match(r.Action, candidate)
+	return matched
+}
+
+// CheckRequiredActions returns the required actions that none of a repository's workflows use.
+// Excluded repositories are not checked.
+func CheckRequiredActions(config *PolicyConfig, repoName string, actions []string) []string {
+	if len(config.RequiredActions) == 0 || ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	var missing []string
+	for _, required := range config.RequiredActions {
+		if !required.Applies(repoName) || contains(missing, required.Action) {
+			continue
+		}
+
+		satisfied := false
+		for _, action := range actions {
+			if required.SatisfiedBy(action) {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			missing = append(missing, required.Action)
+		}
+	}
+
+	return missing
+}
+
+// validateRequiredActions checks the required actions of a policy are well formed
+func validateRequiredActions(required []RequiredAction) error {
+	for _, rule := range required {
+		if _, err := path.Match(rule.Action, ""); err != nil || rule.Action == "" {
+			return fmt.Errorf("invalid required_actions action %q", rule.Action)
+		}
+		for _, repo := range rule.Repos {
+			if _, err := path.Match(repo, ""); err != nil {
+				return fmt.Errorf("invalid required_actions repos pattern %q for %s", repo, rule.Action)
+			}
+		}
+	}
+	return nil
+}
+
+// RequiredWorkflow mandates CI coverage in the repositories matching Repos: a workflow file
+// matching Path, or a workflow that runs on the On event for the default branch
+type RequiredWorkflow struct {
+	// Path is a workflow file name such as codeql.yml, or a path under the repository root
+	// when it contains a slash. Globs are supported.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// On is an event, such as pull_request, that a workflow must run on for the default branch
+	On string `yaml:"on,omitempty" json:"on,omitempty"`
+	// Repos are the repository patterns the requirement applies to; every repository when empty
+	Repos []string `yaml:"repos,omitempty" json:"repos,omitempty"`
+}
+
+// String describes the requirement, such as "codeql.yml" or "on: pull_request"
+func (r RequiredWorkflow) String() string {
+	if r.On != "" {
+		return "on: " + r.On
+	}
+	return r.Path
+}
+
+// Applies reports whether the requirement covers a repository
+func (r RequiredWorkflow) Applies(repoName string) bool {
+	return RequiredAction{Repos: r.Repos}.Applies(repoName)
+}
+
+// HasTriggerRequirements reports whether a repository must have workflows running on certain
+// events, which needs its default branch to be looked up
+func HasTriggerRequirements(config *PolicyConfig, repoName string) bool {
+	for _, required := range config.RequiredWorkflows {
+		if required.On != "" && required.Applies(repoName) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRequiredWorkflows returns the required workflows a repository lacks. Only workflow files
+// of the default branch count; an empty defaultBranch only satisfies event requirements with
+// workflows whose trigger has no branch filter. Excluded repositories are not checked.
+func CheckRequiredWorkflows(config *PolicyConfig, repoName string, files []github.WorkflowFile, defaultBranch string) []RequiredWorkflow {
+	if len(config.RequiredWorkflows) == 0 || ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	var missing []RequiredWorkflow
+	for _, required := range config.RequiredWorkflows {
+		if !required.Applies(repoName) {
+			continue
+		}
+
+		satisfied := false
+		for _, file := range files {
+			if file.Ref != "" {
+				continue
+			}
+			if required.On != "" {
+				triggered, err := github.TriggeredFor(file, required.On, defaultBranch)
+				satisfied = err == nil && triggered
+			} else {
+				satisfied = matchesWorkflowPath(required.Path, file.Path)
+			}
+			if satisfied {
+				break
+			}
+		}
+		if !satisfied {
+			missing = append(missing, required)
+		}
+	}
+
+	return missing
+}
+
+// matchesWorkflowPath matches a workflow file against a file name pattern, or a path pattern
+// when it contains a slash
+func matchesWorkflowPath(pattern, workflowPath string) bool {
+	if !strings.Contains(pattern, "/") {
+		workflowPath = path.Base(workflowPath)
+	}
+	matched, _ := path.Match(pattern, workflowPath)
+	return matched
+}
+
+// validateRequiredWorkflows checks the required workflows of a policy are well formed
+func validateRequiredWorkflows(required []RequiredWorkflow) error {
+	for _, rule := range required {
+		if (rule.Path == "") == (rule.On == "") {
+			return fmt.Errorf("required_workflows entries need exactly one of path and on")
+		}
+		if _, err := path.Match(rule.Path, ""); err != nil {
+			return fmt.Errorf("invalid required_workflows path %q", rule.Path)
+		}
+		for _, repo := range rule.Repos {
+			if _, err := path.Match(repo, ""); err != nil {
+				return fmt.Errorf("invalid required_workflows repos pattern %q for %s", repo, rule)
+			}
+		}
+	}
+	return nil
+}
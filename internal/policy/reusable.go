@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// HasReusableWorkflowPolicy reports whether the policy restricts which reusable workflows jobs
+// may call. Once it does, reusable workflows are checked against these rules instead of the
+// action lists.
+func HasReusableWorkflowPolicy(config *PolicyConfig) bool {
+	return len(config.AllowedReusableWorkflows) > 0 || len(config.DeniedReusableWorkflows) > 0
+}
+
+// IsReusableWorkflowCall reports whether a uses value is checked against the reusable workflow
+// rules of the policy rather than its action lists
+func IsReusableWorkflowCall(config *PolicyConfig, uses string) bool {
+	if !HasReusableWorkflowPolicy(config) {
+		return false
+	}
+	_, ok := github.ParseReusableWorkflow(uses)
+	return ok
+}
+
+// CheckReusableWorkflows returns the reusable workflow calls that violate the reusable workflow
+// rules. Calls matching denied_reusable_workflows are always reported; when
+// allowed_reusable_workflows is set, calls must match one of its patterns. Step actions,
+// excluded repositories and calls already denied by always_deny are skipped.
+func CheckReusableWorkflows(config *PolicyConfig, repoName string, uses []string) []string {
+	if !HasReusableWorkflowPolicy(config) || ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	var violations []string
+	for _, call := range uses {
+		workflow, ok := github.ParseReusableWorkflow(call)
+		if !ok || contains(violations, call) || contains(alwaysDenied(config.AlwaysDeny, []string{call}), call) {
+			continue
+		}
+
+		switch {
+		case matchesAnyWorkflow(config.DeniedReusableWorkflows, workflow, repoName):
+			violations = append(violations, call)
+		case len(config.AllowedReusableWorkflows) > 0 && !matchesAnyWorkflow(config.AllowedReusableWorkflows, workflow, repoName):
+			violations = append(violations, call)
+		}
+	}
+
+	return violations
+}
+
+// validateWorkflowPatterns checks the reusable workflow patterns of a policy list are well formed
+func validateWorkflowPatterns(key string, patterns []string) error {
+	for _, pattern := range patterns {
+		name, ref, _ := strings.Cut(pattern, "@")
+		if _, err := path.Match(name, ""); err != nil || name == "" {
+			return fmt.Errorf("invalid %s pattern %q", key, pattern)
+		}
+		if _, err := path.Match(ref, ""); err != nil {
+			return fmt.Errorf("invalid %s pattern %q", key, pattern)
+		}
+	}
+	return nil
+}
+
+// matchesAnyWorkflow checks if a reusable workflow matches any of the patterns. Patterns are
+// owner/repo, matching every workflow of the repository, or owner/repo/path, optionally
+// followed by @ref. Owner, repository, path and ref support globs, where * does not match /.
+// Workflows in the calling repository match patterns for that repository, or patterns
+// starting with "./" matched against their path.
+func matchesAnyWorkflow(patterns []string, workflow github.ReusableWorkflow, repoName string) bool {
+	qualified := workflow.Qualified(repoName)
+	repo := strings.Join(strings.SplitN(qualified, "/", 3)[:2], "/")
+
+	for _, pattern := range patterns {
+		name, ref, hasRef := strings.Cut(pattern, "@")
+		if hasRef {
+			if matched, _ := path.Match(ref, workflow.Ref); !matched || workflow.Local() {
+				continue
+			}
+		}
+
+		var matched bool
+		switch {
+		case strings.HasPrefix(name, "./"):
+			matched, _ = path.Match(strings.TrimPrefix(name, "./"), workflow.Path)
+			matched = matched && workflow.Local()
+		case strings.Count(name, "/") == 1:
+			matched, _ = path.Match(name, repo)
+		default:
+			matched, _ = path.Match(name, qualified)
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
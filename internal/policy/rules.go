@@ -0,0 +1,75 @@
+package policy
+
+// Rules a violation can be attributed to
+const (
+	RuleAlwaysDeny      = "always_deny"
+	RuleDeniedActions   = "denied_actions"
+	RuleAllowedActions  = "allowed_actions"
+	RuleAllowedOwners   = "allowed_owners"
+	RuleVerifiedCreator = "require_verified_creator"
+	RuleJobRules        = "job_rules"
+)
+
+// RuleExcludedRepos is the rule that allows every action in an excluded repository other than
+// those on the always_deny list
+const RuleExcludedRepos = "excluded_repos"
+
+// Policy levels a rule can come from
+const (
+	SourceGlobal = "global"
+	SourceCustom = "custom"
+	SourceRepo   = "repo"
+)
+
+// Severities of findings
+const (
+	SeverityError   = "error"   // Policy violations and other findings failing the policy
+	SeverityWarning = "warning" // Findings that only fail the policy when escalated, like deprecations
+	SeverityInfo    = "info"    // Details worth reviewing that never fail the policy
+)
+
+// MatchedRule returns the rule that makes an action a violation under a repository's effective
+// policy, along with the list entry that matched it. Actions not allowed by an allow list have
+// no matching entry.
+func MatchedRule(effective EffectivePolicy, action string) (rule, entry string) {
+	normalized := normalizeAction(action)
+
+	if matched, ok := matchingEntry(effective.AlwaysDeny, action, normalized); ok {
+		return RuleAlwaysDeny, matched
+	}
+	if effective.PolicyMode == "deny" || effective.PolicyMode == "mixed" {
+		if matched, ok := matchingEntry(effective.DeniedActions, action, normalized); ok {
+			return RuleDeniedActions, matched
+		}
+	}
+	if effective.PolicyMode == "deny" {
+		return RuleAllowedOwners, ""
+	}
+	return RuleAllowedActions, ""
+}
+
+// RuleSource returns the policy level a repository's rule comes from. The always_deny list
+// and job rules are set globally; other rules come from a repository policy file, a custom
+// rule, or the global policy, as recorded in the effective policy's layers.
+func RuleSource(effective EffectivePolicy, rule string) string {
+	if rule == RuleAlwaysDeny || rule == RuleJobRules {
+		return SourceGlobal
+	}
+	switch {
+	case contains(effective.Layers, LayerRepoOverride):
+		return SourceRepo
+	case contains(effective.Layers, LayerCustomRule):
+		return SourceCustom
+	}
+	return SourceGlobal
+}
+
+// matchingEntry returns the list entry matching an action with or without its version
+func matchingEntry(list []string, action, normalized string) (string, bool) {
+	for _, entry := range list {
+		if entry == action || entry == normalized {
+			return entry, true
+		}
+	}
+	return "", false
+}
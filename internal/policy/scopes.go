@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Prefixes of custom rule keys that select repositories by team or topic instead of by name
+const (
+	TeamSelectorPrefix  = "@"      // "@org/team-slug"
+	TopicSelectorPrefix = "topic:" // "topic:frontend"
+)
+
+// ScopeResolver lists the repositories selected by team, topic and custom property rules
+type ScopeResolver interface {
+	TeamRepositories(ctx context.Context, org, slug string) ([]string, error)
+	TopicRepositories(ctx context.Context, org, topic string) ([]string, error)
+	RepositoryProperties(ctx context.Context, org string) (map[string]map[string][]string, error)
+}
+
+// IsScopeSelector reports whether a custom rule key selects repositories by team or topic
+func IsScopeSelector(key string) bool {
+	return strings.HasPrefix(key, TeamSelectorPrefix) || strings.HasPrefix(key, TopicSelectorPrefix)
+}
+
+// HasScopedRules reports whether any custom rule must be resolved with ResolveScopes
+func HasScopedRules(config *PolicyConfig) bool {
+	for key, rule := range config.CustomRules {
+		if IsScopeSelector(key) || rule.When != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveScopes resolves team and topic custom rule keys, and rules with a custom property
+// condition, to the repositories they select. Topics and properties are looked up within org.
+// It must be called before checking compliance for these rules to take effect; until then
+// only exact repository keys match.
+func ResolveScopes(ctx context.Context, config *PolicyConfig, resolver ScopeResolver, org string) error {
+	scoped := make(map[string][]string)
+	var properties map[string]map[string][]string
+
+	for key, rule := range config.CustomRules {
+		var repos []string
+		var err error
+
+		switch {
+		case rule.When != nil:
+			if rule.When.Property == "" || (rule.When.Equals == "" && len(rule.When.In) == 0) {
+				return fmt.Errorf("invalid condition in custom rule %q, expected property with equals or in", key)
+			}
+			// Property values for the whole organization are fetched once
+			if properties == nil {
+				properties, err = resolver.RepositoryProperties(ctx, org)
+				if err != nil {
+					break
+				}
+			}
+			for repo, values := range properties {
+				if rule.When.Matches(values) {
+					repos = append(repos, repo)
+				}
+			}
+		case strings.HasPrefix(key, TeamSelectorPrefix):
+			teamOrg, slug, found := strings.Cut(strings.TrimPrefix(key, TeamSelectorPrefix), "/")
+			if !found || teamOrg == "" || slug == "" {
+				return fmt.Errorf("invalid team selector %q, expected @org/team", key)
+			}
+			repos, err = resolver.TeamRepositories(ctx, teamOrg, slug)
+		case strings.HasPrefix(key, TopicSelectorPrefix):
+			topic := strings.TrimPrefix(key, TopicSelectorPrefix)
+			if topic == "" {
+				return fmt.Errorf("invalid topic selector %q, expected topic:name", key)
+			}
+			repos, err = resolver.TopicRepositories(ctx, org, topic)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to resolve custom rule %q: %w", key, err)
+		}
+		scoped[key] = repos
+	}
+
+	config.scopedRepos = scoped
+	return nil
+}
+
+// customRuleFor returns the custom rule applying to a repository and its key. An exact
+// repository key takes precedence over team, topic and property rules; when several of
+// those match, the first in sorted key order applies.
+func customRuleFor(config *PolicyConfig, repoName string) (string, Policy, bool) {
+	if rule, exists := config.CustomRules[repoName]; exists && rule.When == nil {
+		return repoName, rule, true
+	}
+
+	keys := make([]string, 0, len(config.scopedRepos))
+	for key := range config.scopedRepos {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		rule, exists := config.CustomRules[key]
+		if exists && contains(config.scopedRepos[key], repoName) {
+			return key, rule, true
+		}
+	}
+
+	return "", Policy{}, false
+}
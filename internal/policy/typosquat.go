@@ -0,0 +1,151 @@
+package policy
+
+import "strings"
+
+// PopularActions are widely used actions that look-alike names are compared against
+var PopularActions = []string{
+	"actions/cache",
+	"actions/checkout",
+	"actions/configure-pages",
+	"actions/create-github-app-token",
+	"actions/dependency-review-action",
+	"actions/deploy-pages",
+	"actions/download-artifact",
+	"actions/github-script",
+	"actions/labeler",
+	"actions/setup-dotnet",
+	"actions/setup-go",
+	"actions/setup-java",
+	"actions/setup-node",
+	"actions/setup-python",
+	"actions/stale",
+	"actions/upload-artifact",
+	"actions/upload-pages-artifact",
+	"aws-actions/amazon-ecr-login",
+	"aws-actions/configure-aws-credentials",
+	"azure/login",
+	"azure/setup-helm",
+	"azure/setup-kubectl",
+	"codecov/codecov-action",
+	"docker/build-push-action",
+	"docker/login-action",
+	"docker/metadata-action",
+	"docker/setup-buildx-action",
+	"docker/setup-qemu-action",
+	"dorny/paths-filter",
+	"github/codeql-action",
+	"golangci/golangci-lint-action",
+	"goreleaser/goreleaser-action",
+	"google-github-actions/auth",
+	"google-github-actions/setup-gcloud",
+	"gradle/actions",
+	"hashicorp/setup-terraform",
+	"peter-evans/create-pull-request",
+	"pnpm/action-setup",
+	"ruby/setup-ruby",
+	"slackapi/slack-github-action",
+	"softprops/action-gh-release",
+	"sigstore/cosign-installer",
+	"tj-actions/changed-files",
+}
+
+// Typosquat is an action whose name closely resembles a popular action without being it
+type Typosquat struct {
+	Action    string `json:"action"`
+	Resembles string `json:"resembles"`
+}
+
+// CheckTyposquatting returns the actions whose owner/repository name is within a small edit
+// distance of a popular action or of the policy's known_actions, when the policy sets
+// detect_typosquatting. Known actions themselves, explicitly allowed actions and actions
+// from allowed owners are not reported, nor are actions in excluded repositories.
+func CheckTyposquatting(config *PolicyConfig, repoName string, actions []string) []Typosquat {
+	if !config.DetectTyposquatting {
+		return nil
+	}
+
+	effective := ResolveEffectivePolicy(config, repoName)
+	if effective.Excluded {
+		return nil
+	}
+
+	known := append(append([]string{}, PopularActions...), config.KnownActions...)
+	for i, name := range known {
+		known[i] = strings.ToLower(name)
+	}
+
+	var typosquats []Typosquat
+	seen := make(map[string]bool)
+	for _, actionWithVersion := range actions {
+		name := actionRepository(actionWithVersion)
+		if name == "" || seen[actionWithVersion] || contains(known, name) {
+			continue
+		}
+		seen[actionWithVersion] = true
+
+		if contains(effective.AllowedOwners, ActionOwner(actionWithVersion)) ||
+			contains(effective.AllowedActions, normalizeAction(actionWithVersion)) || contains(effective.AllowedActions, actionWithVersion) {
+			continue
+		}
+
+		for _, candidate := range known {
+			if editDistance(name, candidate) <= maxTypoDistance(candidate) {
+				typosquats = append(typosquats, Typosquat{Action: actionWithVersion, Resembles: candidate})
+				break
+			}
+		}
+	}
+
+	return typosquats
+}
+
+// actionRepository returns the lowercase owner/repository of an action reference, or an empty
+// string for local actions and container images
+func actionRepository(action string) string {
+	if ActionOwner(action) == "" {
+		return ""
+	}
+	parts := strings.SplitN(normalizeAction(action), "/", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return strings.ToLower(parts[0] + "/" + parts[1])
+}
+
+// maxTypoDistance is the largest edit distance at which a name is considered a look-alike of
+// name; short names allow a single edit so that unrelated short names are not reported
+func maxTypoDistance(name string) int {
+	if len(name) < 12 {
+		return 1
+	}
+	return 2
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions
+// of adjacent characters needed to turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return rows[len(ra)][len(rb)]
+}
@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/pinning"
+	"github.com/ihavespoons/action-control/internal/version"
+)
+
+// VersionFloorViolation is an action reference older than the minimum version the policy sets
+// for the action, or one on a branch that cannot be compared with it
+type VersionFloorViolation struct {
+	Action   string `json:"action"`
+	Workflow string `json:"workflow"`
+	Line     int    `json:"line,omitempty"`
+	Version  string `json:"version,omitempty"` // Version of the reference; empty when it is not a version
+	Minimum  string `json:"minimum"`
+}
+
+// CheckMinVersions returns the references to actions listed in min_versions that are older than
+// their floor, regardless of policy mode. Tags are compared directly, with a major version tag
+// such as v4 read as v4.0.0, and SHA pins by the version in their comment; SHA pins without
+// one are skipped. Branches and unpinned references are reported since they cannot be shown to
+// meet the floor. Excluded repositories are not checked.
+func CheckMinVersions(config *PolicyConfig, repoName string, actions []github.Action) []VersionFloorViolation {
+	if len(config.MinVersions) == 0 || ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	var violations []VersionFloorViolation
+	for _, action := range actions {
+		minimum, ok := config.MinVersions[normalizeAction(action.Uses)]
+		if !ok {
+			continue
+		}
+
+		_, ref, _ := strings.Cut(action.Uses, "@")
+		kind, _ := pinning.Classify(action.Uses)
+		switch kind {
+		case pinning.SHA:
+			comment, ok := pinning.VersionFromComment(action.Comment)
+			if !ok {
+				continue
+			}
+			ref = comment
+		case pinning.Branch, pinning.Unpinned:
+			ref = ""
+		}
+
+		if ref != "" {
+			if cmp, err := version.Compare(ref, minimum); err == nil && cmp >= 0 {
+				continue
+			}
+		}
+		violations = append(violations, VersionFloorViolation{
+			Action:   action.Uses,
+			Workflow: action.Workflow,
+			Line:     action.Line,
+			Version:  ref,
+			Minimum:  minimum,
+		})
+	}
+
+	return violations
+}
+
+// validateMinVersions checks the floors of min_versions are versions
+func validateMinVersions(minVersions map[string]string) error {
+	for action, minimum := range minVersions {
+		if _, err := version.Compare(minimum, minimum); err != nil {
+			return fmt.Errorf("invalid min_versions entry for %s: %w", action, err)
+		}
+	}
+	return nil
+}
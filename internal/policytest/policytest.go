@@ -0,0 +1,172 @@
+package policytest
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Expected outcomes of a test case
+const (
+	ExpectAllow = "allow" // None of the actions violate the policy
+	ExpectDeny  = "deny"  // Every action violates the policy
+)
+
+// DefaultPath is the test file read when no path is given
+const DefaultPath = "policy_test.yaml"
+
+// defaultRepo is the repository test cases are evaluated for when they do not name one
+const defaultRepo = "example-org/example-repo"
+
+// Case is a single table-style test of a policy
+type Case struct {
+	Name     string   `yaml:"name"`
+	Repo     string   `yaml:"repo,omitempty"`
+	Workflow string   `yaml:"workflow,omitempty"` // Workflow file path, for path-scoped custom rules
+	Actions  []string `yaml:"actions"`
+	Expect   string   `yaml:"expect"`
+
+	// Job and Permissions describe the job using the actions, for job rules
+	Job         string            `yaml:"job,omitempty"`
+	Permissions map[string]string `yaml:"permissions,omitempty"`
+}
+
+// Suite is a file of test cases
+type Suite struct {
+	Tests []Case `yaml:"tests"`
+}
+
+// Result is the outcome of running a test case
+type Result struct {
+	Case       Case
+	Passed     bool
+	Unexpected []string // Actions whose outcome differed from the expectation
+}
+
+// Load reads and validates a test file
+func Load(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy tests: %w", err)
+	}
+
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("failed to parse policy tests: %w", err)
+	}
+
+	for i, tc := range suite.Tests {
+		if tc.Expect != ExpectAllow && tc.Expect != ExpectDeny {
+			return nil, fmt.Errorf("test %d (%s): expect must be %q or %q, got %q", i+1, tc.Name, ExpectAllow, ExpectDeny, tc.Expect)
+		}
+		if len(tc.Actions) == 0 {
+			return nil, fmt.Errorf("test %d (%s): no actions given", i+1, tc.Name)
+		}
+	}
+
+	return &suite, nil
+}
+
+// Save writes a test file
+func (s *Suite) Save(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy tests: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write policy tests: %w", err)
+	}
+
+	return nil
+}
+
+// Run evaluates each test case against the policy. Cases expecting allow pass when none of
+// their actions violate the policy in the case's repository; cases expecting deny pass when
+// every action does.
+func Run(config *policy.PolicyConfig, suite *Suite) []Result {
+	results := make([]Result, len(suite.Tests))
+	for i, tc := range suite.Tests {
+		repo := tc.Repo
+		if repo == "" {
+			repo = defaultRepo
+		}
+
+		actions := make([]github.Action, len(tc.Actions))
+		for i, uses := range tc.Actions {
+			actions[i] = github.Action{Uses: uses, Workflow: tc.Workflow, Job: tc.Job, Permissions: tc.Permissions}
+		}
+		violations := policy.CheckWorkflowCompliance(config, repo, actions)
+		denied := make(map[string]bool, len(violations))
+		for _, violation := range violations {
+			denied[violation] = true
+		}
+
+		result := Result{Case: tc}
+		for _, action := range tc.Actions {
+			if denied[action] != (tc.Expect == ExpectDeny) {
+				result.Unexpected = append(result.Unexpected, action)
+			}
+		}
+		result.Passed = len(result.Unexpected) == 0
+		results[i] = result
+	}
+
+	return results
+}
+
+// Generate creates a starter test file from a policy: allowed actions are expected to be
+// allowed and denied actions to be denied, globally and for each repository custom rule, and
+// allow-mode policies get a case expecting unlisted actions to be denied
+func Generate(config *policy.PolicyConfig) *Suite {
+	suite := &Suite{}
+
+	add := func(name, repo string, actions []string, expect string) {
+		if len(actions) == 0 {
+			return
+		}
+		refs := make([]string, len(actions))
+		for i, action := range actions {
+			refs[i] = exampleRef(action)
+		}
+		suite.Tests = append(suite.Tests, Case{Name: name, Repo: repo, Actions: refs, Expect: expect})
+	}
+
+	add("allowed actions are allowed", "", config.AllowedActions, ExpectAllow)
+	add("denied actions are denied", "", config.DeniedActions, ExpectDeny)
+	add("always denied actions are denied", "", config.AlwaysDeny, ExpectDeny)
+	if config.PolicyMode == "allow" {
+		add("unlisted actions are denied", "", []string{"example-owner/unlisted-action"}, ExpectDeny)
+	}
+
+	// Sort repositories for a stable file
+	repos := make([]string, 0, len(config.CustomRules))
+	for repo, rule := range config.CustomRules {
+		if rule.When == nil && strings.Count(repo, "/") == 1 && !strings.ContainsAny(repo, "*?[:") {
+			repos = append(repos, repo)
+		}
+	}
+	sort.Strings(repos)
+
+	for _, repo := range repos {
+		rule := config.CustomRules[repo]
+		add(fmt.Sprintf("%s allows its custom actions", repo), repo, rule.AllowedActions, ExpectAllow)
+		add(fmt.Sprintf("%s denies its custom denied actions", repo), repo, rule.DeniedActions, ExpectDeny)
+	}
+
+	return suite
+}
+
+// exampleRef turns a policy entry into an action reference, adding a version when it has none
+func exampleRef(action string) string {
+	if strings.Contains(action, "@") {
+		return action
+	}
+	return action + "@v1"
+}
@@ -0,0 +1,117 @@
+package policytest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+var testPolicy = &policy.PolicyConfig{
+	PolicyMode:     "allow",
+	AllowedActions: []string{"actions/checkout", "docker/login-action"},
+	AlwaysDeny:     []string{"evil/action"},
+	CustomRules: map[string]policy.Policy{
+		"org/special": {PolicyMode: "allow", AllowedActions: []string{"actions/checkout", "special/tool"}},
+		"team:infra":  {PolicyMode: "allow", AllowedActions: []string{"hashicorp/setup-terraform"}},
+
+		"org/special:.github/workflows/release-*": {AllowedActions: []string{"actions/checkout", "goreleaser/goreleaser-action"}},
+	},
+}
+
+func TestRun(t *testing.T) {
+	suite := &Suite{Tests: []Case{
+		{Name: "checkout allowed", Actions: []string{"actions/checkout@v4", "docker/login-action@v3"}, Expect: ExpectAllow},
+		{Name: "unlisted denied", Actions: []string{"some/action@v1"}, Expect: ExpectDeny},
+		{Name: "special tool only in special repo", Repo: "org/special", Actions: []string{"special/tool@v2"}, Expect: ExpectAllow},
+		{Name: "wrong expectation", Actions: []string{"special/tool@v2", "actions/checkout@v4"}, Expect: ExpectAllow},
+		{Name: "kill switch in special repo", Repo: "org/special", Actions: []string{"evil/action@v1", "actions/checkout@v4"}, Expect: ExpectDeny},
+		{Name: "releaser in release workflow", Repo: "org/special", Workflow: ".github/workflows/release-go.yml", Actions: []string{"goreleaser/goreleaser-action@v6"}, Expect: ExpectAllow},
+		{Name: "releaser elsewhere", Repo: "org/special", Workflow: ".github/workflows/ci.yml", Actions: []string{"goreleaser/goreleaser-action@v6"}, Expect: ExpectDeny},
+	}}
+
+	results := Run(testPolicy, suite)
+	passed := []bool{true, true, true, false, false, true, true}
+	for i, result := range results {
+		if result.Passed != passed[i] {
+			t.Errorf("%s: Passed = %v, want %v (unexpected %v)", result.Case.Name, result.Passed, passed[i], result.Unexpected)
+		}
+	}
+	if !reflect.DeepEqual(results[3].Unexpected, []string{"special/tool@v2"}) {
+		t.Errorf("Expected special/tool to be unexpectedly denied, got %v", results[3].Unexpected)
+	}
+	if !reflect.DeepEqual(results[4].Unexpected, []string{"actions/checkout@v4"}) {
+		t.Errorf("Expected checkout to be unexpectedly allowed, got %v", results[4].Unexpected)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	suite := Generate(testPolicy)
+
+	expected := []Case{
+		{Name: "allowed actions are allowed", Actions: []string{"actions/checkout@v1", "docker/login-action@v1"}, Expect: ExpectAllow},
+		{Name: "always denied actions are denied", Actions: []string{"evil/action@v1"}, Expect: ExpectDeny},
+		{Name: "unlisted actions are denied", Actions: []string{"example-owner/unlisted-action@v1"}, Expect: ExpectDeny},
+		{Name: "org/special allows its custom actions", Repo: "org/special", Actions: []string{"actions/checkout@v1", "special/tool@v1"}, Expect: ExpectAllow},
+	}
+	if !reflect.DeepEqual(suite.Tests, expected) {
+		t.Errorf("Generate() = %+v, want %+v", suite.Tests, expected)
+	}
+
+	// The generated suite passes against the policy it was generated from
+	for _, result := range Run(testPolicy, suite) {
+		if !result.Passed {
+			t.Errorf("Generated test %q failed: %v", result.Case.Name, result.Unexpected)
+		}
+	}
+}
+
+func TestLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	if err := Generate(testPolicy).Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	suite, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(suite.Tests) != 4 {
+		t.Errorf("Expected 4 tests after round trip, got %d", len(suite.Tests))
+	}
+
+	invalid := map[string]string{
+		"bad expectation": "tests:\n  - name: x\n    actions: [a/b@v1]\n    expect: maybe\n",
+		"no actions":      "tests:\n  - name: x\n    expect: allow\n",
+		"invalid YAML":    "tests: [",
+	}
+	for name, content := range invalid {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestRunJobRules(t *testing.T) {
+	config := &policy.PolicyConfig{
+		PolicyMode:    "deny",
+		DeniedActions: []string{"evil/action"},
+		JobRules:      []policy.JobRule{{Permissions: map[string]string{"id-token": "write"}, DenyThirdParty: true}},
+	}
+	suite := &Suite{Tests: []Case{
+		{Name: "third-party in build job", Repo: "org/app", Job: "build", Actions: []string{"goreleaser/goreleaser-action@v6"}, Expect: ExpectAllow},
+		{Name: "third-party with id-token", Repo: "org/app", Job: "release", Permissions: map[string]string{"id-token": "write"}, Actions: []string{"goreleaser/goreleaser-action@v6"}, Expect: ExpectDeny},
+		{Name: "GitHub action with id-token", Repo: "org/app", Job: "release", Permissions: map[string]string{"id-token": "write"}, Actions: []string{"actions/checkout@v4"}, Expect: ExpectAllow},
+	}}
+
+	for _, result := range Run(config, suite) {
+		if !result.Passed {
+			t.Errorf("%s: unexpected %v", result.Case.Name, result.Unexpected)
+		}
+	}
+}
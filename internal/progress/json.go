@@ -0,0 +1,120 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Progress event types emitted by JSON
+const (
+	EventScanStarted  = "scan_started"
+	EventRepoStarted  = "repo_started"
+	EventRepoFinished = "repo_finished"
+	EventScanFinished = "scan_finished"
+)
+
+// Event is a single machine-readable progress event
+type Event struct {
+	Event      string    `json:"event"`
+	Time       time.Time `json:"time"`
+	Repository string    `json:"repository,omitempty"`
+	Index      int       `json:"index,omitempty"`
+	Total      int       `json:"total"`
+	Completed  int       `json:"completed"`
+	Errors     int       `json:"errors"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	APICalls   int64     `json:"api_calls,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// JSON writes organization scan progress as newline-delimited JSON events, one per line,
+// for wrapper tooling that tracks progress or detects stalled scans
+type JSON struct {
+	Out io.Writer
+
+	mu        sync.Mutex
+	enc       *json.Encoder
+	total     int
+	completed int
+	errors    int
+	calls     int64
+	started   time.Time
+	now       func() time.Time
+}
+
+// NewJSON creates a JSON observer writing to out
+func NewJSON(out io.Writer) *JSON {
+	return &JSON{
+		Out: out,
+		enc: json.NewEncoder(out),
+		now: time.Now,
+	}
+}
+
+// ScanStarted implements github.ScanObserver
+func (j *JSON) ScanStarted(total int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.total = total
+	j.completed = 0
+	j.errors = 0
+	j.calls = 0
+	j.started = j.now()
+	j.emit(Event{Event: EventScanStarted})
+}
+
+// RepoStarted implements github.ScanObserver
+func (j *JSON) RepoStarted(repo string, index, total int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.emit(Event{Event: EventRepoStarted, Repository: repo, Index: index})
+}
+
+// RepoScanned implements github.ScanObserver
+func (j *JSON) RepoScanned(stats github.RepoStats) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.completed++
+	j.calls += stats.APICalls
+	event := Event{
+		Event:      EventRepoFinished,
+		Repository: stats.Repository,
+		Index:      stats.Index,
+		DurationMs: stats.Duration.Milliseconds(),
+		APICalls:   stats.APICalls,
+	}
+	if stats.Err != nil {
+		j.errors++
+		event.Error = stats.Err.Error()
+	}
+	j.emit(event)
+}
+
+// ScanFinished implements github.ScanObserver
+func (j *JSON) ScanFinished() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.emit(Event{
+		Event:      EventScanFinished,
+		DurationMs: j.now().Sub(j.started).Milliseconds(),
+		APICalls:   j.calls,
+	})
+}
+
+// emit writes an event with the current counters; callers must hold the lock
+func (j *JSON) emit(event Event) {
+	event.Time = j.now().UTC()
+	event.Total = j.total
+	event.Completed = j.completed
+	event.Errors = j.errors
+	// Progress output is best effort and must never interrupt a scan
+	_ = j.enc.Encode(event)
+}
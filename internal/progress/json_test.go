@@ -0,0 +1,57 @@
+package progress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestJSONEvents(t *testing.T) {
+	var out bytes.Buffer
+	observer := NewJSON(&out)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	observer.now = func() time.Time { return clock }
+
+	observer.ScanStarted(2)
+	observer.RepoStarted("org/repo1", 1, 2)
+	clock = clock.Add(1500 * time.Millisecond)
+	observer.RepoScanned(github.RepoStats{Repository: "org/repo1", Index: 1, Total: 2, Duration: 1500 * time.Millisecond, APICalls: 3})
+	observer.RepoStarted("org/repo2", 2, 2)
+	observer.RepoScanned(github.RepoStats{Repository: "org/repo2", Index: 2, Total: 2, APICalls: 2, Err: errors.New("not found")})
+	observer.ScanFinished()
+
+	var events []Event
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	expected := []string{EventScanStarted, EventRepoStarted, EventRepoFinished, EventRepoStarted, EventRepoFinished, EventScanFinished}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+	for i, name := range expected {
+		if events[i].Event != name {
+			t.Errorf("Event %d: expected %s, got %s", i, name, events[i].Event)
+		}
+	}
+
+	if e := events[2]; e.Repository != "org/repo1" || e.DurationMs != 1500 || e.APICalls != 3 || e.Completed != 1 {
+		t.Errorf("Unexpected repo_finished event: %+v", e)
+	}
+	if e := events[4]; e.Error != "not found" || e.Errors != 1 || e.Completed != 2 {
+		t.Errorf("Expected error to be reported, got %+v", e)
+	}
+	if e := events[5]; e.Total != 2 || e.APICalls != 5 || e.DurationMs != 1500 {
+		t.Errorf("Unexpected scan_finished event: %+v", e)
+	}
+}
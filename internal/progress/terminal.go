@@ -0,0 +1,151 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// spinnerFrames are drawn in turn while a scan is running
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Terminal renders organization scan progress for humans.
+// With Interactive set, a spinner and "n/m repos" counter are redrawn in place;
+// with Verbose set, per-repository timing and API call counts are printed.
+type Terminal struct {
+	Out         io.Writer
+	Interactive bool
+	Verbose     bool
+
+	mu      sync.Mutex
+	done    int
+	total   int
+	frame   int
+	current string
+	started time.Time
+	calls   int64
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewTerminal creates a Terminal observer writing to out
+func NewTerminal(out io.Writer, interactive, verbose bool) *Terminal {
+	return &Terminal{
+		Out:         out,
+		Interactive: interactive,
+		Verbose:     verbose,
+	}
+}
+
+// IsTerminal reports whether the file is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ScanStarted implements github.ScanObserver
+func (t *Terminal) ScanStarted(total int) {
+	t.mu.Lock()
+	t.total = total
+	t.done = 0
+	t.calls = 0
+	t.started = time.Now()
+	t.mu.Unlock()
+
+	if !t.Interactive {
+		return
+	}
+
+	// Animate the spinner until the scan finishes
+	t.stop = make(chan struct{})
+	t.stopped = make(chan struct{})
+	go func() {
+		defer close(t.stopped)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+				t.mu.Lock()
+				t.frame = (t.frame + 1) % len(spinnerFrames)
+				t.draw()
+				t.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// RepoStarted implements github.ScanObserver
+func (t *Terminal) RepoStarted(repo string, index, total int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.current = repo
+	if t.Interactive {
+		t.draw()
+	}
+}
+
+// RepoScanned implements github.ScanObserver
+func (t *Terminal) RepoScanned(stats github.RepoStats) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done = stats.Index
+	t.current = stats.Repository
+	t.calls += stats.APICalls
+
+	if t.Verbose {
+		if t.Interactive {
+			t.clear()
+		}
+		status := "ok"
+		if stats.Err != nil {
+			status = fmt.Sprintf("error: %v", stats.Err)
+		}
+		fmt.Fprintf(t.Out, "[%d/%d] %s: %s, %d API calls (%s)\n",
+			stats.Index, stats.Total, stats.Repository, stats.Duration.Round(time.Millisecond), stats.APICalls, status)
+	}
+
+	if t.Interactive {
+		t.draw()
+	}
+}
+
+// ScanFinished implements github.ScanObserver
+func (t *Terminal) ScanFinished() {
+	if t.Interactive && t.stop != nil {
+		close(t.stop)
+		<-t.stopped
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.Interactive {
+		t.clear()
+	}
+	if t.Verbose {
+		fmt.Fprintf(t.Out, "Scanned %d repositories in %s with %d API calls\n",
+			t.total, time.Since(t.started).Round(time.Millisecond), t.calls)
+	}
+}
+
+// draw redraws the progress line; callers must hold the lock
+func (t *Terminal) draw() {
+	fmt.Fprintf(t.Out, "\r\033[K%s %d/%d repos %s", spinnerFrames[t.frame], t.done, t.total, t.current)
+}
+
+// clear erases the progress line; callers must hold the lock
+func (t *Terminal) clear() {
+	fmt.Fprint(t.Out, "\r\033[K")
+}
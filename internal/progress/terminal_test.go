@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestTerminalVerbose(t *testing.T) {
+	var out bytes.Buffer
+	terminal := NewTerminal(&out, false, true)
+
+	terminal.ScanStarted(2)
+	terminal.RepoScanned(github.RepoStats{Repository: "org/repo1", Index: 1, Total: 2, Duration: 1500 * time.Millisecond, APICalls: 3})
+	terminal.RepoScanned(github.RepoStats{Repository: "org/repo2", Index: 2, Total: 2, APICalls: 2, Err: errors.New("not found")})
+	terminal.ScanFinished()
+
+	result := out.String()
+	expectedPhrases := []string{
+		"[1/2] org/repo1: 1.5s, 3 API calls (ok)",
+		"[2/2] org/repo2: 0s, 2 API calls (error: not found)",
+		"Scanned 2 repositories in",
+		"with 5 API calls",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("Expected output to contain %q, got:\n%s", phrase, result)
+		}
+	}
+}
+
+func TestTerminalInteractive(t *testing.T) {
+	var out bytes.Buffer
+	terminal := NewTerminal(&out, true, false)
+
+	terminal.ScanStarted(1)
+	terminal.RepoScanned(github.RepoStats{Repository: "org/repo1", Index: 1, Total: 1})
+	terminal.ScanFinished()
+
+	result := out.String()
+	if !strings.Contains(result, "1/1 repos org/repo1") {
+		t.Errorf("Expected progress counter in output, got %q", result)
+	}
+	if !strings.HasSuffix(result, "\r\033[K") {
+		t.Error("Expected progress line to be cleared when the scan finishes")
+	}
+}
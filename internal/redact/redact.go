@@ -0,0 +1,104 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redactor replaces the names of private repositories in reports with pseudonyms, so reports
+// can be shared outside the organization without exposing internal project names. Pseudonyms
+// are keyed hashes: the same key gives the same pseudonyms across reports, and names can't be
+// recovered by hashing guesses without it.
+type Redactor struct {
+	key     []byte
+	names   map[string]string // Pseudonyms by lowercase full name
+	pattern *regexp.Regexp    // Matches the names, built once they are all known
+}
+
+// New creates a redactor keyed with key, or with a random key when empty, so pseudonyms only
+// correlate within one run
+func New(key string) *Redactor {
+	keyBytes := []byte(key)
+	if key == "" {
+		keyBytes = make([]byte, 32)
+		rand.Read(keyBytes)
+	}
+	return &Redactor{key: keyBytes, names: make(map[string]string)}
+}
+
+// Hide registers a repository, as owner/name, whose name is redacted
+func (r *Redactor) Hide(fullName string) {
+	owner, _, ok := strings.Cut(fullName, "/")
+	if !ok {
+		return
+	}
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(strings.ToLower(fullName)))
+	r.names[strings.ToLower(fullName)] = strings.ToLower(owner) + "/repo-" + hex.EncodeToString(mac.Sum(nil))[:12]
+	r.pattern = nil
+}
+
+// Name returns the pseudonym of a hidden repository, or the name of any other
+func (r *Redactor) Name(fullName string) string {
+	if r == nil {
+		return fullName
+	}
+	if pseudonym, ok := r.names[strings.ToLower(fullName)]; ok {
+		return pseudonym
+	}
+	return fullName
+}
+
+// Apply replaces the names of hidden repositories in a rendered report, including within URLs
+// and paths. A name is only replaced as a whole, not as part of a longer name. A nil redactor
+// leaves the text unchanged.
+func (r *Redactor) Apply(text string) string {
+	if r == nil || len(r.names) == 0 {
+		return text
+	}
+	if r.pattern == nil {
+		names := make([]string, 0, len(r.names))
+		for name := range r.names {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+		// Prefer the longest name where one is a prefix of another
+		sort.Slice(names, func(i, j int) bool {
+			if len(names[i]) != len(names[j]) {
+				return len(names[i]) > len(names[j])
+			}
+			return names[i] < names[j]
+		})
+		r.pattern = regexp.MustCompile(`(?i)` + strings.Join(names, "|"))
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range r.pattern.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		if start > 0 && nameChar(text[start-1]) || continuesName(text[end:]) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(r.names[strings.ToLower(text[start:end])])
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// continuesName reports whether the text following a match continues the name, as in a longer
+// name such as owner/repo.js, rather than ending it, as does the period ending a sentence
+func continuesName(rest string) bool {
+	rest = strings.TrimLeft(rest, ".")
+	return rest != "" && nameChar(rest[0]) && rest[0] != '.'
+}
+
+// nameChar reports whether c can be part of an owner or repository name
+func nameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.'
+}
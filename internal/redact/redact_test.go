@@ -0,0 +1,53 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	r := New("key")
+	r.Hide("org/payments")
+	r.Hide("org/payments-api")
+	pseudonym := r.Name("org/payments")
+	if !strings.HasPrefix(pseudonym, "org/repo-") || pseudonym == r.Name("org/payments-api") {
+		t.Fatalf("Unexpected pseudonyms %q and %q", pseudonym, r.Name("org/payments-api"))
+	}
+
+	text := `## org/payments
+- ORG/Payments-api uses evil/action@v1 (https://github.com/org/payments/blob/main/.github/workflows/ci.yml)
+- org/payments.js and myorg/payments are other repositories
+Scanned org/payments.
+{"repository": "org/public"}`
+	expected := "## " + pseudonym + `
+- ` + r.Name("org/payments-api") + ` uses evil/action@v1 (https://github.com/` + pseudonym + `/blob/main/.github/workflows/ci.yml)
+- org/payments.js and myorg/payments are other repositories
+Scanned ` + pseudonym + `.
+{"repository": "org/public"}`
+	if redacted := r.Apply(text); redacted != expected {
+		t.Errorf("Apply() =\n%s\nwant\n%s", redacted, expected)
+	}
+}
+
+func TestPseudonyms(t *testing.T) {
+	a, b := New("key"), New("key")
+	a.Hide("org/payments")
+	b.Hide("Org/Payments")
+	if a.Name("org/payments") != b.Name("org/payments") {
+		t.Error("Expected the same key to give the same pseudonyms")
+	}
+
+	random := New("")
+	random.Hide("org/payments")
+	if random.Name("org/payments") == a.Name("org/payments") {
+		t.Error("Expected a random key to give other pseudonyms")
+	}
+
+	if name := a.Name("org/public"); name != "org/public" {
+		t.Errorf("Expected names not hidden to be kept, got %q", name)
+	}
+	var none *Redactor
+	if none.Apply("org/payments") != "org/payments" || none.Name("org/payments") != "org/payments" {
+		t.Error("Expected a nil redactor to leave names unchanged")
+	}
+}
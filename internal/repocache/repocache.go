@@ -0,0 +1,127 @@
+package repocache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// DefaultFile is the cache file name within the user cache directory
+const DefaultFile = "repositories.json"
+
+// entry is the cached repository listing of one organization
+type entry struct {
+	FetchedAt    time.Time           `json:"fetched_at"`
+	Repositories []github.Repository `json:"repositories"`
+}
+
+// Cache keeps organization repository listings in a JSON file for a limited time, so runs
+// within a short window don't enumerate every repository again. Listings are keyed by a scope
+// identifying the API endpoint and token, as another token may see other repositories.
+type Cache struct {
+	path  string
+	ttl   time.Duration
+	scope string
+	now   func() time.Time
+
+	mu sync.Mutex
+}
+
+// DefaultPath returns the cache file in the user cache directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "action-control", DefaultFile), nil
+}
+
+// Scope derives the cache scope from the forge provider, its API base URL and the token. The
+// token is only stored as a hash.
+func Scope(provider, baseURL, token string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{provider, baseURL, token}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// New returns a cache of listings in the file at path that are fresh for ttl
+func New(path string, ttl time.Duration, scope string) *Cache {
+	return &Cache{path: path, ttl: ttl, scope: scope, now: time.Now}
+}
+
+// key identifies the listing of org within the cache scope
+func (c *Cache) key(org string) string {
+	return c.scope + "/" + strings.ToLower(org)
+}
+
+// Repositories returns the cached listing of org if it is younger than the TTL
+func (c *Cache) Repositories(org string) ([]github.Repository, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.load()
+	if err != nil {
+		return nil, false
+	}
+	cached, ok := entries[c.key(org)]
+	if !ok || c.now().Sub(cached.FetchedAt) >= c.ttl {
+		return nil, false
+	}
+	return cached.Repositories, true
+}
+
+// StoreRepositories records the listing of org, dropping expired listings from the file
+func (c *Cache) StoreRepositories(org string, repos []github.Repository) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// An unreadable cache file is replaced
+	entries, err := c.load()
+	if err != nil {
+		entries = make(map[string]entry)
+	}
+	now := c.now()
+	for key, cached := range entries {
+		if now.Sub(cached.FetchedAt) >= c.ttl {
+			delete(entries, key)
+		}
+	}
+	entries[c.key(org)] = entry{FetchedAt: now.UTC(), Repositories: repos}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal repository cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create repository cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write repository cache: %w", err)
+	}
+	return nil
+}
+
+// load reads the cached listings. A missing file yields no listings.
+func (c *Cache) load() (map[string]entry, error) {
+	entries := make(map[string]entry)
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse repository cache: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,73 @@
+package repocache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", DefaultFile)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := New(path, time.Hour, Scope("github", "", "token"))
+	cache.now = func() time.Time { return now }
+
+	// A missing file is an empty cache
+	if _, ok := cache.Repositories("org"); ok {
+		t.Error("Expected empty cache to miss")
+	}
+
+	repos := []github.Repository{{Name: "repo", FullName: "org/repo", Visibility: "private", IsPrivate: true}}
+	if err := cache.StoreRepositories("org", repos); err != nil {
+		t.Fatalf("StoreRepositories returned error: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected cache file to be written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected cache file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// Fresh listings are returned, case-insensitively by organization
+	now = now.Add(30 * time.Minute)
+	cached, ok := cache.Repositories("ORG")
+	if !ok || len(cached) != 1 || cached[0] != repos[0] {
+		t.Errorf("Expected cached listing %+v, got %+v (hit: %v)", repos, cached, ok)
+	}
+
+	// Another token or endpoint does not share listings
+	other := New(path, time.Hour, Scope("github", "", "other-token"))
+	other.now = cache.now
+	if _, ok := other.Repositories("org"); ok {
+		t.Error("Expected listing of another scope to miss")
+	}
+
+	// Listings expire after the TTL
+	now = now.Add(30 * time.Minute)
+	if _, ok := cache.Repositories("org"); ok {
+		t.Error("Expected expired listing to miss")
+	}
+}
+
+func TestCacheCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A corrupt file misses and is replaced on the next store
+	cache := New(path, time.Hour, "scope")
+	if _, ok := cache.Repositories("org"); ok {
+		t.Error("Expected corrupt cache to miss")
+	}
+	if err := cache.StoreRepositories("org", []github.Repository{{Name: "repo"}}); err != nil {
+		t.Fatalf("StoreRepositories returned error: %v", err)
+	}
+	if _, ok := cache.Repositories("org"); !ok {
+		t.Error("Expected stored listing to hit")
+	}
+}
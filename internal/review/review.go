@@ -0,0 +1,378 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Entry is a unique action and the repositories using it
+type Entry struct {
+	Action    string              // Action reference without version
+	Usages    map[string][]string // Full references by repository
+	Violation bool                // Whether any usage violates the current policy
+}
+
+// Repos returns the repositories using the action, sorted
+func (e Entry) Repos() []string {
+	repos := make([]string, 0, len(e.Usages))
+	for repo := range e.Usages {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// Model is the bubbletea model for the review terminal UI
+type Model struct {
+	Policy  *policy.PolicyConfig
+	Added   []string      // Actions allowed during the session, prefixed with their custom rule
+	Edits   []policy.Edit // Changes to save to the policy file
+	Changed bool          // Whether the policy was modified
+
+	entries        []Entry
+	visible        []int // Indexes of entries matching the filter
+	cursor         int
+	selected       map[string]bool
+	filter         string
+	filtering      bool
+	onlyViolations bool
+	height         int
+}
+
+// NewModel builds a review model from discovered actions and the current policy
+func NewModel(actionsMap map[string][]github.Action, config *policy.PolicyConfig) *Model {
+	byAction := make(map[string]*Entry)
+	for repo, actions := range actionsMap {
+		for _, action := range actions {
+			name, _, _ := strings.Cut(action.Uses, "@")
+			entry, ok := byAction[name]
+			if !ok {
+				entry = &Entry{Action: name, Usages: make(map[string][]string)}
+				byAction[name] = entry
+			}
+			entry.Usages[repo] = append(entry.Usages[repo], action.Uses)
+		}
+	}
+
+	m := &Model{
+		Policy:   config,
+		selected: make(map[string]bool),
+		height:   20,
+	}
+	for _, entry := range byAction {
+		m.entries = append(m.entries, *entry)
+	}
+	sort.Slice(m.entries, func(i, j int) bool {
+		return m.entries[i].Action < m.entries[j].Action
+	})
+
+	m.evaluate()
+	m.applyFilter()
+	return m
+}
+
+// evaluate recomputes violation status for every entry against the policy
+func (m *Model) evaluate() {
+	for i := range m.entries {
+		m.entries[i].Violation = false
+		for repo, uses := range m.entries[i].Usages {
+			if _, compliant := policy.CheckActionCompliance(m.Policy, repo, uses); !compliant {
+				m.entries[i].Violation = true
+				break
+			}
+		}
+	}
+}
+
+// applyFilter recomputes the visible entries from the search filter and violation toggle
+func (m *Model) applyFilter() {
+	m.visible = m.visible[:0]
+	query := strings.ToLower(m.filter)
+
+	for i, entry := range m.entries {
+		if m.onlyViolations && !entry.Violation {
+			continue
+		}
+		if query != "" && !matchesQuery(entry, query) {
+			continue
+		}
+		m.visible = append(m.visible, i)
+	}
+
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// matchesQuery reports whether an entry's action or any of its repositories contains the query
+func matchesQuery(entry Entry, query string) bool {
+	if strings.Contains(strings.ToLower(entry.Action), query) {
+		return true
+	}
+	for repo := range entry.Usages {
+		if strings.Contains(strings.ToLower(repo), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// allow permits the given actions in the repositories using them, editing the list that
+// governs each repository: the list of its custom rule when the rule has its own, as adding
+// to an empty one would stop it inheriting the global list, or the global list otherwise.
+// In deny mode the action's entries are removed from the deny list, in allow mode the action
+// is added to the allow list, and in mixed mode both.
+func (m *Model) allow(actions []string) {
+	for _, action := range actions {
+		for _, entry := range m.entries {
+			if entry.Action != action {
+				continue
+			}
+			for _, repo := range entry.Repos() {
+				if _, compliant := policy.CheckActionCompliance(m.Policy, repo, entry.Usages[repo]); compliant {
+					continue
+				}
+				effective := policy.ResolveEffectivePolicy(m.Policy, repo)
+				if effective.PolicyMode == "deny" || effective.PolicyMode == "mixed" {
+					m.apply(m.governingEdit(effective, "denied_actions", action, true))
+				}
+				if effective.PolicyMode == "allow" || effective.PolicyMode == "mixed" {
+					m.apply(m.governingEdit(effective, "allowed_actions", action, false))
+				}
+			}
+		}
+	}
+
+	m.selected = make(map[string]bool)
+	m.evaluate()
+	m.applyFilter()
+}
+
+// governingEdit returns the edit of a list governing a repository with an effective policy
+func (m *Model) governingEdit(effective policy.EffectivePolicy, list, action string, remove bool) policy.Edit {
+	edit := policy.Edit{List: list, Action: action, Remove: remove}
+	if effective.CustomRule != "" && len(ruleList(m.Policy.CustomRules[effective.CustomRule], list)) > 0 {
+		edit.Rule = effective.CustomRule
+	}
+	return edit
+}
+
+// apply makes an edit to the policy and records it, unless it changes nothing
+func (m *Model) apply(edit policy.Edit) {
+	rule := policy.Policy{AllowedActions: m.Policy.AllowedActions, DeniedActions: m.Policy.DeniedActions}
+	if edit.Rule != "" {
+		rule = m.Policy.CustomRules[edit.Rule]
+	}
+	current := ruleList(rule, edit.List)
+
+	var updated []string
+	if edit.Remove {
+		for _, entry := range current {
+			if entry != edit.Action && !strings.HasPrefix(entry, edit.Action+"@") {
+				updated = append(updated, entry)
+			}
+		}
+		if len(updated) == len(current) {
+			return
+		}
+	} else {
+		if containsString(current, edit.Action) {
+			return
+		}
+		updated = append(append([]string(nil), current...), edit.Action)
+	}
+
+	added := edit.Action
+	if edit.Rule != "" {
+		if edit.List == "denied_actions" {
+			rule.DeniedActions = updated
+		} else {
+			rule.AllowedActions = updated
+		}
+		m.Policy.CustomRules[edit.Rule] = rule
+		added = fmt.Sprintf("%s: %s", edit.Rule, edit.Action)
+	} else if edit.List == "denied_actions" {
+		m.Policy.DeniedActions = updated
+	} else {
+		m.Policy.AllowedActions = updated
+	}
+
+	if !containsString(m.Added, added) {
+		m.Added = append(m.Added, added)
+	}
+	m.Edits = append(m.Edits, edit)
+	m.Changed = true
+}
+
+// ruleList returns the allowed_actions or denied_actions list of a policy
+func ruleList(rule policy.Policy, list string) []string {
+	if list == "denied_actions" {
+		return rule.DeniedActions
+	}
+	return rule.AllowedActions
+}
+
+// Init implements tea.Model
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height - 8
+		if m.height < 5 {
+			m.height = 5
+		}
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.visible)-1 {
+				m.cursor++
+			}
+		case "/":
+			m.filtering = true
+		case "v":
+			m.onlyViolations = !m.onlyViolations
+			m.applyFilter()
+		case " ":
+			if entry, ok := m.current(); ok {
+				m.selected[entry.Action] = !m.selected[entry.Action]
+			}
+		case "a":
+			var actions []string
+			for action, selected := range m.selected {
+				if selected {
+					actions = append(actions, action)
+				}
+			}
+			if len(actions) == 0 {
+				if entry, ok := m.current(); ok {
+					actions = []string{entry.Action}
+				}
+			}
+			sort.Strings(actions)
+			m.allow(actions)
+		}
+	}
+
+	return m, nil
+}
+
+// updateFilter handles key presses while the search filter is being edited
+func (m *Model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter, tea.KeyEsc:
+		m.filtering = false
+	case tea.KeyBackspace:
+		if len(m.filter) > 0 {
+			m.filter = m.filter[:len(m.filter)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+	}
+
+	m.applyFilter()
+	return m, nil
+}
+
+// current returns the entry under the cursor
+func (m *Model) current() (Entry, bool) {
+	if len(m.visible) == 0 {
+		return Entry{}, false
+	}
+	return m.entries[m.visible[m.cursor]], true
+}
+
+// View implements tea.Model
+func (m *Model) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Action Control Review (%s mode) — %d actions", m.Policy.PolicyMode, len(m.entries)))
+	if m.onlyViolations {
+		sb.WriteString(", violations only")
+	}
+	sb.WriteString("\n")
+
+	if m.filtering || m.filter != "" {
+		sb.WriteString(fmt.Sprintf("Filter: %s", m.filter))
+		if m.filtering {
+			sb.WriteString("█")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	// Scroll so the cursor stays within the visible window
+	start := 0
+	if m.cursor >= m.height {
+		start = m.cursor - m.height + 1
+	}
+	end := start + m.height
+	if end > len(m.visible) {
+		end = len(m.visible)
+	}
+
+	for i := start; i < end; i++ {
+		entry := m.entries[m.visible[i]]
+
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if m.selected[entry.Action] {
+			check = "[x]"
+		}
+		status := "✅"
+		if entry.Violation {
+			status = "❌"
+		}
+
+		sb.WriteString(fmt.Sprintf("%s%s %s %s (%d repos)\n", cursor, check, status, entry.Action, len(entry.Usages)))
+	}
+
+	if len(m.visible) == 0 {
+		sb.WriteString("  No matching actions\n")
+	}
+
+	if entry, ok := m.current(); ok {
+		sb.WriteString(fmt.Sprintf("\nUsed in: %s\n", strings.Join(entry.Repos(), ", ")))
+	}
+
+	sb.WriteString("\n↑/↓ move • space select • a allow • / search • v violations only • q save and quit\n")
+
+	return sb.String()
+}
+
+// containsString checks if a string slice contains a specific string
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package review
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testActions() map[string][]github.Action {
+	return map[string][]github.Action{
+		"org/repo1": {
+			{Uses: "actions/checkout@v4"},
+			{Uses: "third/party@v1"},
+		},
+		"org/repo2": {
+			{Uses: "actions/checkout@v3"},
+			{Uses: "other/tool@main"},
+		},
+	}
+}
+
+func press(m *Model, keys ...string) {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestNewModel(t *testing.T) {
+	config := &policy.PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+	m := NewModel(testActions(), config)
+
+	if len(m.entries) != 3 {
+		t.Fatalf("Expected 3 unique actions, got %d", len(m.entries))
+	}
+
+	checkout := m.entries[0]
+	if checkout.Action != "actions/checkout" || checkout.Violation {
+		t.Errorf("Expected compliant actions/checkout entry first, got %+v", checkout)
+	}
+	if !reflect.DeepEqual(checkout.Repos(), []string{"org/repo1", "org/repo2"}) {
+		t.Errorf("Expected checkout to be used in both repos, got %v", checkout.Repos())
+	}
+
+	if !m.entries[1].Violation || !m.entries[2].Violation {
+		t.Error("Expected actions missing from allow list to be violations")
+	}
+}
+
+func TestFilterAndAllow(t *testing.T) {
+	config := &policy.PolicyConfig{PolicyMode: "allow", AllowedActions: []string{"actions/checkout"}}
+	m := NewModel(testActions(), config)
+
+	// Search for the third-party action and allow it
+	press(m, "/", "t", "h", "i", "r", "d", "enter")
+	if len(m.visible) != 1 {
+		t.Fatalf("Expected 1 action matching filter, got %d", len(m.visible))
+	}
+	press(m, "a")
+
+	if !reflect.DeepEqual(config.AllowedActions, []string{"actions/checkout", "third/party"}) {
+		t.Errorf("Unexpected allowed actions: %v", config.AllowedActions)
+	}
+	if !m.Changed || !reflect.DeepEqual(m.Added, []string{"third/party"}) {
+		t.Errorf("Expected third/party to be recorded as added, got %v", m.Added)
+	}
+
+	// Clear the filter; the violations-only view now shows just the remaining violation
+	press(m, "/")
+	for range "third" {
+		m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	press(m, "enter", "v")
+	if len(m.visible) != 1 || m.entries[m.visible[0]].Action != "other/tool" {
+		t.Errorf("Expected only other/tool to remain in violations view")
+	}
+
+	if !strings.Contains(m.View(), "other/tool") {
+		t.Error("Expected view to render remaining violation")
+	}
+}
+
+func TestAllowSelectedDenyMode(t *testing.T) {
+	config := &policy.PolicyConfig{PolicyMode: "deny", DeniedActions: []string{"third/party", "other/tool@main"}}
+	m := NewModel(testActions(), config)
+
+	// Select both denied actions and allow them together
+	press(m, "j", " ", "j", " ", "a")
+
+	if len(config.DeniedActions) != 0 {
+		t.Errorf("Expected denied actions to be removed, got %v", config.DeniedActions)
+	}
+	for _, entry := range m.entries {
+		if entry.Violation {
+			t.Errorf("Expected no violations after allowing, got %s", entry.Action)
+		}
+	}
+}
+
+func TestAllowInCustomRule(t *testing.T) {
+	config := &policy.PolicyConfig{
+		PolicyMode:     "allow",
+		AllowedActions: []string{"actions/checkout"},
+		CustomRules: map[string]policy.Policy{
+			"org/repo1": {AllowedActions: []string{"actions/checkout"}},
+			"org/repo2": {PolicyMode: "allow"},
+		},
+	}
+	m := NewModel(testActions(), config)
+
+	// third/party is only used in org/repo1, whose custom rule has its own allow list
+	press(m, "/", "t", "h", "i", "r", "d", "enter", "a")
+	if !reflect.DeepEqual(config.CustomRules["org/repo1"].AllowedActions, []string{"actions/checkout", "third/party"}) {
+		t.Errorf("Expected third/party in the custom rule of org/repo1, got %v", config.CustomRules["org/repo1"].AllowedActions)
+	}
+	if !reflect.DeepEqual(config.AllowedActions, []string{"actions/checkout"}) {
+		t.Errorf("Expected the global allow list to be unchanged, got %v", config.AllowedActions)
+	}
+
+	// org/repo2's rule inherits the global list, which an entry of its own would replace
+	press(m, "/")
+	for range "third" {
+		m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	press(m, "o", "t", "h", "e", "r", "enter", "a")
+	if len(config.CustomRules["org/repo2"].AllowedActions) != 0 || !reflect.DeepEqual(config.AllowedActions, []string{"actions/checkout", "other/tool"}) {
+		t.Errorf("Expected other/tool in the global allow list, got %v and %v", config.AllowedActions, config.CustomRules["org/repo2"].AllowedActions)
+	}
+
+	expectedEdits := []policy.Edit{
+		{Rule: "org/repo1", List: "allowed_actions", Action: "third/party"},
+		{List: "allowed_actions", Action: "other/tool"},
+	}
+	if !reflect.DeepEqual(m.Edits, expectedEdits) {
+		t.Errorf("Expected edits %+v, got %+v", expectedEdits, m.Edits)
+	}
+	if !reflect.DeepEqual(m.Added, []string{"org/repo1: third/party", "other/tool"}) {
+		t.Errorf("Unexpected added actions: %v", m.Added)
+	}
+}
@@ -0,0 +1,132 @@
+package runtimes
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// Reasons an action's runtime fails the policy
+const (
+	ReasonUnknownRegistry = "unknown_registry" // A Docker action's image comes from a registry not in allowed_registries
+	ReasonDeprecatedNode  = "deprecated_node"  // A JavaScript action runs on a deprecated Node.js runtime
+)
+
+// DeprecatedNodeRuntimes are the Node.js runtimes GitHub has deprecated for actions
+var DeprecatedNodeRuntimes = []string{"node12", "node16"}
+
+// Fetcher classifies how an action runs from its action.yml
+type Fetcher interface {
+	GetActionRuntime(ctx context.Context, action string) (github.ActionRuntime, error)
+}
+
+// Finding is an action whose runtime fails a runtime rule of the policy
+type Finding struct {
+	Action  string               `json:"action"`
+	Reason  string               `json:"reason"`
+	Image   string               `json:"image,omitempty"` // Image from an unknown registry
+	Runtime github.ActionRuntime `json:"runtime"`
+}
+
+// HasRules reports whether the policy sets any rule on how actions run
+func HasRules(config *policy.PolicyConfig) bool {
+	return config.DenyDockerActionsFromUnknownRegistries || config.DenyNode16Actions
+}
+
+// Check classifies each action and reports Docker actions whose image, or for actions built
+// from a Dockerfile any of its base images, comes from a registry not in allowed_registries when
+// the policy sets deny_docker_actions_from_unknown_registries, and JavaScript actions running on
+// a deprecated Node.js runtime when it sets deny_node16_actions. Local actions and actions whose
+// action.yml cannot be read are not reported, nor are actions in excluded repositories.
+func Check(ctx context.Context, config *policy.PolicyConfig, repoName string, actions []string, fetcher Fetcher) []Finding {
+	if !HasRules(config) || policy.ResolveEffectivePolicy(config, repoName).Excluded {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var findings []Finding
+	for _, action := range actions {
+		if seen[action] || strings.HasPrefix(action, "./") {
+			continue
+		}
+		seen[action] = true
+
+		runtime, err := fetcher.GetActionRuntime(ctx, action)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case config.DenyDockerActionsFromUnknownRegistries && runtime.Kind == github.ActionKindDocker:
+			if image := unknownRegistryImage(config, runtime); image != "" {
+				findings = append(findings, Finding{Action: action, Reason: ReasonUnknownRegistry, Image: image, Runtime: runtime})
+			}
+		case config.DenyNode16Actions && slices.Contains(DeprecatedNodeRuntimes, runtime.Using):
+			findings = append(findings, Finding{Action: action, Reason: ReasonDeprecatedNode, Runtime: runtime})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Action < findings[j].Action
+	})
+	return findings
+}
+
+// unknownRegistryImage returns the first image a Docker action runs or is built from whose
+// registry is not allowed, or an empty string when all are
+func unknownRegistryImage(config *policy.PolicyConfig, runtime github.ActionRuntime) string {
+	images := runtime.BaseImages
+	if !runtime.BuiltFromSource() {
+		images = []string{runtime.Image}
+	}
+	for _, image := range images {
+		if !slices.Contains(config.AllowedRegistries, policy.ImageRegistry(image)) {
+			return image
+		}
+	}
+	return ""
+}
+
+// Report is the runtime of every third-party action used across repositories
+type Report struct {
+	Actions    []github.ActionRuntime `json:"actions"`
+	Counts     map[string]int         `json:"counts"`               // Actions by kind, "unknown" for unrecognized runtimes
+	Unresolved []string               `json:"unresolved,omitempty"` // Actions whose action.yml could not be read
+}
+
+// Classify looks up the runtime of every distinct third-party action reference, sorted by
+// reference. Local actions are skipped.
+func Classify(ctx context.Context, actionsByRepo map[string][]github.Action, fetcher Fetcher) Report {
+	seen := make(map[string]bool)
+	var references []string
+	for _, actions := range actionsByRepo {
+		for _, action := range actions {
+			if seen[action.Uses] || strings.HasPrefix(action.Uses, "./") {
+				continue
+			}
+			seen[action.Uses] = true
+			references = append(references, action.Uses)
+		}
+	}
+	sort.Strings(references)
+
+	report := Report{Counts: make(map[string]int)}
+	for _, reference := range references {
+		runtime, err := fetcher.GetActionRuntime(ctx, reference)
+		if err != nil {
+			report.Unresolved = append(report.Unresolved, reference)
+			continue
+		}
+		report.Actions = append(report.Actions, runtime)
+		kind := runtime.Kind
+		if kind == "" {
+			kind = "unknown"
+		}
+		report.Counts[kind]++
+	}
+	return report
+}
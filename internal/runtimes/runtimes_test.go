@@ -0,0 +1,126 @@
+package runtimes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// fakeFetcher serves action runtimes from a map keyed by action reference
+type fakeFetcher map[string]github.ActionRuntime
+
+func (f fakeFetcher) GetActionRuntime(ctx context.Context, action string) (github.ActionRuntime, error) {
+	runtime, ok := f[action]
+	if !ok {
+		return github.ActionRuntime{}, errors.New("not found")
+	}
+	runtime.Action = action
+	return runtime, nil
+}
+
+var fetcher = fakeFetcher{
+	"actions/checkout@v3":  {Kind: github.ActionKindJavaScript, Using: "node16"},
+	"actions/checkout@v4":  {Kind: github.ActionKindJavaScript, Using: "node20"},
+	"old/action@v1":        {Kind: github.ActionKindJavaScript, Using: "node12"},
+	"org/prebuilt@v1":      {Kind: github.ActionKindDocker, Using: "docker", Image: "docker://ghcr.io/org/prebuilt:1"},
+	"other/prebuilt@v1":    {Kind: github.ActionKindDocker, Using: "docker", Image: "docker://registry.example.com/tool:1"},
+	"org/built@v1":         {Kind: github.ActionKindDocker, Using: "docker", Image: "Dockerfile", BaseImages: []string{"ghcr.io/org/base:1", "alpine:3"}},
+	"org/composite@v1":     {Kind: github.ActionKindComposite, Using: "composite"},
+	"docker://alpine:3":    {Kind: github.ActionKindDocker, Using: "docker", Image: "docker://alpine:3"},
+	"docker://ghcr.io/x:1": {Kind: github.ActionKindDocker, Using: "docker", Image: "docker://ghcr.io/x:1"},
+}
+
+func TestCheck(t *testing.T) {
+	actions := []string{
+		"actions/checkout@v3",
+		"actions/checkout@v3",
+		"actions/checkout@v4",
+		"old/action@v1",
+		"org/prebuilt@v1",
+		"other/prebuilt@v1",
+		"org/built@v1",
+		"org/composite@v1",
+		"docker://alpine:3",
+		"docker://ghcr.io/x:1",
+		"unknown/action@v1",
+		"./local-action",
+	}
+
+	tests := []struct {
+		name     string
+		config   *policy.PolicyConfig
+		expected map[string]string
+	}{
+		{
+			name:     "no rules",
+			config:   &policy.PolicyConfig{},
+			expected: map[string]string{},
+		},
+		{
+			name:   "deprecated node",
+			config: &policy.PolicyConfig{DenyNode16Actions: true},
+			expected: map[string]string{
+				"actions/checkout@v3": ReasonDeprecatedNode,
+				"old/action@v1":       ReasonDeprecatedNode,
+			},
+		},
+		{
+			name:   "unknown registries",
+			config: &policy.PolicyConfig{DenyDockerActionsFromUnknownRegistries: true, AllowedRegistries: []string{"ghcr.io"}},
+			expected: map[string]string{
+				"other/prebuilt@v1": ReasonUnknownRegistry,
+				"org/built@v1":      ReasonUnknownRegistry,
+				"docker://alpine:3": ReasonUnknownRegistry,
+			},
+		},
+		{
+			name:     "excluded repository",
+			config:   &policy.PolicyConfig{DenyNode16Actions: true, ExcludedRepos: []string{"org/repo"}},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Check(context.Background(), tt.config, "org/repo", actions, fetcher)
+			if len(findings) != len(tt.expected) {
+				t.Fatalf("Expected %d findings, got %+v", len(tt.expected), findings)
+			}
+			for i, finding := range findings {
+				if tt.expected[finding.Action] != finding.Reason {
+					t.Errorf("Unexpected finding %+v", finding)
+				}
+				if i > 0 && findings[i-1].Action > finding.Action {
+					t.Errorf("Expected findings sorted by action")
+				}
+			}
+		})
+	}
+
+	// The first base image from an unknown registry is reported
+	findings := Check(context.Background(), &policy.PolicyConfig{DenyDockerActionsFromUnknownRegistries: true, AllowedRegistries: []string{"ghcr.io"}}, "org/repo", []string{"org/built@v1"}, fetcher)
+	if len(findings) != 1 || findings[0].Image != "alpine:3" {
+		t.Errorf("Expected alpine:3 to be reported, got %+v", findings)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	actionsByRepo := map[string][]github.Action{
+		"org/repo1": {{Uses: "actions/checkout@v4"}, {Uses: "org/built@v1"}, {Uses: "./local"}},
+		"org/repo2": {{Uses: "actions/checkout@v4"}, {Uses: "org/composite@v1"}, {Uses: "unknown/action@v1"}},
+	}
+
+	report := Classify(context.Background(), actionsByRepo, fetcher)
+	if len(report.Actions) != 3 || report.Actions[0].Action != "actions/checkout@v4" {
+		t.Fatalf("Expected 3 sorted actions, got %+v", report.Actions)
+	}
+	if report.Counts[github.ActionKindJavaScript] != 1 || report.Counts[github.ActionKindDocker] != 1 || report.Counts[github.ActionKindComposite] != 1 {
+		t.Errorf("Unexpected counts %v", report.Counts)
+	}
+	if len(report.Unresolved) != 1 || report.Unresolved[0] != "unknown/action@v1" {
+		t.Errorf("Expected unknown/action@v1 to be unresolved, got %v", report.Unresolved)
+	}
+}
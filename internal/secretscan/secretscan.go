@@ -0,0 +1,64 @@
+package secretscan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// patterns recognize credentials by the distinctive formats their issuers give them, rather than
+// by entropy, so commit SHAs and digests pinned in policies are not mistaken for secrets
+var patterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"GitHub fine-grained personal access token", regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`)},
+	{"GitLab personal access token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{"AWS access key ID", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"Slack webhook URL", regexp.MustCompile(`https://hooks\.slack\.com/services/[A-Za-z0-9/]+`)},
+	{"Atlassian API token", regexp.MustCompile(`\bATATT3[A-Za-z0-9_=-]{20,}`)},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+}
+
+// Finding is a credential found in a file, by line and kind. The credential itself is not kept,
+// so findings can be reported without disclosing it.
+type Finding struct {
+	Line int
+	Kind string
+}
+
+// String returns the line and kind of the finding
+func (f Finding) String() string {
+	return fmt.Sprintf("%s on line %d", f.Kind, f.Line)
+}
+
+// Scan returns the credentials found in data, in line order
+func Scan(data []byte) []Finding {
+	var findings []Finding
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// Policies and configs embed long base64 values on a single line
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		for _, p := range patterns {
+			if p.pattern.Match(scanner.Bytes()) {
+				findings = append(findings, Finding{Line: line, Kind: p.kind})
+			}
+		}
+	}
+	return findings
+}
+
+// Describe lists findings for an error message
+func Describe(findings []Finding) string {
+	descriptions := make([]string, len(findings))
+	for i, finding := range findings {
+		descriptions[i] = finding.String()
+	}
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,261 @@
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Repository publishing the release binaries
+const (
+	Owner = "ihavespoons"
+	Repo  = "action-control"
+)
+
+// ChecksumsAsset is the release asset listing the SHA-256 checksum of every binary
+const ChecksumsAsset = "checksums.txt"
+
+// SignatureAsset is the release asset holding the Ed25519 signature of ChecksumsAsset
+const SignatureAsset = ChecksumsAsset + ".sig"
+
+// SigningKey is the base64-encoded PKIX Ed25519 public key release checksums are signed with,
+// pinned at build time via
+// -ldflags "-X github.com/ihavespoons/action-control/internal/selfupdate.SigningKey=MCow..."
+// Builds without one cannot verify releases and refuse to update.
+var SigningKey = ""
+
+// provenanceSuffix is appended to a binary's name for its SLSA provenance asset
+const provenanceSuffix = ".intoto.jsonl"
+
+// ErrNoChecksum is returned when a release does not publish a checksum for a binary
+var ErrNoChecksum = errors.New("release has no checksum for the binary")
+
+// ErrNoSigningKey is returned by builds without a pinned SigningKey
+var ErrNoSigningKey = errors.New("this build has no release signing key to verify updates with; download the release manually")
+
+// ReleaseSource looks up releases and downloads their assets
+type ReleaseSource interface {
+	GetLatestRelease(ctx context.Context, owner, repo string) (github.Release, error)
+	GetReleaseByTag(ctx context.Context, owner, repo, tag string) (github.Release, error)
+	DownloadReleaseAsset(ctx context.Context, owner, repo string, id int64) ([]byte, error)
+}
+
+// AssetName returns the name of the release binary for an operating system and architecture
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("%s-%s-%s", Repo, goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// FindRelease returns the release for a tag, or the latest release when tag is empty
+func FindRelease(ctx context.Context, source ReleaseSource, tag string) (github.Release, error) {
+	if tag == "" {
+		return source.GetLatestRelease(ctx, Owner, Repo)
+	}
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	return source.GetReleaseByTag(ctx, Owner, Repo, tag)
+}
+
+// Download fetches the binary of a release for an operating system and architecture and
+// verifies it against the release checksums, whose signature must verify with the pinned
+// SigningKey: checksums alone come from the same place as the binary they vouch for. When
+// the release carries SLSA provenance for the binary, the digest it attests must match too.
+func Download(ctx context.Context, source ReleaseSource, release github.Release, goos, goarch string) ([]byte, error) {
+	if SigningKey == "" {
+		return nil, ErrNoSigningKey
+	}
+	name := AssetName(goos, goarch)
+	id, ok := release.Assets[name]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no binary for %s/%s", release.Tag, goos, goarch)
+	}
+	checksumsID, ok := release.Assets[ChecksumsAsset]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s to verify the binary with", release.Tag, ChecksumsAsset)
+	}
+	signatureID, ok := release.Assets[SignatureAsset]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s to verify its checksums with", release.Tag, SignatureAsset)
+	}
+
+	checksums, err := source.DownloadReleaseAsset(ctx, Owner, Repo, checksumsID)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := source.DownloadReleaseAsset(ctx, Owner, Repo, signatureID)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifySignature(checksums, signature, SigningKey); err != nil {
+		return nil, fmt.Errorf("release %s: %w", release.Tag, err)
+	}
+
+	binary, err := source.DownloadReleaseAsset(ctx, Owner, Repo, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyChecksum(binary, name, checksums); err != nil {
+		return nil, err
+	}
+
+	if provenanceID, ok := release.Assets[name+provenanceSuffix]; ok {
+		provenance, err := source.DownloadReleaseAsset(ctx, Owner, Repo, provenanceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := VerifyProvenance(binary, name, provenance); err != nil {
+			return nil, err
+		}
+	}
+
+	return binary, nil
+}
+
+// VerifySignature checks the Ed25519 signature of a checksums file with a base64-encoded PKIX
+// public key, as written by openssl pkeyutl -sign -rawin
+func VerifySignature(checksums, signature []byte, publicKey string) error {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil {
+		return fmt.Errorf("invalid release signing key: %w", err)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return fmt.Errorf("invalid release signing key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return fmt.Errorf("invalid release signing key: not an Ed25519 key")
+	}
+	if !ed25519.Verify(key, checksums, signature) {
+		return fmt.Errorf("signature of %s does not verify with the release signing key", ChecksumsAsset)
+	}
+	return nil
+}
+
+// VerifyChecksum checks a binary against its entry in a sha256sum-style checksums file
+func VerifyChecksum(binary []byte, name string, checksums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		if digest := sha256Hex(binary); !strings.EqualFold(fields[0], digest) {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, fields[0], digest)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w %s", ErrNoChecksum, name)
+}
+
+// VerifyProvenance checks that the SLSA provenance of a release attests the binary's digest.
+// It matches the in-toto statement's subjects only; verifying the provenance signature itself
+// is left to slsa-verifier.
+func VerifyProvenance(binary []byte, name string, provenance []byte) error {
+	digest := sha256Hex(binary)
+
+	// Each line is a DSSE envelope whose payload is an in-toto statement
+	scanner := bufio.NewScanner(bytes.NewReader(provenance))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var envelope struct {
+			Payload string `json:"payload"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &envelope); err != nil {
+			return fmt.Errorf("invalid provenance for %s: %w", name, err)
+		}
+		payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			return fmt.Errorf("invalid provenance payload for %s: %w", name, err)
+		}
+
+		var statement struct {
+			Subject []struct {
+				Name   string            `json:"name"`
+				Digest map[string]string `json:"digest"`
+			} `json:"subject"`
+		}
+		if err := json.Unmarshal(payload, &statement); err != nil {
+			return fmt.Errorf("invalid provenance statement for %s: %w", name, err)
+		}
+		for _, subject := range statement.Subject {
+			if subject.Name == name && strings.EqualFold(subject.Digest["sha256"], digest) {
+				return nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading provenance for %s: %w", name, err)
+	}
+
+	return fmt.Errorf("provenance does not attest %s with digest %s", name, digest)
+}
+
+// Replace atomically replaces the executable at path with binary. The new binary is written
+// next to the executable first, so a failed update leaves the original in place. Windows
+// cannot overwrite a running executable, so the original is moved aside to path.old first.
+func Replace(path string, binary []byte, goos string) error {
+	dir := filepath.Dir(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat executable: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed into place
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0o111); err != nil {
+		return fmt.Errorf("failed to make new binary executable: %w", err)
+	}
+
+	if goos == "windows" {
+		old := path + ".old"
+		os.Remove(old) // Left behind by a previous update
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("failed to move current executable aside: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			os.Rename(old, path) // Restore the original
+			return fmt.Errorf("failed to replace executable: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace executable: %w", err)
+	}
+	return nil
+}
+
+// sha256Hex returns the hex-encoded SHA-256 digest of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,187 @@
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// mockSource serves releases and assets from memory
+type mockSource struct {
+	latest github.Release
+	tagged map[string]github.Release
+	assets map[int64][]byte
+}
+
+func (m *mockSource) GetLatestRelease(ctx context.Context, owner, repo string) (github.Release, error) {
+	return m.latest, nil
+}
+
+func (m *mockSource) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (github.Release, error) {
+	release, ok := m.tagged[tag]
+	if !ok {
+		return github.Release{}, fmt.Errorf("release %s not found", tag)
+	}
+	return release, nil
+}
+
+func (m *mockSource) DownloadReleaseAsset(ctx context.Context, owner, repo string, id int64) ([]byte, error) {
+	return m.assets[id], nil
+}
+
+func provenanceFor(name string, binary []byte) []byte {
+	statement := fmt.Sprintf(`{"subject":[{"name":%q,"digest":{"sha256":%q}}]}`, name, sha256Hex(binary))
+	return []byte(fmt.Sprintf(`{"payload":%q}`+"\n", base64.StdEncoding.EncodeToString([]byte(statement))))
+}
+
+// pinSigningKey pins a new release signing key for the test and returns its private key
+func pinSigningKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := SigningKey
+	SigningKey = base64.StdEncoding.EncodeToString(der)
+	t.Cleanup(func() { SigningKey = previous })
+	return private
+}
+
+func TestAssetName(t *testing.T) {
+	if name := AssetName("linux", "arm64"); name != "action-control-linux-arm64" {
+		t.Errorf("Unexpected asset name %q", name)
+	}
+	if name := AssetName("windows", "amd64"); name != "action-control-windows-amd64.exe" {
+		t.Errorf("Unexpected Windows asset name %q", name)
+	}
+}
+
+func TestFindRelease(t *testing.T) {
+	source := &mockSource{
+		latest: github.Release{Tag: "v2.0.0"},
+		tagged: map[string]github.Release{"v1.5.0": {Tag: "v1.5.0"}},
+	}
+
+	if release, err := FindRelease(context.Background(), source, ""); err != nil || release.Tag != "v2.0.0" {
+		t.Errorf("Expected latest release, got %+v, %v", release, err)
+	}
+	if release, err := FindRelease(context.Background(), source, "1.5.0"); err != nil || release.Tag != "v1.5.0" {
+		t.Errorf("Expected tagged release, got %+v, %v", release, err)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	name := AssetName("linux", "amd64")
+	binary := []byte("new binary")
+	checksums := []byte(fmt.Sprintf("%s  %s\n%s  other-binary\n", sha256Hex(binary), name, sha256Hex([]byte("other"))))
+	release := github.Release{Tag: "v2.0.0", Assets: map[string]int64{name: 1, ChecksumsAsset: 2, name + provenanceSuffix: 3, SignatureAsset: 4}}
+	signature := ed25519.Sign(pinSigningKey(t), checksums)
+
+	t.Run("verified", func(t *testing.T) {
+		source := &mockSource{assets: map[int64][]byte{1: binary, 2: checksums, 3: provenanceFor(name, binary), 4: signature}}
+		content, err := Download(context.Background(), source, release, "linux", "amd64")
+		if err != nil || string(content) != string(binary) {
+			t.Errorf("Expected verified binary, got %q, %v", content, err)
+		}
+	})
+
+	t.Run("tampered binary", func(t *testing.T) {
+		source := &mockSource{assets: map[int64][]byte{1: []byte("tampered"), 2: checksums, 3: provenanceFor(name, binary), 4: signature}}
+		if _, err := Download(context.Background(), source, release, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Errorf("Expected checksum mismatch, got %v", err)
+		}
+	})
+
+	t.Run("checksums of a tampered binary", func(t *testing.T) {
+		tampered := []byte(fmt.Sprintf("%s  %s\n", sha256Hex([]byte("tampered")), name))
+		source := &mockSource{assets: map[int64][]byte{1: []byte("tampered"), 2: tampered, 4: signature}}
+		if _, err := Download(context.Background(), source, release, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "does not verify") {
+			t.Errorf("Expected a signature mismatch, got %v", err)
+		}
+	})
+
+	t.Run("unsigned checksums", func(t *testing.T) {
+		unsigned := github.Release{Tag: "v2.0.0", Assets: map[string]int64{name: 1, ChecksumsAsset: 2}}
+		source := &mockSource{assets: map[int64][]byte{1: binary, 2: checksums}}
+		if _, err := Download(context.Background(), source, unsigned, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), SignatureAsset) {
+			t.Errorf("Expected an error for a release without a signature, got %v", err)
+		}
+	})
+
+	t.Run("no pinned key", func(t *testing.T) {
+		previous := SigningKey
+		SigningKey = ""
+		defer func() { SigningKey = previous }()
+		source := &mockSource{assets: map[int64][]byte{1: binary, 2: checksums, 4: signature}}
+		if _, err := Download(context.Background(), source, release, "linux", "amd64"); !errors.Is(err, ErrNoSigningKey) {
+			t.Errorf("Expected ErrNoSigningKey, got %v", err)
+		}
+	})
+
+	t.Run("provenance for another binary", func(t *testing.T) {
+		source := &mockSource{assets: map[int64][]byte{1: binary, 2: checksums, 3: provenanceFor(name, []byte("other")), 4: signature}}
+		if _, err := Download(context.Background(), source, release, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "provenance does not attest") {
+			t.Errorf("Expected provenance mismatch, got %v", err)
+		}
+	})
+
+	t.Run("missing platform", func(t *testing.T) {
+		if _, err := Download(context.Background(), &mockSource{}, release, "plan9", "amd64"); err == nil {
+			t.Error("Expected an error for a platform without a binary")
+		}
+	})
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	binary := []byte("binary")
+	if err := VerifyChecksum(binary, "tool", []byte(sha256Hex(binary)+" *tool\n")); err != nil {
+		t.Errorf("Expected binary-mode checksum entry to verify, got %v", err)
+	}
+	if err := VerifyChecksum(binary, "tool", []byte("")); !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("Expected ErrNoChecksum, got %v", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	for _, goos := range []string{"linux", "windows"} {
+		t.Run(goos, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "action-control")
+			if err := os.WriteFile(path, []byte("old"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := Replace(path, []byte("new"), goos); err != nil {
+				t.Fatalf("Replace returned error: %v", err)
+			}
+			content, _ := os.ReadFile(path)
+			if string(content) != "new" {
+				t.Errorf("Expected replaced executable, got %q", content)
+			}
+			if info, _ := os.Stat(path); info.Mode().Perm()&0o100 == 0 {
+				t.Errorf("Expected executable permissions, got %v", info.Mode())
+			}
+
+			entries, _ := os.ReadDir(filepath.Dir(path))
+			expected := 1
+			if goos == "windows" {
+				expected = 2 // The original is kept as action-control.old
+			}
+			if len(entries) != expected {
+				t.Errorf("Expected %d files after replacing, got %d", expected, len(entries))
+			}
+		})
+	}
+}
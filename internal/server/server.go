@@ -0,0 +1,389 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/store"
+)
+
+// Scanner runs an enforce scan of an organization or owner/repo repository, recording its
+// outcome in the store the server reads
+type Scanner func(ctx context.Context, target string) error
+
+// ui holds the web UI, a single page querying the API
+//
+//go:embed ui
+var ui embed.FS
+
+// Gate evaluates the workflow files a pull request of an owner/repo repository changes,
+// setting the status check of its head commit
+type Gate func(ctx context.Context, repo string, number int, headSHA string) error
+
+// PolicyFile is a policy file the scans the server runs enforce
+type PolicyFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// Config configures a server
+type Config struct {
+	Store store.Store
+	Token string  // Bearer token requests other than health checks and the web UI must present, if not empty
+	Scan  Scanner // Runs the scans requested with POST /scan
+	// Organization limits the scans requested with POST /scan to the organization and its
+	// repositories
+	Organization string
+	// Policy returns the policy files scans enforce, shown in the web UI; nil when unknown
+	Policy func() ([]PolicyFile, error)
+	// WebhookSecret verifies the signatures of GitHub webhook deliveries, which are refused
+	// without it
+	WebhookSecret string
+	Gate          Gate // Evaluates the pull requests webhooks announce
+}
+
+// Server answers queries about the policy posture recorded in a store, and starts scans
+type Server struct {
+	config  Config
+	ctx     context.Context // Cancels running scans when the server shuts down
+	mu      sync.Mutex
+	running map[string]time.Time // Start times of running scans by lower-case target
+	wg      sync.WaitGroup
+}
+
+// New returns a server reading runs from the configured store
+func New(ctx context.Context, config Config) *Server {
+	return &Server{config: config, ctx: ctx, running: make(map[string]time.Time)}
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /runs", s.authorized(s.listRuns))
+	mux.Handle("GET /runs/{id}", s.authorized(s.getRun))
+	mux.Handle("GET /orgs/{org}/violations", s.authorized(s.orgViolations))
+	mux.Handle("GET /repos/{owner}/{repo}/actions", s.authorized(s.repoActions))
+	mux.Handle("GET /scans", s.authorized(s.listScans))
+	mux.Handle("POST /scan", s.authorized(s.startScan))
+	mux.Handle("GET /policy", s.authorized(s.getPolicy))
+	// Webhook deliveries are signed rather than carrying the token
+	mux.HandleFunc("POST /webhooks/github", s.webhook)
+
+	// The web UI asks for the token itself, when the API requires one
+	assets, _ := fs.Sub(ui, "ui")
+	mux.Handle("GET /{$}", http.RedirectHandler("/ui/", http.StatusFound))
+	mux.Handle("GET /ui/", http.StripPrefix("/ui/", uiHeaders(http.FileServerFS(assets))))
+	return mux
+}
+
+// Wait waits for running scans to finish
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// authorized requires the server's token, when it has one
+func (s *Server) authorized(handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.Token != "" {
+			presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(s.config.Token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="action-control"`)
+				writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+				return
+			}
+		}
+		handler(w, r)
+	})
+}
+
+// uiHeaders restricts the web UI to its own assets and the API
+func uiHeaders(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// listRuns lists recorded runs, newest first, optionally of one target and up to a limit
+func (s *Server) listRuns(w http.ResponseWriter, r *http.Request) {
+	filter := store.Filter{Target: r.URL.Query().Get("target"), Limit: 20}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative number")
+			return
+		}
+		filter.Limit = n
+	}
+
+	runs, err := s.config.Store.Runs(r.Context(), filter)
+	if err != nil {
+		s.storeError(w, err)
+		return
+	}
+	if runs == nil {
+		runs = []store.Run{}
+	}
+	writeJSON(w, http.StatusOK, runs)
+}
+
+// getRun returns a run with its repository results
+func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.config.Store.Run(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.storeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// OrgViolations is the response of GET /orgs/{org}/violations
+type OrgViolations struct {
+	Organization string                `json:"organization"`
+	RunID        string                `json:"run_id"`
+	ScannedAt    time.Time             `json:"scanned_at"`
+	Repositories int                   `json:"repositories"` // Evaluated repositories
+	Interrupted  string                `json:"interrupted,omitempty"`
+	Violations   []store.RepositoryRun `json:"violations"` // Repositories not complying with the policy
+}
+
+// orgViolations returns the repositories not complying with the policy in the latest run of an
+// organization
+func (s *Server) orgViolations(w http.ResponseWriter, r *http.Request) {
+	org := r.PathValue("org")
+	runs, err := s.config.Store.Runs(r.Context(), store.Filter{Target: org, Limit: 1})
+	if err != nil {
+		s.storeError(w, err)
+		return
+	}
+	if len(runs) == 0 {
+		writeError(w, http.StatusNotFound, "no recorded run of organization "+org)
+		return
+	}
+	run, err := s.config.Store.Run(r.Context(), runs[0].ID)
+	if err != nil {
+		s.storeError(w, err)
+		return
+	}
+
+	response := OrgViolations{
+		Organization: run.Target,
+		RunID:        run.ID,
+		ScannedAt:    run.StartedAt,
+		Repositories: run.Total,
+		Interrupted:  run.Interrupted,
+		Violations:   []store.RepositoryRun{},
+	}
+	for _, result := range run.Repositories {
+		if !result.Compliant {
+			response.Violations = append(response.Violations, result)
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// RepoActions is the response of GET /repos/{owner}/{repo}/actions
+type RepoActions struct {
+	store.RepositoryRun
+	RunID     string    `json:"run_id"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// repoActions returns the actions a repository uses and its compliance, from the latest run of
+// the repository or its organization that covers it
+func (s *Server) repoActions(w http.ResponseWriter, r *http.Request) {
+	owner, name := r.PathValue("owner"), r.PathValue("repo")
+	repo := owner + "/" + name
+
+	var candidates []store.Run
+	for _, target := range []string{repo, owner} {
+		runs, err := s.config.Store.Runs(r.Context(), store.Filter{Target: target, Limit: 1})
+		if err != nil {
+			s.storeError(w, err)
+			return
+		}
+		candidates = append(candidates, runs...)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].StartedAt.After(candidates[j].StartedAt)
+	})
+
+	for _, candidate := range candidates {
+		run, err := s.config.Store.Run(r.Context(), candidate.ID)
+		if err != nil {
+			s.storeError(w, err)
+			return
+		}
+		for _, result := range run.Repositories {
+			if strings.EqualFold(result.Repository, repo) {
+				if result.Actions == nil {
+					result.Actions = []string{}
+				}
+				writeJSON(w, http.StatusOK, RepoActions{RepositoryRun: result, RunID: run.ID, ScannedAt: run.StartedAt})
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "no recorded run covers repository "+repo)
+}
+
+// ScanRequest is the body of POST /scan, naming an organization or a repository
+type ScanRequest struct {
+	Organization string `json:"org,omitempty"`
+	Repository   string `json:"repo,omitempty"` // owner/repo
+}
+
+// Scan is a scan started or running on the server
+type Scan struct {
+	Target    string    `json:"target"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// startScan starts a scan in the background, unless one of the same target is running. Its
+// outcome is recorded as a run once it finishes.
+func (s *Server) startScan(w http.ResponseWriter, r *http.Request) {
+	var request ScanRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid scan request: "+err.Error())
+		return
+	}
+
+	target := request.Organization
+	switch {
+	case (request.Organization == "") == (request.Repository == ""):
+		writeError(w, http.StatusBadRequest, "name either an organization (org) or a repository (repo) to scan")
+		return
+	case request.Repository != "":
+		owner, name, ok := strings.Cut(request.Repository, "/")
+		if !ok || !validName(owner) || !validName(name) {
+			writeError(w, http.StatusBadRequest, "repo must be owner/repo")
+			return
+		}
+		target = request.Repository
+	case !validName(request.Organization):
+		writeError(w, http.StatusBadRequest, "invalid organization name")
+		return
+	}
+
+	// Scans run with the server's token, so only its own organization may be scanned
+	owner, _, _ := strings.Cut(target, "/")
+	if !strings.EqualFold(owner, s.config.Organization) {
+		writeError(w, http.StatusForbidden, "only "+s.config.Organization+" and its repositories may be scanned")
+		return
+	}
+
+	key := strings.ToLower(target)
+	s.mu.Lock()
+	if startedAt, running := s.running[key]; running {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusConflict, map[string]interface{}{"error": "a scan of " + target + " is already running", "scan": Scan{Target: target, StartedAt: startedAt}})
+		return
+	}
+	scan := Scan{Target: target, StartedAt: time.Now().UTC()}
+	s.running[key] = scan.StartedAt
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, key)
+			s.mu.Unlock()
+		}()
+		if err := s.config.Scan(s.ctx, target); err != nil {
+			log.Printf("Warning: Scan of %s failed: %v", target, err)
+			return
+		}
+		// Runs are recorded to the second
+		runs, err := s.config.Store.Runs(s.ctx, store.Filter{Target: target, Since: scan.StartedAt.Truncate(time.Second), Limit: 1})
+		if err != nil || len(runs) == 0 {
+			log.Printf("Warning: Scan of %s recorded no run", target)
+			return
+		}
+		log.Printf("Scan of %s recorded run %s", target, runs[0].ID)
+	}()
+
+	writeJSON(w, http.StatusAccepted, scan)
+}
+
+// listScans lists the running scans
+func (s *Server) listScans(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	scans := []Scan{}
+	for target, startedAt := range s.running {
+		scans = append(scans, Scan{Target: target, StartedAt: startedAt})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(scans, func(i, j int) bool {
+		return scans[i].Target < scans[j].Target
+	})
+	writeJSON(w, http.StatusOK, scans)
+}
+
+// getPolicy returns the policy files scans enforce
+func (s *Server) getPolicy(w http.ResponseWriter, r *http.Request) {
+	if s.config.Policy == nil {
+		writeError(w, http.StatusNotFound, "the server has no policy configured")
+		return
+	}
+	files, err := s.config.Policy()
+	if err != nil {
+		log.Printf("Warning: Could not read policy: %v", err)
+		writeError(w, http.StatusNotFound, "could not read the policy")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]PolicyFile{"files": files})
+}
+
+// validName reports whether name is a valid GitHub account or repository name, so that it
+// cannot be mistaken for a command-line option
+func validName(name string) bool {
+	if name == "" || name[0] == '-' || name == "." || name == ".." {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// storeError responds to a failure reading the store
+func (s *Server) storeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	log.Printf("Warning: Could not read store: %v", err)
+	writeError(w, http.StatusInternalServerError, "could not read the store")
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
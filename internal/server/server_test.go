@@ -0,0 +1,273 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/store"
+)
+
+// newTestServer returns a server over a store holding an organization run and a later run of
+// one of its repositories
+func newTestServer(t *testing.T, token string, scan Scanner) *Server {
+	t.Helper()
+	ctx := context.Background()
+	history, err := store.Open(ctx, filepath.Join(t.TempDir(), "runs.db"))
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { history.Close() })
+
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	runs := []store.Run{
+		{
+			ID: store.NewID(base), Target: "your-org", StartedAt: base, Total: 2, NonCompliant: 1,
+			Repositories: []store.RepositoryRun{
+				{Repository: "your-org/api", Findings: 1, Errors: 1, Owners: []string{"@your-org/platform"}, Violations: []string{"bad/action@v1"}, Actions: []string{"actions/checkout@v4", "bad/action@v1"}},
+				{Repository: "your-org/web", Compliant: true, Actions: []string{"actions/checkout@v4"}},
+			},
+		},
+		{
+			ID: store.NewID(base.Add(time.Hour)), Target: "your-org/api", StartedAt: base.Add(time.Hour), Total: 1,
+			Repositories: []store.RepositoryRun{
+				{Repository: "your-org/api", Compliant: true, Actions: []string{"actions/checkout@v4"}},
+			},
+		},
+	}
+	for _, run := range runs {
+		if err := history.Save(ctx, run); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+	return New(ctx, Config{Store: history, Token: token, Scan: scan, Organization: "your-org"})
+}
+
+func request(t *testing.T, handler http.Handler, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestOrgViolations(t *testing.T) {
+	server := newTestServer(t, "", nil)
+	response := request(t, server.Handler(), "GET", "/orgs/your-org/violations", "")
+	if response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", response.Code, response.Body)
+	}
+
+	var violations OrgViolations
+	if err := json.Unmarshal(response.Body.Bytes(), &violations); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if violations.Repositories != 2 || len(violations.Violations) != 1 || violations.Violations[0].Repository != "your-org/api" {
+		t.Errorf("Expected the non-compliant repository of the organization run, got %+v", violations)
+	}
+	if got := violations.Violations[0].Owners; len(got) != 1 || got[0] != "@your-org/platform" {
+		t.Errorf("Expected the repository's owners, got %v", got)
+	}
+
+	if response := request(t, server.Handler(), "GET", "/orgs/other-org/violations", ""); response.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an organization without runs, got %d", response.Code)
+	}
+}
+
+func TestRepoActions(t *testing.T) {
+	server := newTestServer(t, "", nil)
+
+	// The repository run is newer than the organization run
+	response := request(t, server.Handler(), "GET", "/repos/your-org/api/actions", "")
+	var actions RepoActions
+	if err := json.Unmarshal(response.Body.Bytes(), &actions); err != nil || response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", response.Code, response.Body)
+	}
+	if !actions.Compliant || len(actions.Actions) != 1 || actions.ScannedAt.Hour() != 10 {
+		t.Errorf("Expected the latest repository run, got %+v", actions)
+	}
+
+	// Only the organization run covers the other repository
+	response = request(t, server.Handler(), "GET", "/repos/YOUR-ORG/web/actions", "")
+	if err := json.Unmarshal(response.Body.Bytes(), &actions); err != nil || response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", response.Code, response.Body)
+	}
+	if actions.Repository != "your-org/web" || actions.ScannedAt.Hour() != 9 {
+		t.Errorf("Expected the organization run, got %+v", actions)
+	}
+
+	if response := request(t, server.Handler(), "GET", "/repos/your-org/missing/actions", ""); response.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unscanned repository, got %d", response.Code)
+	}
+}
+
+func TestRuns(t *testing.T) {
+	server := newTestServer(t, "", nil)
+	response := request(t, server.Handler(), "GET", "/runs?target=your-org&limit=5", "")
+	var runs []store.Run
+	if err := json.Unmarshal(response.Body.Bytes(), &runs); err != nil || response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", response.Code, response.Body)
+	}
+	if len(runs) != 1 || runs[0].Target != "your-org" {
+		t.Fatalf("Expected the organization run, got %+v", runs)
+	}
+
+	response = request(t, server.Handler(), "GET", "/runs/"+runs[0].ID, "")
+	var run store.Run
+	if err := json.Unmarshal(response.Body.Bytes(), &run); err != nil || response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", response.Code, response.Body)
+	}
+	if len(run.Repositories) != 2 {
+		t.Errorf("Expected the run's results, got %+v", run)
+	}
+
+	if response := request(t, server.Handler(), "GET", "/runs/missing", ""); response.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown run, got %d", response.Code)
+	}
+	if response := request(t, server.Handler(), "GET", "/runs?limit=many", ""); response.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid limit, got %d", response.Code)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	server := newTestServer(t, "s3cret", nil)
+	handler := server.Handler()
+
+	if response := request(t, handler, "GET", "/orgs/your-org/violations", ""); response.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a token, got %d", response.Code)
+	}
+	if response := request(t, handler, "GET", "/orgs/your-org/violations", "", "Authorization", "Bearer wrong"); response.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 with a wrong token, got %d", response.Code)
+	}
+	if response := request(t, handler, "GET", "/orgs/your-org/violations", "", "Authorization", "Bearer s3cret"); response.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with the token, got %d", response.Code)
+	}
+	if response := request(t, handler, "GET", "/healthz", ""); response.Code != http.StatusOK {
+		t.Errorf("Expected health checks without a token, got %d", response.Code)
+	}
+}
+
+func TestScan(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var scanned []string
+	server := newTestServer(t, "", func(ctx context.Context, target string) error {
+		<-release
+		mu.Lock()
+		scanned = append(scanned, target)
+		mu.Unlock()
+		return nil
+	})
+	handler := server.Handler()
+
+	response := request(t, handler, "POST", "/scan", `{"org": "your-org"}`)
+	if response.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", response.Code, response.Body)
+	}
+	if response := request(t, handler, "POST", "/scan", `{"org": "YOUR-ORG"}`); response.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 while the scan runs, got %d", response.Code)
+	}
+	if response := request(t, handler, "POST", "/scan", `{"repo": "your-org/api"}`); response.Code != http.StatusAccepted {
+		t.Errorf("Expected a scan of another target to start, got %d", response.Code)
+	}
+
+	var running []Scan
+	json.Unmarshal(request(t, handler, "GET", "/scans", "").Body.Bytes(), &running)
+	if len(running) != 2 {
+		t.Errorf("Expected 2 running scans, got %+v", running)
+	}
+
+	close(release)
+	server.Wait()
+	if len(scanned) != 2 {
+		t.Errorf("Expected both targets scanned, got %v", scanned)
+	}
+	if response := request(t, handler, "POST", "/scan", `{"org": "your-org"}`); response.Code != http.StatusAccepted {
+		t.Errorf("Expected a new scan once the previous one finished, got %d", response.Code)
+	}
+	server.Wait()
+}
+
+func TestScanInvalid(t *testing.T) {
+	server := newTestServer(t, "", func(ctx context.Context, target string) error {
+		t.Errorf("Unexpected scan of %s", target)
+		return nil
+	})
+	for _, body := range []string{
+		`{}`,
+		`{"org": "your-org", "repo": "your-org/api"}`,
+		`{"repo": "your-org"}`,
+		`{"org": "--config=/etc/passwd"}`,
+		`{"repo": "your-org/../api"}`,
+		`not json`,
+	} {
+		if response := request(t, server.Handler(), "POST", "/scan", body); response.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, response.Code)
+		}
+	}
+}
+
+func TestScanOtherOrganization(t *testing.T) {
+	server := newTestServer(t, "", func(ctx context.Context, target string) error {
+		t.Errorf("Unexpected scan of %s", target)
+		return nil
+	})
+	for _, body := range []string{`{"org": "other-org"}`, `{"repo": "other-org/api"}`} {
+		if response := request(t, server.Handler(), "POST", "/scan", body); response.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for %s, got %d", body, response.Code)
+		}
+	}
+}
+
+func TestUI(t *testing.T) {
+	handler := newTestServer(t, "s3cret", nil).Handler()
+
+	if response := request(t, handler, "GET", "/", ""); response.Code != http.StatusFound || response.Header().Get("Location") != "/ui/" {
+		t.Errorf("Expected a redirect to the web UI, got %d to %q", response.Code, response.Header().Get("Location"))
+	}
+
+	// The web UI loads without the token, which its API requests then present
+	response := request(t, handler, "GET", "/ui/", "")
+	if response.Code != http.StatusOK || !strings.Contains(response.Body.String(), `<script src="app.js"`) {
+		t.Fatalf("Expected the web UI page, got %d: %s", response.Code, response.Body)
+	}
+	if csp := response.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "default-src 'self'") {
+		t.Errorf("Expected the web UI restricted to its own assets, got %q", csp)
+	}
+	for _, asset := range []string{"/ui/app.js", "/ui/style.css"} {
+		if response := request(t, handler, "GET", asset, ""); response.Code != http.StatusOK {
+			t.Errorf("Expected %s served, got %d", asset, response.Code)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	server := newTestServer(t, "s3cret", nil)
+	if response := request(t, server.Handler(), "GET", "/policy", "", "Authorization", "Bearer s3cret"); response.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without a policy, got %d", response.Code)
+	}
+
+	server.config.Policy = func() ([]PolicyFile, error) {
+		return []PolicyFile{{Path: "policy.yaml", Content: "allowed_actions: [actions/checkout]\n"}}, nil
+	}
+	if response := request(t, server.Handler(), "GET", "/policy", ""); response.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the policy to require the token, got %d", response.Code)
+	}
+	response := request(t, server.Handler(), "GET", "/policy", "", "Authorization", "Bearer s3cret")
+	var policy struct{ Files []PolicyFile }
+	if err := json.Unmarshal(response.Body.Bytes(), &policy); err != nil || response.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", response.Code, response.Body)
+	}
+	if len(policy.Files) != 1 || policy.Files[0].Path != "policy.yaml" || !strings.Contains(policy.Files[0].Content, "actions/checkout") {
+		t.Errorf("Expected the policy file, got %+v", policy.Files)
+	}
+}
@@ -0,0 +1,190 @@
+"use strict";
+
+// The API token, kept for the browser session when the API requires one
+let token = sessionStorage.getItem("action-control-token") || "";
+// Repository results of the latest run of the selected target
+let results = [];
+
+const $ = (id) => document.getElementById(id);
+
+class Unauthorized extends Error {}
+
+async function api(path) {
+  const headers = token ? { Authorization: "Bearer " + token } : {};
+  const response = await fetch(path, { headers });
+  if (response.status === 401) {
+    throw new Unauthorized();
+  }
+  const body = await response.json();
+  if (!response.ok) {
+    throw new Error(body.error || response.statusText);
+  }
+  return body;
+}
+
+function showError(err) {
+  if (err instanceof Unauthorized) {
+    $("login").hidden = false;
+    return;
+  }
+  $("error").textContent = err.message;
+  $("error").hidden = false;
+}
+
+function element(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined) {
+    node.textContent = text;
+  }
+  if (className) {
+    node.className = className;
+  }
+  return node;
+}
+
+// loadTargets lists the targets of recorded runs, each with its latest run
+async function loadTargets() {
+  const runs = await api("/runs?limit=0");
+  const latest = new Map();
+  for (const run of runs) {
+    if (!latest.has(run.target)) {
+      latest.set(run.target, run.id);
+    }
+  }
+
+  const select = $("target");
+  select.replaceChildren();
+  for (const [target, id] of [...latest].sort()) {
+    const option = element("option", target);
+    option.value = id;
+    select.append(option);
+  }
+  const wanted = decodeURIComponent(location.hash.slice(1));
+  for (const option of select.options) {
+    option.selected = option.textContent === wanted;
+  }
+  if (latest.size === 0) {
+    $("run").textContent = "No runs recorded yet";
+    return;
+  }
+  await loadRun(select.value);
+}
+
+async function loadRun(id) {
+  const run = await api("/runs/" + encodeURIComponent(id));
+  results = run.results || [];
+  location.hash = encodeURIComponent(run.target);
+  $("run").textContent = "Run " + run.id + " at " + new Date(run.started_at).toLocaleString() +
+    (run.interrupted ? " (interrupted: " + run.interrupted + ")" : "");
+  $("total").textContent = run.repositories;
+  $("non-compliant").textContent = run.non_compliant;
+
+  const teams = new Set(results.flatMap((result) => result.owners || []));
+  const team = $("filter-team");
+  const selected = team.value;
+  team.replaceChildren(element("option", "Any"));
+  team.options[0].value = "";
+  for (const owner of [...teams].sort()) {
+    const option = element("option", owner);
+    option.value = owner;
+    option.selected = owner === selected;
+    team.append(option);
+  }
+  render();
+}
+
+// matches applies the filters to a repository result
+function matches(result) {
+  const action = $("filter-action").value.trim().toLowerCase();
+  if (action && !(result.actions || result.violations || []).some((uses) => uses.toLowerCase().includes(action))) {
+    return false;
+  }
+  const team = $("filter-team").value;
+  if (team && !(result.owners || []).includes(team)) {
+    return false;
+  }
+  switch ($("filter-severity").value) {
+    case "compliant":
+      return result.compliant;
+    case "errors":
+      return result.errors > 0;
+    case "warnings":
+      return result.warnings > 0;
+    case "info":
+      return result.info > 0;
+  }
+  return true;
+}
+
+function render() {
+  const rows = results
+    .filter(matches)
+    .sort((a, b) => a.compliant - b.compliant || b.errors - a.errors || a.repository.localeCompare(b.repository))
+    .map((result) => {
+      const row = document.createElement("tr");
+      row.append(
+        element("td", result.repository),
+        element("td", result.compliant ? "✅ Compliant" : "❌ Not compliant", result.compliant ? "pass" : "fail"),
+        element("td", result.errors, "number"),
+        element("td", result.warnings, "number"),
+        element("td", result.info, "number"),
+        element("td", (result.owners || []).join(", ")),
+      );
+      const violations = element("td");
+      for (const uses of result.violations || []) {
+        violations.append(element("code", uses));
+      }
+      row.append(violations);
+      return row;
+    });
+  $("results").replaceChildren(...rows);
+  $("empty").hidden = rows.length > 0;
+}
+
+async function loadPolicy() {
+  const section = $("policy");
+  try {
+    const policy = await api("/policy");
+    section.replaceChildren();
+    for (const file of policy.files) {
+      section.append(element("h2", file.path), element("pre", file.content));
+    }
+  } catch (err) {
+    if (err instanceof Unauthorized) {
+      throw err;
+    }
+    section.replaceChildren(element("p", err.message, "muted"));
+  }
+}
+
+async function start() {
+  $("error").hidden = true;
+  try {
+    await loadTargets();
+    await loadPolicy();
+    $("login").hidden = true;
+  } catch (err) {
+    showError(err);
+  }
+}
+
+$("login").addEventListener("submit", (event) => {
+  event.preventDefault();
+  token = $("token").value;
+  sessionStorage.setItem("action-control-token", token);
+  start();
+});
+$("target").addEventListener("change", () => loadRun($("target").value).catch(showError));
+for (const id of ["filter-action", "filter-severity", "filter-team"]) {
+  $(id).addEventListener("input", render);
+}
+for (const button of document.querySelectorAll("nav button")) {
+  button.addEventListener("click", () => {
+    for (const other of document.querySelectorAll("nav button")) {
+      other.classList.toggle("active", other === button);
+      $(other.dataset.tab).hidden = other !== button;
+    }
+  });
+}
+
+start();
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// maxWebhookPayload is the size of the largest payload GitHub delivers
+const maxWebhookPayload = 25 << 20
+
+// gatedActions are the pull request event actions that change what a pull request merges
+var gatedActions = []string{"opened", "reopened", "synchronize"}
+
+// pullRequestEvent holds the fields of a pull_request webhook payload the gate needs
+type pullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// validSignature reports whether signature, the X-Hub-Signature-256 header of a delivery, is
+// the HMAC of payload with secret
+func validSignature(payload []byte, signature, secret string) bool {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// webhook evaluates the pull requests GitHub webhook deliveries announce as they are opened
+// or pushed to, in the background, so that their status check can block merging workflow
+// changes that violate the policy
+func (s *Server) webhook(w http.ResponseWriter, r *http.Request) {
+	if s.config.WebhookSecret == "" || s.config.Gate == nil {
+		writeError(w, http.StatusNotFound, "webhooks are not configured")
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayload))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "could not read payload: "+err.Error())
+		return
+	}
+	if !validSignature(payload, r.Header.Get("X-Hub-Signature-256"), s.config.WebhookSecret) {
+		writeError(w, http.StatusUnauthorized, "invalid webhook signature")
+		return
+	}
+
+	switch event := r.Header.Get("X-GitHub-Event"); event {
+	case "ping":
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		return
+	case "pull_request":
+	default:
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored " + event + " event"})
+		return
+	}
+
+	var pull pullRequestEvent
+	if err := json.Unmarshal(payload, &pull); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid pull_request payload: "+err.Error())
+		return
+	}
+	if !slices.Contains(gatedActions, pull.Action) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored " + pull.Action + " action"})
+		return
+	}
+	repo := pull.Repository.FullName
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || !validName(owner) || !validName(name) || pull.Number <= 0 {
+		writeError(w, http.StatusBadRequest, "pull_request payload lacks the repository or number")
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.config.Gate(s.ctx, repo, pull.Number, pull.PullRequest.Head.SHA); err != nil {
+			log.Printf("Warning: Evaluation of pull request #%d in %s failed: %v", pull.Number, repo, err)
+			return
+		}
+		log.Printf("Evaluated pull request #%d in %s", pull.Number, repo)
+	}()
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{"repository": repo, "pull_request": pull.Number})
+}
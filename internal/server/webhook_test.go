@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func sign(payload, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhook(t *testing.T) {
+	var mu sync.Mutex
+	var gated []string
+	server := newTestServer(t, "s3cret", nil)
+	server.config.WebhookSecret = "hook"
+	server.config.Gate = func(ctx context.Context, repo string, number int, headSHA string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		gated = append(gated, repo)
+		if number != 42 || headSHA != "abc123" {
+			t.Errorf("Expected pull request 42 at abc123, got %d at %q", number, headSHA)
+		}
+		return nil
+	}
+	handler := server.Handler()
+
+	opened := `{"action": "opened", "number": 42, "pull_request": {"head": {"sha": "abc123"}}, "repository": {"full_name": "your-org/api"}}`
+	response := request(t, handler, "POST", "/webhooks/github", opened, "X-GitHub-Event", "pull_request", "X-Hub-Signature-256", sign(opened, "hook"))
+	if response.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", response.Code, response.Body)
+	}
+
+	// Deliveries are signed rather than carrying the API token
+	if response := request(t, handler, "POST", "/webhooks/github", opened, "X-GitHub-Event", "pull_request", "X-Hub-Signature-256", sign(opened, "other")); response.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for an invalid signature, got %d", response.Code)
+	}
+	if response := request(t, handler, "POST", "/webhooks/github", opened, "X-GitHub-Event", "pull_request", "Authorization", "Bearer s3cret"); response.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a signature, got %d", response.Code)
+	}
+
+	closed := `{"action": "closed", "number": 42, "repository": {"full_name": "your-org/api"}}`
+	if response := request(t, handler, "POST", "/webhooks/github", closed, "X-GitHub-Event", "pull_request", "X-Hub-Signature-256", sign(closed, "hook")); response.Code != http.StatusOK {
+		t.Errorf("Expected closed pull requests ignored, got %d", response.Code)
+	}
+	ping := `{"zen": "Keep it logically awesome."}`
+	if response := request(t, handler, "POST", "/webhooks/github", ping, "X-GitHub-Event", "ping", "X-Hub-Signature-256", sign(ping, "hook")); response.Code != http.StatusOK {
+		t.Errorf("Expected pings answered, got %d", response.Code)
+	}
+	invalid := `{"action": "opened", "number": 42, "repository": {"full_name": "--config=x"}}`
+	if response := request(t, handler, "POST", "/webhooks/github", invalid, "X-GitHub-Event", "pull_request", "X-Hub-Signature-256", sign(invalid, "hook")); response.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid repository, got %d", response.Code)
+	}
+
+	server.Wait()
+	if len(gated) != 1 || gated[0] != "your-org/api" {
+		t.Errorf("Expected the opened pull request evaluated once, got %v", gated)
+	}
+}
+
+func TestWebhookNotConfigured(t *testing.T) {
+	server := newTestServer(t, "", nil)
+	payload := `{"action": "opened", "number": 42, "repository": {"full_name": "your-org/api"}}`
+	if response := request(t, server.Handler(), "POST", "/webhooks/github", payload, "X-GitHub-Event", "pull_request", "X-Hub-Signature-256", sign(payload, "")); response.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without a webhook secret, got %d", response.Code)
+	}
+}
@@ -0,0 +1,190 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/codeowners"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/updates"
+)
+
+// SchemaVersion is the version of the scan file format
+const SchemaVersion = "1"
+
+// Snapshot is the result of scanning an organization or repository: the workflow files of each
+// repository and the other files enforce evaluates, so a policy can be evaluated without API
+// access, such as on an air-gapped host
+type Snapshot struct {
+	SchemaVersion string                 `json:"schema_version"`
+	ScannedAt     time.Time              `json:"scanned_at"`
+	Organization  string                 `json:"organization,omitempty"`
+	Repository    string                 `json:"repository,omitempty"`      // Set for single repository scans
+	OrgPolicy     string                 `json:"org_policy,omitempty"`      // Central policy of the organization's .github repository
+	OrgPolicyPath string                 `json:"org_policy_path,omitempty"` // Path of the central policy within the .github repository
+	Repositories  map[string]*Repository `json:"repositories"`
+}
+
+// Repository is a scanned repository
+type Repository struct {
+	Visibility    string            `json:"visibility,omitempty"`
+	DefaultBranch string            `json:"default_branch,omitempty"`
+	Workflows     []Workflow        `json:"workflows"`
+	Files         map[string]string `json:"files,omitempty"` // Repository policy, CODEOWNERS and update configuration by path
+}
+
+// Workflow is a scanned workflow file, kept as text so scan files can be reviewed before they
+// are carried to another host
+type Workflow struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	SHA     string `json:"sha,omitempty"`
+	Ref     string `json:"ref,omitempty"` // Branch the file was read from; empty for the default branch
+	Content string `json:"content"`
+}
+
+// AddWorkflowFiles records the workflow files of the repository
+func (r *Repository) AddWorkflowFiles(files []github.WorkflowFile) {
+	for _, file := range files {
+		r.Workflows = append(r.Workflows, Workflow{
+			Name:    file.Name,
+			Path:    file.Path,
+			SHA:     file.SHA,
+			Ref:     file.Ref,
+			Content: string(file.Content),
+		})
+	}
+}
+
+// WorkflowFiles returns the workflow files of the repository as read from the API
+func (r *Repository) WorkflowFiles() []github.WorkflowFile {
+	files := make([]github.WorkflowFile, 0, len(r.Workflows))
+	for _, workflow := range r.Workflows {
+		files = append(files, github.WorkflowFile{
+			Name:    workflow.Name,
+			Path:    workflow.Path,
+			SHA:     workflow.SHA,
+			Ref:     workflow.Ref,
+			Content: []byte(workflow.Content),
+		})
+	}
+	return files
+}
+
+// AuxiliaryPaths returns the paths of the files besides workflows that enforce reads from
+// repositories: the repository policy, CODEOWNERS and Dependabot or Renovate configuration
+func AuxiliaryPaths() []string {
+	paths := []string{policy.RepoPolicyPath}
+	paths = append(paths, codeowners.Locations...)
+	return append(paths, updates.ConfigPaths()...)
+}
+
+// New starts a snapshot of an organization, or of a single owner/repo repository
+func New(org, repo string, scannedAt time.Time) *Snapshot {
+	return &Snapshot{
+		SchemaVersion: SchemaVersion,
+		ScannedAt:     scannedAt.UTC(),
+		Organization:  org,
+		Repository:    repo,
+		Repositories:  make(map[string]*Repository),
+	}
+}
+
+// Load reads a scan file
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan file: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse scan file %s: %w", path, err)
+	}
+	if snapshot.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported scan file version %q in %s, expected %q", snapshot.SchemaVersion, path, SchemaVersion)
+	}
+	for name := range snapshot.Repositories {
+		if owner, repo, ok := strings.Cut(name, "/"); !ok || owner == "" || repo == "" {
+			return nil, fmt.Errorf("invalid repository %q in scan file %s, must be owner/repo", name, path)
+		}
+	}
+	if snapshot.Repositories == nil {
+		snapshot.Repositories = make(map[string]*Repository)
+	}
+	return &snapshot, nil
+}
+
+// Write encodes the snapshot as indented JSON
+func (s *Snapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(s); err != nil {
+		return fmt.Errorf("failed to write scan file: %w", err)
+	}
+	return nil
+}
+
+// Names returns the scanned repositories in order
+func (s *Snapshot) Names() []string {
+	names := make([]string, 0, len(s.Repositories))
+	for name := range s.Repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Fixture converts the snapshot into a fixture, so the requests of an evaluation, such as for a
+// repository's policy file or visibility, are answered from the snapshot. Workflow files on
+// other branches are left out, as fixtures only hold the default branch.
+func (s *Snapshot) Fixture() *github.Fixture {
+	fixture := &github.Fixture{Organization: s.Organization}
+
+	orgPolicyRepo, orgPolicyPath := "", s.OrgPolicyPath
+	if s.Organization != "" && s.OrgPolicy != "" {
+		orgPolicyRepo = s.Organization + "/" + policy.OrgPolicyRepo
+	}
+	if orgPolicyPath == "" {
+		orgPolicyPath = policy.OrgPolicyPath
+	}
+
+	for _, name := range s.Names() {
+		repo := s.Repositories[name]
+		files := make(map[string]string, len(repo.Files)+len(repo.Workflows))
+		for filePath, content := range repo.Files {
+			files[filePath] = content
+		}
+		for _, workflow := range repo.Workflows {
+			if workflow.Ref == "" {
+				files[workflow.Path] = workflow.Content
+			}
+		}
+		if strings.EqualFold(name, orgPolicyRepo) {
+			files[orgPolicyPath] = s.OrgPolicy
+			orgPolicyRepo = ""
+		}
+		fixture.Repositories = append(fixture.Repositories, github.FixtureRepository{
+			FullName:      name,
+			Visibility:    repo.Visibility,
+			DefaultBranch: repo.DefaultBranch,
+			Files:         files,
+		})
+	}
+
+	// The .github repository holding the central policy need not have workflows
+	if orgPolicyRepo != "" {
+		fixture.Repositories = append(fixture.Repositories, github.FixtureRepository{
+			FullName: orgPolicyRepo,
+			Files:    map[string]string{orgPolicyPath: s.OrgPolicy},
+		})
+	}
+
+	return fixture
+}
@@ -0,0 +1,86 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	snapshot := New("org", "", time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	repo := &Repository{Visibility: "private", DefaultBranch: "main", Files: map[string]string{policy.RepoPolicyPath: "policy_mode: deny\n"}}
+	repo.AddWorkflowFiles([]github.WorkflowFile{{Name: "ci.yml", Path: ".github/workflows/ci.yml", SHA: "abc", Content: []byte("on: push\n")}})
+	snapshot.Repositories["org/app"] = repo
+
+	path := filepath.Join(t.TempDir(), "scan.json")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := snapshot.Write(file); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	file.Close()
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if loaded.Organization != "org" || !loaded.ScannedAt.Equal(snapshot.ScannedAt) {
+		t.Errorf("Expected organization and scan time to round-trip, got %+v", loaded)
+	}
+	files := loaded.Repositories["org/app"].WorkflowFiles()
+	if len(files) != 1 || string(files[0].Content) != "on: push\n" || files[0].SHA != "abc" {
+		t.Errorf("Expected workflow file to round-trip, got %+v", files)
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]string{
+		"version":    `{"schema_version": "2", "repositories": {}}`,
+		"repository": `{"schema_version": "1", "repositories": {"app": {}}}`,
+		"json":       `{`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scan.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil {
+				t.Error("Expected Load to fail")
+			}
+		})
+	}
+}
+
+func TestFixture(t *testing.T) {
+	snapshot := New("org", "", time.Now())
+	snapshot.OrgPolicy = "policy_mode: allow\n"
+	repo := &Repository{Visibility: "public", DefaultBranch: "main"}
+	repo.AddWorkflowFiles([]github.WorkflowFile{
+		{Name: "ci.yml", Path: ".github/workflows/ci.yml", Content: []byte("on: push\n")},
+		{Name: "ci.yml", Path: ".github/workflows/ci.yml", Ref: "release", Content: []byte("on: release\n")},
+	})
+	snapshot.Repositories["org/app"] = repo
+
+	client := github.NewFixtureClient(snapshot.Fixture())
+	ctx := context.Background()
+
+	// The central policy is served from the organization's .github repository
+	content, err := client.GetRepositoryContent(ctx, "org", policy.OrgPolicyRepo, policy.OrgPolicyPath)
+	if err != nil || string(content) != snapshot.OrgPolicy {
+		t.Errorf("Expected central policy %q, got %q (%v)", snapshot.OrgPolicy, content, err)
+	}
+
+	// Workflow files of the default branch are served
+	content, err = client.GetRepositoryContent(ctx, "org", "app", ".github/workflows/ci.yml")
+	if err != nil || string(content) != "on: push\n" {
+		t.Errorf("Expected default branch workflow, got %q (%v)", content, err)
+	}
+}
@@ -0,0 +1,139 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/pinning"
+	"github.com/ihavespoons/action-control/internal/policy"
+)
+
+// TopOwnersLimit is the number of action owners listed in the statistics
+const TopOwnersLimit = 10
+
+// OwnOrganization replaces the scanned organization among the top owners, so its name isn't
+// shared with the statistics
+const OwnOrganization = "(own organization)"
+
+// OwnerUsage counts the use of one owner's actions
+type OwnerUsage struct {
+	Owner        string `json:"owner"`
+	References   int    `json:"references"`
+	Repositories int    `json:"repositories"`
+}
+
+// Stats are anonymous aggregate metrics of an organization's action usage, without
+// repository names, for comparing posture with other organizations
+type Stats struct {
+	Repositories              int            `json:"repositories"`
+	RepositoriesWithWorkflows int            `json:"repositories_with_workflows"`
+	WorkflowsPercent          float64        `json:"repositories_with_workflows_percent"`
+	Workflows                 int            `json:"workflows"`
+	ActionReferences          int            `json:"action_references"`
+	UniqueActions             int            `json:"unique_actions"`
+	TopOwners                 []OwnerUsage   `json:"top_owners"`
+	PinnedPercent             float64        `json:"pinned_percent"`
+	Pinning                   pinning.Counts `json:"pinning"`
+}
+
+// Compute aggregates the workflow files of the scanned repositories. total is the number of
+// repositories in the organization, including those without workflow files; owners equal to
+// org are reported as OwnOrganization.
+func Compute(filesByRepo map[string][]github.WorkflowFile, total int, org string) *Stats {
+	stats := &Stats{Repositories: total, TopOwners: []OwnerUsage{}}
+	if stats.Repositories < len(filesByRepo) {
+		stats.Repositories = len(filesByRepo)
+	}
+
+	uniqueActions := make(map[string]bool)
+	owners := make(map[string]*OwnerUsage)
+	ownerRepos := make(map[string]map[string]bool)
+
+	for repo, files := range filesByRepo {
+		// Composite action files from scan paths only contribute their action references
+		workflows := 0
+		for _, file := range files {
+			if !file.IsAction() {
+				workflows++
+			}
+		}
+		if workflows > 0 {
+			stats.RepositoriesWithWorkflows++
+			stats.Workflows += workflows
+		}
+
+		for _, action := range github.ExtractActions(files) {
+			// Local actions are versioned with the repository and leak its layout
+			if strings.HasPrefix(action.Uses, "./") {
+				continue
+			}
+			stats.ActionReferences++
+
+			name, _, _ := strings.Cut(action.Uses, "@")
+			uniqueActions[strings.ToLower(name)] = true
+
+			if kind, ok := pinning.Classify(action.Uses); ok {
+				switch kind {
+				case pinning.SHA:
+					stats.Pinning.SHA++
+				case pinning.Tag:
+					stats.Pinning.Tag++
+				case pinning.Branch:
+					stats.Pinning.Branch++
+				case pinning.Unpinned:
+					stats.Pinning.Unpinned++
+				}
+			}
+
+			owner := strings.ToLower(policy.ActionOwner(action.Uses))
+			if owner == "" {
+				continue
+			}
+			if org != "" && owner == strings.ToLower(org) {
+				owner = OwnOrganization
+			}
+			if owners[owner] == nil {
+				owners[owner] = &OwnerUsage{Owner: owner}
+				ownerRepos[owner] = make(map[string]bool)
+			}
+			owners[owner].References++
+			ownerRepos[owner][repo] = true
+		}
+	}
+
+	stats.UniqueActions = len(uniqueActions)
+	stats.WorkflowsPercent = percent(stats.RepositoriesWithWorkflows, stats.Repositories)
+	stats.PinnedPercent = round(stats.Pinning.Percent(pinning.SHA))
+
+	for owner, usage := range owners {
+		usage.Repositories = len(ownerRepos[owner])
+		stats.TopOwners = append(stats.TopOwners, *usage)
+	}
+	sort.Slice(stats.TopOwners, func(i, j int) bool {
+		a, b := stats.TopOwners[i], stats.TopOwners[j]
+		if a.References != b.References {
+			return a.References > b.References
+		}
+		return a.Owner < b.Owner
+	})
+	if len(stats.TopOwners) > TopOwnersLimit {
+		stats.TopOwners = stats.TopOwners[:TopOwnersLimit]
+	}
+
+	return stats
+}
+
+// percent returns n as a share of total, from 0 to 100, to one decimal place
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round(float64(n) * 100 / float64(total))
+}
+
+// round rounds a percentage to one decimal place
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+func workflow(uses ...string) github.WorkflowFile {
+	var sb strings.Builder
+	sb.WriteString("on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n")
+	for _, u := range uses {
+		sb.WriteString(fmt.Sprintf("      - uses: %s\n", u))
+	}
+	return github.WorkflowFile{Name: "ci.yml", Path: ".github/workflows/ci.yml", Content: []byte(sb.String())}
+}
+
+func TestCompute(t *testing.T) {
+	filesByRepo := map[string][]github.WorkflowFile{
+		"acme/api": {workflow(
+			"actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+			"actions/setup-go@v5",
+			"acme/deploy-action@main",
+			"./.github/actions/local",
+		)},
+		"acme/web": {workflow(
+			"actions/checkout@v4",
+			"docker/login-action@v3",
+		)},
+		"acme/docs": {},
+	}
+
+	stats := Compute(filesByRepo, 5, "acme")
+
+	if stats.Repositories != 5 || stats.RepositoriesWithWorkflows != 2 || stats.WorkflowsPercent != 40 {
+		t.Errorf("Unexpected repository counts %+v", stats)
+	}
+	if stats.ActionReferences != 5 {
+		t.Errorf("Expected 5 action references without local actions, got %d", stats.ActionReferences)
+	}
+	if stats.UniqueActions != 4 {
+		t.Errorf("Expected 4 unique actions, got %d", stats.UniqueActions)
+	}
+	if stats.Pinning.SHA != 1 || stats.Pinning.Tag != 3 || stats.Pinning.Branch != 1 || stats.PinnedPercent != 20 {
+		t.Errorf("Unexpected pinning %+v (%.1f%%)", stats.Pinning, stats.PinnedPercent)
+	}
+
+	if len(stats.TopOwners) != 3 {
+		t.Fatalf("Expected 3 owners, got %v", stats.TopOwners)
+	}
+	if top := stats.TopOwners[0]; top.Owner != "actions" || top.References != 3 || top.Repositories != 2 {
+		t.Errorf("Expected actions as the top owner, got %+v", top)
+	}
+
+	// The statistics must not name the organization or its repositories
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatalf("Failed to marshal statistics: %v", err)
+	}
+	for _, leak := range []string{"acme", "api", "web", "docs", "local"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("Statistics leak %q: %s", leak, data)
+		}
+	}
+	if !strings.Contains(string(data), OwnOrganization) {
+		t.Errorf("Expected the organization's own actions as %q, got %s", OwnOrganization, data)
+	}
+}
+
+func TestComputeTopOwnersLimit(t *testing.T) {
+	var uses []string
+	for i := 0; i < TopOwnersLimit+5; i++ {
+		uses = append(uses, fmt.Sprintf("owner%02d/action@v1", i))
+	}
+
+	stats := Compute(map[string][]github.WorkflowFile{"org/repo": {workflow(uses...)}}, 0, "org")
+	if len(stats.TopOwners) != TopOwnersLimit {
+		t.Errorf("Expected %d top owners, got %d", TopOwnersLimit, len(stats.TopOwners))
+	}
+	if stats.Repositories != 1 || stats.WorkflowsPercent != 100 {
+		t.Errorf("Expected the scanned repositories to count when the total is unknown, got %+v", stats)
+	}
+}
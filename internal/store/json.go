@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// blobs reads and writes documents by key, in a directory or an object storage bucket
+type blobs interface {
+	put(ctx context.Context, key string, data []byte) error
+	get(ctx context.Context, key string) ([]byte, error) // Returns an error wrapping fs.ErrNotExist for missing keys
+	list(ctx context.Context, prefix string) ([]string, error)
+}
+
+// jsonStore keeps each run as a JSON document named by its ID, under runs/ below the prefix.
+// Run IDs sort by start time, so listing needs no index.
+type jsonStore struct {
+	blobs  blobs
+	prefix string
+}
+
+// key returns the key of a run's document
+func (s *jsonStore) key(id string) string {
+	return path.Join(s.prefix, "runs", id+".json")
+}
+
+// Save implements Store
+func (s *jsonStore) Save(ctx context.Context, run Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run: %w", err)
+	}
+	if err := s.blobs.put(ctx, s.key(run.ID), data); err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	return nil
+}
+
+// Runs implements Store
+func (s *jsonStore) Runs(ctx context.Context, filter Filter) ([]Run, error) {
+	keys, err := s.blobs.list(ctx, path.Join(s.prefix, "runs")+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+
+	var runs []Run
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".json") {
+			continue
+		}
+		run, err := s.Run(ctx, strings.TrimSuffix(path.Base(key), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		if !filter.matches(run) {
+			continue
+		}
+		run.Repositories = nil
+		runs = append(runs, run)
+		if filter.Limit > 0 && len(runs) == filter.Limit {
+			break
+		}
+	}
+	return runs, nil
+}
+
+// Run implements Store
+func (s *jsonStore) Run(ctx context.Context, id string) (Run, error) {
+	if strings.ContainsAny(id, `/\`) || id == "" {
+		return Run{}, ErrNotFound
+	}
+	data, err := s.blobs.get(ctx, s.key(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Run{}, ErrNotFound
+	} else if err != nil {
+		return Run{}, fmt.Errorf("failed to read run %s: %w", id, err)
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return Run{}, fmt.Errorf("failed to parse run %s: %w", id, err)
+	}
+	return run, nil
+}
+
+// Close implements Store
+func (s *jsonStore) Close() error {
+	return nil
+}
+
+// dirBlobs keeps documents as files below a directory
+type dirBlobs string
+
+func (d dirBlobs) put(_ context.Context, key string, data []byte) error {
+	file := filepath.Join(string(d), filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o600)
+}
+
+func (d dirBlobs) get(_ context.Context, key string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(string(d), filepath.FromSlash(key)))
+}
+
+func (d dirBlobs) list(_ context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(string(d), filepath.FromSlash(prefix)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			keys = append(keys, prefix+entry.Name())
+		}
+	}
+	return keys, nil
+}
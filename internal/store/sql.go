@@ -0,0 +1,201 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the pgx driver
+	_ "modernc.org/sqlite"             // Registers the sqlite driver
+)
+
+// schema creates the tables runs are kept in, so the history can be queried with SQL. Both
+// SQLite and Postgres accept it.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS runs (
+		id TEXT PRIMARY KEY,
+		target TEXT NOT NULL,
+		started_at TIMESTAMP NOT NULL,
+		tool_version TEXT NOT NULL,
+		interrupted TEXT NOT NULL,
+		repositories INTEGER NOT NULL,
+		non_compliant INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS repository_results (
+		run_id TEXT NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+		repository TEXT NOT NULL,
+		compliant BOOLEAN NOT NULL,
+		findings INTEGER NOT NULL,
+		errors INTEGER NOT NULL,
+		warnings INTEGER NOT NULL,
+		info INTEGER NOT NULL,
+		owners TEXT NOT NULL,
+		violations TEXT NOT NULL,
+		actions TEXT NOT NULL,
+		PRIMARY KEY (run_id, repository)
+	)`,
+	`CREATE INDEX IF NOT EXISTS runs_target_started_at ON runs (target, started_at)`,
+}
+
+// listSeparator joins the owners and violations of a repository result in their columns
+const listSeparator = "\n"
+
+// sqlStore keeps runs in a SQL database
+type sqlStore struct {
+	db *sql.DB
+}
+
+// openSQLite opens the SQLite database at path, creating it and its directory when missing
+func openSQLite(ctx context.Context, path string) (Store, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create store directory: %w", err)
+		}
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite store: %w", err)
+	}
+	return newSQLStore(ctx, db)
+}
+
+// openPostgres connects to the Postgres database at url
+func openPostgres(ctx context.Context, url string) (Store, error) {
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Postgres store: %w", err)
+	}
+	return newSQLStore(ctx, db)
+}
+
+// newSQLStore creates the schema in db when missing
+func newSQLStore(ctx context.Context, db *sql.DB) (Store, error) {
+	for _, statement := range schema {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to create store schema: %w", err)
+		}
+	}
+	return &sqlStore{db: db}, nil
+}
+
+// Save implements Store
+func (s *sqlStore) Save(ctx context.Context, run Run) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO runs (id, target, started_at, tool_version, interrupted, repositories, non_compliant) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		run.ID, run.Target, run.StartedAt.UTC(), run.ToolVersion, run.Interrupted, run.Total, run.NonCompliant); err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	for _, result := range run.Repositories {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO repository_results (run_id, repository, compliant, findings, errors, warnings, info, owners, violations, actions) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			run.ID, result.Repository, result.Compliant, result.Findings, result.Errors, result.Warnings, result.Info,
+			strings.Join(result.Owners, listSeparator), strings.Join(result.Violations, listSeparator), strings.Join(result.Actions, listSeparator)); err != nil {
+			return fmt.Errorf("failed to save results of %s: %w", result.Repository, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	return nil
+}
+
+const selectRuns = `SELECT id, target, started_at, tool_version, interrupted, repositories, non_compliant FROM runs`
+
+// Runs implements Store
+func (s *sqlStore) Runs(ctx context.Context, filter Filter) ([]Run, error) {
+	query := selectRuns + ` WHERE ($1 = '' OR LOWER(target) = LOWER($1)) AND started_at >= $2 ORDER BY started_at DESC, id DESC`
+	args := []interface{}{filter.Target, filter.Since.UTC()}
+	if filter.Limit > 0 {
+		query += ` LIMIT $3`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	return runs, nil
+}
+
+// Run implements Store
+func (s *sqlStore) Run(ctx context.Context, id string) (Run, error) {
+	run, err := scanRun(s.db.QueryRowContext(ctx, selectRuns+` WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Run{}, ErrNotFound
+	} else if err != nil {
+		return Run{}, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT repository, compliant, findings, errors, warnings, info, owners, violations, actions FROM repository_results WHERE run_id = $1 ORDER BY repository`, id)
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to read results of run %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result RepositoryRun
+		var owners, violations, actions string
+		if err := rows.Scan(&result.Repository, &result.Compliant, &result.Findings, &result.Errors, &result.Warnings, &result.Info, &owners, &violations, &actions); err != nil {
+			return Run{}, fmt.Errorf("failed to read results of run %s: %w", id, err)
+		}
+		result.Owners = splitList(owners)
+		result.Violations = splitList(violations)
+		result.Actions = splitList(actions)
+		run.Repositories = append(run.Repositories, result)
+	}
+	if err := rows.Err(); err != nil {
+		return Run{}, fmt.Errorf("failed to read results of run %s: %w", id, err)
+	}
+	return run, nil
+}
+
+// Close implements Store
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// scanRun reads a run from a row of selectRuns
+func scanRun(row interface{ Scan(...interface{}) error }) (Run, error) {
+	var run Run
+	err := row.Scan(&run.ID, &run.Target, &run.StartedAt, &run.ToolVersion, &run.Interrupted, &run.Total, &run.NonCompliant)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Run{}, err
+	} else if err != nil {
+		return Run{}, fmt.Errorf("failed to read run: %w", err)
+	}
+	run.StartedAt = run.StartedAt.UTC()
+	return run, nil
+}
+
+// splitList splits a column of joined list entries
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, listSeparator)
+}
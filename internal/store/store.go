@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for a run the store does not hold
+var ErrNotFound = errors.New("run not found")
+
+// Run is the recorded outcome of an enforce run
+type Run struct {
+	ID           string          `json:"id"`
+	Target       string          `json:"target"` // Scanned organization or repository
+	StartedAt    time.Time       `json:"started_at"`
+	ToolVersion  string          `json:"tool_version"`
+	Interrupted  string          `json:"interrupted,omitempty"`
+	Total        int             `json:"repositories"`  // Evaluated repositories
+	NonCompliant int             `json:"non_compliant"` // Repositories not complying with the policy
+	Repositories []RepositoryRun `json:"results,omitempty"`
+}
+
+// RepositoryRun is the outcome of a repository in a run
+type RepositoryRun struct {
+	Repository string   `json:"repository"`
+	Compliant  bool     `json:"compliant"`
+	Findings   int      `json:"findings"`
+	Errors     int      `json:"errors"`
+	Warnings   int      `json:"warnings"`
+	Info       int      `json:"info"`
+	Owners     []string `json:"owners,omitempty"`
+	Violations []string `json:"violations,omitempty"`
+	Actions    []string `json:"actions,omitempty"` // Action references the workflows use
+}
+
+// Filter selects runs, newest first
+type Filter struct {
+	Target string    // Only runs of this organization or repository, case-insensitively
+	Since  time.Time // Only runs started at or after this time
+	Limit  int       // At most this many runs; zero for all
+}
+
+// Store keeps the history of enforce runs
+type Store interface {
+	// Save records a run along with the results of its repositories
+	Save(ctx context.Context, run Run) error
+	// Runs returns the runs matching the filter, newest first, without their repository results
+	Runs(ctx context.Context, filter Filter) ([]Run, error)
+	// Run returns a run with its repository results, or ErrNotFound
+	Run(ctx context.Context, id string) (Run, error)
+	Close() error
+}
+
+// NewID returns a run ID that sorts by start time
+func NewID(startedAt time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return startedAt.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+}
+
+// Open opens the store at location: a postgres:// or postgresql:// connection URL, an
+// s3://bucket/prefix or json://directory location for JSON documents, or else the path of a
+// SQLite database, optionally prefixed with sqlite://
+func Open(ctx context.Context, location string) (Store, error) {
+	switch {
+	case location == "":
+		return nil, errors.New("no store location given")
+	case strings.HasPrefix(location, "postgres://"), strings.HasPrefix(location, "postgresql://"):
+		return openPostgres(ctx, location)
+	case strings.HasPrefix(location, "s3://"):
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid S3 location %s, expected s3://bucket/prefix", location)
+		}
+		blobs, err := newS3Blobs(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonStore{blobs: blobs, prefix: strings.Trim(prefix, "/")}, nil
+	case strings.HasPrefix(location, "json://"):
+		return &jsonStore{blobs: dirBlobs(strings.TrimPrefix(location, "json://"))}, nil
+	default:
+		return openSQLite(ctx, strings.TrimPrefix(location, "sqlite://"))
+	}
+}
+
+// matches reports whether a run passes the filter's target and start time
+func (f Filter) matches(run Run) bool {
+	if f.Target != "" && !strings.EqualFold(f.Target, run.Target) {
+		return false
+	}
+	return f.Since.IsZero() || !run.StartedAt.Before(f.Since)
+}
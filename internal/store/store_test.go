@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRun(target string, startedAt time.Time) Run {
+	return Run{
+		ID:           NewID(startedAt),
+		Target:       target,
+		StartedAt:    startedAt,
+		ToolVersion:  "v1.2.3",
+		Total:        2,
+		NonCompliant: 1,
+		Repositories: []RepositoryRun{
+			{
+				Repository: target + "/api",
+				Findings:   3,
+				Errors:     2,
+				Warnings:   1,
+				Owners:     []string{"@" + target + "/platform", "@" + target + "/payments"},
+				Violations: []string{"bad/action@v1", "other/action@main"},
+				Actions:    []string{"actions/checkout@v4", "bad/action@v1", "other/action@main"},
+			},
+			{Repository: target + "/web", Compliant: true},
+		},
+	}
+}
+
+// testStore checks saving and querying runs in a store
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	first := testRun("your-org", base)
+	second := testRun("your-org", base.Add(24*time.Hour))
+	other := testRun("other-org", base.Add(48*time.Hour))
+	for _, run := range []Run{first, second, other} {
+		if err := store.Save(ctx, run); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	runs, err := store.Runs(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("Runs() error: %v", err)
+	}
+	if len(runs) != 3 || runs[0].ID != other.ID || runs[2].ID != first.ID {
+		t.Fatalf("Expected the 3 runs newest first, got %+v", runs)
+	}
+	if runs[0].Repositories != nil || runs[0].Total != 2 || runs[0].NonCompliant != 1 || !runs[0].StartedAt.Equal(other.StartedAt) {
+		t.Errorf("Expected run summaries without results, got %+v", runs[0])
+	}
+
+	runs, err = store.Runs(ctx, Filter{Target: "YOUR-ORG", Since: base.Add(time.Hour)})
+	if err != nil || len(runs) != 1 || runs[0].ID != second.ID {
+		t.Errorf("Expected the second run of your-org, got %+v (err: %v)", runs, err)
+	}
+	runs, err = store.Runs(ctx, Filter{Limit: 2})
+	if err != nil || len(runs) != 2 || runs[1].ID != second.ID {
+		t.Errorf("Expected the 2 newest runs, got %+v (err: %v)", runs, err)
+	}
+
+	run, err := store.Run(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !reflect.DeepEqual(run, first) {
+		t.Errorf("Run() = %+v, expected %+v", run, first)
+	}
+
+	if _, err := store.Run(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing run, got %v", err)
+	}
+}
+
+func TestSQLiteStore(t *testing.T) {
+	store, err := Open(context.Background(), filepath.Join(t.TempDir(), "history", "runs.db"))
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer store.Close()
+	testStore(t, store)
+}
+
+func TestJSONStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(context.Background(), "json://"+dir)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer store.Close()
+	testStore(t, store)
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "runs", "*.json"))
+	if len(matches) != 3 {
+		t.Errorf("Expected a JSON document per run, got %v", matches)
+	}
+	if _, err := store.Run(context.Background(), "../runs/x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected run IDs with paths to be rejected, got %v", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if id := NewID(time.Date(2026, 5, 1, 9, 0, 0, 500, time.UTC)); !strings.HasPrefix(id, "20260501T090000Z-") {
+		t.Errorf("Expected the ID to start with the start time, got %s", id)
+	}
+	if _, err := Open(context.Background(), "s3:///prefix"); err == nil {
+		t.Error("Expected an S3 location without a bucket to be rejected")
+	}
+	if _, err := Open(context.Background(), ""); err == nil {
+		t.Error("Expected an empty location to be rejected")
+	}
+}
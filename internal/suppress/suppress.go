@@ -0,0 +1,185 @@
+package suppress
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+// Marker starts an ignore annotation in a workflow file comment, such as
+//
+//	# action-control: ignore=legacy/tool reason="migrating to v2" expires=2025-09-01
+const Marker = "action-control:"
+
+// dateLayout is the format of the expires field
+const dateLayout = "2006-01-02"
+
+var (
+	markerPattern = regexp.MustCompile(`#\s*` + regexp.QuoteMeta(Marker) + `(.*)$`)
+	fieldPattern  = regexp.MustCompile(`(\w+)=("[^"]*"|\S+)`)
+)
+
+// Annotation is an ignore annotation in a workflow file. It suppresses policy violations of
+// the actions matching Pattern referenced in the same workflow file until it expires.
+type Annotation struct {
+	Pattern  string // Action name, optionally with @ref; globs are supported
+	Reason   string
+	Expires  string // Last day the annotation applies, YYYY-MM-DD; empty for no expiry
+	Workflow string
+	Ref      string // Branch of the workflow file; empty for the default branch
+	Line     int
+}
+
+// Expired reports whether the annotation no longer applies at now
+func (a Annotation) Expired(now time.Time) bool {
+	return a.Expires != "" && now.Format(dateLayout) > a.Expires
+}
+
+// Matches reports whether the annotation's pattern matches an action reference. Patterns
+// without a ref match every ref of the action.
+func (a Annotation) Matches(uses string) bool {
+	pattern := strings.ToLower(a.Pattern)
+	uses = strings.ToLower(uses)
+	if !strings.Contains(pattern, "@") {
+		uses, _, _ = strings.Cut(uses, "@")
+	}
+	matched, err := path.Match(pattern, uses)
+	return err == nil && matched
+}
+
+// Suppression is a violation suppressed by an annotation, or one whose annotation expired and
+// no longer suppresses it, for auditing
+type Suppression struct {
+	Action   string `json:"action"`
+	Workflow string `json:"workflow"`
+	Ref      string `json:"ref,omitempty"`
+	Line     int    `json:"line,omitempty"` // Line of the annotation
+	Pattern  string `json:"pattern"`
+	Reason   string `json:"reason"`
+	Expires  string `json:"expires,omitempty"`
+	Expired  bool   `json:"expired,omitempty"`
+}
+
+// Parse finds the ignore annotations in workflow files. Malformed annotations are returned
+// as errors and not applied: each needs an ignore pattern and a reason, and a valid expiry
+// date when it has one.
+func Parse(files []github.WorkflowFile) ([]Annotation, []error) {
+	var annotations []Annotation
+	var errs []error
+
+	for _, file := range files {
+		for i, line := range strings.Split(string(file.Content), "\n") {
+			match := markerPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+
+			annotation := Annotation{Workflow: file.Path, Ref: file.Ref, Line: i + 1}
+			for _, field := range fieldPattern.FindAllStringSubmatch(match[1], -1) {
+				value := strings.Trim(field[2], `"`)
+				switch field[1] {
+				case "ignore":
+					annotation.Pattern = value
+				case "reason":
+					annotation.Reason = value
+				case "expires":
+					annotation.Expires = value
+				}
+			}
+
+			if err := validate(annotation); err != nil {
+				errs = append(errs, fmt.Errorf("%s:%d: %w", file.Path, i+1, err))
+				continue
+			}
+			annotations = append(annotations, annotation)
+		}
+	}
+
+	return annotations, errs
+}
+
+func validate(annotation Annotation) error {
+	if annotation.Pattern == "" {
+		return fmt.Errorf("ignore annotation without an ignore= pattern")
+	}
+	if _, err := path.Match(annotation.Pattern, ""); err != nil {
+		return fmt.Errorf("invalid ignore pattern %q", annotation.Pattern)
+	}
+	if strings.TrimSpace(annotation.Reason) == "" {
+		return fmt.Errorf("ignore annotation for %s without a reason=", annotation.Pattern)
+	}
+	if annotation.Expires != "" {
+		if _, err := time.Parse(dateLayout, annotation.Expires); err != nil {
+			return fmt.Errorf("invalid expires date %q, expected YYYY-MM-DD", annotation.Expires)
+		}
+	}
+	return nil
+}
+
+// Apply removes the violations whose every reference is covered by an unexpired annotation in
+// its workflow file, and returns the remaining violations with the suppressions. References
+// covered only by expired annotations keep their violation and are listed as expired.
+func Apply(annotations []Annotation, actions []github.Action, violations []string, now time.Time) ([]string, []Suppression) {
+	if len(annotations) == 0 {
+		return violations, nil
+	}
+
+	var remaining []string
+	var suppressions []Suppression
+	for _, violation := range violations {
+		var applied, expired []Suppression
+		suppressed := true
+		for _, action := range actions {
+			if action.Uses != violation {
+				continue
+			}
+
+			annotation, ok := find(annotations, action, now, false)
+			if ok {
+				applied = append(applied, suppression(annotation, action, false))
+				continue
+			}
+			suppressed = false
+			if annotation, ok := find(annotations, action, now, true); ok {
+				expired = append(expired, suppression(annotation, action, true))
+			}
+		}
+
+		if suppressed && len(applied) > 0 {
+			suppressions = append(suppressions, applied...)
+			continue
+		}
+		remaining = append(remaining, violation)
+		suppressions = append(suppressions, expired...)
+	}
+
+	return remaining, suppressions
+}
+
+// find returns the first annotation in an action's workflow file matching it, among the
+// unexpired annotations or else the expired ones
+func find(annotations []Annotation, action github.Action, now time.Time, expired bool) (Annotation, bool) {
+	for _, annotation := range annotations {
+		if annotation.Workflow == action.Workflow && annotation.Ref == action.Ref && annotation.Expired(now) == expired && annotation.Matches(action.Uses) {
+			return annotation, true
+		}
+	}
+	return Annotation{}, false
+}
+
+func suppression(annotation Annotation, action github.Action, expired bool) Suppression {
+	return Suppression{
+		Action:   action.Uses,
+		Workflow: action.Workflow,
+		Ref:      action.Ref,
+		Line:     annotation.Line,
+		Pattern:  annotation.Pattern,
+		Reason:   annotation.Reason,
+		Expires:  annotation.Expires,
+		Expired:  expired,
+	}
+}
@@ -0,0 +1,113 @@
+package suppress
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+)
+
+const annotatedWorkflow = `on: push
+jobs:
+  build:
+    steps:
+      # action-control: ignore=legacy/tool reason="migrating to v2" expires=2025-09-01
+      - uses: legacy/tool@v1
+      - uses: old/tool@v2 # action-control: ignore=old/tool@v2 reason=replaced
+      # action-control: ignore=stale/* reason="expired exception" expires=2025-01-31
+      - uses: stale/tool@v1
+      # action-control: ignore=missing/reason
+      # action-control: ignore=bad/date reason=typo expires=2025-13-01
+`
+
+func TestParse(t *testing.T) {
+	files := []github.WorkflowFile{{Path: ".github/workflows/ci.yml", Content: []byte(annotatedWorkflow)}}
+
+	annotations, errs := Parse(files)
+	if len(annotations) != 3 {
+		t.Fatalf("Expected 3 annotations, got %+v", annotations)
+	}
+	want := Annotation{Pattern: "legacy/tool", Reason: "migrating to v2", Expires: "2025-09-01", Workflow: ".github/workflows/ci.yml", Line: 5}
+	if annotations[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, annotations[0])
+	}
+	if annotations[1].Pattern != "old/tool@v2" || annotations[1].Reason != "replaced" || annotations[1].Line != 7 {
+		t.Errorf("Expected the trailing annotation to be parsed, got %+v", annotations[1])
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 invalid annotations, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "ci.yml:10") || !strings.Contains(errs[0].Error(), "reason") {
+		t.Errorf("Expected the missing reason to be reported with its line, got %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), "2025-13-01") {
+		t.Errorf("Expected the invalid date to be reported, got %v", errs[1])
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		uses    string
+		want    bool
+	}{
+		{"legacy/tool", "legacy/tool@v1", true},
+		{"Legacy/Tool", "legacy/tool@v1", true},
+		{"legacy/tool@v1", "legacy/tool@v1", true},
+		{"legacy/tool@v1", "legacy/tool@v2", false},
+		{"legacy/*", "legacy/tool@v1", true},
+		{"legacy/*", "other/tool@v1", false},
+		{"legacy/tool", "legacy/tool-extra@v1", false},
+	}
+
+	for _, test := range tests {
+		if got := (Annotation{Pattern: test.pattern}).Matches(test.uses); got != test.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", test.pattern, test.uses, got, test.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	annotations := []Annotation{
+		{Pattern: "legacy/tool", Reason: "migrating", Expires: "2025-06-01", Workflow: "ci.yml", Line: 5},
+		{Pattern: "stale/tool", Reason: "old exception", Expires: "2025-05-31", Workflow: "ci.yml", Line: 8},
+		{Pattern: "shared/tool", Reason: "only here", Workflow: "ci.yml", Line: 11},
+	}
+	actions := []github.Action{
+		{Uses: "legacy/tool@v1", Workflow: "ci.yml"},
+		{Uses: "stale/tool@v1", Workflow: "ci.yml"},
+		{Uses: "shared/tool@v1", Workflow: "ci.yml"},
+		{Uses: "shared/tool@v1", Workflow: "release.yml"},
+		{Uses: "legacy/tool@v1", Workflow: "ci.yml", Ref: "release"},
+		{Uses: "other/tool@v1", Workflow: "ci.yml"},
+	}
+	violations := []string{"legacy/tool@v1", "stale/tool@v1", "shared/tool@v1", "other/tool@v1"}
+
+	remaining, suppressions := Apply(annotations, actions[:4], violations, now)
+	if !slices.Equal(remaining, []string{"stale/tool@v1", "shared/tool@v1", "other/tool@v1"}) {
+		t.Errorf("Expected expired and partially covered violations to remain, got %v", remaining)
+	}
+	if len(suppressions) != 2 {
+		t.Fatalf("Expected a suppression and an expired annotation, got %+v", suppressions)
+	}
+	if suppressions[0].Action != "legacy/tool@v1" || suppressions[0].Expired || suppressions[0].Line != 5 {
+		t.Errorf("Expected legacy/tool to be suppressed on its last day, got %+v", suppressions[0])
+	}
+	if suppressions[1].Action != "stale/tool@v1" || !suppressions[1].Expired {
+		t.Errorf("Expected the expired annotation to be recorded, got %+v", suppressions[1])
+	}
+
+	// Annotations on the default branch do not cover the workflow on other branches
+	remaining, _ = Apply(annotations, actions, []string{"legacy/tool@v1"}, now)
+	if !slices.Equal(remaining, []string{"legacy/tool@v1"}) {
+		t.Errorf("Expected the violation on another branch to remain, got %v", remaining)
+	}
+
+	if remaining, suppressions := Apply(nil, actions, violations, now); !slices.Equal(remaining, violations) || suppressions != nil {
+		t.Errorf("Expected violations to be unchanged without annotations, got %v and %v", remaining, suppressions)
+	}
+}
@@ -0,0 +1,54 @@
+package tokenscopes
+
+import "strings"
+
+// Kind is the type of credential a token is
+type Kind string
+
+const (
+	KindClassic      Kind = "classic personal access token"
+	KindFineGrained  Kind = "fine-grained personal access token"
+	KindInstallation Kind = "GitHub App installation token"
+	KindAppUser      Kind = "GitHub App user token"
+	KindOAuth        Kind = "OAuth app token"
+	KindUnknown      Kind = "token"
+)
+
+// prefixes identify the kind of a token by the prefix GitHub issues it with
+var prefixes = []struct {
+	prefix string
+	kind   Kind
+}{
+	{"github_pat_", KindFineGrained},
+	{"ghp_", KindClassic},
+	{"ghs_", KindInstallation},
+	{"ghu_", KindAppUser},
+	{"gho_", KindOAuth},
+}
+
+// Classify returns the kind of a token from its prefix. Tokens without a known prefix, such as
+// classic tokens issued before prefixes were introduced, are classic when scopesReported is
+// set, as only classic and OAuth tokens report scopes.
+func Classify(token string, scopesReported bool) Kind {
+	for _, p := range prefixes {
+		if strings.HasPrefix(token, p.prefix) {
+			return p.kind
+		}
+	}
+	if scopesReported {
+		return KindClassic
+	}
+	return KindUnknown
+}
+
+// FineGrained reports whether tokens of the kind are limited to the permissions and
+// repositories they were granted, rather than to broad scopes over everything their owner can
+// access
+func (k Kind) FineGrained() bool {
+	return k == KindFineGrained || k == KindInstallation || k == KindAppUser
+}
+
+// Scoped reports whether tokens of the kind are granted OAuth scopes
+func (k Kind) Scoped() bool {
+	return k == KindClassic || k == KindOAuth
+}
@@ -0,0 +1,40 @@
+package tokenscopes
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		token          string
+		scopesReported bool
+		expected       Kind
+	}{
+		{"ghp_abc", true, KindClassic},
+		{"github_pat_11ABC_def", false, KindFineGrained},
+		{"ghs_abc", false, KindInstallation},
+		{"ghu_abc", false, KindAppUser},
+		{"gho_abc", true, KindOAuth},
+		{"0123456789abcdef0123456789abcdef01234567", true, KindClassic},
+		{"0123456789abcdef0123456789abcdef01234567", false, KindUnknown},
+	}
+	for _, tt := range tests {
+		if kind := Classify(tt.token, tt.scopesReported); kind != tt.expected {
+			t.Errorf("Classify(%q, %v) = %q, want %q", tt.token, tt.scopesReported, kind, tt.expected)
+		}
+	}
+}
+
+func TestKindFineGrained(t *testing.T) {
+	for _, kind := range []Kind{KindFineGrained, KindInstallation, KindAppUser} {
+		if !kind.FineGrained() || kind.Scoped() {
+			t.Errorf("Expected %s to be fine-grained and not scoped", kind)
+		}
+	}
+	for _, kind := range []Kind{KindClassic, KindOAuth} {
+		if kind.FineGrained() || !kind.Scoped() {
+			t.Errorf("Expected %s to be scoped and not fine-grained", kind)
+		}
+	}
+	if KindUnknown.FineGrained() || KindUnknown.Scoped() {
+		t.Error("Expected an unknown token to be neither fine-grained nor scoped")
+	}
+}
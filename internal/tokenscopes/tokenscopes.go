@@ -0,0 +1,121 @@
+package tokenscopes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Requirement is a token scope an operation needs, and what it is needed for
+type Requirement struct {
+	Scope   string
+	Purpose string
+}
+
+// String returns the scope and its purpose
+func (r Requirement) String() string {
+	if r.Purpose == "" {
+		return r.Scope
+	}
+	return fmt.Sprintf("%s (%s)", r.Scope, r.Purpose)
+}
+
+// readRepositories lets scans see private and internal repositories and read their workflow
+// files; without it they are silently left out of organization listings
+var readRepositories = Requirement{Scope: "repo", Purpose: "list private repositories and read their workflow files"}
+
+// Defaults are the scopes of a classic token each command needs
+var Defaults = map[string][]Requirement{
+	"report":            {readRepositories},
+	"enforce":           {readRepositories},
+	"export":            {readRepositories},
+	"impact":            {readRepositories},
+	"review":            {readRepositories},
+	"inventory":         {readRepositories},
+	"scan":              {readRepositories},
+	"sync-org-settings": {{Scope: "admin:org", Purpose: "read and update the organization's allowed actions settings"}},
+}
+
+// parents maps each scope to the broader scope that includes it
+var parents = map[string]string{
+	"repo:status":        "repo",
+	"repo_deployment":    "repo",
+	"public_repo":        "repo",
+	"repo:invite":        "repo",
+	"security_events":    "repo",
+	"write:org":          "admin:org",
+	"read:org":           "write:org",
+	"manage_runners:org": "admin:org",
+	"write:repo_hook":    "admin:repo_hook",
+	"read:repo_hook":     "write:repo_hook",
+	"write:public_key":   "admin:public_key",
+	"read:public_key":    "write:public_key",
+	"write:gpg_key":      "admin:gpg_key",
+	"read:gpg_key":       "write:gpg_key",
+	"read:packages":      "write:packages",
+	"read:user":          "user",
+	"user:email":         "user",
+	"user:follow":        "user",
+	"read:enterprise":    "admin:enterprise",
+}
+
+// Required returns the scopes a command needs. An override for the command, such as from the
+// token_scopes setting, replaces the defaults; an empty override skips the check.
+func Required(command string, overrides map[string][]string) []Requirement {
+	if scopes, ok := overrides[command]; ok {
+		required := make([]Requirement, 0, len(scopes))
+		for _, scope := range scopes {
+			required = append(required, Requirement{Scope: scope})
+		}
+		return required
+	}
+	return Defaults[command]
+}
+
+// Missing returns the required scopes not granted, directly or through a broader scope
+func Missing(granted []string, required []Requirement) []Requirement {
+	grantedSet := make(map[string]bool, len(granted))
+	for _, scope := range granted {
+		grantedSet[strings.ToLower(scope)] = true
+	}
+
+	var missing []Requirement
+	for _, requirement := range required {
+		if !covered(grantedSet, strings.ToLower(requirement.Scope)) {
+			missing = append(missing, requirement)
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		return missing[i].Scope < missing[j].Scope
+	})
+	return missing
+}
+
+// Excessive returns the granted scopes beyond what the required scopes need, neither required
+// nor included in a required scope, sorted
+func Excessive(granted []string, required []Requirement) []string {
+	requiredSet := make(map[string]bool, len(required))
+	for _, requirement := range required {
+		requiredSet[strings.ToLower(requirement.Scope)] = true
+	}
+
+	var excessive []string
+	for _, scope := range granted {
+		if !covered(requiredSet, strings.ToLower(scope)) {
+			excessive = append(excessive, scope)
+		}
+	}
+	sort.Strings(excessive)
+	return excessive
+}
+
+// covered reports whether a scope or one of the scopes including it was granted
+func covered(granted map[string]bool, scope string) bool {
+	for scope != "" {
+		if granted[scope] {
+			return true
+		}
+		scope = parents[scope]
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package tokenscopes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissing(t *testing.T) {
+	required := []Requirement{
+		{Scope: "repo", Purpose: "read repositories"},
+		{Scope: "read:org"},
+	}
+
+	tests := []struct {
+		name     string
+		granted  []string
+		expected []Requirement
+	}{
+		{"all granted", []string{"repo", "read:org"}, nil},
+		{"broader scope", []string{"repo", "admin:org"}, nil},
+		{"narrower scope", []string{"public_repo", "read:org"}, []Requirement{{Scope: "repo", Purpose: "read repositories"}}},
+		{"case insensitive", []string{"REPO", "Read:Org"}, nil},
+		{"none", nil, []Requirement{{Scope: "read:org"}, {Scope: "repo", Purpose: "read repositories"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if missing := Missing(tt.granted, required); !reflect.DeepEqual(missing, tt.expected) {
+				t.Errorf("Missing(%v) = %+v, want %+v", tt.granted, missing, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	if required := Required("sync-org-settings", nil); len(required) != 1 || required[0].Scope != "admin:org" {
+		t.Errorf("Expected admin:org for sync-org-settings, got %+v", required)
+	}
+	if required := Required("policy", nil); len(required) != 0 {
+		t.Errorf("Expected no scopes for commands without API access, got %+v", required)
+	}
+
+	// Overrides replace the defaults, and an empty override skips the check
+	overrides := map[string][]string{"report": {"public_repo"}, "enforce": {}}
+	if required := Required("report", overrides); !reflect.DeepEqual(required, []Requirement{{Scope: "public_repo"}}) {
+		t.Errorf("Expected the override for report, got %+v", required)
+	}
+	if required := Required("enforce", overrides); len(required) != 0 {
+		t.Errorf("Expected no scopes with an empty override, got %+v", required)
+	}
+	if required := Required("export", overrides); !reflect.DeepEqual(required, Defaults["export"]) {
+		t.Errorf("Expected the defaults without an override, got %+v", required)
+	}
+}
+
+func TestRequirementString(t *testing.T) {
+	if s := (Requirement{Scope: "repo", Purpose: "read repositories"}).String(); s != "repo (read repositories)" {
+		t.Errorf("String() = %q", s)
+	}
+	if s := (Requirement{Scope: "read:org"}).String(); s != "read:org" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestExcessive(t *testing.T) {
+	required := []Requirement{{Scope: "repo"}, {Scope: "read:org"}}
+
+	tests := []struct {
+		name     string
+		granted  []string
+		expected []string
+	}{
+		{"exact", []string{"repo", "read:org"}, nil},
+		{"narrower scopes", []string{"public_repo", "repo:status"}, nil},
+		{"broader scope", []string{"repo", "admin:org"}, []string{"admin:org"}},
+		{"unrelated scopes", []string{"workflow", "repo", "delete_repo"}, []string{"delete_repo", "workflow"}},
+		{"case insensitive", []string{"REPO", "Read:Org"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if excessive := Excessive(tt.granted, required); !reflect.DeepEqual(excessive, tt.expected) {
+				t.Errorf("Excessive(%v) = %v, want %v", tt.granted, excessive, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package updates
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported dependency update tools
+const (
+	ToolDependabot = "dependabot"
+	ToolRenovate   = "renovate"
+)
+
+// actionsEcosystem is the Dependabot ecosystem and Renovate manager updating workflow actions
+const actionsEcosystem = "github-actions"
+
+// ContentFetcher reads a file from a repository, returning an error or no content when it does not exist
+type ContentFetcher interface {
+	GetRepositoryContent(ctx context.Context, owner, repo, path string) ([]byte, error)
+}
+
+// Configuration file locations, in the order the tools look them up
+var (
+	dependabotPaths = []string{".github/dependabot.yml", ".github/dependabot.yaml"}
+	renovatePaths   = []string{
+		"renovate.json", "renovate.json5",
+		".github/renovate.json", ".github/renovate.json5",
+		".gitlab/renovate.json", ".gitlab/renovate.json5",
+		".renovaterc", ".renovaterc.json", ".renovaterc.json5",
+	}
+)
+
+// Coverage describes whether a repository has automated updates configured for its actions
+type Coverage struct {
+	Covered    bool   `json:"covered"`
+	Tool       string `json:"tool,omitempty"`        // Tool whose configuration was found
+	ConfigPath string `json:"config_path,omitempty"` // Path of that configuration
+}
+
+// ConfigPaths returns the paths of the Dependabot and Renovate configurations Check reads
+func ConfigPaths() []string {
+	return append(append([]string(nil), dependabotPaths...), renovatePaths...)
+}
+
+// Check looks for a Dependabot or Renovate configuration that updates the repository's actions.
+// When configurations exist but none covers actions, the first one found is reported.
+func Check(ctx context.Context, fetcher ContentFetcher, owner, repo string) Coverage {
+	var found Coverage
+
+	candidates := []struct {
+		tool   string
+		paths  []string
+		covers func([]byte) bool
+	}{
+		{ToolDependabot, dependabotPaths, DependabotCoversActions},
+		{ToolRenovate, renovatePaths, RenovateCoversActions},
+	}
+
+	for _, candidate := range candidates {
+		for _, path := range candidate.paths {
+			content, err := fetcher.GetRepositoryContent(ctx, owner, repo, path)
+			if err != nil || len(content) == 0 {
+				continue
+			}
+
+			coverage := Coverage{Covered: candidate.covers(content), Tool: candidate.tool, ConfigPath: path}
+			if coverage.Covered {
+				return coverage
+			}
+			if found.Tool == "" {
+				found = coverage
+			}
+			break // Each tool only reads its first configuration file
+		}
+	}
+
+	return found
+}
+
+// DependabotCoversActions reports whether a dependabot.yml configures the github-actions ecosystem
+func DependabotCoversActions(content []byte) bool {
+	var config struct {
+		Updates []struct {
+			PackageEcosystem string `yaml:"package-ecosystem"`
+		} `yaml:"updates"`
+	}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return false
+	}
+
+	for _, update := range config.Updates {
+		if update.PackageEcosystem == actionsEcosystem {
+			return true
+		}
+	}
+	return false
+}
+
+// RenovateCoversActions reports whether a Renovate configuration leaves the github-actions
+// manager enabled. Renovate enables it by default, so only configurations disabling Renovate,
+// restricting enabledManagers or disabling the manager are reported as not covering actions.
+// JSON5 configurations that are not plain JSON cannot be inspected and are assumed to cover actions.
+func RenovateCoversActions(content []byte) bool {
+	var config struct {
+		Enabled         *bool    `json:"enabled"`
+		EnabledManagers []string `json:"enabledManagers"`
+		GitHubActions   *struct {
+			Enabled *bool `json:"enabled"`
+		} `json:"github-actions"`
+	}
+	if err := json.Unmarshal(content, &config); err != nil {
+		return true
+	}
+
+	if config.Enabled != nil && !*config.Enabled {
+		return false
+	}
+	if len(config.EnabledManagers) > 0 && !containsFold(config.EnabledManagers, actionsEcosystem) {
+		return false
+	}
+	if config.GitHubActions != nil && config.GitHubActions.Enabled != nil && !*config.GitHubActions.Enabled {
+		return false
+	}
+	return true
+}
+
+// containsFold checks if a slice contains a string, ignoring case
+func containsFold(slice []string, item string) bool {
+	for _, s := range slice {
+		if strings.EqualFold(s, item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package updates
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeFetcher serves repository files from a map
+type fakeFetcher map[string]string
+
+func (f fakeFetcher) GetRepositoryContent(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	content, ok := f[path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return []byte(content), nil
+}
+
+func TestDependabotCoversActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{
+			name:     "github-actions ecosystem",
+			content:  "version: 2\nupdates:\n  - package-ecosystem: gomod\n    directory: /\n  - package-ecosystem: github-actions\n    directory: /\n",
+			expected: true,
+		},
+		{
+			name:     "other ecosystems only",
+			content:  "version: 2\nupdates:\n  - package-ecosystem: npm\n    directory: /\n",
+			expected: false,
+		},
+		{
+			name:     "invalid YAML",
+			content:  "updates: [",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DependabotCoversActions([]byte(tt.content)); got != tt.expected {
+				t.Errorf("DependabotCoversActions() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenovateCoversActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"default managers", `{"extends": ["config:recommended"]}`, true},
+		{"enabled managers include actions", `{"enabledManagers": ["gomod", "github-actions"]}`, true},
+		{"enabled managers exclude actions", `{"enabledManagers": ["gomod"]}`, false},
+		{"manager disabled", `{"github-actions": {"enabled": false}}`, false},
+		{"renovate disabled", `{"enabled": false}`, false},
+		{"JSON5 assumed covered", "{\n  // comment\n  extends: ['config:recommended'],\n}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenovateCoversActions([]byte(tt.content)); got != tt.expected {
+				t.Errorf("RenovateCoversActions() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		files    fakeFetcher
+		expected Coverage
+	}{
+		{
+			name:     "no configuration",
+			files:    fakeFetcher{},
+			expected: Coverage{},
+		},
+		{
+			name:     "dependabot",
+			files:    fakeFetcher{".github/dependabot.yaml": "updates:\n  - package-ecosystem: github-actions\n"},
+			expected: Coverage{Covered: true, Tool: ToolDependabot, ConfigPath: ".github/dependabot.yaml"},
+		},
+		{
+			name: "renovate covers what dependabot does not",
+			files: fakeFetcher{
+				".github/dependabot.yml": "updates:\n  - package-ecosystem: npm\n",
+				".github/renovate.json":  `{}`,
+			},
+			expected: Coverage{Covered: true, Tool: ToolRenovate, ConfigPath: ".github/renovate.json"},
+		},
+		{
+			name:     "configuration without actions",
+			files:    fakeFetcher{".github/dependabot.yml": "updates:\n  - package-ecosystem: npm\n"},
+			expected: Coverage{Covered: false, Tool: ToolDependabot, ConfigPath: ".github/dependabot.yml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(ctx, tt.files, "owner", "repo"); got != tt.expected {
+				t.Errorf("Check() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
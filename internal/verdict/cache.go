@@ -0,0 +1,87 @@
+package verdict
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Entry is the outcome of a previous enforce run
+type Entry struct {
+	Key       string    `json:"key"`
+	Output    string    `json:"output"`
+	ExitCode  int       `json:"exit_code"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Cache records enforce verdicts per repository, keyed by a hash of every input that
+// determines the verdict, so unchanged repositories need not be evaluated again
+type Cache struct {
+	Entries map[string]Entry `json:"entries"`
+}
+
+// Key derives a cache key from the inputs that determine a verdict
+func Key(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Load reads a cache file. A missing file yields an empty cache.
+func Load(path string) (*Cache, error) {
+	cache := &Cache{Entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verdict cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failed to parse verdict cache: %w", err)
+	}
+	if cache.Entries == nil {
+		cache.Entries = make(map[string]Entry)
+	}
+
+	return cache, nil
+}
+
+// Lookup returns the cached verdict for a repository if it was recorded with the same key
+func (c *Cache) Lookup(repo, key string) (Entry, bool) {
+	entry, ok := c.Entries[repo]
+	if !ok || entry.Key != key {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Store records the verdict for a repository, replacing any previous entry
+func (c *Cache) Store(repo, key, output string, exitCode int) {
+	c.Entries[repo] = Entry{
+		Key:       key,
+		Output:    output,
+		ExitCode:  exitCode,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Save writes the cache file
+func (c *Cache) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal verdict cache: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write verdict cache: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package verdict
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	if Key("a", "bc") == Key("ab", "c") {
+		t.Error("Expected key parts to be separated")
+	}
+	if Key("tree", "policy") != Key("tree", "policy") {
+		t.Error("Expected keys to be deterministic")
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "verdicts.json")
+
+	// A missing file is an empty cache
+	cache, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if _, ok := cache.Lookup("org/repo", "k1"); ok {
+		t.Error("Expected empty cache to miss")
+	}
+
+	cache.Store("org/repo", "k1", "✅ All repositories comply with the action policy.\n", 0)
+	cache.Store("org/other", "k2", "violations\n", 1)
+	if err := cache.Save(path); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	entry, ok := loaded.Lookup("org/other", "k2")
+	if !ok || entry.ExitCode != 1 || entry.Output != "violations\n" || entry.CreatedAt.IsZero() {
+		t.Errorf("Unexpected cached entry: %+v (hit: %v)", entry, ok)
+	}
+	if _, ok := loaded.Lookup("org/repo", "changed"); ok {
+		t.Error("Expected a different key to miss")
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for corrupt cache, got nil")
+	}
+}
@@ -0,0 +1,74 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is the release version of the binary, set at build time via
+// -ldflags "-X github.com/ihavespoons/action-control/internal/version.Version=v1.2.3"
+var Version = "dev"
+
+// IsRelease reports whether the binary was built from a tagged release
+func IsRelease() bool {
+	_, err := parse(Version)
+	return err == nil
+}
+
+// Compare compares two semantic versions, returning -1, 0 or 1.
+// A leading "v" and any pre-release or build suffix are ignored.
+func Compare(a, b string) (int, error) {
+	va, err := parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parse(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < 3; i++ {
+		if va[i] < vb[i] {
+			return -1, nil
+		}
+		if va[i] > vb[i] {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// AtLeast reports whether the running binary is at or above the minimum version
+func AtLeast(minimum string) (bool, error) {
+	cmp, err := Compare(Version, minimum)
+	if err != nil {
+		return false, err
+	}
+	return cmp >= 0, nil
+}
+
+// parse splits a version string into its major, minor and patch components
+func parse(v string) ([3]int, error) {
+	var parts [3]int
+
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	fields := strings.Split(v, ".")
+	if len(fields) == 0 || len(fields) > 3 || fields[0] == "" {
+		return parts, fmt.Errorf("invalid version: %q", v)
+	}
+
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, fmt.Errorf("invalid version: %q", v)
+		}
+		parts[i] = n
+	}
+
+	return parts, nil
+}
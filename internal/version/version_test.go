@@ -0,0 +1,48 @@
+package version
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.4.0", "1.4.0", 0},
+		{"v1.4.0", "1.4", 0},
+		{"1.3.9", "1.4.0", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0-rc.1", "1.9.9", 1},
+	}
+
+	for _, tt := range tests {
+		got, err := Compare(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("Compare(%q, %q) returned error: %v", tt.a, tt.b, err)
+		}
+		if got != tt.expected {
+			t.Errorf("Compare(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+
+	if _, err := Compare("dev", "1.0.0"); err == nil {
+		t.Error("Expected error comparing non-semantic version, got nil")
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	original := Version
+	defer func() { Version = original }()
+
+	Version = "v1.4.2"
+	if ok, err := AtLeast("1.4.0"); err != nil || !ok {
+		t.Errorf("Expected v1.4.2 to satisfy 1.4.0, got %v (err: %v)", ok, err)
+	}
+	if ok, err := AtLeast("1.5.0"); err != nil || ok {
+		t.Errorf("Expected v1.4.2 not to satisfy 1.5.0, got %v (err: %v)", ok, err)
+	}
+
+	Version = "dev"
+	if IsRelease() {
+		t.Error("Expected dev build not to be a release")
+	}
+}
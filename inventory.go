@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/inventory"
+	"github.com/ihavespoons/action-control/internal/policy"
+
+	"github.com/spf13/viper"
+)
+
+func runInventory() {
+	// Validate GitHub token
+	token := resolveToken()
+
+	// Get target organization or repository
+	org := viper.GetString("organization")
+	specificRepo := viper.GetString("repository")
+
+	// At least one target must be specified
+	if org == "" && specificRepo == "" {
+		log.Fatal("Either organization (--org) or specific repository (--repo) must be provided.")
+	}
+
+	// The inventory is consumed by tooling, so default to JSON
+	outputFormat := outputFormat("json")
+	if outputFormat != "json" && outputFormat != "html" {
+		log.Fatalf("Unsupported output format for inventory: %s", outputFormat)
+	}
+
+	// Evaluate policy status when a policy is provided
+	var config *policy.PolicyConfig
+	if policyFile := viper.GetString("inventory_policy_file"); policyFile != "" {
+		var err error
+		config, err = policy.LoadPolicyConfig(policyFile)
+		if err != nil {
+			log.Fatalf("Error loading policy file: %v", err)
+		}
+	}
+
+	// Carry first-seen timestamps over from the previous inventory
+	var previous *inventory.Inventory
+	if previousFile := viper.GetString("inventory_previous"); previousFile != "" {
+		var err error
+		previous, err = inventory.Load(previousFile)
+		if err != nil {
+			log.Fatalf("Error loading previous inventory: %v", err)
+		}
+	}
+
+	// Initialize GitHub API client
+	client := newClient(token)
+	ctx, cancel := commandContext()
+	defer cancel()
+	checkTokenScopes(ctx, client, "inventory")
+	exitWithEstimate(ctx, client, org, specificRepo, 0)
+
+	// Map to store discovered actions by repository
+	githubActionsMap := make(map[string][]github.Action)
+	var err error
+
+	if specificRepo != "" {
+		// Scan a single repository
+		parts := strings.Split(specificRepo, "/")
+		if len(parts) != 2 {
+			log.Fatalf("Invalid repository format. Use 'owner/repo' format.")
+		}
+
+		log.Printf("Scanning repository %s...", specificRepo)
+		actions, err := client.GetActions(ctx, parts[0], parts[1])
+		if err != nil {
+			log.Fatalf("Error retrieving actions from repository %s: %v", specificRepo, err)
+		}
+		githubActionsMap[specificRepo] = actions
+	} else {
+		// Scan an entire organization
+		log.Printf("Scanning repositories in %s organization...", org)
+		checkpointOrgScan(client, org)
+		githubActionsMap, err = client.ActionsForOrg(ctx, org)
+		if scanInterrupted(err) {
+			defer exit(1) // Signal the interruption once the partial results are reported
+		} else if err != nil {
+			log.Fatalf("Error retrieving actions: %v", err)
+		}
+	}
+
+	inv := inventory.Build(githubActionsMap, config, previous, time.Now().UTC())
+	inv.EnrichPublishers(ctx, client)
+	if viper.GetBool("inventory_enrich_metadata") {
+		inv.EnrichMetadata(ctx, client)
+	}
+
+	var result string
+	switch outputFormat {
+	case "json":
+		result, err = formatter.FormatJSON(inv)
+		if err != nil {
+			log.Fatalf("Error formatting JSON: %v", err)
+		}
+	case "html":
+		result, err = formatter.FormatInventoryHTML(inv)
+		if err != nil {
+			log.Fatalf("Error formatting HTML: %v", err)
+		}
+	}
+
+	fmt.Println(result)
+}
@@ -1,19 +1,49 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
-
+	"syscall"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/callgraph"
+	"github.com/ihavespoons/action-control/internal/checkpoint"
+	"github.com/ihavespoons/action-control/internal/config"
+	"github.com/ihavespoons/action-control/internal/credentials"
+	"github.com/ihavespoons/action-control/internal/dashboard"
+	"github.com/ihavespoons/action-control/internal/deadlinks"
+	"github.com/ihavespoons/action-control/internal/deprecations"
 	"github.com/ihavespoons/action-control/internal/export"
 	"github.com/ihavespoons/action-control/internal/formatter"
 	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/history"
+	"github.com/ihavespoons/action-control/internal/metadata"
+	"github.com/ihavespoons/action-control/internal/pinning"
 	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/policytest"
+	"github.com/ihavespoons/action-control/internal/progress"
+	"github.com/ihavespoons/action-control/internal/repocache"
+	"github.com/ihavespoons/action-control/internal/runtimes"
+	"github.com/ihavespoons/action-control/internal/snapshot"
+	"github.com/ihavespoons/action-control/internal/tokenscopes"
+	"github.com/ihavespoons/action-control/internal/verdict"
+	"github.com/ihavespoons/action-control/internal/version"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 )
 
 func main() {
@@ -22,8 +52,9 @@ func main() {
 
 	// Define root command
 	var rootCmd = &cobra.Command{
-		Use:   "action-control",
-		Short: "A CLI tool to enforce a Github actions policy that you create",
+		Use:     "action-control",
+		Short:   "A CLI tool to enforce a Github actions policy that you create",
+		Version: version.Version,
 	}
 
 	// Define subcommands
@@ -43,6 +74,14 @@ func main() {
 		},
 	}
 
+	var scanCmd = &cobra.Command{
+		Use:   "scan",
+		Short: "Capture workflow files into a scan file for evaluating policy offline with enforce --input",
+		Run: func(cmd *cobra.Command, args []string) {
+			runScan()
+		},
+	}
+
 	var exportCmd = &cobra.Command{
 		Use:   "export",
 		Short: "Export a policy file based on discovered GitHub Actions",
@@ -51,52 +90,453 @@ func main() {
 		},
 	}
 
+	var historyCmd = &cobra.Command{
+		Use:   "history [run-id]",
+		Short: "List the enforce runs recorded in the store, or show the results of one run",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runHistory(args)
+		},
+	}
+
+	var serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a REST API querying the enforce runs recorded in the store and starting scans",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runServe(cmd)
+		},
+	}
+
+	var impactCmd = &cobra.Command{
+		Use:   "impact",
+		Short: "Report workflows pinned to runner labels that are being retired",
+		Run: func(cmd *cobra.Command, args []string) {
+			runImpact()
+		},
+	}
+
+	var reviewCmd = &cobra.Command{
+		Use:   "review",
+		Short: "Interactively review discovered actions and update the allow list",
+		Run: func(cmd *cobra.Command, args []string) {
+			runReview()
+		},
+	}
+
+	var syncOrgSettingsCmd = &cobra.Command{
+		Use:   "sync-org-settings",
+		Short: "Compare the organization's allowed actions settings with the policy and reconcile them with --apply",
+		Run: func(cmd *cobra.Command, args []string) {
+			runSyncOrgSettings()
+		},
+	}
+
+	var policyCmd = &cobra.Command{
+		Use:   "policy",
+		Short: "Work with policy files",
+	}
+
+	var policyTestCmd = &cobra.Command{
+		Use:   "test",
+		Short: "Run table-style test cases against a policy file",
+		Run: func(cmd *cobra.Command, args []string) {
+			runPolicyTest()
+		},
+	}
+
+	var policyExplainCmd = &cobra.Command{
+		Use:   "explain",
+		Short: "Explain which policy rule allows or denies an action in a repository",
+		Run: func(cmd *cobra.Command, args []string) {
+			runPolicyExplain()
+		},
+	}
+
+	var policyMigrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade policy files to the newest policy format version",
+		Run: func(cmd *cobra.Command, args []string) {
+			runPolicyMigrate()
+		},
+	}
+
+	var actionsCmd = &cobra.Command{
+		Use:   "actions",
+		Short: "Inspect the actions used across your organization",
+	}
+
+	var actionsInventoryCmd = &cobra.Command{
+		Use:   "inventory",
+		Short: "Output one record per unique action with versions, repositories, pinning, publisher and policy status",
+		Run: func(cmd *cobra.Command, args []string) {
+			runInventory()
+		},
+	}
+
+	var selfUpdateCmd = &cobra.Command{
+		Use:   "self-update",
+		Short: "Replace the running binary with the latest verified release",
+		Run: func(cmd *cobra.Command, args []string) {
+			runSelfUpdate()
+		},
+	}
+
+	var actionEntrypointCmd = &cobra.Command{
+		Use:    "action-entrypoint",
+		Short:  "Enforce policy from the GitHub Action's INPUT_* environment variables",
+		Hidden: true, // Entrypoint of the Docker action
+		Run: func(cmd *cobra.Command, args []string) {
+			runActionEntrypoint()
+		},
+	}
+
+	var configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Create, inspect and update the configuration file",
+	}
+
+	var configInitCmd = &cobra.Command{
+		Use:   "init",
+		Short: "Create a commented config.yaml documenting every setting",
+		Run: func(cmd *cobra.Command, args []string) {
+			force, _ := cmd.Flags().GetBool("force")
+			runConfigInit(force)
+		},
+	}
+
+	var configGetCmd = &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the effective value of a setting",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+			runConfigGet(args[0], showSecrets)
+		},
+	}
+
+	var configSetCmd = &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a setting in the config file",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			runConfigSet(args[0], args[1])
+		},
+	}
+
+	var configViewCmd = &cobra.Command{
+		Use:   "view",
+		Short: "Print the effective configuration from flags, environment and config file, and where each setting comes from",
+		Run: func(cmd *cobra.Command, args []string) {
+			showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+			runConfigView(showSecrets)
+		},
+	}
+
+	var configEnvCmd = &cobra.Command{
+		Use:   "env",
+		Short: "List the environment variables that set each setting",
+		Run: func(cmd *cobra.Command, args []string) {
+			runConfigEnv()
+		},
+	}
+
+	var authCmd = &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the API token stored in the OS keychain",
+	}
+
+	var authLoginCmd = &cobra.Command{
+		Use:   "login",
+		Short: "Store an API token using the git credential helper",
+		Run: func(cmd *cobra.Command, args []string) {
+			withToken, _ := cmd.Flags().GetBool("with-token")
+			runAuthLogin(withToken)
+		},
+	}
+
+	var authLogoutCmd = &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored API token",
+		Run: func(cmd *cobra.Command, args []string) {
+			runAuthLogout()
+		},
+	}
+
+	var authStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show where the API token is loaded from",
+		Run: func(cmd *cobra.Command, args []string) {
+			runAuthStatus()
+		},
+	}
+
 	// Configure global flags available to all commands
 	rootCmd.PersistentFlags().String("config", "", "config file (default is ./config.yaml)")
 	rootCmd.PersistentFlags().String("org", "", "GitHub organization name")
 	rootCmd.PersistentFlags().String("repo", "", "Specific repository to check (format: owner/repo)")
-	rootCmd.PersistentFlags().String("output", "", "Output format (markdown or json)")
+	rootCmd.PersistentFlags().StringArray("output", nil, "Output format (markdown, json, sarif, html or dashboard); repeat with --output-file to write several formats in one run")
+	rootCmd.PersistentFlags().StringArray("output-file", nil, "File to write the output format at the same position to instead of standard output")
+	rootCmd.PersistentFlags().String("template", "", "Go text/template file rendered by the template output format")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Print per-repository timing and API call counts during scans")
+	rootCmd.PersistentFlags().Bool("quiet", false, "Suppress scan progress messages, printing only results and errors")
+	rootCmd.PersistentFlags().String("progress-format", "text", "Scan progress format on stderr: text or json (NDJSON events)")
+	rootCmd.PersistentFlags().String("provider", "github", "Forge provider: github, gitea, forgejo or fixture")
+	rootCmd.PersistentFlags().String("store", "", "Store enforce runs are recorded in: a SQLite database path, postgres:// URL, s3://bucket/prefix or json://directory")
+	rootCmd.PersistentFlags().String("fixture", "", "Fixture bundle of repositories and workflow files served by --provider fixture")
+	rootCmd.PersistentFlags().String("record", "", "Record the API responses of the scan into a fixture bundle to replay with --provider fixture")
+	rootCmd.PersistentFlags().Bool("redact", false, "Replace the names of private and internal repositories with pseudonyms in report and enforce output, to share it outside the organization")
+	rootCmd.PersistentFlags().Bool("hardened", false, "Reject oversized, deeply nested or alias-expanding workflow files instead of parsing them")
+	rootCmd.PersistentFlags().String("branches", "", "Also scan workflow files on branches matching this glob pattern (e.g. 'release/*')")
+	rootCmd.PersistentFlags().Bool("all-branches", false, "Also scan workflow files on all branches, same as --branches '*'")
+	rootCmd.PersistentFlags().String("workflows-path", github.DefaultWorkflowsPath, "Directory workflow files are read from (e.g. .gitea/workflows)")
+	rootCmd.PersistentFlags().StringArray("scan-path", nil, "Directory searched for composite action.yml files to scan along with workflows (e.g. .github/actions)")
+	rootCmd.PersistentFlags().String("source", "files", "Workflow discovery source: files (repository contents) or runs (recent workflow runs)")
+	rootCmd.PersistentFlags().String("since", "30d", "Look-back window for --source runs (e.g. 30d, 2w, 12h)")
+	rootCmd.PersistentFlags().Int("concurrency", 4, "Number of repositories fetched in parallel during organization scans")
+	rootCmd.PersistentFlags().String("checkpoint", checkpoint.DefaultPath, "File recording organization scan progress, removed once the scan completes")
+	rootCmd.PersistentFlags().Bool("resume", false, "Resume an interrupted organization scan from its checkpoint file")
+	rootCmd.PersistentFlags().Bool("skip-scope-check", false, "Don't check that a classic token has the scopes the command needs before scanning")
+	rootCmd.PersistentFlags().Duration("repo-cache-ttl", 0, "Reuse organization repository listings cached within this long (e.g. 1h); 0 to always list repositories")
+	rootCmd.PersistentFlags().String("repo-cache", "", "File caching organization repository listings (default in the user cache directory)")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Stop scanning after this long (e.g. 10m) and report the partial results; 0 for no limit")
+	rootCmd.PersistentFlags().Bool("estimate", false, "Predict the API requests of the scan and check the token's remaining quota, without scanning")
+	rootCmd.PersistentFlags().String("base-url", "", "API base URL for GitHub Enterprise or self-hosted Gitea/Forgejo instances")
+	rootCmd.PersistentFlags().String("proxy", "", "Proxy URL for API requests (default from HTTPS_PROXY)")
+	rootCmd.PersistentFlags().String("ca-bundle", "", "PEM file of CA certificates to trust in addition to the system ones, e.g. of a TLS-intercepting proxy")
+	rootCmd.PersistentFlags().Duration("http-timeout", 0, "Timeout of each API request attempt (e.g. 30s); 0 for none")
+	rootCmd.PersistentFlags().Int("http-retries", 0, "Retries of API requests that failed or hit a server error")
+	rootCmd.PersistentFlags().Duration("http-retry-backoff", time.Second, "Delay before the first retry of an API request, doubled for each further retry")
 
 	// Configure command-specific flags
-	enforceCmd.Flags().String("policy", "policy.yaml", "Path to policy configuration file")
+	reportCmd.Flags().Int("history", 0, "Sample workflow history monthly over this many months and chart action adoption (html output)")
+	reportCmd.Flags().Bool("pinning", false, "Classify action references as SHA, tag, branch or unpinned instead of listing usage")
+	reportCmd.Flags().Bool("runtimes", false, "Classify third-party actions as Docker, JavaScript or composite from their action.yml instead of listing usage")
+	reportCmd.Flags().Bool("stats", false, "Report anonymous aggregate statistics without repository names instead of listing usage, to share as a benchmark")
+	reportCmd.Flags().Bool("call-graph", false, "Map which workflows call which reusable workflows instead of listing usage")
+	reportCmd.Flags().Int("history-top", 15, "Number of most used actions to include in the adoption heatmap")
+	reportCmd.Flags().String("policy", "", "Path to a policy file the dashboard output counts violations by repository against")
+	reportCmd.Flags().String("dashboard-history", "", "JSON file the dashboard output records each run's summary in, to chart trends across runs")
+
+	enforceCmd.Flags().StringArray("policy", []string{"policy.yaml"}, "Path to policy configuration file, or directory of them; repeat to combine several files in order")
+	enforceCmd.Flags().String("policy-content", "", "Policy YAML to enforce instead of a policy file, plain or prefixed with 'base64:' (default from ACTION_CONTROL_POLICY_CONTENT)")
 	enforceCmd.Flags().Bool("ignore-local-policy", false, "Ignore local policy files and only use provided policy")
 	enforceCmd.Flags().MarkHidden("ignore-local-policy") // Hidden flag for internal use
+	enforceCmd.Flags().Bool("verify-pins", false, "Verify that SHA pins with a version comment still match the tag they name")
+	enforceCmd.Flags().Bool("lint", false, "Run actionlint on workflow files and include its findings in the report")
+	enforceCmd.Flags().Bool("annotations", os.Getenv("GITHUB_ACTIONS") == "true", "Emit GitHub Actions ::error workflow commands for violations on stderr (default when running in GitHub Actions)")
+	enforceCmd.Flags().Int("pull-request", 0, "Evaluate only the workflow files this pull request of --repo changes")
+	enforceCmd.Flags().Bool("status-check", false, "Set the "+statusContext+" commit status of the --pull-request head commit, for branch protection to require")
+	enforceCmd.Flags().Bool("with-report", false, "Include the action usage report from the same scan in the output")
+	enforceCmd.Flags().String("cache-file", "", "Reuse the verdict stored in this file when the repository's .github directory and policy are unchanged (--repo only)")
+	enforceCmd.Flags().Bool("explain", false, "Report which rule allowed or denied each action: global or custom rule, allow or deny list, and the entry matched")
+	enforceCmd.Flags().Int("max-violations", 0, "Number of findings tolerated before enforce fails, to roll out a policy gradually")
+	enforceCmd.Flags().String("fail-on-severity", "error", "Lowest severity of findings that count towards failing: error, warning (deprecation warnings too) or none")
+	enforceCmd.Flags().Bool("step-outputs", os.Getenv("GITHUB_OUTPUT") != "", "Write violations_count, compliant and report_json to the GitHub Actions step outputs (default when running in GitHub Actions)")
+	enforceCmd.Flags().String("group-violations-by", formatter.GroupByRepo, "Layout of the policy violations in the markdown report: repo, action to list each violating action once with the repositories using it, or team to list the repositories of each owning team")
+	enforceCmd.Flags().Bool("summary-only", false, "Print one line per repository, compliant or its number of findings, instead of the full report")
+	enforceCmd.Flags().Bool("org-policy", false, "Discover the central policy at <org>/.github:action-control-policy.yaml, falling back to --policy when absent (default when neither --policy is given nor policy.yaml exists)")
+
+	enforceCmd.Flags().String("input", "", "Evaluate the scan file written by the scan command instead of scanning through the API")
+	enforceCmd.Flags().StringSlice("notify", nil, "Notification channels to send the outcome to: email or jira, configured under notifications in the config file")
+	enforceCmd.Flags().String("owners-file", "", "Owners mapping file associating repositories with owning teams, and owners with email addresses, to report and route notifications by")
+	enforceCmd.Flags().Bool("owners-from-teams", false, "Resolve the owners of repositories the owners file does not list from the teams with the highest permission on them")
+	enforceCmd.Flags().Bool("require-fine-grained", false, "Fail unless the token is a fine-grained personal access token or GitHub App token, for organizations mandating least-privilege credentials")
+
+	scanCmd.Flags().String("output", "scan.json", "Scan file to write, or - for standard output")
 
 	exportCmd.Flags().String("file", "policy.yaml", "Output file path for generated policy")
 	exportCmd.Flags().Bool("include-versions", false, "Include version tags in action references")
 	exportCmd.Flags().Bool("include-custom", false, "Generate custom rules for each repository")
 	exportCmd.Flags().String("policy-mode", "allow", "Policy mode: allow or deny")
+	exportCmd.Flags().String("from-violations", "", "Export only actions violating the given policy file (deny mode by default)")
+	exportCmd.Flags().Bool("merge", false, "Add newly discovered actions to the existing policy file instead of overwriting it")
+
+	historyCmd.Flags().Int("limit", 20, "Number of most recent runs to list; 0 for all")
+	serveCmd.Flags().String("listen", "127.0.0.1:8080", "Address the REST API listens on")
+	serveCmd.Flags().Bool("insecure-no-auth", false, "Serve the API without a serve_token, letting anyone who can reach it start scans and read their findings")
+	impactCmd.Flags().StringSlice("label", nil, "Retiring runner label, optionally with a replacement (label=replacement)")
+
+	reviewCmd.Flags().String("policy", "policy.yaml", "Path to the policy file to review and update")
+
+	syncOrgSettingsCmd.Flags().String("policy", "policy.yaml", "Path to the allow-mode policy file that is the source of truth")
+	syncOrgSettingsCmd.Flags().Bool("dry-run", true, "Preview the settings that would reconcile the organization with the policy (default unless --apply)")
+	syncOrgSettingsCmd.Flags().Bool("apply", false, "Update the organization's allowed actions settings to match the policy")
+
+	policyTestCmd.Flags().StringArray("policy", []string{"policy.yaml"}, "Path to the policy file under test, or directory of them; repeat to combine several files in order")
+	policyTestCmd.Flags().String("tests", policytest.DefaultPath, "Path to the YAML test cases")
+	policyTestCmd.Flags().Bool("generate", false, "Write a starter test file from the policy instead of running tests")
+
+	policyExplainCmd.Flags().StringArray("policy", []string{"policy.yaml"}, "Path to the policy file, or directory of them; repeat to combine several files in order")
+	policyExplainCmd.Flags().StringSlice("action", nil, "Action reference to explain (e.g. actions/checkout@v4), can be repeated")
+	policyExplainCmd.Flags().String("repo-policy", "", "Path to a repository policy file to merge, as enforce does with .github/action-control-policy.yaml")
+	policyExplainCmd.Flags().String("workflow", "", "Path of the workflow file referencing the actions, for custom rules scoped to workflow paths (e.g. .github/workflows/deploy.yml)")
+	policyMigrateCmd.Flags().StringArray("policy", []string{"policy.yaml"}, "Path to the policy file to upgrade in place, or directory of them; repeatable")
+	policyMigrateCmd.Flags().Bool("dry-run", false, "Print the upgraded policies instead of writing them")
+
+	actionsInventoryCmd.Flags().String("policy", "", "Path to a policy file used to evaluate each action's policy status")
+	actionsInventoryCmd.Flags().String("previous", "", "Path to a previous JSON inventory to carry first-seen dates over from")
+	actionsInventoryCmd.Flags().Bool("enrich-metadata", false, "Look up each action repository's archived status, last push, stars and open security advisories")
+
+	selfUpdateCmd.Flags().Bool("check", false, "Only report whether a newer release is available")
+	selfUpdateCmd.Flags().String("version", "", "Install this release (e.g. v1.4.0) instead of the latest, including older ones")
+
+	authLoginCmd.Flags().Bool("with-token", false, "Read the token from standard input instead of prompting")
+
+	configInitCmd.Flags().Bool("force", false, "Overwrite an existing config file")
+	configGetCmd.Flags().Bool("show-secrets", false, "Print secret settings such as github_token instead of redacting them")
+	configViewCmd.Flags().Bool("show-secrets", false, "Print secret settings such as github_token instead of redacting them")
 
 	// Bind flags to viper to enable config file and environment variable usage
-	viper.BindPFlag("organization", rootCmd.PersistentFlags().Lookup("org"))
-	viper.BindPFlag("repository", rootCmd.PersistentFlags().Lookup("repo"))
-	viper.BindPFlag("output_format", rootCmd.PersistentFlags().Lookup("output"))
-	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
-	viper.BindPFlag("policy_file", enforceCmd.Flags().Lookup("policy"))
-	viper.BindPFlag("ignore_local_policy", enforceCmd.Flags().Lookup("ignore-local-policy"))
-	viper.BindPFlag("export_file", exportCmd.Flags().Lookup("file"))
-	viper.BindPFlag("include_versions", exportCmd.Flags().Lookup("include-versions"))
-	viper.BindPFlag("include_custom", exportCmd.Flags().Lookup("include-custom"))
-	viper.BindPFlag("policy_mode", exportCmd.Flags().Lookup("policy-mode"))
+	bindFlag("organization", rootCmd.PersistentFlags().Lookup("org"))
+	bindFlag("repository", rootCmd.PersistentFlags().Lookup("repo"))
+	bindFlag("output_format", rootCmd.PersistentFlags().Lookup("output"))
+	bindFlag("output_files", rootCmd.PersistentFlags().Lookup("output-file"))
+	bindFlag("template", rootCmd.PersistentFlags().Lookup("template"))
+	bindFlag("config", rootCmd.PersistentFlags().Lookup("config"))
+	bindFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	bindFlag("progress_format", rootCmd.PersistentFlags().Lookup("progress-format"))
+	bindFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+	bindFlag("provider", rootCmd.PersistentFlags().Lookup("provider"))
+	bindFlag("base_url", rootCmd.PersistentFlags().Lookup("base-url"))
+	bindFlag("fixture", rootCmd.PersistentFlags().Lookup("fixture"))
+	bindFlag("store", rootCmd.PersistentFlags().Lookup("store"))
+	bindFlag("history_limit", historyCmd.Flags().Lookup("limit"))
+	bindFlag("serve_listen", serveCmd.Flags().Lookup("listen"))
+	bindFlag("serve_insecure_no_auth", serveCmd.Flags().Lookup("insecure-no-auth"))
+	bindFlag("record", rootCmd.PersistentFlags().Lookup("record"))
+	bindFlag("redact", rootCmd.PersistentFlags().Lookup("redact"))
+	bindFlag("proxy_url", rootCmd.PersistentFlags().Lookup("proxy"))
+	bindFlag("ca_bundle", rootCmd.PersistentFlags().Lookup("ca-bundle"))
+	bindFlag("http_timeout", rootCmd.PersistentFlags().Lookup("http-timeout"))
+	bindFlag("http_retries", rootCmd.PersistentFlags().Lookup("http-retries"))
+	bindFlag("http_retry_backoff", rootCmd.PersistentFlags().Lookup("http-retry-backoff"))
+	bindFlag("hardened_parsing", rootCmd.PersistentFlags().Lookup("hardened"))
+	bindFlag("branches", rootCmd.PersistentFlags().Lookup("branches"))
+	bindFlag("all_branches", rootCmd.PersistentFlags().Lookup("all-branches"))
+	bindFlag("workflows_path", rootCmd.PersistentFlags().Lookup("workflows-path"))
+	bindFlag("scan_paths", rootCmd.PersistentFlags().Lookup("scan-path"))
+	bindFlag("source", rootCmd.PersistentFlags().Lookup("source"))
+	bindFlag("concurrency", rootCmd.PersistentFlags().Lookup("concurrency"))
+	bindFlag("checkpoint", rootCmd.PersistentFlags().Lookup("checkpoint"))
+	bindFlag("resume", rootCmd.PersistentFlags().Lookup("resume"))
+	bindFlag("skip_scope_check", rootCmd.PersistentFlags().Lookup("skip-scope-check"))
+	bindFlag("repo_cache_ttl", rootCmd.PersistentFlags().Lookup("repo-cache-ttl"))
+	bindFlag("repo_cache", rootCmd.PersistentFlags().Lookup("repo-cache"))
+	bindFlag("since", rootCmd.PersistentFlags().Lookup("since"))
+	bindFlag("estimate", rootCmd.PersistentFlags().Lookup("estimate"))
+	bindFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	bindFlag("history_months", reportCmd.Flags().Lookup("history"))
+	bindFlag("history_top", reportCmd.Flags().Lookup("history-top"))
+	bindFlag("pinning", reportCmd.Flags().Lookup("pinning"))
+	bindFlag("runtimes", reportCmd.Flags().Lookup("runtimes"))
+	bindFlag("call_graph", reportCmd.Flags().Lookup("call-graph"))
+	bindFlag("stats", reportCmd.Flags().Lookup("stats"))
+	bindFlag("report_policy_file", reportCmd.Flags().Lookup("policy"))
+	bindFlag("dashboard_history", reportCmd.Flags().Lookup("dashboard-history"))
+	bindFlag("policy_file", enforceCmd.Flags().Lookup("policy"))
+	bindFlag("policy_content", enforceCmd.Flags().Lookup("policy-content"))
+	bindFlag("ignore_local_policy", enforceCmd.Flags().Lookup("ignore-local-policy"))
+	bindFlag("lint", enforceCmd.Flags().Lookup("lint"))
+	bindFlag("verify_pins", enforceCmd.Flags().Lookup("verify-pins"))
+	bindFlag("org_policy", enforceCmd.Flags().Lookup("org-policy"))
+	bindFlag("cache_file", enforceCmd.Flags().Lookup("cache-file"))
+	bindFlag("with_report", enforceCmd.Flags().Lookup("with-report"))
+	bindFlag("annotations", enforceCmd.Flags().Lookup("annotations"))
+	bindFlag("pull_request", enforceCmd.Flags().Lookup("pull-request"))
+	bindFlag("status_check", enforceCmd.Flags().Lookup("status-check"))
+	bindFlag("explain", enforceCmd.Flags().Lookup("explain"))
+	bindFlag("summary_only", enforceCmd.Flags().Lookup("summary-only"))
+	bindFlag("group_violations_by", enforceCmd.Flags().Lookup("group-violations-by"))
+	bindFlag("step_outputs", enforceCmd.Flags().Lookup("step-outputs"))
+	bindFlag("max_violations", enforceCmd.Flags().Lookup("max-violations"))
+	bindFlag("fail_on_severity", enforceCmd.Flags().Lookup("fail-on-severity"))
+	bindFlag("input", enforceCmd.Flags().Lookup("input"))
+	bindFlag("notify", enforceCmd.Flags().Lookup("notify"))
+	bindFlag("owners_file", enforceCmd.Flags().Lookup("owners-file"))
+	bindFlag("owners_from_teams", enforceCmd.Flags().Lookup("owners-from-teams"))
+	bindFlag("require_fine_grained", enforceCmd.Flags().Lookup("require-fine-grained"))
+	bindFlag("scan_output", scanCmd.Flags().Lookup("output"))
+	bindFlag("export_file", exportCmd.Flags().Lookup("file"))
+	bindFlag("include_versions", exportCmd.Flags().Lookup("include-versions"))
+	bindFlag("include_custom", exportCmd.Flags().Lookup("include-custom"))
+	bindFlag("policy_mode", exportCmd.Flags().Lookup("policy-mode"))
+	bindFlag("from_violations", exportCmd.Flags().Lookup("from-violations"))
+	bindFlag("export_merge", exportCmd.Flags().Lookup("merge"))
+	bindFlag("deprecated_labels", impactCmd.Flags().Lookup("label"))
+	bindFlag("review_policy_file", reviewCmd.Flags().Lookup("policy"))
+	bindFlag("sync_policy_file", syncOrgSettingsCmd.Flags().Lookup("policy"))
+	bindFlag("sync_dry_run", syncOrgSettingsCmd.Flags().Lookup("dry-run"))
+	bindFlag("sync_apply", syncOrgSettingsCmd.Flags().Lookup("apply"))
+	bindFlag("policy_test_policy_file", policyTestCmd.Flags().Lookup("policy"))
+	bindFlag("policy_test_file", policyTestCmd.Flags().Lookup("tests"))
+	bindFlag("policy_test_generate", policyTestCmd.Flags().Lookup("generate"))
+	bindFlag("policy_explain_policy_file", policyExplainCmd.Flags().Lookup("policy"))
+	bindFlag("policy_explain_actions", policyExplainCmd.Flags().Lookup("action"))
+	bindFlag("policy_explain_repo_policy_file", policyExplainCmd.Flags().Lookup("repo-policy"))
+	bindFlag("policy_explain_workflow", policyExplainCmd.Flags().Lookup("workflow"))
+	bindFlag("policy_migrate_policy_file", policyMigrateCmd.Flags().Lookup("policy"))
+	bindFlag("policy_migrate_dry_run", policyMigrateCmd.Flags().Lookup("dry-run"))
+	bindFlag("self_update_check", selfUpdateCmd.Flags().Lookup("check"))
+	bindFlag("self_update_version", selfUpdateCmd.Flags().Lookup("version"))
+	bindFlag("inventory_policy_file", actionsInventoryCmd.Flags().Lookup("policy"))
+	bindFlag("inventory_previous", actionsInventoryCmd.Flags().Lookup("previous"))
+	bindFlag("inventory_enrich_metadata", actionsInventoryCmd.Flags().Lookup("enrich-metadata"))
 
 	// Add subcommands to root command
 	rootCmd.AddCommand(reportCmd)
 	rootCmd.AddCommand(enforceCmd)
+	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(actionEntrypointCmd)
 	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(impactCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(reviewCmd)
+	rootCmd.AddCommand(syncOrgSettingsCmd)
+	policyCmd.AddCommand(policyTestCmd, policyExplainCmd, policyMigrateCmd)
+	rootCmd.AddCommand(policyCmd)
+	actionsCmd.AddCommand(actionsInventoryCmd)
+	rootCmd.AddCommand(actionsCmd)
+	rootCmd.AddCommand(selfUpdateCmd)
+	configCmd.AddCommand(configInitCmd, configGetCmd, configSetCmd, configViewCmd, configEnvCmd)
+	rootCmd.AddCommand(configCmd)
+	authCmd.AddCommand(authLoginCmd, authLogoutCmd, authStatusCmd)
+	rootCmd.AddCommand(authCmd)
 
 	// Execute command
 	if err := rootCmd.Execute(); err != nil {
 		log.Println(err)
-		os.Exit(1)
+		exit(1)
+	}
+	saveRecording()
+}
+
+// recorder captures the API responses of the scan with --record; nil when not recording
+var recorder *github.Recorder
+
+// saveRecording writes the API responses captured with --record to the fixture file
+func saveRecording() {
+	if recorder == nil {
+		return
+	}
+	path := viper.GetString("record")
+	if err := recorder.Save(path); err != nil {
+		log.Printf("Warning: Could not save recorded fixture: %v", err)
+		return
 	}
+	log.Printf("Recorded API responses to %s, replay them with --provider fixture --fixture %s", path, path)
+}
+
+// exit saves any recorded fixture and exits with the status code
+func exit(code int) {
+	saveRecording()
+	os.Exit(code)
 }
 
 func runReport() {
 	// Validate GitHub token
-	token := viper.GetString("github_token")
-	if token == "" {
-		log.Fatal("GitHub token not provided. Set it in config.yaml or as GITHUB_TOKEN environment variable.")
-	}
+	token := resolveToken()
 
 	// Get target organization or repository
 	org := viper.GetString("organization")
@@ -107,15 +547,34 @@ func runReport() {
 		log.Fatal("Either organization (--org) or specific repository (--repo) must be provided.")
 	}
 
-	// Set default output format if not specified
-	outputFormat := viper.GetString("output_format")
-	if outputFormat == "" {
-		outputFormat = "markdown"
+	// Pair output formats with their files, defaulting to markdown, and check a custom
+	// template can be read before scanning
+	targets := outputTargets("markdown")
+	outputTemplate()
+
+	// Count violations by repository on the dashboard when a policy is provided
+	var dashboardPolicy *policy.PolicyConfig
+	if policyFile := viper.GetString("report_policy_file"); policyFile != "" {
+		var err error
+		dashboardPolicy, err = policy.LoadPolicyConfig(policyFile)
+		if err != nil {
+			log.Fatalf("Error loading policy file: %v", err)
+		}
 	}
 
 	// Initialize GitHub API client
-	client := github.NewClient(token)
-	ctx := context.Background()
+	client := newClient(token)
+	ctx, cancel := commandContext()
+	defer cancel()
+	checkTokenScopes(ctx, client, "report")
+	exitWithEstimate(ctx, client, org, specificRepo, 0)
+	setupRedaction(ctx, client, org, specificRepo)
+
+	// Report anonymous statistics instead of listing usage
+	if viper.GetBool("stats") {
+		runReportStats(ctx, client, org, specificRepo, targets)
+		return
+	}
 
 	// Map to store discovered actions by repository
 	githubActionsMap := make(map[string][]github.Action)
@@ -130,7 +589,7 @@ func runReport() {
 		}
 		owner, repo := parts[0], parts[1]
 
-		fmt.Printf("Scanning repository %s...\n", specificRepo)
+		announce("Scanning repository %s...\n", specificRepo)
 		actions, err := client.GetActions(ctx, owner, repo)
 		if err != nil {
 			log.Fatalf("Error retrieving actions from repository %s: %v", specificRepo, err)
@@ -140,113 +599,333 @@ func runReport() {
 		}
 	} else {
 		// Scan an entire organization
-		fmt.Printf("Scanning repositories in %s organization...\n", org)
+		announce("Scanning repositories in %s organization...\n", org)
+		checkpointOrgScan(client, org)
 		githubActionsMap, err = client.ActionsForOrg(ctx, org)
-		if err != nil {
+		if scanInterrupted(err) {
+			defer exit(1) // Signal the interruption once the partial results are reported
+		} else if err != nil {
 			log.Fatalf("Error retrieving actions: %v", err)
 		}
 	}
 
+	// Report how action references are pinned instead of listing usage
+	if viper.GetBool("pinning") {
+		pinningReport := pinning.Analyze(githubActionsMap)
+		render := func(format string) (string, error) {
+			switch format {
+			case "json":
+				return formatter.FormatJSON(pinningReport)
+			case "markdown":
+				return formatter.FormatPinning(pinningReport), nil
+			case "template":
+				return renderTemplate(pinningReport)
+			}
+			return "", fmt.Errorf("unsupported output format for pinning report: %s", format)
+		}
+		summary := fmt.Sprintf("Classified %d action references across %d repositories.", pinningReport.Summary.Total(), len(githubActionsMap))
+		fmt.Println(writeOutputs(targets, render, summary))
+		return
+	}
+
+	// Report how third-party actions run instead of listing usage
+	if viper.GetBool("runtimes") {
+		runtimeReport := runtimes.Classify(ctx, githubActionsMap, client)
+		render := func(format string) (string, error) {
+			switch format {
+			case "json":
+				return formatter.FormatJSON(runtimeReport)
+			case "markdown":
+				return formatter.FormatRuntimeReport(runtimeReport), nil
+			case "template":
+				return renderTemplate(runtimeReport)
+			}
+			return "", fmt.Errorf("unsupported output format for runtime report: %s", format)
+		}
+		summary := fmt.Sprintf("Classified %d third-party actions across %d repositories.", len(runtimeReport.Actions), len(githubActionsMap))
+		fmt.Println(writeOutputs(targets, render, summary))
+		return
+	}
+
+	// Report reusable workflow calls instead of listing usage
+	if viper.GetBool("call_graph") {
+		graph := callgraph.Build(githubActionsMap)
+		render := func(format string) (string, error) {
+			switch format {
+			case "json":
+				return formatter.FormatJSON(graph)
+			case "markdown":
+				return formatter.FormatCallGraph(graph), nil
+			case "template":
+				return renderTemplate(graph)
+			}
+			return "", fmt.Errorf("unsupported output format for call graph: %s", format)
+		}
+		summary := fmt.Sprintf("Found %d calls to %d reusable workflows across %d repositories.", len(graph.Edges), len(graph.Callees), len(githubActionsMap))
+		fmt.Println(writeOutputs(targets, render, summary))
+		return
+	}
+
 	// Convert GitHub actions to formatter-compatible structure
-	actionsMap := make(map[string][]formatter.Action)
-	for repo, actions := range githubActionsMap {
-		formatterActions := make([]formatter.Action, len(actions))
-		for i, action := range actions {
-			formatterActions[i] = formatter.Action{
-				Name: action.Name,
-				Uses: action.Uses,
+	actionsMap := usageActions(githubActionsMap)
+
+	// Summarize the scan for the dashboard, charting the trend of the summaries recorded in
+	// the history file by earlier runs
+	var summaryDashboard *dashboard.Dashboard
+	if hasOutputFormat("dashboard") {
+		title := org
+		if title == "" {
+			title = specificRepo
+		}
+		summaryDashboard = dashboard.Build(title+" GitHub Actions", githubActionsMap, dashboardPolicy, time.Now())
+		if historyFile := viper.GetString("dashboard_history"); historyFile != "" {
+			points, err := dashboard.LoadHistory(historyFile)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			summaryDashboard.Trend = dashboard.Record(points, summaryDashboard.Point())
+			if err := dashboard.SaveHistory(historyFile, summaryDashboard.Trend); err != nil {
+				log.Printf("Warning: Could not save dashboard history: %v", err)
 			}
 		}
-		actionsMap[repo] = formatterActions
 	}
 
 	// Format and output the results
-	var result string
-	switch outputFormat {
-	case "json":
-		jsonData, err := formatter.FormatJSON(actionsMap)
-		if err != nil {
-			log.Fatalf("Error formatting JSON: %v", err)
+	render := func(format string) (string, error) {
+		switch format {
+		case "json":
+			return formatter.FormatJSON(actionsMap)
+		case "markdown":
+			return formatter.FormatMarkdown(actionsMap), nil
+		case "template":
+			return renderTemplate(formatter.UsageTemplateData{Usage: actionsMap})
+		case "html":
+			// Sample workflow history to chart adoption over time
+			var heatmap *history.Heatmap
+			if months := viper.GetInt("history_months"); months > 0 {
+				repos := make([]string, 0, len(githubActionsMap))
+				for repo := range githubActionsMap {
+					repos = append(repos, repo)
+				}
+				sort.Strings(repos)
+
+				log.Printf("Sampling workflow history over %d months...", months)
+				heatmap = history.Build(ctx, client, repos, history.MonthlySamples(time.Now(), months))
+				heatmap.Top(viper.GetInt("history_top"))
+			}
+
+			return formatter.FormatHTML(actionsMap, heatmap)
+		case "dashboard":
+			return formatter.FormatDashboard(summaryDashboard)
 		}
-		result = jsonData
-	case "markdown":
-		result = formatter.FormatMarkdown(actionsMap)
-	default:
-		log.Fatalf("Unsupported output format: %s", outputFormat)
+		return "", fmt.Errorf("unsupported output format: %s", format)
 	}
 
-	fmt.Println(result)
+	references := 0
+	for _, actions := range actionsMap {
+		references += len(actions)
+	}
+	summary := fmt.Sprintf("Found %d action references across %d repositories.", references, len(actionsMap))
+	fmt.Println(writeOutputs(targets, render, summary))
 }
 
 func runEnforce() {
-	// Validate GitHub token
-	token := viper.GetString("github_token")
-	if token == "" {
-		log.Fatal("GitHub token not provided. Set it in config.yaml or as GITHUB_TOKEN environment variable.")
-	}
+	startedAt := time.Now()
 
 	// Get target organization or repository
 	org := viper.GetString("organization")
 	specificRepo := viper.GetString("repository")
 
+	// Evaluate a scan file instead of scanning, defaulting to the scanned target
+	var scan *snapshot.Snapshot
+	if input := viper.GetString("input"); input != "" {
+		var err error
+		scan, err = snapshot.Load(input)
+		if err != nil {
+			log.Fatalf("Error loading scan file: %v", err)
+		}
+		if org != "" && scan.Organization != "" && !strings.EqualFold(org, scan.Organization) {
+			log.Fatalf("Scan file %s covers organization %s, not %s", input, scan.Organization, org)
+		}
+		if org == "" && specificRepo == "" {
+			org, specificRepo = scan.Organization, scan.Repository
+		}
+		if specificRepo != "" && scan.Repositories[specificRepo] == nil && scan.Repository != specificRepo {
+			log.Fatalf("Scan file %s does not cover repository %s", input, specificRepo)
+		}
+		log.Printf("Evaluating scan file %s from %s", input, scan.ScannedAt.Format(time.RFC3339))
+	}
+
 	// At least one target must be specified
 	if org == "" && specificRepo == "" {
 		log.Fatal("Either organization (--org) or specific repository (--repo) must be provided.")
 	}
 
-	// Determine policy source: environment variable or file
-	policyContent := os.Getenv("ACTION_CONTROL_POLICY_CONTENT")
+	// Check output formats, a custom template and the failure threshold before scanning
+	outputTargets("markdown")
+	outputTemplate()
+	switch severity := viper.GetString("fail_on_severity"); severity {
+	case failOnError, failOnWarning, failOnNone:
+	default:
+		log.Fatalf("Unsupported severity for --fail-on-severity: %s", severity)
+	}
+	if viper.GetInt("max_violations") < 0 {
+		log.Fatal("--max-violations cannot be negative")
+	}
+	switch groupBy := viper.GetString("group_violations_by"); groupBy {
+	case formatter.GroupByRepo, formatter.GroupByAction:
+	case formatter.GroupByTeam:
+		if !ownersConfigured() {
+			log.Printf("Warning: --group-violations-by team without --owners-file or --owners-from-teams lists every repository as unowned")
+		}
+	default:
+		log.Fatalf("Unsupported grouping for --group-violations-by: %s, must be 'repo', 'action' or 'team'", groupBy)
+	}
+	if _, err := ownersMapping(); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	checkNotifications()
+	pullRequest := viper.GetInt("pull_request")
+	switch {
+	case pullRequest < 0:
+		log.Fatal("--pull-request must be a pull request number")
+	case pullRequest > 0 && (specificRepo == "" || scan != nil):
+		log.Fatal("--pull-request evaluates a pull request of the repository given with --repo, not a scan file")
+	case viper.GetBool("status_check") && pullRequest == 0:
+		log.Fatal("--status-check sets the status of the pull request given with --pull-request")
+	}
+
+	// Initialize GitHub API client, or serve the scan file's repositories without one
+	var client *github.Client
+	var token string
+	if scan != nil {
+		client = github.NewFixtureClient(scan.Fixture())
+	} else {
+		token = resolveToken()
+		client = newClient(token)
+	}
+	ctx, cancel := commandContext()
+	defer cancel()
+	if scan == nil {
+		checkTokenScopes(ctx, client, "enforce")
+		checkLeastPrivilege(ctx, client, token)
+	}
+
+	// Determine policy source: inline content, organization .github repository or file
+	policyContent := viper.GetString("policy_content")
 	ignoreLocalPolicy := viper.GetBool("ignore_local_policy")
 
 	var localPolicy *policy.PolicyConfig
 	var err error
 
-	// Handle policy from environment variable with highest priority when flag is set
-	if policyContent != "" && ignoreLocalPolicy {
-		log.Println("Using policy from environment variable")
-
-		// Create a temporary file for the policy content
-		tmpFile, err := os.CreateTemp("", "policy-*.yaml")
-		if err != nil {
-			log.Fatalf("Error creating temporary policy file: %v", err)
-		}
-		defer os.Remove(tmpFile.Name()) // Clean up after we're done
-
-		// Write content to temporary file
-		if _, err := tmpFile.WriteString(policyContent); err != nil {
-			tmpFile.Close()
-			log.Fatalf("Error writing to temporary policy file: %v", err)
-		}
-		tmpFile.Close()
+	// Resolve the organization that owns the central policy
+	policyOrg := org
+	if policyOrg == "" {
+		policyOrg = strings.Split(specificRepo, "/")[0]
+	}
 
-		// Load policy configuration from temporary file
-		localPolicy, err = policy.LoadPolicyConfig(tmpFile.Name())
+	// Inline policy content, from --policy-content or ACTION_CONTROL_POLICY_CONTENT, takes priority
+	if policyContent != "" {
+		log.Println("Using policy from --policy-content")
+		localPolicy, err = policy.ParsePolicyContent(policyContent)
 		if err != nil {
-			log.Fatalf("Error loading policy from environment variable: %v", err)
+			log.Fatalf("Error loading policy content: %v", err)
 		}
+	} else if orgPolicy := loadOrgPolicy(ctx, client, policyOrg); orgPolicy != nil {
+		localPolicy = orgPolicy
 	} else {
-		// Use policy from file
-		policyFile := viper.GetString("policy_file")
-		if policyFile == "" {
-			policyFile = "policy.yaml"
+		// Use the policy from one or more files, or directories of files, combined in order
+		policyFiles := settingList("policy_file")
+		if len(policyFiles) == 0 {
+			policyFiles = []string{policy.DefaultPolicyFile}
 		}
 
 		// Load policy configuration from file
-		localPolicy, err = policy.LoadPolicyConfig(policyFile)
+		localPolicy, err = policy.LoadPolicyFiles(policyFiles)
 		if err != nil {
 			log.Fatalf("Error loading policy file: %v", err)
 		}
 	}
 
-	// Initialize GitHub API client
-	client := github.NewClient(token)
-	ctx := context.Background()
+	warnUnversionedPolicy(localPolicy)
 
-	// Map to store discovered actions by repository
-	githubActionsMap := make(map[string][]github.Action)
+	// Refuse to evaluate a policy that requires a newer binary
+	if err := policy.CheckToolVersion(localPolicy); err != nil {
+		if localPolicy.ToolVersionCheck == "warn" {
+			log.Printf("Warning: %v", err)
+		} else {
+			log.Fatalf("Error: %v", err)
+		}
+	}
 
-	// Fetch actions from GitHub
-	if specificRepo != "" {
+	if viper.GetBool("owners_from_teams") && scan != nil {
+		log.Fatal("Owning teams cannot be resolved from a scan file, use --owners-file instead")
+	}
+
+	// Resolve custom rules scoped to teams, topics or custom properties
+	if policy.HasScopedRules(localPolicy) && scan != nil {
+		log.Fatal("Custom rules scoped to teams, topics or custom properties cannot be resolved from a scan file")
+	}
+	if policy.HasScopedRules(localPolicy) {
+		scopeOrg := org
+		if scopeOrg == "" {
+			scopeOrg, _, _ = strings.Cut(specificRepo, "/")
+		}
+		if err := policy.ResolveScopes(ctx, localPolicy, client, scopeOrg); err != nil {
+			log.Fatalf("Error resolving custom rules: %v", err)
+		}
+	}
+
+	if scan == nil {
+		exitWithEstimate(ctx, client, org, specificRepo, enforceRequestsPerRepo(localPolicy))
+	}
+
+	// Report the cached verdict when nothing relevant changed since the last run
+	var verdictCache *verdict.Cache
+	var verdictKey string
+	if cacheFile := viper.GetString("cache_file"); cacheFile != "" && scan != nil {
+		log.Printf("Verdict cache does not apply to scan files, evaluating")
+	} else if cacheFile != "" {
+		if key, ok := verdictCacheKey(ctx, client, specificRepo, localPolicy); ok {
+			verdictCache, err = verdict.Load(cacheFile)
+			if err != nil {
+				log.Fatalf("Error loading verdict cache: %v", err)
+			}
+			if entry, hit := verdictCache.Lookup(specificRepo, key); hit {
+				log.Printf("Workflow files and policy unchanged since %s, reporting cached verdict", entry.CreatedAt.Format(time.RFC3339))
+				fmt.Print(entry.Output)
+				if stepOutputsEnabled() {
+					// Only the verdict is cached, not the findings behind it
+					if err := appendStepOutputs(map[string]string{"compliant": strconv.FormatBool(entry.ExitCode == 0)}); err != nil {
+						log.Printf("Warning: Could not write step outputs: %v", err)
+					}
+				}
+				exit(entry.ExitCode)
+			}
+			verdictKey = key
+		}
+	}
+
+	// Evaluate each repository as soon as its workflow files arrive
+	setupRedaction(ctx, client, org, specificRepo)
+	enforcement := newEnforcement(ctx, client, localPolicy, ignoreLocalPolicy)
+	var pull github.PullRequest
+
+	if scan != nil {
+		// Evaluate the scanned repositories, or the one given with --repo
+		enforcement.goOffline()
+		for _, name := range scan.Names() {
+			if specificRepo == "" || name == specificRepo {
+				enforcement.evaluate(name, scan.Repositories[name].WorkflowFiles())
+			}
+		}
+	} else if pullRequest > 0 {
+		// Scan the workflow files a pull request changes
+		if _, _, ok := strings.Cut(specificRepo, "/"); !ok {
+			log.Fatalf("Invalid repository format. Use 'owner/repo' format.")
+		}
+		pull = evaluatePullRequest(ctx, client, enforcement, specificRepo)
+	} else if specificRepo != "" {
 		// Scan a single repository
 		parts := strings.Split(specificRepo, "/")
 		if len(parts) != 2 {
@@ -254,83 +933,483 @@ func runEnforce() {
 		}
 		owner, repo := parts[0], parts[1]
 
-		fmt.Printf("Scanning repository %s and enforcing policy...\n", specificRepo)
-		actions, err := client.GetActions(ctx, owner, repo)
+		announce("Scanning repository %s and enforcing policy...\n", specificRepo)
+		files, err := client.GetWorkflowFiles(ctx, owner, repo)
 		if err != nil {
 			log.Fatalf("Error retrieving actions from repository %s: %v", specificRepo, err)
 		}
-		if len(actions) > 0 {
-			githubActionsMap[specificRepo] = actions
+		if len(files) > 0 {
+			enforcement.evaluate(specificRepo, files)
 		}
 	} else {
-		// Scan an entire organization
-		fmt.Printf("Scanning repositories in %s organization and enforcing policy...\n", org)
-		githubActionsMap, err = client.ActionsForOrg(ctx, org)
-		if err != nil {
+		// Scan an entire organization, fetching repositories in parallel
+		announce("Scanning repositories in %s organization and enforcing policy...\n", org)
+		checkpointOrgScan(client, org)
+		err = client.StreamWorkflowFilesForOrg(ctx, org, func(repo string, files []github.WorkflowFile) error {
+			enforcement.evaluate(repo, files)
+			return nil
+		})
+		if scanInterrupted(err) {
+			enforcement.report.Interrupted = err.Error()
+		} else if err != nil {
 			log.Fatalf("Error retrieving actions: %v", err)
 		}
 	}
 
-	// Track policy violations found
-	violations := make(map[string][]string)
+	// Record the owners of each repository, to report and route notifications by
+	resolveOwners(ctx, client, enforcement)
 
-	// Check each repository against policy
-	for repoFullName, actions := range githubActionsMap {
-		// Extract owner and repo name
-		parts := strings.Split(repoFullName, "/")
-		if len(parts) != 2 {
-			continue
-		}
-		owner := parts[0]
-		repoName := parts[1]
-
-		// Use local policy as base
-		var repoPolicy *policy.PolicyConfig
-		repoPolicy = localPolicy
-
-		// Check for repository-specific policy if not ignoring local policies
-		if !ignoreLocalPolicy {
-			repoPolicyContent, err := client.GetRepositoryContent(ctx, owner, repoName, ".github/action-control-policy.yaml")
-			if err == nil && len(repoPolicyContent) > 0 {
-				// Merge repository policy with local policy
-				repoPolicy, err = policy.MergeRepoPolicy(localPolicy, repoPolicyContent, repoFullName)
-				if err != nil {
-					log.Printf("Warning: Could not parse policy file in repository %s: %v", repoFullName, err)
-					// Fall back to local policy on error
-					repoPolicy = localPolicy
-				}
-			}
+	// Annotate violations inline in the workflow run and pull request diff
+	formatter.SortViolations(enforcement.report.Violations)
+	if viper.GetBool("annotations") {
+		fmt.Fprint(os.Stderr, formatter.FormatAnnotations(enforcement.report.Violations, enforcement.imageViolations))
+		fmt.Fprint(os.Stderr, formatter.FormatDeprecationAnnotations(enforcement.deprecations, deprecations.Failing(enforcement.policy)))
+	}
+
+	// Generate and print or write the reports, keeping the console concise when all go to files
+	// or when only a digest is wanted
+	output := writeOutputs(outputTargets("markdown"), enforcement.render, enforcement.summary())
+	if viper.GetBool("summary_only") {
+		output = redactor.Apply(formatter.FormatSummaryOnly(enforcement.report) + enforcement.summary())
+	}
+	fmt.Print(output)
+
+	// Let later workflow steps branch on the outcome or upload the report
+	if stepOutputsEnabled() {
+		if err := writeStepOutputs(enforcement); err != nil {
+			log.Printf("Warning: Could not write step outputs: %v", err)
+		}
+	}
+
+	// Send the outcome to repository owners and the configured recipients
+	target := org
+	if specificRepo != "" {
+		target = specificRepo
+	}
+	if pullRequest > 0 {
+		// Pass or fail the pull request's status check. The outcome of its changes is not the
+		// repository's, so neither notified nor recorded.
+		state, description := pullRequestStatus(enforcement)
+		setStatus(ctx, client, specificRepo, pull, state, description)
+	} else {
+		sendNotifications(ctx, client, enforcement, target)
+
+		// Keep the outcome in the history of runs
+		recordRun(ctx, target, enforcement, startedAt)
+	}
+
+	// Exit with exitFindings if the findings fail the policy, and 1 if the scan was cut short
+	exitCode := 0
+	if enforcement.report.Interrupted != "" {
+		exitCode = 1
+	} else if enforcement.failed() {
+		exitCode = exitFindings
+	}
+
+	// Remember the verdict for the next run, unless the scan was cut short
+	if verdictCache != nil && enforcement.report.Interrupted == "" {
+		verdictCache.Store(specificRepo, verdictKey, output, exitCode)
+		if err := verdictCache.Save(viper.GetString("cache_file")); err != nil {
+			log.Printf("Warning: Could not save verdict cache: %v", err)
 		}
+	}
 
-		// Extract action strings for policy check
-		actionStrings := make([]string, len(actions))
+	if exitCode != 0 {
+		exit(exitCode)
+	}
+}
+
+// usageActions converts scanned GitHub actions to the formatter's usage report structure
+func usageActions(githubActionsMap map[string][]github.Action) map[string][]formatter.Action {
+	actionsMap := make(map[string][]formatter.Action)
+	for repo, actions := range githubActionsMap {
+		formatterActions := make([]formatter.Action, len(actions))
 		for i, action := range actions {
-			actionStrings[i] = action.Uses
+			formatterActions[i] = formatter.Action{
+				Name: action.Name,
+				Uses: action.Uses,
+			}
+		}
+		actionsMap[repo] = formatterActions
+	}
+	return actionsMap
+}
+
+// verdictCacheKey derives the verdict cache key for a single repository from the state of its
+// .github directory, the policy and the options affecting the verdict. It returns false when the
+// verdict depends on state outside the repository and must always be evaluated.
+func verdictCacheKey(ctx context.Context, client *github.Client, repo string, config *policy.PolicyConfig) (string, bool) {
+	switch {
+	case repo == "":
+		log.Printf("Verdict cache only applies to single repository scans (--repo), evaluating")
+		return "", false
+	case hasOutputFiles():
+		log.Printf("Verdict cache does not apply when writing output files, evaluating")
+		return "", false
+	case viper.GetString("source") == "runs":
+		log.Printf("Verdict cache does not apply to workflow run discovery, evaluating")
+		return "", false
+	case viper.GetInt("pull_request") > 0:
+		log.Printf("Verdict cache does not apply to pull requests, evaluating")
+		return "", false
+	case viper.GetBool("redact"):
+		log.Printf("Verdict cache does not apply to redacted output, evaluating")
+		return "", false
+	case viper.GetString("workflows_path") != github.DefaultWorkflowsPath || len(settingList("scan_paths")) > 0:
+		log.Printf("Verdict cache only tracks the .github/workflows directory, evaluating")
+		return "", false
+	case viper.GetBool("verify_pins") || viper.GetBool("owners_from_teams") || config.RequireVerifiedCreator || config.RequireActionsUpdates || config.RequireWorkflowProtection || len(config.WorkflowOwners) > 0 || metadata.HasThresholds(config) || deadlinks.Enabled(config) || runtimes.HasRules(config) || deprecations.Failing(config) || policy.HasScopedRules(config) || policy.HasTriggerRequirements(config, repo) || policy.InputRulesNeedVisibility(config, repo):
+		log.Printf("Verdict depends on state outside the repository, evaluating")
+		return "", false
+	}
+
+	owner, name, _ := strings.Cut(repo, "/")
+	treeSHA, err := client.GithubTreeSHA(ctx, owner, name, "HEAD")
+	if err != nil {
+		log.Printf("Warning: Could not read workflow tree for verdict cache: %v", err)
+		return "", false
+	}
+
+	return verdict.Key(
+		version.Version,
+		policy.ConfigDigest(config),
+		treeSHA,
+		strings.Join(settingList("output_format"), ","),
+		outputTemplate(),
+		viper.GetString("branches"),
+		strconv.FormatBool(viper.GetBool("all_branches")),
+		strconv.FormatBool(viper.GetBool("lint")),
+		strconv.FormatBool(viper.GetBool("with_report")),
+		strconv.FormatBool(viper.GetBool("explain")),
+		strconv.FormatBool(viper.GetBool("ignore_local_policy")),
+		strconv.FormatBool(viper.GetBool("summary_only")),
+		viper.GetString("group_violations_by"),
+		ownersFileContent(),
+		strconv.Itoa(viper.GetInt("max_violations")),
+		viper.GetString("fail_on_severity"),
+		time.Now().Format("2006-01-02"), // Ignore annotations expire by date
+	), true
+}
+
+// resolveToken returns the API token from configuration, falling back to the credential store
+func resolveToken() string {
+	// Fixtures are served without a token
+	if strings.EqualFold(viper.GetString("provider"), github.ProviderFixture) {
+		return ""
+	}
+
+	if token := viper.GetString("github_token"); token != "" {
+		return token
+	}
+
+	token, err := credentials.NewStore(credentialHost()).Get()
+	if err != nil {
+		log.Printf("Warning: Could not read token from credential store: %v", err)
+	}
+	if token == "" {
+		log.Fatal("GitHub token not provided. Set it in config.yaml, as GITHUB_TOKEN environment variable, or run 'action-control auth login'.")
+	}
+	return token
+}
+
+// checkTokenScopes fails fast when a classic token lacks the scopes the command needs, rather
+// than with permission errors part way through a scan. The scopes of each command can be
+// overridden with the token_scopes setting. Tokens that don't report scopes, such as
+// fine-grained and GitHub App tokens, and other providers are not checked.
+func checkTokenScopes(ctx context.Context, client *github.Client, command string) {
+	if provider := viper.GetString("provider"); viper.GetBool("skip_scope_check") || (provider != "" && !strings.EqualFold(provider, github.ProviderGitHub)) {
+		return
+	}
+	required := tokenscopes.Required(command, viper.GetStringMapStringSlice("token_scopes"))
+	if len(required) == 0 {
+		return
+	}
+
+	granted, reported, err := client.TokenScopes(ctx)
+	if err != nil {
+		log.Printf("Warning: Could not check token scopes: %v", err)
+		return
+	}
+	if !reported {
+		return
+	}
+
+	if missing := tokenscopes.Missing(granted, required); len(missing) > 0 {
+		descriptions := make([]string, len(missing))
+		for i, requirement := range missing {
+			descriptions[i] = requirement.String()
 		}
+		log.Fatalf("Error: The token is missing scopes needed by %s: %s. Grant them to the token, set token_scopes.%s in the config file to the scopes to require, or pass --skip-scope-check.",
+			command, strings.Join(descriptions, ", "), command)
+	}
+}
 
-		// Check actions against policy
-		repoViolations, compliant := policy.CheckActionCompliance(repoPolicy, repoFullName, actionStrings)
-		if !compliant {
-			violations[repoFullName] = repoViolations
+// checkLeastPrivilege enforces --require-fine-grained, failing unless the token is limited to
+// the permissions and repositories it was granted, and warns when a classic token grants scopes
+// enforce doesn't need, as a token leaking from automation exposes everything it can access
+func checkLeastPrivilege(ctx context.Context, client *github.Client, token string) {
+	requireFineGrained := viper.GetBool("require_fine_grained")
+	provider := viper.GetString("provider")
+	if strings.EqualFold(provider, github.ProviderFixture) {
+		return
+	}
+	if provider != "" && !strings.EqualFold(provider, github.ProviderGitHub) {
+		if requireFineGrained {
+			log.Fatalf("Error: --require-fine-grained checks GitHub tokens, not %s tokens", provider)
 		}
+		return
 	}
 
-	// Generate and print report
-	report := formatter.FormatPolicyViolations(violations, localPolicy.PolicyMode)
-	fmt.Println(report)
+	kind := tokenscopes.Classify(token, false)
+	var granted []string
+	if kind == tokenscopes.KindUnknown || kind.Scoped() {
+		scopes, reported, err := client.TokenScopes(ctx)
+		if err != nil {
+			if requireFineGrained && kind == tokenscopes.KindUnknown {
+				log.Fatalf("Error: Could not determine whether the token is fine-grained: %v", err)
+			}
+			log.Printf("Warning: Could not check token scopes: %v", err)
+		}
+		granted = scopes
+		kind = tokenscopes.Classify(token, reported)
+	}
 
-	// Exit with error code if violations found
-	if len(violations) > 0 {
-		os.Exit(1)
+	if requireFineGrained && !kind.FineGrained() {
+		log.Fatalf("Error: --require-fine-grained is set, but the token is a %s. Use a fine-grained personal access token or a GitHub App installation token with read access to contents and metadata.", kind)
+	}
+	if !kind.Scoped() || viper.GetBool("skip_scope_check") {
+		return
+	}
+
+	required := tokenscopes.Required("enforce", viper.GetStringMapStringSlice("token_scopes"))
+	if len(required) == 0 {
+		return
+	}
+	if viper.GetBool("owners_from_teams") {
+		required = append(required, tokenscopes.Requirement{Scope: "read:org"})
+	}
+	if excessive := tokenscopes.Excessive(granted, required); len(excessive) > 0 {
+		log.Printf("Warning: The %s grants scopes enforce doesn't need: %s. Prefer a fine-grained personal access token or a GitHub App installation token with read access to contents and metadata.",
+			kind, strings.Join(excessive, ", "))
+	}
+}
+
+// credentialHost returns the host that stored credentials are keyed by
+func credentialHost() string {
+	if baseURL := viper.GetString("base_url"); baseURL != "" {
+		if parsed, err := url.Parse(baseURL); err == nil && parsed.Host != "" {
+			return parsed.Host
+		}
+	}
+	return credentials.DefaultHost
+}
+
+// commandContext returns the context of a command, cancelled on an interrupt or termination
+// signal or once --timeout elapses, so scans stop cleanly and report their partial results.
+// A second signal terminates the process immediately.
+func commandContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	cancel := stop
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		cancel = func() {
+			cancelTimeout()
+			stop()
+		}
+	}
+
+	// Restore the default signal handling once cancelled
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, cancel
+}
+
+// scanInterrupted reports whether err stopped an organization scan early, in which case the
+// repositories scanned so far are still reported
+func scanInterrupted(err error) bool {
+	var interrupted *github.ScanInterruptedError
+	if !errors.As(err, &interrupted) {
+		return false
+	}
+	log.Printf("Warning: %v, reporting partial results", err)
+	return true
+}
+
+// httpConfig returns the proxy, CA bundle, timeout and retry settings of API requests
+func httpConfig() github.HTTPConfig {
+	return github.HTTPConfig{
+		ProxyURL:     viper.GetString("proxy_url"),
+		CABundle:     viper.GetString("ca_bundle"),
+		Timeout:      viper.GetDuration("http_timeout"),
+		Retries:      viper.GetInt("http_retries"),
+		RetryBackoff: viper.GetDuration("http_retry_backoff"),
 	}
 }
 
+// newClient initializes the API client for the configured forge provider
+func newClient(token string) *github.Client {
+	var client *github.Client
+	if strings.EqualFold(viper.GetString("provider"), github.ProviderFixture) {
+		// Serve repositories and workflow files from a local bundle
+		path := viper.GetString("fixture")
+		if path == "" {
+			log.Fatal("The fixture provider needs a fixture bundle (--fixture).")
+		}
+		fixture, err := github.LoadFixture(path)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		client = github.NewFixtureClient(fixture)
+	} else {
+		// Capture the API responses into a fixture to replay the scan later
+		config := httpConfig()
+		if viper.GetString("record") != "" {
+			if viper.GetBool("redact") {
+				log.Fatal("--record captures raw API responses, including repository names and descriptions, which --redact cannot redact")
+			}
+			recorder = github.NewRecorder(viper.GetString("organization"))
+			config.Recorder = recorder
+		}
+
+		var err error
+		client, err = github.NewClientForProvider(token, viper.GetString("provider"), viper.GetString("base_url"), config)
+		if err != nil {
+			log.Fatalf("Error initializing client: %v", err)
+		}
+	}
+
+	// Show scan progress on a terminal and timing details when verbose,
+	// or emit NDJSON events for wrapper tooling
+	switch progressFormat := viper.GetString("progress_format"); progressFormat {
+	case "json":
+		client.SetObserver(progress.NewJSON(os.Stderr))
+	case "", "text":
+		interactive := progress.IsTerminal(os.Stderr) && !viper.GetBool("quiet")
+		verbose := viper.GetBool("verbose")
+		if interactive || verbose {
+			client.SetObserver(progress.NewTerminal(os.Stderr, interactive, verbose))
+		}
+	default:
+		log.Fatalf("Unsupported progress format: %s", progressFormat)
+	}
+
+	// Reuse recent organization repository listings
+	if ttl := viper.GetDuration("repo_cache_ttl"); ttl > 0 {
+		path := viper.GetString("repo_cache")
+		if path == "" {
+			var err error
+			if path, err = repocache.DefaultPath(); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+		}
+		scope := repocache.Scope(viper.GetString("provider"), viper.GetString("base_url"), token)
+		client.SetRepositoryCache(repocache.New(path, ttl, scope))
+	}
+
+	// Fetch repositories of organization scans in parallel
+	client.SetConcurrency(viper.GetInt("concurrency"))
+
+	// Scan workflow files on other branches too
+	branches := viper.GetString("branches")
+	if viper.GetBool("all_branches") {
+		branches = "*"
+	}
+	if branches != "" {
+		if err := client.SetBranches(branches); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+
+	// Read workflows from another directory, and composite actions from extra paths
+	if err := client.SetWorkflowsPath(viper.GetString("workflows_path")); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := client.SetScanPaths(settingList("scan_paths")); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	// Discover workflow files from recent runs instead of the repository contents
+	switch source := viper.GetString("source"); source {
+	case "", "files":
+	case "runs":
+		since, err := github.ParseSince(viper.GetString("since"))
+		if err != nil {
+			log.Fatalf("Error: invalid --since: %v", err)
+		}
+		client.SetRunSource(time.Now().Add(-since))
+	default:
+		log.Fatalf("Unsupported workflow source: %s, must be 'files' or 'runs'", source)
+	}
+
+	// Guard against maliciously crafted workflow files
+	if viper.GetBool("hardened_parsing") {
+		github.SetHardenedParsing(&github.DefaultParseLimits)
+	}
+
+	return client
+}
+
+// checkpointOrgScan records the progress of the upcoming organization scan in the checkpoint
+// file, restoring the repositories scanned before an interruption when resuming
+func checkpointOrgScan(client *github.Client, org string) {
+	path := viper.GetString("checkpoint")
+	if path == "" {
+		return
+	}
+
+	// Resuming is only safe when the same workflow files would be scanned
+	scope := strings.Join([]string{
+		viper.GetString("source"),
+		viper.GetString("since"),
+		viper.GetString("branches"),
+		strconv.FormatBool(viper.GetBool("all_branches")),
+		viper.GetString("workflows_path"),
+		strings.Join(settingList("scan_paths"), ":"),
+	}, ",")
+
+	cp, err := checkpoint.Open(path, org, scope, viper.GetBool("resume"))
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if count := cp.Count(); count > 0 {
+		log.Printf("Resuming scan started at %s, %d repositories already scanned", cp.StartedAt().Format(time.RFC3339), count)
+	}
+	client.SetCheckpoint(cp)
+}
+
+// loadOrgPolicy discovers the central policy in the organization's .github repository, with
+// --org-policy, or when neither --org-policy nor --policy is given and there is no local
+// policy.yaml. It returns nil when discovery is disabled or the organization has no central
+// policy; a central policy that can't be read or parsed is fatal.
+func loadOrgPolicy(ctx context.Context, client *github.Client, org string) *policy.PolicyConfig {
+	var requested *bool
+	if viper.IsSet("org_policy") {
+		value := viper.GetBool("org_policy")
+		requested = &value
+	}
+	if org == "" || !policy.DiscoverOrgPolicy(requested, viper.IsSet("policy_file")) {
+		return nil
+	}
+
+	orgPolicy, err := policy.LoadOrgPolicy(ctx, client, org)
+	if err != nil {
+		log.Fatalf("Error loading central policy: %v", err)
+	}
+	if orgPolicy == nil {
+		log.Printf("No central policy found in %s/%s, falling back to local policy", org, policy.OrgPolicyRepo)
+		return nil
+	}
+
+	log.Printf("Using central policy from %s/%s:%s (sha256:%s)", org, policy.OrgPolicyRepo, orgPolicy.Path, orgPolicy.Digest)
+	return orgPolicy.Config
+}
+
 func runExport() {
 	// Validate GitHub token
-	token := viper.GetString("github_token")
-	if token == "" {
-		log.Fatal("GitHub token not provided. Set it in config.yaml or as GITHUB_TOKEN environment variable.")
-	}
+	token := resolveToken()
 
 	// Get target organization or repository
 	org := viper.GetString("organization")
@@ -348,14 +1427,46 @@ func runExport() {
 	exporter.IncludeCustom = viper.GetBool("include_custom")
 	exporter.PolicyMode = viper.GetString("policy_mode")
 
+	// Seed the export from violations of an existing policy
+	var existingPolicy *policy.PolicyConfig
+	if violationsPolicy := viper.GetString("from_violations"); violationsPolicy != "" {
+		var err error
+		existingPolicy, err = policy.LoadPolicyConfig(violationsPolicy)
+		if err != nil {
+			log.Fatalf("Error loading policy file: %v", err)
+		}
+
+		// Violations are exported as a deny list unless a mode was chosen explicitly
+		if !viper.IsSet("policy_mode") {
+			exporter.PolicyMode = "deny"
+		}
+	}
+
+	// Merge into the list of the existing policy file's mode, which an explicit mode must match
+	if viper.GetBool("export_merge") {
+		fileMode, exists, err := exporter.PolicyFileMode()
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if exists && export.ExportMode(fileMode) != exporter.PolicyMode {
+			if viper.IsSet("policy_mode") {
+				log.Fatalf("Error: --policy-mode %s contradicts the %s mode of %s", exporter.PolicyMode, fileMode, exporter.OutputPath)
+			}
+			exporter.PolicyMode = export.ExportMode(fileMode)
+		}
+	}
+
 	// Validate policy mode
 	if exporter.PolicyMode != "allow" && exporter.PolicyMode != "deny" {
 		log.Fatalf("Invalid policy mode: %s, must be 'allow' or 'deny'", exporter.PolicyMode)
 	}
 
 	// Initialize GitHub API client
-	client := github.NewClient(token)
-	ctx := context.Background()
+	client := newClient(token)
+	ctx, cancel := commandContext()
+	defer cancel()
+	checkTokenScopes(ctx, client, "export")
+	exitWithEstimate(ctx, client, org, specificRepo, 0)
 
 	// Map to store discovered actions by repository
 	githubActionsMap := make(map[string][]github.Action)
@@ -370,7 +1481,7 @@ func runExport() {
 		}
 		owner, repo := parts[0], parts[1]
 
-		fmt.Printf("Scanning repository %s for actions...\n", specificRepo)
+		announce("Scanning repository %s for actions...\n", specificRepo)
 		actions, err := client.GetActions(ctx, owner, repo)
 		if err != nil {
 			log.Fatalf("Error retrieving actions from repository %s: %v", specificRepo, err)
@@ -380,19 +1491,41 @@ func runExport() {
 		}
 	} else {
 		// Export from an entire organization
-		fmt.Printf("Scanning repositories in %s organization for actions...\n", org)
+		announce("Scanning repositories in %s organization for actions...\n", org)
+		checkpointOrgScan(client, org)
 		githubActionsMap, err = client.ActionsForOrg(ctx, org)
-		if err != nil {
+		if scanInterrupted(err) {
+			defer exit(1) // Signal the interruption once the partial results are reported
+		} else if err != nil {
 			log.Fatalf("Error retrieving actions: %v", err)
 		}
 	}
 
 	// Generate policy from discovered actions
-	policyConfig, err := exporter.GeneratePolicyFromActions(githubActionsMap)
+	var policyConfig *policy.PolicyConfig
+	if existingPolicy != nil {
+		policyConfig, err = exporter.GeneratePolicyFromViolations(githubActionsMap, existingPolicy)
+	} else {
+		policyConfig, err = exporter.GeneratePolicyFromActions(githubActionsMap)
+	}
 	if err != nil {
 		log.Fatalf("Error generating policy: %v", err)
 	}
 
+	// Merge into the existing policy file, keeping curated edits
+	if viper.GetBool("export_merge") {
+		added, err := exporter.MergePolicyFile(policyConfig)
+		if err != nil {
+			log.Fatalf("Error merging policy file: %v", err)
+		}
+
+		fmt.Printf("Merged %d new entries into %s\n", len(added), exporter.OutputPath)
+		for _, entry := range added {
+			fmt.Printf("  + %s\n", entry)
+		}
+		return
+	}
+
 	// Export the policy to a file
 	if err := exporter.ExportPolicyFile(policyConfig); err != nil {
 		log.Fatalf("Error writing policy file: %v", err)
@@ -411,6 +1544,104 @@ func runExport() {
 	}
 }
 
+func runAuthLogin(withToken bool) {
+	var line string
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		// Read pasted tokens without echoing them to the terminal
+		if !withToken {
+			fmt.Printf("Paste your API token for %s: ", credentialHost())
+		}
+		input, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			log.Fatalf("Error reading token: %v", err)
+		}
+		line = string(input)
+	} else {
+		// Read a single line so tokens can be piped in
+		input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			log.Fatalf("Error reading token: %v", err)
+		}
+		line = input
+	}
+
+	token := strings.TrimSpace(line)
+	if token == "" {
+		log.Fatal("No token provided.")
+	}
+
+	if err := credentials.NewStore(credentialHost()).Set(token); err != nil {
+		log.Fatalf("Error storing token: %v", err)
+	}
+
+	fmt.Printf("Token for %s stored in credential helper\n", credentialHost())
+}
+
+func runAuthLogout() {
+	if err := credentials.NewStore(credentialHost()).Delete(); err != nil {
+		log.Fatalf("Error removing token: %v", err)
+	}
+
+	fmt.Printf("Token for %s removed from credential helper\n", credentialHost())
+}
+
+func runAuthStatus() {
+	if token := viper.GetString("github_token"); token != "" {
+		fmt.Printf("Token loaded from configuration or environment%s\n", tokenKind(token))
+		return
+	}
+
+	token, err := credentials.NewStore(credentialHost()).Get()
+	if err != nil {
+		log.Fatalf("Error reading credential store: %v", err)
+	}
+	if token == "" {
+		fmt.Printf("No token configured for %s\n", credentialHost())
+		os.Exit(1)
+	}
+
+	fmt.Printf("Token for %s loaded from credential helper%s\n", credentialHost(), tokenKind(token))
+}
+
+// tokenKind describes the kind of a token recognized by its prefix, for auth status
+func tokenKind(token string) string {
+	if kind := tokenscopes.Classify(token, false); kind != tokenscopes.KindUnknown {
+		return fmt.Sprintf(" (%s)", kind)
+	}
+	return ""
+}
+
+// resolveConfigReferences refuses a config file that appears to embed secrets, and resolves
+// its settings given as ${NAME} references from the environment
+func resolveConfigReferences(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Warning: Error reading config file: %v", err)
+		return
+	}
+	if err := config.CheckSecrets(path, data); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+		return
+	}
+
+	resolved, changed, unset, err := config.ResolveReferences(data, os.LookupEnv)
+	if err != nil {
+		log.Printf("Warning: Error resolving config file references: %v", err)
+		return
+	}
+	for _, name := range unset {
+		log.Printf("Warning: Config file references environment variable %s, which is not set", name)
+	}
+	if changed {
+		if err := viper.ReadConfig(bytes.NewReader(resolved)); err != nil {
+			log.Printf("Warning: Error reading config file: %v", err)
+		}
+	}
+}
+
 // initConfig reads configuration from file and environment variables
 func initConfig() {
 	if configFile := viper.GetString("config"); configFile != "" {
@@ -427,9 +1658,11 @@ func initConfig() {
 		viper.AddConfigPath("$HOME")                        // User's home directory (fallback)
 	}
 
-	// Enable environment variable overrides
+	// Enable environment variable overrides, including nested settings such as a.b as
+	// ACTION_CONTROL_A_B
+	viper.SetEnvPrefix(config.EnvPrefix)
+	viper.SetEnvKeyReplacer(config.EnvKeyReplacer)
 	viper.AutomaticEnv()
-	viper.SetEnvPrefix("ACTION_CONTROL")
 
 	// Try to read config file, but continue if not found
 	if err := viper.ReadInConfig(); err != nil {
@@ -442,5 +1675,10 @@ func initConfig() {
 		}
 	} else {
 		log.Printf("Using config file: %s", viper.ConfigFileUsed())
+		resolveConfigReferences(viper.ConfigFileUsed())
 	}
+
+	// Bind every known setting, including those from the config file, to its environment
+	// variable and aliases, so settings only given in the environment are listed and decoded
+	bindEnv()
 }
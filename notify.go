@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/notify"
+	"github.com/spf13/viper"
+)
+
+// Notification channels enabled with --notify
+const (
+	notifyEmail = "email"
+	notifyJira  = "jira"
+)
+
+// notificationChannels are the channels --notify accepts
+var notificationChannels = []string{notifyEmail, notifyJira}
+
+// checkNotifications validates the channels of --notify and their settings before scanning, so
+// a misconfigured channel fails the run before the API quota is spent on it
+func checkNotifications() {
+	for _, channel := range settingList("notify") {
+		if !slices.Contains(notificationChannels, channel) {
+			log.Fatalf("Unsupported notification channel for --notify: %s, must be one of %s", channel, strings.Join(notificationChannels, ", "))
+		}
+	}
+	if slices.Contains(settingList("notify"), notifyEmail) {
+		if _, err := notify.NewEmail(emailConfig()); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	if slices.Contains(settingList("notify"), notifyJira) {
+		if _, err := notify.NewJira(jiraConfig(), nil); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+}
+
+// emailConfig reads the email notifier's settings, each of which can be set in the environment
+func emailConfig() notify.EmailConfig {
+	return notify.EmailConfig{
+		Host:       viper.GetString("notifications.email.host"),
+		Port:       viper.GetInt("notifications.email.port"),
+		Username:   viper.GetString("notifications.email.username"),
+		Password:   viper.GetString("notifications.email.password"),
+		From:       viper.GetString("notifications.email.from"),
+		Recipients: settingList("notifications.email.recipients"),
+		Subject:    viper.GetString("notifications.email.subject"),
+		Template:   viper.GetString("notifications.email.template"),
+	}
+}
+
+// jiraConfig reads the Jira notifier's settings, each of which can be set in the environment
+func jiraConfig() notify.JiraConfig {
+	return notify.JiraConfig{
+		URL:       viper.GetString("notifications.jira.url"),
+		Username:  viper.GetString("notifications.jira.username"),
+		Token:     viper.GetString("notifications.jira.token"),
+		Project:   viper.GetString("notifications.jira.project"),
+		IssueType: viper.GetString("notifications.jira.issue_type"),
+		Labels:    settingList("notifications.jira.labels"),
+	}
+}
+
+// sendNotifications notifies the channels of --notify of the enforce outcome. Failing to notify
+// is reported but does not change the outcome.
+func sendNotifications(ctx context.Context, client *github.Client, enforcement *enforcement, target string) {
+	channels := settingList("notify")
+	if len(channels) == 0 {
+		return
+	}
+
+	digest := notify.NewDigest(target, enforcement.report, time.Now())
+	if slices.Contains(channels, notifyEmail) {
+		email, err := notify.NewEmail(emailConfig())
+		if err != nil {
+			log.Printf("Warning: Could not send email notifications: %v", err)
+			return
+		}
+		if err := email.Notify(digest, ownerEmails(ctx, client, enforcement, digest)); err != nil {
+			log.Printf("Warning: Could not send email notifications: %v", err)
+		}
+	}
+	if slices.Contains(channels, notifyJira) {
+		if err := notifyJiraIssues(digest); err != nil {
+			log.Printf("Warning: Could not update Jira issues: %v", err)
+		}
+	}
+}
+
+// notifyJiraIssues opens or updates the Jira issue of each repository in the digest, sending
+// requests through the configured proxy
+func notifyJiraIssues(digest notify.Digest) error {
+	client, err := httpConfig().HTTPClient()
+	if err != nil {
+		return err
+	}
+	jira, err := notify.NewJira(jiraConfig(), client)
+	if err != nil {
+		return err
+	}
+	return jira.Notify(digest)
+}
+
+// ownerEmails resolves the email addresses of the owners of each repository in the digest: its
+// owners from the owners mapping file or the teams API, or else the CODEOWNERS owners of its
+// violating workflows, falling back to its workflows directory. Owner handles reach owners
+// through the mapping's contacts.
+func ownerEmails(ctx context.Context, client *github.Client, enforcement *enforcement, digest notify.Digest) map[string][]string {
+	mapping, err := ownersMapping()
+	if err != nil {
+		log.Printf("Warning: Could not resolve repository owners: %v", err)
+		return nil
+	}
+
+	emails := make(map[string][]string)
+	for _, repo := range digest.Repositories {
+		repoOwners := repo.Owners
+		if len(repoOwners) == 0 {
+			repoOwners = codeOwners(ctx, client, repo.Name, enforcement.violatingWorkflows(repo.Name))
+		}
+		if addresses := mapping.Emails(repoOwners); len(addresses) > 0 {
+			emails[repo.Name] = addresses
+		}
+	}
+	return emails
+}
+
+// codeOwners returns the CODEOWNERS owners of a repository's workflows, or of its workflows
+// directory when none are given
+func codeOwners(ctx context.Context, client *github.Client, repoFullName string, workflows []string) []string {
+	owner, name, _ := strings.Cut(repoFullName, "/")
+	ruleset := client.GetCodeOwners(ctx, owner, name)
+	if len(workflows) == 0 {
+		workflows = []string{viper.GetString("workflows_path") + "/"}
+	}
+
+	var result []string
+	for _, workflow := range workflows {
+		for _, codeOwner := range ruleset.OwnersFor(workflow) {
+			if !slices.Contains(result, codeOwner) {
+				result = append(result, codeOwner)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/orgsettings"
+	"github.com/ihavespoons/action-control/internal/policy"
+
+	"github.com/spf13/viper"
+)
+
+func runSyncOrgSettings() {
+	// Validate GitHub token
+	token := resolveToken()
+
+	// Organization settings only exist for organizations
+	org := viper.GetString("organization")
+	if org == "" {
+		log.Fatal("An organization (--org) must be provided.")
+	}
+
+	// Load the policy that is the source of truth
+	config, err := policy.LoadPolicyConfig(viper.GetString("sync_policy_file"))
+	if err != nil {
+		log.Fatalf("Error loading policy file: %v", err)
+	}
+
+	// Initialize GitHub API client
+	client := newClient(token)
+	ctx, cancel := commandContext()
+	defer cancel()
+	checkTokenScopes(ctx, client, "sync-org-settings")
+
+	current, err := client.GetOrgActionsSettings(ctx, org)
+	if err != nil {
+		log.Fatalf("Error retrieving organization settings: %v", err)
+	}
+
+	drift, err := orgsettings.Compare(config, *current)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	// Make the policy the source of truth for the organization settings
+	if viper.GetBool("sync_apply") && !drift.InSync() {
+		log.Printf("Applying policy to the allowed actions settings of %s...", org)
+		if err := client.UpdateOrgActionsSettings(ctx, org, drift.Target); err != nil {
+			log.Fatalf("Error updating organization settings: %v", err)
+		}
+		log.Printf("Updated %s to allow %d action patterns", org, len(drift.Target.PatternsAllowed))
+	}
+
+	// Format and output the results
+	switch outputFormat := outputFormat("markdown"); outputFormat {
+	case "json":
+		jsonData, err := formatter.FormatJSON(drift)
+		if err != nil {
+			log.Fatalf("Error formatting JSON: %v", err)
+		}
+		fmt.Println(jsonData)
+	case "markdown":
+		fmt.Println(formatter.FormatOrgSettingsDrift(org, drift))
+	default:
+		log.Fatalf("Unsupported output format: %s", outputFormat)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/redact"
+	"github.com/spf13/viper"
+)
+
+// outputTarget is an output format and the file it is written to; an empty path is standard output
+type outputTarget struct {
+	format string
+	path   string
+}
+
+// outputFormat returns the first configured output format, or defaultFormat when none is set.
+// Commands producing a single output use it.
+func outputFormat(defaultFormat string) string {
+	if formats := settingList("output_format"); len(formats) > 0 && formats[0] != "" {
+		return formats[0]
+	}
+	return defaultFormat
+}
+
+// hasOutputFormat reports whether format is among the configured output formats
+func hasOutputFormat(format string) bool {
+	return slices.Contains(settingList("output_format"), format)
+}
+
+// outputTargets pairs each --output format with the --output-file given at the same position.
+// Formats without a file are written to standard output, which only one format may be.
+func outputTargets(defaultFormat string) []outputTarget {
+	formats := settingList("output_format")
+	files := settingList("output_files")
+	if len(formats) == 0 {
+		formats = []string{defaultFormat}
+	}
+	if len(files) > len(formats) {
+		log.Fatalf("Each --output-file needs a matching --output format, got %d files for %d formats", len(files), len(formats))
+	}
+
+	targets := make([]outputTarget, len(formats))
+	stdout := 0
+	for i, format := range formats {
+		targets[i] = outputTarget{format: format}
+		if i < len(files) {
+			targets[i].path = files[i]
+		} else {
+			stdout++
+		}
+	}
+	if stdout > 1 {
+		log.Fatalf("Only one output format can be written to standard output, use --output-file for the others")
+	}
+
+	return targets
+}
+
+// outputTemplate returns the custom template given with --template for the template output
+// format, or an empty string when the template format is not requested
+func outputTemplate() string {
+	if !hasOutputFormat("template") {
+		return ""
+	}
+
+	path := viper.GetString("template")
+	if path == "" {
+		log.Fatal("The template output format requires a template file (--template).")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Error reading template file: %v", err)
+	}
+	return string(content)
+}
+
+// renderTemplate renders data through the custom template given with --template
+func renderTemplate(data interface{}) (string, error) {
+	return formatter.FormatTemplate(filepath.Base(viper.GetString("template")), outputTemplate(), data)
+}
+
+// hasOutputFiles reports whether any output is written to a file
+func hasOutputFiles() bool {
+	return len(settingList("output_files")) > 0
+}
+
+// writeOutputs renders each output target and writes it to its file, returning the output
+// destined for standard output. When every output goes to a file, summary is returned instead,
+// so the console stays concise.
+func writeOutputs(targets []outputTarget, render func(format string) (string, error), summary string) string {
+	stdout := summary
+
+	for _, target := range targets {
+		content, err := render(target.format)
+		if err != nil {
+			log.Fatalf("Error formatting %s output: %v", target.format, err)
+		}
+		content = redactor.Apply(content)
+
+		if target.path == "" {
+			stdout = content
+			continue
+		}
+
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if err := os.WriteFile(target.path, []byte(content), 0o644); err != nil {
+			log.Fatalf("Error writing %s output to %s: %v", target.format, target.path, err)
+		}
+		log.Printf("Wrote %s output to %s", target.format, target.path)
+	}
+
+	return redactor.Apply(stdout)
+}
+
+// redactor hides the names of private repositories in output with --redact; nil otherwise
+var redactor *redact.Redactor
+
+// setupRedaction hides the names of the repositories that are not public with --redact: those
+// of the organization, or with --repo those of the repository's owner, as its workflows may
+// reference the owner's private actions and reusable workflows. Names whose visibility can't
+// be determined are hidden too, and the scan stopped when the repositories can't be listed.
+func setupRedaction(ctx context.Context, client *github.Client, org, repo string) {
+	if !viper.GetBool("redact") {
+		return
+	}
+	redactor = redact.New(viper.GetString("redact_secret"))
+
+	owner := org
+	if repo != "" {
+		owner, _, _ = strings.Cut(repo, "/")
+	}
+	repos, err := client.ListRepositories(ctx, owner)
+	if err != nil {
+		log.Fatalf("Error listing the repositories of %s to redact the names of private ones: %v", owner, err)
+	}
+	listed := false
+	for _, details := range repos {
+		if !public(details) {
+			redactor.Hide(details.FullName)
+		}
+		listed = listed || strings.EqualFold(details.FullName, repo)
+	}
+
+	// A repository missing from the listing, such as one created since it was cached
+	if repo != "" && !listed {
+		details, err := client.GetRepository(ctx, owner, strings.TrimPrefix(repo, owner+"/"))
+		if err != nil {
+			log.Printf("Warning: Could not determine the visibility of %s, redacting its name: %v", repo, err)
+		}
+		if err != nil || !public(details) {
+			redactor.Hide(repo)
+		}
+	}
+}
+
+// public reports whether a repository is public, as its visibility or, for forges without
+// one, its private flag says
+func public(repo github.Repository) bool {
+	if repo.Visibility != "" {
+		return repo.Visibility == "public"
+	}
+	return !repo.IsPrivate
+}
+
+// announce prints scan progress chatter to standard output unless --quiet is set
+func announce(format string, args ...interface{}) {
+	if !viper.GetBool("quiet") {
+		fmt.Print(redactor.Apply(fmt.Sprintf(format, args...)))
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/owners"
+	"github.com/spf13/viper"
+)
+
+// ownersMapping loads the owners mapping file, or returns nil when none is configured
+func ownersMapping() (*owners.Mapping, error) {
+	path := viper.GetString("owners_file")
+	if path == "" {
+		return nil, nil
+	}
+	return owners.Load(path)
+}
+
+// ownersConfigured reports whether repository owners are resolved for the report
+func ownersConfigured() bool {
+	return viper.GetString("owners_file") != "" || viper.GetBool("owners_from_teams")
+}
+
+// ownersFileContent returns the content of the owners mapping file, for the verdict cache key
+func ownersFileContent() string {
+	path := viper.GetString("owners_file")
+	if path == "" {
+		return ""
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// resolveOwners records the owners of each evaluated repository in the report: those the owners
+// mapping file lists for it, or else, with --owners-from-teams, the teams with the highest
+// permission on it
+func resolveOwners(ctx context.Context, client *github.Client, enforcement *enforcement) {
+	if !ownersConfigured() {
+		return
+	}
+	mapping, err := ownersMapping()
+	if err != nil {
+		log.Printf("Warning: Could not resolve repository owners: %v", err)
+		return
+	}
+
+	for repo, result := range enforcement.report.Repositories {
+		repoOwners := mapping.OwnersOf(repo)
+		if len(repoOwners) == 0 && viper.GetBool("owners_from_teams") {
+			owner, name, _ := strings.Cut(repo, "/")
+			repoOwners, err = client.OwningTeams(ctx, owner, name)
+			if err != nil {
+				log.Printf("Warning: Could not resolve owning teams of %s: %v", repo, err)
+			}
+		}
+		if len(repoOwners) > 0 {
+			result.Owners = repoOwners
+			enforcement.report.Repositories[repo] = result
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/spf13/viper"
+)
+
+func runPolicyMigrate() {
+	files, err := policy.PolicyFiles(settingList("policy_migrate_policy_file"))
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	dryRun := viper.GetBool("policy_migrate_dry_run")
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatalf("Error reading policy file: %v", err)
+		}
+
+		result, err := policy.MigratePolicy(content)
+		if err != nil {
+			log.Fatalf("Error migrating %s: %v", file, err)
+		}
+		if !result.Upgraded() {
+			log.Printf("%s is already at policy version %d", file, result.ToVersion)
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("# %s: version %d -> %d (%s)\n", file, result.FromVersion, result.ToVersion, strings.Join(result.Changes, "; "))
+			fmt.Print(string(result.Content))
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			log.Fatalf("Error reading policy file: %v", err)
+		}
+		if err := os.WriteFile(file, result.Content, info.Mode().Perm()); err != nil {
+			log.Fatalf("Error writing policy file: %v", err)
+		}
+		fmt.Printf("Migrated %s from version %d to %d\n", file, result.FromVersion, result.ToVersion)
+		for _, change := range result.Changes {
+			fmt.Printf("  - %s\n", change)
+		}
+	}
+}
+
+// warnUnversionedPolicy points to policy migrate when a policy predates the version field
+func warnUnversionedPolicy(config *policy.PolicyConfig) {
+	if config.Version == 0 {
+		log.Printf("Warning: The policy has no version field and is read as version 0; run 'action-control policy migrate' to upgrade it to version %d", policy.CurrentVersion)
+	}
+}
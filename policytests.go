@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/policytest"
+
+	"github.com/spf13/viper"
+)
+
+func runPolicyTest() {
+	// Load the policy under test
+	config, err := policy.LoadPolicyFiles(settingList("policy_test_policy_file"))
+	if err != nil {
+		log.Fatalf("Error loading policy file: %v", err)
+	}
+
+	testsFile := viper.GetString("policy_test_file")
+
+	// Write a starter test file instead of running tests
+	if viper.GetBool("policy_test_generate") {
+		if _, err := os.Stat(testsFile); err == nil {
+			log.Fatalf("Test file %s already exists, remove it or choose another with --tests", testsFile)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Fatalf("Error checking test file: %v", err)
+		}
+
+		suite := policytest.Generate(config)
+		if err := suite.Save(testsFile); err != nil {
+			log.Fatalf("Error writing test file: %v", err)
+		}
+		fmt.Printf("Wrote %d starter tests to %s\n", len(suite.Tests), testsFile)
+		return
+	}
+
+	suite, err := policytest.Load(testsFile)
+	if err != nil {
+		log.Fatalf("Error loading test file: %v", err)
+	}
+
+	failed := 0
+	for _, result := range policytest.Run(config, suite) {
+		if result.Passed {
+			fmt.Printf("PASS  %s\n", result.Case.Name)
+			continue
+		}
+
+		failed++
+		outcome := "allowed"
+		if result.Case.Expect == policytest.ExpectAllow {
+			outcome = "denied"
+		}
+		fmt.Printf("FAIL  %s\n      expected %s, but %s: %s\n", result.Case.Name, result.Case.Expect, outcome, strings.Join(result.Unexpected, ", "))
+	}
+
+	fmt.Printf("\n%d passed, %d failed\n", len(suite.Tests)-failed, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runPolicyExplain() {
+	config, err := policy.LoadPolicyFiles(settingList("policy_explain_policy_file"))
+	if err != nil {
+		log.Fatalf("Error loading policy file: %v", err)
+	}
+
+	repo := viper.GetString("repository")
+	actions := settingList("policy_explain_actions")
+	if repo == "" || len(actions) == 0 {
+		log.Fatal("Explaining a decision requires a repository (--repo) and at least one action (--action).")
+	}
+
+	// Merge a repository policy file the same way enforce does
+	effectiveConfig := config
+	repoPolicyFile := viper.GetString("policy_explain_repo_policy_file")
+	if repoPolicyFile != "" && config.RepoPolicy == policy.RepoPolicyIgnore {
+		log.Printf("The policy ignores repository policy files (repo_policy: %s), not merging %s", policy.RepoPolicyIgnore, repoPolicyFile)
+		repoPolicyFile = ""
+	}
+	if repoPolicyFile != "" {
+		content, err := os.ReadFile(repoPolicyFile)
+		if err != nil {
+			log.Fatalf("Error reading repository policy file: %v", err)
+		}
+		merged, err := policy.MergePolicies(config, content, repo)
+		if err != nil {
+			log.Fatalf("Error merging repository policy file: %v", err)
+		}
+		effectiveConfig = merged.Policy
+		for _, conflict := range merged.Conflicts {
+			log.Printf("Merge conflict: %s in %s %q (resolution: %s)", conflict.Kind, conflict.Rule, conflict.Entry, conflict.Resolution)
+		}
+	}
+
+	effective := policy.ResolveWorkflowPolicy(effectiveConfig, repo, viper.GetString("policy_explain_workflow"))
+	if repoPolicyFile != "" {
+		effective.Layers = append([]string{policy.LayerGlobal, policy.LayerRepoOverride}, effective.Layers[1:]...)
+	}
+
+	explanations := make([]policy.Explanation, len(actions))
+	for i, action := range actions {
+		explanations[i] = policy.ExplainEffective(effective, repo, action)
+	}
+
+	if outputFormat("markdown") == "json" {
+		jsonData, err := formatter.FormatJSON(explanations)
+		if err != nil {
+			log.Fatalf("Error formatting JSON: %v", err)
+		}
+		fmt.Println(jsonData)
+		return
+	}
+	for i, explanation := range explanations {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(formatter.FormatExplanation(explanation))
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/spf13/viper"
+)
+
+// statusContext names the commit status --status-check sets, which branch protection can
+// require to block merging pull requests
+const statusContext = "action-control"
+
+// evaluatePullRequest evaluates the workflow files the pull request given with --pull-request
+// changes, rather than all of the repository's, holding only the violating actions its changes
+// introduce against it, and returns the pull request
+func evaluatePullRequest(ctx context.Context, client *github.Client, enforcement *enforcement, repo string) github.PullRequest {
+	owner, name, _ := strings.Cut(repo, "/")
+	number := viper.GetInt("pull_request")
+
+	announce("Scanning the workflow files pull request #%d changes in %s and enforcing policy...\n", number, repo)
+	pull, files, err := client.GetPullRequestWorkflowFiles(ctx, owner, name, number)
+	if err != nil {
+		log.Fatalf("Error retrieving workflow files of pull request #%d in %s: %v", number, repo, err)
+	}
+	setStatus(ctx, client, repo, pull, github.StatusPending, fmt.Sprintf("Evaluating %d changed workflow files", len(files)))
+
+	// Violating actions the changed files used before are not the pull request's doing
+	baseFiles, err := client.GetPullRequestBaseFiles(ctx, owner, name, pull)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	enforcement.baseReferences = map[string][]string{repo: nil}
+	for _, action := range github.ExtractActions(baseFiles) {
+		enforcement.baseReferences[repo] = append(enforcement.baseReferences[repo], action.Uses)
+	}
+
+	enforcement.partial = true
+	if len(files) > 0 {
+		enforcement.evaluate(repo, files)
+	}
+	return pull
+}
+
+// pullRequestStatus returns the commit status of a pull request evaluation
+func pullRequestStatus(enforcement *enforcement) (state, description string) {
+	if len(enforcement.report.Repositories) == 0 {
+		return github.StatusSuccess, "No workflow changes to evaluate"
+	}
+	errors, warnings := enforcement.findingCounts()
+	if enforcement.failed() {
+		return github.StatusFailure, fmt.Sprintf("Workflow changes violate the action policy: %d errors, %d warnings", errors, warnings)
+	}
+	if errors+warnings > 0 {
+		return github.StatusSuccess, fmt.Sprintf("Workflow changes are within the tolerated findings: %d errors, %d warnings", errors, warnings)
+	}
+	return github.StatusSuccess, "Workflow changes comply with the action policy"
+}
+
+// setStatus sets the commit status of the pull request's head commit when --status-check is
+// set. Failing to set it is reported but does not change the outcome.
+func setStatus(ctx context.Context, client *github.Client, repo string, pull github.PullRequest, state, description string) {
+	if !viper.GetBool("status_check") {
+		return
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+	if err := client.CreateStatus(ctx, owner, name, pull.HeadSHA, state, statusContext, description, ""); err != nil {
+		log.Printf("Warning: Could not set the status of pull request #%d: %v", pull.Number, err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/review"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/viper"
+)
+
+func runReview() {
+	// Validate GitHub token
+	token := resolveToken()
+
+	// Get target organization or repository
+	org := viper.GetString("organization")
+	specificRepo := viper.GetString("repository")
+
+	// At least one target must be specified
+	if org == "" && specificRepo == "" {
+		log.Fatal("Either organization (--org) or specific repository (--repo) must be provided.")
+	}
+
+	// Load the policy being reviewed
+	policyFile := viper.GetString("review_policy_file")
+	localPolicy, err := policy.LoadPolicyConfig(policyFile)
+	if err != nil {
+		log.Fatalf("Error loading policy file: %v", err)
+	}
+
+	// Initialize GitHub API client
+	client := newClient(token)
+	ctx, cancel := commandContext()
+	defer cancel()
+	checkTokenScopes(ctx, client, "review")
+
+	// Map to store discovered actions by repository
+	githubActionsMap := make(map[string][]github.Action)
+
+	if specificRepo != "" {
+		// Scan a single repository
+		parts := strings.Split(specificRepo, "/")
+		if len(parts) != 2 {
+			log.Fatalf("Invalid repository format. Use 'owner/repo' format.")
+		}
+
+		announce("Scanning repository %s...\n", specificRepo)
+		actions, err := client.GetActions(ctx, parts[0], parts[1])
+		if err != nil {
+			log.Fatalf("Error retrieving actions from repository %s: %v", specificRepo, err)
+		}
+		githubActionsMap[specificRepo] = actions
+	} else {
+		// Scan an entire organization
+		announce("Scanning repositories in %s organization...\n", org)
+		checkpointOrgScan(client, org)
+		githubActionsMap, err = client.ActionsForOrg(ctx, org)
+		if scanInterrupted(err) {
+			defer exit(1) // Signal the interruption once the partial results are reported
+		} else if err != nil {
+			log.Fatalf("Error retrieving actions: %v", err)
+		}
+	}
+
+	// Run the interactive review
+	model := review.NewModel(githubActionsMap, localPolicy)
+	if _, err := tea.NewProgram(model, tea.WithAltScreen()).Run(); err != nil {
+		log.Fatalf("Error running review: %v", err)
+	}
+
+	if !model.Changed {
+		fmt.Println("No policy changes made")
+		return
+	}
+
+	// Edit the lists changed during the session in place, keeping the file's comments
+	if err := policy.EditPolicyFile(policyFile, model.Edits); err != nil {
+		log.Fatalf("Error writing policy file: %v", err)
+	}
+
+	fmt.Printf("Allowed %d actions and updated %s:\n", len(model.Added), policyFile)
+	for _, action := range model.Added {
+		fmt.Printf("  + %s\n", action)
+	}
+}
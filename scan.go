@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/snapshot"
+	"github.com/spf13/viper"
+)
+
+// runScan captures the workflow files and policy-relevant files of the target repositories into
+// a scan file, so that enforce --input can evaluate them on a host without API access
+func runScan() {
+	// Validate GitHub token
+	token := resolveToken()
+
+	// Get target organization or repository
+	org := viper.GetString("organization")
+	specificRepo := viper.GetString("repository")
+
+	// At least one target must be specified
+	if org == "" && specificRepo == "" {
+		log.Fatal("Either organization (--org) or specific repository (--repo) must be provided.")
+	}
+
+	outputPath := viper.GetString("scan_output")
+	if outputPath == "" {
+		log.Fatal("--output must name the scan file to write, or - for standard output")
+	}
+	if outputPath == "-" {
+		// Keep standard output to the scan file
+		viper.Set("quiet", true)
+	}
+
+	// Initialize GitHub API client
+	client := newClient(token)
+	ctx, cancel := commandContext()
+	defer cancel()
+	checkTokenScopes(ctx, client, "scan")
+	auxiliaryPaths := snapshot.AuxiliaryPaths()
+	exitWithEstimate(ctx, client, org, specificRepo, len(auxiliaryPaths)+1)
+
+	scan := snapshot.New(org, specificRepo, time.Now())
+
+	// Capture each repository along with the files the evaluation reads besides its workflows
+	capture := func(repoFullName string, files []github.WorkflowFile) error {
+		owner, name, _ := strings.Cut(repoFullName, "/")
+		repository := &snapshot.Repository{}
+		repository.AddWorkflowFiles(files)
+
+		details, err := client.GetRepository(ctx, owner, name)
+		if err != nil {
+			log.Printf("Warning: Could not get details of repository %s: %v", repoFullName, err)
+		}
+		repository.Visibility = details.Visibility
+		repository.DefaultBranch = details.DefaultBranch
+
+		contents, err := client.GetRepositoryFiles(ctx, owner, name, auxiliaryPaths)
+		if err != nil {
+			return fmt.Errorf("failed to read files of repository %s: %w", repoFullName, err)
+		}
+		if len(contents) > 0 {
+			repository.Files = make(map[string]string, len(contents))
+			for path, content := range contents {
+				repository.Files[path] = string(content)
+			}
+		}
+
+		scan.Repositories[repoFullName] = repository
+		return nil
+	}
+
+	if specificRepo != "" {
+		// Scan a single repository
+		parts := strings.Split(specificRepo, "/")
+		if len(parts) != 2 {
+			log.Fatalf("Invalid repository format. Use 'owner/repo' format.")
+		}
+
+		announce("Scanning repository %s...\n", specificRepo)
+		files, err := client.GetWorkflowFiles(ctx, parts[0], parts[1])
+		if err != nil {
+			log.Fatalf("Error retrieving actions from repository %s: %v", specificRepo, err)
+		}
+		if len(files) > 0 {
+			if err := capture(specificRepo, files); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+		}
+	} else {
+		// Scan an entire organization, fetching repositories in parallel
+		announce("Scanning repositories in %s organization...\n", org)
+		checkpointOrgScan(client, org)
+		err := client.StreamWorkflowFilesForOrg(ctx, org, capture)
+		if scanInterrupted(err) {
+			// A partial scan file would pass repositories it lacks, so none is written
+			log.Printf("Scan interrupted, no scan file written: %v", err)
+			exit(1)
+		} else if err != nil {
+			log.Fatalf("Error retrieving actions: %v", err)
+		}
+	}
+
+	// Capture the central policy, for evaluations that discover it
+	policyOrg := org
+	if policyOrg == "" {
+		policyOrg, _, _ = strings.Cut(specificRepo, "/")
+	}
+	scan.OrgPolicy, scan.OrgPolicyPath = scanOrgPolicy(ctx, client, policyOrg)
+
+	if err := writeScan(scan, outputPath); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if outputPath != "-" {
+		announce("Wrote scan of %d repositories to %s\n", len(scan.Repositories), outputPath)
+	}
+}
+
+// scanOrgPolicy returns the content and path of the organization's central policy, or empty
+// strings when it has none
+func scanOrgPolicy(ctx context.Context, client *github.Client, org string) (string, string) {
+	for _, path := range policy.OrgPolicyPaths {
+		content, err := client.GetRepositoryContent(ctx, org, policy.OrgPolicyRepo, path)
+		if err == nil && len(content) > 0 {
+			return string(content), path
+		}
+	}
+	return "", ""
+}
+
+// writeScan writes the scan file to path, or to standard output for -
+func writeScan(scan *snapshot.Snapshot, path string) error {
+	if path == "-" {
+		return scan.Write(os.Stdout)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create scan file: %w", err)
+	}
+	if err := scan.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
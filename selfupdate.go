@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/selfupdate"
+	"github.com/ihavespoons/action-control/internal/version"
+
+	"github.com/spf13/viper"
+)
+
+func runSelfUpdate() {
+	// Releases are public, so a token is optional and only raises the rate limit
+	client, err := github.NewClientWithHTTPConfig(viper.GetString("github_token"), httpConfig())
+	if err != nil {
+		log.Fatalf("Error initializing client: %v", err)
+	}
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	requested := viper.GetString("self_update_version")
+	release, err := selfupdate.FindRelease(ctx, client, requested)
+	if err != nil {
+		log.Fatalf("Error looking up release: %v", err)
+	}
+
+	// Only move forward unless a version was asked for explicitly
+	if requested == "" && version.IsRelease() {
+		if cmp, err := version.Compare(version.Version, release.Tag); err == nil && cmp >= 0 {
+			fmt.Printf("action-control %s is up to date\n", version.Version)
+			return
+		}
+	}
+
+	if viper.GetBool("self_update_check") {
+		fmt.Printf("action-control %s is available (running %s)\n", release.Tag, version.Version)
+		return
+	}
+
+	binary, err := selfupdate.Download(ctx, client, release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		log.Fatalf("Error downloading %s: %v", release.Tag, err)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Error locating the running executable: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	if err := selfupdate.Replace(executable, binary, runtime.GOOS); err != nil {
+		log.Fatalf("Error installing %s: %v", release.Tag, err)
+	}
+
+	fmt.Printf("Updated action-control %s to %s (%s)\n", version.Version, release.Tag, executable)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/policy"
+	"github.com/ihavespoons/action-control/internal/server"
+	"github.com/ihavespoons/action-control/internal/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// serveScanFlags are the global flags the server sets itself on the scans it runs, rather than
+// passing on its own
+var serveScanFlags = []string{"org", "repo", "output", "output-file", "template", "quiet", "progress-format", "record", "checkpoint", "resume", "estimate"}
+
+// runServe serves the REST API querying the runs recorded in the store and starting scans,
+// until interrupted
+func runServe(cmd *cobra.Command) {
+	location := viper.GetString("store")
+	if location == "" {
+		log.Fatal("--store must name the store runs are recorded in and queried from")
+	}
+
+	// The server runs until interrupted, --timeout applies to the scans it runs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	history, err := store.Open(ctx, location)
+	if err != nil {
+		log.Fatalf("Error opening store: %v", err)
+	}
+	defer history.Close()
+
+	// The API starts scans with the server's GitHub token and returns their findings, including
+	// those of private repositories, so it is only served without a token when asked to
+	token := viper.GetString("serve_token")
+	if token == "" {
+		if !viper.GetBool("serve_insecure_no_auth") {
+			log.Fatal("serve_token must be set for the API to require a bearer token, or pass --insecure-no-auth to serve it unauthenticated")
+		}
+		log.Printf("Warning: No serve_token is set, the API accepts unauthenticated requests")
+	}
+
+	org := viper.GetString("organization")
+	if org == "" {
+		log.Fatal("--org must name the organization the server scans")
+	}
+
+	enforce := enforceRunner(cmd)
+	var client *github.Client
+	if viper.GetString("serve_webhook_secret") != "" {
+		// Sets the status of pull requests whose evaluation fails
+		client = newClient(resolveToken())
+	}
+	api := server.New(ctx, server.Config{
+		Store:         history,
+		Token:         token,
+		Scan:          scanner(enforce, location),
+		Organization:  org,
+		Policy:        servedPolicy,
+		WebhookSecret: viper.GetString("serve_webhook_secret"),
+		Gate:          gate(enforce, client),
+	})
+	httpServer := &http.Server{
+		Addr:              viper.GetString("serve_listen"),
+		Handler:           api.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+	announce("Serving the API on %s\n", httpServer.Addr)
+
+	select {
+	case err := <-errs:
+		log.Fatalf("Error: %v", err)
+	case <-ctx.Done():
+	}
+
+	announce("Shutting down, waiting for running scans...\n")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Warning: Could not shut down the API gracefully: %v", err)
+	}
+	api.Wait()
+}
+
+// servedPolicy returns the policy the scans enforce, for the web UI: the inline policy content
+// or the policy files. A central policy is read by each scan and not shown.
+func servedPolicy() ([]server.PolicyFile, error) {
+	if content := viper.GetString("policy_content"); content != "" {
+		return []server.PolicyFile{{Path: "policy_content", Content: content}}, nil
+	}
+
+	paths := settingList("policy_file")
+	if len(paths) == 0 {
+		paths = []string{"policy.yaml"}
+	}
+	files, err := policy.PolicyFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	var served []server.PolicyFile
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file: %w", err)
+		}
+		served = append(served, server.PolicyFile{Path: file, Content: string(content)})
+	}
+	return served, nil
+}
+
+// checkpointName replaces the characters of a target not allowed in file names
+var checkpointName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// enforceRunner returns a function running enforce with arguments in a child process. The
+// child reads the same config file and environment as the server, along with the global flags
+// the server was started with.
+func enforceRunner(cmd *cobra.Command) func(ctx context.Context, args ...string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Error: Could not locate the action-control executable: %v", err)
+	}
+
+	var inherited []string
+	cmd.InheritedFlags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed || slices.Contains(serveScanFlags, flag.Name) {
+			return
+		}
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range values.GetSlice() {
+				inherited = append(inherited, fmt.Sprintf("--%s=%s", flag.Name, value))
+			}
+			return
+		}
+		inherited = append(inherited, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+	})
+	if configFile := viper.ConfigFileUsed(); configFile != "" && !cmd.Flags().Changed("config") {
+		inherited = append(inherited, "--config="+configFile)
+	}
+
+	return func(ctx context.Context, args ...string) error {
+		child := exec.CommandContext(ctx, executable, append(append([]string{"enforce", "--quiet"}, args...), inherited...)...)
+		child.Stderr = os.Stderr
+
+		err := child.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == exitFindings {
+			// Findings failing the policy are the run's outcome, not an error; errors are
+			// reported on standard error and exit with 1
+			return nil
+		}
+		return err
+	}
+}
+
+// scanner returns the scanner running enforce for a target, recording its run in the store
+func scanner(enforce func(ctx context.Context, args ...string) error, location string) server.Scanner {
+	return func(ctx context.Context, target string) error {
+		targetFlag := "--org=" + target
+		if strings.Contains(target, "/") {
+			targetFlag = "--repo=" + target
+		}
+		// Scans of different targets run at the same time, each with its own checkpoint
+		checkpointPath := filepath.Join(os.TempDir(), "action-control-serve-"+checkpointName.ReplaceAllString(target, "_")+".ndjson")
+
+		announce("Scanning %s...\n", target)
+		return enforce(ctx, targetFlag, "--store="+location, "--checkpoint="+checkpointPath)
+	}
+}
+
+// gate returns the gate running enforce on the workflow files a pull request changes, setting
+// its status check. When the evaluation itself fails, the gate sets an error status so the pull
+// request doesn't wait on a pending check forever.
+func gate(enforce func(ctx context.Context, args ...string) error, client *github.Client) server.Gate {
+	return func(ctx context.Context, repo string, number int, headSHA string) error {
+		announce("Evaluating pull request #%d in %s...\n", number, repo)
+		err := enforce(ctx, "--repo="+repo, "--pull-request="+strconv.Itoa(number), "--status-check")
+		if err != nil && headSHA != "" {
+			owner, name, _ := strings.Cut(repo, "/")
+			if statusErr := client.CreateStatus(ctx, owner, name, headSHA, github.StatusError, statusContext, "The action policy could not be evaluated", ""); statusErr != nil {
+				log.Printf("Warning: Could not set the status of pull request #%d: %v", number, statusErr)
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ihavespoons/action-control/internal/formatter"
+	"github.com/ihavespoons/action-control/internal/github"
+	"github.com/ihavespoons/action-control/internal/stats"
+)
+
+// runReportStats reports anonymous aggregate statistics of the scanned repositories, without
+// repository names, so they can be shared as a benchmark
+func runReportStats(ctx context.Context, client *github.Client, org, specificRepo string, targets []outputTarget) {
+	filesByRepo := make(map[string][]github.WorkflowFile)
+	total := 0
+
+	if specificRepo != "" {
+		owner, repo, found := strings.Cut(specificRepo, "/")
+		if !found || strings.Contains(repo, "/") {
+			log.Fatalf("Invalid repository format. Use 'owner/repo' format.")
+		}
+
+		announce("Scanning repository %s...\n", specificRepo)
+		files, err := client.GetWorkflowFiles(ctx, owner, repo)
+		if err != nil {
+			log.Fatalf("Error retrieving workflow files from repository %s: %v", specificRepo, err)
+		}
+		filesByRepo[specificRepo] = files
+		org = owner
+		total = 1
+	} else {
+		// Repositories without workflow files count towards the share of those with them
+		repos, err := client.ListRepositories(ctx, org)
+		if err != nil {
+			log.Fatalf("Error listing repositories: %v", err)
+		}
+		total = len(repos)
+
+		announce("Scanning repositories in %s organization...\n", org)
+		checkpointOrgScan(client, org)
+		filesByRepo, err = client.WorkflowFilesForOrg(ctx, org)
+		if scanInterrupted(err) {
+			defer exit(1) // Signal the interruption once the partial results are reported
+		} else if err != nil {
+			log.Fatalf("Error retrieving workflow files: %v", err)
+		}
+	}
+
+	result := stats.Compute(filesByRepo, total, org)
+	render := func(format string) (string, error) {
+		switch format {
+		case "json":
+			return formatter.FormatJSON(result)
+		case "markdown":
+			return formatter.FormatStats(result), nil
+		case "template":
+			return renderTemplate(result)
+		}
+		return "", fmt.Errorf("unsupported output format for statistics: %s", format)
+	}
+	summary := fmt.Sprintf("Aggregated %d action references across %d repositories.", result.ActionReferences, result.Repositories)
+	fmt.Println(writeOutputs(targets, render, summary))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// defaultReportJSON is where the JSON report is written for the report_json step output when no
+// --output-file holds it. Relative paths resolve against the workspace both inside the Docker
+// action and in later steps.
+const defaultReportJSON = "action-control-report.json"
+
+// writeStepOutputs appends the outcome of an enforce run to the GitHub Actions step outputs
+// file: violations_count, compliant and report_json, the path of the JSON report
+func writeStepOutputs(enforcement *enforcement) error {
+	reportPath := ""
+	for _, target := range outputTargets("markdown") {
+		if target.format == "json" && target.path != "" {
+			reportPath = target.path
+		}
+	}
+	if reportPath == "" {
+		content, err := enforcement.render("json")
+		if err != nil {
+			return err
+		}
+		content = redactor.Apply(content)
+		if err := os.WriteFile(defaultReportJSON, []byte(content+"\n"), 0o644); err != nil {
+			return err
+		}
+		reportPath = defaultReportJSON
+	}
+
+	errors, _ := enforcement.findingCounts()
+	return appendStepOutputs(map[string]string{
+		"violations_count": strconv.Itoa(errors),
+		"compliant":        strconv.FormatBool(errors == 0 && enforcement.report.Interrupted == ""),
+		"report_json":      reportPath,
+	})
+}
+
+// appendStepOutputs appends name=value lines to the file named by GITHUB_OUTPUT, in sorted order
+func appendStepOutputs(outputs map[string]string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return fmt.Errorf("GITHUB_OUTPUT is not set")
+	}
+
+	var sb strings.Builder
+	for _, name := range []string{"compliant", "report_json", "violations_count"} {
+		if value, ok := outputs[name]; ok {
+			sb.WriteString(fmt.Sprintf("%s=%s\n", name, value))
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(sb.String()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// stepOutputsEnabled reports whether enforce writes step outputs, which it does by default
+// when running in GitHub Actions
+func stepOutputsEnabled() bool {
+	if !viper.GetBool("step_outputs") {
+		return false
+	}
+	if os.Getenv("GITHUB_OUTPUT") == "" {
+		log.Printf("Warning: --step-outputs requires GITHUB_OUTPUT, skipping step outputs")
+		return false
+	}
+	return true
+}
@@ -13,16 +13,22 @@ const (
 	actionYamlPath = "../action.yml"
 	dockerfilePath = "../Dockerfile"
 	entrypointPath = "../entrypoint.sh"
+	compositePath  = "../composite/action.yml"
+	powershellPath = "../composite/action-control.ps1"
+
+	actionInputsPath = "../internal/actioninputs/inputs.go"
 )
 
 // TestActionControl runs all tests for the GitHub Action
 func TestActionControl(t *testing.T) {
 	t.Run("ActionYamlStructure", testActionYamlStructure)
 	t.Run("ActionInputs", testActionInputs)
+	t.Run("ActionOutputs", testActionOutputs)
 	t.Run("ActionRuns", testActionRuns)
 	t.Run("DockerfileConfig", testDockerfileConfig)
 	t.Run("EntrypointScript", testEntrypointScript)
 	t.Run("CommandLineHandling", testCommandLineHandling)
+	t.Run("CompositeAction", testCompositeAction)
 }
 
 // Helper function to read and parse the action.yml file
@@ -107,6 +113,21 @@ func testActionInputs(t *testing.T) {
 			hasDefault:  false,
 			description: "ignoring local policy files",
 		},
+		"cache_file": {
+			required:    false,
+			hasDefault:  true,
+			description: "cache",
+		},
+		"max_violations": {
+			required:    false,
+			hasDefault:  true,
+			description: "tolerated",
+		},
+		"fail_on_severity": {
+			required:    false,
+			hasDefault:  true,
+			description: "severity",
+		},
 	}
 
 	// Check each expected input
@@ -138,6 +159,27 @@ func testActionInputs(t *testing.T) {
 	}
 }
 
+// testActionOutputs verifies the outputs enforce writes to GITHUB_OUTPUT are declared
+func testActionOutputs(t *testing.T) {
+	actionConfig := readActionYaml(t)
+
+	outputs, ok := actionConfig["outputs"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Outputs section is missing or not properly formatted")
+	}
+
+	for _, name := range []string{"violations_count", "compliant", "report_json"} {
+		output, ok := outputs[name].(map[string]interface{})
+		if !ok {
+			t.Errorf("Output %q missing from action.yml", name)
+			continue
+		}
+		if description, _ := output["description"].(string); description == "" {
+			t.Errorf("Output %q is missing a description", name)
+		}
+	}
+}
+
 // testActionRuns verifies the runs section in the action.yml file
 func testActionRuns(t *testing.T) {
 	actionConfig := readActionYaml(t)
@@ -157,39 +199,14 @@ func testActionRuns(t *testing.T) {
 		t.Error("Runs section is missing a valid 'image' field")
 	}
 
-	// Check command arguments
+	// Check the binary reads the inputs itself rather than from positional arguments
 	args, ok := runs["args"].([]interface{})
 	if !ok {
 		t.Error("Runs section is missing 'args' field or it's not an array")
 		return
 	}
-
-	// Check that first argument is the 'enforce' command
-	if len(args) == 0 || args[0] != "enforce" {
-		t.Error("The first argument should be the 'enforce' command")
-	}
-
-	// Check for required arguments and values
-	requiredArgs := map[string]string{
-		"--repo":           "${{ inputs.github_repository }}",
-		"--output":         "${{ inputs.output_format }}",
-		"--github-token":   "${{ inputs.github_token }}",
-		"--policy-content": "${{ inputs.policy_content }}",
-	}
-
-	for flag, expectedValue := range requiredArgs {
-		found := false
-		for i, arg := range args {
-			if arg == flag && i+1 < len(args) {
-				if args[i+1] == expectedValue {
-					found = true
-					break
-				}
-			}
-		}
-		if !found {
-			t.Errorf("Args should include '%s' flag with value '%s'", flag, expectedValue)
-		}
+	if len(args) != 1 || args[0] != "action-entrypoint" {
+		t.Errorf("Args should only run the 'action-entrypoint' command, got %v", args)
 	}
 }
 
@@ -252,12 +269,10 @@ func testEntrypointScript(t *testing.T) {
 
 	// Check for critical functionality
 	essentialFunctionality := []string{
-		"CMD=",                     // Command argument extraction
-		"POLICY_CONTENT=",          // Policy content extraction
-		"TEMP_POLICY_FILE=",        // Temporary file creation
-		"--ignore-local-policy",    // Local policy override
-		"exec /app/action-control", // Main binary execution
-		"exit 1",                   // Error handling
+		"set -e",                          // Error handling
+		"uname -m",                        // Architecture mismatch reporting
+		"exec /app/action-control \"$@\"", // Main binary execution
+		"exit 1",                          // Error handling
 	}
 
 	for _, phrase := range essentialFunctionality {
@@ -265,45 +280,108 @@ func testEntrypointScript(t *testing.T) {
 			t.Errorf("entrypoint.sh should contain %q", phrase)
 		}
 	}
+
+	// Check argument handling stays in the binary
+	for _, positional := range []string{"\"$3\"", "\"$9\"", "${11}"} {
+		if strings.Contains(content, positional) {
+			t.Errorf("entrypoint.sh should not parse positional argument %s", positional)
+		}
+	}
 }
 
-// testCommandLineHandling verifies that arguments are properly handled
+// testCommandLineHandling verifies that every action input is read by the action entrypoint
 func testCommandLineHandling(t *testing.T) {
-	// Read entrypoint script
-	scriptData, err := os.ReadFile(entrypointPath)
+	actionConfig := readActionYaml(t)
+	inputs, ok := actionConfig["inputs"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Inputs section is not properly formatted")
+	}
+
+	data, err := os.ReadFile(actionInputsPath)
 	if err != nil {
-		t.Fatalf("Failed to read entrypoint.sh: %v", err)
+		t.Fatalf("Failed to read action inputs: %v", err)
 	}
 
-	scriptContent := string(scriptData)
+	for name := range inputs {
+		if !strings.Contains(string(data), "\""+name+"\"") {
+			t.Errorf("Action input %q is not read by the action entrypoint", name)
+		}
+	}
+}
+
+// testCompositeAction verifies the composite action mirrors the Docker action's interface
+func testCompositeAction(t *testing.T) {
+	data, err := os.ReadFile(compositePath)
+	if err != nil {
+		t.Fatalf("Failed to read composite action: %v", err)
+	}
 
-	// Check that the script extracts command-line arguments properly
-	argumentExtractions := []string{
-		"CMD=\"$1\"",            // Extract command (enforce, report, etc)
-		"REPO=\"$3\"",           // Extract repository
-		"OUTPUT_FORMAT=\"$5\"",  // Extract output format
-		"GITHUB_TOKEN=\"$7\"",   // Extract GitHub token
-		"POLICY_CONTENT=\"$9\"", // Extract policy content
+	var composite map[string]interface{}
+	if err := yaml.Unmarshal(data, &composite); err != nil {
+		t.Fatalf("Failed to parse composite action: %v", err)
 	}
 
-	for _, extraction := range argumentExtractions {
-		if !strings.Contains(scriptContent, extraction) {
-			t.Errorf("entrypoint.sh should contain %q", extraction)
+	dockerAction := readActionYaml(t)
+	for _, section := range []string{"inputs", "outputs"} {
+		expected, _ := dockerAction[section].(map[string]interface{})
+		actual, ok := composite[section].(map[string]interface{})
+		if !ok {
+			t.Errorf("Composite action is missing its %s section", section)
+			continue
+		}
+		for name := range expected {
+			if _, exists := actual[name]; !exists {
+				t.Errorf("Composite action is missing %s %q", section, name)
+			}
 		}
 	}
 
-	// Check that command line arguments are passed to the executable
-	execCommand := "exec /app/action-control"
-	if !strings.Contains(scriptContent, execCommand) {
-		t.Errorf("entrypoint.sh should execute the main binary with %q", execCommand)
+	runs, ok := composite["runs"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Composite action runs section is not properly formatted")
+	}
+	if using, _ := runs["using"].(string); using != "composite" {
+		t.Errorf("Expected runs.using to be 'composite', got %q", using)
+	}
+	steps, ok := runs["steps"].([]interface{})
+	if !ok || len(steps) == 0 {
+		t.Fatal("Composite action has no steps")
+	}
+	for i, step := range steps {
+		if shell, _ := step.(map[string]interface{})["shell"].(string); shell == "" {
+			t.Errorf("Composite action step %d must declare a shell", i)
+		}
 	}
 
-	// Check that the script validates required inputs
-	if !strings.Contains(scriptContent, "if [ -n \"$GITHUB_TOKEN\" ]") {
-		t.Error("entrypoint.sh should validate GitHub token")
+	// Check the binary is verified and run with the policy content only
+	content := string(data)
+	for _, phrase := range []string{
+		"checksums.txt",
+		"runner.arch",
+		"--ignore-local-policy",
+		"--max-violations",
+		"--fail-on-severity",
+		"shell: pwsh",
+		"action-control.ps1",
+	} {
+		if !strings.Contains(content, phrase) {
+			t.Errorf("Composite action should contain %q", phrase)
+		}
 	}
 
-	if !strings.Contains(scriptContent, "if [ -n \"$POLICY_CONTENT\" ]") {
-		t.Error("entrypoint.sh should validate policy content")
+	// Check the PowerShell path for Windows runners selects and verifies the binary too
+	script, err := os.ReadFile(powershellPath)
+	if err != nil {
+		t.Fatalf("Failed to read PowerShell script: %v", err)
+	}
+	for _, phrase := range []string{
+		"RUNNER_ARCH_NAME",
+		"action-control-windows-$goarch.exe",
+		"Get-FileHash",
+		"--ignore-local-policy",
+	} {
+		if !strings.Contains(string(script), phrase) {
+			t.Errorf("PowerShell script should contain %q", phrase)
+		}
 	}
 }
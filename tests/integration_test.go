@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -150,10 +151,16 @@ func TestPolicyModeFlag(t *testing.T) {
 
 // findBinary attempts to locate the action-control binary
 func findBinary() (string, error) {
+	// Windows binaries carry an .exe suffix
+	suffix := ""
+	if runtime.GOOS == "windows" {
+		suffix = ".exe"
+	}
+
 	// Try common locations
 	locations := []string{
-		"../action-control",
-		"../bin/action-control",
+		"../action-control" + suffix,
+		"../bin/action-control" + suffix,
 	}
 
 	for _, loc := range locations {
@@ -167,10 +174,10 @@ func findBinary() (string, error) {
 	}
 
 	// If not found in standard locations, try building it
-	cmd := exec.Command("go", "build", "-o", "action-control")
+	cmd := exec.Command("go", "build", "-o", "action-control"+suffix)
 	cmd.Dir = ".."
 	if err := cmd.Run(); err == nil {
-		return filepath.Abs("../action-control")
+		return filepath.Abs("../action-control" + suffix)
 	}
 
 	return "", fmt.Errorf("could not find or build action-control binary")